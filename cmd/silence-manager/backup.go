@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/backup"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/plan"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// newBackupStore creates the backup store selected by the configuration, or returns nil
+// if backups are disabled
+func newBackupStore(cfg *config.Config) backup.Store {
+	switch cfg.Backup.Backend {
+	case "file":
+		return backup.NewFileStore(cfg.Backup.Dir)
+	case "s3":
+		return backup.NewS3Store(backup.S3Config{
+			Bucket:          cfg.Backup.Bucket,
+			Prefix:          cfg.Backup.Prefix,
+			Region:          cfg.Backup.S3Region,
+			Endpoint:        cfg.Backup.Endpoint,
+			AccessKeyID:     cfg.Backup.S3AccessKeyID,
+			SecretAccessKey: cfg.Backup.S3SecretAccessKey,
+			SessionToken:    cfg.Backup.S3SessionToken,
+		})
+	case "gcs":
+		return backup.NewGCSStore(backup.GCSConfig{
+			Bucket:   cfg.Backup.Bucket,
+			Prefix:   cfg.Backup.Prefix,
+			Endpoint: cfg.Backup.Endpoint,
+			Token:    cfg.Backup.GCSToken,
+		})
+	default:
+		return nil
+	}
+}
+
+// backupSilences snapshots the managed silences to the backup store
+func backupSilences(store backup.Store, am alertmanager.AlertManager) error {
+	silences, err := am.ListSilences()
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	snapshot := backup.NewSnapshot(silences, time.Now())
+	if err := backup.Save(store, snapshot); err != nil {
+		return err
+	}
+	log.Printf("Backed up %d managed silences", len(snapshot.Silences))
+	return nil
+}
+
+// runRestore recreates the backed up silences missing from Alertmanager, or with --plan
+// prints the changes the restore would make
+func runRestore(args []string) {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	previous := fs.Bool("previous", false, "Restore from the snapshot before the latest one")
+	planOnly := fs.Bool("plan", false, "Print the changes the restore would make without applying them")
+	fs.Parse(args)
+
+	cfg := loadConfig()
+	store := newBackupStore(cfg)
+	if store == nil {
+		log.Printf("Restoring requires a backup store (set BACKUP_BACKEND)")
+		os.Exit(exitConfig)
+	}
+	role, _ := authz.ParseRole(cfg.Auth.Role) // Validated by LoadConfig
+	if err := authz.Authorize(role, authz.OpWrite, false); err != nil {
+		log.Fatalf("Refusing to restore silences: %v", err)
+	}
+
+	name := backup.LatestObject
+	if *previous {
+		name = backup.PreviousObject
+	}
+	snapshot, err := backup.Load(store, name)
+	if err != nil {
+		log.Fatalf("Failed to load backup: %v", err)
+	}
+	log.Printf("Loaded backup of %d silences taken at %s", len(snapshot.Silences), snapshot.Taken.Format(time.RFC3339))
+
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	syncConfig, err := newSyncConfig(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	ts := newTicketSystem(cfg)
+	if *planOnly {
+		p := plan.New()
+		synchronizer := sync.NewSynchronizer(plan.WrapAlertManager(am, p), plan.WrapTicketSystem(ts, p), syncConfig)
+		if _, err := synchronizer.RestoreSilences(snapshot.AlertmanagerSilences()); err != nil {
+			fatal(withExitCode(runExitCode(err), err))
+		}
+		if err := p.WriteText(os.Stdout); err != nil {
+			log.Fatalf("Failed to write plan: %v", err)
+		}
+		return
+	}
+
+	restored, err := sync.NewSynchronizer(am, ts, syncConfig).RestoreSilences(snapshot.AlertmanagerSilences())
+	for _, r := range restored {
+		fmt.Printf("Restored silence %s as %s (ticket %s), expiring at %s\n", r.PreviousID, r.SilenceID, r.TicketRef, r.EndsAt.Format(time.RFC3339))
+	}
+	if err != nil {
+		fatal(withExitCode(runExitCode(err), err))
+	}
+	fmt.Printf("Restored %d of %d backed up silences\n", len(restored), len(snapshot.Silences))
+}
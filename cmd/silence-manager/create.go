@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// runCreate creates a ticket and a linked silence in one step, e.g.
+// silence-manager create --summary "Disk replacement" --duration 3d alertname=DiskFull instance=db-1,
+// or from a silence template, e.g. silence-manager create --template node-maintenance node=db-1
+func runCreate(args []string) {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	summary := fs.String("summary", "", "Summary of the created ticket")
+	duration := fs.String("duration", "", "Silence duration, e.g. 4h or 3d (default: SYNC_DEFAULT_SILENCE_DURATION)")
+	comment := fs.String("comment", "", "Silence comment, also added to the ticket description")
+	author := fs.String("author", os.Getenv("USER"), "Creator recorded on the silence")
+	ticketKey := fs.String("ticket", "", "Link an existing ticket instead of creating one")
+	templateName := fs.String("template", "", "Create the silence from a template of SILENCE_TEMPLATES_FILE; arguments are its parameters")
+	listTemplates := fs.Bool("list-templates", false, "List the silence templates and exit")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: silence-manager create [flags] matcher...\n")
+		fmt.Fprintf(fs.Output(), "       silence-manager create --template name [flags] parameter=value...\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *listTemplates {
+		templates := loadTemplates(loadConfig())
+		for _, name := range templates.Names() {
+			fmt.Printf("%-24s %s\n", name, templates[name].Description)
+		}
+		return
+	}
+
+	var def sync.SilenceDefinition
+	var cfg *config.Config
+	if *templateName != "" {
+		params, err := sync.ParseTemplateParams(fs.Args())
+		if err != nil {
+			log.Print(err)
+			os.Exit(exitUsage)
+		}
+		cfg = loadConfig()
+		if def, err = loadTemplates(cfg).Definition(*templateName, params); err != nil {
+			log.Print(err)
+			os.Exit(exitUsage)
+		}
+	} else {
+		if fs.NArg() == 0 {
+			fs.Usage()
+			os.Exit(exitUsage)
+		}
+		matchers, err := sync.ParseMatchers(fs.Args())
+		if err != nil {
+			log.Print(err)
+			os.Exit(exitUsage)
+		}
+		def.Matchers = matchers
+	}
+
+	// Flags override the template
+	def.CreatedBy, def.Ticket = *author, *ticketKey
+	if *comment != "" {
+		def.Comment = *comment
+	}
+	if *summary != "" {
+		def.Summary = *summary
+	}
+	if def.Summary == "" && def.Ticket == "" {
+		log.Fatalf("Either --summary or --ticket is required")
+	}
+	if *duration != "" {
+		var err error
+		if def.Duration, err = sync.ParseDuration(*duration); err != nil {
+			log.Fatalf("Invalid duration %q: %v", *duration, err)
+		}
+	}
+
+	if cfg == nil {
+		cfg = loadConfig()
+	}
+	role, _ := authz.ParseRole(cfg.Auth.Role) // Validated by LoadConfig
+	if err := authz.Authorize(role, authz.OpWrite, false); err != nil {
+		log.Fatalf("Refusing to create silence: %v", err)
+	}
+
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	syncConfig, err := newSyncConfig(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	synchronizer := sync.NewSynchronizer(am, newTicketSystem(cfg), syncConfig)
+
+	silence, err := synchronizer.CreateSilence(def)
+	if err != nil {
+		log.Fatalf("Failed to create silence: %v", err)
+	}
+	fmt.Printf("Created silence %s linked to ticket %s, expiring at %s\n",
+		silence.ID, silence.TicketRef, silence.EndsAt.Format(time.RFC3339))
+}
+
+// loadTemplates reads the silence templates of SILENCE_TEMPLATES_FILE, exiting on an
+// invalid file. Without the setting there are no templates.
+func loadTemplates(cfg *config.Config) sync.SilenceTemplates {
+	if cfg.Templates.File == "" {
+		return sync.SilenceTemplates{}
+	}
+	templates, err := sync.LoadSilenceTemplates(cfg.Templates.File)
+	if err != nil {
+		fatal(withExitCode(exitConfig, err))
+	}
+	return templates
+}
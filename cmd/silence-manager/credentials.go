@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+)
+
+var (
+	secretCredentialsOnce sync.Once
+	secretCredentials     *k8s.SecretCredentials
+)
+
+// jiraSecretCredentials returns the Jira credentials read from JIRA_CREDENTIALS_SECRET_NAME.
+// The Secret is read and watched once per process, so the ticket systems of daemon runs
+// and operator projects share the rotated credentials.
+func jiraSecretCredentials(cfg *config.Config) *k8s.SecretCredentials {
+	secretCredentialsOnce.Do(func() {
+		credentials, err := k8s.NewSecretCredentials(context.Background(), cfg.Jira.CredentialsSecretNamespace, cfg.Jira.CredentialsSecretName)
+		if err != nil {
+			fatal(withExitCode(exitConfig, fmt.Errorf("failed to read Jira credentials: %w", err)))
+		}
+		log.Printf("Reading Jira credentials from Secret %s", cfg.Jira.CredentialsSecretName)
+		secretCredentials = credentials
+	})
+	return secretCredentials
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+)
+
+// runDaemon runs synchronization repeatedly until the process is terminated
+func runDaemon(args []string) {
+	log.Printf("Starting silence-manager daemon version=%s commit=%s date=%s", version, commit, date)
+
+	cfg := loadConfig()
+
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	interval := fs.Duration("interval", time.Duration(cfg.Daemon.IntervalMinutes)*time.Minute, "Time between synchronization runs")
+	fs.Parse(args)
+	if *interval <= 0 {
+		log.Fatalf("Interval must be positive")
+	}
+	verifyWorkflow(cfg)
+
+	// The daemon keeps the lease across runs so that CronJob runs defer while it is alive
+	leaseDuration := time.Duration(cfg.Lock.LeaseDurationSeconds) * time.Second
+	if leaseDuration < 2*(*interval) {
+		leaseDuration = 2 * (*interval)
+	}
+	lock := newRunLock(cfg, k8s.ModeDaemon, leaseDuration)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	notifyShutdown(ctx, stop, "daemon", cfg.Daemon.ShutdownTimeout)
+	// The run in progress when the daemon is told to stop may finish within the timeout
+	runCtx, cancelRuns := drainContext(ctx, cfg.Daemon.ShutdownTimeout)
+	defer cancelRuns()
+
+	// With leader election, the elector holds the lease continuously instead of each run
+	// acquiring it, and a replica that becomes the leader runs straight away. The lease is
+	// kept until the run in progress at shutdown has finished.
+	elector := newLeaderElector(cfg, k8s.ModeDaemon)
+	becameLeader := make(chan struct{}, 1)
+	var electionDone <-chan struct{}
+	electionCtx, stopElection := context.WithCancel(context.Background())
+	defer stopElection()
+	if elector != nil {
+		lock = nil
+		electionDone = runLeaderElection(electionCtx, elector, func() {
+			select {
+			case becameLeader <- struct{}{}:
+			default:
+			}
+		})
+	}
+
+	if cfg.Alertmanager.AutoDiscover {
+		watcher, err := k8s.NewServiceWatcher()
+		if err != nil {
+			log.Printf("Warning: cannot watch the discovered Alertmanager service, discovering on every run: %v", err)
+		} else {
+			alertmanagerCache = newDiscoveryCache(ctx, cfg, watcher)
+		}
+	}
+
+	// Liveness fails when the loop is stuck, leaving slack for runs longer than the interval
+	healthServer = startHealthServer(ctx, cfg, k8s.ModeDaemon, 3*(*interval))
+	defer startTracing(cfg)()
+
+	log.Printf("Running synchronization every %v", *interval)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		runDaemonCycle(ctx, runCtx, cfg, lock, leaseDuration, elector)
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		case <-becameLeader:
+		}
+		// No run starts once shutting down, even if a tick arrived at the same time
+		if ctx.Err() != nil {
+			log.Println("Daemon stopped")
+			if lock != nil {
+				if err := lock.Release(context.Background()); err != nil {
+					log.Printf("Warning: failed to release run lock: %v", err)
+				}
+			}
+			if electionDone != nil {
+				stopElection()
+				<-electionDone
+			}
+			return
+		}
+	}
+}
+
+// runDaemonCycle performs one daemon synchronization run if this replica is the leader
+// or the run lock can be held, renewing the lock while it runs. The run stops between
+// silences once runCtx is done.
+func runDaemonCycle(ctx, runCtx context.Context, cfg *config.Config, lock runLock, leaseDuration time.Duration, elector *k8s.LeaderElector) {
+	if healthServer != nil {
+		defer healthServer.Beat()
+	}
+	if elector != nil {
+		if status := elector.Status(); !status.Leading {
+			log.Printf("Not the leader (lease held by %s), skipping this run", holderName(status.Holder))
+			return
+		}
+	}
+	if lock != nil {
+		acquired, holder, err := lock.Acquire(ctx)
+		if err != nil {
+			log.Printf("Warning: failed to acquire run lock, skipping run: %v", err)
+			return
+		}
+		if !acquired {
+			deferRun(cfg, k8s.ModeDaemon, holder)
+			return
+		}
+		var stopRenewing func()
+		runCtx, stopRenewing = holdRunLock(runCtx, lock, leaseDuration)
+		defer stopRenewing()
+	}
+
+	started := time.Now()
+	result, err := syncOnce(runCtx, cfg)
+	reportRun(cfg, started, result, err)
+	writeSummary(cfg, started, result, err)
+	journalRun(started, result, err)
+	exportState()
+	if healthServer != nil {
+		if err != nil {
+			healthServer.RecordRun(started, nil, 0, err)
+		} else {
+			healthServer.RecordRun(started, result.Counts(), len(result.Errors), nil)
+		}
+	}
+	if err != nil {
+		log.Printf("Synchronization failed: %v", err)
+		return
+	}
+	if logResult(result) {
+		log.Println("Synchronization completed successfully")
+	}
+}
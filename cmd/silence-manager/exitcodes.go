@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// Exit codes, so that CronJob monitoring and scripts can tell failures apart
+const (
+	exitFailure            = 1 // Any failure not covered below
+	exitUsage              = 2 // Unknown command or invalid flags, as used by the flag package
+	exitConfig             = 3 // Invalid configuration
+	exitDiscovery          = 4 // Alertmanager or metrics backend discovery failed
+	exitPartialFailure     = 5 // The run completed, but some silences or tickets failed
+	exitBackendUnavailable = 6 // Alertmanager or the ticket system could not be reached at all
+	exitAuth               = 7 // Alertmanager or the ticket system rejected the credentials
+	exitTransient          = 8 // The run completed, and only timeouts, unavailable backends or rate limits failed it
+)
+
+// exitError attaches an exit code to an error
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode attaches an exit code to err
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code attached to err, or exitFailure
+func exitCode(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return exitFailure
+}
+
+// fatal logs err and exits with its exit code
+func fatal(err error) {
+	log.Print(err)
+	os.Exit(exitCode(err))
+}
+
+// runExitCode returns the exit code of a synchronization run that could not complete:
+// rejected credentials, or the backend being unavailable
+func runExitCode(err error) int {
+	if apierror.CategoryOf(err) == apierror.Auth {
+		return exitAuth
+	}
+	return exitBackendUnavailable
+}
+
+// syncExitCode returns the exit code of a completed synchronization run by the kind of
+// its failures. Rejected credentials need an operator and take precedence; then the
+// ticket system being unavailable for every managed silence. A run failed only by
+// transient errors or rate limits is likely to succeed when retried, which is told apart
+// from a partial failure that will recur.
+func syncExitCode(result *sync.SyncResult) int {
+	if len(result.Errors) == 0 {
+		return 0
+	}
+	counts := result.ErrorsByCategory()
+	if counts[apierror.Auth] > 0 {
+		return exitAuth
+	}
+	unavailable := 0
+	for _, m := range result.Managed {
+		if m.Health == sync.HealthTicketUnavailable {
+			unavailable++
+		}
+	}
+	if unavailable > 0 && unavailable == len(result.Managed) {
+		return exitBackendUnavailable
+	}
+	if counts[apierror.Transient]+counts[apierror.RateLimited] == len(result.Errors) {
+		return exitTransient
+	}
+	return exitPartialFailure
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/export"
+)
+
+// runExport prints the managed silences and their tickets as GitOps silence definitions
+// or as Terraform HCL, without changing anything
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "yaml", "Output format: yaml or hcl")
+	fs.Parse(args)
+
+	if *format != "yaml" && *format != "hcl" {
+		log.Printf("Unknown export format: %s (must be 'yaml' or 'hcl')", *format)
+		os.Exit(exitUsage)
+	}
+
+	cfg := loadConfig()
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	silences, err := am.ListSilences()
+	if err != nil {
+		log.Fatalf("Failed to list silences: %v", err)
+	}
+
+	// Keep the names of silences already declared in Git
+	names := make(map[string]string)
+	st, err := newStateStore(cfg).Load()
+	if err != nil {
+		log.Printf("Warning: failed to load state, GitOps definition names are not exported: %v", err)
+	} else {
+		for name, rec := range st.GitOps {
+			names[rec.SilenceID] = name
+		}
+	}
+
+	exported := export.Collect(silences, names)
+	if *format == "hcl" {
+		err = export.WriteHCL(os.Stdout, exported, time.Now())
+	} else {
+		err = export.WriteYAML(os.Stdout, exported, time.Now())
+	}
+	if err != nil {
+		log.Fatalf("Failed to export silences: %v", err)
+	}
+}
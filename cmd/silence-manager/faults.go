@@ -0,0 +1,28 @@
+//go:build faultinject
+
+package main
+
+import (
+	"log"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/faults"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// withFaults wraps the backend clients with the faults configured by the
+// FAULT_INJECTION_* environment variables. Only compiled with the faultinject tag.
+func withFaults(am alertmanager.AlertManager, ts ticket.TicketSystem) (alertmanager.AlertManager, ticket.TicketSystem) {
+	cfg, err := faults.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid fault injection configuration: %v", err)
+	}
+	if !cfg.Enabled() {
+		return am, ts
+	}
+
+	log.Printf("Warning: fault injection enabled (latency=%v jitter=%v error-rate=%.2f timeout-rate=%.2f timeout=%v operations=%v)",
+		cfg.Latency, cfg.Jitter, cfg.ErrorRate, cfg.TimeoutRate, cfg.Timeout, cfg.Operations)
+	injector := faults.NewInjector(cfg)
+	return faults.WrapAlertManager(am, injector), faults.WrapTicketSystem(ts, injector)
+}
@@ -0,0 +1,14 @@
+//go:build !faultinject
+
+package main
+
+import (
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// withFaults returns the clients unchanged; build with -tags faultinject to enable
+// fault injection
+func withFaults(am alertmanager.AlertManager, ts ticket.TicketSystem) (alertmanager.AlertManager, ticket.TicketSystem) {
+	return am, ts
+}
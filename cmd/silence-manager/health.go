@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/health"
+	"github.com/conallob/silence-manager/pkg/k8s"
+	"github.com/conallob/silence-manager/pkg/metrics"
+)
+
+// metricsExporter serves the metrics of the daemon's runs on /metrics, or is nil unless
+// METRICS_BACKEND is prometheus and the health endpoints are served
+var metricsExporter *metrics.PrometheusExporter
+
+// healthServer reports the daemon's runs on its status endpoint, or is nil when the
+// health endpoints are disabled or not served by the current mode
+var healthServer *health.Server
+
+// startHealthServer serves the health endpoints until ctx is done, probing the same
+// backends as the validate command, /metrics when METRICS_BACKEND is prometheus, and the
+// daemon's web UI when WEBUI_ENABLED is true. A
+// positive maxIdle fails liveness when no cycle completed for that long. It returns nil
+// when HEALTH_ENABLED is false.
+func startHealthServer(ctx context.Context, cfg *config.Config, mode string, maxIdle time.Duration) *health.Server {
+	if !cfg.Health.Enabled {
+		return nil
+	}
+
+	var checks []health.Check
+	for _, check := range configChecks(cfg) {
+		probe := check.probe
+		checks = append(checks, health.Check{Name: check.name, Probe: func() error {
+			_, err := probe()
+			return err
+		}})
+	}
+
+	server := health.NewServer(mode, version, maxIdle, checks)
+	if cfg.Metrics.Enabled && cfg.Metrics.Backend == "prometheus" {
+		metricsExporter = metrics.NewPrometheusExporter()
+		server.Handle("/metrics", metricsExporter.Handler())
+	}
+	if cfg.WebUI.Enabled && mode == k8s.ModeDaemon {
+		server.Handle("/ui/", newWebUI(cfg))
+	}
+	go func() {
+		if err := server.Run(ctx, cfg.Health.Address, cfg.Health.ProbeInterval); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
+	log.Printf("Serving health endpoints on %s", cfg.Health.Address)
+	return server
+}
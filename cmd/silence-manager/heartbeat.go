@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/heartbeat"
+)
+
+// sendHeartbeat pings the dead man's switch configured by HEARTBEAT_URL. It is called only
+// after runs without errors, so the monitor alerts when runs fail or stop. A failed ping
+// is logged rather than failing the run, as the missing ping is itself reported.
+func sendHeartbeat(cfg *config.Config) {
+	if cfg.Heartbeat.URL == "" {
+		return
+	}
+
+	pinger := heartbeat.NewPinger(heartbeat.Config{
+		URL:       cfg.Heartbeat.URL,
+		Method:    cfg.Heartbeat.Method,
+		TLSConfig: newTLSConfig(cfg),
+	})
+	if err := pinger.Ping(context.Background()); err != nil {
+		log.Printf("Warning: failed to send heartbeat to %s: %v", pinger.Host(), err)
+		return
+	}
+	log.Printf("Sent heartbeat to %s", pinger.Host())
+}
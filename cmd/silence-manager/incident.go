@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/incident"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// failureStore keeps the failure record of the daemon between runs with the in-memory
+// state backend; CronJob runs need a persistent backend to count consecutive failures
+var failureStore state.Store
+
+// newIncidentNotifier creates the incident provider client, or returns nil if failure
+// alerts are disabled
+func newIncidentNotifier(cfg *config.Config) incident.Notifier {
+	switch cfg.FailureAlert.Provider {
+	case "pagerduty":
+		return incident.NewPagerDuty(cfg.FailureAlert.APIURL, cfg.FailureAlert.RoutingKey, cfg.FailureAlert.Source)
+	case "opsgenie":
+		return incident.NewOpsgenie(cfg.FailureAlert.APIURL, cfg.FailureAlert.RoutingKey, cfg.FailureAlert.Source)
+	default:
+		return nil
+	}
+}
+
+// reportRun applies the failure alert policy to a completed or aborted run, raising an
+// incident when runs keep failing and resolving it after the next successful run.
+// Failures to reach the provider are logged; triggers are retried by the next failing run.
+func reportRun(cfg *config.Config, started time.Time, result *sync.SyncResult, runErr error) {
+	notifier := newIncidentNotifier(cfg)
+	if notifier == nil {
+		return
+	}
+
+	if failureStore == nil {
+		failureStore = newStateStore(cfg)
+	}
+	st, err := failureStore.Load()
+	if err != nil {
+		log.Printf("Warning: failed to load failure record: %v", err)
+		return
+	}
+
+	run := incident.Run{Started: started, Aborted: runErr != nil}
+	if result != nil {
+		run.Errors = len(result.Errors)
+		run.Silences = result.Hygiene.TotalSilences
+	}
+	policy := incident.Policy{
+		ErrorRate:           cfg.FailureAlert.ErrorRate,
+		ConsecutiveFailures: cfg.FailureAlert.ConsecutiveFailures,
+	}
+	action, reason := policy.Evaluate(&st.Failures, run)
+
+	ctx := context.Background()
+	switch action {
+	case incident.ActionTrigger:
+		event := incident.Event{
+			Summary: fmt.Sprintf("silence-manager runs failing: %s", reason),
+			Details: map[string]string{
+				"consecutive_failures": strconv.Itoa(st.Failures.Consecutive),
+				"failing_since":        st.Failures.Since.UTC().Format(time.RFC3339),
+				"errors":               strconv.Itoa(run.Errors),
+				"silences":             strconv.Itoa(run.Silences),
+			},
+		}
+		if runErr != nil {
+			event.Details["last_error"] = runErr.Error()
+		} else if run.Errors > 0 {
+			event.Details["last_error"] = result.Errors[len(result.Errors)-1].Error()
+		}
+		if err := notifier.Trigger(ctx, event); err != nil {
+			log.Printf("Warning: failed to raise %s incident: %v", cfg.FailureAlert.Provider, err)
+		} else {
+			st.Failures.Alerting = true
+			log.Printf("Raised %s incident: %s", cfg.FailureAlert.Provider, event.Summary)
+		}
+	case incident.ActionResolve:
+		if err := notifier.Resolve(ctx); err != nil {
+			log.Printf("Warning: failed to resolve %s incident: %v", cfg.FailureAlert.Provider, err)
+			st.Failures.Alerting = true
+		} else {
+			log.Printf("Resolved %s incident after a successful run", cfg.FailureAlert.Provider)
+		}
+	}
+
+	if err := failureStore.Save(st); err != nil {
+		log.Printf("Warning: failed to save failure record: %v", err)
+	}
+}
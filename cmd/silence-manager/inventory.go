@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// publishInventory publishes the managed silence/ticket pairs and their health to a ConfigMap
+func publishInventory(cfg *config.Config, result *sync.SyncResult) error {
+	inventory, err := json.MarshalIndent(result.Managed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, m := range result.Managed {
+		counts[m.Health]++
+	}
+
+	data := map[string]string{
+		"inventory.json":    string(inventory),
+		"lastSync":          result.Hygiene.Timestamp.UTC().Format(time.RFC3339),
+		"total":             strconv.Itoa(len(result.Managed)),
+		"healthy":           strconv.Itoa(counts[sync.HealthHealthy]),
+		"ticketNotOpen":     strconv.Itoa(counts[sync.HealthTicketNotOpen]),
+		"ticketUnavailable": strconv.Itoa(counts[sync.HealthTicketUnavailable]),
+		"ticketUnassigned":  strconv.Itoa(counts[sync.HealthTicketUnassigned]),
+		"silenceMissing":    strconv.Itoa(counts[sync.HealthSilenceMissing]),
+	}
+
+	return k8s.PublishInventory(k8s.InventoryConfig{
+		Name:      cfg.Inventory.ConfigMapName,
+		Namespace: cfg.Inventory.Namespace,
+	}, data)
+}
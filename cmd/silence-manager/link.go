@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// runLink attaches an existing silence to an existing ticket
+func runLink(args []string) {
+	fs := flag.NewFlagSet("link", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: silence-manager link <silence-id> <ticket-key>\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	synchronizer := newLinkSynchronizer(loadConfig(), "link")
+	if err := synchronizer.LinkSilence(fs.Arg(0), fs.Arg(1)); err != nil {
+		log.Fatalf("Failed to link silence: %v", err)
+	}
+	fmt.Printf("Linked silence %s to ticket %s\n", fs.Arg(0), fs.Arg(1))
+}
+
+// runUnlink detaches a silence from its ticket
+func runUnlink(args []string) {
+	fs := flag.NewFlagSet("unlink", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: silence-manager unlink <silence-id>\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	synchronizer := newLinkSynchronizer(loadConfig(), "unlink")
+	if err := synchronizer.UnlinkSilence(fs.Arg(0)); err != nil {
+		log.Fatalf("Failed to unlink silence: %v", err)
+	}
+	fmt.Printf("Unlinked silence %s\n", fs.Arg(0))
+}
+
+// newLinkSynchronizer authorizes a link operation and creates the synchronizer for it
+func newLinkSynchronizer(cfg *config.Config, command string) *sync.Synchronizer {
+	role, _ := authz.ParseRole(cfg.Auth.Role) // Validated by LoadConfig
+	if err := authz.Authorize(role, authz.OpWrite, false); err != nil {
+		log.Fatalf("Refusing to %s silence: %v", command, err)
+	}
+
+	synchronizer, err := newOperationSynchronizer(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	return synchronizer
+}
+
+// newOperationSynchronizer creates a synchronizer for listing, linking and unlinking
+// silences, without the maintenance calendar and on-call resolver of sync runs
+func newOperationSynchronizer(cfg *config.Config) (*sync.Synchronizer, error) {
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	syncConfig, err := newSyncConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	synchronizer := sync.NewSynchronizer(am, newTicketSystem(cfg), syncConfig)
+	synchronizer.SetStateStore(newStateStore(cfg))
+	return synchronizer, nil
+}
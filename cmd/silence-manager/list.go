@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// runList prints the active silences, their tickets and the action the next sync
+// run would take for each, without changing anything
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	output := fs.String("output", "text", "Output format: text or json")
+	fs.Parse(args)
+
+	if *output != "text" && *output != "json" {
+		log.Fatalf("Unknown output format: %s (must be 'text' or 'json')", *output)
+	}
+
+	synchronizer, err := newOperationSynchronizer(loadConfig())
+	if err != nil {
+		fatal(err)
+	}
+
+	plan, err := synchronizer.Plan()
+	if err != nil {
+		log.Fatalf("Failed to list silences: %v", err)
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(plan); err != nil {
+			log.Fatalf("Failed to encode silences: %v", err)
+		}
+		return
+	}
+	printPlan(plan)
+}
+
+func printPlan(plan []sync.PlannedAction) {
+	if len(plan) == 0 {
+		fmt.Println("No active silences.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SILENCE\tTICKET\tSTATUS\tEXPIRES\tNEXT ACTION")
+	for _, p := range plan {
+		action := p.Action
+		if p.Action == sync.ActionExtend {
+			action = fmt.Sprintf("extend until %s", p.NewEndsAt.Format(time.RFC3339))
+		} else if p.Reason != "" {
+			action = fmt.Sprintf("%s (%s)", p.Action, p.Reason)
+		}
+		status := orDash(p.TicketStatus)
+		if p.SLA != "" {
+			status = fmt.Sprintf("%s (%s)", status, p.SLA)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.SilenceID, orDash(p.TicketRef), status,
+			p.EndsAt.Format(time.RFC3339), action)
+	}
+	w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
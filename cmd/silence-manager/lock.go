@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+	"github.com/conallob/silence-manager/pkg/redis"
+)
+
+// runLock ensures only one instance synchronizes at a time, through a Lease or a Redis key
+type runLock interface {
+	// Acquire takes or renews the lock, returning the holder if another instance holds it
+	Acquire(ctx context.Context) (bool, *k8s.LockHolder, error)
+
+	// Release gives up the lock if this instance holds it
+	Release(ctx context.Context) error
+}
+
+// redisRunLock adapts the Redis lock to runLock
+type redisRunLock struct {
+	lock *redis.Lock
+}
+
+func (l redisRunLock) Acquire(ctx context.Context) (bool, *k8s.LockHolder, error) {
+	acquired, holder, err := l.lock.Acquire(ctx)
+	if holder == nil {
+		return acquired, nil, err
+	}
+	return acquired, &k8s.LockHolder{Identity: holder.Identity, Mode: holder.Mode}, err
+}
+
+func (l redisRunLock) Release(ctx context.Context) error {
+	return l.lock.Release(ctx)
+}
+
+// holdRunLock renews the held run lock every third of leaseDuration until the returned
+// stop function is called, so that a run outlasting the lease keeps it. The returned
+// context is cancelled when another instance has taken the lock, stopping the run
+// between silences rather than letting it mutate alongside the new holder.
+func holdRunLock(ctx context.Context, lock runLock, leaseDuration time.Duration) (context.Context, func()) {
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(leaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			acquired, holder, err := lock.Acquire(runCtx)
+			switch {
+			case err != nil:
+				log.Printf("Warning: failed to renew run lock: %v", err)
+			case !acquired && holder.Identity == "":
+				// A concurrent update or expiry raced the renewal; the next attempt retries
+				log.Printf("Warning: failed to renew run lock, retrying")
+			case !acquired:
+				log.Printf("Warning: run lock was taken by %s, stopping the run", holderName(holder.Identity))
+				cancel()
+				return
+			}
+		}
+	}()
+	return runCtx, func() {
+		cancel()
+		<-done
+	}
+}
+
+// newRunLock creates the run lock for the given mode, or returns nil when locking is disabled
+func newRunLock(cfg *config.Config, mode string, leaseDuration time.Duration) runLock {
+	if !cfg.Lock.Enabled {
+		return nil
+	}
+
+	if cfg.Lock.Backend == "redis" {
+		return redisRunLock{redis.NewLock(newRedisClient(cfg), redis.LockConfig{
+			Key:      cfg.Lock.RedisKey,
+			Identity: instanceIdentity(),
+			Token:    redis.NewLockToken(instanceIdentity()),
+			Mode:     mode,
+			TTL:      leaseDuration,
+		})}
+	}
+
+	lock, err := k8s.NewRunLock(k8s.LockConfig{
+		Name:          cfg.Lock.LeaseName,
+		Namespace:     cfg.Lock.Namespace,
+		Identity:      instanceIdentity(),
+		Mode:          mode,
+		LeaseDuration: leaseDuration,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create run lock: %v", err)
+	}
+	return lock
+}
+
+// leaderElector is set by long-running commands campaigning for leadership, so that runs
+// can record the leadership state in their metrics
+var leaderElector *k8s.LeaderElector
+
+// newLeaderElector creates the leader elector for the given mode, or returns nil when
+// leader election is disabled. Daemon replicas campaign for the run lock lease itself, so
+// that CronJob runs defer to the leader; operators use a lease of their own.
+func newLeaderElector(cfg *config.Config, mode string) *k8s.LeaderElector {
+	if !cfg.LeaderElection.Enabled {
+		return nil
+	}
+
+	name := cfg.Lock.LeaseName
+	if mode == k8s.ModeOperator {
+		name += "-operator"
+	}
+	lock, err := k8s.NewRunLock(k8s.LockConfig{
+		Name:          name,
+		Namespace:     cfg.Lock.Namespace,
+		Identity:      instanceIdentity(),
+		Mode:          mode,
+		LeaseDuration: cfg.LeaderElection.LeaseDuration,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create leader election lease: %v", err)
+	}
+	log.Printf("Leader election enabled: lease %s, duration %v, retry period %v",
+		name, cfg.LeaderElection.LeaseDuration, cfg.LeaderElection.RetryPeriod)
+	return k8s.NewLeaderElector(lock, cfg.LeaderElection.RetryPeriod)
+}
+
+// runLeaderElection campaigns in the background, calling onStarted when this instance
+// becomes the leader. The process exits when leadership is lost, so that a run in
+// progress cannot mutate alongside the new leader. The returned channel is closed once
+// the lease has been released after ctx ends.
+func runLeaderElection(ctx context.Context, elector *k8s.LeaderElector, onStarted func()) <-chan struct{} {
+	leaderElector = elector
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := elector.Run(ctx, onStarted); err != nil {
+			log.Fatalf("Exiting: %v", err)
+		}
+	}()
+	return done
+}
+
+// holderName describes a lease holder for logging
+func holderName(identity string) string {
+	if identity == "" {
+		return "unknown"
+	}
+	return identity
+}
+
+// instanceIdentity names this instance on the run lock, preferring the pod name. Two
+// processes in one pod share it, so the Redis lock keys ownership on a per-process token.
+func instanceIdentity() string {
+	if name := os.Getenv("POD_NAME"); name != "" {
+		return name
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		return hostname
+	}
+	return "silence-manager"
+}
+
+// deferRun skips a run because another instance holds the run lock, and publishes a
+// warning metric so that overlapping CronJob and daemon deployments are noticed
+func deferRun(cfg *config.Config, mode string, holder *k8s.LockHolder) {
+	holderMode := holder.Mode
+	if holderMode == "" {
+		holderMode = "unknown"
+	}
+	log.Printf("Warning: run lock is held by %s (%s), deferring this %s run", holder.Identity, holderMode, mode)
+
+	publisher, err := newMetricsPublisher(cfg)
+	if err != nil {
+		log.Printf("Warning: failed to initialize metrics publisher: %v", err)
+		return
+	}
+	defer func() {
+		if err := publisher.Close(); err != nil {
+			log.Printf("Warning: failed to close metrics publisher: %v", err)
+		}
+	}()
+
+	publisher.RecordDeferredRun(mode, holderMode)
+	if err := publisher.Push(); err != nil {
+		log.Printf("Warning: failed to push metrics: %v", err)
+	}
+}
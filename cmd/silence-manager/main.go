@@ -1,14 +1,30 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/apierror"
+	"github.com/conallob/silence-manager/pkg/calendar"
 	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/gitops"
 	"github.com/conallob/silence-manager/pkg/k8s"
 	"github.com/conallob/silence-manager/pkg/metrics"
+	"github.com/conallob/silence-manager/pkg/oncall"
+	"github.com/conallob/silence-manager/pkg/redis"
+	"github.com/conallob/silence-manager/pkg/rules"
+	"github.com/conallob/silence-manager/pkg/slack"
+	"github.com/conallob/silence-manager/pkg/state"
 	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/teams"
 	"github.com/conallob/silence-manager/pkg/ticket"
 )
 
@@ -20,47 +36,737 @@ var (
 
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	// The first non-flag argument selects the command; sync is the default for the CronJob
+	command := "sync"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
+		args = args[1:]
+	}
+
+	switch command {
+	case "sync":
+		runSync(args)
+	case "daemon":
+		runDaemon(args)
+	case "serve":
+		runServe(args)
+	case "operator":
+		runOperator(args)
+	case "slo":
+		runSLO(args)
+	case "report":
+		runReport(args)
+	case "purge":
+		runPurge(args)
+	case "import-silences":
+		runImport(args)
+	case "list":
+		runList(args)
+	case "create":
+		runCreate(args)
+	case "link":
+		runLink(args)
+	case "unlink":
+		runUnlink(args)
+	case "revert-extension":
+		runRevertExtension(args)
+	case "restore":
+		runRestore(args)
+	case "rollback":
+		runRollback(args)
+	case "export":
+		runExport(args)
+	case "validate-config":
+		runValidateConfig(args)
+	default:
+		log.Printf("Unknown command: %s (must be 'sync', 'daemon', 'serve', 'operator', 'slo', 'report', 'purge', 'import-silences', 'list', 'create', 'link', 'unlink', 'revert-extension', 'restore', 'rollback', 'export' or 'validate-config')", command)
+		os.Exit(exitUsage)
+	}
+}
+
+// newStateStore creates the state store selected by the configuration. With
+// STATE_SYNC_BACKEND, the state file is imported from its remote copy on first use.
+func newStateStore(cfg *config.Config) state.Store {
+	switch cfg.State.Backend {
+	case "file":
+		if cfg.State.SyncBackend == "" {
+			return state.NewFileStore(cfg.State.FilePath)
+		}
+		if syncedState == nil {
+			remote, err := newStateRemote(cfg)
+			if err != nil {
+				log.Fatalf("Failed to create state sync backend: %v", err)
+			}
+			syncedState = state.NewSyncedStore(state.NewFileStore(cfg.State.FilePath), remote)
+		}
+		return syncedState
+	case "redis":
+		return state.NewRemoteStore(redis.NewStateKey(newRedisClient(cfg), cfg.State.RedisKey))
+	default:
+		return state.NewMemoryStore()
+	}
+}
+
+// runSync performs a single synchronization run, or with --plan prints the changes the
+// run would make without applying them
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	planOnly := fs.Bool("plan", false, "Print the changes the run would make without applying them")
+	output := fs.String("output", "text", "Plan output format: text or json")
+	fs.Parse(args)
+
 	log.Printf("Starting silence-manager version=%s commit=%s date=%s", version, commit, date)
 
-	// Load configuration
+	cfg := loadConfig()
+	if *planOnly {
+		runPlan(cfg, *output)
+		return
+	}
+	verifyWorkflow(cfg)
+
+	// Defer to a daemon (or an overlapping CronJob run) holding the run lock
+	leaseDuration := time.Duration(cfg.Lock.LeaseDurationSeconds) * time.Second
+	lock := newRunLock(cfg, k8s.ModeCronJob, leaseDuration)
+	runCtx, stopRenewing := context.Background(), func() {}
+	if lock != nil {
+		acquired, holder, err := lock.Acquire(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to acquire run lock: %v", err)
+		}
+		if !acquired {
+			deferRun(cfg, k8s.ModeCronJob, holder)
+			return
+		}
+		runCtx, stopRenewing = holdRunLock(runCtx, lock, leaseDuration)
+	}
+
+	stopTracing := startTracing(cfg)
+	started := time.Now()
+	result, err := syncOnce(runCtx, cfg)
+	stopRenewing()
+	stopTracing()
+	reportRun(cfg, started, result, err)
+	writeSummary(cfg, started, result, err)
+	exportState()
+	if lock != nil {
+		if err := lock.Release(context.Background()); err != nil {
+			log.Printf("Warning: failed to release run lock: %v", err)
+		}
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	if !logResult(result) {
+		os.Exit(syncExitCode(result))
+	}
+
+	log.Println("Synchronization completed successfully")
+}
+
+// loadConfig loads the configuration, exiting on failure
+func loadConfig() *config.Config {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
-		os.Exit(1)
+		log.Printf("Failed to load configuration: %v", err)
+		os.Exit(exitConfig)
 	}
 
 	log.Printf("Configuration loaded successfully")
-	log.Printf("Jira URL: %s", cfg.Jira.URL)
+	log.Printf("Jira URL: %s", config.RedactURL(cfg.Jira.URL))
 	log.Printf("Jira Project: %s", cfg.Jira.ProjectKey)
+	if cfg.TLS.InsecureSkipVerify {
+		log.Println("WARNING: TLS certificate verification is disabled")
+	}
+	return cfg
+}
+
+// verifyWorkflow checks, when enabled, that the Jira workflow has the reopen and close
+// transitions the runs need, exiting before the first run otherwise
+func verifyWorkflow(cfg *config.Config) {
+	if !cfg.Jira.VerifyWorkflow {
+		return
+	}
+	found, err := newTicketSystem(cfg).CheckTransitions(neededTransitions(cfg))
+	if err != nil {
+		log.Printf("Jira workflow verification failed: %v", err)
+		log.Printf("Set JIRA_REOPEN_TRANSITION, JIRA_CLOSE_TRANSITION or JIRA_STATUS_MAP to match the workflow")
+		os.Exit(exitConfig)
+	}
+	log.Printf("Jira workflow verified: %s", found)
+}
+
+// syncOnce builds the clients and performs one synchronization run, which stops between
+// silences once ctx is done
+func syncOnce(ctx context.Context, cfg *config.Config) (*sync.SyncResult, error) {
+	// Import the exported state before changing anything, so no run works without it
+	if cfg.State.SyncBackend != "" {
+		if _, err := newStateStore(cfg).Load(); err != nil {
+			return nil, err
+		}
+	}
+
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := newTicketSystem(cfg)
+	amClient, tsClient := withFaults(am, ts)
+
+	// Create synchronizer
+	syncConfig, err := newSyncConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Sync configuration:")
+	log.Printf("  Annotation prefix: %s", cfg.Sync.AnnotationPrefix)
+	log.Printf("  Expiry threshold: %v", syncConfig.ExpiryThreshold)
+	log.Printf("  Clock tolerance: %v (Alertmanager server time: %v)", syncConfig.ClockTolerance, syncConfig.UseServerTime)
+	log.Printf("  Extension duration: %v", syncConfig.ExtensionDuration)
+	log.Printf("  Default silence duration: %v", syncConfig.DefaultSilenceDuration)
+	log.Printf("  Check alerts: %v", syncConfig.CheckAlerts)
+	log.Printf("  Check silenced alerts: %v", syncConfig.CheckSilencedAlerts)
+	log.Printf("  Process directives: %v", syncConfig.ProcessDirectives)
+	log.Printf("  Duration label prefix: %s", syncConfig.DurationLabelPrefix)
+	log.Printf("  Require assignee: %v", syncConfig.RequireAssignee)
+	log.Printf("  Lifecycle labels: %v", syncConfig.LifecycleLabels)
+	log.Printf("  Update due date: %v", syncConfig.UpdateDueDate)
+	log.Printf("  Extension history: %v", syncConfig.ExtensionHistory)
+	if syncConfig.MaxExtensions > 0 {
+		log.Printf("  Maximum extensions: %d", syncConfig.MaxExtensions)
+	}
+	log.Printf("  Ticket matchers: %v", syncConfig.TicketMatchers)
+	if syncConfig.SilenceUIURL != "" {
+		log.Printf("  Silence links: %s", config.RedactURL(syncConfig.SilenceUIURL))
+	}
+	if len(syncConfig.SeverityPriorities) > 0 {
+		log.Printf("  Severity priorities: %v", syncConfig.SeverityPriorities)
+	}
+	if len(syncConfig.PriorityExtensions) > 0 {
+		log.Printf("  Priority extensions: %v", syncConfig.PriorityExtensions)
+	}
+	if len(syncConfig.SeveritySLAs) > 0 {
+		log.Printf("  Severity SLAs: %v (priority: %q, label: %q)", syncConfig.SeveritySLAs, syncConfig.SLAPriority, syncConfig.SLALabel)
+	}
+	if syncConfig.AckPrefix != "" {
+		log.Printf("  Karma acknowledgements: comments starting with %q", syncConfig.AckPrefix)
+	}
+	if syncConfig.DiscoveryQuery != "" {
+		log.Printf("  Ticket discovery: %s", syncConfig.DiscoveryQuery)
+	}
+	switch syncConfig.ReopenStrategy {
+	case sync.ReopenStrategyNewTicket:
+		log.Printf("  Refired alerts: new ticket linked as %q, copying labels %v", syncConfig.RefireLinkType, syncConfig.RefireCopyLabels)
+	case sync.ReopenStrategyComment:
+		log.Printf("  Refired alerts: comment on the closed ticket")
+	default:
+		if syncConfig.RefireSubTasks {
+			log.Printf("  Refired alerts: reopen with a sub-task per instance")
+		}
+	}
+	if len(syncConfig.Watchers) > 0 {
+		log.Printf("  Ticket watchers: %v", syncConfig.Watchers)
+	}
+	if syncConfig.BusinessHours != nil {
+		log.Printf("  Business hours: %s on %s (%s)", cfg.Sync.BusinessHours, cfg.Sync.BusinessDays, cfg.Sync.BusinessTimezone)
+	}
+
+	synchronizer := newSynchronizer(cfg, amClient, tsClient, syncConfig)
+
+	// Initialize metrics publisher if enabled
+	publisher, err := newMetricsPublisher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	synchronizer.SetMetricsPublisher(publisher)
+	ts.SetRateLimitObserver(publisher)
+	ts.SetRequestObserver(publisher)
+	if observed, ok := am.(*alertmanager.PrometheusAlertManager); ok {
+		observed.SetRequestObserver(publisher)
+	}
+	if leaderElector != nil {
+		if status := leaderElector.Status(); status.Leading {
+			publisher.RecordLeader(status.Identity, status.Since)
+		}
+	}
+	defer func() {
+		if err := publisher.Close(); err != nil {
+			log.Printf("Warning: failed to close metrics publisher: %v", err)
+		}
+	}()
+
+	// Publish the managed silence inventory if enabled
+	if cfg.Inventory.Enabled {
+		synchronizer.AddRunStep("inventory", func(result *sync.SyncResult) error {
+			if err := publishInventory(cfg, result); err != nil {
+				log.Printf("Warning: failed to publish inventory: %v", err)
+				return fmt.Errorf("publish inventory: %w", err)
+			}
+			return nil
+		})
+	}
+
+	// Snapshot the managed silences if backups are enabled
+	if store := newBackupStore(cfg); store != nil {
+		synchronizer.AddRunStep("backup", func(*sync.SyncResult) error {
+			if err := backupSilences(store, am); err != nil {
+				log.Printf("Warning: failed to back up silences: %v", err)
+				return fmt.Errorf("back up silences: %w", err)
+			}
+			return nil
+		})
+	}
+
+	// Perform synchronization
+	log.Println("Starting synchronization run...")
+	if cfg.Sync.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Sync.Timeout)
+		defer cancel()
+		log.Printf("Synchronization deadline: %v", cfg.Sync.Timeout)
+	}
+	result, err := synchronizer.SyncContext(ctx)
+	if err != nil {
+		return result, withExitCode(runExitCode(err), err)
+	}
+
+	// Report the run to the dead man's switch only if it completed without errors
+	if len(result.Errors) == 0 {
+		sendHeartbeat(cfg)
+	}
+
+	return result, nil
+}
+
+// newSynchronizer creates the synchronizer with the state store, maintenance calendar and
+// on-call resolver selected by the configuration
+func newSynchronizer(cfg *config.Config, am alertmanager.AlertManager, ts ticket.TicketSystem, syncConfig sync.SyncConfig) *sync.Synchronizer {
+	synchronizer := sync.NewSynchronizer(am, ts, syncConfig)
+	synchronizer.SetStateStore(newStateStore(cfg))
+	log.Printf("Created synchronizer (state backend: %s)", cfg.State.Backend)
+	if cfg.State.SyncBackend != "" {
+		log.Printf("State is imported from and exported to %s %s", cfg.State.SyncBackend, cfg.State.SyncName)
+	}
+
+	switches := sync.ActionSwitches{
+		Extend: cfg.Actions.Extend,
+		Delete: cfg.Actions.Delete,
+		Reopen: cfg.Actions.Reopen,
+		Create: cfg.Actions.Create,
+	}
+	if !switches.AllEnabled() {
+		synchronizer.AddActionHooks(switches)
+		log.Printf("Action switches: extend %v, delete %v, reopen %v, create %v",
+			switches.Extend, switches.Delete, switches.Reopen, switches.Create)
+		if !switches.Create && (cfg.Maintenance.CalendarURL != "" || cfg.GitOps.Path != "" || cfg.Change.Query != "") {
+			log.Printf("Warning: ENABLE_CREATE is off; maintenance windows, change windows and GitOps definitions fail to apply")
+		}
+	}
+
+	if syncConfig.AutoCloseAfter > 0 {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: closing quiet tickets requires a persistent state backend; expired silences are forgotten between runs")
+		}
+		log.Printf("Closing tickets whose alerts stay quiet for %v after their silence expires", syncConfig.AutoCloseAfter)
+	}
+
+	if syncConfig.RecreateOnReopenFor > 0 {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: recreating silences of reopened tickets requires a persistent state backend; deleted silences are forgotten between runs")
+		}
+		log.Printf("Recreating silences of tickets reopened within %v of being resolved", syncConfig.RecreateOnReopenFor)
+	}
+
+	if syncConfig.ExpireIdleAfter > 0 {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: letting idle silences lapse requires a persistent state backend; idle silences are forgotten between runs")
+		}
+		log.Printf("Letting silences lapse that match no alerts for %v", syncConfig.ExpireIdleAfter)
+	}
+
+	if syncConfig.JournalRetention > 0 {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: the undo journal requires a persistent state backend; runs cannot be rolled back after the process exits")
+		}
+		log.Printf("Journaling deleted and changed silences for %v for rollback", syncConfig.JournalRetention)
+	}
+
+	if len(syncConfig.TicketProjects) > 0 {
+		if !slices.ContainsFunc(syncConfig.TicketProjects, func(project string) bool { return strings.EqualFold(project, cfg.Jira.ProjectKey) }) {
+			log.Printf("Warning: SYNC_TICKET_PROJECTS does not include JIRA_PROJECT_KEY %s; silences of the tickets silence-manager creates are skipped", cfg.Jira.ProjectKey)
+		}
+		log.Printf("Managing only tickets of the projects %v", syncConfig.TicketProjects)
+	}
+
+	if syncConfig.Teams != nil {
+		log.Printf("Team mapping enabled: %d team(s) by label %s", len(syncConfig.Teams.Teams()), syncConfig.Teams.Label)
+		if len(syncConfig.TicketProjects) > 0 {
+			for _, project := range syncConfig.Teams.Projects() {
+				if !slices.ContainsFunc(syncConfig.TicketProjects, func(allowed string) bool { return strings.EqualFold(allowed, project) }) {
+					log.Printf("Warning: SYNC_TICKET_PROJECTS does not include team project %s; silences of the tickets created for the team are skipped", project)
+				}
+			}
+		}
+	}
+
+	if len(syncConfig.ManagedCreators) > 0 {
+		log.Printf("Managing only silences created by %v", syncConfig.ManagedCreators)
+	}
+	if len(syncConfig.IgnoredCreators) > 0 {
+		log.Printf("Ignoring silences created by %v", syncConfig.IgnoredCreators)
+	}
+	if syncConfig.ProtectLabel != "" {
+		log.Printf("Leaving silences marked %q or with a matcher on label %s alone", syncConfig.ProtectToken, syncConfig.ProtectLabel)
+	} else {
+		log.Printf("Leaving silences marked %q alone", syncConfig.ProtectToken)
+	}
+
+	if cfg.Maintenance.CalendarURL != "" {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: maintenance calendar requires a persistent state backend; silences may be duplicated across runs")
+		}
+		synchronizer.SetMaintenanceCalendar(calendar.NewFeed(cfg.Maintenance.CalendarURL, cfg.Maintenance.Lookahead))
+		log.Printf("Maintenance calendar enabled (lookahead: %v)", syncConfig.MaintenanceLookahead)
+	}
+
+	if cfg.GitOps.Path != "" {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: GitOps requires a persistent state backend; removed definitions cannot be pruned across runs")
+		}
+		synchronizer.SetGitOpsSource(gitops.NewDirectory(cfg.GitOps.Path))
+		log.Printf("GitOps enabled (path: %s, prune: %v)", cfg.GitOps.Path, syncConfig.GitOpsPrune)
+	}
+
+	if cfg.Change.Query != "" {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: change silences require a persistent state backend; silences may be duplicated across runs")
+		}
+		log.Printf("Change silences enabled (query: %s, lookahead: %v)", cfg.Change.Query, syncConfig.MaintenanceLookahead)
+		if cfg.Change.EndField == "" {
+			log.Printf("  Without a planned end field, change silences are scheduled and handed over to the regular lifecycle once started")
+		}
+	}
+
+	if resolver := newOnCallResolver(cfg); resolver != nil {
+		synchronizer.SetOnCallResolver(resolver)
+		log.Printf("On-call assignment enabled (provider: %s, team label: %s)", cfg.OnCall.Provider, syncConfig.OnCallTeamLabel)
+	}
+
+	if len(syncConfig.SeveritySLAs) > 0 {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: severity SLAs require a persistent state backend; tickets may be escalated again on every run")
+		}
+		if cfg.Slack.WebhookURL != "" {
+			synchronizer.SetNotifier(slack.NewNotifier(cfg.Slack.WebhookURL))
+			log.Printf("SLA escalations are posted to the Slack webhook channel")
+		}
+	}
+
+	if syncConfig.DetectDrift {
+		if cfg.State.Backend == "none" {
+			log.Printf("Warning: drift detection requires a persistent state backend; edits made between runs cannot be detected")
+		}
+		log.Printf("Drift detection enabled (ticket comments: %v)", syncConfig.DriftComments)
+	}
+
+	if syncConfig.MaxExtensions > 0 && cfg.OnCall.GrafanaWebhookURL != "" {
+		synchronizer.SetEscalator(oncall.NewGrafanaOnCall(cfg.OnCall.GrafanaWebhookURL))
+		log.Printf("Silences lapsing at the maximum extensions are escalated to Grafana OnCall")
+	}
+
+	if cfg.Rules.APIURL != "" {
+		synchronizer.SetRuleSource(rules.NewPrometheus(cfg.Rules.APIURL, cfg.Rules.APIToken))
+		log.Printf("Removed alerting rule check enabled (rules API: %s)", cfg.Rules.APIURL)
+	}
+	return synchronizer
+}
+
+// newTicketSystem creates the Jira ticket system client
+func newTicketSystem(cfg *config.Config) *ticket.JiraTicketSystem {
+	ts := ticket.NewJiraTicketSystem(
+		cfg.Jira.URL,
+		cfg.Jira.Username,
+		cfg.Jira.APIToken,
+		cfg.Jira.ProjectKey,
+		cfg.Sync.AnnotationPrefix,
+	)
+	if cfg.Jira.CredentialsSecretName != "" {
+		ts.SetCredentialSource(jiraSecretCredentials(cfg))
+	}
+	var customFields []string
+	for _, field := range []string{cfg.Sync.DurationField, cfg.Sync.MatchersField, cfg.Change.StartField, cfg.Change.EndField, cfg.Change.MatchersField} {
+		if field != "" {
+			customFields = append(customFields, field)
+		}
+	}
+	if len(customFields) > 0 {
+		ts.SetCustomFields(customFields)
+	}
+	if cfg.Sync.SilenceRefField != "" {
+		ts.SetSilenceRefField(cfg.Sync.SilenceRefField)
+	}
+	if cfg.Jira.SubTaskIssueType != "" {
+		ts.SetSubTaskIssueType(cfg.Jira.SubTaskIssueType)
+	}
+	if cfg.Jira.ServiceDeskID != "" {
+		fields, err := cfg.GetRequestFields()
+		if err != nil {
+			fatal(withExitCode(exitConfig, fmt.Errorf("invalid Jira request fields: %w", err)))
+		}
+		ts.SetServiceDesk(ticket.ServiceDesk{
+			ID:            cfg.Jira.ServiceDeskID,
+			RequestTypeID: cfg.Jira.RequestTypeID,
+			Fields:        fields,
+			Participants:  cfg.Jira.RequestParticipants,
+			OnBehalfOf:    cfg.Jira.RequestOnBehalfOf,
+		})
+		log.Printf("Raising tickets as requests of type %s in service desk %s", cfg.Jira.RequestTypeID, cfg.Jira.ServiceDeskID)
+	}
+	if len(cfg.Jira.SLAFields) > 0 {
+		ts.SetSLAFields(cfg.Jira.SLAFields)
+	}
+	if tlsConfig := newTLSConfig(cfg); tlsConfig != nil {
+		ts.SetTLSConfig(tlsConfig)
+	}
+	statuses, err := cfg.GetJiraStatuses()
+	if err != nil {
+		fatal(withExitCode(exitConfig, fmt.Errorf("invalid Jira status mapping: %w", err)))
+	}
+	ts.SetWorkflow(ticket.Workflow{
+		Statuses:         statuses,
+		ReopenTransition: cfg.Jira.ReopenTransition,
+		CloseTransition:  cfg.Jira.CloseTransition,
+	})
+	ts.SetRateLimit(ticket.RateLimit{
+		MaxRetries: cfg.Jira.MaxRetries,
+		MaxWait:    cfg.Jira.MaxRetryWait,
+		Budget:     cfg.Jira.RequestBudget,
+	})
+	log.Println("Initialized Jira ticket system client")
+	return ts
+}
+
+// newTLSConfig returns the TLS configuration shared by the Alertmanager, Jira and
+// Pushgateway clients, or nil if the defaults apply
+func newTLSConfig(cfg *config.Config) *tls.Config {
+	tlsConfig, err := cfg.GetTLSConfig()
+	if err != nil {
+		fatal(withExitCode(exitConfig, fmt.Errorf("invalid TLS configuration: %w", err)))
+	}
+	return tlsConfig
+}
 
+// newSyncConfig converts the loaded configuration into the synchronizer configuration
+func newSyncConfig(cfg *config.Config) (sync.SyncConfig, error) {
+	businessHours, err := cfg.GetBusinessHours()
+	if err != nil {
+		return sync.SyncConfig{}, withExitCode(exitConfig, fmt.Errorf("invalid business hours configuration: %w", err))
+	}
+	severityPriorities, err := cfg.GetSeverityPriorities()
+	if err != nil {
+		return sync.SyncConfig{}, withExitCode(exitConfig, fmt.Errorf("invalid severity priorities: %w", err))
+	}
+	priorityExtensions, err := cfg.GetPriorityExtensions()
+	if err != nil {
+		return sync.SyncConfig{}, withExitCode(exitConfig, fmt.Errorf("invalid priority extensions: %w", err))
+	}
+	severitySLAs, err := cfg.GetSeveritySLAs()
+	if err != nil {
+		return sync.SyncConfig{}, withExitCode(exitConfig, fmt.Errorf("invalid severity SLAs: %w", err))
+	}
+	onCallSchedules, err := cfg.GetOnCallSchedules()
+	if err != nil {
+		return sync.SyncConfig{}, withExitCode(exitConfig, fmt.Errorf("invalid on-call schedules: %w", err))
+	}
+	reopenStrategy, err := sync.ParseReopenStrategy(cfg.Sync.ReopenStrategy)
+	if err != nil {
+		return sync.SyncConfig{}, withExitCode(exitConfig, err)
+	}
+	teamDirectory, err := loadTeams(cfg)
+	if err != nil {
+		return sync.SyncConfig{}, withExitCode(exitConfig, err)
+	}
+	discoveryQuery := ""
+	if cfg.Sync.TicketDiscovery {
+		discoveryQuery = cfg.Sync.DiscoveryJQL
+		if discoveryQuery == "" {
+			var labels []string
+			if cfg.Sync.LifecycleLabels {
+				labels = []string{sync.LabelSilenceActive, sync.LabelSilenceExpiringSoon, sync.LabelSilenceExpired}
+			}
+			discoveryQuery = ticket.SilenceRefJQL(cfg.Jira.ProjectKey, cfg.Sync.SilenceRefField, labels)
+		}
+	}
+	ackPrefix := ""
+	if cfg.Sync.KarmaAcks {
+		ackPrefix = cfg.Sync.KarmaAckPrefix
+	}
+	return sync.SyncConfig{
+		ExpiryThreshold:        cfg.Sync.ExpiryThreshold,
+		ClockTolerance:         cfg.Sync.ClockTolerance,
+		UseServerTime:          cfg.Sync.UseServerTime,
+		ExtensionDuration:      cfg.Sync.ExtensionDuration,
+		DefaultSilenceDuration: cfg.Sync.DefaultSilenceDuration,
+		CheckAlerts:            cfg.Sync.CheckAlerts,
+		ProcessDirectives:      cfg.Sync.ProcessDirectives,
+		DurationLabelPrefix:    cfg.Sync.DurationLabelPrefix,
+		DurationField:          cfg.Sync.DurationField,
+		DurationFieldUnit:      durationFieldUnit(cfg.Sync.DurationFieldUnit),
+		RequireAssignee:        cfg.Sync.RequireAssignee,
+		BusinessHours:          businessHours,
+		LifecycleLabels:        cfg.Sync.LifecycleLabels,
+		UpdateDueDate:          cfg.Sync.UpdateDueDate,
+		ExtensionHistory:       cfg.Sync.ExtensionHistory,
+		AutoCloseAfter:         time.Duration(cfg.Sync.AutoCloseAfterDays) * 24 * time.Hour,
+		RecreateOnReopenFor:    time.Duration(cfg.Sync.RecreateOnReopenDays) * 24 * time.Hour,
+		ExpireIdleAfter:        time.Duration(cfg.Sync.ExpireIdleAfterDays) * 24 * time.Hour,
+		MaxExtensions:          cfg.Sync.MaxExtensions,
+		JournalRetention:       time.Duration(cfg.Sync.JournalDays) * 24 * time.Hour,
+		SilenceUIURL:           cfg.Alertmanager.ExternalURL,
+		TicketMatchers:         cfg.Sync.TicketMatchers,
+		MatchersField:          cfg.Sync.MatchersField,
+		SeverityPriorities:     severityPriorities,
+		PriorityExtensions:     priorityExtensions,
+		SeveritySLAs:           severitySLAs,
+		SLAPriority:            cfg.Sync.SLAPriority,
+		SLALabel:               cfg.Sync.SLALabel,
+		AckPrefix:              ackPrefix,
+		OnCallTeamLabel:        cfg.OnCall.TeamLabel,
+		OnCallSchedules:        onCallSchedules,
+		Teams:                  teamDirectory,
+		MaintenanceLookahead:   cfg.Maintenance.Lookahead,
+		GitOpsPrune:            cfg.GitOps.Prune,
+		ChangeQuery:            cfg.Change.Query,
+		ChangeStartField:       cfg.Change.StartField,
+		ChangeEndField:         cfg.Change.EndField,
+		ChangeMatchersField:    cfg.Change.MatchersField,
+		DiscoveryQuery:         discoveryQuery,
+		ReopenStrategy:         reopenStrategy,
+		RefireLinkType:         cfg.Sync.RefireLinkType,
+		RefireCopyLabels:       cfg.Sync.RefireCopyLabels,
+		RefireSubTasks:         cfg.Sync.RefireSubTasks,
+		Watchers:               cfg.Sync.Watchers,
+		TicketProjects:         cfg.Sync.TicketProjects,
+		ManagedCreators:        cfg.Sync.ManagedCreators,
+		IgnoredCreators:        cfg.Sync.IgnoredCreators,
+		CheckSilencedAlerts:    cfg.Sync.CheckSilencedAlerts || cfg.Sync.ExpireIdleAfterDays > 0,
+		DetectDrift:            cfg.Sync.DetectDrift,
+		DriftComments:          cfg.Sync.DriftComments,
+		ProtectToken:           cfg.Sync.ProtectToken,
+		ProtectLabel:           cfg.Sync.ProtectLabel,
+	}, nil
+}
+
+// durationFieldUnit converts SYNC_DURATION_FIELD_UNIT, validated by config.Load
+func durationFieldUnit(unit string) time.Duration {
+	if unit == "hours" {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// loadTeams reads the team mapping of TEAMS_FILE, or returns nil without the setting
+func loadTeams(cfg *config.Config) (*teams.Directory, error) {
+	if cfg.Teams.File == "" {
+		return nil, nil
+	}
+	return teams.Load(cfg.Teams.File)
+}
+
+// newOnCallResolver creates the on-call schedule client, or returns nil if on-call
+// assignment is disabled
+func newOnCallResolver(cfg *config.Config) oncall.Resolver {
+	switch cfg.OnCall.Provider {
+	case "pagerduty":
+		return oncall.NewPagerDuty(cfg.OnCall.APIURL, cfg.OnCall.APIToken)
+	case "opsgenie":
+		return oncall.NewOpsgenie(cfg.OnCall.APIURL, cfg.OnCall.APIToken)
+	default:
+		return nil
+	}
+}
+
+// discoveryScheme describes the configured discovery scheme for logging
+func discoveryScheme(scheme string) string {
+	if scheme == "" {
+		return "auto"
+	}
+	return scheme
+}
+
+// discoveryScope describes which namespaces discovery searches beyond the preferred ones
+// for logging
+func discoveryScope(namespacesOnly bool, selector string) string {
+	switch {
+	case namespacesOnly:
+		return "namespaces-only"
+	case selector != "":
+		return "selector " + selector
+	default:
+		return "all"
+	}
+}
+
+// newAlertManager creates the Alertmanager client, discovering its URL when configured to
+func newAlertManager(cfg *config.Config) (alertmanager.AlertManager, error) {
 	// Determine Alertmanager URL (auto-discovery or explicit)
 	alertmanagerURL := cfg.Alertmanager.URL
 	if cfg.Alertmanager.AutoDiscover {
 		log.Println("Alertmanager auto-discovery enabled")
-		log.Printf("Discovery config: service-name=%s, label=%s, port=%d, namespaces=%v",
+		log.Printf("Discovery config: service-name=%s, label=%s, port=%d, namespaces=%v, scope=%s, scheme=%s, mode=%s",
 			cfg.Alertmanager.DiscoveryServiceName,
 			cfg.Alertmanager.DiscoveryServiceLabel,
 			cfg.Alertmanager.DiscoveryPort,
-			cfg.Alertmanager.DiscoveryNamespaces)
+			cfg.Alertmanager.DiscoveryNamespaces,
+			discoveryScope(cfg.Alertmanager.DiscoveryNamespacesOnly, cfg.Alertmanager.DiscoveryNamespaceSelector),
+			discoveryScheme(cfg.Alertmanager.DiscoveryScheme),
+			cfg.Alertmanager.DiscoveryMode)
 
-		discovered, err := k8s.DiscoverAlertmanager(k8s.DiscoveryConfig{
-			ServiceName:      cfg.Alertmanager.DiscoveryServiceName,
-			ServiceLabel:     cfg.Alertmanager.DiscoveryServiceLabel,
-			Port:             cfg.Alertmanager.DiscoveryPort,
-			PreferNamespaces: cfg.Alertmanager.DiscoveryNamespaces,
-		})
+		discovered, err := discoverAlertmanager(cfg)
 		if err != nil {
-			log.Fatalf("Failed to discover Alertmanager: %v", err)
-			os.Exit(1)
+			return nil, withExitCode(exitDiscovery, fmt.Errorf("failed to discover Alertmanager: %w", err))
 		}
 		alertmanagerURL = discovered.URL
 		log.Printf("Using discovered Alertmanager: %s", alertmanagerURL)
 	} else {
-		log.Printf("Using configured Alertmanager URL: %s", alertmanagerURL)
+		log.Printf("Using configured Alertmanager URL: %s", config.RedactURL(alertmanagerURL))
 	}
 
-	log.Printf("Alertmanager URL: %s", alertmanagerURL)
+	log.Printf("Alertmanager URL: %s", config.RedactURL(alertmanagerURL))
 	log.Printf("Alertmanager Auth Type: %s", cfg.Alertmanager.AuthType)
+	log.Printf("Alertmanager request timeout: %v", cfg.Alertmanager.Timeout)
+
+	ticketRefPattern, err := cfg.GetTicketRefPattern()
+	if err != nil {
+		return nil, withExitCode(exitConfig, fmt.Errorf("invalid ticket reference pattern: %w", err))
+	}
+	if ticketRefPattern != nil {
+		log.Printf("Adopting silences whose comment matches %s", ticketRefPattern)
+	}
+	silenceFilter, err := cfg.GetSilenceFilter()
+	if err != nil {
+		return nil, withExitCode(exitConfig, fmt.Errorf("invalid silence filter: %w", err))
+	}
+	if len(silenceFilter) > 0 {
+		log.Printf("Fetching only silences matching %v", silenceFilter)
+	}
+
+	var oidc *alertmanager.OIDCConfig
+	if cfg.Alertmanager.AuthType == "bearer" && cfg.Alertmanager.OIDCClientID != "" {
+		oidc = &alertmanager.OIDCConfig{
+			IssuerURL:    cfg.Alertmanager.OIDCIssuerURL,
+			TokenURL:     cfg.Alertmanager.OIDCTokenURL,
+			ClientID:     cfg.Alertmanager.OIDCClientID,
+			ClientSecret: cfg.Alertmanager.OIDCClientSecret,
+			Scopes:       cfg.Alertmanager.OIDCScopes,
+			Audience:     cfg.Alertmanager.OIDCAudience,
+		}
+		log.Printf("Fetching Alertmanager bearer tokens as OIDC client %s", oidc.ClientID)
+	}
 
 	// Initialize Alertmanager client
 	am := alertmanager.NewPrometheusAlertManagerWithConfig(alertmanager.AlertManagerConfig{
@@ -69,81 +775,141 @@ func main() {
 		Username:         cfg.Alertmanager.Username,
 		Password:         cfg.Alertmanager.Password,
 		BearerToken:      cfg.Alertmanager.BearerToken,
+		BearerTokenFile:  cfg.Alertmanager.BearerTokenFile,
+		OIDC:             oidc,
+		ClientCertFile:   cfg.Alertmanager.ClientCertFile,
+		ClientKeyFile:    cfg.Alertmanager.ClientKeyFile,
 		AnnotationPrefix: cfg.Sync.AnnotationPrefix,
+		TicketRefPattern: ticketRefPattern,
+		SilenceFilter:    silenceFilter,
+		TLSConfig:        newTLSConfig(cfg),
+
+		Timeout:             cfg.Alertmanager.Timeout,
+		MaxIdleConns:        cfg.Alertmanager.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.Alertmanager.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.Alertmanager.IdleConnTimeout,
+		DisableKeepAlives:   cfg.Alertmanager.DisableKeepAlives,
 	})
 	log.Println("Initialized Prometheus Alertmanager client")
+	return am, nil
+}
 
-	// Initialize Jira client
-	ts := ticket.NewJiraTicketSystem(
-		cfg.Jira.URL,
-		cfg.Jira.Username,
-		cfg.Jira.APIToken,
-		cfg.Jira.ProjectKey,
-		cfg.Sync.AnnotationPrefix,
-	)
-	log.Println("Initialized Jira ticket system client")
+// logResult logs the results of a synchronization run and reports whether it succeeded
+func logResult(result *sync.SyncResult) bool {
+	// Log results
+	log.Println("=== Synchronization Results ===")
+	log.Printf("Silences extended: %d", result.SilencesExtended)
+	log.Printf("Silences deleted: %d", result.SilencesDeleted)
+	log.Printf("Silences created: %d", result.SilencesCreated)
+	log.Printf("Tickets reopened: %d", result.TicketsReopened)
+	log.Printf("Tickets closed (alerts quiet): %d", result.TicketsClosed)
+	log.Printf("Directives applied: %d", result.DirectivesApplied)
+	log.Printf("Extensions withheld (no assignee): %d", result.ExtensionsWithheld)
+	log.Printf("Ticket labels updated: %d", result.LabelsUpdated)
+	log.Printf("Matchers updated from tickets: %d", result.MatchersUpdated)
+	log.Printf("Maintenance silences created: %d, retired: %d", result.MaintenanceCreated, result.MaintenanceRetired)
+	log.Printf("Declared silences applied: %d, pruned: %d", result.GitOpsApplied, result.GitOpsPruned)
+	log.Printf("Change silences created: %d, removed: %d", result.ChangeSilencesCreated, result.ChangeSilencesRemoved)
+	log.Printf("Silences recreated for discovered tickets: %d", result.TicketsDiscovered)
+	log.Printf("Silences recreated for reopened tickets: %d", result.SilencesRecreated)
+	log.Printf("Follow-up tickets for refired alerts: %d", result.FollowUpTickets)
+	log.Printf("Sub-tasks for refired alert instances: %d", result.SubTasksCreated)
+	log.Printf("Closed tickets commented for refired alerts: %d", result.RefireComments)
+	log.Printf("Hygiene: silences=%d open-ticket-ratio=%.2f orphan-ratio=%.2f median-age=%v",
+		result.Hygiene.TotalSilences, result.Hygiene.OpenTicketRatio(), result.Hygiene.OrphanRatio(), result.Hygiene.MedianAge)
+	log.Printf("Errors: %d%s", len(result.Errors), errorBreakdown(result))
 
-	// Create synchronizer
-	expiryThreshold, extensionDuration, defaultSilenceDuration := cfg.GetSyncDurations()
-	syncConfig := sync.SyncConfig{
-		ExpiryThreshold:        expiryThreshold,
-		ExtensionDuration:      extensionDuration,
-		DefaultSilenceDuration: defaultSilenceDuration,
-		CheckAlerts:            cfg.Sync.CheckAlerts,
+	if len(result.Errors) > 0 {
+		log.Println("Errors encountered:")
+		for i, err := range result.Errors {
+			log.Printf("  %d. %v", i+1, err)
+		}
+		return false
 	}
+	return true
+}
 
-	log.Printf("Sync configuration:")
-	log.Printf("  Annotation prefix: %s", cfg.Sync.AnnotationPrefix)
-	log.Printf("  Expiry threshold: %v", syncConfig.ExpiryThreshold)
-	log.Printf("  Extension duration: %v", syncConfig.ExtensionDuration)
-	log.Printf("  Default silence duration: %v", syncConfig.DefaultSilenceDuration)
-	log.Printf("  Check alerts: %v", syncConfig.CheckAlerts)
+// errorBreakdown formats the errors of a run by kind of failure, e.g. " (auth=1, transient=2)"
+func errorBreakdown(result *sync.SyncResult) string {
+	counts := result.ErrorsByCategory()
+	var parts []string
+	for _, category := range apierror.Categories {
+		if counts[category] > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", category, counts[category]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
 
-	synchronizer := sync.NewSynchronizer(am, ts, syncConfig)
-	log.Println("Created synchronizer")
+// newMetricsURL returns the configured metrics backend URL, or discovers it
+func newMetricsURL(cfg *config.Config) (string, error) {
+	metricsURL := cfg.Metrics.URL
+	if cfg.Metrics.AutoDiscover {
+		log.Println("Metrics backend auto-discovery enabled")
+		log.Printf("Discovery config: service-name=%s, label=%s, port=%d, namespaces=%v, scope=%s",
+			cfg.Metrics.DiscoveryServiceName,
+			cfg.Metrics.DiscoveryServiceLabel,
+			cfg.Metrics.DiscoveryPort,
+			cfg.Metrics.DiscoveryNamespaces,
+			discoveryScope(cfg.Metrics.DiscoveryNamespacesOnly, cfg.Metrics.DiscoveryNamespaceSelector))
 
-	// Initialize metrics publisher if enabled
-	if cfg.Metrics.Enabled {
-		log.Printf("Metrics publishing enabled: backend=%s", cfg.Metrics.Backend)
+		var discovered *k8s.DiscoveredService
+		var discErr error
 
-		metricsURL := cfg.Metrics.URL
-		if cfg.Metrics.AutoDiscover {
-			log.Println("Metrics backend auto-discovery enabled")
-			log.Printf("Discovery config: service-name=%s, label=%s, port=%d, namespaces=%v",
-				cfg.Metrics.DiscoveryServiceName,
-				cfg.Metrics.DiscoveryServiceLabel,
-				cfg.Metrics.DiscoveryPort,
-				cfg.Metrics.DiscoveryNamespaces)
-
-			var discovered *k8s.DiscoveredService
-			var discErr error
-
-			discoveryConfig := k8s.DiscoveryConfig{
-				ServiceName:      cfg.Metrics.DiscoveryServiceName,
-				ServiceLabel:     cfg.Metrics.DiscoveryServiceLabel,
-				Port:             cfg.Metrics.DiscoveryPort,
-				PreferNamespaces: cfg.Metrics.DiscoveryNamespaces,
-			}
+		discoveryConfig := k8s.DiscoveryConfig{
+			ServiceName:       cfg.Metrics.DiscoveryServiceName,
+			ServiceLabel:      cfg.Metrics.DiscoveryServiceLabel,
+			Port:              cfg.Metrics.DiscoveryPort,
+			PreferNamespaces:  cfg.Metrics.DiscoveryNamespaces,
+			NamespacesOnly:    cfg.Metrics.DiscoveryNamespacesOnly,
+			NamespaceSelector: cfg.Metrics.DiscoveryNamespaceSelector,
+			HealthCheck:       true,
+			TLSConfig:         newTLSConfig(cfg),
+		}
 
-			switch cfg.Metrics.Backend {
-			case "pushgateway":
-				discovered, discErr = k8s.DiscoverPushgateway(discoveryConfig)
-			case "otel":
-				discovered, discErr = k8s.DiscoverOTelCollector(discoveryConfig)
-			default:
-				log.Fatalf("Unknown metrics backend: %s", cfg.Metrics.Backend)
-				os.Exit(1)
-			}
+		switch cfg.Metrics.Backend {
+		case "pushgateway":
+			discovered, discErr = k8s.DiscoverPushgateway(discoveryConfig)
+		case "otel":
+			discovered, discErr = k8s.DiscoverOTelCollector(discoveryConfig)
+		default:
+			return "", withExitCode(exitConfig, fmt.Errorf("unknown metrics backend: %s", cfg.Metrics.Backend))
+		}
+
+		if discErr != nil {
+			return "", withExitCode(exitDiscovery, fmt.Errorf("failed to discover metrics backend: %w", discErr))
+		}
 
-			if discErr != nil {
-				log.Fatalf("Failed to discover metrics backend: %v", discErr)
-				os.Exit(1)
+		metricsURL = discovered.URL
+		log.Printf("Using discovered metrics backend: %s", metricsURL)
+	} else {
+		log.Printf("Using configured metrics backend URL: %s", config.RedactURL(metricsURL))
+	}
+	return metricsURL, nil
+}
+
+// newMetricsPublisher creates the metrics publisher selected by the configuration,
+// falling back to a no-op publisher when metrics are disabled
+func newMetricsPublisher(cfg *config.Config) (metrics.Publisher, error) {
+	if cfg.Metrics.Enabled {
+		log.Printf("Metrics publishing enabled: backend=%s", cfg.Metrics.Backend)
+
+		if cfg.Metrics.Backend == "prometheus" {
+			if metricsExporter == nil {
+				log.Println("Warning: metrics are only served on /metrics by the daemon and operator, not publishing the metrics of this run")
+				return metrics.NewNoopPublisher(), nil
 			}
+			publisher := metricsExporter.NewPublisher()
+			publisher.RecordBuildInfo(version, commit, date)
+			return publisher, nil
+		}
 
-			metricsURL = discovered.URL
-			log.Printf("Using discovered metrics backend: %s", metricsURL)
-		} else {
-			log.Printf("Using configured metrics backend URL: %s", metricsURL)
+		metricsURL, err := newMetricsURL(cfg)
+		if err != nil {
+			return nil, err
 		}
 
 		var publisher metrics.Publisher
@@ -152,8 +918,10 @@ func main() {
 		switch cfg.Metrics.Backend {
 		case "pushgateway":
 			publisher, metricsErr = metrics.NewPushgatewayPublisher(metrics.PushgatewayConfig{
-				URL:     metricsURL,
-				JobName: cfg.Metrics.JobName,
+				URL:       metricsURL,
+				JobName:   cfg.Metrics.JobName,
+				Instance:  cfg.Metrics.PushgatewayInstance,
+				TLSConfig: newTLSConfig(cfg),
 			})
 		case "otel":
 			publisher, metricsErr = metrics.NewOTelPublisher(metrics.OTelConfig{
@@ -161,54 +929,19 @@ func main() {
 				Insecure: cfg.Metrics.OTelInsecure,
 			})
 		default:
-			log.Fatalf("Unknown metrics backend: %s", cfg.Metrics.Backend)
-			os.Exit(1)
+			return nil, withExitCode(exitConfig, fmt.Errorf("unknown metrics backend: %s", cfg.Metrics.Backend))
 		}
 
 		if metricsErr != nil {
-			log.Fatalf("Failed to initialize metrics publisher: %v", metricsErr)
-			os.Exit(1)
+			return nil, fmt.Errorf("failed to initialize metrics publisher: %w", metricsErr)
 		}
 
 		// Record build info
 		publisher.RecordBuildInfo(version, commit, date)
-
-		// Set the publisher on the synchronizer
-		synchronizer.SetMetricsPublisher(publisher)
 		log.Printf("Metrics publisher initialized and configured")
-
-		// Ensure we close the publisher when done
-		defer func() {
-			if err := publisher.Close(); err != nil {
-				log.Printf("Warning: failed to close metrics publisher: %v", err)
-			}
-		}()
-	} else {
-		log.Println("Metrics publishing disabled")
-	}
-
-	// Perform synchronization
-	log.Println("Starting synchronization run...")
-	result, err := synchronizer.Sync()
-	if err != nil {
-		log.Printf("Synchronization completed with errors: %v", err)
+		return publisher, nil
 	}
 
-	// Log results
-	log.Println("=== Synchronization Results ===")
-	log.Printf("Silences extended: %d", result.SilencesExtended)
-	log.Printf("Silences deleted: %d", result.SilencesDeleted)
-	log.Printf("Silences created: %d", result.SilencesCreated)
-	log.Printf("Tickets reopened: %d", result.TicketsReopened)
-	log.Printf("Errors: %d", len(result.Errors))
-
-	if len(result.Errors) > 0 {
-		log.Println("Errors encountered:")
-		for i, err := range result.Errors {
-			log.Printf("  %d. %v", i+1, err)
-		}
-		os.Exit(1)
-	}
-
-	log.Println("Synchronization completed successfully")
+	log.Println("Metrics publishing disabled")
+	return metrics.NewNoopPublisher(), nil
 }
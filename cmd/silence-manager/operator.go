@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+	"github.com/conallob/silence-manager/pkg/operator"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// runOperator reconciles SilencePolicy resources into managed silences and tickets until
+// the process is terminated. Silences created for policies are extended and expired by
+// the regular sync, which runs alongside as a CronJob or daemon.
+func runOperator(args []string) {
+	log.Printf("Starting silence-manager operator version=%s commit=%s date=%s", version, commit, date)
+
+	cfg := loadConfig()
+
+	fs := flag.NewFlagSet("operator", flag.ExitOnError)
+	namespace := fs.String("namespace", cfg.Operator.Namespace, "Namespace of the watched SilencePolicies (default: all namespaces)")
+	resync := fs.Duration("resync", cfg.Operator.ResyncInterval, "Longest time between reconciliations of a SilencePolicy")
+	fs.Parse(args)
+	if *resync <= 0 {
+		log.Fatalf("Resync interval must be positive")
+	}
+
+	role, _ := authz.ParseRole(cfg.Auth.Role) // Validated by LoadConfig
+	if err := authz.Authorize(role, authz.OpWrite, false); err != nil {
+		log.Fatalf("Refusing to run the operator: %v", err)
+	}
+
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	syncConfig, err := newSyncConfig(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	mgr, err := k8s.NewPolicyManager(*namespace)
+	if err != nil {
+		fatal(withExitCode(exitConfig, err))
+	}
+
+	reconciler := operator.NewReconciler(am, projectBackends(cfg, am, syncConfig))
+	controller := operator.NewController(k8s.NewPolicyClient(mgr.GetClient()), reconciler, *resync)
+	if err := k8s.WatchPolicies(mgr, controller); err != nil {
+		fatal(withExitCode(exitConfig, err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The controller reports no cycles, so liveness only reflects that the process serves
+	startHealthServer(ctx, cfg, k8s.ModeOperator, 0)
+
+	// Only the leader reconciles; the other replicas wait to take over
+	if elector := newLeaderElector(cfg, k8s.ModeOperator); elector != nil {
+		leading := make(chan struct{})
+		electionDone := runLeaderElection(ctx, elector, func() { close(leading) })
+		defer func() { <-electionDone }()
+
+		log.Println("Waiting for leadership")
+		select {
+		case <-ctx.Done():
+			log.Println("Shutting down operator")
+			return
+		case <-leading:
+		}
+	}
+
+	if *namespace == "" {
+		log.Printf("Watching SilencePolicies in all namespaces, reconciling at least every %v", *resync)
+	} else {
+		log.Printf("Watching SilencePolicies in namespace %s, reconciling at least every %v", *namespace, *resync)
+	}
+	if err := mgr.Start(ctx); err != nil {
+		fatal(err)
+	}
+	log.Println("Shutting down operator")
+}
+
+// projectBackends returns the ticket system and synchronizer of each Jira project, created
+// on first use. An empty project selects JIRA_PROJECT_KEY. The controller reconciles one
+// policy at a time (controller-runtime's default of one worker), so the cache needs no
+// locking. The reconciler resets the request budget of a cached client on every reconcile.
+func projectBackends(cfg *config.Config, am alertmanager.AlertManager, syncConfig sync.SyncConfig) operator.Backends {
+	backends := make(map[string]operator.Backend)
+	return func(project string) (operator.Backend, error) {
+		if project == "" {
+			project = cfg.Jira.ProjectKey
+		}
+		if backend, ok := backends[project]; ok {
+			return backend, nil
+		}
+
+		projectCfg := *cfg
+		projectCfg.Jira.ProjectKey = project
+		ts := newTicketSystem(&projectCfg)
+		backend := operator.Backend{Tickets: ts, Synchronizer: sync.NewSynchronizer(am, ts, syncConfig)}
+		backends[project] = backend
+		return backend, nil
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/plan"
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// runPlan performs a synchronization run against clients that record writes instead of
+// applying them, and prints the recorded changes. The persisted state is read but never
+// saved, and no metrics or inventory are published.
+func runPlan(cfg *config.Config, output string) {
+	if output != "text" && output != "json" {
+		log.Fatalf("Unknown output format: %s (must be 'text' or 'json')", output)
+	}
+
+	p, err := planSync(cfg)
+	if err != nil {
+		fatal(err)
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(p)
+	} else {
+		err = p.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write plan: %v", err)
+	}
+}
+
+// planSync records the changes one synchronization run would make
+func planSync(cfg *config.Config) (*plan.Plan, error) {
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	syncConfig, err := newSyncConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := plan.New()
+	synchronizer := newSynchronizer(cfg, plan.WrapAlertManager(am, p), plan.WrapTicketSystem(newTicketSystem(cfg), p), syncConfig)
+
+	// Decide on the persisted state, but keep the run's updates in memory
+	st, err := newStateStore(cfg).Load()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := state.NewMemoryStore()
+	snapshot.Save(st)
+	synchronizer.SetStateStore(snapshot)
+
+	result, err := synchronizer.Sync()
+	if err != nil {
+		return nil, withExitCode(runExitCode(err), err)
+	}
+	for _, err := range result.Errors {
+		log.Printf("Warning: %v", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/api"
+)
+
+// runPurge deletes silences in bulk through the REST API of the server at API_URL. The
+// server authorizes the deletion by the role of the bearer token in API_TOKEN: admin
+// tokens may purge, operator tokens only with --confirm, so automation cannot raise its
+// own privileges. Protected silences and silences of unmanaged creators are never deleted.
+func runPurge(args []string) {
+	cfg := loadConfig()
+
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	scope := fs.String("scope", api.PurgeOrphans, "Silences to delete: orphans (no ticket reference) or all")
+	confirm := fs.Bool("confirm", false, "Confirm the bulk deletion")
+	dryRun := fs.Bool("dry-run", false, "List the silences that would be deleted without deleting them")
+	server := fs.String("server", cfg.API.URL, "Base URL of the silence-manager API performing the purge")
+	fs.Parse(args)
+
+	if *scope != api.PurgeOrphans && *scope != api.PurgeAll {
+		log.Fatalf("Unknown scope: %s (must be 'orphans' or 'all')", *scope)
+	}
+	if *server == "" || cfg.API.Token == "" {
+		fatal(withExitCode(exitConfig, errors.New("purge is performed through the API: set API_URL (or --server) and API_TOKEN")))
+	}
+
+	client := api.NewClient(*server, cfg.API.Token)
+	result, err := client.Purge(context.Background(), *scope, *confirm, *dryRun)
+	if err != nil {
+		var statusErr *api.StatusError
+		switch {
+		case !errors.As(err, &statusErr):
+			fatal(withExitCode(exitBackendUnavailable, err))
+		case statusErr.StatusCode == http.StatusUnauthorized:
+			fatal(withExitCode(exitAuth, fmt.Errorf("API rejected the token in API_TOKEN: %w", err)))
+		case statusErr.StatusCode == http.StatusForbidden && !*confirm:
+			log.Fatalf("Refusing to purge silences: %v (re-run with --confirm)", err)
+		default:
+			log.Fatalf("Refusing to purge silences: %v", err)
+		}
+	}
+
+	fmt.Printf("%d silence(s) in scope %q:\n", len(result.Silences), result.Scope)
+	for _, silence := range result.Silences {
+		fmt.Printf("  %s  ends %s  ticket=%q  created-by=%s\n",
+			silence.ID, silence.EndsAt.Format(time.RFC3339), silence.TicketRef, silence.CreatedBy)
+	}
+	if *dryRun {
+		return
+	}
+
+	log.Printf("Deleted %d of %d silence(s)", result.Deleted, len(result.Silences))
+	if result.Deleted != len(result.Silences) {
+		os.Exit(exitPartialFailure)
+	}
+}
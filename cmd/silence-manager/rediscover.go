@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+)
+
+// alertmanagerCache is set by the daemon to keep the discovered Alertmanager across runs.
+// Other commands leave it nil and discover on every call.
+var alertmanagerCache *discoveryCache
+
+// discoveryCache holds the discovered Alertmanager while a watch on its service reports no
+// change. When the service is deleted or its ports change, the entry is dropped and
+// Alertmanager is discovered again, so redeployments need no restart.
+type discoveryCache struct {
+	ctx     context.Context
+	cfg     *config.Config
+	watcher *k8s.ServiceWatcher
+
+	mu      sync.Mutex
+	service *k8s.DiscoveredService
+}
+
+func newDiscoveryCache(ctx context.Context, cfg *config.Config, watcher *k8s.ServiceWatcher) *discoveryCache {
+	return &discoveryCache{ctx: ctx, cfg: cfg, watcher: watcher}
+}
+
+// alertmanagerDiscoveryConfig returns the discovery settings for Alertmanager
+func alertmanagerDiscoveryConfig(cfg *config.Config) k8s.DiscoveryConfig {
+	return k8s.DiscoveryConfig{
+		ServiceName:       cfg.Alertmanager.DiscoveryServiceName,
+		ServiceLabel:      cfg.Alertmanager.DiscoveryServiceLabel,
+		Port:              cfg.Alertmanager.DiscoveryPort,
+		PreferNamespaces:  cfg.Alertmanager.DiscoveryNamespaces,
+		NamespacesOnly:    cfg.Alertmanager.DiscoveryNamespacesOnly,
+		NamespaceSelector: cfg.Alertmanager.DiscoveryNamespaceSelector,
+		Scheme:            cfg.Alertmanager.DiscoveryScheme,
+		HealthCheck:       cfg.Alertmanager.DiscoveryHealthCheck,
+		TLSConfig:         newTLSConfig(cfg),
+		Endpoints:         cfg.Alertmanager.DiscoveryMode == "endpoints",
+	}
+}
+
+// discoverAlertmanager returns the cached Alertmanager service when there is one, and
+// discovers it otherwise
+func discoverAlertmanager(cfg *config.Config) (*k8s.DiscoveredService, error) {
+	if alertmanagerCache != nil {
+		return alertmanagerCache.discover()
+	}
+	return k8s.DiscoverAlertmanager(alertmanagerDiscoveryConfig(cfg))
+}
+
+// discover returns the cached service, discovering and watching it when the cache is empty
+func (c *discoveryCache) discover() (*k8s.DiscoveredService, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.service != nil {
+		return c.service, nil
+	}
+
+	discoveryConfig := alertmanagerDiscoveryConfig(c.cfg)
+	service, err := k8s.DiscoverAlertmanager(discoveryConfig)
+	if err != nil {
+		return nil, err
+	}
+	c.service = service
+	go c.rediscoverOn(c.watcher.Watch(c.ctx, discoveryConfig, *service), service)
+	return service, nil
+}
+
+// rediscoverOn drops service from the cache once changed is closed and discovers
+// Alertmanager again
+func (c *discoveryCache) rediscoverOn(changed <-chan struct{}, service *k8s.DiscoveredService) {
+	select {
+	case <-c.ctx.Done():
+		return
+	case <-changed:
+	}
+
+	log.Printf("Alertmanager service %s/%s was deleted or its ports changed, re-discovering", service.Namespace, service.Name)
+	c.mu.Lock()
+	if c.service == service {
+		c.service = nil
+	}
+	c.mu.Unlock()
+
+	discovered, err := c.discover()
+	if err != nil {
+		log.Printf("Warning: failed to re-discover Alertmanager, retrying on the next run: %v", err)
+		return
+	}
+	log.Printf("Re-discovered Alertmanager: %s", discovered.URL)
+}
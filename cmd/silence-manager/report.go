@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/report"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// runReport prints a silence hygiene report of the activity in the window and the active
+// silences that need attention
+func runReport(args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	window := fs.Duration("window", 7*24*time.Hour, "Time window to report on")
+	longLived := fs.Duration("long-lived", 30*24*time.Hour, "Age from which active silences are listed as long-lived")
+	output := fs.String("output", "markdown", "Output format: markdown, html or json")
+	idle := fs.Bool("idle", false, "List silences that match no alerts (one Alertmanager query per silence)")
+	publish := fs.Bool("publish", false, "Publish the report to the configured Confluence page and Jira issue instead of printing it")
+	fs.Parse(args)
+
+	if *output != "markdown" && *output != "html" && *output != "json" {
+		log.Fatalf("Unknown output format: %s (must be 'markdown', 'html' or 'json')", *output)
+	}
+
+	cfg := loadConfig()
+	if cfg.State.Backend == "none" {
+		log.Fatalf("Reporting requires a persistent state store (set STATE_BACKEND)")
+	}
+	var publishers []report.Publisher
+	if *publish {
+		publishers = newReportPublishers(cfg)
+		if len(publishers) == 0 {
+			fatal(withExitCode(exitConfig, fmt.Errorf("--publish requires REPORT_CONFLUENCE_URL or REPORT_JIRA_ISSUE")))
+		}
+	}
+	st, err := newStateStore(cfg).Load()
+	if err != nil {
+		log.Fatalf("Failed to load state: %v", err)
+	}
+
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	silences, err := am.ListSilences()
+	if err != nil {
+		log.Fatalf("Failed to list silences: %v", err)
+	}
+
+	now := time.Now()
+	r := report.Build(st.Hygiene, silences, *longLived, now.Add(-*window), now)
+	if *idle {
+		r.AddIdle(idleSilences(am, silences, now))
+	}
+	if cfg.Sync.KarmaAcks {
+		var acks []*alertmanager.Silence
+		for _, silence := range silences {
+			if sync.IsAcknowledgement(silence, cfg.Sync.KarmaAckPrefix) {
+				acks = append(acks, silence)
+			}
+		}
+		r.AddAcknowledged(acks)
+	}
+
+	if *publish {
+		failed := 0
+		for _, p := range publishers {
+			if err := p.Publish(context.Background(), r); err != nil {
+				log.Printf("Failed to publish report to %s: %v", p.Target(), err)
+				failed++
+				continue
+			}
+			log.Printf("Published report to %s", p.Target())
+		}
+		if failed > 0 {
+			os.Exit(exitFailure)
+		}
+		return
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(r)
+	case "html":
+		err = r.WriteHTML(os.Stdout)
+	default:
+		err = r.WriteMarkdown(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+// newReportPublishers returns the configured report sinks. Confluence is called with
+// the Jira credentials, as one Atlassian API token covers both.
+func newReportPublishers(cfg *config.Config) []report.Publisher {
+	var publishers []report.Publisher
+	if cfg.Report.ConfluenceURL != "" {
+		confluence := report.ConfluenceConfig{
+			URL:       cfg.Report.ConfluenceURL,
+			SpaceKey:  cfg.Report.ConfluenceSpace,
+			Title:     cfg.Report.ConfluenceTitle,
+			ParentID:  cfg.Report.ConfluenceParentID,
+			Username:  cfg.Jira.Username,
+			APIToken:  cfg.Jira.APIToken,
+			TLSConfig: newTLSConfig(cfg),
+		}
+		if cfg.Jira.CredentialsSecretName != "" {
+			confluence.Credentials = jiraSecretCredentials(cfg)
+		}
+		publishers = append(publishers, report.NewConfluence(confluence))
+	}
+	if cfg.Report.JiraIssue != "" {
+		publishers = append(publishers, report.NewJiraComment(newTicketSystem(cfg), cfg.Report.JiraIssue))
+	}
+	return publishers
+}
+
+// idleSilences returns the started silences that currently match no alerts. Silences
+// whose alerts cannot be queried are left out.
+func idleSilences(am alertmanager.AlertManager, silences []*alertmanager.Silence, now time.Time) []*alertmanager.Silence {
+	idle := make([]*alertmanager.Silence, 0)
+	for _, s := range silences {
+		if s.StartsAt.After(now) {
+			continue
+		}
+		alerts, err := am.GetSilencedAlerts(s.ID)
+		if err != nil {
+			log.Printf("Warning: failed to get alerts silenced by %s: %v", s.ID, err)
+			continue
+		}
+		if len(alerts) == 0 {
+			idle = append(idle, s)
+		}
+	}
+	return idle
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// runRevertExtension moves the end of a silence back to before its latest extension
+func runRevertExtension(args []string) {
+	fs := flag.NewFlagSet("revert-extension", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: silence-manager revert-extension <silence-id>\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	synchronizer := newLinkSynchronizer(loadConfig(), "revert the extension of")
+	silence, err := synchronizer.RevertExtension(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to revert extension: %v", err)
+	}
+	fmt.Printf("Reverted extension of silence %s, now expiring at %s\n", silence.ID, silence.EndsAt.Format(time.RFC3339))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/plan"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// runRollback lists the journaled runs, or undoes the deletions and extensions of one
+func runRollback(args []string) {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	runID := fs.String("run", "", "ID of the run to roll back, as logged by the run and listed by --list")
+	list := fs.Bool("list", false, "List the journaled runs and their actions")
+	planOnly := fs.Bool("plan", false, "Print the changes the rollback would make without applying them")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: silence-manager rollback --list | --run <id> [--plan]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *list == (*runID != "") {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	cfg := loadConfig()
+	if cfg.Sync.JournalDays == 0 {
+		log.Printf("Warning: SYNC_JOURNAL_DAYS is not set, so runs are no longer journaled")
+	}
+
+	if *list {
+		synchronizer, err := newOperationSynchronizer(cfg)
+		if err != nil {
+			fatal(err)
+		}
+		runs, err := synchronizer.JournalRuns()
+		if err != nil {
+			log.Fatalf("Failed to read journal: %v", err)
+		}
+		if len(runs) == 0 {
+			fmt.Println("No journaled runs")
+			return
+		}
+		for _, run := range runs {
+			fmt.Printf("%s  %s\n", run.ID, journalSummary(run))
+		}
+		return
+	}
+
+	role, _ := authz.ParseRole(cfg.Auth.Role) // Validated by LoadConfig
+	if err := authz.Authorize(role, authz.OpWrite, false); err != nil {
+		log.Fatalf("Refusing to roll back run: %v", err)
+	}
+
+	am, err := newAlertManager(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	syncConfig, err := newSyncConfig(cfg)
+	if err != nil {
+		fatal(err)
+	}
+	ts := newTicketSystem(cfg)
+	if *planOnly {
+		// Decide on the persisted journal, but keep marking the run rolled back in memory
+		st, err := newStateStore(cfg).Load()
+		if err != nil {
+			log.Fatalf("Failed to load state: %v", err)
+		}
+		snapshot := state.NewMemoryStore()
+		snapshot.Save(st)
+
+		p := plan.New()
+		synchronizer := sync.NewSynchronizer(plan.WrapAlertManager(am, p), plan.WrapTicketSystem(ts, p), syncConfig)
+		synchronizer.SetStateStore(snapshot)
+		if _, err := synchronizer.RollbackRun(*runID); err != nil {
+			fatal(withExitCode(runExitCode(err), err))
+		}
+		if err := p.WriteText(os.Stdout); err != nil {
+			log.Fatalf("Failed to write plan: %v", err)
+		}
+		return
+	}
+
+	synchronizer := sync.NewSynchronizer(am, ts, syncConfig)
+	synchronizer.SetStateStore(newStateStore(cfg))
+	actions, err := synchronizer.RollbackRun(*runID)
+	exportState()
+	rolledBack := 0
+	for _, action := range actions {
+		entry := action.Entry
+		switch {
+		case action.Skipped != "":
+			fmt.Printf("Skipped %s of silence %s (ticket %s): %s\n", entry.Action, entry.SilenceID, entry.TicketRef, action.Skipped)
+		case entry.Action == state.JournalDelete:
+			rolledBack++
+			fmt.Printf("Restored silence %s as %s (ticket %s), expiring at %s\n", entry.SilenceID, action.SilenceID, entry.TicketRef, action.EndsAt.Format(time.RFC3339))
+		default:
+			rolledBack++
+			fmt.Printf("Reverted %s of silence %s (ticket %s), expiring at %s\n", entry.Action, entry.SilenceID, entry.TicketRef, action.EndsAt.Format(time.RFC3339))
+		}
+	}
+	if err != nil {
+		fatal(withExitCode(runExitCode(err), err))
+	}
+	fmt.Printf("Rolled back %d of %d actions of run %s\n", rolledBack, len(actions), *runID)
+}
+
+// journalSummary describes the actions of a journaled run for listing
+func journalSummary(run state.JournalRun) string {
+	counts := make(map[string]int)
+	for _, entry := range run.Entries {
+		counts[entry.Action]++
+	}
+	summary := fmt.Sprintf("deleted=%d extended=%d updated=%d", counts[state.JournalDelete], counts[state.JournalExtend], counts[state.JournalUpdate])
+	if !run.RolledBack.IsZero() {
+		summary += fmt.Sprintf("  (rolled back at %s)", run.RolledBack.Format(time.RFC3339))
+	}
+	return summary
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/api"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+	"github.com/conallob/silence-manager/pkg/plan"
+	"github.com/conallob/silence-manager/pkg/slack"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/webhook"
+)
+
+// runServe serves the REST API for on-demand operations, with SLACK_SIGNING_SECRET the
+// Slack slash command and with WEBHOOK_TOKEN the Alertmanager webhook receiver, until the
+// process is terminated
+func runServe(args []string) {
+	log.Printf("Starting silence-manager API server version=%s commit=%s date=%s", version, commit, date)
+
+	cfg := loadConfig()
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	address := fs.String("address", cfg.API.Address, "Listen address of the REST API")
+	fs.Parse(args)
+
+	tokens, _ := cfg.GetAPITokens() // Validated by LoadConfig
+	if len(tokens) == 0 && cfg.Slack.SigningSecret == "" && cfg.Webhook.Token == "" {
+		fatal(withExitCode(exitConfig, fmt.Errorf("API_TOKENS, SLACK_SIGNING_SECRET or WEBHOOK_TOKEN is required by the serve command")))
+	}
+	verifyWorkflow(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer startTracing(cfg)()
+	notifyShutdown(ctx, stop, "API server", cfg.Daemon.ShutdownTimeout)
+	// A run requested before the server is told to stop may finish within the timeout
+	runCtx, cancelRuns := drainContext(ctx, cfg.Daemon.ShutdownTimeout)
+	defer cancelRuns()
+
+	leaseDuration := time.Duration(cfg.Lock.LeaseDurationSeconds) * time.Second
+	backend := &apiBackend{
+		cfg:           cfg,
+		lock:          newRunLock(cfg, k8s.ModeAPI, leaseDuration),
+		leaseDuration: leaseDuration,
+		runCtx:        runCtx,
+	}
+	server := api.NewServer(backend, tokens)
+	server.SetShutdownTimeout(cfg.Daemon.ShutdownTimeout + shutdownGrace)
+	if templates := loadTemplates(cfg); len(templates) > 0 {
+		server.SetTemplates(templates)
+		log.Printf("Loaded %d silence template(s) from %s", len(templates), cfg.Templates.File)
+	}
+	if cfg.Slack.SigningSecret != "" {
+		handler := slack.NewHandler(backend, cfg.Slack.SigningSecret, cfg.Slack.Operators)
+		teamDirectory, err := loadTeams(cfg)
+		if err != nil {
+			fatal(withExitCode(exitConfig, err))
+		}
+		handler.SetTeams(teamDirectory)
+		server.Handle("POST /slack/commands", handler)
+		log.Printf("Serving Slack slash command on /slack/commands (%d operator(s))", len(cfg.Slack.Operators))
+	}
+	if cfg.Webhook.Token != "" {
+		name, value, _ := cfg.Webhook.GetFileLabel() // Validated by LoadConfig
+		var filer webhook.Backend = backend
+		if !cfg.Actions.Create {
+			filer = disabledCreation{}
+			log.Printf("Warning: ENABLE_CREATE is off; the webhook receiver files no alerts")
+		}
+		server.Handle("POST /alertmanager/webhook", webhook.NewHandler(filer, cfg.Webhook.Token, name, value))
+		log.Printf("Serving Alertmanager webhook receiver on /alertmanager/webhook (filing alerts with %s=%s)", name, value)
+	}
+	log.Printf("Serving REST API on %s (%d token(s))", *address, len(tokens))
+	if err := server.Run(ctx, *address); err != nil {
+		fatal(err)
+	}
+	log.Println("API server stopped")
+}
+
+// disabledCreation files no alerts, for the webhook receiver while ENABLE_CREATE is off.
+// Silences created on request through the API or Slack are not affected.
+type disabledCreation struct{}
+
+func (disabledCreation) Create(context.Context, sync.SilenceDefinition) (*alertmanager.Silence, error) {
+	return nil, sync.ErrActionDisabled
+}
+
+// apiBackend performs the operations of the REST API, the Slack slash command, the
+// webhook receiver and the daemon's web UI with the clients selected by the configuration, created anew for every
+// request like the corresponding commands
+type apiBackend struct {
+	cfg  *config.Config
+	lock runLock
+	// leaseDuration is how long the run lock is valid, renewed while a run holds it
+	leaseDuration time.Duration
+	// runCtx, when set, stops runs between silences once the shutdown deadline passes
+	runCtx context.Context
+}
+
+// Sync performs a synchronization run, deferring to a CronJob or daemon holding the run lock
+func (b *apiBackend) Sync(ctx context.Context) (*api.SyncResponse, error) {
+	if b.lock != nil {
+		acquired, holder, err := b.lock.Acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire run lock: %w", err)
+		}
+		if !acquired {
+			return nil, fmt.Errorf("%w: run lock is held by %s (%s)", api.ErrRunInProgress, holderName(holder.Identity), holder.Mode)
+		}
+		defer func() {
+			if err := b.lock.Release(context.Background()); err != nil {
+				log.Printf("Warning: failed to release run lock: %v", err)
+			}
+		}()
+	}
+
+	runCtx := b.runCtx
+	if runCtx == nil {
+		runCtx = context.Background()
+	}
+	if b.lock != nil {
+		var stopRenewing func()
+		runCtx, stopRenewing = holdRunLock(runCtx, b.lock, b.leaseDuration)
+		defer stopRenewing()
+	}
+	started := time.Now()
+	result, err := syncOnce(runCtx, b.cfg)
+	reportRun(b.cfg, started, result, err)
+	writeSummary(b.cfg, started, result, err)
+	exportState()
+	if err != nil {
+		return nil, err
+	}
+	logResult(result)
+
+	response := &api.SyncResponse{Started: started, Finished: time.Now(), Counts: result.Counts()}
+	for _, err := range result.Errors {
+		response.Errors = append(response.Errors, err.Error())
+	}
+	return response, nil
+}
+
+// List returns the active silences and the next action for each, like the list command
+func (b *apiBackend) List(ctx context.Context) ([]sync.PlannedAction, error) {
+	synchronizer, err := newOperationSynchronizer(b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return synchronizer.Plan()
+}
+
+// Plan returns the changes a run would make, like sync --plan
+func (b *apiBackend) Plan(ctx context.Context) (*plan.Plan, error) {
+	return planSync(b.cfg)
+}
+
+// Link attaches a silence to a ticket, like the link command
+func (b *apiBackend) Link(ctx context.Context, silenceID, ticketKey string) error {
+	synchronizer, err := newOperationSynchronizer(b.cfg)
+	if err != nil {
+		return err
+	}
+	return synchronizer.LinkSilence(silenceID, ticketKey)
+}
+
+// Unlink detaches a silence from its ticket, like the unlink command
+func (b *apiBackend) Unlink(ctx context.Context, silenceID string) error {
+	synchronizer, err := newOperationSynchronizer(b.cfg)
+	if err != nil {
+		return err
+	}
+	return synchronizer.UnlinkSilence(silenceID)
+}
+
+// Create creates a silence linked to an existing or new ticket, like the create command
+func (b *apiBackend) Create(ctx context.Context, def sync.SilenceDefinition) (*alertmanager.Silence, error) {
+	synchronizer, err := newOperationSynchronizer(b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return synchronizer.CreateSilence(def)
+}
+
+// Purge deletes the orphan silences, or all managed silences, for the API's purge route.
+// The route authorizes the bulk deletion by the role of the caller's bearer token.
+func (b *apiBackend) Purge(ctx context.Context, all, dryRun bool) (*api.PurgeResponse, error) {
+	synchronizer, err := newOperationSynchronizer(b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := synchronizer.PurgeTargets(all)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+
+	result := &api.PurgeResponse{}
+	for _, silence := range targets {
+		result.Silences = append(result.Silences, api.PurgedSilence{
+			ID: silence.ID, TicketRef: silence.TicketRef, CreatedBy: silence.CreatedBy, EndsAt: silence.EndsAt,
+		})
+	}
+	if !dryRun {
+		result.Deleted = synchronizer.Purge(targets)
+		log.Printf("Purged %d of %d silence(s)", result.Deleted, len(targets))
+	}
+	return result, nil
+}
+
+// Extend moves the end of a silence to duration from now, for the web UI
+func (b *apiBackend) Extend(ctx context.Context, silenceID string, duration time.Duration) (*alertmanager.Silence, error) {
+	synchronizer, err := newOperationSynchronizer(b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return synchronizer.ExtendSilence(silenceID, duration)
+}
+
+// Delete expires a silence, for the web UI
+func (b *apiBackend) Delete(ctx context.Context, silenceID string) error {
+	synchronizer, err := newOperationSynchronizer(b.cfg)
+	if err != nil {
+		return err
+	}
+	return synchronizer.DeleteSilence(silenceID)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// shutdownGrace is how long work cancelled at the shutdown deadline may take to record
+// its outcome, such as the journal and summary of a run stopped early
+const shutdownGrace = 5 * time.Second
+
+// drainContext returns a context for the work started before ctx is done, such as the
+// run in progress when SIGTERM arrives. It outlives ctx by up to timeout, so that the
+// run can finish, and is then cancelled, so that the run stops between silences.
+func drainContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	drain, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(timeout, cancel)
+	})
+	return drain, func() {
+		stop()
+		cancel()
+	}
+}
+
+// notifyShutdown logs the shutdown once ctx is done and lets a second signal terminate
+// the process at once, by undoing the signal handling set up with stop
+func notifyShutdown(ctx context.Context, stop context.CancelFunc, what string, timeout time.Duration) {
+	context.AfterFunc(ctx, func() {
+		log.Printf("Shutting down %s, letting work in progress finish within %v (signal again to exit now)", what, timeout)
+		stop()
+	})
+}
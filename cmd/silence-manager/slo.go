@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/slo"
+)
+
+// runSLO prints the silence hygiene SLO report computed from the state store
+func runSLO(args []string) {
+	fs := flag.NewFlagSet("slo", flag.ExitOnError)
+	window := fs.Duration("window", 7*24*time.Hour, "Time window to evaluate")
+	output := fs.String("output", "text", "Output format: text or json")
+	fs.Parse(args)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.State.Backend == "none" {
+		log.Fatalf("SLO reporting requires a persistent state store (set STATE_BACKEND)")
+	}
+
+	st, err := newStateStore(cfg).Load()
+	if err != nil {
+		log.Fatalf("Failed to load state: %v", err)
+	}
+
+	objectives := slo.Objectives{
+		MinOpenTicketRatio: cfg.SLO.MinOpenTicketRatio,
+		MaxOrphanRatio:     cfg.SLO.MaxOrphanRatio,
+		MaxMedianAge:       cfg.SLO.MaxMedianAge,
+	}
+
+	now := time.Now()
+	report := slo.Evaluate(st.Hygiene, objectives, now.Add(-*window), now)
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	case "text":
+		printSLOReport(report)
+	default:
+		log.Fatalf("Unknown output format: %s (must be 'text' or 'json')", *output)
+	}
+
+	if !report.Met() {
+		os.Exit(1)
+	}
+}
+
+func printSLOReport(r *slo.Report) {
+	fmt.Printf("Silence hygiene report: %s to %s (%d samples)\n\n",
+		r.Since.Format(time.RFC3339), r.Until.Format(time.RFC3339), r.Samples)
+
+	if r.Samples == 0 {
+		fmt.Println("No hygiene samples recorded in this window.")
+		return
+	}
+
+	fmt.Printf("%-24s %10s %10s %10s %12s\n", "Indicator", "Latest", "Average", "Objective", "Compliance")
+	fmt.Printf("%-24s %9.1f%% %9.1f%% %9s %11.1f%%\n", "Open ticket ratio",
+		r.Latest.OpenTicketRatio()*100, r.AvgOpenTicketRatio*100,
+		fmt.Sprintf(">=%.0f%%", r.Objectives.MinOpenTicketRatio*100), r.OpenTicketCompliance*100)
+	fmt.Printf("%-24s %9.1f%% %9.1f%% %9s %11.1f%%\n", "Orphan ratio",
+		r.Latest.OrphanRatio()*100, r.AvgOrphanRatio*100,
+		fmt.Sprintf("<=%.0f%%", r.Objectives.MaxOrphanRatio*100), r.OrphanCompliance*100)
+	fmt.Printf("%-24s %10s %10s %10s %11.1f%%\n", "Median silence age",
+		r.Latest.MedianAge.Round(time.Hour), r.MedianAge.Round(time.Hour),
+		"<="+r.Objectives.MaxMedianAge.String(), r.MedianAgeCompliance*100)
+
+	fmt.Println()
+	if r.Met() {
+		fmt.Println("All objectives are currently met.")
+	} else {
+		fmt.Println("One or more objectives are currently NOT met.")
+	}
+}
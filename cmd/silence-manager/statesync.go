@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/tls"
+	"log"
+
+	"github.com/conallob/silence-manager/pkg/backup"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/k8s"
+	"github.com/conallob/silence-manager/pkg/redis"
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// syncedState is the state store of the process when STATE_SYNC_BACKEND is set, shared
+// so that the remote copy is imported once and every change is exported
+var syncedState *state.SyncedStore
+
+// newStateRemote creates the copy of the state selected by STATE_SYNC_BACKEND
+func newStateRemote(cfg *config.Config) (state.Remote, error) {
+	switch cfg.State.SyncBackend {
+	case "configmap":
+		return k8s.NewStateConfigMap(cfg.State.SyncName, cfg.State.SyncNamespace)
+	case "s3":
+		return backup.NewObject(backup.NewS3Store(backup.S3Config{
+			Bucket:          cfg.State.SyncBucket,
+			Region:          cfg.Backup.S3Region,
+			Endpoint:        cfg.Backup.Endpoint,
+			AccessKeyID:     cfg.Backup.S3AccessKeyID,
+			SecretAccessKey: cfg.Backup.S3SecretAccessKey,
+			SessionToken:    cfg.Backup.S3SessionToken,
+		}), cfg.State.SyncName), nil
+	case "gcs":
+		return backup.NewObject(backup.NewGCSStore(backup.GCSConfig{
+			Bucket:   cfg.State.SyncBucket,
+			Endpoint: cfg.Backup.Endpoint,
+			Token:    cfg.Backup.GCSToken,
+		}), cfg.State.SyncName), nil
+	case "redis":
+		return redis.NewStateKey(newRedisClient(cfg), cfg.State.SyncName), nil
+	default:
+		return nil, nil
+	}
+}
+
+// newRedisClient creates a client of the Redis server of the configuration
+func newRedisClient(cfg *config.Config) *redis.Client {
+	redisConfig := redis.Config{
+		Address:  cfg.Redis.Address,
+		Username: cfg.Redis.Username,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	}
+	if cfg.Redis.TLS {
+		redisConfig.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return redis.NewClient(redisConfig)
+}
+
+// exportState writes the state back to its remote copy after a run that changed it.
+// A failed export is logged, and retried after the next run of a daemon or server.
+func exportState() {
+	if syncedState == nil {
+		return
+	}
+	exported, err := syncedState.Export()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	if exported {
+		log.Printf("Exported state")
+	}
+}
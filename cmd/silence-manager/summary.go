@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// writeSummary writes the JSON summary of the run started at started to SYNC_SUMMARY_OUTPUT:
+// a line on stdout for "-", or otherwise a file replaced on every run
+func writeSummary(cfg *config.Config, started time.Time, result *sync.SyncResult, runErr error) {
+	output := cfg.Sync.SummaryOutput
+	if output == "" {
+		return
+	}
+
+	data, err := json.Marshal(sync.NewSummary(result, started, time.Now(), runErr))
+	if err != nil {
+		log.Printf("Warning: failed to marshal run summary: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	if output == "-" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			log.Printf("Warning: failed to write run summary: %v", err)
+		}
+		return
+	}
+	if err := replaceFile(output, data); err != nil {
+		log.Printf("Warning: failed to write run summary: %v", err)
+	}
+}
+
+// replaceFile writes data to path, replacing the previous file atomically so that readers
+// never see a partial summary
+func replaceFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/tracing"
+)
+
+// tracingShutdownTimeout bounds how long exporting the remaining spans may delay exit
+const tracingShutdownTimeout = 10 * time.Second
+
+// startTracing exports traces of the sync runs when TRACING_ENABLED is true. The returned
+// function exports the remaining spans and must be called before the process exits.
+// Tracing that cannot be started is logged and left disabled rather than failing runs.
+func startTracing(cfg *config.Config) func() {
+	if !cfg.Tracing.Enabled {
+		return func() {}
+	}
+
+	shutdown, err := tracing.Start(tracing.Config{
+		URL:       cfg.Tracing.URL,
+		Insecure:  cfg.Tracing.Insecure,
+		TLSConfig: newTLSConfig(cfg),
+		Version:   version,
+	})
+	if err != nil {
+		log.Printf("Warning: failed to start tracing, continuing without traces: %v", err)
+		return func() {}
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			log.Printf("Warning: failed to export traces: %v", err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/backup"
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/gitops"
+	"github.com/conallob/silence-manager/pkg/metrics"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// configCheck is a single read-only probe of validate-config
+type configCheck struct {
+	name  string
+	probe func() (string, error)
+}
+
+// runValidateConfig loads and validates the configuration, then probes Alertmanager,
+// Jira, the metrics backend, the state store and the backup store without changing
+// anything
+func runValidateConfig(args []string) {
+	fs := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	fs.Parse(args)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("FAIL  Configuration: %v\n", err)
+		os.Exit(exitConfig)
+	}
+	fmt.Println("PASS  Configuration")
+
+	failed := 0
+	for _, check := range configChecks(cfg) {
+		detail, err := check.probe()
+		switch {
+		case err != nil:
+			fmt.Printf("FAIL  %s: %v\n", check.name, err)
+			failed++
+		case detail != "":
+			fmt.Printf("PASS  %s: %s\n", check.name, detail)
+		default:
+			fmt.Printf("PASS  %s\n", check.name)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n%d check(s) failed\n", failed)
+		os.Exit(exitFailure)
+	}
+	fmt.Println("\nAll checks passed")
+}
+
+// configChecks returns the probes for the configured backends
+func configChecks(cfg *config.Config) []configCheck {
+	jira := newTicketSystem(cfg)
+
+	checks := []configCheck{
+		{"Alertmanager", func() (string, error) {
+			am, err := newAlertManager(cfg)
+			if err != nil {
+				return "", err
+			}
+			silences, err := am.ListSilences()
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d active silence(s)", len(silences)), nil
+		}},
+		{"Jira authentication", jira.CheckAuth},
+		{"Jira project", jira.CheckProject},
+		{"Jira transitions", func() (string, error) {
+			return jira.CheckTransitions(neededTransitions(cfg))
+		}},
+		{"State store", func() (string, error) {
+			if _, err := newStateStore(cfg).Load(); err != nil {
+				return "", err
+			}
+			if cfg.State.SyncBackend != "" {
+				return fmt.Sprintf("%s, synced to %s %s", cfg.State.Backend, cfg.State.SyncBackend, cfg.State.SyncName), nil
+			}
+			return cfg.State.Backend, nil
+		}},
+	}
+
+	// Scraped metrics have no backend to check
+	if cfg.Metrics.Enabled && cfg.Metrics.Backend != "prometheus" {
+		checks = append(checks, configCheck{"Metrics backend", func() (string, error) {
+			url, err := newMetricsURL(cfg)
+			if err != nil {
+				return "", err
+			}
+			if cfg.Metrics.Backend == "otel" {
+				return url, metrics.CheckOTelCollector(url)
+			}
+			return url, metrics.CheckPushgateway(url, newTLSConfig(cfg))
+		}})
+	}
+	if cfg.GitOps.Path != "" {
+		checks = append(checks, configCheck{"GitOps definitions", func() (string, error) {
+			defs, err := gitops.NewDirectory(cfg.GitOps.Path).Definitions()
+			if err != nil {
+				return "", err
+			}
+			for _, def := range defs {
+				if _, err := sync.ParseMatchers(def.Matchers); err != nil {
+					return "", fmt.Errorf("%s: %w", def, err)
+				}
+				if def.Duration != "" {
+					if _, err := sync.ParseDuration(def.Duration); err != nil {
+						return "", fmt.Errorf("%s: invalid duration %q: %w", def, def.Duration, err)
+					}
+				}
+			}
+			return fmt.Sprintf("%d silence(s) declared in %s", len(defs), cfg.GitOps.Path), nil
+		}})
+	}
+	if store := newBackupStore(cfg); store != nil {
+		checks = append(checks, configCheck{"Backup store", func() (string, error) {
+			snapshot, err := backup.Load(store, backup.LatestObject)
+			if errors.Is(err, backup.ErrNotFound) {
+				return cfg.Backup.Backend + ", no backup yet", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s, %d silence(s) backed up at %s", cfg.Backup.Backend, len(snapshot.Silences), snapshot.Taken.Format(time.RFC3339)), nil
+		}})
+	}
+	return checks
+}
+
+// neededTransitions reports whether the configured runs reopen and close tickets
+func neededTransitions(cfg *config.Config) (reopen, closing bool) {
+	reopen = cfg.Sync.CheckAlerts && cfg.Sync.ReopenStrategy == "reopen"
+	closing = cfg.Sync.AutoCloseAfterDays > 0 || cfg.Maintenance.CalendarURL != ""
+	return reopen, closing
+}
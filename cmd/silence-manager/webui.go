@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/config"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/webui"
+)
+
+// activityJournal keeps the daemon's recent runs and the actions taken from the web UI,
+// or is nil when the web UI is disabled
+var activityJournal *webui.Journal
+
+// newWebUI creates the web UI handler served by the daemon on the health address
+func newWebUI(cfg *config.Config) *webui.Handler {
+	tokens, _ := cfg.GetAPITokens() // Validated by LoadConfig
+	activityJournal = webui.NewJournal(webui.DefaultJournalSize)
+	log.Printf("Serving web UI on %s/ui/ (%d token(s))", cfg.Health.Address, len(tokens))
+	return webui.NewHandler(&apiBackend{cfg: cfg}, activityJournal, webui.Config{
+		Tokens:    tokens,
+		TicketURL: ticketBrowseURL(cfg.Jira.URL),
+	})
+}
+
+// ticketBrowseURL returns the prefix of links to Jira issues, without credentials
+func ticketBrowseURL(jiraURL string) string {
+	u, err := url.Parse(jiraURL)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/browse/"
+	return u.String()
+}
+
+// journalRun records a daemon run in the web UI's journal
+func journalRun(started time.Time, result *sync.SyncResult, err error) {
+	if activityJournal == nil {
+		return
+	}
+
+	entry := webui.Entry{Time: started, Actor: "sync run", Action: "sync"}
+	switch {
+	case err != nil:
+		entry.Detail = fmt.Sprintf("failed: %v", err)
+	default:
+		var changes []string
+		for name, count := range result.Counts() {
+			if count > 0 {
+				changes = append(changes, fmt.Sprintf("%s %d", name, count))
+			}
+		}
+		sort.Strings(changes)
+		if len(changes) == 0 {
+			changes = append(changes, "no changes")
+		}
+		if len(result.Errors) > 0 {
+			changes = append(changes, fmt.Sprintf("%d error(s)", len(result.Errors)))
+		}
+		entry.Detail = strings.Join(changes, ", ")
+	}
+	activityJournal.Record(entry)
+
+	if result == nil {
+		return
+	}
+	for _, d := range result.Drifts {
+		detail := fmt.Sprintf("%s (ticket %s)", d.Changes, d.TicketRef)
+		if d.ReplacedID != "" {
+			detail += fmt.Sprintf(", replacing silence %s", d.ReplacedID)
+		}
+		activityJournal.Record(webui.Entry{
+			Time:   d.Detected,
+			Actor:  d.CreatedBy + " (outside silence-manager)",
+			Action: "edit",
+			Target: d.SilenceID,
+			Detail: detail,
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// ErrNoServerTime is returned by ServerTime for alertmanagers that cannot report the time
+// of their server
+var ErrNoServerTime = errors.New("alertmanager does not report its server time")
+
+// Clock is implemented by alertmanagers that report the time of the Alertmanager server,
+// so that silence expiry is judged by the clock that actually expires silences
+type Clock interface {
+	ServerTime() (time.Time, error)
+}
+
+// ServerTime returns the current time of am's server, or ErrNoServerTime if am does not
+// implement Clock
+func ServerTime(am AlertManager) (time.Time, error) {
+	clock, ok := am.(Clock)
+	if !ok {
+		return time.Time{}, ErrNoServerTime
+	}
+	return clock.ServerTime()
+}
+
+// ServerTime returns the current time of the Alertmanager server
+func (p *PrometheusAlertManager) ServerTime() (time.Time, error) {
+	return p.ServerTimeContext(context.Background())
+}
+
+// ServerTimeContext returns the current time of the Alertmanager server, read from the
+// Date header of its status endpoint. The header has a resolution of one second, so the
+// server time is taken to be half a second past it at the middle of the request.
+func (p *PrometheusAlertManager) ServerTimeContext(ctx context.Context) (time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/v2/status", nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := p.addAuth(req); err != nil {
+		return time.Time{}, err
+	}
+
+	sent := time.Now()
+	resp, err := p.do(req, "status")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get status: %w", err)
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return time.Time{}, apierror.FromStatus(resp.StatusCode, string(body))
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Date header %q: %w", resp.Header.Get("Date"), err)
+	}
+
+	middle := sent.Add(received.Sub(sent) / 2)
+	return date.Add(500 * time.Millisecond).Add(time.Since(middle)), nil
+}
@@ -0,0 +1,37 @@
+package alertmanager
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServerTime(t *testing.T) {
+	skewed := time.Now().Add(-time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Date", skewed.UTC().Format(http.TimeFormat))
+		w.Write([]byte(`{"cluster":{"status":"ready"}}`))
+	}))
+	defer server.Close()
+
+	serverTime, err := ServerTime(NewPrometheusAlertManager(server.URL))
+	if err != nil {
+		t.Fatalf("ServerTime() failed: %v", err)
+	}
+	if diff := serverTime.Sub(skewed); diff < -time.Second || diff > 2*time.Second {
+		t.Errorf("Expected the time of the server, %v, got %v", skewed, serverTime)
+	}
+}
+
+func TestServerTime_Unsupported(t *testing.T) {
+	var am AlertManager = &boundAlertManager{}
+	if _, err := ServerTime(am); !errors.Is(err, ErrNoServerTime) {
+		t.Errorf("Expected ErrNoServerTime, got %v", err)
+	}
+}
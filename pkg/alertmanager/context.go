@@ -0,0 +1,54 @@
+package alertmanager
+
+import (
+	"context"
+	"time"
+)
+
+// WithContext returns an AlertManager whose requests are bound to ctx. Alertmanagers that
+// do not implement ContextAlertManager are returned unchanged.
+func WithContext(ctx context.Context, am AlertManager) AlertManager {
+	cam, ok := am.(ContextAlertManager)
+	if !ok {
+		return am
+	}
+	return &boundAlertManager{ctx: ctx, next: cam}
+}
+
+// boundAlertManager passes a fixed context to every request of the wrapped alertmanager
+type boundAlertManager struct {
+	ctx  context.Context
+	next ContextAlertManager
+}
+
+func (b *boundAlertManager) GetSilence(id string) (*Silence, error) {
+	return b.next.GetSilenceContext(b.ctx, id)
+}
+
+func (b *boundAlertManager) ListSilences() ([]*Silence, error) {
+	return b.next.ListSilencesContext(b.ctx)
+}
+
+func (b *boundAlertManager) CreateSilence(silence *Silence) (string, error) {
+	return b.next.CreateSilenceContext(b.ctx, silence)
+}
+
+func (b *boundAlertManager) UpdateSilence(silence *Silence) error {
+	return b.next.UpdateSilenceContext(b.ctx, silence)
+}
+
+func (b *boundAlertManager) DeleteSilence(id string) error {
+	return b.next.DeleteSilenceContext(b.ctx, id)
+}
+
+func (b *boundAlertManager) ExtendSilence(id string, newEndTime time.Time) error {
+	return b.next.ExtendSilenceContext(b.ctx, id, newEndTime)
+}
+
+func (b *boundAlertManager) GetAlerts(matchers []Matcher) ([]*Alert, error) {
+	return b.next.GetAlertsContext(b.ctx, matchers)
+}
+
+func (b *boundAlertManager) GetSilencedAlerts(silenceID string) ([]*Alert, error) {
+	return b.next.GetSilencedAlertsContext(b.ctx, silenceID)
+}
@@ -0,0 +1,94 @@
+package alertmanager
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// tracer traces Alertmanager requests; spans are dropped unless tracing is enabled
+var tracer = otel.Tracer("github.com/conallob/silence-manager/pkg/alertmanager")
+
+// RequestObserver is notified of the duration of every Alertmanager request, e.g. to
+// publish latency metrics
+type RequestObserver interface {
+	// RecordRequestDuration records how long a request took until its response headers
+	// arrived or it failed
+	// system is the backend, e.g. alertmanager
+	// operation is the kind of request, e.g. list or create
+	RecordRequestDuration(system, operation string, duration time.Duration)
+}
+
+// SetRequestObserver sets the observer notified of the duration of every request
+func (p *PrometheusAlertManager) SetRequestObserver(observer RequestObserver) {
+	p.requestObserver = observer
+}
+
+// defaultMaxRetries is how often a transiently failing request is retried
+const defaultMaxRetries = 2
+
+// do sends a request to Alertmanager, reporting its duration under operation and
+// tracing it as a child of the span in the request's context. Reads and deletions are
+// retried with backoff when Alertmanager cannot be reached or a proxy answers with 502,
+// 503 or 504; creating or updating a silence may have taken effect, so it is not retried.
+func (p *PrometheusAlertManager) do(req *http.Request, operation string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := p.send(req, operation)
+		if attempt >= p.maxRetries || !isTransient(req, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		p.sleep(time.Second << attempt)
+	}
+}
+
+// isTransient reports whether a read or deletion failed in a way that a retry may fix
+func isTransient(req *http.Request, resp *http.Response, err error) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodDelete || req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return apierror.CategoryOf(err) == apierror.Transient
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// send sends a single attempt of a request
+func (p *PrometheusAlertManager) send(req *http.Request, operation string) (*http.Response, error) {
+	ctx, span := tracer.Start(req.Context(), "alertmanager "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	started := time.Now()
+	resp, err := p.httpClient.Do(req.WithContext(ctx))
+	if p.requestObserver != nil {
+		p.requestObserver.RecordRequestDuration("alertmanager", operation, time.Since(started))
+	}
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	return resp, err
+}
@@ -2,11 +2,18 @@ package alertmanager
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
 )
 
 // PrometheusAlertManager implements the AlertManager interface for Prometheus Alertmanager
@@ -15,19 +22,48 @@ type PrometheusAlertManager struct {
 	authType         string
 	username         string
 	password         string
-	bearerToken      string
+	tokenSource      TokenSource
 	httpClient       *http.Client
 	annotationPrefix string
+	ticketRefPattern *regexp.Regexp
+	silenceFilter    []Matcher
+	requestObserver  RequestObserver
+	maxRetries       int // Retries of a transiently failing read or deletion
+	sleep            func(time.Duration)
 }
 
 // AlertManagerConfig holds configuration for creating a new Alertmanager client
 type AlertManagerConfig struct {
-	BaseURL          string
-	AuthType         string // "none", "basic", "bearer"
-	Username         string
-	Password         string
-	BearerToken      string
+	BaseURL     string
+	AuthType    string // "none", "basic", "bearer", "mtls"
+	Username    string
+	Password    string
+	BearerToken string
+	// BearerTokenFile, when set for bearer auth, is read instead of BearerToken and read
+	// again whenever it changes, so rotated service account tokens are picked up
+	BearerTokenFile string
+	// OIDC, when set for bearer auth, fetches tokens with the client credentials grant
+	// and refreshes them before they expire. It takes precedence over the other sources.
+	OIDC             *OIDCConfig
+	ClientCertFile   string // PEM client certificate for mtls auth
+	ClientKeyFile    string // PEM private key for mtls auth
 	AnnotationPrefix string
+	// TicketRefPattern, when set, finds a ticket reference anywhere in the comment of
+	// silences without the annotation header. The first capture group is used if the
+	// pattern has one, otherwise the whole match.
+	TicketRefPattern *regexp.Regexp
+	// TLSConfig, when set, is used for HTTPS connections instead of the defaults
+	TLSConfig *tls.Config
+	// SilenceFilter, when set, restricts ListSilences to silences with matching matchers.
+	// The filter is applied by Alertmanager, so other silences are not transferred.
+	SilenceFilter []Matcher
+	// Timeout bounds each request, including reading the response; 0 uses 30s
+	Timeout time.Duration
+	// Connection pool and keep-alive settings; zero values use the Go defaults
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	DisableKeepAlives   bool
 }
 
 // NewPrometheusAlertManager creates a new Prometheus Alertmanager client
@@ -45,29 +81,109 @@ func NewPrometheusAlertManagerWithConfig(config AlertManagerConfig) *PrometheusA
 	if prefix == "" {
 		prefix = "silence-manager"
 	}
+	tlsConfig := config.TLSConfig
+	if config.AuthType == "mtls" {
+		tlsConfig = withClientCertificate(tlsConfig, config.ClientCertFile, config.ClientKeyFile)
+	}
+	httpClient := newHTTPClient(config, tlsConfig)
 	return &PrometheusAlertManager{
 		baseURL:          config.BaseURL,
 		authType:         config.AuthType,
 		username:         config.Username,
 		password:         config.Password,
-		bearerToken:      config.BearerToken,
+		tokenSource:      newTokenSource(config, httpClient),
 		annotationPrefix: prefix,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		ticketRefPattern: config.TicketRefPattern,
+		silenceFilter:    config.SilenceFilter,
+		httpClient:       httpClient,
+		maxRetries:       defaultMaxRetries,
+		sleep:            time.Sleep,
 	}
 }
 
+// newTokenSource picks the bearer token source from config. Token requests share
+// httpClient, so they use the same TLS settings and timeout as Alertmanager requests.
+func newTokenSource(config AlertManagerConfig, httpClient *http.Client) TokenSource {
+	switch {
+	case config.OIDC != nil:
+		return NewOIDCTokenSource(*config.OIDC, httpClient)
+	case config.BearerTokenFile != "":
+		return NewFileTokenSource(config.BearerTokenFile)
+	default:
+		return staticToken(config.BearerToken)
+	}
+}
+
+// ErrSilenceNotFound is returned when Alertmanager does not know a silence, e.g. because
+// it expired longer ago than its retention
+var ErrSilenceNotFound = apierror.New(apierror.NotFound, "silence not found")
+
+// defaultTimeout bounds each request when no timeout is configured
+const defaultTimeout = 30 * time.Second
+
+// newHTTPClient creates the HTTP client with the configured timeout and connection pool,
+// using tlsConfig for HTTPS connections if set
+func newHTTPClient(config AlertManagerConfig, tlsConfig *tls.Config) *http.Client {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	transport.DisableKeepAlives = config.DisableKeepAlives
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// withClientCertificate returns a copy of tlsConfig presenting the client certificate in
+// certFile and keyFile. The files are read on every handshake so that rotated
+// certificates, such as those renewed by cert-manager, are picked up without a restart.
+func withClientCertificate(tlsConfig *tls.Config, certFile, keyFile string) *tls.Config {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		return &cert, nil
+	}
+	return tlsConfig
+}
+
 // addAuth adds authentication headers to the HTTP request
-func (p *PrometheusAlertManager) addAuth(req *http.Request) {
+func (p *PrometheusAlertManager) addAuth(req *http.Request) error {
 	switch p.authType {
 	case "basic":
 		req.SetBasicAuth(p.username, p.password)
 	case "bearer":
-		req.Header.Set("Authorization", "Bearer "+p.bearerToken)
-	case "none":
-		// No authentication
+		token, err := p.tokenSource.Token(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get bearer token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case "none", "mtls":
+		// No authentication headers; mtls authenticates during the TLS handshake
 	}
+	return nil
 }
 
 // API response structures for Prometheus Alertmanager
@@ -97,33 +213,43 @@ type promAlert struct {
 	Annotations map[string]string `json:"annotations"`
 	StartsAt    time.Time         `json:"startsAt"`
 	EndsAt      time.Time         `json:"endsAt"`
-	Status      struct {
-		State string `json:"state"`
-	} `json:"status"`
+	Status      promAlertStatus   `json:"status"`
+}
+
+type promAlertStatus struct {
+	State      string   `json:"state"`
+	SilencedBy []string `json:"silencedBy"`
 }
 
 // GetSilence retrieves a silence by ID
 func (p *PrometheusAlertManager) GetSilence(id string) (*Silence, error) {
+	return p.GetSilenceContext(context.Background(), id)
+}
+
+// GetSilenceContext retrieves a silence by ID, giving up when ctx is done
+func (p *PrometheusAlertManager) GetSilenceContext(ctx context.Context, id string) (*Silence, error) {
 	url := fmt.Sprintf("%s/api/v2/silence/%s", p.baseURL, id)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	p.addAuth(req)
+	if err := p.addAuth(req); err != nil {
+		return nil, err
+	}
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req, "get")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get silence: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("silence not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, apierror.FromStatus(resp.StatusCode, string(body))
 	}
 
 	var ps promSilence
@@ -134,16 +260,27 @@ func (p *PrometheusAlertManager) GetSilence(id string) (*Silence, error) {
 	return p.convertFromPromSilence(&ps), nil
 }
 
-// ListSilences returns all active silences
+// ListSilences returns all active silences matching the silence filter
 func (p *PrometheusAlertManager) ListSilences() ([]*Silence, error) {
+	return p.ListSilencesContext(context.Background())
+}
+
+// ListSilencesContext returns all active silences matching the silence filter, giving up
+// when ctx is done
+func (p *PrometheusAlertManager) ListSilencesContext(ctx context.Context) ([]*Silence, error) {
 	url := fmt.Sprintf("%s/api/v2/silences", p.baseURL)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if len(p.silenceFilter) > 0 {
+		url += "?" + filterQuery(p.silenceFilter).Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	p.addAuth(req)
+	if err := p.addAuth(req); err != nil {
+		return nil, err
+	}
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req, "list")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list silences: %w", err)
 	}
@@ -151,7 +288,7 @@ func (p *PrometheusAlertManager) ListSilences() ([]*Silence, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, apierror.FromStatus(resp.StatusCode, string(body))
 	}
 
 	var psList []promSilence
@@ -173,6 +310,14 @@ func (p *PrometheusAlertManager) ListSilences() ([]*Silence, error) {
 
 // CreateSilence creates a new silence and returns its ID
 func (p *PrometheusAlertManager) CreateSilence(silence *Silence) (string, error) {
+	return p.CreateSilenceContext(context.Background(), silence)
+}
+
+// CreateSilenceContext creates a new silence and returns its ID, giving up when ctx is done
+func (p *PrometheusAlertManager) CreateSilenceContext(ctx context.Context, silence *Silence) (string, error) {
+	if err := silence.Validate(); err != nil {
+		return "", err
+	}
 	ps := p.convertToPromSilence(silence)
 
 	body, err := json.Marshal(ps)
@@ -181,14 +326,16 @@ func (p *PrometheusAlertManager) CreateSilence(silence *Silence) (string, error)
 	}
 
 	url := fmt.Sprintf("%s/api/v2/silences", p.baseURL)
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	p.addAuth(req)
+	if err := p.addAuth(req); err != nil {
+		return "", err
+	}
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req, "create")
 	if err != nil {
 		return "", fmt.Errorf("failed to create silence: %w", err)
 	}
@@ -196,7 +343,7 @@ func (p *PrometheusAlertManager) CreateSilence(silence *Silence) (string, error)
 
 	if resp.StatusCode != http.StatusOK {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(responseBody))
+		return "", apierror.FromStatus(resp.StatusCode, string(responseBody))
 	}
 
 	var result struct {
@@ -211,8 +358,17 @@ func (p *PrometheusAlertManager) CreateSilence(silence *Silence) (string, error)
 
 // UpdateSilence updates an existing silence
 func (p *PrometheusAlertManager) UpdateSilence(silence *Silence) error {
-	// In Alertmanager, updating a silence requires deleting and recreating it
-	// However, we can reuse the same ID by including it in the POST
+	return p.UpdateSilenceContext(context.Background(), silence)
+}
+
+// UpdateSilenceContext updates an existing silence, giving up when ctx is done. If
+// Alertmanager replaces the silence, silence.ID is set to the ID of the replacement.
+func (p *PrometheusAlertManager) UpdateSilenceContext(ctx context.Context, silence *Silence) error {
+	if err := silence.Validate(); err != nil {
+		return err
+	}
+	// Posting a silence with its ID updates it in place, unless its matchers changed: then
+	// Alertmanager expires it and answers with the ID of a new silence
 	ps := p.convertToPromSilence(silence)
 	ps.ID = silence.ID
 
@@ -222,14 +378,16 @@ func (p *PrometheusAlertManager) UpdateSilence(silence *Silence) error {
 	}
 
 	url := fmt.Sprintf("%s/api/v2/silences", p.baseURL)
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	p.addAuth(req)
+	if err := p.addAuth(req); err != nil {
+		return err
+	}
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req, "update")
 	if err != nil {
 		return fmt.Errorf("failed to update silence: %w", err)
 	}
@@ -237,7 +395,17 @@ func (p *PrometheusAlertManager) UpdateSilence(silence *Silence) error {
 
 	if resp.StatusCode != http.StatusOK {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(responseBody))
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	var result struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.SilenceID != "" {
+		silence.ID = result.SilenceID
 	}
 
 	return nil
@@ -245,22 +413,33 @@ func (p *PrometheusAlertManager) UpdateSilence(silence *Silence) error {
 
 // DeleteSilence deletes a silence by ID
 func (p *PrometheusAlertManager) DeleteSilence(id string) error {
+	return p.DeleteSilenceContext(context.Background(), id)
+}
+
+// DeleteSilenceContext deletes a silence by ID, giving up when ctx is done
+func (p *PrometheusAlertManager) DeleteSilenceContext(ctx context.Context, id string) error {
 	url := fmt.Sprintf("%s/api/v2/silence/%s", p.baseURL, id)
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create delete request: %w", err)
 	}
-	p.addAuth(req)
+	if err := p.addAuth(req); err != nil {
+		return err
+	}
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req, "delete")
 	if err != nil {
 		return fmt.Errorf("failed to delete silence: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return apierror.FromStatus(resp.StatusCode, string(body))
 	}
 
 	return nil
@@ -268,25 +447,44 @@ func (p *PrometheusAlertManager) DeleteSilence(id string) error {
 
 // ExtendSilence extends the end time of a silence
 func (p *PrometheusAlertManager) ExtendSilence(id string, newEndTime time.Time) error {
-	silence, err := p.GetSilence(id)
+	return p.ExtendSilenceContext(context.Background(), id, newEndTime)
+}
+
+// ExtendSilenceContext extends the end time of a silence, giving up when ctx is done
+func (p *PrometheusAlertManager) ExtendSilenceContext(ctx context.Context, id string, newEndTime time.Time) error {
+	silence, err := p.GetSilenceContext(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get silence for extension: %w", err)
 	}
 
 	silence.EndsAt = newEndTime
-	return p.UpdateSilence(silence)
+	return p.UpdateSilenceContext(ctx, silence)
 }
 
-// GetAlerts returns all active alerts matching the given matchers
+// GetAlerts returns all active alerts matching the given matchers. Alertmanager filters
+// the alerts, leaving out silenced, inhibited and unprocessed ones.
 func (p *PrometheusAlertManager) GetAlerts(matchers []Matcher) ([]*Alert, error) {
-	url := fmt.Sprintf("%s/api/v2/alerts", p.baseURL)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	return p.GetAlertsContext(context.Background(), matchers)
+}
+
+// GetAlertsContext returns all active alerts matching the given matchers, giving up when
+// ctx is done
+func (p *PrometheusAlertManager) GetAlertsContext(ctx context.Context, matchers []Matcher) ([]*Alert, error) {
+	query := filterQuery(matchers)
+	query.Set("active", "true")
+	query.Set("silenced", "false")
+	query.Set("inhibited", "false")
+	query.Set("unprocessed", "false")
+	url := fmt.Sprintf("%s/api/v2/alerts?%s", p.baseURL, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	p.addAuth(req)
+	if err := p.addAuth(req); err != nil {
+		return nil, err
+	}
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.do(req, "alerts")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get alerts: %w", err)
 	}
@@ -294,7 +492,7 @@ func (p *PrometheusAlertManager) GetAlerts(matchers []Matcher) ([]*Alert, error)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, apierror.FromStatus(resp.StatusCode, string(body))
 	}
 
 	var paList []promAlert
@@ -302,6 +500,8 @@ func (p *PrometheusAlertManager) GetAlerts(matchers []Matcher) ([]*Alert, error)
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	// Alertmanager versions without filter support return every alert, so the state and
+	// matchers are checked again
 	alerts := make([]*Alert, 0)
 	for i := range paList {
 		// Only include firing alerts
@@ -316,6 +516,72 @@ func (p *PrometheusAlertManager) GetAlerts(matchers []Matcher) ([]*Alert, error)
 	return alerts, nil
 }
 
+// GetSilencedAlerts returns the alerts currently silenced by the silence with the given ID
+func (p *PrometheusAlertManager) GetSilencedAlerts(silenceID string) ([]*Alert, error) {
+	return p.GetSilencedAlertsContext(context.Background(), silenceID)
+}
+
+// GetSilencedAlertsContext returns the alerts currently silenced by the silence with the
+// given ID, giving up when ctx is done. Alertmanager is asked for the silenced alerts
+// matching the silence's matchers; alerts muted by other silences only are left out.
+func (p *PrometheusAlertManager) GetSilencedAlertsContext(ctx context.Context, silenceID string) ([]*Alert, error) {
+	silence, err := p.GetSilenceContext(ctx, silenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get silence: %w", err)
+	}
+
+	query := filterQuery(silence.Matchers)
+	query.Set("active", "false")
+	query.Set("silenced", "true")
+	query.Set("inhibited", "true")
+	query.Set("unprocessed", "false")
+	url := fmt.Sprintf("%s/api/v2/alerts?%s", p.baseURL, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := p.addAuth(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.do(req, "silenced_alerts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, apierror.FromStatus(resp.StatusCode, string(body))
+	}
+
+	var paList []promAlert
+	if err := json.NewDecoder(resp.Body).Decode(&paList); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	alerts := make([]*Alert, 0)
+	for i := range paList {
+		for _, id := range paList[i].Status.SilencedBy {
+			if id == silenceID {
+				alerts = append(alerts, p.convertFromPromAlert(&paList[i]))
+				break
+			}
+		}
+	}
+
+	return alerts, nil
+}
+
+// filterQuery returns the filter query parameters selecting alerts or silences by matchers
+func filterQuery(matchers []Matcher) url.Values {
+	query := url.Values{}
+	for _, m := range matchers {
+		query.Add("filter", fmt.Sprintf("%s%s%q", m.Name, m.op(), m.Value))
+	}
+	return query
+}
+
 // Helper functions for conversion
 func (p *PrometheusAlertManager) convertFromPromSilence(ps *promSilence) *Silence {
 	matchers := make([]Matcher, len(ps.Matchers))
@@ -353,8 +619,9 @@ func (p *PrometheusAlertManager) convertToPromSilence(s *Silence) *promSilence {
 		}
 	}
 
-	// Embed ticket reference in comment if present
-	comment := s.Comment
+	// Embed ticket reference in comment if present, replacing any previous header. Silences
+	// adopted through the ticket reference pattern get the header on their first update.
+	comment := p.stripTicketRefHeader(s.Comment)
 	if s.TicketRef != "" {
 		comment = fmt.Sprintf("# %s: %s\n%s", p.annotationPrefix, s.TicketRef, comment)
 	}
@@ -388,9 +655,9 @@ func (p *PrometheusAlertManager) matchesMatchers(alert *Alert, matchers []Matche
 		labelValue, exists := alert.Labels[matcher.Name]
 
 		if matcher.IsRegex {
-			// For simplicity, we'll do basic string matching here
-			// In production, you'd want to compile and cache regex patterns
-			matched := labelValue == matcher.Value
+			// Alertmanager anchors regex matchers at both ends
+			re, err := regexp.Compile("^(?:" + matcher.Value + ")$")
+			matched := err == nil && re.MatchString(labelValue)
 			if matcher.IsEqual != matched {
 				return false
 			}
@@ -410,8 +677,38 @@ func (p *PrometheusAlertManager) matchesMatchers(alert *Alert, matchers []Matche
 	return true
 }
 
-// extractTicketRef extracts the ticket reference from a comment
+// extractTicketRef extracts the ticket reference from a comment, falling back to the
+// ticket reference pattern for comments without the annotation header
 func (p *PrometheusAlertManager) extractTicketRef(comment string) string {
+	if ref := p.headerTicketRef(comment); ref != "" {
+		return ref
+	}
+	if p.ticketRefPattern == nil {
+		return ""
+	}
+
+	match := p.ticketRefPattern.FindStringSubmatch(comment)
+	switch {
+	case match == nil:
+		return ""
+	case len(match) > 1:
+		return match[1]
+	default:
+		return match[0]
+	}
+}
+
+// stripTicketRefHeader removes the "# prefix: " header line from a comment
+func (p *PrometheusAlertManager) stripTicketRefHeader(comment string) string {
+	if p.headerTicketRef(comment) == "" {
+		return comment
+	}
+	_, rest, _ := strings.Cut(comment, "\n")
+	return rest
+}
+
+// headerTicketRef extracts the ticket reference from the "# prefix: " header of a comment
+func (p *PrometheusAlertManager) headerTicketRef(comment string) string {
 	// Look for pattern "# prefix: TICKET-123"
 	prefix := fmt.Sprintf("# %s: ", p.annotationPrefix)
 	if len(comment) < len(prefix) {
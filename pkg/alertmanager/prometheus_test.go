@@ -1,11 +1,27 @@
 package alertmanager
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
 )
 
 func TestNewPrometheusAlertManager(t *testing.T) {
@@ -63,6 +79,144 @@ func TestNewPrometheusAlertManagerWithConfig_DefaultPrefix(t *testing.T) {
 	}
 }
 
+func TestNewPrometheusAlertManagerWithConfig_Transport(t *testing.T) {
+	am := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+		BaseURL:             "http://localhost:9093",
+		Timeout:             5 * time.Second,
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     time.Minute,
+		DisableKeepAlives:   true,
+	})
+
+	if am.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected 5s timeout, got %v", am.httpClient.Timeout)
+	}
+	transport := am.httpClient.Transport.(*http.Transport)
+	if transport.MaxIdleConns != 20 || transport.MaxIdleConnsPerHost != 10 ||
+		transport.IdleConnTimeout != time.Minute || !transport.DisableKeepAlives {
+		t.Errorf("Unexpected transport settings: %+v", transport)
+	}
+
+	defaults := NewPrometheusAlertManager("http://localhost:9093")
+	if defaults.httpClient.Timeout != 30*time.Second {
+		t.Errorf("Expected default 30s timeout, got %v", defaults.httpClient.Timeout)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode([]promSilence{})
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManager(server.URL)
+	if _, err := WithContext(context.Background(), am).ListSilences(); err != nil {
+		t.Fatalf("ListSilences() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WithContext(ctx, am).ListSilences(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancelled request, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", requests)
+	}
+
+	// Alertmanagers without context support are returned unchanged
+	var plain AlertManager = struct{ AlertManager }{am}
+	if WithContext(ctx, plain) != plain {
+		t.Error("Expected an alertmanager without context support to be returned unchanged")
+	}
+}
+
+func TestDo_RetriesTransientFailures(t *testing.T) {
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.Method]++
+		if r.Method == http.MethodGet && attempts[r.Method] == 1 || r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]promSilence{})
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManager(server.URL)
+	var slept []time.Duration
+	am.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	if _, err := am.ListSilences(); err != nil {
+		t.Fatalf("ListSilences() failed: %v", err)
+	}
+	if attempts[http.MethodGet] != 2 || len(slept) != 1 {
+		t.Errorf("Expected the list request to be retried once, got %d attempts and waits %v", attempts[http.MethodGet], slept)
+	}
+
+	// Creating a silence may have taken effect, so it is not retried
+	_, err := am.CreateSilence(&Silence{
+		Matchers:  []Matcher{{Name: "alertname", Value: "Test", IsEqual: true}},
+		CreatedBy: "test",
+		Comment:   "test",
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(time.Hour),
+	})
+	if attempts[http.MethodPost] != 1 {
+		t.Errorf("Expected 1 create request, got %d", attempts[http.MethodPost])
+	}
+	if apierror.CategoryOf(err) != apierror.Transient {
+		t.Errorf("Expected a transient error, got %v", err)
+	}
+}
+
+func TestDeleteSilence_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := NewPrometheusAlertManager(server.URL).DeleteSilence("gone")
+	if !errors.Is(err, ErrSilenceNotFound) || apierror.CategoryOf(err) != apierror.NotFound {
+		t.Errorf("Expected ErrSilenceNotFound, got %v", err)
+	}
+}
+
+// recordingObserver records the operations of observed requests
+type recordingObserver struct {
+	operations []string
+}
+
+func (o *recordingObserver) RecordRequestDuration(system, operation string, duration time.Duration) {
+	o.operations = append(o.operations, system+"/"+operation)
+}
+
+func TestSetRequestObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			return
+		}
+		json.NewEncoder(w).Encode([]promSilence{})
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	am := NewPrometheusAlertManager(server.URL)
+	am.SetRequestObserver(observer)
+	if _, err := am.ListSilences(); err != nil {
+		t.Fatalf("ListSilences() failed: %v", err)
+	}
+	if err := am.DeleteSilence("silence-1"); err != nil {
+		t.Fatalf("DeleteSilence() failed: %v", err)
+	}
+
+	if len(observer.operations) != 2 || observer.operations[0] != "alertmanager/list" || observer.operations[1] != "alertmanager/delete" {
+		t.Errorf("Expected list and delete to be observed, got %v", observer.operations)
+	}
+}
+
 func TestGetSilence_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/silence/test-id" {
@@ -120,6 +274,105 @@ func TestGetSilence_NotFound(t *testing.T) {
 	}
 }
 
+func TestListSilences_CustomTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is self-signed, so the default client rejects it
+	if _, err := NewPrometheusAlertManager(server.URL).ListSilences(); err == nil {
+		t.Error("Expected error for untrusted certificate")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	am := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+		BaseURL:   server.URL,
+		TLSConfig: &tls.Config{RootCAs: pool},
+	})
+	if _, err := am.ListSilences(); err != nil {
+		t.Errorf("Expected trusted CA to be accepted, got %v", err)
+	}
+}
+
+func TestListSilences_MTLS(t *testing.T) {
+	certFile, keyFile, clientCert := writeClientCertificate(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	withoutCert := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+		BaseURL:   server.URL,
+		AuthType:  "none",
+		TLSConfig: &tls.Config{RootCAs: rootCAs},
+	})
+	if _, err := withoutCert.ListSilences(); err == nil {
+		t.Error("Expected error without a client certificate")
+	}
+
+	am := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+		BaseURL:        server.URL,
+		AuthType:       "mtls",
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+		TLSConfig:      &tls.Config{RootCAs: rootCAs},
+	})
+	if _, err := am.ListSilences(); err != nil {
+		t.Errorf("Expected client certificate to be accepted, got %v", err)
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key to PEM files
+func writeClientCertificate(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "silence-manager"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
 func TestListSilences_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/silences" {
@@ -168,6 +421,28 @@ func TestListSilences_Success(t *testing.T) {
 	}
 }
 
+func TestListSilences_Filter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query()["filter"]
+		if len(filter) != 2 || filter[0] != `team="payments"` || filter[1] != `env=~"prod|staging"` {
+			t.Errorf("Expected team and env filters, got %v", filter)
+		}
+		json.NewEncoder(w).Encode([]promSilence{})
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+		BaseURL: server.URL,
+		SilenceFilter: []Matcher{
+			{Name: "team", Value: "payments", IsEqual: true},
+			{Name: "env", Value: "prod|staging", IsRegex: true, IsEqual: true},
+		},
+	})
+	if _, err := am.ListSilences(); err != nil {
+		t.Fatalf("ListSilences() failed: %v", err)
+	}
+}
+
 func TestCreateSilence_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/silences" {
@@ -219,6 +494,74 @@ func TestCreateSilence_Success(t *testing.T) {
 	}
 }
 
+func TestSilenceValidate(t *testing.T) {
+	now := time.Now()
+	valid := Silence{
+		CreatedBy: "silence-manager",
+		Comment:   "Disk replacement",
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		Matchers:  []Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(s *Silence)
+		wantErr string
+	}{
+		{"Valid", func(s *Silence) {}, ""},
+		{"Ticket reference instead of comment", func(s *Silence) { s.Comment = ""; s.TicketRef = "PROJ-1" }, ""},
+		{"No matchers", func(s *Silence) { s.Matchers = nil }, "at least one matcher is required"},
+		{"Invalid label name", func(s *Silence) { s.Matchers[0].Name = "alert-name" }, `invalid label name "alert-name"`},
+		{"Invalid regex", func(s *Silence) { s.Matchers[0] = Matcher{Name: "instance", Value: "db-[", IsRegex: true, IsEqual: true} }, "matcher 1 (instance=~db-[): invalid regex"},
+		{"Only empty matchers", func(s *Silence) { s.Matchers[0] = Matcher{Name: "instance", Value: ".*", IsRegex: true, IsEqual: true} }, "must not match the empty string"},
+		{"End before start", func(s *Silence) { s.EndsAt = now.Add(-time.Hour) }, "must be after start time"},
+		{"No end", func(s *Silence) { s.EndsAt = time.Time{} }, "end time is required"},
+		{"No creator", func(s *Silence) { s.CreatedBy = " " }, "creator is required"},
+		{"No comment", func(s *Silence) { s.Comment = "" }, "comment is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silence := valid
+			silence.Matchers = append([]Matcher(nil), valid.Matchers...)
+			tt.modify(&silence)
+			err := silence.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateSilence_Invalid(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	_, err := NewPrometheusAlertManager(server.URL).CreateSilence(&Silence{
+		CreatedBy: "silence-manager",
+		Comment:   "Broken",
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(time.Hour),
+		Matchers:  []Matcher{{Name: "instance", Value: "(", IsRegex: true, IsEqual: true}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid regex") {
+		t.Errorf("Expected invalid regex error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected the invalid silence not to be sent, got %d request(s)", requests)
+	}
+}
+
 func TestUpdateSilence_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -261,6 +604,32 @@ func TestUpdateSilence_Success(t *testing.T) {
 	if err != nil {
 		t.Fatalf("UpdateSilence() failed: %v", err)
 	}
+	if silence.ID != "existing-id" {
+		t.Errorf("Expected ID 'existing-id' to be kept, got '%s'", silence.ID)
+	}
+}
+
+func TestUpdateSilence_Replaced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"silenceID": "replacement-id"}`))
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManager(server.URL)
+	silence := &Silence{
+		ID:        "existing-id",
+		CreatedBy: "test-user",
+		Comment:   "Updated matchers",
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(48 * time.Hour),
+		Matchers:  []Matcher{{Name: "alertname", Value: "OtherAlert", IsEqual: true}},
+	}
+	if err := am.UpdateSilence(silence); err != nil {
+		t.Fatalf("UpdateSilence() failed: %v", err)
+	}
+	if silence.ID != "replacement-id" {
+		t.Errorf("Expected the ID of the replacement, got '%s'", silence.ID)
+	}
 }
 
 func TestDeleteSilence_Success(t *testing.T) {
@@ -328,11 +697,50 @@ func TestExtendSilence_Success(t *testing.T) {
 	}
 }
 
+func TestExtendSilence_KeepsSingleTicketHeader(t *testing.T) {
+	var posted promSilence
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(promSilence{
+				ID:        "test-id",
+				CreatedBy: "test-user",
+				Comment:   "# silence-manager: PROJ-1\nDisk alerts",
+				StartsAt:  time.Now(),
+				EndsAt:    time.Now().Add(time.Hour),
+				Matchers:  []promMatcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+			})
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&posted)
+		json.NewEncoder(w).Encode(map[string]string{"silenceID": "test-id"})
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManager(server.URL)
+	if err := am.ExtendSilence("test-id", time.Now().Add(24*time.Hour)); err != nil {
+		t.Fatalf("ExtendSilence() failed: %v", err)
+	}
+
+	expected := "# silence-manager: PROJ-1\nDisk alerts"
+	if posted.Comment != expected {
+		t.Errorf("Expected comment %q, got %q", expected, posted.Comment)
+	}
+}
+
 func TestGetAlerts_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/alerts" {
 			t.Errorf("Expected path '/api/v2/alerts', got '%s'", r.URL.Path)
 		}
+		query := r.URL.Query()
+		if filter := query["filter"]; len(filter) != 1 || filter[0] != `alertname="TestAlert"` {
+			t.Errorf("Expected alertname filter, got %v", filter)
+		}
+		if query.Get("active") != "true" || query.Get("silenced") != "false" ||
+			query.Get("inhibited") != "false" || query.Get("unprocessed") != "false" {
+			t.Errorf("Expected only active alerts to be requested, got %s", r.URL.RawQuery)
+		}
 
 		response := []promAlert{
 			{
@@ -340,21 +748,21 @@ func TestGetAlerts_Success(t *testing.T) {
 				Annotations: map[string]string{"summary": "Test alert"},
 				StartsAt:    time.Now(),
 				EndsAt:      time.Now().Add(1 * time.Hour),
-				Status:      struct{ State string `json:"state"` }{State: "active"},
+				Status:      promAlertStatus{State: "active"},
 			},
 			{
 				Labels:      map[string]string{"alertname": "OtherAlert", "severity": "warning"},
 				Annotations: map[string]string{"summary": "Other alert"},
 				StartsAt:    time.Now(),
 				EndsAt:      time.Now().Add(1 * time.Hour),
-				Status:      struct{ State string `json:"state"` }{State: "active"},
+				Status:      promAlertStatus{State: "active"},
 			},
 			{
 				Labels:      map[string]string{"alertname": "InactiveAlert"},
 				Annotations: map[string]string{"summary": "Inactive alert"},
 				StartsAt:    time.Now(),
 				EndsAt:      time.Now().Add(1 * time.Hour),
-				Status:      struct{ State string `json:"state"` }{State: "resolved"},
+				Status:      promAlertStatus{State: "resolved"},
 			},
 		}
 		json.NewEncoder(w).Encode(response)
@@ -386,13 +794,13 @@ func TestGetAlerts_NoMatchers(t *testing.T) {
 				Labels:   map[string]string{"alertname": "Alert1"},
 				StartsAt: time.Now(),
 				EndsAt:   time.Now().Add(1 * time.Hour),
-				Status:   struct{ State string `json:"state"` }{State: "active"},
+				Status:   promAlertStatus{State: "active"},
 			},
 			{
 				Labels:   map[string]string{"alertname": "Alert2"},
 				StartsAt: time.Now(),
 				EndsAt:   time.Now().Add(1 * time.Hour),
-				Status:   struct{ State string `json:"state"` }{State: "active"},
+				Status:   promAlertStatus{State: "active"},
 			},
 		}
 		json.NewEncoder(w).Encode(response)
@@ -411,6 +819,49 @@ func TestGetAlerts_NoMatchers(t *testing.T) {
 	}
 }
 
+func TestGetSilencedAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/silence/test-id":
+			json.NewEncoder(w).Encode(promSilence{
+				ID:       "test-id",
+				Matchers: []promMatcher{{Name: "instance", Value: "db-.*", IsRegex: true, IsEqual: true}},
+			})
+		case "/api/v2/alerts":
+			query := r.URL.Query()
+			if filter := query["filter"]; len(filter) != 1 || filter[0] != `instance=~"db-.*"` {
+				t.Errorf("Expected the silence matchers as filter, got %v", filter)
+			}
+			if query.Get("active") != "false" || query.Get("silenced") != "true" {
+				t.Errorf("Expected only silenced alerts to be requested, got %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode([]promAlert{
+				{
+					Labels: map[string]string{"alertname": "DiskFull", "instance": "db-1"},
+					Status: promAlertStatus{State: "suppressed", SilencedBy: []string{"other-id", "test-id"}},
+				},
+				{
+					Labels: map[string]string{"alertname": "DiskFull", "instance": "db-2"},
+					Status: promAlertStatus{State: "suppressed", SilencedBy: []string{"other-id"}},
+				},
+			})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManager(server.URL)
+	alerts, err := am.GetSilencedAlerts("test-id")
+	if err != nil {
+		t.Fatalf("GetSilencedAlerts() failed: %v", err)
+	}
+	// Alerts muted only by other silences are left out
+	if len(alerts) != 1 || alerts[0].Labels["instance"] != "db-1" {
+		t.Errorf("Expected the alert silenced by test-id, got %+v", alerts)
+	}
+}
+
 func TestExtractTicketRef(t *testing.T) {
 	am := NewPrometheusAlertManager("http://localhost:9093")
 
@@ -471,6 +922,86 @@ func TestExtractTicketRef_CustomPrefix(t *testing.T) {
 	}
 }
 
+func TestExtractTicketRef_Pattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		comment  string
+		expected string
+	}{
+		{
+			name:     "Key anywhere in comment",
+			pattern:  `\b[A-Z][A-Z0-9]+-[0-9]+\b`,
+			comment:  "silencing until PROJ-123 fixed",
+			expected: "PROJ-123",
+		},
+		{
+			name:     "Header takes precedence",
+			pattern:  `\b[A-Z][A-Z0-9]+-[0-9]+\b`,
+			comment:  "# silence-manager: PROJ-1\nsee also OPS-2",
+			expected: "PROJ-1",
+		},
+		{
+			name:     "Capture group",
+			pattern:  `(?i)ticket:?\s*([A-Z]+-[0-9]+)`,
+			comment:  "Flapping disk, ticket OPS-42",
+			expected: "OPS-42",
+		},
+		{
+			name:     "No match",
+			pattern:  `\bOPS-[0-9]+\b`,
+			comment:  "silencing until PROJ-123 fixed",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+				BaseURL:          "http://localhost:9093",
+				TicketRefPattern: regexp.MustCompile(tt.pattern),
+			})
+			if result := am.extractTicketRef(tt.comment); result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExtendSilence_AdoptedSilenceGetsHeader(t *testing.T) {
+	var posted promSilence
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(promSilence{
+				ID:        "test-id",
+				CreatedBy: "test-user",
+				Comment:   "silencing until PROJ-123 fixed",
+				StartsAt:  time.Now(),
+				EndsAt:    time.Now().Add(time.Hour),
+				Matchers:  []promMatcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+			})
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&posted)
+		json.NewEncoder(w).Encode(map[string]string{"silenceID": "test-id"})
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+		BaseURL:          server.URL,
+		TicketRefPattern: regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`),
+	})
+	if err := am.ExtendSilence("test-id", time.Now().Add(24*time.Hour)); err != nil {
+		t.Fatalf("ExtendSilence() failed: %v", err)
+	}
+
+	expected := "# silence-manager: PROJ-123\nsilencing until PROJ-123 fixed"
+	if posted.Comment != expected {
+		t.Errorf("Expected comment %q, got %q", expected, posted.Comment)
+	}
+}
+
 func TestMatchesMatchers(t *testing.T) {
 	am := NewPrometheusAlertManager("http://localhost:9093")
 
@@ -523,6 +1054,27 @@ func TestMatchesMatchers(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "Regex matcher (=~) matching",
+			matchers: []Matcher{
+				{Name: "instance", Value: "server[0-9]+", IsRegex: true, IsEqual: true},
+			},
+			expected: true,
+		},
+		{
+			name: "Regex matcher (=~) is anchored",
+			matchers: []Matcher{
+				{Name: "instance", Value: "server", IsRegex: true, IsEqual: true},
+			},
+			expected: false,
+		},
+		{
+			name: "Negative regex matcher (!~) not matching",
+			matchers: []Matcher{
+				{Name: "severity", Value: "crit.*", IsRegex: true, IsEqual: false},
+			},
+			expected: false,
+		},
 		{
 			name:     "No matchers",
 			matchers: []Matcher{},
@@ -599,9 +1151,36 @@ func TestAddAuth(t *testing.T) {
 			am := NewPrometheusAlertManagerWithConfig(config)
 
 			req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
-			am.addAuth(req)
+			if err := am.addAuth(req); err != nil {
+				t.Fatalf("addAuth failed: %v", err)
+			}
 
 			tt.checkFunc(t, req)
 		})
 	}
 }
+
+func TestConvertToPromSilence_ReplacesTicketRef(t *testing.T) {
+	am := NewPrometheusAlertManager("http://localhost:9093")
+
+	tests := []struct {
+		name      string
+		ticketRef string
+		expected  string
+	}{
+		{"Relinked", "PROJ-456", "# silence-manager: PROJ-456\nDisk replacement"},
+		{"Unlinked", "", "Disk replacement"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := am.convertToPromSilence(&Silence{
+				Comment:   "# silence-manager: PROJ-123\nDisk replacement",
+				TicketRef: tt.ticketRef,
+			})
+			if ps.Comment != tt.expected {
+				t.Errorf("Expected comment %q, got %q", tt.expected, ps.Comment)
+			}
+		})
+	}
+}
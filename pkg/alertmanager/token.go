@@ -0,0 +1,183 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the bearer token sent with each request
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// staticToken is a bearer token that never changes
+type staticToken string
+
+func (t staticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// fileTokenSource reads the bearer token from a file that is rotated in place, such as a
+// projected Kubernetes service account token. The file is read again when it changes.
+type fileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+// NewFileTokenSource returns a token source reading the token from path
+func NewFileTokenSource(path string) TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+func (f *fileTokenSource) Token(ctx context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read bearer token file: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.token != "" && info.ModTime().Equal(f.modTime) {
+		return f.token, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read bearer token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("bearer token file %s is empty", f.path)
+	}
+	f.token, f.modTime = token, info.ModTime()
+	return f.token, nil
+}
+
+// OIDCConfig holds the OAuth 2.0 client credentials used to fetch bearer tokens
+type OIDCConfig struct {
+	// IssuerURL is used to discover the token endpoint when TokenURL is not set
+	IssuerURL    string
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Audience     string // Sent as the audience parameter, e.g. for Auth0 or Okta
+}
+
+// tokenRefreshMargin is how long before its expiry a token is replaced
+const tokenRefreshMargin = time.Minute
+
+// oidcTokenSource fetches tokens with the client credentials grant and reuses each
+// token until shortly before it expires
+type oidcTokenSource struct {
+	config     OIDCConfig
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	tokenURL string
+	token    string
+	expiry   time.Time
+}
+
+// NewOIDCTokenSource returns a token source fetching tokens from an OAuth 2.0 or OIDC
+// token endpoint with httpClient
+func NewOIDCTokenSource(config OIDCConfig, httpClient *http.Client) TokenSource {
+	return &oidcTokenSource{config: config, httpClient: httpClient, tokenURL: config.TokenURL}
+}
+
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (o *oidcTokenSource) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.token != "" && time.Now().Before(o.expiry) {
+		return o.token, nil
+	}
+
+	if o.tokenURL == "" {
+		tokenURL, err := o.discoverTokenURL(ctx)
+		if err != nil {
+			return "", err
+		}
+		o.tokenURL = tokenURL
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(o.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.config.Scopes, " "))
+	}
+	if o.config.Audience != "" {
+		form.Set("audience", o.config.Audience)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(o.config.ClientSecret))
+
+	var resp oidcTokenResponse
+	if err := o.getJSON(req, &resp); err != nil {
+		return "", fmt.Errorf("failed to fetch token: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", fmt.Errorf("failed to fetch token: no access_token in response")
+	}
+
+	o.token = resp.AccessToken
+	o.expiry = time.Time{}
+	if resp.ExpiresIn > 0 {
+		o.expiry = time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - tokenRefreshMargin)
+	}
+	return o.token, nil
+}
+
+// discoverTokenURL reads the token endpoint from the issuer's discovery document
+func (o *oidcTokenSource) discoverTokenURL(ctx context.Context) (string, error) {
+	discoveryURL := strings.TrimSuffix(o.config.IssuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create discovery request: %w", err)
+	}
+
+	var doc struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := o.getJSON(req, &doc); err != nil {
+		return "", fmt.Errorf("failed to discover token endpoint: %w", err)
+	}
+	if doc.TokenEndpoint == "" {
+		return "", fmt.Errorf("failed to discover token endpoint: no token_endpoint in %s", discoveryURL)
+	}
+	return doc.TokenEndpoint, nil
+}
+
+func (o *oidcTokenSource) getJSON(req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,133 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTokenSource_PicksUpRotatedToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewFileTokenSource(path)
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token != "first" {
+		t.Errorf("Expected token 'first', got %q", token)
+	}
+
+	if err := os.WriteFile(path, []byte("second\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	token, err = source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token != "second" {
+		t.Errorf("Expected rotated token 'second', got %q", token)
+	}
+}
+
+func TestFileTokenSource_MissingFile(t *testing.T) {
+	source := NewFileTokenSource(filepath.Join(t.TempDir(), "missing"))
+	if _, err := source.Token(context.Background()); err == nil {
+		t.Error("Expected error for missing token file")
+	}
+}
+
+func TestOIDCTokenSource(t *testing.T) {
+	var requests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"token_endpoint": server.URL + "/token"})
+		case "/token":
+			requests++
+			user, pass, _ := r.BasicAuth()
+			if user != "silence-manager" || pass != "secret" {
+				t.Errorf("Expected client credentials silence-manager:secret, got %s:%s", user, pass)
+			}
+			if r.FormValue("grant_type") != "client_credentials" {
+				t.Errorf("Expected client_credentials grant, got %q", r.FormValue("grant_type"))
+			}
+			if r.FormValue("scope") != "alertmanager.read alertmanager.write" {
+				t.Errorf("Unexpected scope %q", r.FormValue("scope"))
+			}
+			json.NewEncoder(w).Encode(oidcTokenResponse{AccessToken: "access-token", TokenType: "Bearer", ExpiresIn: 3600})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	source := NewOIDCTokenSource(OIDCConfig{
+		IssuerURL:    server.URL,
+		ClientID:     "silence-manager",
+		ClientSecret: "secret",
+		Scopes:       []string{"alertmanager.read", "alertmanager.write"},
+	}, server.Client())
+
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token failed: %v", err)
+		}
+		if token != "access-token" {
+			t.Errorf("Expected token 'access-token', got %q", token)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the token to be fetched once and cached, got %d requests", requests)
+	}
+}
+
+func TestOIDCTokenSource_RefreshesExpiredToken(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Tokens valid for less than the refresh margin are replaced on every use
+		json.NewEncoder(w).Encode(oidcTokenResponse{AccessToken: "short-lived", ExpiresIn: 30})
+	}))
+	defer server.Close()
+
+	source := NewOIDCTokenSource(OIDCConfig{TokenURL: server.URL, ClientID: "id", ClientSecret: "secret"}, server.Client())
+	for i := 0; i < 2; i++ {
+		if _, err := source.Token(context.Background()); err != nil {
+			t.Fatalf("Token failed: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected the expiring token to be refreshed, got %d requests", requests)
+	}
+}
+
+func TestOIDCTokenSource_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	am := NewPrometheusAlertManagerWithConfig(AlertManagerConfig{
+		BaseURL:  server.URL,
+		AuthType: "bearer",
+		OIDC:     &OIDCConfig{TokenURL: server.URL + "/token", ClientID: "id", ClientSecret: "wrong"},
+	})
+	if _, err := am.ListSilences(); err == nil {
+		t.Error("Expected ListSilences to fail when no token can be fetched")
+	}
+}
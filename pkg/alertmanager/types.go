@@ -1,6 +1,9 @@
 package alertmanager
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Silence represents a silence in an alertmanager system
 type Silence struct {
@@ -21,6 +24,24 @@ type Matcher struct {
 	IsEqual bool // true for =, false for !=
 }
 
+// String renders the matcher in the name=value, name!=value, name=~regex or name!~regex form
+func (m Matcher) String() string {
+	return m.Name + m.op() + m.Value
+}
+
+// op returns the matcher operator: =, !=, =~ or !~
+func (m Matcher) op() string {
+	switch {
+	case m.IsRegex && m.IsEqual:
+		return "=~"
+	case m.IsRegex && !m.IsEqual:
+		return "!~"
+	case !m.IsEqual:
+		return "!="
+	}
+	return "="
+}
+
 // Alert represents an alert that has fired
 type Alert struct {
 	Labels      map[string]string
@@ -41,7 +62,8 @@ type AlertManager interface {
 	// CreateSilence creates a new silence and returns its ID
 	CreateSilence(silence *Silence) (string, error)
 
-	// UpdateSilence updates an existing silence
+	// UpdateSilence updates an existing silence. Alertmanager expires a silence whose
+	// matchers change and replaces it with a new one; silence.ID is then set to the new ID.
 	UpdateSilence(silence *Silence) error
 
 	// DeleteSilence deletes a silence by ID
@@ -52,4 +74,22 @@ type AlertManager interface {
 
 	// GetAlerts returns all active alerts matching the given matchers
 	GetAlerts(matchers []Matcher) ([]*Alert, error)
+
+	// GetSilencedAlerts returns the alerts currently silenced by the given silence
+	GetSilencedAlerts(silenceID string) ([]*Alert, error)
+}
+
+// ContextAlertManager is implemented by alertmanagers whose requests accept a context, so
+// that deadlines and cancellation of the caller are enforced
+type ContextAlertManager interface {
+	AlertManager
+
+	GetSilenceContext(ctx context.Context, id string) (*Silence, error)
+	ListSilencesContext(ctx context.Context) ([]*Silence, error)
+	CreateSilenceContext(ctx context.Context, silence *Silence) (string, error)
+	UpdateSilenceContext(ctx context.Context, silence *Silence) error
+	DeleteSilenceContext(ctx context.Context, id string) error
+	ExtendSilenceContext(ctx context.Context, id string, newEndTime time.Time) error
+	GetAlertsContext(ctx context.Context, matchers []Matcher) ([]*Alert, error)
+	GetSilencedAlertsContext(ctx context.Context, silenceID string) ([]*Alert, error)
 }
@@ -0,0 +1,74 @@
+package alertmanager
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// labelNamePattern matches the label names accepted by Alertmanager
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Validate checks the silence against the rules Alertmanager enforces, so that an invalid
+// silence is reported with the offending field instead of an opaque 400 response. All
+// problems found are returned together.
+func (s *Silence) Validate() error {
+	var problems []string
+	if len(s.Matchers) == 0 {
+		problems = append(problems, "at least one matcher is required")
+	}
+	matchesEmpty := true
+	for i, m := range s.Matchers {
+		if err := m.validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("matcher %d (%s): %v", i+1, m, err))
+			continue
+		}
+		if !m.matchesEmpty() {
+			matchesEmpty = false
+		}
+	}
+	if len(s.Matchers) > 0 && matchesEmpty {
+		problems = append(problems, "at least one matcher must not match the empty string, or the silence would match every alert")
+	}
+
+	switch {
+	case s.EndsAt.IsZero():
+		problems = append(problems, "end time is required")
+	case !s.EndsAt.After(s.StartsAt):
+		problems = append(problems, fmt.Sprintf("end time %s must be after start time %s", s.EndsAt.Format(time.RFC3339), s.StartsAt.Format(time.RFC3339)))
+	}
+	if strings.TrimSpace(s.CreatedBy) == "" {
+		problems = append(problems, "creator is required")
+	}
+	if strings.TrimSpace(s.Comment) == "" && s.TicketRef == "" {
+		problems = append(problems, "comment is required")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid silence: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validate checks the label name and, for regex matchers, that the regex compiles
+func (m Matcher) validate() error {
+	if !labelNamePattern.MatchString(m.Name) {
+		return fmt.Errorf("invalid label name %q", m.Name)
+	}
+	if m.IsRegex {
+		if _, err := regexp.Compile("^(?:" + m.Value + ")$"); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+	return nil
+}
+
+// matchesEmpty reports whether the matcher matches an alert without the label
+func (m Matcher) matchesEmpty() bool {
+	matched := m.Value == ""
+	if m.IsRegex {
+		matched = regexp.MustCompile("^(?:" + m.Value + ")$").MatchString("")
+	}
+	return matched == m.IsEqual
+}
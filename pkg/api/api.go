@@ -0,0 +1,399 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/plan"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// defaultShutdownTimeout bounds how long in-flight requests may take once the server stops
+const defaultShutdownTimeout = 30 * time.Second
+
+// ErrRunInProgress is returned by Backend.Sync when another run holds the run lock
+var ErrRunInProgress = errors.New("a synchronization run is already in progress")
+
+// SyncResponse reports a synchronization run triggered through the API
+type SyncResponse struct {
+	Started  time.Time      `json:"started"`
+	Finished time.Time      `json:"finished"`
+	Counts   map[string]int `json:"counts"`
+	Errors   []string       `json:"errors,omitempty"`
+}
+
+// Backend performs the operations exposed by the API
+type Backend interface {
+	// Sync performs a synchronization run
+	Sync(ctx context.Context) (*SyncResponse, error)
+
+	// List returns the active silences, their tickets and the next action for each
+	List(ctx context.Context) ([]sync.PlannedAction, error)
+
+	// Plan returns the changes a synchronization run would make, without applying them
+	Plan(ctx context.Context) (*plan.Plan, error)
+
+	// Link attaches a silence to a ticket
+	Link(ctx context.Context, silenceID, ticketKey string) error
+
+	// Unlink detaches a silence from its ticket
+	Unlink(ctx context.Context, silenceID string) error
+
+	// Create creates a silence linked to an existing or new ticket
+	Create(ctx context.Context, def sync.SilenceDefinition) (*alertmanager.Silence, error)
+
+	// Purge deletes the silences without a ticket reference, or all managed silences, and
+	// returns them. With dryRun they are only listed.
+	Purge(ctx context.Context, all, dryRun bool) (*PurgeResponse, error)
+}
+
+// PurgeResponse reports the silences selected by a purge, and how many were deleted
+type PurgeResponse struct {
+	Scope    string          `json:"scope"`
+	Silences []PurgedSilence `json:"silences"`
+	Deleted  int             `json:"deleted"`
+}
+
+// PurgedSilence is a silence selected by a purge
+type PurgedSilence struct {
+	ID        string    `json:"id"`
+	TicketRef string    `json:"ticketRef,omitempty"`
+	CreatedBy string    `json:"createdBy"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+// Purge scopes
+const (
+	PurgeOrphans = "orphans"
+	PurgeAll     = "all"
+)
+
+// Server serves the REST API:
+//
+//   - POST /api/v1/sync triggers a synchronization run and returns its result
+//   - GET /api/v1/silences lists the managed silences with their ticket status
+//   - GET /api/v1/plan returns the changes the next run would make
+//   - PUT /api/v1/silences/{id}/ticket links a silence to the ticket {"ticket": "OPS-1"}
+//   - DELETE /api/v1/silences/{id}/ticket unlinks a silence
+//   - POST /api/v1/silences creates a silence and its ticket, from matchers or a template
+//   - GET /api/v1/purge?scope=orphans lists the silences a purge would delete
+//   - POST /api/v1/purge?scope=orphans&confirm=true deletes them
+//
+// Requests authenticate with a bearer token, whose role authorizes the operation. Bulk
+// destructive operations need an admin token, or an operator token with confirm=true.
+// Only one request changing silences runs at a time.
+type Server struct {
+	backend Backend
+	tokens  map[string]authz.Role
+
+	busy      chan struct{}           // Held by the operation changing silences
+	routes    map[string]http.Handler // Served alongside the API, e.g. the Slack endpoint
+	templates sync.SilenceTemplates
+	// shutdownTimeout bounds how long Run waits for in-flight requests once it stops
+	shutdownTimeout time.Duration
+}
+
+// NewServer creates an API server authenticating the given bearer tokens
+func NewServer(backend Backend, tokens map[string]authz.Role) *Server {
+	return &Server{backend: backend, tokens: tokens, busy: make(chan struct{}, 1), routes: make(map[string]http.Handler),
+		shutdownTimeout: defaultShutdownTimeout}
+}
+
+// SetShutdownTimeout sets how long Run waits for in-flight requests, such as a
+// synchronization run, once its context is done
+func (s *Server) SetShutdownTimeout(timeout time.Duration) {
+	s.shutdownTimeout = timeout
+}
+
+// SetTemplates sets the silence templates that create requests may refer to by name
+func (s *Server) SetTemplates(templates sync.SilenceTemplates) {
+	s.templates = templates
+}
+
+// Handle serves handler for pattern alongside the API. The handler authenticates requests
+// itself, as the Slack endpoint does with the app's request signatures.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.routes[pattern] = handler
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/sync", s.authorized(authz.OpWrite, s.handleSync))
+	mux.HandleFunc("GET /api/v1/silences", s.authorized(authz.OpRead, s.handleList))
+	mux.HandleFunc("GET /api/v1/plan", s.authorized(authz.OpRead, s.handlePlan))
+	mux.HandleFunc("PUT /api/v1/silences/{id}/ticket", s.authorized(authz.OpWrite, s.handleLink))
+	mux.HandleFunc("DELETE /api/v1/silences/{id}/ticket", s.authorized(authz.OpWrite, s.handleUnlink))
+	mux.HandleFunc("POST /api/v1/silences", s.authorized(authz.OpWrite, s.handleCreate))
+	mux.HandleFunc("GET /api/v1/purge", s.authorized(authz.OpRead, s.handlePurge))
+	mux.HandleFunc("POST /api/v1/purge", s.authorized(authz.OpBulkDestructive, s.handlePurge))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	for pattern, handler := range s.routes {
+		mux.Handle(pattern, handler)
+	}
+	return mux
+}
+
+// Run serves the API on addr until ctx is done. It then stops accepting requests and
+// returns once the requests in flight have finished, or after the shutdown timeout.
+func (s *Server) Run(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to serve API on %s: %w", addr, err)
+	}
+	return s.serve(ctx, ln)
+}
+
+// serve serves the API on ln until ctx is done, like Run
+func (s *Server) serve(ctx context.Context, ln net.Listener) error {
+	server := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	stopped := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+		defer cancel()
+		stopped <- server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve API on %s: %w", ln.Addr(), err)
+	}
+	// Serve returns as soon as the shutdown begins, before the requests in flight finish
+	if err := <-stopped; err != nil {
+		log.Printf("Warning: requests still in flight after %v, stopping anyway: %v", s.shutdownTimeout, err)
+	}
+	return nil
+}
+
+// authorized wraps a handler with authentication and authorization of op. Bulk
+// destructive operations are confirmed by the query parameter confirm=true.
+func (s *Server) authorized(op authz.Operation, handler func(http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		role, ok := authz.BearerRole(r.Header.Get("Authorization"), s.tokens)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="silence-manager"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		confirmed := r.URL.Query().Get("confirm") == "true"
+		if err := authz.Authorize(role, op, confirmed); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		log.Printf("API %s %s (role: %s)", r.Method, r.URL.Path, role)
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	select {
+	case s.busy <- struct{}{}:
+		defer func() { <-s.busy }()
+	default:
+		writeError(w, http.StatusConflict, ErrRunInProgress)
+		return
+	}
+
+	result, err := s.backend.Sync(r.Context())
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	silences, err := s.backend.List(r.Context())
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	if silences == nil {
+		silences = []sync.PlannedAction{}
+	}
+	writeJSON(w, http.StatusOK, silences)
+}
+
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	p, err := s.backend.Plan(r.Context())
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// linkRequest is the body of a link request
+type linkRequest struct {
+	Ticket string `json:"ticket"`
+}
+
+func (s *Server) handleLink(w http.ResponseWriter, r *http.Request) {
+	var req linkRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil || req.Ticket == "" {
+		writeError(w, http.StatusBadRequest, errors.New(`expected a body like {"ticket": "OPS-1"}`))
+		return
+	}
+
+	s.busy <- struct{}{}
+	defer func() { <-s.busy }()
+	if err := s.backend.Link(r.Context(), r.PathValue("id"), req.Ticket); err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleUnlink(w http.ResponseWriter, r *http.Request) {
+	s.busy <- struct{}{}
+	defer func() { <-s.busy }()
+	if err := s.backend.Unlink(r.Context(), r.PathValue("id")); err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createRequest is the body of a create request. With a template, its parameters are
+// filled in and the other fields override the template; otherwise matchers are required.
+type createRequest struct {
+	Template   string            `json:"template"`
+	Parameters map[string]string `json:"parameters"`
+	Matchers   []string          `json:"matchers"`
+	Duration   string            `json:"duration"`
+	Summary    string            `json:"summary"`
+	Comment    string            `json:"comment"`
+	Ticket     string            `json:"ticket"`
+	CreatedBy  string            `json:"createdBy"`
+}
+
+// createResponse reports the created silence
+type createResponse struct {
+	SilenceID string    `json:"silenceID"`
+	Ticket    string    `json:"ticket"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	def, err := s.definition(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.busy <- struct{}{}
+	defer func() { <-s.busy }()
+	silence, err := s.backend.Create(r.Context(), def)
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, createResponse{SilenceID: silence.ID, Ticket: silence.TicketRef, EndsAt: silence.EndsAt})
+}
+
+// handlePurge lists the silences in the requested scope on GET, and deletes them on POST
+func (s *Server) handlePurge(w http.ResponseWriter, r *http.Request) {
+	scope := r.URL.Query().Get("scope")
+	if scope == "" {
+		scope = PurgeOrphans
+	}
+	if scope != PurgeOrphans && scope != PurgeAll {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown scope %q (must be %q or %q)", scope, PurgeOrphans, PurgeAll))
+		return
+	}
+
+	dryRun := r.Method == http.MethodGet
+	if !dryRun {
+		s.busy <- struct{}{}
+		defer func() { <-s.busy }()
+	}
+	result, err := s.backend.Purge(r.Context(), scope == PurgeAll, dryRun)
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	result.Scope = scope
+	if result.Silences == nil {
+		result.Silences = []PurgedSilence{}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// definition returns the silence definition of a create request
+func (s *Server) definition(req createRequest) (sync.SilenceDefinition, error) {
+	var def sync.SilenceDefinition
+	if req.Template != "" {
+		var err error
+		if def, err = s.templates.Definition(req.Template, req.Parameters); err != nil {
+			return def, err
+		}
+	}
+	matchers, err := sync.ParseMatchers(req.Matchers)
+	if err != nil {
+		return def, err
+	}
+	def.Matchers = append(def.Matchers, matchers...)
+	if len(def.Matchers) == 0 {
+		return def, errors.New("matchers or a template are required")
+	}
+	if req.Duration != "" {
+		if def.Duration, err = sync.ParseDuration(req.Duration); err != nil {
+			return def, fmt.Errorf("invalid duration %q: %w", req.Duration, err)
+		}
+	}
+	if req.Summary != "" {
+		def.Summary = req.Summary
+	}
+	if req.Comment != "" {
+		def.Comment = req.Comment
+	}
+	def.Ticket, def.CreatedBy = req.Ticket, req.CreatedBy
+	if def.CreatedBy == "" {
+		def.CreatedBy = "silence-manager (api)"
+	}
+	if def.Summary == "" && def.Ticket == "" {
+		return def, errors.New("a summary or a ticket is required")
+	}
+	return def, nil
+}
+
+// writeBackendError maps an error of the backend to a response status
+func writeBackendError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrRunInProgress), errors.Is(err, sync.ErrSilenceExists):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, alertmanager.ErrSilenceNotFound):
+		writeError(w, http.StatusNotFound, err)
+	default:
+		log.Printf("Warning: API request failed: %v", err)
+		writeError(w, http.StatusBadGateway, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Warning: failed to write response: %v", err)
+	}
+}
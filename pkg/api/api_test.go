@@ -0,0 +1,344 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/plan"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// fakeBackend records the operations performed through the API
+type fakeBackend struct {
+	syncs   int
+	links   map[string]string
+	block   chan struct{} // Holds Sync until closed, if set
+	started chan struct{}
+	created []sync.SilenceDefinition
+	purged  int
+}
+
+func (f *fakeBackend) Sync(ctx context.Context) (*SyncResponse, error) {
+	f.syncs++
+	if f.block != nil {
+		close(f.started)
+		<-f.block
+	}
+	return &SyncResponse{Counts: map[string]int{"silencesExtended": 2}}, nil
+}
+
+func (f *fakeBackend) List(ctx context.Context) ([]sync.PlannedAction, error) {
+	return []sync.PlannedAction{{SilenceID: "silence-1", TicketRef: "OPS-1", TicketStatus: "In Progress", Action: sync.ActionNone}}, nil
+}
+
+func (f *fakeBackend) Plan(ctx context.Context) (*plan.Plan, error) {
+	return plan.New(), nil
+}
+
+func (f *fakeBackend) Link(ctx context.Context, silenceID, ticketKey string) error {
+	if silenceID == "missing" {
+		return fmt.Errorf("failed to get silence %s: %w", silenceID, alertmanager.ErrSilenceNotFound)
+	}
+	f.links[silenceID] = ticketKey
+	return nil
+}
+
+func (f *fakeBackend) Unlink(ctx context.Context, silenceID string) error {
+	delete(f.links, silenceID)
+	return nil
+}
+
+func (f *fakeBackend) Create(ctx context.Context, def sync.SilenceDefinition) (*alertmanager.Silence, error) {
+	if len(f.created) > 0 {
+		return nil, fmt.Errorf("%w: silence silence-1 already matches", sync.ErrSilenceExists)
+	}
+	f.created = append(f.created, def)
+	return &alertmanager.Silence{ID: "silence-2", TicketRef: "OPS-2", EndsAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+func (f *fakeBackend) Purge(ctx context.Context, all, dryRun bool) (*PurgeResponse, error) {
+	result := &PurgeResponse{Silences: []PurgedSilence{{ID: "orphan-1", CreatedBy: "alice"}}}
+	if all {
+		result.Silences = append(result.Silences, PurgedSilence{ID: "silence-1", TicketRef: "OPS-1", CreatedBy: "alice"})
+	}
+	if !dryRun {
+		f.purged += len(result.Silences)
+		result.Deleted = len(result.Silences)
+	}
+	return result, nil
+}
+
+func newTestServer(backend *fakeBackend) *httptest.Server {
+	return httptest.NewServer(NewServer(backend, map[string]authz.Role{
+		"viewer-token":   authz.RoleViewer,
+		"operator-token": authz.RoleOperator,
+		"admin-token":    authz.RoleAdmin,
+	}).Handler())
+}
+
+func request(t *testing.T, server *httptest.Server, method, path, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServer_Authorization(t *testing.T) {
+	backend := &fakeBackend{links: map[string]string{}}
+	server := newTestServer(backend)
+	defer server.Close()
+
+	tests := []struct {
+		method, path, token string
+		want                int
+	}{
+		{http.MethodGet, "/api/v1/silences", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/silences", "wrong-token", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/silences", "viewer-token", http.StatusOK},
+		{http.MethodGet, "/api/v1/plan", "viewer-token", http.StatusOK},
+		{http.MethodPost, "/api/v1/sync", "viewer-token", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/sync", "operator-token", http.StatusOK},
+		{http.MethodDelete, "/api/v1/silences/silence-1/ticket", "viewer-token", http.StatusForbidden},
+		{http.MethodGet, "/healthz", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if resp := request(t, server, tt.method, tt.path, tt.token, ""); resp.StatusCode != tt.want {
+			t.Errorf("%s %s with %q: expected %d, got %d", tt.method, tt.path, tt.token, tt.want, resp.StatusCode)
+		}
+	}
+	if backend.syncs != 1 {
+		t.Errorf("Expected only the operator's sync to run, got %d runs", backend.syncs)
+	}
+}
+
+func TestServer_Purge(t *testing.T) {
+	backend := &fakeBackend{links: map[string]string{}}
+	server := newTestServer(backend)
+	defer server.Close()
+
+	tests := []struct {
+		method, path, token string
+		want                int
+	}{
+		{http.MethodGet, "/api/v1/purge", "viewer-token", http.StatusOK},
+		{http.MethodPost, "/api/v1/purge?confirm=true", "viewer-token", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/purge", "operator-token", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/purge?scope=everything&confirm=true", "operator-token", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if resp := request(t, server, tt.method, tt.path, tt.token, ""); resp.StatusCode != tt.want {
+			t.Errorf("%s %s with %q: expected %d, got %d", tt.method, tt.path, tt.token, tt.want, resp.StatusCode)
+		}
+	}
+	if backend.purged != 0 {
+		t.Fatalf("Expected nothing to be purged without authorization, got %d", backend.purged)
+	}
+
+	ctx := context.Background()
+	if _, err := NewClient(server.URL, "operator-token").Purge(ctx, PurgeOrphans, false, false); err == nil {
+		t.Error("Expected an unconfirmed purge by an operator to be refused")
+	}
+	result, err := NewClient(server.URL, "operator-token").Purge(ctx, PurgeOrphans, true, false)
+	if err != nil {
+		t.Fatalf("Purge() failed: %v", err)
+	}
+	if result.Scope != PurgeOrphans || result.Deleted != 1 || backend.purged != 1 {
+		t.Errorf("Expected the orphan to be purged, got %+v", result)
+	}
+	result, err = NewClient(server.URL+"/", "admin-token").Purge(ctx, PurgeAll, false, false)
+	if err != nil {
+		t.Fatalf("Purge() failed: %v", err)
+	}
+	if result.Scope != PurgeAll || result.Deleted != 2 {
+		t.Errorf("Expected an admin to purge all silences unconfirmed, got %+v", result)
+	}
+}
+
+func TestServer_ListAndSync(t *testing.T) {
+	server := newTestServer(&fakeBackend{links: map[string]string{}})
+	defer server.Close()
+
+	var silences []sync.PlannedAction
+	if err := json.NewDecoder(request(t, server, http.MethodGet, "/api/v1/silences", "viewer-token", "").Body).Decode(&silences); err != nil {
+		t.Fatalf("Failed to decode silences: %v", err)
+	}
+	if len(silences) != 1 || silences[0].TicketStatus != "In Progress" {
+		t.Errorf("Unexpected silences: %+v", silences)
+	}
+
+	var result SyncResponse
+	if err := json.NewDecoder(request(t, server, http.MethodPost, "/api/v1/sync", "operator-token", "").Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode sync result: %v", err)
+	}
+	if result.Counts["silencesExtended"] != 2 {
+		t.Errorf("Unexpected sync result: %+v", result)
+	}
+}
+
+func TestServer_LinkAndUnlink(t *testing.T) {
+	backend := &fakeBackend{links: map[string]string{}}
+	server := newTestServer(backend)
+	defer server.Close()
+
+	if resp := request(t, server, http.MethodPut, "/api/v1/silences/silence-1/ticket", "operator-token", `{"ticket": "OPS-1"}`); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204 for link, got %d", resp.StatusCode)
+	}
+	if backend.links["silence-1"] != "OPS-1" {
+		t.Errorf("Expected silence-1 linked to OPS-1, got %v", backend.links)
+	}
+	if resp := request(t, server, http.MethodPut, "/api/v1/silences/silence-1/ticket", "operator-token", `{}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a ticket, got %d", resp.StatusCode)
+	}
+	if resp := request(t, server, http.MethodPut, "/api/v1/silences/missing/ticket", "operator-token", `{"ticket": "OPS-1"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown silence, got %d", resp.StatusCode)
+	}
+
+	if resp := request(t, server, http.MethodDelete, "/api/v1/silences/silence-1/ticket", "operator-token", ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204 for unlink, got %d", resp.StatusCode)
+	}
+	if _, ok := backend.links["silence-1"]; ok {
+		t.Error("Expected silence-1 to be unlinked")
+	}
+}
+
+func TestServer_ConcurrentSync(t *testing.T) {
+	backend := &fakeBackend{links: map[string]string{}, block: make(chan struct{}), started: make(chan struct{})}
+	server := newTestServer(backend)
+	defer server.Close()
+
+	done := make(chan int)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/sync", nil)
+		req.Header.Set("Authorization", "Bearer operator-token")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+
+	select {
+	case <-backend.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("First sync did not start")
+	}
+	if resp := request(t, server, http.MethodPost, "/api/v1/sync", "operator-token", ""); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 while a sync is running, got %d", resp.StatusCode)
+	}
+	close(backend.block)
+	if status := <-done; status != http.StatusOK {
+		t.Errorf("Expected the first sync to succeed, got %d", status)
+	}
+}
+
+func TestServer_RunWaitsForSync(t *testing.T) {
+	backend := &fakeBackend{links: map[string]string{}, block: make(chan struct{}), started: make(chan struct{})}
+	srv := NewServer(backend, map[string]authz.Role{"operator-token": authz.RoleOperator})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- srv.serve(ctx, ln) }()
+
+	done := make(chan int)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, "http://"+ln.Addr().String()+"/api/v1/sync", nil)
+		req.Header.Set("Authorization", "Bearer operator-token")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	select {
+	case <-backend.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync did not start")
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+		t.Fatal("Expected the server to wait for the sync in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(backend.block)
+	if status := <-done; status != http.StatusOK {
+		t.Errorf("Expected the sync in flight to complete, got %d", status)
+	}
+	if err := <-stopped; err != nil {
+		t.Errorf("serve() failed: %v", err)
+	}
+}
+
+func TestServer_Create(t *testing.T) {
+	templates, err := sync.ParseSilenceTemplates([]byte(`
+- name: node-maintenance
+  matchers: ['instance=~"{{ .node }}(:.*)?"']
+  duration: 4h
+  summary: Maintenance of {{ .node }}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &fakeBackend{links: map[string]string{}}
+	srv := NewServer(backend, map[string]authz.Role{"operator-token": authz.RoleOperator})
+	srv.SetTemplates(templates)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+
+	for _, body := range []string{
+		`{"template": "reboot"}`,
+		`{"template": "node-maintenance"}`,
+		`{"matchers": ["alertname=DiskFull"]}`,
+		`{"summary": "Disk replacement"}`,
+	} {
+		if resp := request(t, server, http.MethodPost, "/api/v1/silences", "operator-token", body); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, resp.StatusCode)
+		}
+	}
+
+	resp := request(t, server, http.MethodPost, "/api/v1/silences", "operator-token",
+		`{"template": "node-maintenance", "parameters": {"node": "db-1"}, "matchers": ["severity=warning"], "duration": "2h"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	var created createResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil || created.SilenceID != "silence-2" || created.Ticket != "OPS-2" {
+		t.Errorf("Unexpected response: %+v (%v)", created, err)
+	}
+	def := backend.created[0]
+	if def.Summary != "Maintenance of db-1" || def.Duration != 2*time.Hour || len(def.Matchers) != 2 || def.CreatedBy != "silence-manager (api)" {
+		t.Errorf("Expected the filled in template with the request's overrides, got %+v", def)
+	}
+
+	if resp := request(t, server, http.MethodPost, "/api/v1/silences", "operator-token", `{"matchers": ["alertname=DiskFull"], "ticket": "OPS-1"}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for a duplicate silence, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// clientTimeout bounds a request to the API, which may delete many silences
+const clientTimeout = 5 * time.Minute
+
+// Client performs operations through the REST API of a silence-manager server, which
+// authorizes them by the role of its bearer token
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the API at baseURL, e.g. "http://silence-manager-api:8090"
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: clientTimeout},
+	}
+}
+
+// StatusError is an error response of the API
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s (status %d)", e.Message, e.StatusCode)
+}
+
+// Purge deletes the silences of scope, PurgeOrphans or PurgeAll. An operator token must
+// confirm the deletion. With dryRun the silences are only listed.
+func (c *Client) Purge(ctx context.Context, scope string, confirm, dryRun bool) (*PurgeResponse, error) {
+	query := url.Values{"scope": {scope}}
+	method := http.MethodPost
+	if dryRun {
+		method = http.MethodGet
+	} else if confirm {
+		query.Set("confirm", "true")
+	}
+
+	var result PurgeResponse
+	if err := c.do(ctx, method, "/api/v1/purge?"+query.Encode(), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// do sends a request without a body and decodes the response into result
+func (c *Client) do(ctx context.Context, method, path string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		if body.Error == "" {
+			body.Error = http.StatusText(resp.StatusCode)
+		}
+		return &StatusError{StatusCode: resp.StatusCode, Message: body.Error}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
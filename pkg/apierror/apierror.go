@@ -0,0 +1,138 @@
+// Package apierror classifies the errors of the Alertmanager and ticket system clients, so
+// that callers can decide whether a failure is worth retrying or needs an operator.
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+)
+
+// Category is the kind of a failed request
+type Category string
+
+const (
+	// Auth is a rejected credential or a missing permission (401, 403)
+	Auth Category = "auth"
+	// NotFound is a silence or ticket that does not exist (404)
+	NotFound Category = "not-found"
+	// RateLimited is a request throttled by the backend (429) or a used-up request budget
+	RateLimited Category = "rate-limited"
+	// Transient is a failure that is likely to go away: an unreachable backend, a
+	// timeout, or a 5xx response from a proxy or an overloaded server
+	Transient Category = "transient"
+	// Permanent is a request the backend rejected and will keep rejecting, such as an
+	// invalid silence, and any failure not covered above
+	Permanent Category = "permanent"
+)
+
+// Categories lists all categories in order of severity
+var Categories = []Category{Auth, NotFound, RateLimited, Transient, Permanent}
+
+// Error is an error of a known category
+type Error struct {
+	Category   Category
+	StatusCode int // HTTP status of the response, 0 if there was none
+	Err        error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// New returns an error of category with message text
+func New(category Category, text string) error {
+	return &Error{Category: category, Err: errors.New(text)}
+}
+
+// Errorf returns an error of category formatted like fmt.Errorf
+func Errorf(category Category, format string, args ...any) error {
+	return &Error{Category: category, Err: fmt.Errorf(format, args...)}
+}
+
+// FromStatus returns the error for an unexpected HTTP response, categorized by its status
+// code, with the response body in its message
+func FromStatus(statusCode int, body string) error {
+	return &Error{
+		Category:   StatusCategory(statusCode),
+		StatusCode: statusCode,
+		Err:        fmt.Errorf("unexpected status code %d: %s", statusCode, body),
+	}
+}
+
+// StatusCategory returns the category of an unexpected HTTP status code
+func StatusCategory(statusCode int) Category {
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return Auth
+	case statusCode == http.StatusNotFound:
+		return NotFound
+	case statusCode == http.StatusTooManyRequests:
+		return RateLimited
+	case statusCode == http.StatusRequestTimeout, statusCode >= 500:
+		return Transient
+	default:
+		return Permanent
+	}
+}
+
+// categorized is implemented by errors that know their category, such as the rate limit
+// errors of the ticket clients
+type categorized interface {
+	Category() Category
+}
+
+// CategoryOf returns the category of err. Timeouts, refused or reset connections and
+// failed DNS lookups are transient; errors of unknown origin, such as a rejected TLS
+// certificate, are permanent.
+func CategoryOf(err error) Category {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Category
+	}
+	var c categorized
+	if errors.As(err, &c) {
+		return c.Category()
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return Transient
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return Transient
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) && opErr.Op == "dial" || errors.As(err, &dnsErr) {
+		return Transient
+	}
+	// The connection was closed before a response arrived
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && errors.Is(urlErr.Err, io.EOF) {
+		return Transient
+	}
+	return Permanent
+}
+
+// Retryable reports whether retrying the request that failed with err may succeed
+func Retryable(err error) bool {
+	switch CategoryOf(err) {
+	case Transient, RateLimited:
+		return true
+	}
+	return false
+}
+
+// Count returns the number of errs in each category
+func Count(errs []error) map[Category]int {
+	counts := make(map[Category]int)
+	for _, err := range errs {
+		counts[CategoryOf(err)]++
+	}
+	return counts
+}
@@ -0,0 +1,59 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+type throttled struct{}
+
+func (throttled) Error() string      { return "throttled" }
+func (throttled) Category() Category { return RateLimited }
+
+func TestCategoryOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Category
+	}{
+		{"unauthorized", FromStatus(401, "bad token"), Auth},
+		{"forbidden", FromStatus(403, ""), Auth},
+		{"not found", FromStatus(404, ""), NotFound},
+		{"too many requests", FromStatus(429, ""), RateLimited},
+		{"bad gateway", FromStatus(502, ""), Transient},
+		{"bad request", FromStatus(400, "invalid matcher"), Permanent},
+		{"wrapped", fmt.Errorf("failed to get ticket: %w", New(NotFound, "ticket not found")), NotFound},
+		{"categorized", fmt.Errorf("search: %w", throttled{}), RateLimited},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, Transient},
+		{"tls alert", &net.OpError{Op: "remote error", Err: errors.New("tls: certificate required")}, Permanent},
+		{"deadline", fmt.Errorf("list silences: %w", context.DeadlineExceeded), Transient},
+		{"unknown", errors.New("failed to decode response"), Permanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategoryOf(tt.err); got != tt.want {
+				t.Errorf("CategoryOf(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromStatus_Message(t *testing.T) {
+	err := FromStatus(500, "internal error")
+	if err.Error() != "unexpected status code 500: internal error" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+	if !Retryable(err) || Retryable(FromStatus(401, "")) {
+		t.Error("Expected 5xx responses to be retryable and 401 not")
+	}
+}
+
+func TestCount(t *testing.T) {
+	counts := Count([]error{FromStatus(401, ""), FromStatus(503, ""), FromStatus(504, ""), errors.New("other")})
+	if counts[Auth] != 1 || counts[Transient] != 2 || counts[Permanent] != 1 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+}
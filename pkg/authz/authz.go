@@ -0,0 +1,100 @@
+package authz
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Role is the privilege level of a caller of the CLI or API
+type Role string
+
+const (
+	// RoleViewer may only read silences, tickets and reports
+	RoleViewer Role = "viewer"
+	// RoleOperator may change individual silences; bulk destructive operations need confirmation
+	RoleOperator Role = "operator"
+	// RoleAdmin may perform any operation, including bulk destructive ones
+	RoleAdmin Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Operation classifies what a request does
+type Operation string
+
+const (
+	// OpRead only reads state
+	OpRead Operation = "read"
+	// OpWrite changes a single silence or ticket
+	OpWrite Operation = "write"
+	// OpBulkDestructive deletes or expires many silences at once (e.g. purging orphans)
+	OpBulkDestructive Operation = "bulk-destructive"
+)
+
+var (
+	// ErrForbidden is returned when the role may not perform the operation at all
+	ErrForbidden = errors.New("forbidden")
+	// ErrConfirmationRequired is returned when the operation needs explicit confirmation
+	ErrConfirmationRequired = errors.New("confirmation required")
+)
+
+// ParseRole parses a role name
+func ParseRole(value string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := roleLevels[role]; !ok {
+		return "", fmt.Errorf("invalid role %q (must be 'viewer', 'operator' or 'admin')", value)
+	}
+	return role, nil
+}
+
+// Authorize checks whether role may perform op. Bulk destructive operations require
+// the admin role, or the operator role together with an explicit confirmation.
+func Authorize(role Role, op Operation, confirmed bool) error {
+	level, ok := roleLevels[role]
+	if !ok {
+		return fmt.Errorf("%w: unknown role %q", ErrForbidden, role)
+	}
+
+	switch op {
+	case OpRead:
+		return nil
+	case OpWrite:
+		if level < roleLevels[RoleOperator] {
+			return fmt.Errorf("%w: role %s may not perform %s operations", ErrForbidden, role, op)
+		}
+		return nil
+	case OpBulkDestructive:
+		if level < roleLevels[RoleOperator] {
+			return fmt.Errorf("%w: role %s may not perform %s operations", ErrForbidden, role, op)
+		}
+		if level < roleLevels[RoleAdmin] && !confirmed {
+			return fmt.Errorf("%w: %s operations by role %s must be explicitly confirmed", ErrConfirmationRequired, op, role)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrForbidden, op)
+	}
+}
+
+// BearerRole returns the role of the bearer token in an Authorization header value, such
+// as "Bearer s3cr3t", looked up in tokens
+func BearerRole(authorization string, tokens map[string]Role) (Role, bool) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	// Compare against every token so that the time taken does not reveal a match
+	var role Role
+	for candidate, candidateRole := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+			role = candidateRole
+		}
+	}
+	return role, role != ""
+}
@@ -0,0 +1,60 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      Role
+		op        Operation
+		confirmed bool
+		wantErr   error
+	}{
+		{name: "viewer read", role: RoleViewer, op: OpRead},
+		{name: "viewer write", role: RoleViewer, op: OpWrite, wantErr: ErrForbidden},
+		{name: "viewer bulk confirmed", role: RoleViewer, op: OpBulkDestructive, confirmed: true, wantErr: ErrForbidden},
+		{name: "operator write", role: RoleOperator, op: OpWrite},
+		{name: "operator bulk unconfirmed", role: RoleOperator, op: OpBulkDestructive, wantErr: ErrConfirmationRequired},
+		{name: "operator bulk confirmed", role: RoleOperator, op: OpBulkDestructive, confirmed: true},
+		{name: "admin bulk", role: RoleAdmin, op: OpBulkDestructive},
+		{name: "unknown role", role: Role("root"), op: OpRead, wantErr: ErrForbidden},
+		{name: "unknown operation", role: RoleAdmin, op: Operation("nuke"), wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.role, tt.op, tt.confirmed)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	if role, err := ParseRole(" Admin "); err != nil || role != RoleAdmin {
+		t.Errorf("Expected admin role, got %q (err: %v)", role, err)
+	}
+	if _, err := ParseRole("superuser"); err == nil {
+		t.Error("Expected error for invalid role")
+	}
+}
+
+func TestBearerRole(t *testing.T) {
+	tokens := map[string]Role{"op-token": RoleOperator, "view-token": RoleViewer}
+
+	if role, ok := BearerRole("Bearer op-token", tokens); !ok || role != RoleOperator {
+		t.Errorf("Expected operator role, got %q (ok: %v)", role, ok)
+	}
+	for _, header := range []string{"", "Bearer ", "Bearer other", "Basic op-token", "op-token"} {
+		if role, ok := BearerRole(header, tokens); ok {
+			t.Errorf("Expected no role for %q, got %q", header, role)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// Object names of the latest snapshot and the one it replaced
+const (
+	LatestObject   = "silences.json"
+	PreviousObject = "silences.previous.json"
+)
+
+// ErrNotFound is returned by Store.Get for objects that do not exist
+var ErrNotFound = errors.New("backup not found")
+
+// Store is the interface that all backup store implementations must satisfy
+type Store interface {
+	// Put writes an object, replacing any previous version
+	Put(name string, data []byte) error
+
+	// Get reads an object, returning ErrNotFound if it does not exist
+	Get(name string) ([]byte, error)
+}
+
+// Snapshot is a copy of the managed silences and their ticket links at a point in time
+type Snapshot struct {
+	Taken    time.Time `json:"taken"`
+	Silences []Silence `json:"silences"`
+}
+
+// Silence is a backed up silence
+type Silence struct {
+	ID        string    `json:"id"`
+	TicketRef string    `json:"ticketRef"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Matchers  []Matcher `json:"matchers"`
+}
+
+// Matcher is a backed up silence matcher
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// NewSnapshot takes a snapshot of the silences linked to a ticket
+func NewSnapshot(silences []*alertmanager.Silence, taken time.Time) *Snapshot {
+	snapshot := &Snapshot{Taken: taken, Silences: make([]Silence, 0, len(silences))}
+	for _, s := range silences {
+		if s.TicketRef == "" {
+			continue
+		}
+		matchers := make([]Matcher, len(s.Matchers))
+		for i, m := range s.Matchers {
+			matchers[i] = Matcher{Name: m.Name, Value: m.Value, IsRegex: m.IsRegex, IsEqual: m.IsEqual}
+		}
+		snapshot.Silences = append(snapshot.Silences, Silence{
+			ID:        s.ID,
+			TicketRef: s.TicketRef,
+			CreatedBy: s.CreatedBy,
+			Comment:   s.Comment,
+			StartsAt:  s.StartsAt,
+			EndsAt:    s.EndsAt,
+			Matchers:  matchers,
+		})
+	}
+	return snapshot
+}
+
+// AlertmanagerSilences returns the backed up silences
+func (s *Snapshot) AlertmanagerSilences() []*alertmanager.Silence {
+	silences := make([]*alertmanager.Silence, 0, len(s.Silences))
+	for _, b := range s.Silences {
+		matchers := make([]alertmanager.Matcher, len(b.Matchers))
+		for i, m := range b.Matchers {
+			matchers[i] = alertmanager.Matcher{Name: m.Name, Value: m.Value, IsRegex: m.IsRegex, IsEqual: m.IsEqual}
+		}
+		silences = append(silences, &alertmanager.Silence{
+			ID:        b.ID,
+			TicketRef: b.TicketRef,
+			CreatedBy: b.CreatedBy,
+			Comment:   b.Comment,
+			StartsAt:  b.StartsAt,
+			EndsAt:    b.EndsAt,
+			Matchers:  matchers,
+		})
+	}
+	return silences
+}
+
+// Save stores the snapshot as the latest one, keeping the latest one so far as the
+// previous snapshot. An empty snapshot never replaces one with silences: an
+// Alertmanager that lost its state would otherwise overwrite the backup meant to
+// restore it.
+func Save(store Store, snapshot *Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	latest, err := store.Get(LatestObject)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return fmt.Errorf("failed to read latest backup: %w", err)
+	default:
+		var previous Snapshot
+		if err := json.Unmarshal(latest, &previous); err == nil && len(previous.Silences) > 0 && len(snapshot.Silences) == 0 {
+			return fmt.Errorf("refusing to replace the backup of %d silences taken at %s with an empty snapshot",
+				len(previous.Silences), previous.Taken.Format(time.RFC3339))
+		}
+		if err := store.Put(PreviousObject, latest); err != nil {
+			return fmt.Errorf("failed to keep previous backup: %w", err)
+		}
+	}
+
+	if err := store.Put(LatestObject, data); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// Object is a single object of a store, such as the state document exported between
+// runs. It implements state.Remote.
+type Object struct {
+	store Store
+	name  string
+}
+
+// NewObject returns the object called name in store
+func NewObject(store Store, name string) *Object {
+	return &Object{store: store, name: name}
+}
+
+// Get returns the content of the object, or nil if it does not exist
+func (o *Object) Get() ([]byte, error) {
+	data, err := o.store.Get(o.name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Put replaces the content of the object
+func (o *Object) Put(data []byte) error {
+	return o.store.Put(o.name, data)
+}
+
+// Load reads a snapshot, LatestObject or PreviousObject, from the store
+func Load(store Store, name string) (*Snapshot, error) {
+	data, err := store.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup %s: %w", name, err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode backup %s: %w", name, err)
+	}
+	return &snapshot, nil
+}
+
+// defaultTimeout is the HTTP timeout used by the object storage clients
+const defaultTimeout = 30 * time.Second
+
+// do sends a request and returns the response body if the status is 2xx, or
+// ErrNotFound for a missing object
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
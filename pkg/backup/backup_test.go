@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+func testSilences() []*alertmanager.Silence {
+	now := time.Now().Truncate(time.Second)
+	return []*alertmanager.Silence{
+		{
+			ID:        "silence-1",
+			TicketRef: "PROJ-1",
+			CreatedBy: "alice",
+			Comment:   "Disk replacement",
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(24 * time.Hour),
+			Matchers:  []alertmanager.Matcher{{Name: "instance", Value: "db-.*", IsRegex: true, IsEqual: true}},
+		},
+		{ID: "orphan", CreatedBy: "bob", Comment: "No ticket"},
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	snapshot := NewSnapshot(testSilences(), time.Now())
+	if len(snapshot.Silences) != 1 || snapshot.Silences[0].ID != "silence-1" {
+		t.Fatalf("Expected only the silence linked to a ticket, got %+v", snapshot.Silences)
+	}
+
+	restored := snapshot.AlertmanagerSilences()
+	if len(restored) != 1 || restored[0].TicketRef != "PROJ-1" || restored[0].Matchers[0].String() != "instance=~db-.*" {
+		t.Errorf("Unexpected silences from snapshot: %+v", restored[0])
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+
+	if _, err := Load(store, LatestObject); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound before the first backup, got %v", err)
+	}
+
+	first := NewSnapshot(testSilences(), time.Now().Add(-time.Hour))
+	if err := Save(store, first); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	second := NewSnapshot(testSilences()[:1], time.Now())
+	second.Silences[0].ID = "silence-2"
+	if err := Save(store, second); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	latest, err := Load(store, LatestObject)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if latest.Silences[0].ID != "silence-2" {
+		t.Errorf("Expected the latest snapshot, got %+v", latest.Silences)
+	}
+	previous, err := Load(store, PreviousObject)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if previous.Silences[0].ID != "silence-1" {
+		t.Errorf("Expected the replaced snapshot to be kept, got %+v", previous.Silences)
+	}
+}
+
+func TestObject(t *testing.T) {
+	object := NewObject(NewFileStore(t.TempDir()), "state.json")
+
+	data, err := object.Get()
+	if err != nil || data != nil {
+		t.Fatalf("Expected no content for a missing object, got %q, %v", data, err)
+	}
+	if err := object.Put([]byte(`{"silences":{}}`)); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	data, err = object.Get()
+	if err != nil || string(data) != `{"silences":{}}` {
+		t.Errorf("Unexpected content %q, %v", data, err)
+	}
+}
+
+func TestSave_RefusesEmptySnapshot(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	if err := Save(store, NewSnapshot(testSilences(), time.Now())); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	err := Save(store, NewSnapshot(nil, time.Now()))
+	if err == nil || !strings.Contains(err.Error(), "refusing to replace the backup of 1 silences") {
+		t.Fatalf("Expected an empty snapshot to be refused, got %v", err)
+	}
+	latest, err := Load(store, LatestObject)
+	if err != nil || len(latest.Silences) != 1 {
+		t.Errorf("Expected the backup to be kept, got %+v, %v", latest, err)
+	}
+}
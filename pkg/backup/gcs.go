@@ -0,0 +1,132 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default Google Cloud Storage API and metadata server token URLs
+const (
+	DefaultGCSEndpoint = "https://storage.googleapis.com"
+	metadataTokenURL   = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCSConfig holds configuration for a Google Cloud Storage backup bucket
+type GCSConfig struct {
+	Bucket   string
+	Prefix   string // Prepended to object names, e.g. "silence-manager/"
+	Endpoint string // Overrides DefaultGCSEndpoint
+	// Token is an OAuth 2.0 access token. Without one, tokens of the workload's service
+	// account are fetched from the metadata server, as on GKE with Workload Identity.
+	Token string
+}
+
+// GCSStore keeps backups as objects in a Google Cloud Storage bucket, using the JSON API
+type GCSStore struct {
+	config     GCSConfig
+	httpClient *http.Client
+	tokenURL   string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCSStore creates a new Google Cloud Storage backup store
+func NewGCSStore(config GCSConfig) *GCSStore {
+	if config.Endpoint == "" {
+		config.Endpoint = DefaultGCSEndpoint
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &GCSStore{
+		config:     config,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		tokenURL:   metadataTokenURL,
+	}
+}
+
+// Put uploads an object
+func (g *GCSStore) Put(name string, data []byte) error {
+	query := url.Values{}
+	query.Set("uploadType", "media")
+	query.Set("name", g.config.Prefix+name)
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", g.config.Endpoint, url.PathEscape(g.config.Bucket), query.Encode()),
+		bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := g.authorize(req); err != nil {
+		return err
+	}
+
+	_, err = do(g.httpClient, req)
+	return err
+}
+
+// Get downloads an object
+func (g *GCSStore) Get(name string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet,
+		fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", g.config.Endpoint, url.PathEscape(g.config.Bucket), url.PathEscape(g.config.Prefix+name)),
+		nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := g.authorize(req); err != nil {
+		return nil, err
+	}
+	return do(g.httpClient, req)
+}
+
+// authorize adds the bearer token to the request
+func (g *GCSStore) authorize(req *http.Request) error {
+	token := g.config.Token
+	if token == "" {
+		var err error
+		if token, err = g.metadataToken(); err != nil {
+			return err
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+type metadataTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// metadataToken returns an access token of the workload's service account, reusing it
+// until shortly before it expires
+func (g *GCSStore) metadataToken() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && time.Now().Before(g.tokenExpiry) {
+		return g.token, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, g.tokenURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	body, err := do(g.httpClient, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token from the metadata server: %w", err)
+	}
+
+	var resp metadataTokenResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to decode access token: %w", err)
+	}
+	g.token = resp.AccessToken
+	g.tokenExpiry = time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - time.Minute)
+	return g.token, nil
+}
@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGCSStore_MetadataToken(t *testing.T) {
+	tokenRequests := 0
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			tokenRequests++
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				t.Errorf("Expected the metadata flavor header")
+			}
+			io.WriteString(w, `{"access_token":"ya29.token","expires_in":3600,"token_type":"Bearer"}`)
+			return
+		case r.Header.Get("Authorization") != "Bearer ya29.token":
+			t.Errorf("Expected the metadata token, got %q", r.Header.Get("Authorization"))
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/backups/o":
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Query().Get("name")] = string(body)
+		case r.Method == http.MethodGet && r.URL.Query().Get("alt") == "media":
+			body, ok := objects[r.URL.Path[len("/storage/v1/b/backups/o/"):]]
+			if !ok {
+				http.Error(w, "Not Found", http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, body)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGCSStore(GCSConfig{Bucket: "backups", Prefix: "silence-manager/", Endpoint: server.URL})
+	g.tokenURL = server.URL + "/token"
+
+	if _, err := g.Get(LatestObject); err != ErrNotFound {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if err := g.Put(LatestObject, []byte(`{"silences":[]}`)); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	data, err := g.Get(LatestObject)
+	if err != nil || string(data) != `{"silences":[]}` {
+		t.Errorf("Expected the object back, got %q, %v", data, err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("Expected the token to be reused, got %d token requests", tokenRequests)
+	}
+}
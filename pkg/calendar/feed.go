@@ -0,0 +1,45 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Feed reads events from an iCalendar feed URL, such as the secret iCal address of a
+// Google Calendar or an Outlook/Exchange published calendar
+type Feed struct {
+	url        string
+	lookahead  time.Duration
+	httpClient *http.Client
+}
+
+// NewFeed creates a new iCalendar feed source. Recurring events are expanded into their
+// occurrences up to lookahead from now.
+func NewFeed(url string, lookahead time.Duration) *Feed {
+	return &Feed{
+		url:       url,
+		lookahead: lookahead,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Events downloads and parses the feed
+func (f *Feed) Events() ([]Event, error) {
+	resp, err := f.httpClient.Get(f.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calendar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	now := time.Now()
+	return Parse(resp.Body, now, now.Add(f.lookahead))
+}
@@ -0,0 +1,489 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a planned maintenance window read from a calendar feed
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Cancelled   bool
+	// RecurrenceID is the original start of an occurrence of a recurring event
+	RecurrenceID time.Time
+}
+
+// Key identifies the event, or the occurrence of a recurring event, across feed reads
+func (e Event) Key() string {
+	if e.RecurrenceID.IsZero() {
+		return e.UID
+	}
+	return e.UID + "/" + e.RecurrenceID.UTC().Format("20060102T150405Z")
+}
+
+// overlaps reports whether the event overlaps the window from from to until
+func (e Event) overlaps(from, until time.Time) bool {
+	return e.End.After(from) && !e.Start.After(until)
+}
+
+// Source provides maintenance events
+type Source interface {
+	// Events returns the events currently published by the source
+	Events() ([]Event, error)
+}
+
+// component is a VEVENT as read, before its recurrences are expanded
+type component struct {
+	Event
+	rrule   string
+	rdates  []time.Time
+	exdates []exdate
+	err     error // The first invalid property, which makes the event skipped
+}
+
+// exdate is an excluded occurrence; a DATE value excludes the occurrences of that day
+type exdate struct {
+	t    time.Time
+	date bool
+}
+
+// Parse reads the VEVENT components of an iCalendar (RFC 5545) document. Recurring
+// events (RRULE and RDATE, less EXDATE) are expanded into the occurrences overlapping
+// the window from from to until, keyed by their RECURRENCE-ID, and modified occurrences
+// replace the ones they override. Single events are returned whatever their time. Events
+// that cannot be read, such as those without a UID or with an invalid date, are logged
+// and skipped so that they do not hide the rest of the feed.
+func Parse(r io.Reader, from, until time.Time) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var masters, overrides []*component
+	var current *component
+	for i, line := range lines {
+		name, params, value, ok := splitProperty(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &component{}
+		case name == "END" && value == "VEVENT":
+			if current == nil {
+				log.Printf("Warning: skipping line %d of calendar: END:VEVENT without BEGIN:VEVENT", i+1)
+				continue
+			}
+			switch {
+			case current.UID == "":
+				log.Printf("Warning: skipping calendar event ending on line %d: event without UID", i+1)
+			case current.err != nil:
+				log.Printf("Warning: skipping calendar event %s: %v", current.UID, current.err)
+			case current.Start.IsZero():
+				log.Printf("Warning: skipping calendar event %s: missing DTSTART", current.UID)
+			default:
+				if current.End.IsZero() {
+					current.End = current.Start
+				}
+				if current.RecurrenceID.IsZero() {
+					masters = append(masters, current)
+				} else {
+					overrides = append(overrides, current)
+				}
+			}
+			current = nil
+		case current == nil || current.err != nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescapeText(value)
+		case name == "DESCRIPTION":
+			current.Description = unescapeText(value)
+		case name == "STATUS":
+			current.Cancelled = strings.EqualFold(value, "CANCELLED")
+		case name == "RRULE":
+			current.rrule = value
+		case name == "DTSTART" || name == "DTEND" || name == "RECURRENCE-ID" || name == "RDATE" || name == "EXDATE":
+			if params["VALUE"] == "PERIOD" {
+				current.err = fmt.Errorf("unsupported %s period", name)
+				continue
+			}
+			for _, v := range strings.Split(value, ",") {
+				t, err := parseDateTime(v, params)
+				if err != nil {
+					current.err = fmt.Errorf("invalid %s: %w", name, err)
+					break
+				}
+				switch name {
+				case "DTSTART":
+					current.Start = t
+				case "DTEND":
+					current.End = t
+				case "RECURRENCE-ID":
+					current.RecurrenceID = t
+				case "RDATE":
+					current.rdates = append(current.rdates, t)
+				case "EXDATE":
+					current.exdates = append(current.exdates, exdate{t: t, date: len(v) == len("20060102")})
+				}
+			}
+		}
+	}
+
+	var events []Event
+	index := make(map[string]int)
+	for _, master := range masters {
+		occurrences, err := master.occurrences(from, until)
+		if err != nil {
+			log.Printf("Warning: skipping calendar event %s: %v", master.UID, err)
+			continue
+		}
+		for _, occurrence := range occurrences {
+			index[occurrence.Key()] = len(events)
+			events = append(events, occurrence)
+		}
+	}
+	for _, override := range overrides {
+		if i, ok := index[override.Key()]; ok {
+			events[i] = override.Event
+		} else if override.overlaps(from, until) {
+			events = append(events, override.Event)
+		}
+	}
+	return events, nil
+}
+
+// occurrences expands a recurring event into its occurrences overlapping the window from
+// from to until. A single event is returned as is.
+func (c *component) occurrences(from, until time.Time) ([]Event, error) {
+	if c.rrule == "" && len(c.rdates) == 0 {
+		return []Event{c.Event}, nil
+	}
+
+	starts := append([]time.Time{c.Start}, c.rdates...)
+	if c.rrule != "" {
+		rule, err := parseRecurrenceRule(c.rrule)
+		if err != nil {
+			return nil, err
+		}
+		starts = append(starts, rule.starts(c.Start, until)...)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	duration := c.End.Sub(c.Start)
+	var events []Event
+	for i, start := range starts {
+		if (i > 0 && start.Equal(starts[i-1])) || c.excluded(start) {
+			continue
+		}
+		occurrence := c.Event
+		occurrence.Start, occurrence.End, occurrence.RecurrenceID = start, start.Add(duration), start
+		if occurrence.overlaps(from, until) {
+			events = append(events, occurrence)
+		}
+	}
+	return events, nil
+}
+
+// excluded reports whether an EXDATE removes the occurrence starting at start
+func (c *component) excluded(start time.Time) bool {
+	for _, ex := range c.exdates {
+		if ex.t.Equal(start) {
+			return true
+		}
+		if ex.date {
+			y, m, d := start.In(ex.t.Location()).Date()
+			if ey, em, ed := ex.t.Date(); y == ey && m == em && d == ed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maxPeriods bounds the expansion of a recurrence rule without an end
+const maxPeriods = 100000
+
+// weekdays maps the BYDAY day names to weekdays
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// byDay is a BYDAY value such as "TU" or "-1FR"; Ordinal is 0 for every such day
+type byDay struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// recurrenceRule is an RRULE. The DAILY, WEEKLY, MONTHLY and YEARLY frequencies are
+// supported with INTERVAL, COUNT, UNTIL, BYDAY (not with YEARLY) and BYMONTHDAY (MONTHLY).
+type recurrenceRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []byDay
+	ByMonthDay []int
+}
+
+// parseRecurrenceRule parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH"
+func parseRecurrenceRule(value string) (recurrenceRule, error) {
+	rule := recurrenceRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, v, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(v)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(v)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(v)
+		case "UNTIL":
+			rule.Until, err = parseDateTime(v, nil)
+		case "BYDAY":
+			for _, day := range strings.Split(v, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day[max(len(day)-2, 0):])]
+				if !ok {
+					err = fmt.Errorf("unknown day %q", day)
+					break
+				}
+				var ordinal int
+				if n := day[:len(day)-2]; n != "" {
+					if ordinal, err = strconv.Atoi(n); err != nil {
+						break
+					}
+				}
+				rule.ByDay = append(rule.ByDay, byDay{Ordinal: ordinal, Weekday: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(v, ",") {
+				var d int
+				if d, err = strconv.Atoi(day); err != nil {
+					break
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, d)
+			}
+		case "WKST":
+			// Weeks start on Monday; other starts only matter for rare rules
+		default:
+			return rule, fmt.Errorf("unsupported RRULE part %s", key)
+		}
+		if err != nil {
+			return rule, fmt.Errorf("invalid RRULE %s: %w", key, err)
+		}
+	}
+
+	switch {
+	case rule.Freq != "DAILY" && rule.Freq != "WEEKLY" && rule.Freq != "MONTHLY" && rule.Freq != "YEARLY":
+		return rule, fmt.Errorf("unsupported RRULE frequency %q", rule.Freq)
+	case len(rule.ByMonthDay) > 0 && rule.Freq != "MONTHLY":
+		return rule, fmt.Errorf("unsupported RRULE BYMONTHDAY with FREQ=%s", rule.Freq)
+	case len(rule.ByDay) > 0 && rule.Freq == "YEARLY":
+		return rule, fmt.Errorf("unsupported RRULE BYDAY with FREQ=YEARLY")
+	}
+	for _, day := range rule.ByDay {
+		if day.Ordinal != 0 && rule.Freq != "MONTHLY" {
+			return rule, fmt.Errorf("unsupported RRULE BYDAY ordinal with FREQ=%s", rule.Freq)
+		}
+	}
+	return rule, nil
+}
+
+// starts returns the starts of the occurrences of the rule from dtstart up to until,
+// bounded by COUNT and UNTIL. Occurrences keep the wall clock time of dtstart across
+// daylight saving changes.
+func (r recurrenceRule) starts(dtstart, until time.Time) []time.Time {
+	y, m, d := dtstart.Date()
+	hour, minute, second := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, second, 0, loc)
+	}
+
+	var starts []time.Time
+	count := 0
+	for k := 0; k < maxPeriods; k++ {
+		var period time.Time
+		var candidates []time.Time
+		switch r.Freq {
+		case "DAILY":
+			period = at(y, m, d+k*r.Interval)
+			if r.matchesDay(period.Weekday()) {
+				candidates = []time.Time{period}
+			}
+		case "WEEKLY":
+			// Weeks start on Monday
+			period = at(y, m, d-(int(dtstart.Weekday())+6)%7+7*k*r.Interval)
+			if len(r.ByDay) == 0 {
+				candidates = []time.Time{at(y, m, d+7*k*r.Interval)}
+			}
+			for _, day := range r.ByDay {
+				candidates = append(candidates, period.AddDate(0, 0, (int(day.Weekday)+6)%7))
+			}
+		case "MONTHLY":
+			period = at(y, m+time.Month(k*r.Interval), 1)
+			candidates = r.monthDays(period, d, at)
+		case "YEARLY":
+			period = at(y+k*r.Interval, 1, 1)
+			if date := at(y+k*r.Interval, m, d); date.Day() == d {
+				candidates = []time.Time{date}
+			}
+		}
+		if period.After(until) {
+			break
+		}
+
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		for _, start := range candidates {
+			if start.Before(dtstart) {
+				continue
+			}
+			count++
+			if (r.Count > 0 && count > r.Count) || (!r.Until.IsZero() && start.After(r.Until)) {
+				return starts
+			}
+			if !start.After(until) {
+				starts = append(starts, start)
+			}
+		}
+	}
+	return starts
+}
+
+// matchesDay reports whether BYDAY, if any, includes weekday
+func (r recurrenceRule) matchesDay(weekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// monthDays returns the occurrences in the month starting at first: the BYMONTHDAY days,
+// the BYDAY weekdays, or else day, the day of the month of DTSTART. Days the month lacks
+// are skipped.
+func (r recurrenceRule) monthDays(first time.Time, day int, at func(int, time.Month, int) time.Time) []time.Time {
+	y, m := first.Year(), first.Month()
+	daysInMonth := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	var days []int
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d += daysInMonth + 1
+			}
+			days = append(days, d)
+		}
+	case len(r.ByDay) > 0:
+		for _, byDay := range r.ByDay {
+			firstDay := 1 + (int(byDay.Weekday)-int(first.Weekday())+7)%7
+			var matching []int
+			for d := firstDay; d <= daysInMonth; d += 7 {
+				matching = append(matching, d)
+			}
+			switch {
+			case byDay.Ordinal == 0:
+				days = append(days, matching...)
+			case byDay.Ordinal > 0 && byDay.Ordinal <= len(matching):
+				days = append(days, matching[byDay.Ordinal-1])
+			case byDay.Ordinal < 0 && -byDay.Ordinal <= len(matching):
+				days = append(days, matching[len(matching)+byDay.Ordinal])
+			}
+		}
+	default:
+		days = []int{day}
+	}
+
+	var dates []time.Time
+	for _, d := range days {
+		if d >= 1 && d <= daysInMonth {
+			dates = append(dates, at(y, m, d))
+		}
+	}
+	return dates
+}
+
+// unfold joins folded content lines (continuations start with a space or tab)
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitProperty splits a content line such as "DTSTART;TZID=Europe/Dublin:20240101T090000"
+// into its name, parameters and value
+func splitProperty(line string) (string, map[string]string, string, bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", nil, "", false
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+// parseDateTime parses DATE and DATE-TIME values in UTC, floating or TZID-qualified form
+func parseDateTime(value string, params map[string]string) (time.Time, error) {
+	loc := time.UTC
+	if tzid, ok := params["TZID"]; ok {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown timezone %q", tzid)
+		}
+		loc = l
+	}
+
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		return time.ParseInLocation("20060102", value, loc)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
+
+// unescapeText reverses iCalendar TEXT escaping
+func unescapeText(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,164 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//Maintenance//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:db-upgrade@example.com\r\n" +
+	"SUMMARY:Database upgrade\r\n" +
+	"DESCRIPTION:Upgrade primary\\, then replicas.\\nmatchers: service=postgres \r\n" +
+	" severity=~\"warning|critical\"\r\n" +
+	"DTSTART:20240312T220000Z\r\n" +
+	"DTEND:20240313T020000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:network@example.com\r\n" +
+	"SUMMARY:Network maintenance\r\n" +
+	"DTSTART;TZID=Europe/Dublin:20240701T090000\r\n" +
+	"DTEND;TZID=Europe/Dublin:20240701T110000\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:freeze@example.com\r\n" +
+	"SUMMARY:Change freeze\r\n" +
+	"DTSTART;VALUE=DATE:20241224\r\n" +
+	"DTEND;VALUE=DATE:20241227\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// window covers the events of testCalendar
+var windowFrom, windowUntil = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(testCalendar), windowFrom, windowUntil)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
+	}
+
+	db := events[0]
+	if db.UID != "db-upgrade@example.com" || db.Summary != "Database upgrade" {
+		t.Errorf("Unexpected event: %+v", db)
+	}
+	expectedDescription := "Upgrade primary, then replicas.\nmatchers: service=postgres severity=~\"warning|critical\""
+	if db.Description != expectedDescription {
+		t.Errorf("Expected description %q, got %q", expectedDescription, db.Description)
+	}
+	if !db.Start.Equal(time.Date(2024, 3, 12, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected start: %v", db.Start)
+	}
+	if !db.End.Equal(time.Date(2024, 3, 13, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected end: %v", db.End)
+	}
+
+	network := events[1]
+	if !network.Cancelled {
+		t.Error("Expected network event to be cancelled")
+	}
+	if !network.Start.Equal(time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected TZID start to be converted, got %v", network.Start.UTC())
+	}
+
+	freeze := events[2]
+	if freeze.End.Sub(freeze.Start) != 72*time.Hour {
+		t.Errorf("Expected all-day event to last 3 days, got %v", freeze.End.Sub(freeze.Start))
+	}
+}
+
+func TestParse_SkipsBadEvents(t *testing.T) {
+	feed := "BEGIN:VEVENT\nUID:x\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nSUMMARY:No UID\nDTSTART:20240312T220000Z\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nUID:bad-date\nDTSTART:2024-03-12\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nUID:bad-rule\nDTSTART:20240312T220000Z\nRRULE:FREQ=HOURLY\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nUID:good\nDTSTART:20240312T220000Z\nDTEND:20240313T020000Z\nEND:VEVENT\n"
+	events, err := Parse(strings.NewReader(feed), windowFrom, windowUntil)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if len(events) != 1 || events[0].UID != "good" {
+		t.Errorf("Expected only the valid event, got %+v", events)
+	}
+}
+
+func TestParse_Recurrences(t *testing.T) {
+	feed := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:patching@example.com\r\n" +
+		"SUMMARY:Weekly patching\r\n" +
+		"DTSTART;TZID=Europe/Dublin:20240305T220000\r\n" +
+		"DTEND;TZID=Europe/Dublin:20240305T230000\r\n" +
+		"RRULE:FREQ=WEEKLY;BYDAY=TU\r\n" +
+		"EXDATE;TZID=Europe/Dublin:20240319T220000\r\n" +
+		"RDATE;TZID=Europe/Dublin:20240321T220000\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:patching@example.com\r\n" +
+		"SUMMARY:Weekly patching (moved)\r\n" +
+		"RECURRENCE-ID;TZID=Europe/Dublin:20240402T220000\r\n" +
+		"DTSTART;TZID=Europe/Dublin:20240403T220000\r\n" +
+		"DTEND;TZID=Europe/Dublin:20240403T230000\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:backup@example.com\r\n" +
+		"DTSTART:20240131T010000Z\r\n" +
+		"DTEND:20240131T020000Z\r\n" +
+		"RRULE:FREQ=MONTHLY;COUNT=3\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	// Only the occurrences overlapping the window are returned
+	dublin, _ := time.LoadLocation("Europe/Dublin")
+	from := time.Date(2024, 3, 12, 0, 0, 0, 0, dublin)
+	until := time.Date(2024, 4, 5, 0, 0, 0, 0, dublin)
+	events, err := Parse(strings.NewReader(feed), from, until)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	var got []string
+	for _, event := range events {
+		got = append(got, event.Key()+" "+event.Start.In(dublin).Format("01-02 15:04"))
+	}
+	// The 03-19 occurrence is excluded, 03-21 is added and 04-02 is moved; the wall clock
+	// time is kept across the change to summer time on 03-31. The monthly backup skips
+	// the months without a 31st and ends after 3 occurrences.
+	want := []string{
+		"patching@example.com/20240312T220000Z 03-12 22:00",
+		"patching@example.com/20240321T220000Z 03-21 22:00",
+		"patching@example.com/20240326T220000Z 03-26 22:00",
+		"patching@example.com/20240402T210000Z 04-03 22:00",
+		"backup@example.com/20240331T010000Z 03-31 02:00",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected occurrences:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if moved := events[3]; moved.Summary != "Weekly patching (moved)" || moved.End.Sub(moved.Start) != time.Hour {
+		t.Errorf("Expected the moved occurrence to replace the original, got %+v", moved)
+	}
+}
+
+func TestFeed_Events(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(testCalendar))
+	}))
+	defer server.Close()
+
+	events, err := NewFeed(server.URL, 24*time.Hour).Events()
+	if err != nil {
+		t.Fatalf("Events() failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("Expected 3 events, got %d", len(events))
+	}
+}
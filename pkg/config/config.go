@@ -1,34 +1,100 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/schedule"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/ticket"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Alertmanager AlertmanagerConfig
-	Jira         JiraConfig
-	Sync         SyncConfig
-	Metrics      MetricsConfig
+	Alertmanager   AlertmanagerConfig
+	Jira           JiraConfig
+	Sync           SyncConfig
+	Metrics        MetricsConfig
+	State          StateConfig
+	Redis          RedisConfig
+	Backup         BackupConfig
+	SLO            SLOConfig
+	Inventory      InventoryConfig
+	Lock           LockConfig
+	LeaderElection LeaderElectionConfig
+	Daemon         DaemonConfig
+	Operator       OperatorConfig
+	Health         HealthConfig
+	Tracing        TracingConfig
+	Heartbeat      HeartbeatConfig
+	Auth           AuthConfig
+	API            APIConfig
+	Slack          SlackConfig
+	Webhook        WebhookConfig
+	WebUI          WebUIConfig
+	Maintenance    MaintenanceConfig
+	GitOps         GitOpsConfig
+	Templates      TemplatesConfig
+	Teams          TeamsConfig
+	Rules          RulesConfig
+	Change         ChangeConfig
+	Report         ReportConfig
+	OnCall         OnCallConfig
+	FailureAlert   FailureAlertConfig
+	TLS            TLSConfig
+	Actions        ActionsConfig
 }
 
 // AlertmanagerConfig holds Alertmanager-specific configuration
 type AlertmanagerConfig struct {
 	URL                   string
-	AuthType              string // "none", "basic", "bearer"
+	AuthType              string // "none", "basic", "bearer", "mtls"
 	Username              string // For basic auth
 	Password              string // For basic auth
 	BearerToken           string // For bearer token auth
+	BearerTokenFile       string // Re-read when it changes, e.g. a projected service account token
+	// OAuth 2.0 client credentials for bearer auth; tokens are fetched and refreshed
+	OIDCIssuerURL    string   // Used to discover the token endpoint
+	OIDCTokenURL     string   // Token endpoint, overriding discovery
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCScopes       []string
+	OIDCAudience     string
+	ClientCertFile        string // For mtls auth
+	ClientKeyFile         string // For mtls auth
+	ExternalURL           string // Web UI URL linked from tickets, e.g. "https://alertmanager.example.com"
+	SilenceFilter         []string // Matchers restricting the silences fetched, e.g. team="payments"
+	// HTTP client configuration
+	Timeout             time.Duration // Per-request timeout
+	MaxIdleConns        int           // Idle connections kept open; 0 uses the Go default
+	MaxIdleConnsPerHost int           // Idle connections kept open per host; 0 uses the Go default
+	IdleConnTimeout     time.Duration // How long idle connections are kept open
+	DisableKeepAlives   bool          // Use a new connection for every request
 	// Auto-discovery configuration
 	AutoDiscover          bool
 	DiscoveryServiceName  string   // Service name pattern to match
 	DiscoveryServiceLabel string   // Label selector for discovery
 	DiscoveryPort         int      // Port to use for discovered services
 	DiscoveryNamespaces   []string // Preferred namespaces to search first
+	DiscoveryScheme       string   // "http" or "https"; empty detects TLS from the service
+	DiscoveryHealthCheck  bool     // Select the first discovered service that passes a health probe
+	DiscoveryMode         string   // "service" or "endpoints" to address a single replica
+	// DiscoveryNamespacesOnly searches only DiscoveryNamespaces and never lists namespaces,
+	// so that discovery works with namespace-scoped RBAC
+	DiscoveryNamespacesOnly bool
+	// DiscoveryNamespaceSelector restricts the search beyond DiscoveryNamespaces to the
+	// namespaces matching this label selector
+	DiscoveryNamespaceSelector string
 }
 
 // JiraConfig holds Jira-specific configuration
@@ -37,23 +103,94 @@ type JiraConfig struct {
 	Username   string
 	APIToken   string
 	ProjectKey string
+	// Kubernetes Secret holding jira-username and jira-api-token, watched for rotation
+	// instead of reading JIRA_USERNAME and JIRA_API_TOKEN
+	CredentialsSecretName      string
+	CredentialsSecretNamespace string // Defaults to the pod's namespace
+	// Workflow mapping for custom Jira workflows
+	StatusMap        string // e.g. "Triage=open,Won't Fix=closed"
+	ReopenTransition string // Transition name or ID used to reopen tickets
+	CloseTransition  string // Transition name or ID used to close tickets
+	VerifyWorkflow   bool   // Check the reopen and close transitions before syncing
+	// Handling of throttled requests
+	MaxRetries    int           // Retries of a throttled or transiently failing request
+	MaxRetryWait  time.Duration // Longest Retry-After delay that is waited out
+	RequestBudget int           // Maximum number of requests per run; 0 is unlimited
+	// Issue type of the sub-tasks created for refired alert instances
+	SubTaskIssueType string
+	// Jira Service Management: raise tickets as customer requests of a service desk
+	ServiceDeskID       string
+	RequestTypeID       string
+	RequestFields       string   // JSON object of the request type's required fields, e.g. {"customfield_10060":"production"}
+	RequestParticipants []string // Account IDs or email addresses
+	RequestOnBehalfOf   string   // Account ID or email address of the customer
+	SLAFields           []string // SLA fields shown with the ticket status, e.g. customfield_10030
 }
 
 // SyncConfig holds synchronization configuration
 type SyncConfig struct {
-	ExpiryThresholdHours        int
-	ExtensionDurationHours      int
-	DefaultSilenceDurationHours int
-	CheckAlerts                 bool
-	AnnotationPrefix            string
+	ExpiryThreshold        time.Duration
+	ExtensionDuration      time.Duration
+	DefaultSilenceDuration time.Duration
+	Timeout                time.Duration // Deadline of a run; 0 is unlimited
+	ClockTolerance         time.Duration // Allowance for skewed clocks in expiry decisions
+	UseServerTime          bool          // Judge expiry by the Alertmanager server's clock
+	CheckAlerts            bool
+	CheckSilencedAlerts    bool // Report managed silences that match no alerts as idle
+	AnnotationPrefix       string
+	ProcessDirectives      bool
+	DurationLabelPrefix    string
+	DurationField          string
+	DurationFieldUnit      string // Unit of a plain number in DurationField: "hours" or "days"
+	RequireAssignee        bool
+	BusinessHoursEnabled   bool
+	BusinessHours          string // e.g. "09:00-17:00"
+	BusinessDays           string // e.g. "Mon-Fri" or "Mon,Tue,Wed"
+	BusinessTimezone       string // IANA timezone name
+	LifecycleLabels        bool
+	UpdateDueDate          bool
+	ExtensionHistory       bool
+	AutoCloseAfterDays     int // 0 disables closing tickets whose alerts stay quiet
+	RecreateOnReopenDays   int // How long resolved tickets are watched for being reopened; 0 disables
+	ExpireIdleAfterDays    int // Stop extending silences that matched no alerts this long; 0 disables
+	MaxExtensions          int // Stop extending silences extended this often; 0 disables
+	JournalDays            int // How long the undo journal of each run is kept for rollback; 0 disables
+	TicketMatchers         bool
+	MatchersField          string
+	SilenceRefField        string   // Jira custom field holding the silence ID instead of the description
+	SeverityPriorities     string   // e.g. "critical=Highest,warning=Medium"
+	PriorityExtensions     string   // e.g. "Highest=1d,High=72h"
+	PriorityExtensionHours string   // Deprecated hour-based form, e.g. "Highest=24,High=72"
+	SeveritySLAs           string   // How long alerts of each severity may stay silenced, e.g. "critical=3d,warning=14d"
+	SLAPriority            string   // Priority tickets exceeding their SLA are raised to
+	SLALabel               string   // Label added to tickets exceeding their SLA
+	KarmaAcks              bool     // Link karma acknowledgements without a ticket to a ticket and manage them
+	KarmaAckPrefix         string   // Comment prefix of karma acknowledgements
+	TicketRefPattern       string   // Regex finding ticket keys in hand-written silence comments
+	TicketDiscovery        bool     // Reconcile open tickets whose silence is no longer active
+	DiscoveryJQL           string   // Overrides the JQL query finding tickets with silence references
+	ReopenStrategy         string   // "reopen", "new-ticket" or "comment" for alerts refiring on closed tickets
+	RefireLinkType         string   // Issue link type between a new ticket and the closed one, e.g. "Relates"
+	RefireCopyLabels       []string // Labels copied to the new ticket; a trailing "*" matches a prefix
+	RefireSubTasks         bool     // Reopen with a sub-task per instance of an alert refiring with several
+	Watchers               []string // Account IDs or emails watching tickets that are created or reopened
+	SummaryOutput          string   // "-" writes a JSON summary of each run to stdout; otherwise a file path
+	TicketProjects         []string // Jira projects whose tickets are managed; references to others are skipped
+	ManagedCreators        []string // Only silences created by these identities are managed; a trailing "*" matches a prefix
+	IgnoredCreators        []string // Silences created by these identities are never managed
+	DetectDrift            bool     // Detect managed silences edited outside silence-manager
+	DriftComments          bool     // Comment on the ticket of a silence edited outside silence-manager
+	ProtectToken           string   // Comment token of silences that opt out of management
+	ProtectLabel           string   // Silences with a matcher on this label opt out of management
 }
 
 // MetricsConfig holds metrics publishing configuration
 type MetricsConfig struct {
 	Enabled               bool
-	Backend               string // "pushgateway", "otel", or ""
+	Backend               string // "pushgateway", "otel", "prometheus" (scraped from /metrics), or ""
 	URL                   string
 	JobName               string // For Pushgateway
+	PushgatewayInstance   string // Pushgateway grouping label of this deployment
 	OTelInsecure          bool   // For OTel - use insecure connection
 	// Auto-discovery configuration
 	AutoDiscover          bool
@@ -61,10 +198,262 @@ type MetricsConfig struct {
 	DiscoveryServiceLabel string   // Label selector for discovery
 	DiscoveryPort         int      // Port to use for discovered services
 	DiscoveryNamespaces   []string // Preferred namespaces to search first
+	// DiscoveryNamespacesOnly and DiscoveryNamespaceSelector scope the search as for
+	// Alertmanager discovery
+	DiscoveryNamespacesOnly    bool
+	DiscoveryNamespaceSelector string
+}
+
+// StateConfig holds configuration for persisting state between runs
+type StateConfig struct {
+	Backend  string // "none", "file" or "redis"
+	FilePath string // For the file backend
+	RedisKey string // For the redis backend
+	// SyncBackend imports the state file from and exports it to "configmap", "s3", "gcs"
+	// or "redis" around each run, for runners without persistent storage; "" disables it
+	SyncBackend   string
+	SyncName      string // ConfigMap name, object name or Redis key of the state copy
+	SyncNamespace string // Namespace of the ConfigMap (default: the pod's namespace)
+	SyncBucket    string // Bucket of the object; credentials are shared with BACKUP_*
+}
+
+// RedisConfig holds the connection settings of the Redis server
+type RedisConfig struct {
+	Address  string // host:port
+	Username string // ACL user; empty authenticates with the password only
+	Password string
+	DB       int
+	TLS      bool
+}
+
+// BackupConfig holds configuration for backing up the managed silences after each run
+type BackupConfig struct {
+	Backend           string // "none", "file", "s3" or "gcs"
+	Dir               string // For the file backend
+	Bucket            string // For the s3 and gcs backends
+	Prefix            string // Prepended to object names
+	Endpoint          string // Overrides the S3 or GCS API endpoint, e.g. a MinIO server
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3SessionToken    string
+	GCSToken          string // Without a token, the metadata server is asked for one
+}
+
+// SLOConfig holds silence hygiene objectives
+type SLOConfig struct {
+	MinOpenTicketRatio float64
+	MaxOrphanRatio     float64
+	MaxMedianAge       time.Duration
+}
+
+// InventoryConfig holds configuration for publishing the managed silence inventory
+type InventoryConfig struct {
+	Enabled       bool
+	ConfigMapName string
+	Namespace     string // Defaults to the pod's namespace
+}
+
+// LockConfig holds configuration for the run lock
+type LockConfig struct {
+	Enabled              bool
+	Backend              string // "lease" or "redis"
+	LeaseName            string
+	Namespace            string // Defaults to the pod's namespace
+	LeaseDurationSeconds int    // Also the expiry of the redis lock
+	RedisKey             string // For the redis backend
+}
+
+// LeaderElectionConfig holds configuration for electing a leader among daemon or operator
+// replicas. The election uses the run lock lease (LOCK_LEASE_NAME and LOCK_NAMESPACE).
+type LeaderElectionConfig struct {
+	Enabled       bool
+	LeaseDuration time.Duration // How long the lease is valid without renewal
+	RetryPeriod   time.Duration // How often the lease is acquired or renewed
+}
+
+// DaemonConfig holds configuration for running as a long-lived daemon
+type DaemonConfig struct {
+	IntervalMinutes int
+	// ShutdownTimeout bounds how long the daemon and the serve command let the run in
+	// progress finish after SIGTERM or SIGINT
+	ShutdownTimeout time.Duration
+}
+
+// OperatorConfig holds configuration for reconciling SilencePolicy resources
+type OperatorConfig struct {
+	Namespace      string        // Namespace of the watched policies; empty watches all namespaces
+	ResyncInterval time.Duration // How often all policies are reconciled without changes
+}
+
+// HealthConfig holds configuration for the health and status HTTP endpoints of the
+// daemon and operator
+type HealthConfig struct {
+	Enabled       bool
+	Address       string        // Listen address, e.g. ":8080"
+	ProbeInterval time.Duration // How often backend reachability is checked
+}
+
+// TracingConfig holds configuration for exporting OpenTelemetry traces of sync runs
+type TracingConfig struct {
+	Enabled  bool
+	URL      string // OTLP/HTTP collector endpoint, e.g. otel-collector:4318
+	Insecure bool   // Use plain HTTP instead of HTTPS
+}
+
+// HeartbeatConfig holds configuration for the dead man's switch pinged after successful runs
+type HeartbeatConfig struct {
+	URL    string // Ping URL, e.g. https://hc-ping.com/<uuid>; empty disables the heartbeat
+	Method string // "GET", "POST" or "HEAD"
+}
+
+// AuthConfig holds authorization configuration for CLI and API operations
+type AuthConfig struct {
+	Role string // "viewer", "operator" or "admin"
+}
+
+// APIConfig holds configuration for the REST API of the serve command
+type APIConfig struct {
+	Address string // Listen address, e.g. ":8090"
+	Tokens  string // Bearer tokens with their roles, e.g. "operator=s3cr3t,viewer=t0k3n"
+	// URL and Token address the API of a running server for the commands performed
+	// through it, such as purge, whose authorization is left to the server
+	URL   string
+	Token string
+}
+
+// WebUIConfig holds configuration for the web UI served by the daemon on the health address
+type WebUIConfig struct {
+	Enabled bool // Serve the dashboard on /ui/; requires API_TOKENS
+}
+
+// SlackConfig holds configuration for the Slack slash command endpoint of the serve command
+type SlackConfig struct {
+	SigningSecret string   // Signing secret of the Slack app; empty disables the endpoint
+	Operators     []string // Slack user IDs allowed to create silences; others may only list
+	WebhookURL    string   // Incoming webhook URL of the channel notified of escalated tickets
+}
+
+// WebhookConfig holds configuration for the Alertmanager webhook receiver of the serve command
+type WebhookConfig struct {
+	Token     string // Bearer token Alertmanager sends; empty disables the receiver
+	FileLabel string // Label, as name=value, of the alerts to file tickets for
+}
+
+// GetFileLabel splits the file label into its name and value
+func (c *WebhookConfig) GetFileLabel() (name, value string, err error) {
+	name, value, ok := strings.Cut(c.FileLabel, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return "", "", fmt.Errorf("expected name=value, got %q", c.FileLabel)
+	}
+	return strings.TrimSpace(name), strings.TrimSpace(value), nil
+}
+
+// MaintenanceConfig holds configuration for the planned maintenance calendar
+type MaintenanceConfig struct {
+	CalendarURL string        // iCal feed URL; empty disables maintenance silences
+	Lookahead   time.Duration // How far ahead of an event its silence is created
+}
+
+// GitOpsConfig holds configuration for silences declared in a Git repository
+type GitOpsConfig struct {
+	Path  string // Directory of YAML silence definitions, e.g. a git-sync checkout; empty disables GitOps
+	Prune bool   // Expire the silences of definitions removed from the directory
+}
+
+// TemplatesConfig holds configuration for the silence templates of the create command and
+// the REST API
+type TemplatesConfig struct {
+	File string // YAML file of named silence templates; empty disables templates
+}
+
+// TeamsConfig holds configuration for the mapping of alert teams to their Jira project,
+// Slack channel and on-call schedule
+type TeamsConfig struct {
+	File string // YAML team mapping file; empty disables the mapping
+}
+
+// RulesConfig holds configuration for no longer extending silences of removed alerting rules
+type RulesConfig struct {
+	APIURL   string // Prometheus or Thanos base URL serving /api/v1/rules; empty disables the check
+	APIToken string // Bearer token sent to the rules API
+}
+
+// ChangeConfig holds configuration for silencing the planned windows of change tickets
+type ChangeConfig struct {
+	Query         string // Query finding approved change tickets, e.g. JQL; empty disables change silences
+	StartField    string // Custom field of the planned start, e.g. customfield_10060
+	EndField      string // Custom field of the planned end; changes without one get a scheduled silence
+	MatchersField string // Custom field of the matchers, e.g. "service=checkout, env=prod"
+}
+
+// ReportConfig holds configuration for publishing the hygiene report with report --publish
+type ReportConfig struct {
+	ConfluenceURL      string // Confluence base URL, e.g. https://example.atlassian.net/wiki; empty disables publishing to Confluence
+	ConfluenceSpace    string // Key of the space of the page
+	ConfluenceTitle    string // Title of the page, updated on every run
+	ConfluenceParentID string // Parent page of the page when it is created
+	JiraIssue          string // Issue the report is added to as a comment, e.g. one shown on a dashboard
+}
+
+// OnCallConfig holds configuration for assigning reopened tickets to the current on-call
+type OnCallConfig struct {
+	Provider  string // "pagerduty", "opsgenie", or "" to disable
+	APIURL    string // Overrides the provider's default API URL
+	APIToken  string // PagerDuty REST API token or Opsgenie API key
+	TeamLabel string // Alert label naming the owning team
+	Schedules string // e.g. "storage=PABC123,network=PDEF456"
+	// GrafanaWebhookURL is the Grafana OnCall webhook integration paged when a silence
+	// lapses at SYNC_MAX_EXTENSIONS; empty disables escalation
+	GrafanaWebhookURL string
+}
+
+// FailureAlertConfig holds configuration for raising an incident when runs keep failing
+type FailureAlertConfig struct {
+	Provider            string  // "pagerduty", "opsgenie", or "" to disable
+	APIURL              string  // Overrides the provider's default API URL
+	RoutingKey          string  // PagerDuty Events API integration key or Opsgenie API key
+	Source              string  // Names this deployment in the incident and keys it
+	ErrorRate           float64 // Errors per processed silence above which a run alerts; 0 disables
+	ConsecutiveFailures int     // Failed runs in a row that alert; 0 disables
+}
+
+// TLSConfig holds TLS settings for the Alertmanager, Jira and Pushgateway clients
+type TLSConfig struct {
+	CAFile             string // PEM bundle trusted in addition to the system roots
+	InsecureSkipVerify bool
+	MinVersion         string // "1.0", "1.1", "1.2" or "1.3"; empty uses the Go default
+}
+
+// ActionsConfig turns kinds of automated actions on and off, for rolling silence-manager
+// out step by step
+type ActionsConfig struct {
+	Extend bool
+	Delete bool
+	Reopen bool
+	Create bool // Also covers recreating silences
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
+	secrets := make(map[string]string, len(secretKeys))
+	for _, key := range secretKeys {
+		value, err := getSecret(key)
+		if err != nil {
+			return nil, err
+		}
+		secrets[key] = value
+	}
+
+	durations := make(map[string]time.Duration, len(durationSettings))
+	for _, setting := range durationSettings {
+		value, err := getEnvDuration(setting.key, setting.legacyKey, setting.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		durations[setting.key] = value
+	}
+
 	alertmanagerURL := getEnv("ALERTMANAGER_URL", "")
 	autoDiscover := alertmanagerURL == "" || getEnvBool("ALERTMANAGER_AUTO_DISCOVER", alertmanagerURL == "")
 
@@ -72,45 +461,288 @@ func LoadConfig() (*Config, error) {
 	metricsEnabled := getEnvBool("METRICS_ENABLED", false)
 	metricsURL := getEnv("METRICS_URL", "")
 	metricsBackend := getEnv("METRICS_BACKEND", "")
-	metricsAutoDiscover := metricsURL == "" && metricsEnabled && metricsBackend != ""
+	// Prometheus scrapes the /metrics endpoint, so there is no backend URL to discover
+	metricsAutoDiscover := metricsURL == "" && metricsEnabled && metricsBackend != "" && metricsBackend != "prometheus"
 
 	cfg := &Config{
 		Alertmanager: AlertmanagerConfig{
-			URL:                   alertmanagerURL,
-			AuthType:              getEnv("ALERTMANAGER_AUTH_TYPE", "none"),
-			Username:              getEnv("ALERTMANAGER_USERNAME", ""),
-			Password:              getEnv("ALERTMANAGER_PASSWORD", ""),
-			BearerToken:           getEnv("ALERTMANAGER_BEARER_TOKEN", ""),
-			AutoDiscover:          autoDiscover,
-			DiscoveryServiceName:  getEnv("ALERTMANAGER_DISCOVERY_SERVICE_NAME", "alertmanager"),
-			DiscoveryServiceLabel: getEnv("ALERTMANAGER_DISCOVERY_SERVICE_LABEL", "app=alertmanager"),
-			DiscoveryPort:         getEnvInt("ALERTMANAGER_DISCOVERY_PORT", 9093),
-			DiscoveryNamespaces:   getEnvSlice("ALERTMANAGER_DISCOVERY_NAMESPACES", []string{"monitoring", "default"}),
+			URL:                        alertmanagerURL,
+			AuthType:                   getEnv("ALERTMANAGER_AUTH_TYPE", "none"),
+			Username:                   secrets["ALERTMANAGER_USERNAME"],
+			Password:                   secrets["ALERTMANAGER_PASSWORD"],
+			BearerToken:                secrets["ALERTMANAGER_BEARER_TOKEN"],
+			BearerTokenFile:            getEnv("ALERTMANAGER_BEARER_TOKEN_FILE", ""),
+			OIDCIssuerURL:              getEnv("ALERTMANAGER_OIDC_ISSUER_URL", ""),
+			OIDCTokenURL:               getEnv("ALERTMANAGER_OIDC_TOKEN_URL", ""),
+			OIDCClientID:               getEnv("ALERTMANAGER_OIDC_CLIENT_ID", ""),
+			OIDCClientSecret:           secrets["ALERTMANAGER_OIDC_CLIENT_SECRET"],
+			OIDCScopes:                 getEnvSlice("ALERTMANAGER_OIDC_SCOPES", nil),
+			OIDCAudience:               getEnv("ALERTMANAGER_OIDC_AUDIENCE", ""),
+			ClientCertFile:             getEnv("ALERTMANAGER_CLIENT_CERT_FILE", ""),
+			ClientKeyFile:              getEnv("ALERTMANAGER_CLIENT_KEY_FILE", ""),
+			ExternalURL:                getEnv("ALERTMANAGER_EXTERNAL_URL", ""),
+			SilenceFilter:              getEnvSlice("ALERTMANAGER_SILENCE_FILTER", nil),
+			Timeout:                    durations["ALERTMANAGER_TIMEOUT"],
+			MaxIdleConns:               getEnvInt("ALERTMANAGER_MAX_IDLE_CONNS", 0),
+			MaxIdleConnsPerHost:        getEnvInt("ALERTMANAGER_MAX_IDLE_CONNS_PER_HOST", 0),
+			IdleConnTimeout:            durations["ALERTMANAGER_IDLE_CONN_TIMEOUT"],
+			DisableKeepAlives:          getEnvBool("ALERTMANAGER_DISABLE_KEEP_ALIVES", false),
+			AutoDiscover:               autoDiscover,
+			DiscoveryServiceName:       getEnv("ALERTMANAGER_DISCOVERY_SERVICE_NAME", "alertmanager"),
+			DiscoveryServiceLabel:      getEnv("ALERTMANAGER_DISCOVERY_SERVICE_LABEL", "app=alertmanager"),
+			DiscoveryPort:              getEnvInt("ALERTMANAGER_DISCOVERY_PORT", 9093),
+			DiscoveryNamespaces:        getEnvSlice("ALERTMANAGER_DISCOVERY_NAMESPACES", []string{"monitoring", "default"}),
+			DiscoveryScheme:            getEnv("ALERTMANAGER_DISCOVERY_SCHEME", ""),
+			DiscoveryHealthCheck:       getEnvBool("ALERTMANAGER_DISCOVERY_HEALTH_CHECK", true),
+			DiscoveryMode:              getEnv("ALERTMANAGER_DISCOVERY_MODE", "service"),
+			DiscoveryNamespacesOnly:    getEnvBool("ALERTMANAGER_DISCOVERY_NAMESPACES_ONLY", false),
+			DiscoveryNamespaceSelector: getEnv("ALERTMANAGER_DISCOVERY_NAMESPACE_SELECTOR", ""),
 		},
 		Jira: JiraConfig{
-			URL:        getEnv("JIRA_URL", ""),
-			Username:   getEnv("JIRA_USERNAME", ""),
-			APIToken:   getEnv("JIRA_API_TOKEN", ""),
-			ProjectKey: getEnv("JIRA_PROJECT_KEY", ""),
+			URL:                        getEnv("JIRA_URL", ""),
+			Username:                   secrets["JIRA_USERNAME"],
+			APIToken:                   secrets["JIRA_API_TOKEN"],
+			ProjectKey:                 getEnv("JIRA_PROJECT_KEY", ""),
+			CredentialsSecretName:      getEnv("JIRA_CREDENTIALS_SECRET_NAME", ""),
+			CredentialsSecretNamespace: getEnv("JIRA_CREDENTIALS_SECRET_NAMESPACE", ""),
+			StatusMap:                  getEnv("JIRA_STATUS_MAP", ""),
+			ReopenTransition:           getEnv("JIRA_REOPEN_TRANSITION", ""),
+			CloseTransition:            getEnv("JIRA_CLOSE_TRANSITION", ""),
+			VerifyWorkflow:             getEnvBool("JIRA_VERIFY_WORKFLOW", false),
+			MaxRetries:                 getEnvInt("JIRA_MAX_RETRIES", 3),
+			MaxRetryWait:               durations["JIRA_MAX_RETRY_WAIT"],
+			RequestBudget:              getEnvInt("JIRA_REQUEST_BUDGET", 0),
+			SubTaskIssueType:           getEnv("JIRA_SUBTASK_ISSUE_TYPE", "Sub-task"),
+			ServiceDeskID:              getEnv("JIRA_SERVICE_DESK_ID", ""),
+			RequestTypeID:              getEnv("JIRA_REQUEST_TYPE_ID", ""),
+			RequestFields:              getEnv("JIRA_REQUEST_FIELDS", ""),
+			RequestParticipants:        getEnvSlice("JIRA_REQUEST_PARTICIPANTS", nil),
+			RequestOnBehalfOf:          getEnv("JIRA_REQUEST_ON_BEHALF_OF", ""),
+			SLAFields:                  getEnvSlice("JIRA_SLA_FIELDS", nil),
 		},
 		Sync: SyncConfig{
-			ExpiryThresholdHours:        getEnvInt("SYNC_EXPIRY_THRESHOLD_HOURS", 24),
-			ExtensionDurationHours:      getEnvInt("SYNC_EXTENSION_DURATION_HOURS", 168), // 7 days
-			DefaultSilenceDurationHours: getEnvInt("SYNC_DEFAULT_SILENCE_DURATION_HOURS", 168), // 7 days
-			CheckAlerts:                 getEnvBool("SYNC_CHECK_ALERTS", true),
-			AnnotationPrefix:            getEnv("SYNC_ANNOTATION_PREFIX", "silence-manager"),
+			ExpiryThreshold:        durations["SYNC_EXPIRY_THRESHOLD"],
+			ExtensionDuration:      durations["SYNC_EXTENSION_DURATION"],
+			DefaultSilenceDuration: durations["SYNC_DEFAULT_SILENCE_DURATION"],
+			Timeout:                durations["SYNC_TIMEOUT"],
+			ClockTolerance:         durations["SYNC_CLOCK_TOLERANCE"],
+			UseServerTime:          getEnvBool("SYNC_USE_SERVER_TIME", true),
+			CheckAlerts:            getEnvBool("SYNC_CHECK_ALERTS", true),
+			CheckSilencedAlerts:    getEnvBool("SYNC_CHECK_SILENCED_ALERTS", false),
+			AnnotationPrefix:       getEnv("SYNC_ANNOTATION_PREFIX", "silence-manager"),
+			ProcessDirectives:      getEnvBool("SYNC_PROCESS_DIRECTIVES", false),
+			DurationLabelPrefix:    getEnv("SYNC_DURATION_LABEL_PREFIX", "silence-duration"),
+			DurationField:          getEnv("SYNC_DURATION_FIELD", ""),
+			DurationFieldUnit:      getEnv("SYNC_DURATION_FIELD_UNIT", "days"),
+			RequireAssignee:        getEnvBool("SYNC_REQUIRE_ASSIGNEE", false),
+			BusinessHoursEnabled:   getEnvBool("SYNC_BUSINESS_HOURS_ENABLED", false),
+			BusinessHours:          getEnv("SYNC_BUSINESS_HOURS", "09:00-17:00"),
+			BusinessDays:           getEnv("SYNC_BUSINESS_DAYS", "Mon-Fri"),
+			BusinessTimezone:       getEnv("SYNC_BUSINESS_TIMEZONE", "UTC"),
+			LifecycleLabels:        getEnvBool("SYNC_LIFECYCLE_LABELS", false),
+			UpdateDueDate:          getEnvBool("SYNC_UPDATE_DUE_DATE", false),
+			ExtensionHistory:       getEnvBool("SYNC_EXTENSION_HISTORY", false),
+			AutoCloseAfterDays:     getEnvInt("SYNC_AUTO_CLOSE_AFTER_DAYS", 0),
+			RecreateOnReopenDays:   getEnvInt("SYNC_RECREATE_ON_REOPEN_DAYS", 0),
+			ExpireIdleAfterDays:    getEnvInt("SYNC_EXPIRE_IDLE_AFTER_DAYS", 0),
+			MaxExtensions:          getEnvInt("SYNC_MAX_EXTENSIONS", 0),
+			JournalDays:            getEnvInt("SYNC_JOURNAL_DAYS", 0),
+			TicketMatchers:         getEnvBool("SYNC_TICKET_MATCHERS", false),
+			MatchersField:          getEnv("SYNC_MATCHERS_FIELD", ""),
+			SilenceRefField:        getEnv("SYNC_SILENCE_REF_FIELD", ""),
+			SeverityPriorities:     getEnv("SYNC_SEVERITY_PRIORITIES", ""),
+			PriorityExtensions:     getEnv("SYNC_PRIORITY_EXTENSIONS", ""),
+			PriorityExtensionHours: getEnv("SYNC_PRIORITY_EXTENSION_HOURS", ""),
+			SeveritySLAs:           getEnv("SYNC_SEVERITY_SLAS", ""),
+			SLAPriority:            getEnv("SYNC_SLA_PRIORITY", ""),
+			SLALabel:               getEnv("SYNC_SLA_LABEL", "silence-sla-breached"),
+			KarmaAcks:              getEnvBool("SYNC_KARMA_ACKS", false),
+			KarmaAckPrefix:         getEnv("SYNC_KARMA_ACK_PREFIX", "ACK!"),
+			TicketRefPattern:       getEnv("SYNC_TICKET_REF_PATTERN", ""),
+			TicketDiscovery:        getEnvBool("SYNC_TICKET_DISCOVERY", false),
+			DiscoveryJQL:           getEnv("SYNC_DISCOVERY_JQL", ""),
+			ReopenStrategy:         getEnv("SYNC_REOPEN_STRATEGY", "reopen"),
+			RefireLinkType:         getEnv("SYNC_REFIRE_LINK_TYPE", "Relates"),
+			RefireCopyLabels:       getEnvSlice("SYNC_REFIRE_COPY_LABELS", nil),
+			RefireSubTasks:         getEnvBool("SYNC_REFIRE_SUBTASKS", false),
+			Watchers:               getEnvSlice("SYNC_WATCHERS", nil),
+			SummaryOutput:          getEnv("SYNC_SUMMARY_OUTPUT", ""),
+			TicketProjects:         getEnvSlice("SYNC_TICKET_PROJECTS", nil),
+			ManagedCreators:        getEnvSlice("SYNC_MANAGED_CREATORS", nil),
+			IgnoredCreators:        getEnvSlice("SYNC_IGNORED_CREATORS", nil),
+			DetectDrift:            getEnvBool("SYNC_DETECT_DRIFT", false),
+			DriftComments:          getEnvBool("SYNC_DRIFT_COMMENTS", false),
+			ProtectToken:           getEnv("SYNC_PROTECT_TOKEN", "#do-not-manage"),
+			ProtectLabel:           getEnv("SYNC_PROTECT_LABEL", ""),
 		},
 		Metrics: MetricsConfig{
-			Enabled:               metricsEnabled,
-			Backend:               metricsBackend,
-			URL:                   metricsURL,
-			JobName:               getEnv("METRICS_PUSHGATEWAY_JOB_NAME", "silence_manager"),
-			OTelInsecure:          getEnvBool("METRICS_OTEL_INSECURE", true),
-			AutoDiscover:          metricsAutoDiscover,
-			DiscoveryServiceName:  getEnv("METRICS_DISCOVERY_SERVICE_NAME", ""),
-			DiscoveryServiceLabel: getEnv("METRICS_DISCOVERY_SERVICE_LABEL", ""),
-			DiscoveryPort:         getEnvInt("METRICS_DISCOVERY_PORT", 0),
-			DiscoveryNamespaces:   getEnvSlice("METRICS_DISCOVERY_NAMESPACES", []string{"monitoring", "default"}),
+			Enabled:                    metricsEnabled,
+			Backend:                    metricsBackend,
+			URL:                        metricsURL,
+			JobName:                    getEnv("METRICS_PUSHGATEWAY_JOB_NAME", "silence_manager"),
+			PushgatewayInstance:        getEnv("METRICS_PUSHGATEWAY_INSTANCE", "silence-manager"),
+			OTelInsecure:               getEnvBool("METRICS_OTEL_INSECURE", true),
+			AutoDiscover:               metricsAutoDiscover,
+			DiscoveryServiceName:       getEnv("METRICS_DISCOVERY_SERVICE_NAME", ""),
+			DiscoveryServiceLabel:      getEnv("METRICS_DISCOVERY_SERVICE_LABEL", ""),
+			DiscoveryPort:              getEnvInt("METRICS_DISCOVERY_PORT", 0),
+			DiscoveryNamespaces:        getEnvSlice("METRICS_DISCOVERY_NAMESPACES", []string{"monitoring", "default"}),
+			DiscoveryNamespacesOnly:    getEnvBool("METRICS_DISCOVERY_NAMESPACES_ONLY", false),
+			DiscoveryNamespaceSelector: getEnv("METRICS_DISCOVERY_NAMESPACE_SELECTOR", ""),
+		},
+		State: StateConfig{
+			Backend:  getEnv("STATE_BACKEND", "none"),
+			FilePath: getEnv("STATE_FILE_PATH", "/var/lib/silence-manager/state.json"),
+			RedisKey: getEnv("STATE_REDIS_KEY", "silence-manager:state"),
+
+			SyncBackend:   getEnv("STATE_SYNC_BACKEND", ""),
+			SyncName:      getEnv("STATE_SYNC_NAME", ""),
+			SyncNamespace: getEnv("STATE_SYNC_NAMESPACE", ""),
+			SyncBucket:    getEnv("STATE_SYNC_BUCKET", getEnv("BACKUP_BUCKET", "")),
+		},
+		Redis: RedisConfig{
+			Address:  getEnv("REDIS_ADDRESS", ""),
+			Username: getEnv("REDIS_USERNAME", ""),
+			Password: secrets["REDIS_PASSWORD"],
+			DB:       getEnvInt("REDIS_DB", 0),
+			TLS:      getEnvBool("REDIS_TLS", false),
+		},
+		Backup: BackupConfig{
+			Backend:           getEnv("BACKUP_BACKEND", "none"),
+			Dir:               getEnv("BACKUP_DIR", "/var/lib/silence-manager/backup"),
+			Bucket:            getEnv("BACKUP_BUCKET", ""),
+			Prefix:            getEnv("BACKUP_PREFIX", "silence-manager/"),
+			Endpoint:          getEnv("BACKUP_ENDPOINT", ""),
+			S3Region:          getEnv("BACKUP_S3_REGION", "us-east-1"),
+			S3AccessKeyID:     secrets["BACKUP_S3_ACCESS_KEY_ID"],
+			S3SecretAccessKey: secrets["BACKUP_S3_SECRET_ACCESS_KEY"],
+			S3SessionToken:    secrets["BACKUP_S3_SESSION_TOKEN"],
+			GCSToken:          secrets["BACKUP_GCS_TOKEN"],
+		},
+		SLO: SLOConfig{
+			MinOpenTicketRatio: getEnvFloat("SLO_MIN_OPEN_TICKET_RATIO", 0.9),
+			MaxOrphanRatio:     getEnvFloat("SLO_MAX_ORPHAN_RATIO", 0.1),
+			MaxMedianAge:       durations["SLO_MAX_MEDIAN_AGE"],
+		},
+		Inventory: InventoryConfig{
+			Enabled:       getEnvBool("INVENTORY_ENABLED", false),
+			ConfigMapName: getEnv("INVENTORY_CONFIGMAP_NAME", "silence-manager-inventory"),
+			Namespace:     getEnv("INVENTORY_NAMESPACE", ""),
+		},
+		Lock: LockConfig{
+			Enabled:              getEnvBool("LOCK_ENABLED", false),
+			Backend:              getEnv("LOCK_BACKEND", "lease"),
+			LeaseName:            getEnv("LOCK_LEASE_NAME", "silence-manager"),
+			Namespace:            getEnv("LOCK_NAMESPACE", ""),
+			LeaseDurationSeconds: getEnvInt("LOCK_LEASE_DURATION_SECONDS", 600),
+			RedisKey:             getEnv("LOCK_REDIS_KEY", "silence-manager:lock"),
+		},
+		LeaderElection: LeaderElectionConfig{
+			Enabled:       getEnvBool("LEADER_ELECTION_ENABLED", false),
+			LeaseDuration: durations["LEADER_ELECTION_LEASE_DURATION"],
+			RetryPeriod:   durations["LEADER_ELECTION_RETRY_PERIOD"],
+		},
+		Daemon: DaemonConfig{
+			IntervalMinutes: getEnvInt("DAEMON_INTERVAL_MINUTES", 60),
+			ShutdownTimeout: durations["SHUTDOWN_TIMEOUT"],
+		},
+		Operator: OperatorConfig{
+			Namespace:      getEnv("OPERATOR_NAMESPACE", ""),
+			ResyncInterval: durations["OPERATOR_RESYNC_INTERVAL"],
+		},
+		Health: HealthConfig{
+			Enabled:       getEnvBool("HEALTH_ENABLED", true),
+			Address:       getEnv("HEALTH_ADDRESS", ":8080"),
+			ProbeInterval: durations["HEALTH_PROBE_INTERVAL"],
+		},
+		Tracing: TracingConfig{
+			Enabled:  getEnvBool("TRACING_ENABLED", false),
+			URL:      getEnv("TRACING_URL", ""),
+			Insecure: getEnvBool("TRACING_INSECURE", true),
+		},
+		Heartbeat: HeartbeatConfig{
+			URL:    secrets["HEARTBEAT_URL"],
+			Method: strings.ToUpper(getEnv("HEARTBEAT_METHOD", "GET")),
+		},
+		Auth: AuthConfig{
+			Role: getEnv("AUTH_ROLE", "operator"),
+		},
+		API: APIConfig{
+			Address: getEnv("API_ADDRESS", ":8090"),
+			Tokens:  secrets["API_TOKENS"],
+			URL:     getEnv("API_URL", ""),
+			Token:   secrets["API_TOKEN"],
+		},
+		WebUI: WebUIConfig{
+			Enabled: getEnvBool("WEBUI_ENABLED", false),
+		},
+		Slack: SlackConfig{
+			SigningSecret: secrets["SLACK_SIGNING_SECRET"],
+			Operators:     getEnvSlice("SLACK_OPERATORS", nil),
+			WebhookURL:    secrets["SLACK_WEBHOOK_URL"],
+		},
+		Webhook: WebhookConfig{
+			Token:     secrets["WEBHOOK_TOKEN"],
+			FileLabel: getEnv("WEBHOOK_FILE_LABEL", "file_ticket=true"),
+		},
+		Maintenance: MaintenanceConfig{
+			CalendarURL: getEnv("MAINTENANCE_CALENDAR_URL", ""),
+			Lookahead:   durations["MAINTENANCE_LOOKAHEAD"],
+		},
+		GitOps: GitOpsConfig{
+			Path:  getEnv("GITOPS_PATH", ""),
+			Prune: getEnvBool("GITOPS_PRUNE", true),
+		},
+		Templates: TemplatesConfig{
+			File: getEnv("SILENCE_TEMPLATES_FILE", ""),
+		},
+		Teams: TeamsConfig{
+			File: getEnv("TEAMS_FILE", ""),
+		},
+		Rules: RulesConfig{
+			APIURL:   getEnv("RULES_API_URL", ""),
+			APIToken: secrets["RULES_API_TOKEN"],
+		},
+		Change: ChangeConfig{
+			Query:         getEnv("CHANGE_QUERY", ""),
+			StartField:    getEnv("CHANGE_START_FIELD", ""),
+			EndField:      getEnv("CHANGE_END_FIELD", ""),
+			MatchersField: getEnv("CHANGE_MATCHERS_FIELD", ""),
+		},
+		Report: ReportConfig{
+			ConfluenceURL:      getEnv("REPORT_CONFLUENCE_URL", ""),
+			ConfluenceSpace:    getEnv("REPORT_CONFLUENCE_SPACE", ""),
+			ConfluenceTitle:    getEnv("REPORT_CONFLUENCE_TITLE", "Silence hygiene report"),
+			ConfluenceParentID: getEnv("REPORT_CONFLUENCE_PARENT_ID", ""),
+			JiraIssue:          getEnv("REPORT_JIRA_ISSUE", ""),
+		},
+		OnCall: OnCallConfig{
+			Provider:  getEnv("ONCALL_PROVIDER", ""),
+			APIURL:    getEnv("ONCALL_API_URL", ""),
+			APIToken:  secrets["ONCALL_API_TOKEN"],
+			TeamLabel: getEnv("ONCALL_TEAM_LABEL", "team"),
+			Schedules: getEnv("ONCALL_SCHEDULES", ""),
+
+			GrafanaWebhookURL: secrets["GRAFANA_ONCALL_WEBHOOK_URL"],
+		},
+		FailureAlert: FailureAlertConfig{
+			Provider:            getEnv("FAILURE_ALERT_PROVIDER", ""),
+			APIURL:              getEnv("FAILURE_ALERT_API_URL", ""),
+			RoutingKey:          secrets["FAILURE_ALERT_ROUTING_KEY"],
+			Source:              getEnv("FAILURE_ALERT_SOURCE", "silence-manager"),
+			ErrorRate:           getEnvFloat("FAILURE_ALERT_ERROR_RATE", 0),
+			ConsecutiveFailures: getEnvInt("FAILURE_ALERT_CONSECUTIVE_FAILURES", 3),
+		},
+		TLS: TLSConfig{
+			CAFile:             getEnv("TLS_CA_FILE", ""),
+			InsecureSkipVerify: getEnvBool("TLS_INSECURE_SKIP_VERIFY", false),
+			MinVersion:         getEnv("TLS_MIN_VERSION", ""),
+		},
+		Actions: ActionsConfig{
+			Extend: getEnvBool("ENABLE_EXTEND", true),
+			Delete: getEnvBool("ENABLE_DELETE", true),
+			Reopen: getEnvBool("ENABLE_REOPEN", true),
+			Create: getEnvBool("ENABLE_CREATE", true),
 		},
 	}
 
@@ -118,16 +750,52 @@ func LoadConfig() (*Config, error) {
 	if cfg.Jira.URL == "" {
 		return nil, fmt.Errorf("JIRA_URL is required")
 	}
-	if cfg.Jira.Username == "" {
-		return nil, fmt.Errorf("JIRA_USERNAME is required")
-	}
-	if cfg.Jira.APIToken == "" {
-		return nil, fmt.Errorf("JIRA_API_TOKEN is required")
+	if cfg.Jira.CredentialsSecretName == "" {
+		if cfg.Jira.Username == "" {
+			return nil, fmt.Errorf("JIRA_USERNAME is required unless JIRA_CREDENTIALS_SECRET_NAME is set")
+		}
+		if cfg.Jira.APIToken == "" {
+			return nil, fmt.Errorf("JIRA_API_TOKEN is required unless JIRA_CREDENTIALS_SECRET_NAME is set")
+		}
 	}
 	if cfg.Jira.ProjectKey == "" {
 		return nil, fmt.Errorf("JIRA_PROJECT_KEY is required")
 	}
 
+	if _, err := cfg.GetJiraStatuses(); err != nil {
+		return nil, fmt.Errorf("invalid JIRA_STATUS_MAP: %w", err)
+	}
+	if (cfg.Jira.ServiceDeskID == "") != (cfg.Jira.RequestTypeID == "") {
+		return nil, fmt.Errorf("JIRA_SERVICE_DESK_ID and JIRA_REQUEST_TYPE_ID must be set together")
+	}
+	if _, err := cfg.GetRequestFields(); err != nil {
+		return nil, fmt.Errorf("invalid JIRA_REQUEST_FIELDS: %w", err)
+	}
+	if cfg.Jira.MaxRetries < 0 {
+		return nil, fmt.Errorf("JIRA_MAX_RETRIES must not be negative")
+	}
+	if cfg.Jira.RequestBudget < 0 {
+		return nil, fmt.Errorf("JIRA_REQUEST_BUDGET must not be negative")
+	}
+
+	if cfg.Alertmanager.Timeout <= 0 {
+		return nil, fmt.Errorf("ALERTMANAGER_TIMEOUT must be positive")
+	}
+	if cfg.Alertmanager.MaxIdleConns < 0 || cfg.Alertmanager.MaxIdleConnsPerHost < 0 {
+		return nil, fmt.Errorf("ALERTMANAGER_MAX_IDLE_CONNS and ALERTMANAGER_MAX_IDLE_CONNS_PER_HOST must not be negative")
+	}
+
+	switch cfg.Alertmanager.DiscoveryScheme {
+	case "", "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid ALERTMANAGER_DISCOVERY_SCHEME: %s (must be 'http' or 'https')", cfg.Alertmanager.DiscoveryScheme)
+	}
+	switch cfg.Alertmanager.DiscoveryMode {
+	case "service", "endpoints":
+	default:
+		return nil, fmt.Errorf("invalid ALERTMANAGER_DISCOVERY_MODE: %s (must be 'service' or 'endpoints')", cfg.Alertmanager.DiscoveryMode)
+	}
+
 	// Validate alertmanager auth configuration
 	switch cfg.Alertmanager.AuthType {
 	case "basic":
@@ -135,38 +803,656 @@ func LoadConfig() (*Config, error) {
 			return nil, fmt.Errorf("ALERTMANAGER_USERNAME and ALERTMANAGER_PASSWORD are required when ALERTMANAGER_AUTH_TYPE is 'basic'")
 		}
 	case "bearer":
-		if cfg.Alertmanager.BearerToken == "" {
-			return nil, fmt.Errorf("ALERTMANAGER_BEARER_TOKEN is required when ALERTMANAGER_AUTH_TYPE is 'bearer'")
+		if cfg.Alertmanager.OIDCClientID != "" {
+			if cfg.Alertmanager.OIDCClientSecret == "" {
+				return nil, fmt.Errorf("ALERTMANAGER_OIDC_CLIENT_SECRET is required when ALERTMANAGER_OIDC_CLIENT_ID is set")
+			}
+			if cfg.Alertmanager.OIDCIssuerURL == "" && cfg.Alertmanager.OIDCTokenURL == "" {
+				return nil, fmt.Errorf("ALERTMANAGER_OIDC_ISSUER_URL or ALERTMANAGER_OIDC_TOKEN_URL is required when ALERTMANAGER_OIDC_CLIENT_ID is set")
+			}
+		} else if cfg.Alertmanager.BearerToken == "" {
+			return nil, fmt.Errorf("ALERTMANAGER_BEARER_TOKEN, ALERTMANAGER_BEARER_TOKEN_FILE or ALERTMANAGER_OIDC_CLIENT_ID is required when ALERTMANAGER_AUTH_TYPE is 'bearer'")
+		}
+	case "mtls":
+		if cfg.Alertmanager.ClientCertFile == "" || cfg.Alertmanager.ClientKeyFile == "" {
+			return nil, fmt.Errorf("ALERTMANAGER_CLIENT_CERT_FILE and ALERTMANAGER_CLIENT_KEY_FILE are required when ALERTMANAGER_AUTH_TYPE is 'mtls'")
+		}
+		if _, err := tls.LoadX509KeyPair(cfg.Alertmanager.ClientCertFile, cfg.Alertmanager.ClientKeyFile); err != nil {
+			return nil, fmt.Errorf("invalid Alertmanager client certificate: %w", err)
 		}
 	case "none":
 		// No validation needed
 	default:
-		return nil, fmt.Errorf("invalid ALERTMANAGER_AUTH_TYPE: %s (must be 'none', 'basic', or 'bearer')", cfg.Alertmanager.AuthType)
+		return nil, fmt.Errorf("invalid ALERTMANAGER_AUTH_TYPE: %s (must be 'none', 'basic', 'bearer', or 'mtls')", cfg.Alertmanager.AuthType)
 	}
 
 	// Validate metrics configuration
 	if cfg.Metrics.Enabled {
 		if cfg.Metrics.Backend == "" {
-			return nil, fmt.Errorf("METRICS_BACKEND is required when METRICS_ENABLED is true (must be 'pushgateway' or 'otel')")
+			return nil, fmt.Errorf("METRICS_BACKEND is required when METRICS_ENABLED is true (must be 'pushgateway', 'otel' or 'prometheus')")
 		}
-		if cfg.Metrics.Backend != "pushgateway" && cfg.Metrics.Backend != "otel" {
-			return nil, fmt.Errorf("invalid METRICS_BACKEND: %s (must be 'pushgateway' or 'otel')", cfg.Metrics.Backend)
+		switch cfg.Metrics.Backend {
+		case "pushgateway", "otel":
+		case "prometheus":
+			if !cfg.Health.Enabled {
+				return nil, fmt.Errorf("METRICS_BACKEND=prometheus is served on the health endpoints and requires HEALTH_ENABLED")
+			}
+		default:
+			return nil, fmt.Errorf("invalid METRICS_BACKEND: %s (must be 'pushgateway', 'otel' or 'prometheus')", cfg.Metrics.Backend)
 		}
-		// URL is not required if auto-discovery is enabled
-		if !cfg.Metrics.AutoDiscover && cfg.Metrics.URL == "" {
+		// URL is not required if auto-discovery is enabled or metrics are scraped
+		if !cfg.Metrics.AutoDiscover && cfg.Metrics.URL == "" && cfg.Metrics.Backend != "prometheus" {
 			return nil, fmt.Errorf("METRICS_URL is required when metrics are enabled and auto-discovery is disabled")
 		}
 	}
 
+	// Validate state configuration
+	switch cfg.State.Backend {
+	case "none":
+		// No validation needed
+	case "file":
+		if cfg.State.FilePath == "" {
+			return nil, fmt.Errorf("STATE_FILE_PATH is required when STATE_BACKEND is 'file'")
+		}
+	case "redis":
+		if cfg.Redis.Address == "" {
+			return nil, fmt.Errorf("REDIS_ADDRESS is required when STATE_BACKEND is 'redis'")
+		}
+		if cfg.State.RedisKey == "" {
+			return nil, fmt.Errorf("STATE_REDIS_KEY is required when STATE_BACKEND is 'redis'")
+		}
+	default:
+		return nil, fmt.Errorf("invalid STATE_BACKEND: %s (must be 'none', 'file' or 'redis')", cfg.State.Backend)
+	}
+	if cfg.State.SyncBackend != "" && cfg.State.Backend != "file" {
+		return nil, fmt.Errorf("STATE_SYNC_BACKEND requires STATE_BACKEND=file")
+	}
+	switch cfg.State.SyncBackend {
+	case "":
+	case "configmap":
+		if cfg.State.SyncName == "" {
+			cfg.State.SyncName = "silence-manager-state"
+		}
+	case "s3", "gcs":
+		if cfg.State.SyncName == "" {
+			cfg.State.SyncName = "silence-manager/state.json"
+		}
+		if cfg.State.SyncBucket == "" {
+			return nil, fmt.Errorf("STATE_SYNC_BUCKET or BACKUP_BUCKET is required when STATE_SYNC_BACKEND is '%s'", cfg.State.SyncBackend)
+		}
+		if cfg.State.SyncBackend == "s3" && (cfg.Backup.S3AccessKeyID == "" || cfg.Backup.S3SecretAccessKey == "") {
+			return nil, fmt.Errorf("BACKUP_S3_ACCESS_KEY_ID and BACKUP_S3_SECRET_ACCESS_KEY are required when STATE_SYNC_BACKEND is 's3'")
+		}
+	case "redis":
+		if cfg.State.SyncName == "" {
+			cfg.State.SyncName = "silence-manager:state"
+		}
+		if cfg.Redis.Address == "" {
+			return nil, fmt.Errorf("REDIS_ADDRESS is required when STATE_SYNC_BACKEND is 'redis'")
+		}
+	default:
+		return nil, fmt.Errorf("invalid STATE_SYNC_BACKEND: %s (must be 'configmap', 's3', 'gcs' or 'redis')", cfg.State.SyncBackend)
+	}
+	if cfg.Redis.DB < 0 {
+		return nil, fmt.Errorf("REDIS_DB must not be negative")
+	}
+
+	// Validate backup configuration
+	switch cfg.Backup.Backend {
+	case "none":
+	case "file":
+		if cfg.Backup.Dir == "" {
+			return nil, fmt.Errorf("BACKUP_DIR is required when BACKUP_BACKEND is 'file'")
+		}
+	case "s3":
+		if cfg.Backup.Bucket == "" {
+			return nil, fmt.Errorf("BACKUP_BUCKET is required when BACKUP_BACKEND is 's3'")
+		}
+		if cfg.Backup.S3AccessKeyID == "" || cfg.Backup.S3SecretAccessKey == "" {
+			return nil, fmt.Errorf("BACKUP_S3_ACCESS_KEY_ID and BACKUP_S3_SECRET_ACCESS_KEY are required when BACKUP_BACKEND is 's3'")
+		}
+	case "gcs":
+		if cfg.Backup.Bucket == "" {
+			return nil, fmt.Errorf("BACKUP_BUCKET is required when BACKUP_BACKEND is 'gcs'")
+		}
+	default:
+		return nil, fmt.Errorf("invalid BACKUP_BACKEND: %s (must be 'none', 'file', 's3' or 'gcs')", cfg.Backup.Backend)
+	}
+
+	// Validate business hours
+	if _, err := cfg.GetBusinessHours(); err != nil {
+		return nil, fmt.Errorf("invalid business hours configuration: %w", err)
+	}
+
+	// Validate priority mappings
+	if _, err := cfg.GetSeverityPriorities(); err != nil {
+		return nil, fmt.Errorf("invalid SYNC_SEVERITY_PRIORITIES: %w", err)
+	}
+	if cfg.Sync.KarmaAcks && strings.TrimSpace(cfg.Sync.KarmaAckPrefix) == "" {
+		return nil, fmt.Errorf("SYNC_KARMA_ACK_PREFIX must not be empty when SYNC_KARMA_ACKS is enabled")
+	}
+	if strings.TrimSpace(cfg.Sync.ProtectToken) == "" {
+		return nil, fmt.Errorf("SYNC_PROTECT_TOKEN must not be blank")
+	}
+	if cfg.Sync.DriftComments && !cfg.Sync.DetectDrift {
+		return nil, fmt.Errorf("SYNC_DRIFT_COMMENTS requires SYNC_DETECT_DRIFT")
+	}
+	if _, err := cfg.GetSeveritySLAs(); err != nil {
+		return nil, fmt.Errorf("invalid SYNC_SEVERITY_SLAS: %w", err)
+	}
+	if _, err := cfg.GetPriorityExtensions(); err != nil {
+		return nil, fmt.Errorf("invalid SYNC_PRIORITY_EXTENSIONS: %w", err)
+	}
+
+	if cfg.Lock.Enabled && cfg.Lock.LeaseDurationSeconds <= 0 {
+		return nil, fmt.Errorf("LOCK_LEASE_DURATION_SECONDS must be positive")
+	}
+	switch cfg.Lock.Backend {
+	case "lease":
+	case "redis":
+		if cfg.Lock.Enabled && cfg.Redis.Address == "" {
+			return nil, fmt.Errorf("REDIS_ADDRESS is required when LOCK_BACKEND is 'redis'")
+		}
+	default:
+		return nil, fmt.Errorf("invalid LOCK_BACKEND: %s (must be 'lease' or 'redis')", cfg.Lock.Backend)
+	}
+	if cfg.Daemon.IntervalMinutes <= 0 {
+		return nil, fmt.Errorf("DAEMON_INTERVAL_MINUTES must be positive")
+	}
+	if cfg.Daemon.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("SHUTDOWN_TIMEOUT must be positive")
+	}
+	if cfg.Sync.ClockTolerance < 0 {
+		return nil, fmt.Errorf("SYNC_CLOCK_TOLERANCE must not be negative")
+	}
+	if cfg.Sync.AutoCloseAfterDays < 0 {
+		return nil, fmt.Errorf("SYNC_AUTO_CLOSE_AFTER_DAYS must not be negative")
+	}
+	if cfg.Sync.ExpireIdleAfterDays < 0 {
+		return nil, fmt.Errorf("SYNC_EXPIRE_IDLE_AFTER_DAYS must not be negative")
+	}
+	if cfg.Sync.MaxExtensions < 0 {
+		return nil, fmt.Errorf("SYNC_MAX_EXTENSIONS must not be negative")
+	}
+	// Extensions are counted in the extension history of each silence
+	if cfg.Sync.MaxExtensions > 0 && !cfg.Sync.ExtensionHistory {
+		return nil, fmt.Errorf("SYNC_MAX_EXTENSIONS requires SYNC_EXTENSION_HISTORY")
+	}
+	if cfg.Sync.JournalDays < 0 {
+		return nil, fmt.Errorf("SYNC_JOURNAL_DAYS must not be negative")
+	}
+	if cfg.Sync.RecreateOnReopenDays < 0 {
+		return nil, fmt.Errorf("SYNC_RECREATE_ON_REOPEN_DAYS must not be negative")
+	}
+	if cfg.LeaderElection.Enabled {
+		if cfg.LeaderElection.RetryPeriod <= 0 {
+			return nil, fmt.Errorf("LEADER_ELECTION_RETRY_PERIOD must be positive")
+		}
+		if cfg.LeaderElection.LeaseDuration <= 2*cfg.LeaderElection.RetryPeriod {
+			return nil, fmt.Errorf("LEADER_ELECTION_LEASE_DURATION must be more than twice LEADER_ELECTION_RETRY_PERIOD")
+		}
+	}
+	if cfg.Operator.ResyncInterval <= 0 {
+		return nil, fmt.Errorf("OPERATOR_RESYNC_INTERVAL must be positive")
+	}
+	if cfg.Health.Enabled && cfg.Health.ProbeInterval <= 0 {
+		return nil, fmt.Errorf("HEALTH_PROBE_INTERVAL must be positive")
+	}
+	if cfg.Tracing.Enabled && cfg.Tracing.URL == "" {
+		return nil, fmt.Errorf("TRACING_URL is required when TRACING_ENABLED is true")
+	}
+	if cfg.Maintenance.CalendarURL != "" && cfg.Maintenance.Lookahead <= 0 {
+		return nil, fmt.Errorf("MAINTENANCE_LOOKAHEAD must be positive")
+	}
+	if cfg.Report.ConfluenceURL != "" && (cfg.Report.ConfluenceSpace == "" || cfg.Report.ConfluenceTitle == "") {
+		return nil, fmt.Errorf("REPORT_CONFLUENCE_SPACE and REPORT_CONFLUENCE_TITLE are required when REPORT_CONFLUENCE_URL is set")
+	}
+	if cfg.Change.Query != "" {
+		if cfg.Change.StartField == "" || cfg.Change.MatchersField == "" {
+			return nil, fmt.Errorf("CHANGE_START_FIELD and CHANGE_MATCHERS_FIELD are required when CHANGE_QUERY is set")
+		}
+		if cfg.Maintenance.Lookahead <= 0 {
+			return nil, fmt.Errorf("MAINTENANCE_LOOKAHEAD must be positive")
+		}
+	}
+
+	// Validate ticket reference pattern
+	if _, err := cfg.GetTicketRefPattern(); err != nil {
+		return nil, fmt.Errorf("invalid SYNC_TICKET_REF_PATTERN: %w", err)
+	}
+	if _, err := cfg.GetSilenceFilter(); err != nil {
+		return nil, fmt.Errorf("invalid ALERTMANAGER_SILENCE_FILTER: %w", err)
+	}
+
+	// Ticket discovery needs something to find the tickets by; references in descriptions
+	// cannot be searched reliably
+	if cfg.Sync.TicketDiscovery && cfg.Sync.DiscoveryJQL == "" && !cfg.Sync.LifecycleLabels && cfg.Sync.SilenceRefField == "" {
+		return nil, fmt.Errorf("SYNC_TICKET_DISCOVERY requires SYNC_DISCOVERY_JQL, SYNC_LIFECYCLE_LABELS or SYNC_SILENCE_REF_FIELD")
+	}
+
+	if cfg.Sync.DurationFieldUnit != "hours" && cfg.Sync.DurationFieldUnit != "days" {
+		return nil, fmt.Errorf("invalid SYNC_DURATION_FIELD_UNIT: %s (must be 'hours' or 'days')", cfg.Sync.DurationFieldUnit)
+	}
+	switch cfg.Sync.ReopenStrategy {
+	case "reopen", "new-ticket", "comment":
+	default:
+		return nil, fmt.Errorf("invalid SYNC_REOPEN_STRATEGY: %s (must be 'reopen', 'new-ticket' or 'comment')", cfg.Sync.ReopenStrategy)
+	}
+	if cfg.Sync.RefireSubTasks && cfg.Sync.ReopenStrategy != "reopen" {
+		return nil, fmt.Errorf("SYNC_REFIRE_SUBTASKS requires SYNC_REOPEN_STRATEGY=reopen")
+	}
+
+	// Validate heartbeat configuration
+	if cfg.Heartbeat.URL != "" {
+		if u, err := url.Parse(cfg.Heartbeat.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid HEARTBEAT_URL: must be an http or https URL")
+		}
+	}
+	switch cfg.Heartbeat.Method {
+	case "GET", "POST", "HEAD":
+	default:
+		return nil, fmt.Errorf("invalid HEARTBEAT_METHOD: %s (must be 'GET', 'POST' or 'HEAD')", cfg.Heartbeat.Method)
+	}
+
+	// Validate on-call configuration
+	switch cfg.OnCall.Provider {
+	case "":
+	case "pagerduty", "opsgenie":
+		if cfg.OnCall.APIToken == "" {
+			return nil, fmt.Errorf("ONCALL_API_TOKEN is required when ONCALL_PROVIDER is set")
+		}
+	default:
+		return nil, fmt.Errorf("invalid ONCALL_PROVIDER: %s (must be 'pagerduty' or 'opsgenie')", cfg.OnCall.Provider)
+	}
+	if _, err := cfg.GetOnCallSchedules(); err != nil {
+		return nil, fmt.Errorf("invalid ONCALL_SCHEDULES: %w", err)
+	}
+
+	// Validate failure alert configuration
+	switch cfg.FailureAlert.Provider {
+	case "":
+	case "pagerduty", "opsgenie":
+		if cfg.FailureAlert.RoutingKey == "" {
+			return nil, fmt.Errorf("FAILURE_ALERT_ROUTING_KEY is required when FAILURE_ALERT_PROVIDER is set")
+		}
+		if cfg.FailureAlert.ErrorRate < 0 || cfg.FailureAlert.ErrorRate > 1 {
+			return nil, fmt.Errorf("FAILURE_ALERT_ERROR_RATE must be between 0 and 1")
+		}
+		if cfg.FailureAlert.ConsecutiveFailures < 0 {
+			return nil, fmt.Errorf("FAILURE_ALERT_CONSECUTIVE_FAILURES must not be negative")
+		}
+		if cfg.FailureAlert.ErrorRate == 0 && cfg.FailureAlert.ConsecutiveFailures == 0 {
+			return nil, fmt.Errorf("FAILURE_ALERT_PROVIDER requires FAILURE_ALERT_ERROR_RATE or FAILURE_ALERT_CONSECUTIVE_FAILURES")
+		}
+	default:
+		return nil, fmt.Errorf("invalid FAILURE_ALERT_PROVIDER: %s (must be 'pagerduty' or 'opsgenie')", cfg.FailureAlert.Provider)
+	}
+
+	// Validate TLS settings
+	if _, err := cfg.GetTLSConfig(); err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	// Validate authorization role
+	if _, err := authz.ParseRole(cfg.Auth.Role); err != nil {
+		return nil, fmt.Errorf("invalid AUTH_ROLE: %w", err)
+	}
+	tokens, err := cfg.GetAPITokens()
+	if err != nil {
+		return nil, fmt.Errorf("invalid API_TOKENS: %w", err)
+	}
+	if cfg.WebUI.Enabled {
+		if !cfg.Health.Enabled {
+			return nil, fmt.Errorf("WEBUI_ENABLED requires HEALTH_ENABLED, as the web UI is served on the health endpoints")
+		}
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("WEBUI_ENABLED requires API_TOKENS to sign in to the web UI")
+		}
+	}
+	if _, _, err := cfg.Webhook.GetFileLabel(); err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_FILE_LABEL: %w", err)
+	}
+
 	return cfg, nil
 }
 
-// GetSyncDurations converts hour-based configuration to time.Duration
-func (c *Config) GetSyncDurations() (expiryThreshold, extensionDuration, defaultSilenceDuration time.Duration) {
-	expiryThreshold = time.Duration(c.Sync.ExpiryThresholdHours) * time.Hour
-	extensionDuration = time.Duration(c.Sync.ExtensionDurationHours) * time.Hour
-	defaultSilenceDuration = time.Duration(c.Sync.DefaultSilenceDurationHours) * time.Hour
-	return
+// GetBusinessHours returns the configured business hours, or nil if alignment is disabled
+func (c *Config) GetBusinessHours() (*schedule.BusinessHours, error) {
+	if !c.Sync.BusinessHoursEnabled {
+		return nil, nil
+	}
+	return schedule.ParseBusinessHours(c.Sync.BusinessHours, c.Sync.BusinessDays, c.Sync.BusinessTimezone)
+}
+
+// GetSeverityPriorities returns the alert severity to ticket priority mapping, keyed by
+// lowercase severity
+func (c *Config) GetSeverityPriorities() (map[string]string, error) {
+	pairs, err := parsePairs(c.Sync.SeverityPriorities)
+	if err != nil {
+		return nil, err
+	}
+	priorities := make(map[string]string, len(pairs))
+	for severity, priority := range pairs {
+		priorities[strings.ToLower(severity)] = priority
+	}
+	return priorities, nil
+}
+
+// GetPriorityExtensions returns the extension duration for each ticket priority, keyed
+// by lowercase priority. The deprecated hour-based SYNC_PRIORITY_EXTENSION_HOURS is used
+// when SYNC_PRIORITY_EXTENSIONS is not set.
+func (c *Config) GetPriorityExtensions() (map[string]time.Duration, error) {
+	value, parse := c.Sync.PriorityExtensions, ParseDuration
+	if value == "" {
+		value, parse = c.Sync.PriorityExtensionHours, parseHours
+	}
+	pairs, err := parsePairs(value)
+	if err != nil {
+		return nil, err
+	}
+	extensions := make(map[string]time.Duration, len(pairs))
+	for priority, value := range pairs {
+		d, err := parse(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration %q for priority %q", value, priority)
+		}
+		extensions[strings.ToLower(priority)] = d
+	}
+	return extensions, nil
+}
+
+// GetSeveritySLAs returns how long the alerts of each severity may stay silenced before
+// their ticket is escalated, keyed by lowercase severity
+func (c *Config) GetSeveritySLAs() (map[string]time.Duration, error) {
+	pairs, err := parsePairs(c.Sync.SeveritySLAs)
+	if err != nil {
+		return nil, err
+	}
+	slas := make(map[string]time.Duration, len(pairs))
+	for severity, value := range pairs {
+		d, err := ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration %q for severity %q", value, severity)
+		}
+		slas[strings.ToLower(severity)] = d
+	}
+	return slas, nil
+}
+
+// GetJiraStatuses returns the ticket status of each mapped Jira status, keyed by
+// lowercase Jira status name
+func (c *Config) GetJiraStatuses() (map[string]ticket.TicketStatus, error) {
+	pairs, err := parsePairs(c.Jira.StatusMap)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]ticket.TicketStatus, len(pairs))
+	for name, value := range pairs {
+		status, err := ticket.ParseStatus(strings.ToLower(value))
+		if err != nil {
+			return nil, fmt.Errorf("status %q: %w", name, err)
+		}
+		statuses[strings.ToLower(name)] = status
+	}
+	return statuses, nil
+}
+
+// GetRequestFields returns the values of the service desk request type's fields, or nil
+// if none are configured
+func (c *Config) GetRequestFields() (map[string]interface{}, error) {
+	if c.Jira.RequestFields == "" {
+		return nil, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(c.Jira.RequestFields), &fields); err != nil {
+		return nil, fmt.Errorf("must be a JSON object: %w", err)
+	}
+	return fields, nil
+}
+
+// GetTicketRefPattern returns the compiled ticket reference pattern, or nil if silences
+// are only adopted through the annotation header
+func (c *Config) GetTicketRefPattern() (*regexp.Regexp, error) {
+	if c.Sync.TicketRefPattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(c.Sync.TicketRefPattern)
+}
+
+// GetSilenceFilter returns the matchers restricting the silences fetched from
+// Alertmanager, or nil if all silences are fetched
+func (c *Config) GetSilenceFilter() ([]alertmanager.Matcher, error) {
+	if len(c.Alertmanager.SilenceFilter) == 0 {
+		return nil, nil
+	}
+	return sync.ParseMatchers(c.Alertmanager.SilenceFilter)
+}
+
+// GetOnCallSchedules returns the on-call schedule identifier for each team
+func (c *Config) GetOnCallSchedules() (map[string]string, error) {
+	return parsePairs(c.OnCall.Schedules)
+}
+
+// GetAPITokens returns the role of each bearer token of the REST API, parsed from
+// role=token entries. Several tokens may have the same role. Errors never include tokens.
+func (c *Config) GetAPITokens() (map[string]authz.Role, error) {
+	tokens := make(map[string]authz.Role)
+	for i, item := range strings.Split(c.API.Tokens, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, token, ok := strings.Cut(item, "=")
+		token = strings.TrimSpace(token)
+		if !ok || token == "" {
+			return nil, fmt.Errorf("entry %d is not role=token", i+1)
+		}
+		role, err := authz.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		if _, ok := tokens[token]; ok {
+			return nil, fmt.Errorf("entry %d repeats a token", i+1)
+		}
+		tokens[token] = role
+	}
+	return tokens, nil
+}
+
+// tlsVersions maps TLS_MIN_VERSION values to TLS protocol versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// GetTLSConfig returns the TLS configuration for outgoing HTTP clients, or nil if the
+// defaults apply
+func (c *Config) GetTLSConfig() (*tls.Config, error) {
+	if c.TLS.CAFile == "" && !c.TLS.InsecureSkipVerify && c.TLS.MinVersion == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.TLS.InsecureSkipVerify}
+	if c.TLS.MinVersion != "" {
+		version, ok := tlsVersions[c.TLS.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS_MIN_VERSION %q (must be 1.0, 1.1, 1.2 or 1.3)", c.TLS.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+	if c.TLS.CAFile != "" {
+		pem, err := os.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS_CA_FILE: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in TLS_CA_FILE %s", c.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// parsePairs parses a comma-separated list of key=value pairs
+func parsePairs(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid entry %q (expected key=value)", item)
+		}
+		pairs[k] = v
+	}
+	return pairs, nil
+}
+
+// secretKeys are the settings that can also be read from a file named by the
+// corresponding _FILE variable, e.g. JIRA_API_TOKEN_FILE
+var secretKeys = []string{
+	"ALERTMANAGER_USERNAME",
+	"ALERTMANAGER_PASSWORD",
+	"ALERTMANAGER_BEARER_TOKEN",
+	"ALERTMANAGER_OIDC_CLIENT_SECRET",
+	"JIRA_USERNAME",
+	"JIRA_API_TOKEN",
+	"ONCALL_API_TOKEN",
+	"GRAFANA_ONCALL_WEBHOOK_URL",
+	"RULES_API_TOKEN",
+	"HEARTBEAT_URL",
+	"FAILURE_ALERT_ROUTING_KEY",
+	"API_TOKENS",
+	"API_TOKEN",
+	"SLACK_SIGNING_SECRET",
+	"SLACK_WEBHOOK_URL",
+	"WEBHOOK_TOKEN",
+	"REDIS_PASSWORD",
+	"BACKUP_S3_ACCESS_KEY_ID",
+	"BACKUP_S3_SECRET_ACCESS_KEY",
+	"BACKUP_S3_SESSION_TOKEN",
+	"BACKUP_GCS_TOKEN",
+}
+
+// getSecret returns the value of key, or the contents of the file named by key_FILE,
+// such as a mounted Kubernetes Secret or Docker secret. Trailing newlines are removed.
+func getSecret(key string) (string, error) {
+	value := os.Getenv(key)
+	path := os.Getenv(key + "_FILE")
+	switch {
+	case path == "":
+		return value, nil
+	case value != "":
+		return "", fmt.Errorf("only one of %s and %s_FILE may be set", key, key)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", key, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// durationSettings lists the duration settings with their deprecated hour-based names,
+// which are still read when the duration setting is not set. Settings introduced
+// after the switch to durations have no legacy name.
+var durationSettings = []struct {
+	key          string
+	legacyKey    string
+	defaultValue time.Duration
+}{
+	{"SYNC_EXPIRY_THRESHOLD", "SYNC_EXPIRY_THRESHOLD_HOURS", 24 * time.Hour},
+	{"SYNC_EXTENSION_DURATION", "SYNC_EXTENSION_DURATION_HOURS", 7 * 24 * time.Hour},
+	{"SYNC_DEFAULT_SILENCE_DURATION", "SYNC_DEFAULT_SILENCE_DURATION_HOURS", 7 * 24 * time.Hour},
+	{"SLO_MAX_MEDIAN_AGE", "SLO_MAX_MEDIAN_AGE_HOURS", 30 * 24 * time.Hour},
+	{"MAINTENANCE_LOOKAHEAD", "MAINTENANCE_LOOKAHEAD_HOURS", 24 * time.Hour},
+	{"JIRA_MAX_RETRY_WAIT", "", time.Minute},
+	{"ALERTMANAGER_TIMEOUT", "", 30 * time.Second},
+	{"ALERTMANAGER_IDLE_CONN_TIMEOUT", "", 90 * time.Second},
+	{"SYNC_TIMEOUT", "", 0},
+	{"SYNC_CLOCK_TOLERANCE", "", 0},
+	{"OPERATOR_RESYNC_INTERVAL", "", 5 * time.Minute},
+	{"LEADER_ELECTION_LEASE_DURATION", "", 15 * time.Second},
+	{"LEADER_ELECTION_RETRY_PERIOD", "", 2 * time.Second},
+	{"HEALTH_PROBE_INTERVAL", "", time.Minute},
+	{"SHUTDOWN_TIMEOUT", "", 20 * time.Second},
+}
+
+// getEnvDuration returns the duration in key, falling back to the whole hours in
+// legacyKey and then to defaultValue
+func getEnvDuration(key, legacyKey string, defaultValue time.Duration) (time.Duration, error) {
+	if value := os.Getenv(key); value != "" {
+		d, err := ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return d, nil
+	}
+	if value := os.Getenv(legacyKey); value != "" {
+		d, err := parseHours(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %w", legacyKey, err)
+		}
+		return d, nil
+	}
+	return defaultValue, nil
+}
+
+// ParseDuration parses a Go duration string such as "36h" or "90m", additionally
+// accepting a whole number of days such as "14d"
+func ParseDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %q must not be negative", value)
+	}
+	return d, nil
+}
+
+// parseHours parses a whole number of hours, as used by the deprecated *_HOURS settings
+func parseHours(value string) (time.Duration, error) {
+	hours, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours %q", value)
+	}
+	return time.Duration(hours) * time.Hour, nil
+}
+
+// RedactURL hides the password and query parameter values of a URL, which may carry
+// credentials or access tokens, for logging
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, "xxxxx")
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
 }
 
 // Helper functions
@@ -186,6 +1472,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/gitops"
+)
+
+// Silence is a managed silence with its ticket, as exported
+type Silence struct {
+	Name      string // Name of its GitOps definition, or derived from the ticket key
+	SilenceID string
+	Ticket    string
+	Matchers  []string // e.g. alertname=DiskFull or instance=~db-.*
+	Comment   string
+	EndsAt    time.Time
+}
+
+// Collect returns the silences linked to a ticket, sorted by name. names maps silence
+// IDs to the names of the GitOps definitions they were created for; other silences are
+// named after their ticket key, with a suffix for tickets having several silences.
+func Collect(silences []*alertmanager.Silence, names map[string]string) []Silence {
+	var exported []Silence
+	for _, s := range silences {
+		if s.TicketRef == "" {
+			continue
+		}
+		matchers := make([]string, len(s.Matchers))
+		for i, m := range s.Matchers {
+			matchers[i] = m.String()
+		}
+		exported = append(exported, Silence{
+			Name:      names[s.ID],
+			SilenceID: s.ID,
+			Ticket:    s.TicketRef,
+			Matchers:  matchers,
+			Comment:   s.Comment,
+			EndsAt:    s.EndsAt,
+		})
+	}
+
+	// Derive the missing names in a stable order, avoiding the declared ones
+	sort.Slice(exported, func(i, j int) bool {
+		if exported[i].Ticket != exported[j].Ticket {
+			return exported[i].Ticket < exported[j].Ticket
+		}
+		return exported[i].SilenceID < exported[j].SilenceID
+	})
+	taken := make(map[string]bool)
+	for _, s := range exported {
+		taken[s.Name] = s.Name != ""
+	}
+	for i := range exported {
+		if exported[i].Name != "" {
+			continue
+		}
+		base := strings.ToLower(exported[i].Ticket)
+		name := base
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[name] = true
+		exported[i].Name = name
+	}
+
+	sort.Slice(exported, func(i, j int) bool { return exported[i].Name < exported[j].Name })
+	return exported
+}
+
+// WriteYAML writes the silences as GitOps silence definitions, ready to be committed to
+// the repository read by GITOPS_PATH. The duration is the time each silence has left,
+// rounded up to whole days.
+func WriteYAML(w io.Writer, silences []Silence, now time.Time) error {
+	defs := make([]gitops.Definition, len(silences))
+	for i, s := range silences {
+		defs[i] = gitops.Definition{
+			Name:     s.Name,
+			Matchers: s.Matchers,
+			Ticket:   s.Ticket,
+			Comment:  s.Comment,
+			Duration: remainingDays(s.EndsAt, now),
+		}
+	}
+
+	data, err := yaml.Marshal(struct {
+		Silences []gitops.Definition `json:"silences"`
+	}{defs})
+	if err != nil {
+		return fmt.Errorf("failed to encode silences: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "# Silences managed by silence-manager, exported at %s\n%s", now.UTC().Format(time.RFC3339), data)
+	return err
+}
+
+// WriteHCL writes the silences as a Terraform locals block mapping names to silences,
+// for audits in Terraform or as input of resources managing the silences there
+func WriteHCL(w io.Writer, silences []Silence, now time.Time) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Silences managed by silence-manager, exported at %s\n", now.UTC().Format(time.RFC3339))
+	b.WriteString("locals {\n  silence_manager_silences = {\n")
+	for _, s := range silences {
+		matchers := make([]string, len(s.Matchers))
+		for i, m := range s.Matchers {
+			matchers[i] = hclString(m)
+		}
+		fmt.Fprintf(&b, "    %s = {\n", hclString(s.Name))
+		fmt.Fprintf(&b, "      silence_id = %s\n", hclString(s.SilenceID))
+		fmt.Fprintf(&b, "      ticket     = %s\n", hclString(s.Ticket))
+		fmt.Fprintf(&b, "      matchers   = [%s]\n", strings.Join(matchers, ", "))
+		fmt.Fprintf(&b, "      comment    = %s\n", hclString(s.Comment))
+		fmt.Fprintf(&b, "      ends_at    = %s\n", hclString(s.EndsAt.UTC().Format(time.RFC3339)))
+		b.WriteString("    }\n")
+	}
+	b.WriteString("  }\n}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// hclString quotes a string for HCL, escaping the template sequences ${ and %{
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			b.WriteRune(r)
+			if i+1 < len(s) && s[i+1] == '{' {
+				b.WriteRune(r)
+			}
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// remainingDays formats the time left until end as whole days, at least one
+func remainingDays(end, now time.Time) string {
+	days := int((end.Sub(now) + 24*time.Hour - 1) / (24 * time.Hour))
+	if days < 1 {
+		days = 1
+	}
+	return fmt.Sprintf("%dd", days)
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/gitops"
+)
+
+func testSilences(now time.Time) []*alertmanager.Silence {
+	return []*alertmanager.Silence{
+		{ID: "b", TicketRef: "OPS-2", Comment: "Disk ${replacement}", EndsAt: now.Add(36 * time.Hour),
+			Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}}},
+		{ID: "a", TicketRef: "OPS-2", EndsAt: now.Add(time.Hour),
+			Matchers: []alertmanager.Matcher{{Name: "instance", Value: "db-.*", IsEqual: true, IsRegex: true}}},
+		{ID: "c", TicketRef: "OPS-3", EndsAt: now.Add(72 * time.Hour),
+			Matchers: []alertmanager.Matcher{{Name: "team", Value: "network", IsEqual: true}}},
+		{ID: "d", Comment: "unmanaged", EndsAt: now.Add(time.Hour),
+			Matchers: []alertmanager.Matcher{{Name: "team", Value: "db", IsEqual: true}}},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	exported := Collect(testSilences(now), map[string]string{"c": "network-flapping"})
+
+	if len(exported) != 3 {
+		t.Fatalf("Expected the 3 silences linked to a ticket, got %+v", exported)
+	}
+	names := []string{exported[0].Name, exported[1].Name, exported[2].Name}
+	if strings.Join(names, ",") != "network-flapping,ops-2,ops-2-2" {
+		t.Errorf("Unexpected names: %v", names)
+	}
+	if exported[1].SilenceID != "a" || exported[1].Matchers[0] != "instance=~db-.*" {
+		t.Errorf("Unexpected silence: %+v", exported[1])
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	now := time.Now()
+	var buf bytes.Buffer
+	if err := WriteYAML(&buf, Collect(testSilences(now), nil), now); err != nil {
+		t.Fatalf("WriteYAML() failed: %v", err)
+	}
+
+	// The export can be committed as GitOps definitions as is
+	defs, err := gitops.Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Export is not valid GitOps definitions: %v\n%s", err, buf.String())
+	}
+	if len(defs) != 3 || defs[0].Name != "ops-2" || defs[0].Ticket != "OPS-2" || defs[0].Duration != "1d" {
+		t.Errorf("Unexpected definitions: %+v", defs)
+	}
+	if defs[1].Duration != "2d" {
+		t.Errorf("Expected 36h to round up to 2d, got %s", defs[1].Duration)
+	}
+}
+
+func TestWriteHCL(t *testing.T) {
+	now := time.Now()
+	var buf bytes.Buffer
+	if err := WriteHCL(&buf, Collect(testSilences(now), nil), now); err != nil {
+		t.Fatalf("WriteHCL() failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"locals {\n  silence_manager_silences = {\n",
+		`    "ops-2-2" = {`,
+		`      matchers   = ["alertname=DiskFull"]`,
+		`      comment    = "Disk $${replacement}"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package faults
+
+import (
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// AlertManager wraps an Alertmanager client with fault injection
+type AlertManager struct {
+	next     alertmanager.AlertManager
+	injector *Injector
+}
+
+// WrapAlertManager returns an Alertmanager client that injects faults before calling next
+func WrapAlertManager(next alertmanager.AlertManager, injector *Injector) *AlertManager {
+	return &AlertManager{next: next, injector: injector}
+}
+
+// GetSilence retrieves a silence by ID
+func (a *AlertManager) GetSilence(id string) (*alertmanager.Silence, error) {
+	if err := a.injector.Inject("GetSilence"); err != nil {
+		return nil, err
+	}
+	return a.next.GetSilence(id)
+}
+
+// ListSilences returns all active silences
+func (a *AlertManager) ListSilences() ([]*alertmanager.Silence, error) {
+	if err := a.injector.Inject("ListSilences"); err != nil {
+		return nil, err
+	}
+	return a.next.ListSilences()
+}
+
+// CreateSilence creates a new silence and returns its ID
+func (a *AlertManager) CreateSilence(silence *alertmanager.Silence) (string, error) {
+	if err := a.injector.Inject("CreateSilence"); err != nil {
+		return "", err
+	}
+	return a.next.CreateSilence(silence)
+}
+
+// UpdateSilence updates an existing silence
+func (a *AlertManager) UpdateSilence(silence *alertmanager.Silence) error {
+	if err := a.injector.Inject("UpdateSilence"); err != nil {
+		return err
+	}
+	return a.next.UpdateSilence(silence)
+}
+
+// DeleteSilence deletes a silence by ID
+func (a *AlertManager) DeleteSilence(id string) error {
+	if err := a.injector.Inject("DeleteSilence"); err != nil {
+		return err
+	}
+	return a.next.DeleteSilence(id)
+}
+
+// ExtendSilence extends the end time of a silence
+func (a *AlertManager) ExtendSilence(id string, newEndTime time.Time) error {
+	if err := a.injector.Inject("ExtendSilence"); err != nil {
+		return err
+	}
+	return a.next.ExtendSilence(id, newEndTime)
+}
+
+// GetAlerts returns all active alerts matching the given matchers
+func (a *AlertManager) GetAlerts(matchers []alertmanager.Matcher) ([]*alertmanager.Alert, error) {
+	if err := a.injector.Inject("GetAlerts"); err != nil {
+		return nil, err
+	}
+	return a.next.GetAlerts(matchers)
+}
+
+// GetSilencedAlerts returns the alerts currently silenced by the given silence
+func (a *AlertManager) GetSilencedAlerts(silenceID string) ([]*alertmanager.Alert, error) {
+	if err := a.injector.Inject("GetSilencedAlerts"); err != nil {
+		return nil, err
+	}
+	return a.next.GetSilencedAlerts(silenceID)
+}
+
+// ServerTime returns the current time of the Alertmanager server
+func (a *AlertManager) ServerTime() (time.Time, error) {
+	if err := a.injector.Inject("ServerTime"); err != nil {
+		return time.Time{}, err
+	}
+	return alertmanager.ServerTime(a.next)
+}
@@ -0,0 +1,179 @@
+// Package faults wraps the Alertmanager and ticket system clients to inject latency,
+// timeouts and errors, so that partial-failure handling can be exercised against real
+// backends in integration tests. It is wired into the binary only when built with the
+// faultinject build tag.
+package faults
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+var (
+	// ErrInjected is returned by operations failed by the injector. It is transient, like
+	// the backend failures it stands in for.
+	ErrInjected = apierror.New(apierror.Transient, "injected fault")
+
+	// ErrInjectedTimeout is returned by operations timed out by the injector
+	ErrInjectedTimeout = apierror.New(apierror.Transient, "injected timeout")
+)
+
+// Config holds the faults to inject
+type Config struct {
+	Latency     time.Duration // Delay added to every operation
+	Jitter      time.Duration // Random extra delay of up to Jitter
+	ErrorRate   float64       // Share of operations failing with ErrInjected (0-1)
+	TimeoutRate float64       // Share of operations failing with ErrInjectedTimeout (0-1)
+	Timeout     time.Duration // How long a timed out operation blocks before failing
+	Operations  []string      // Operations to inject faults into, e.g. "CreateSilence" (default: all)
+	Seed        int64         // Random seed; 0 uses the current time
+}
+
+// Enabled reports whether the configuration injects any fault
+func (c Config) Enabled() bool {
+	return c.Latency > 0 || c.Jitter > 0 || c.ErrorRate > 0 || c.TimeoutRate > 0
+}
+
+// Validate checks that rates and durations are in range
+func (c Config) Validate() error {
+	if c.ErrorRate < 0 || c.ErrorRate > 1 {
+		return fmt.Errorf("error rate must be between 0 and 1, got %v", c.ErrorRate)
+	}
+	if c.TimeoutRate < 0 || c.TimeoutRate > 1 {
+		return fmt.Errorf("timeout rate must be between 0 and 1, got %v", c.TimeoutRate)
+	}
+	if c.Latency < 0 || c.Jitter < 0 || c.Timeout < 0 {
+		return fmt.Errorf("latency, jitter and timeout must not be negative")
+	}
+	return nil
+}
+
+// ConfigFromEnv reads the fault configuration from FAULT_INJECTION_* environment variables
+func ConfigFromEnv() (Config, error) {
+	var cfg Config
+	var err error
+
+	if cfg.Latency, err = envDuration("FAULT_INJECTION_LATENCY"); err != nil {
+		return cfg, err
+	}
+	if cfg.Jitter, err = envDuration("FAULT_INJECTION_JITTER"); err != nil {
+		return cfg, err
+	}
+	if cfg.ErrorRate, err = envFloat("FAULT_INJECTION_ERROR_RATE"); err != nil {
+		return cfg, err
+	}
+	if cfg.TimeoutRate, err = envFloat("FAULT_INJECTION_TIMEOUT_RATE"); err != nil {
+		return cfg, err
+	}
+	if cfg.Timeout, err = envDuration("FAULT_INJECTION_TIMEOUT"); err != nil {
+		return cfg, err
+	}
+	if cfg.TimeoutRate > 0 && cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if value := os.Getenv("FAULT_INJECTION_SEED"); value != "" {
+		if cfg.Seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return cfg, fmt.Errorf("invalid FAULT_INJECTION_SEED %q: %w", value, err)
+		}
+	}
+	for _, op := range strings.Split(os.Getenv("FAULT_INJECTION_OPERATIONS"), ",") {
+		if op = strings.TrimSpace(op); op != "" {
+			cfg.Operations = append(cfg.Operations, op)
+		}
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func envDuration(key string) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return d, nil
+}
+
+func envFloat(key string) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return f, nil
+}
+
+// Injector decides which operations are delayed or failed
+type Injector struct {
+	cfg        Config
+	operations map[string]bool
+	sleep      func(time.Duration)
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewInjector creates an injector for the given configuration
+func NewInjector(cfg Config) *Injector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	var operations map[string]bool
+	if len(cfg.Operations) > 0 {
+		operations = make(map[string]bool, len(cfg.Operations))
+		for _, op := range cfg.Operations {
+			operations[op] = true
+		}
+	}
+
+	return &Injector{
+		cfg:        cfg,
+		operations: operations,
+		sleep:      time.Sleep,
+		rand:       rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Inject applies the configured latency to the operation and returns an error if the
+// operation should fail
+func (i *Injector) Inject(op string) error {
+	if i.operations != nil && !i.operations[op] {
+		return nil
+	}
+
+	i.mu.Lock()
+	delay := i.cfg.Latency
+	if i.cfg.Jitter > 0 {
+		delay += time.Duration(i.rand.Int63n(int64(i.cfg.Jitter)))
+	}
+	roll := i.rand.Float64()
+	i.mu.Unlock()
+
+	if delay > 0 {
+		i.sleep(delay)
+	}
+
+	switch {
+	case roll < i.cfg.TimeoutRate:
+		i.sleep(i.cfg.Timeout)
+		return fmt.Errorf("%s: %w after %v", op, ErrInjectedTimeout, i.cfg.Timeout)
+	case roll < i.cfg.TimeoutRate+i.cfg.ErrorRate:
+		return fmt.Errorf("%s: %w", op, ErrInjected)
+	}
+	return nil
+}
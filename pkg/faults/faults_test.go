@@ -0,0 +1,129 @@
+package faults
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// stubAlertManager counts GetSilence calls and panics on anything else
+type stubAlertManager struct {
+	alertmanager.AlertManager
+	calls int
+}
+
+func (s *stubAlertManager) GetSilence(id string) (*alertmanager.Silence, error) {
+	s.calls++
+	return &alertmanager.Silence{ID: id}, nil
+}
+
+func newTestInjector(cfg Config) (*Injector, *time.Duration) {
+	var slept time.Duration
+	i := NewInjector(cfg)
+	i.sleep = func(d time.Duration) { slept += d }
+	return i, &slept
+}
+
+func TestInject_ErrorRate(t *testing.T) {
+	always, _ := newTestInjector(Config{ErrorRate: 1, Seed: 1})
+	if err := always.Inject("GetSilence"); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected ErrInjected, got %v", err)
+	}
+
+	never, _ := newTestInjector(Config{Seed: 1})
+	for n := 0; n < 100; n++ {
+		if err := never.Inject("GetSilence"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+func TestInject_ErrorRateIsApproximate(t *testing.T) {
+	i, _ := newTestInjector(Config{ErrorRate: 0.3, Seed: 42})
+	failures := 0
+	for n := 0; n < 1000; n++ {
+		if i.Inject("GetSilence") != nil {
+			failures++
+		}
+	}
+	if failures < 250 || failures > 350 {
+		t.Errorf("Expected roughly 300 failures out of 1000, got %d", failures)
+	}
+}
+
+func TestInject_LatencyAndTimeout(t *testing.T) {
+	i, slept := newTestInjector(Config{Latency: 200 * time.Millisecond, TimeoutRate: 1, Timeout: 5 * time.Second, Seed: 1})
+	err := i.Inject("ListSilences")
+	if !errors.Is(err, ErrInjectedTimeout) {
+		t.Errorf("Expected ErrInjectedTimeout, got %v", err)
+	}
+	if *slept != 5200*time.Millisecond {
+		t.Errorf("Expected 5.2s of delay, got %v", *slept)
+	}
+}
+
+func TestInject_Operations(t *testing.T) {
+	i, _ := newTestInjector(Config{ErrorRate: 1, Operations: []string{"CreateSilence"}, Seed: 1})
+	if err := i.Inject("GetSilence"); err != nil {
+		t.Errorf("Expected GetSilence to be unaffected, got %v", err)
+	}
+	if err := i.Inject("CreateSilence"); err == nil {
+		t.Error("Expected CreateSilence to fail")
+	}
+}
+
+func TestWrapAlertManager(t *testing.T) {
+	stub := &stubAlertManager{}
+	i, _ := newTestInjector(Config{ErrorRate: 1, Seed: 1})
+	am := WrapAlertManager(stub, i)
+
+	if _, err := am.GetSilence("silence-1"); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected ErrInjected, got %v", err)
+	}
+	if stub.calls != 0 {
+		t.Errorf("Expected failed call not to reach the backend, got %d calls", stub.calls)
+	}
+
+	i.cfg.ErrorRate = 0
+	silence, err := am.GetSilence("silence-1")
+	if err != nil || silence.ID != "silence-1" || stub.calls != 1 {
+		t.Errorf("Expected call to pass through, got silence=%v err=%v calls=%d", silence, err, stub.calls)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	os.Setenv("FAULT_INJECTION_LATENCY", "250ms")
+	os.Setenv("FAULT_INJECTION_ERROR_RATE", "0.2")
+	os.Setenv("FAULT_INJECTION_TIMEOUT_RATE", "0.1")
+	os.Setenv("FAULT_INJECTION_OPERATIONS", "CreateSilence, AddComment")
+	defer func() {
+		for _, key := range []string{"FAULT_INJECTION_LATENCY", "FAULT_INJECTION_ERROR_RATE", "FAULT_INJECTION_TIMEOUT_RATE", "FAULT_INJECTION_OPERATIONS"} {
+			os.Unsetenv(key)
+		}
+	}()
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv() failed: %v", err)
+	}
+	if !cfg.Enabled() {
+		t.Error("Expected fault injection to be enabled")
+	}
+	if cfg.Latency != 250*time.Millisecond || cfg.ErrorRate != 0.2 || cfg.TimeoutRate != 0.1 {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Expected timeout to default to 30s, got %v", cfg.Timeout)
+	}
+	if len(cfg.Operations) != 2 || cfg.Operations[1] != "AddComment" {
+		t.Errorf("Unexpected operations: %v", cfg.Operations)
+	}
+
+	os.Setenv("FAULT_INJECTION_ERROR_RATE", "1.5")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("Expected error for an error rate above 1")
+	}
+}
@@ -0,0 +1,181 @@
+package faults
+
+import (
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// TicketSystem wraps a ticket system client with fault injection. Status checks do
+// not perform I/O and are passed through unchanged.
+type TicketSystem struct {
+	next     ticket.TicketSystem
+	injector *Injector
+}
+
+// WrapTicketSystem returns a ticket system client that injects faults before calling next
+func WrapTicketSystem(next ticket.TicketSystem, injector *Injector) *TicketSystem {
+	return &TicketSystem{next: next, injector: injector}
+}
+
+// GetTicket retrieves a ticket by its key
+func (t *TicketSystem) GetTicket(key string) (*ticket.Ticket, error) {
+	if err := t.injector.Inject("GetTicket"); err != nil {
+		return nil, err
+	}
+	return t.next.GetTicket(key)
+}
+
+// GetTickets retrieves several tickets at once, keyed by ticket key
+func (t *TicketSystem) GetTickets(keys []string) (map[string]*ticket.Ticket, error) {
+	if err := t.injector.Inject("GetTickets"); err != nil {
+		return nil, err
+	}
+	return t.next.GetTickets(keys)
+}
+
+// SearchTickets returns all tickets matching a query
+func (t *TicketSystem) SearchTickets(query string) ([]*ticket.Ticket, error) {
+	if err := t.injector.Inject("SearchTickets"); err != nil {
+		return nil, err
+	}
+	return t.next.SearchTickets(query)
+}
+
+// CreateTicket creates a new ticket and returns its key
+func (t *TicketSystem) CreateTicket(tkt *ticket.Ticket) (string, error) {
+	if err := t.injector.Inject("CreateTicket"); err != nil {
+		return "", err
+	}
+	return t.next.CreateTicket(tkt)
+}
+
+// UpdateTicket updates an existing ticket
+func (t *TicketSystem) UpdateTicket(tkt *ticket.Ticket) error {
+	if err := t.injector.Inject("UpdateTicket"); err != nil {
+		return err
+	}
+	return t.next.UpdateTicket(tkt)
+}
+
+// UpdateLabels adds and removes labels on a ticket
+func (t *TicketSystem) UpdateLabels(key string, add, remove []string) error {
+	if err := t.injector.Inject("UpdateLabels"); err != nil {
+		return err
+	}
+	return t.next.UpdateLabels(key, add, remove)
+}
+
+// SetPriority changes the priority of a ticket
+func (t *TicketSystem) SetPriority(key string, priority string) error {
+	if err := t.injector.Inject("SetPriority"); err != nil {
+		return err
+	}
+	return t.next.SetPriority(key, priority)
+}
+
+// SetDueDate sets the due date of a ticket
+func (t *TicketSystem) SetDueDate(key string, due time.Time) error {
+	if err := t.injector.Inject("SetDueDate"); err != nil {
+		return err
+	}
+	return t.next.SetDueDate(key, due)
+}
+
+// AssignTicket assigns a ticket to a user
+func (t *TicketSystem) AssignTicket(key string, user string) error {
+	if err := t.injector.Inject("AssignTicket"); err != nil {
+		return err
+	}
+	return t.next.AssignTicket(key, user)
+}
+
+// AddWatcher adds a user to the watchers of a ticket
+func (t *TicketSystem) AddWatcher(key string, user string) error {
+	if err := t.injector.Inject("AddWatcher"); err != nil {
+		return err
+	}
+	return t.next.AddWatcher(key, user)
+}
+
+// SetRemoteLink adds a link to an external resource
+func (t *TicketSystem) SetRemoteLink(key string, link ticket.RemoteLink) error {
+	if err := t.injector.Inject("SetRemoteLink"); err != nil {
+		return err
+	}
+	return t.next.SetRemoteLink(key, link)
+}
+
+// DeleteRemoteLink removes a link to an external resource
+func (t *TicketSystem) DeleteRemoteLink(key string, globalID string) error {
+	if err := t.injector.Inject("DeleteRemoteLink"); err != nil {
+		return err
+	}
+	return t.next.DeleteRemoteLink(key, globalID)
+}
+
+// LinkTickets links two tickets
+func (t *TicketSystem) LinkTickets(key, linkedKey, linkType string) error {
+	if err := t.injector.Inject("LinkTickets"); err != nil {
+		return err
+	}
+	return t.next.LinkTickets(key, linkedKey, linkType)
+}
+
+// ReopenTicket reopens a closed/resolved ticket
+func (t *TicketSystem) ReopenTicket(key string, comment string) error {
+	if err := t.injector.Inject("ReopenTicket"); err != nil {
+		return err
+	}
+	return t.next.ReopenTicket(key, comment)
+}
+
+// CloseTicket marks a ticket as closed
+func (t *TicketSystem) CloseTicket(key string, comment string) error {
+	if err := t.injector.Inject("CloseTicket"); err != nil {
+		return err
+	}
+	return t.next.CloseTicket(key, comment)
+}
+
+// AddComment adds a comment to a ticket
+func (t *TicketSystem) AddComment(key string, comment string) error {
+	if err := t.injector.Inject("AddComment"); err != nil {
+		return err
+	}
+	return t.next.AddComment(key, comment)
+}
+
+// GetComments returns all comments on a ticket, oldest first
+func (t *TicketSystem) GetComments(key string) ([]*ticket.Comment, error) {
+	if err := t.injector.Inject("GetComments"); err != nil {
+		return nil, err
+	}
+	return t.next.GetComments(key)
+}
+
+// CurrentUser returns the user the wrapped client comments as, if it reports one
+func (t *TicketSystem) CurrentUser() (string, error) {
+	if err := t.injector.Inject("CurrentUser"); err != nil {
+		return "", err
+	}
+	if cu, ok := t.next.(interface{ CurrentUser() (string, error) }); ok {
+		return cu.CurrentUser()
+	}
+	return "", nil
+}
+
+// IsResolved checks if a ticket is in a resolved state
+func (t *TicketSystem) IsResolved(tkt *ticket.Ticket) bool {
+	return t.next.IsResolved(tkt)
+}
+
+// IsClosed checks if a ticket is in a closed state
+func (t *TicketSystem) IsClosed(tkt *ticket.Ticket) bool {
+	return t.next.IsClosed(tkt)
+}
+
+// IsOpen checks if a ticket is in an open state
+func (t *TicketSystem) IsOpen(tkt *ticket.Ticket) bool {
+	return t.next.IsOpen(tkt)
+}
@@ -0,0 +1,141 @@
+package gitops
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Definition is a silence declared in a file of the repository
+type Definition struct {
+	// Name identifies the definition across runs; renaming it replaces the silence
+	Name     string   `json:"name"`
+	Matchers []string `json:"matchers"` // e.g. alertname=DiskFull or instance=~"db-.*"
+	Ticket   string   `json:"ticket,omitempty"`
+	Summary  string   `json:"summary,omitempty"` // Summary of the ticket created without Ticket
+	Comment  string   `json:"comment,omitempty"`
+	Duration string   `json:"duration,omitempty"` // e.g. 30d; defaults to the default silence duration
+
+	// File is the path of the declaring file, relative to the directory
+	File string `json:"-"`
+}
+
+// Source provides the declared silences
+type Source interface {
+	Definitions() ([]Definition, error)
+}
+
+// Directory reads definitions from the YAML files below a directory, such as a Git
+// checkout kept up to date by a git-sync sidecar. Each file holds a list of definitions
+// or an object with a "silences" list. Hidden files and directories, such as .git, are
+// skipped.
+type Directory struct {
+	path string
+}
+
+// NewDirectory creates a source reading the YAML files below path
+func NewDirectory(path string) *Directory {
+	return &Directory{path: path}
+}
+
+// Definitions reads and validates every definition. Any invalid file fails the whole
+// read, so that a broken commit never prunes the silences it did not declare.
+func (d *Directory) Definitions() ([]Definition, error) {
+	if _, err := os.Stat(d.path); err != nil {
+		return nil, fmt.Errorf("failed to read silence definitions: %w", err)
+	}
+
+	var defs []Definition
+	seen := make(map[string]string)
+	err := filepath.WalkDir(d.path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && path != d.path {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !isYAML(entry.Name()) {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(d.path, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fileDefs, err := Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		for _, def := range fileDefs {
+			if other, ok := seen[def.Name]; ok {
+				return fmt.Errorf("%s: silence %q is already declared in %s", rel, def.Name, other)
+			}
+			seen[def.Name] = rel
+			def.File = rel
+			defs = append(defs, def)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silence definitions: %w", err)
+	}
+
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs, nil
+}
+
+// Parse parses the definitions of one file, either a list or an object with a
+// "silences" list. Unknown fields are rejected to catch typos in reviewed files.
+func Parse(data []byte) ([]Definition, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	if string(jsonData) == "null" {
+		return nil, nil // Empty file
+	}
+
+	var defs []Definition
+	if strings.HasPrefix(string(jsonData), "[") {
+		err = yaml.UnmarshalStrict(data, &defs)
+	} else {
+		var doc struct {
+			Silences []Definition `json:"silences"`
+		}
+		err = yaml.UnmarshalStrict(data, &doc)
+		defs = doc.Silences
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid silence definitions: %w", err)
+	}
+
+	for i, def := range defs {
+		if def.Name == "" {
+			return nil, fmt.Errorf("silence %d has no name", i+1)
+		}
+		if len(def.Matchers) == 0 {
+			return nil, fmt.Errorf("silence %q has no matchers", def.Name)
+		}
+	}
+	return defs, nil
+}
+
+func isYAML(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// String names the definition and its file for logs and ticket comments
+func (d Definition) String() string {
+	return fmt.Sprintf("%q (%s)", d.Name, d.File)
+}
@@ -0,0 +1,100 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirectory_Definitions(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "storage.yaml"), `
+silences:
+  - name: disk-full
+    matchers: ["alertname=DiskFull", 'instance=~"db-.*"']
+    ticket: OPS-12
+    duration: 30d
+`)
+	writeFile(t, filepath.Join(dir, "teams", "network.yml"), `
+- name: network-flapping
+  matchers: [team=network]
+  summary: Flapping links
+`)
+	writeFile(t, filepath.Join(dir, "empty.yaml"), "")
+	writeFile(t, filepath.Join(dir, "README.md"), "not a definition")
+	writeFile(t, filepath.Join(dir, ".git", "config.yaml"), "not: [a definition")
+
+	defs, err := NewDirectory(dir).Definitions()
+	if err != nil {
+		t.Fatalf("Definitions() failed: %v", err)
+	}
+	if len(defs) != 2 {
+		t.Fatalf("Expected 2 definitions, got %+v", defs)
+	}
+	if defs[0].Name != "disk-full" || defs[0].Ticket != "OPS-12" || defs[0].Duration != "30d" || defs[0].File != "storage.yaml" {
+		t.Errorf("Unexpected definition: %+v", defs[0])
+	}
+	if len(defs[0].Matchers) != 2 || defs[0].Matchers[1] != `instance=~"db-.*"` {
+		t.Errorf("Unexpected matchers: %v", defs[0].Matchers)
+	}
+	if defs[1].Name != "network-flapping" || defs[1].File != filepath.Join("teams", "network.yml") {
+		t.Errorf("Unexpected definition: %+v", defs[1])
+	}
+}
+
+func TestDirectory_DefinitionsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "duplicate name",
+			files:   map[string]string{"a.yaml": "- {name: x, matchers: [a=b]}", "b.yaml": "- {name: x, matchers: [c=d]}"},
+			wantErr: "already declared in a.yaml",
+		},
+		{
+			name:    "missing name",
+			files:   map[string]string{"a.yaml": "- {matchers: [a=b]}"},
+			wantErr: "has no name",
+		},
+		{
+			name:    "missing matchers",
+			files:   map[string]string{"a.yaml": "- {name: x}"},
+			wantErr: "has no matchers",
+		},
+		{
+			name:    "unknown field",
+			files:   map[string]string{"a.yaml": "- {name: x, matchers: [a=b], expires: 30d}"},
+			wantErr: "invalid silence definitions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(dir, name), content)
+			}
+			_, err := NewDirectory(dir).Definitions()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := NewDirectory(filepath.Join(t.TempDir(), "missing")).Definitions(); err == nil {
+		t.Error("Expected error for a missing directory")
+	}
+}
@@ -0,0 +1,239 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take once the server stops
+const shutdownTimeout = 5 * time.Second
+
+// Check probes whether a backend is reachable
+type Check struct {
+	Name  string
+	Probe func() error
+}
+
+// Backend is the outcome of the latest probe of a backend
+type Backend struct {
+	Reachable bool      `json:"reachable"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Run summarizes a completed synchronization run
+type Run struct {
+	Started  time.Time      `json:"started"`
+	Finished time.Time      `json:"finished"`
+	Counts   map[string]int `json:"counts,omitempty"`
+	Errors   int            `json:"errors"`          // Errors of individual silences and tickets
+	Error    string         `json:"error,omitempty"` // Set when the run failed entirely
+}
+
+// Status is reported by the /status endpoint
+type Status struct {
+	Mode        string             `json:"mode"`
+	Version     string             `json:"version"`
+	StartedAt   time.Time          `json:"startedAt"`
+	LastRun     *Run               `json:"lastRun,omitempty"`
+	LastSuccess *time.Time         `json:"lastSuccess,omitempty"`
+	Backends    map[string]Backend `json:"backends"`
+}
+
+// Server serves liveness, readiness and status endpoints for the daemon and operator:
+//
+//   - /healthz fails when no cycle completed within the maximum idle time
+//   - /readyz fails until all backends were probed reachable, and after a run failed entirely
+//   - /status reports the last run, the last successful run and the reachability of each backend
+type Server struct {
+	checks  []Check
+	maxIdle time.Duration
+	now     func() time.Time
+	routes  map[string]http.Handler
+
+	mu       sync.Mutex
+	status   Status
+	lastBeat time.Time
+}
+
+// NewServer creates a status server probing checks. A positive maxIdle fails liveness
+// when Beat or RecordRun was not called for that long.
+func NewServer(mode, version string, maxIdle time.Duration, checks []Check) *Server {
+	return newServer(mode, version, maxIdle, checks, time.Now)
+}
+
+func newServer(mode, version string, maxIdle time.Duration, checks []Check, now func() time.Time) *Server {
+	started := now()
+	return &Server{
+		checks:   checks,
+		maxIdle:  maxIdle,
+		now:      now,
+		routes:   map[string]http.Handler{},
+		lastBeat: started,
+		status: Status{
+			Mode:      mode,
+			Version:   version,
+			StartedAt: started,
+			Backends:  map[string]Backend{},
+		},
+	}
+}
+
+// Beat records that a cycle completed, including cycles that skipped the run
+func (s *Server) Beat() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastBeat = s.now()
+}
+
+// RecordRun records a completed synchronization run. err is the error that failed the
+// run entirely, and failures the number of individual errors of a completed run.
+func (s *Server) RecordRun(started time.Time, counts map[string]int, failures int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	finished := s.now()
+	s.lastBeat = finished
+	run := &Run{Started: started, Finished: finished, Counts: counts, Errors: failures}
+	if err != nil {
+		run.Error = err.Error()
+	} else if failures == 0 {
+		s.status.LastSuccess = &finished
+	}
+	s.status.LastRun = run
+}
+
+// Probe checks the reachability of every backend
+func (s *Server) Probe() {
+	for _, check := range s.checks {
+		err := check.Probe()
+		backend := Backend{Reachable: err == nil, CheckedAt: s.now()}
+		if err != nil {
+			backend.Error = err.Error()
+		}
+
+		s.mu.Lock()
+		s.status.Backends[check.Name] = backend
+		s.mu.Unlock()
+	}
+}
+
+// Handle serves handler on pattern alongside the health endpoints, e.g. /metrics. It must
+// be called before Handler or Run.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.routes[pattern] = handler
+}
+
+// Status returns a copy of the current status
+func (s *Server) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Backends = make(map[string]Backend, len(s.status.Backends))
+	for name, backend := range s.status.Backends {
+		status.Backends[name] = backend
+	}
+	return status
+}
+
+// live reports why the process is not live, or nil
+func (s *Server) live() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxIdle > 0 {
+		if idle := s.now().Sub(s.lastBeat); idle > s.maxIdle {
+			return fmt.Errorf("no cycle completed for %v", idle.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// ready reports why the process is not ready, or nil
+func (s *Server) ready() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, check := range s.checks {
+		backend, ok := s.status.Backends[check.Name]
+		if !ok {
+			return fmt.Errorf("%s not probed yet", check.Name)
+		}
+		if !backend.Reachable {
+			return fmt.Errorf("%s unreachable: %s", check.Name, backend.Error)
+		}
+	}
+	if run := s.status.LastRun; run != nil && run.Error != "" {
+		return fmt.Errorf("last run failed: %s", run.Error)
+	}
+	return nil
+}
+
+// Handler returns the HTTP handler serving /healthz, /readyz, /status and the routes
+// added with Handle
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for pattern, handler := range s.routes {
+		mux.Handle(pattern, handler)
+	}
+	mux.HandleFunc("/healthz", probeHandler(s.live))
+	mux.HandleFunc("/readyz", probeHandler(s.ready))
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.Status()); err != nil {
+			log.Printf("Warning: failed to write status: %v", err)
+		}
+	})
+	return mux
+}
+
+// probeHandler answers 200 when check passes and 503 with the reason otherwise
+func probeHandler(check func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := check(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	}
+}
+
+// Run serves the endpoints on addr and probes the backends every probeInterval until ctx
+// is done
+func (s *Server) Run(ctx context.Context, addr string, probeInterval time.Duration) error {
+	server := &http.Server{Addr: addr, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		ticker := time.NewTicker(probeInterval)
+		defer ticker.Stop()
+		for {
+			s.Probe()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve health endpoints on %s: %w", addr, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestServer_Liveness(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newServer("daemon", "test", time.Hour, nil, func() time.Time { return now })
+	handler := s.Handler()
+
+	if rec := get(t, handler, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected live after start, got %d", rec.Code)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if rec := get(t, handler, "/healthz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected not live after 2h without a cycle, got %d", rec.Code)
+	}
+
+	s.Beat()
+	if rec := get(t, handler, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected live after a cycle, got %d", rec.Code)
+	}
+}
+
+func TestServer_Readiness(t *testing.T) {
+	var jiraErr error
+	s := NewServer("daemon", "test", 0, []Check{
+		{Name: "Alertmanager", Probe: func() error { return nil }},
+		{Name: "Jira", Probe: func() error { return jiraErr }},
+	})
+	handler := s.Handler()
+
+	if rec := get(t, handler, "/readyz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected not ready before probing, got %d", rec.Code)
+	}
+
+	jiraErr = errors.New("connection refused")
+	s.Probe()
+	if rec := get(t, handler, "/readyz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected not ready with Jira unreachable, got %d", rec.Code)
+	}
+
+	jiraErr = nil
+	s.Probe()
+	if rec := get(t, handler, "/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected ready with all backends reachable, got %d: %s", rec.Code, rec.Body)
+	}
+
+	s.RecordRun(time.Now(), nil, 0, errors.New("failed to list silences"))
+	if rec := get(t, handler, "/readyz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected not ready after a failed run, got %d", rec.Code)
+	}
+}
+
+func TestServer_Status(t *testing.T) {
+	s := NewServer("daemon", "1.2.3", 0, []Check{
+		{Name: "Jira", Probe: func() error { return errors.New("401 Unauthorized") }},
+	})
+	s.Probe()
+	s.RecordRun(time.Now(), map[string]int{"silencesExtended": 2}, 1, nil)
+
+	rec := get(t, s.Handler(), "/status")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+
+	if status.Mode != "daemon" || status.Version != "1.2.3" {
+		t.Errorf("Unexpected mode %q and version %q", status.Mode, status.Version)
+	}
+	if status.LastRun == nil || status.LastRun.Counts["silencesExtended"] != 2 || status.LastRun.Errors != 1 {
+		t.Errorf("Unexpected last run: %+v", status.LastRun)
+	}
+	if status.LastSuccess != nil {
+		t.Errorf("Expected no successful run with errors, got %v", status.LastSuccess)
+	}
+	if jira := status.Backends["Jira"]; jira.Reachable || jira.Error != "401 Unauthorized" {
+		t.Errorf("Unexpected Jira backend: %+v", jira)
+	}
+}
+
+func TestServer_Handle(t *testing.T) {
+	s := NewServer("daemon", "test", 0, nil)
+	s.Handle("/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("silence_manager_build_info 1\n"))
+	}))
+
+	rec := get(t, s.Handler(), "/metrics")
+	if rec.Code != http.StatusOK || rec.Body.String() != "silence_manager_build_info 1\n" {
+		t.Errorf("Unexpected /metrics response %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,82 @@
+package heartbeat
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultTimeout bounds a single ping, so that an unreachable monitor cannot hold up a run
+const defaultTimeout = 10 * time.Second
+
+// Config holds configuration for pinging a dead man's switch such as healthchecks.io,
+// Dead Man's Snitch or any monitor expecting a request to a URL
+type Config struct {
+	URL    string // Ping URL; it usually embeds the check's secret token
+	Method string // HTTP method, GET if empty
+	// TLSConfig, when set, is used for HTTPS connections instead of the defaults
+	TLSConfig *tls.Config
+}
+
+// Pinger reports successful runs to a dead man's switch, which alerts when the pings stop
+type Pinger struct {
+	url    string
+	method string
+	client *http.Client
+}
+
+// NewPinger creates a pinger for the monitor at cfg.URL
+func NewPinger(cfg Config) *Pinger {
+	method := cfg.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSConfig != nil {
+		transport.TLSClientConfig = cfg.TLSConfig
+	}
+	return &Pinger{
+		url:    cfg.URL,
+		method: method,
+		client: &http.Client{Transport: transport, Timeout: defaultTimeout},
+	}
+}
+
+// Ping sends the heartbeat. Errors never include the URL, which is a secret of the check.
+func (p *Pinger) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create heartbeat request")
+	}
+	req.Header.Set("User-Agent", "silence-manager")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// Unwrap the url.Error, which quotes the URL
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send heartbeat: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d sending heartbeat", resp.StatusCode)
+	}
+	return nil
+}
+
+// Host returns the host of the monitor, for logging without the secret parts of the URL
+func (p *Pinger) Host() string {
+	u, err := url.Parse(p.url)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
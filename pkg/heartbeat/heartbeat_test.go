@@ -0,0 +1,50 @@
+package heartbeat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPinger_Ping(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	if err := NewPinger(Config{URL: server.URL + "/5f3c-check"}).Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() failed: %v", err)
+	}
+	if method != http.MethodGet || path != "/5f3c-check" {
+		t.Errorf("Expected GET /5f3c-check, got %s %s", method, path)
+	}
+
+	if err := NewPinger(Config{URL: server.URL + "/5f3c-check", Method: http.MethodPost}).Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() failed: %v", err)
+	}
+	if method != http.MethodPost {
+		t.Errorf("Expected POST, got %s", method)
+	}
+}
+
+func TestPinger_PingErrorsOmitURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+
+	pinger := NewPinger(Config{URL: server.URL + "/secret-token"})
+	err := pinger.Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+
+	server.Close()
+	err = pinger.Ping(context.Background())
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected an error without the URL, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package incident
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// defaultTimeout is the HTTP timeout used by the incident provider clients
+const defaultTimeout = 30 * time.Second
+
+// Event describes failing runs to the incident provider
+type Event struct {
+	Summary string            // One line shown as the incident title
+	Details map[string]string // Shown with the incident
+}
+
+// Notifier raises and resolves the incident of failing runs. Every run that breaches the
+// policy triggers again; providers deduplicate the events into a single open incident.
+type Notifier interface {
+	// Trigger raises the incident, or updates it if it is already open
+	Trigger(ctx context.Context, event Event) error
+
+	// Resolve closes the incident
+	Resolve(ctx context.Context) error
+}
+
+// Run is the outcome of a synchronization run
+type Run struct {
+	Started  time.Time
+	Aborted  bool // The run stopped early, e.g. because Alertmanager was unreachable
+	Errors   int
+	Silences int // Silences processed by the run
+}
+
+// Failed reports whether the run failed, by stopping early or with errors
+func (r Run) Failed() bool {
+	return r.Aborted || r.Errors > 0
+}
+
+// ErrorRate returns the fraction of errors per processed silence. Runs that stopped early
+// or failed without processing any silence have an error rate of 1.
+func (r Run) ErrorRate() float64 {
+	if r.Aborted || (r.Errors > 0 && r.Silences == 0) {
+		return 1
+	}
+	if r.Silences == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Silences)
+}
+
+// Action is what the policy decided to do with the incident after a run
+type Action int
+
+const (
+	// ActionNone leaves the incident as it is
+	ActionNone Action = iota
+	// ActionTrigger raises or updates the incident
+	ActionTrigger
+	// ActionResolve closes the incident after a successful run
+	ActionResolve
+)
+
+// Policy decides when failing runs raise an incident
+type Policy struct {
+	ErrorRate           float64 // Error rate of a single run above which it alerts; 0 disables
+	ConsecutiveFailures int     // Failed runs in a row that alert; 0 disables
+}
+
+// Evaluate records the run and returns what to do with the incident. An incident stays
+// open until a run succeeds, even if later failing runs no longer breach the policy.
+func (p Policy) Evaluate(record *state.FailureRecord, run Run) (Action, string) {
+	if !run.Failed() {
+		alerting := record.Alerting
+		*record = state.FailureRecord{}
+		if alerting {
+			return ActionResolve, ""
+		}
+		return ActionNone, ""
+	}
+
+	if record.Consecutive == 0 {
+		record.Since = run.Started
+	}
+	record.Consecutive++
+
+	switch {
+	case p.ErrorRate > 0 && run.ErrorRate() > p.ErrorRate:
+		return ActionTrigger, fmt.Sprintf("error rate %.0f%% exceeds %.0f%%", run.ErrorRate()*100, p.ErrorRate*100)
+	case p.ConsecutiveFailures > 0 && record.Consecutive >= p.ConsecutiveFailures:
+		return ActionTrigger, fmt.Sprintf("%d consecutive runs failed", record.Consecutive)
+	}
+	return ActionNone, ""
+}
+
+// send posts a JSON event and checks that the provider accepted it
+func send(client *http.Client, req *http.Request) error {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package incident
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+func TestPolicy_ConsecutiveFailures(t *testing.T) {
+	policy := Policy{ConsecutiveFailures: 3}
+	record := &state.FailureRecord{}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		if action, _ := policy.Evaluate(record, Run{Started: start.Add(time.Duration(i) * time.Hour), Errors: 1, Silences: 10}); action != ActionNone {
+			t.Fatalf("Run %d: expected no action before the threshold, got %v", i+1, action)
+		}
+	}
+	action, reason := policy.Evaluate(record, Run{Started: start.Add(2 * time.Hour), Aborted: true})
+	if action != ActionTrigger || reason != "3 consecutive runs failed" {
+		t.Fatalf("Expected a trigger on the third failed run, got %v %q", action, reason)
+	}
+	if !record.Since.Equal(start) {
+		t.Errorf("Expected failures since %v, got %v", start, record.Since)
+	}
+
+	record.Alerting = true
+	if action, _ := policy.Evaluate(record, Run{Silences: 10}); action != ActionResolve {
+		t.Errorf("Expected a successful run to resolve the incident, got %v", action)
+	}
+	if record.Consecutive != 0 || record.Alerting {
+		t.Errorf("Expected the record to be reset, got %+v", record)
+	}
+	if action, _ := policy.Evaluate(record, Run{Silences: 10}); action != ActionNone {
+		t.Errorf("Expected no action for a successful run without an incident, got %v", action)
+	}
+}
+
+func TestPolicy_ErrorRate(t *testing.T) {
+	policy := Policy{ErrorRate: 0.2}
+	record := &state.FailureRecord{}
+
+	if action, _ := policy.Evaluate(record, Run{Errors: 1, Silences: 10}); action != ActionNone {
+		t.Errorf("Expected no action for an error rate of 10%%, got %v", action)
+	}
+	action, reason := policy.Evaluate(record, Run{Errors: 3, Silences: 10})
+	if action != ActionTrigger || reason != "error rate 30% exceeds 20%" {
+		t.Errorf("Expected a trigger for an error rate of 30%%, got %v %q", action, reason)
+	}
+	if action, _ := policy.Evaluate(record, Run{Aborted: true}); action != ActionTrigger {
+		t.Errorf("Expected an aborted run to breach the error rate, got %v", action)
+	}
+}
+
+func TestPagerDuty_TriggerAndResolve(t *testing.T) {
+	var events []pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/enqueue" {
+			t.Errorf("Expected path '/v2/enqueue', got '%s'", r.URL.Path)
+		}
+		var event pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pd := NewPagerDuty(server.URL, "routing-key", "prod")
+	if err := pd.Trigger(context.Background(), Event{Summary: "3 consecutive runs failed", Details: map[string]string{"errors": "2"}}); err != nil {
+		t.Fatalf("Trigger() failed: %v", err)
+	}
+	if err := pd.Resolve(context.Background()); err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	trigger, resolve := events[0], events[1]
+	if trigger.EventAction != "trigger" || trigger.RoutingKey != "routing-key" || trigger.Payload == nil ||
+		trigger.Payload.Summary != "3 consecutive runs failed" || trigger.Payload.Source != "prod" {
+		t.Errorf("Unexpected trigger event: %+v", trigger)
+	}
+	if resolve.EventAction != "resolve" || resolve.DedupKey != trigger.DedupKey || resolve.Payload != nil {
+		t.Errorf("Expected the resolve event to share the dedup key %q, got %+v", trigger.DedupKey, resolve)
+	}
+}
+
+func TestOpsgenie_TriggerAndResolve(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "GenieKey api-key" {
+			t.Errorf("Unexpected Authorization header %q", got)
+		}
+		paths = append(paths, r.URL.RequestURI())
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	og := NewOpsgenie(server.URL, "api-key", "prod")
+	if err := og.Trigger(context.Background(), Event{Summary: "error rate 30% exceeds 20%"}); err != nil {
+		t.Fatalf("Trigger() failed: %v", err)
+	}
+	if err := og.Resolve(context.Background()); err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	if len(paths) != 2 || paths[0] != "/v2/alerts" || paths[1] != "/v2/alerts/prod%2Fsync-failure/close?identifierType=alias" {
+		t.Errorf("Unexpected requests: %v", paths)
+	}
+}
+
+func TestSend_RejectedEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"status":"invalid event"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if err := NewPagerDuty(server.URL, "routing-key", "prod").Resolve(context.Background()); err == nil {
+		t.Error("Expected an error for a rejected event")
+	}
+}
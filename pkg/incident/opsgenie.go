@@ -0,0 +1,84 @@
+package incident
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultOpsgenieURL is the Opsgenie REST API base URL. EU accounts use
+// https://api.eu.opsgenie.com.
+const DefaultOpsgenieURL = "https://api.opsgenie.com"
+
+// Opsgenie raises incidents as Opsgenie alerts
+type Opsgenie struct {
+	baseURL    string
+	apiKey     string
+	source     string
+	httpClient *http.Client
+}
+
+// NewOpsgenie creates an Opsgenie client authenticating with an API integration key. The
+// source names this deployment and keys the alert.
+func NewOpsgenie(baseURL, apiKey, source string) *Opsgenie {
+	if baseURL == "" {
+		baseURL = DefaultOpsgenieURL
+	}
+	return &Opsgenie{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		source:     source,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type opsgenieAlert struct {
+	Message  string            `json:"message"`
+	Alias    string            `json:"alias"`
+	Source   string            `json:"source"`
+	Priority string            `json:"priority"`
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+// alias keys the alert, so that repeated triggers update the open alert
+func (o *Opsgenie) alias() string {
+	return o.source + "/sync-failure"
+}
+
+// Trigger creates the alert, or increases the count of the open one
+func (o *Opsgenie) Trigger(ctx context.Context, event Event) error {
+	message := event.Summary
+	if len(message) > 130 {
+		message = message[:130] // Opsgenie's limit
+	}
+	return o.post(ctx, "/v2/alerts", opsgenieAlert{
+		Message:  message,
+		Alias:    o.alias(),
+		Source:   o.source,
+		Priority: "P2",
+		Details:  event.Details,
+	})
+}
+
+// Resolve closes the alert
+func (o *Opsgenie) Resolve(ctx context.Context) error {
+	path := fmt.Sprintf("/v2/alerts/%s/close?identifierType=alias", url.PathEscape(o.alias()))
+	return o.post(ctx, path, map[string]string{"source": o.source})
+}
+
+func (o *Opsgenie) post(ctx context.Context, path string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "GenieKey "+o.apiKey)
+	return send(o.httpClient, req)
+}
@@ -0,0 +1,84 @@
+package incident
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultPagerDutyURL is the PagerDuty Events API base URL
+const DefaultPagerDutyURL = "https://events.pagerduty.com"
+
+// PagerDuty raises incidents with the PagerDuty Events API v2
+type PagerDuty struct {
+	baseURL    string
+	routingKey string
+	source     string
+	httpClient *http.Client
+}
+
+// NewPagerDuty creates a PagerDuty client sending events to the service of the
+// integration (routing) key. The source names this deployment and keys the incident.
+func NewPagerDuty(baseURL, routingKey, source string) *PagerDuty {
+	if baseURL == "" {
+		baseURL = DefaultPagerDutyURL
+	}
+	return &PagerDuty{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		routingKey: routingKey,
+		source:     source,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Component     string            `json:"component"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// Trigger raises the incident, or adds the event to the open one
+func (p *PagerDuty) Trigger(ctx context.Context, event Event) error {
+	return p.enqueue(ctx, pagerDutyEvent{
+		EventAction: "trigger",
+		Payload: &pagerDutyPayload{
+			Summary:       event.Summary,
+			Source:        p.source,
+			Severity:      "error",
+			Component:     "silence-manager",
+			CustomDetails: event.Details,
+		},
+	})
+}
+
+// Resolve resolves the incident
+func (p *PagerDuty) Resolve(ctx context.Context) error {
+	return p.enqueue(ctx, pagerDutyEvent{EventAction: "resolve"})
+}
+
+func (p *PagerDuty) enqueue(ctx context.Context, event pagerDutyEvent) error {
+	event.RoutingKey = p.routingKey
+	event.DedupKey = p.source + "/sync-failure"
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v2/enqueue", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return send(p.httpClient, req)
+}
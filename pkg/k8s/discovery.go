@@ -2,9 +2,14 @@ package k8s
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,17 +23,47 @@ type DiscoveryConfig struct {
 	ServiceLabel     string // Label selector (e.g., "app=alertmanager")
 	Port             int    // Port to connect to (default: 9093)
 	PreferNamespaces []string // Preferred namespaces to search first
+	// NamespacesOnly searches only PreferNamespaces and never lists namespaces, so that
+	// discovery needs no cluster-scoped permissions
+	NamespacesOnly bool
+	// NamespaceSelector restricts the search beyond PreferNamespaces to the namespaces
+	// matching this label selector
+	NamespaceSelector string
+	// Scheme of the discovered URL, "http" or "https"; empty detects TLS from the service
+	Scheme string
+	// HealthCheck probes each candidate and selects the first healthy one instead of the
+	// first one found
+	HealthCheck bool
+	// TLSConfig, when set, is used for health probes of https services
+	TLSConfig *tls.Config
+	// Endpoints resolves each service to its ready endpoints, e.g. the pods behind a
+	// headless service, and selects a single replica instead of the service address
+	Endpoints bool
+	// healthPaths are probed in order until one answers; set by the Discover functions
+	healthPaths []string
 }
 
+// probeTimeout bounds each health probe of a discovered service
+const probeTimeout = 5 * time.Second
+
+// schemeAnnotations select the scheme of a discovered service, in order of precedence
+var schemeAnnotations = []string{"silence-manager/scheme", "prometheus.io/scheme"}
+
 // DiscoveredService represents a discovered Alertmanager service
 type DiscoveredService struct {
 	Name      string
 	Namespace string
 	URL       string
+	// Replicas are the URLs of the ready endpoints of the service when discovering
+	// endpoints; URL is then one of them
+	Replicas []string
 }
 
 // DiscoverAlertmanager discovers Alertmanager services across all namespaces
 func DiscoverAlertmanager(cfg DiscoveryConfig) (*DiscoveredService, error) {
+	// Older Alertmanagers and some proxies only serve the API, not /-/healthy
+	cfg.healthPaths = []string{"/-/healthy", "/api/v2/status"}
+
 	// Create in-cluster config
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -41,50 +76,9 @@ func DiscoverAlertmanager(cfg DiscoveryConfig) (*DiscoveredService, error) {
 		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
 	}
 
-	ctx := context.Background()
-
-	// Search for services
-	var discoveredServices []DiscoveredService
-
-	// First, try preferred namespaces if specified
-	if len(cfg.PreferNamespaces) > 0 {
-		for _, ns := range cfg.PreferNamespaces {
-			services, err := findServicesInNamespace(ctx, clientset, ns, cfg)
-			if err != nil {
-				log.Printf("Warning: failed to search namespace %s: %v", ns, err)
-				continue
-			}
-			discoveredServices = append(discoveredServices, services...)
-			if len(discoveredServices) > 0 {
-				log.Printf("Found Alertmanager in preferred namespace: %s", ns)
-				break
-			}
-		}
-	}
-
-	// If not found in preferred namespaces, search all namespaces
-	if len(discoveredServices) == 0 {
-		log.Println("Searching all namespaces for Alertmanager services...")
-
-		// List all namespaces
-		namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to list namespaces: %w", err)
-		}
-
-		for _, ns := range namespaces.Items {
-			// Skip already-searched preferred namespaces
-			if contains(cfg.PreferNamespaces, ns.Name) {
-				continue
-			}
-
-			services, err := findServicesInNamespace(ctx, clientset, ns.Name, cfg)
-			if err != nil {
-				log.Printf("Warning: failed to search namespace %s: %v", ns.Name, err)
-				continue
-			}
-			discoveredServices = append(discoveredServices, services...)
-		}
+	discoveredServices, err := findServices(context.Background(), clientset, cfg, "Alertmanager")
+	if err != nil {
+		return nil, err
 	}
 
 	// Return results
@@ -98,17 +92,75 @@ func DiscoverAlertmanager(cfg DiscoveryConfig) (*DiscoveredService, error) {
 		log.Printf("  %d. %s/%s - %s", i+1, svc.Namespace, svc.Name, svc.URL)
 	}
 
-	// Return the first discovered service
-	selected := discoveredServices[0]
+	selected, err := selectService(discoveredServices, cfg, "Alertmanager")
+	if err != nil {
+		return nil, err
+	}
 	log.Printf("Selected Alertmanager: %s/%s - %s", selected.Namespace, selected.Name, selected.URL)
 
-	return &selected, nil
+	return selected, nil
+}
+
+// findServices searches the preferred namespaces in order, stopping at the first one with
+// matching services, and then the other namespaces unless cfg.NamespacesOnly is set
+func findServices(ctx context.Context, clientset kubernetes.Interface, cfg DiscoveryConfig, serviceName string) ([]DiscoveredService, error) {
+	var discoveredServices []DiscoveredService
+
+	// First, try preferred namespaces if specified
+	for _, ns := range cfg.PreferNamespaces {
+		services, err := findServicesInNamespace(ctx, clientset, ns, cfg)
+		if err != nil {
+			log.Printf("Warning: failed to search namespace %s: %v", ns, err)
+			continue
+		}
+		discoveredServices = append(discoveredServices, services...)
+		if len(discoveredServices) > 0 {
+			log.Printf("Found %s in preferred namespace: %s", serviceName, ns)
+			return discoveredServices, nil
+		}
+	}
+	if cfg.NamespacesOnly {
+		return nil, nil
+	}
+
+	// If not found in preferred namespaces, search all namespaces or those matching the selector
+	if cfg.NamespaceSelector != "" {
+		log.Printf("Searching namespaces matching %s for %s services...", cfg.NamespaceSelector, serviceName)
+	} else {
+		log.Printf("Searching all namespaces for %s services...", serviceName)
+	}
+	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: cfg.NamespaceSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	for _, ns := range namespaces.Items {
+		// Skip already-searched preferred namespaces
+		if contains(cfg.PreferNamespaces, ns.Name) {
+			continue
+		}
+
+		services, err := findServicesInNamespace(ctx, clientset, ns.Name, cfg)
+		if err != nil {
+			log.Printf("Warning: failed to search namespace %s: %v", ns.Name, err)
+			continue
+		}
+		discoveredServices = append(discoveredServices, services...)
+	}
+	return discoveredServices, nil
 }
 
 // findServicesInNamespace searches for Alertmanager services in a specific namespace
-func findServicesInNamespace(ctx context.Context, clientset *kubernetes.Clientset, namespace string, cfg DiscoveryConfig) ([]DiscoveredService, error) {
+func findServicesInNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string, cfg DiscoveryConfig) ([]DiscoveredService, error) {
 	var discovered []DiscoveredService
 
+	toDiscovered := func(svc corev1.Service) *DiscoveredService {
+		if cfg.Endpoints {
+			return resolveEndpoints(ctx, clientset, svc, cfg)
+		}
+		return serviceToDiscovered(svc, cfg.Port, cfg.Scheme)
+	}
+
 	// Try label selector first if provided
 	if cfg.ServiceLabel != "" {
 		services, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{
@@ -116,7 +168,7 @@ func findServicesInNamespace(ctx context.Context, clientset *kubernetes.Clientse
 		})
 		if err == nil && len(services.Items) > 0 {
 			for _, svc := range services.Items {
-				if ds := serviceToDiscovered(svc, cfg.Port); ds != nil {
+				if ds := toDiscovered(svc); ds != nil {
 					discovered = append(discovered, *ds)
 				}
 			}
@@ -133,7 +185,7 @@ func findServicesInNamespace(ctx context.Context, clientset *kubernetes.Clientse
 		for _, svc := range services.Items {
 			// Match service name (case-insensitive contains)
 			if strings.Contains(strings.ToLower(svc.Name), strings.ToLower(cfg.ServiceName)) {
-				if ds := serviceToDiscovered(svc, cfg.Port); ds != nil {
+				if ds := toDiscovered(svc); ds != nil {
 					discovered = append(discovered, *ds)
 				}
 			}
@@ -143,31 +195,26 @@ func findServicesInNamespace(ctx context.Context, clientset *kubernetes.Clientse
 	return discovered, nil
 }
 
-// serviceToDiscovered converts a Kubernetes service to a DiscoveredService
-func serviceToDiscovered(svc corev1.Service, preferredPort int) *DiscoveredService {
+// serviceToDiscovered converts a Kubernetes service to a DiscoveredService. The URL uses
+// scheme if set, otherwise https when the service or its port indicates TLS.
+func serviceToDiscovered(svc corev1.Service, preferredPort int, scheme string) *DiscoveredService {
 	// Determine the port to use
 	port := preferredPort
 	if port == 0 {
 		port = 9093 // Default Alertmanager port
 	}
 
-	// Verify the service has the port we're looking for
-	portFound := false
-	for _, p := range svc.Spec.Ports {
-		if int(p.Port) == port || p.Name == "web" || p.Name == "http" {
-			port = int(p.Port)
-			portFound = true
-			break
-		}
+	selected := servicePort(svc, port)
+	if selected != nil {
+		port = int(selected.Port)
 	}
 
-	// If preferred port not found, use first available port
-	if !portFound && len(svc.Spec.Ports) > 0 {
-		port = int(svc.Spec.Ports[0].Port)
+	if scheme == "" {
+		scheme = detectScheme(svc, selected)
 	}
 
 	// Build URL
-	url := fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", svc.Name, svc.Namespace, port)
+	url := fmt.Sprintf("%s://%s.%s.svc.cluster.local:%d", scheme, svc.Name, svc.Namespace, port)
 
 	return &DiscoveredService{
 		Name:      svc.Name,
@@ -176,6 +223,118 @@ func serviceToDiscovered(svc corev1.Service, preferredPort int) *DiscoveredServi
 	}
 }
 
+// servicePort returns the port of svc numbered port or with a web port name, falling back
+// to its first port. It returns nil when svc has no ports.
+func servicePort(svc corev1.Service, port int) *corev1.ServicePort {
+	for i, p := range svc.Spec.Ports {
+		if int(p.Port) == port || isWebPortName(p.Name) {
+			return &svc.Spec.Ports[i]
+		}
+	}
+	if len(svc.Spec.Ports) > 0 {
+		return &svc.Spec.Ports[0]
+	}
+	return nil
+}
+
+// resolveEndpoints looks up the endpoints of svc and converts them with
+// endpointsToDiscovered, logging services that cannot be resolved
+func resolveEndpoints(ctx context.Context, clientset kubernetes.Interface, svc corev1.Service, cfg DiscoveryConfig) *DiscoveredService {
+	endpoints, err := clientset.CoreV1().Endpoints(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("Warning: failed to get endpoints of %s/%s: %v", svc.Namespace, svc.Name, err)
+		return nil
+	}
+	ds := endpointsToDiscovered(svc, endpoints, cfg.Port, cfg.Scheme)
+	if ds == nil {
+		log.Printf("Skipping %s/%s: no ready endpoints", svc.Namespace, svc.Name)
+	}
+	return ds
+}
+
+// endpointsToDiscovered converts a service and its endpoints to a DiscoveredService whose
+// Replicas are the URLs of the ready endpoints and whose URL is the first of them. Pods
+// with a hostname, such as StatefulSet pods behind a headless service, are addressed by
+// their DNS name and others by IP. It returns nil when no endpoint is ready.
+func endpointsToDiscovered(svc corev1.Service, endpoints *corev1.Endpoints, preferredPort int, scheme string) *DiscoveredService {
+	if preferredPort == 0 {
+		preferredPort = 9093 // Default Alertmanager port
+	}
+	selected := servicePort(svc, preferredPort)
+	if scheme == "" {
+		scheme = detectScheme(svc, selected)
+	}
+
+	var replicas []string
+	for _, subset := range endpoints.Subsets {
+		port, ok := endpointPort(subset, selected)
+		if !ok {
+			continue
+		}
+		for _, addr := range subset.Addresses {
+			host := addr.IP
+			if addr.Hostname != "" {
+				host = fmt.Sprintf("%s.%s.%s.svc.cluster.local", addr.Hostname, svc.Name, svc.Namespace)
+			}
+			replicas = append(replicas, fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(int(port)))))
+		}
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	return &DiscoveredService{
+		Name:      svc.Name,
+		Namespace: svc.Namespace,
+		URL:       replicas[0],
+		Replicas:  replicas,
+	}
+}
+
+// endpointPort returns the port of subset backing the selected service port, which
+// endpoints carry under the same name
+func endpointPort(subset corev1.EndpointSubset, selected *corev1.ServicePort) (int32, bool) {
+	for _, p := range subset.Ports {
+		if selected == nil || p.Name == selected.Name {
+			return p.Port, true
+		}
+	}
+	return 0, false
+}
+
+// isWebPortName reports whether a port name conventionally serves the web API
+func isWebPortName(name string) bool {
+	switch name {
+	case "web", "http", "https", "web-tls":
+		return true
+	}
+	return false
+}
+
+// detectScheme returns "https" when an annotation selects it, or when the port uses a
+// TLS port number, name or application protocol, and "http" otherwise
+func detectScheme(svc corev1.Service, port *corev1.ServicePort) string {
+	for _, annotation := range schemeAnnotations {
+		if value := strings.ToLower(svc.Annotations[annotation]); value == "http" || value == "https" {
+			return value
+		}
+	}
+	if port == nil {
+		return "http"
+	}
+	if port.Port == 443 || port.Port == 8443 {
+		return "https"
+	}
+	name := strings.ToLower(port.Name)
+	if strings.Contains(name, "https") || strings.Contains(name, "tls") {
+		return "https"
+	}
+	if port.AppProtocol != nil && strings.EqualFold(*port.AppProtocol, "https") {
+		return "https"
+	}
+	return "http"
+}
+
 // contains checks if a slice contains a string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -202,6 +361,7 @@ func DiscoverPushgateway(cfg DiscoveryConfig) (*DiscoveredService, error) {
 	if cfg.ServiceLabel == "" {
 		cfg.ServiceLabel = "app=pushgateway"
 	}
+	cfg.healthPaths = []string{"/-/healthy"}
 
 	return discoverService(cfg, "Pushgateway")
 }
@@ -222,6 +382,7 @@ func DiscoverOTelCollector(cfg DiscoveryConfig) (*DiscoveredService, error) {
 	if cfg.ServiceLabel == "" {
 		cfg.ServiceLabel = "app=opentelemetry-collector"
 	}
+	// The OTLP receiver has no health endpoint, so collectors are not probed
 
 	return discoverService(cfg, "OTel Collector")
 }
@@ -240,50 +401,9 @@ func discoverService(cfg DiscoveryConfig, serviceName string) (*DiscoveredServic
 		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
 	}
 
-	ctx := context.Background()
-
-	// Search for services
-	var discoveredServices []DiscoveredService
-
-	// First, try preferred namespaces if specified
-	if len(cfg.PreferNamespaces) > 0 {
-		for _, ns := range cfg.PreferNamespaces {
-			services, err := findServicesInNamespace(ctx, clientset, ns, cfg)
-			if err != nil {
-				log.Printf("Warning: failed to search namespace %s: %v", ns, err)
-				continue
-			}
-			discoveredServices = append(discoveredServices, services...)
-			if len(discoveredServices) > 0 {
-				log.Printf("Found %s in preferred namespace: %s", serviceName, ns)
-				break
-			}
-		}
-	}
-
-	// If not found in preferred namespaces, search all namespaces
-	if len(discoveredServices) == 0 {
-		log.Printf("Searching all namespaces for %s services...", serviceName)
-
-		// List all namespaces
-		namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to list namespaces: %w", err)
-		}
-
-		for _, ns := range namespaces.Items {
-			// Skip already-searched preferred namespaces
-			if contains(cfg.PreferNamespaces, ns.Name) {
-				continue
-			}
-
-			services, err := findServicesInNamespace(ctx, clientset, ns.Name, cfg)
-			if err != nil {
-				log.Printf("Warning: failed to search namespace %s: %v", ns.Name, err)
-				continue
-			}
-			discoveredServices = append(discoveredServices, services...)
-		}
+	discoveredServices, err := findServices(context.Background(), clientset, cfg, serviceName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Return results
@@ -297,9 +417,74 @@ func discoverService(cfg DiscoveryConfig, serviceName string) (*DiscoveredServic
 		log.Printf("  %d. %s/%s - %s", i+1, svc.Namespace, svc.Name, svc.URL)
 	}
 
-	// Return the first discovered service
-	selected := discoveredServices[0]
+	selected, err := selectService(discoveredServices, cfg, serviceName)
+	if err != nil {
+		return nil, err
+	}
 	log.Printf("Selected %s: %s/%s - %s", serviceName, selected.Namespace, selected.Name, selected.URL)
 
-	return &selected, nil
+	return selected, nil
+}
+
+// selectService returns the first discovered service that passes the health probe,
+// logging and skipping the others. Services resolved to endpoints have each replica
+// probed; unhealthy replicas are dropped and the URL is set to the first healthy one.
+// Without a health check the first service is returned.
+func selectService(services []DiscoveredService, cfg DiscoveryConfig, serviceName string) (*DiscoveredService, error) {
+	if !cfg.HealthCheck || len(cfg.healthPaths) == 0 {
+		return &services[0], nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSConfig != nil {
+		transport.TLSClientConfig = cfg.TLSConfig
+	}
+	client := &http.Client{Timeout: probeTimeout, Transport: transport}
+
+	for i := range services {
+		if len(services[i].Replicas) > 0 {
+			var healthy []string
+			for _, replica := range services[i].Replicas {
+				if err := probeService(client, replica, cfg.healthPaths); err != nil {
+					log.Printf("Skipping unhealthy %s replica %s: %v", serviceName, replica, err)
+					continue
+				}
+				healthy = append(healthy, replica)
+			}
+			if len(healthy) == 0 {
+				log.Printf("Skipping %s %s/%s: no healthy replicas", serviceName, services[i].Namespace, services[i].Name)
+				continue
+			}
+			services[i].Replicas = healthy
+			services[i].URL = healthy[0]
+			return &services[i], nil
+		}
+
+		if err := probeService(client, services[i].URL, cfg.healthPaths); err != nil {
+			log.Printf("Skipping unhealthy %s %s/%s: %v", serviceName, services[i].Namespace, services[i].Name, err)
+			continue
+		}
+		return &services[i], nil
+	}
+	return nil, fmt.Errorf("none of the %d discovered %s services is healthy", len(services), serviceName)
+}
+
+// probeService requests each of paths on baseURL until one answers with a success or an
+// authentication challenge, which shows the service is serving. Connection failures are
+// not retried on the other paths.
+func probeService(client *http.Client, baseURL string, paths []string) error {
+	var lastErr error
+	for _, path := range paths {
+		resp, err := client.Get(baseURL + path)
+		if err != nil {
+			return fmt.Errorf("unreachable: %w", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode/100 == 2 || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return nil
+		}
+		lastErr = fmt.Errorf("%s returned status %d", path, resp.StatusCode)
+	}
+	return lastErr
 }
@@ -1,10 +1,17 @@
 package k8s
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestServiceToDiscovered(t *testing.T) {
@@ -12,6 +19,7 @@ func TestServiceToDiscovered(t *testing.T) {
 		name          string
 		service       corev1.Service
 		preferredPort int
+		scheme        string
 		expectedURL   string
 		expectedPort  int
 	}{
@@ -119,11 +127,99 @@ func TestServiceToDiscovered(t *testing.T) {
 			expectedURL:   "http://alertmanager.monitoring.svc.cluster.local:9093",
 			expectedPort:  9093,
 		},
+		{
+			name: "Service with https port name",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "alertmanager",
+					Namespace: "monitoring",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Port: 8080, Name: "metrics"},
+						{Port: 9443, Name: "https"},
+					},
+				},
+			},
+			preferredPort: 9093,
+			expectedURL:   "https://alertmanager.monitoring.svc.cluster.local:9443",
+			expectedPort:  9443,
+		},
+		{
+			name: "Service with web-tls port name",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "alertmanager",
+					Namespace: "monitoring",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Port: 9093, Name: "web-tls"},
+					},
+				},
+			},
+			preferredPort: 9093,
+			expectedURL:   "https://alertmanager.monitoring.svc.cluster.local:9093",
+			expectedPort:  9093,
+		},
+		{
+			name: "Service with TLS port number",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "alertmanager",
+					Namespace: "monitoring",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Port: 8443},
+					},
+				},
+			},
+			preferredPort: 9093,
+			expectedURL:   "https://alertmanager.monitoring.svc.cluster.local:8443",
+			expectedPort:  8443,
+		},
+		{
+			name: "Service with scheme annotation",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "alertmanager",
+					Namespace:   "monitoring",
+					Annotations: map[string]string{"prometheus.io/scheme": "https"},
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Port: 9093, Name: "web"},
+					},
+				},
+			},
+			preferredPort: 9093,
+			expectedURL:   "https://alertmanager.monitoring.svc.cluster.local:9093",
+			expectedPort:  9093,
+		},
+		{
+			name: "Scheme override",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "alertmanager",
+					Namespace: "monitoring",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Port: 443, Name: "https"},
+					},
+				},
+			},
+			preferredPort: 9093,
+			scheme:        "http",
+			expectedURL:   "http://alertmanager.monitoring.svc.cluster.local:443",
+			expectedPort:  443,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := serviceToDiscovered(tt.service, tt.preferredPort)
+			result := serviceToDiscovered(tt.service, tt.preferredPort, tt.scheme)
 
 			if result == nil {
 				t.Fatal("Expected non-nil result")
@@ -144,6 +240,64 @@ func TestServiceToDiscovered(t *testing.T) {
 	}
 }
 
+func TestEndpointsToDiscovered(t *testing.T) {
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager-operated", Namespace: "monitoring"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Ports: []corev1.ServicePort{
+				{Port: 9094, Name: "tcp-mesh"},
+				{Port: 9093, Name: "web"},
+			},
+		},
+	}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager-operated", Namespace: "monitoring"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{
+				{IP: "10.0.0.1", Hostname: "alertmanager-main-0"},
+				{IP: "10.0.0.2"},
+				{IP: "fd00::3"},
+			},
+			NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.4"}},
+			Ports: []corev1.EndpointPort{
+				{Port: 9094, Name: "tcp-mesh"},
+				{Port: 9093, Name: "web"},
+			},
+		}},
+	}
+
+	ds := endpointsToDiscovered(svc, endpoints, 9093, "")
+	if ds == nil {
+		t.Fatal("Expected a discovered service")
+	}
+	expected := []string{
+		"http://alertmanager-main-0.alertmanager-operated.monitoring.svc.cluster.local:9093",
+		"http://10.0.0.2:9093",
+		"http://[fd00::3]:9093",
+	}
+	if len(ds.Replicas) != len(expected) {
+		t.Fatalf("Expected replicas %v, got %v", expected, ds.Replicas)
+	}
+	for i := range expected {
+		if ds.Replicas[i] != expected[i] {
+			t.Errorf("Replica %d: expected %s, got %s", i, expected[i], ds.Replicas[i])
+		}
+	}
+	if ds.URL != expected[0] {
+		t.Errorf("Expected URL %s, got %s", expected[0], ds.URL)
+	}
+
+	if ds := endpointsToDiscovered(svc, endpoints, 9093, "https"); ds.URL != "https://alertmanager-main-0.alertmanager-operated.monitoring.svc.cluster.local:9093" {
+		t.Errorf("Expected the https scheme to be used, got %s", ds.URL)
+	}
+
+	endpoints.Subsets[0].Addresses = nil
+	if ds := endpointsToDiscovered(svc, endpoints, 9093, ""); ds != nil {
+		t.Errorf("Expected nil without ready endpoints, got %+v", ds)
+	}
+}
+
 func TestContains(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -247,3 +401,124 @@ func TestDiscoveredService(t *testing.T) {
 //     // Use fake.NewSimpleClientset() from k8s.io/client-go/kubernetes/fake
 //     // to create a fake Kubernetes client for testing
 // }
+
+func TestSelectService_SkipsUnhealthy(t *testing.T) {
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	var probed []string
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probed = append(probed, r.URL.Path)
+		if r.URL.Path != "/api/v2/status" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer healthy.Close()
+
+	services := []DiscoveredService{
+		{Name: "alertmanager-down", Namespace: "monitoring", URL: unreachable.URL},
+		{Name: "alertmanager-starting", Namespace: "monitoring", URL: unhealthy.URL},
+		{Name: "alertmanager", Namespace: "monitoring", URL: healthy.URL},
+	}
+	cfg := DiscoveryConfig{HealthCheck: true, healthPaths: []string{"/-/healthy", "/api/v2/status"}}
+
+	selected, err := selectService(services, cfg, "Alertmanager")
+	if err != nil {
+		t.Fatalf("selectService() failed: %v", err)
+	}
+	if selected.Name != "alertmanager" {
+		t.Errorf("Expected the healthy service to be selected, got %s", selected.Name)
+	}
+	if len(probed) != 2 {
+		t.Errorf("Expected /api/v2/status to be probed after /-/healthy, got %v", probed)
+	}
+
+	if _, err := selectService(services[:2], cfg, "Alertmanager"); err == nil {
+		t.Error("Expected error when no service is healthy")
+	}
+
+	cfg.HealthCheck = false
+	selected, err = selectService(services, cfg, "Alertmanager")
+	if err != nil || selected.Name != "alertmanager-down" {
+		t.Errorf("Expected the first service without a health check, got %v, %v", selected, err)
+	}
+}
+
+func TestSelectService_Replicas(t *testing.T) {
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+
+	services := []DiscoveredService{
+		{Name: "alertmanager-down", Namespace: "monitoring", URL: unhealthy.URL, Replicas: []string{unhealthy.URL}},
+		{Name: "alertmanager-operated", Namespace: "monitoring", URL: unhealthy.URL, Replicas: []string{unhealthy.URL, healthy.URL}},
+	}
+	cfg := DiscoveryConfig{HealthCheck: true, Endpoints: true, healthPaths: []string{"/-/healthy"}}
+
+	selected, err := selectService(services, cfg, "Alertmanager")
+	if err != nil {
+		t.Fatalf("selectService() failed: %v", err)
+	}
+	if selected.Name != "alertmanager-operated" || selected.URL != healthy.URL {
+		t.Errorf("Expected the healthy replica of alertmanager-operated, got %s at %s", selected.Name, selected.URL)
+	}
+	if len(selected.Replicas) != 1 || selected.Replicas[0] != healthy.URL {
+		t.Errorf("Expected only the healthy replica, got %v", selected.Replicas)
+	}
+}
+
+func namespacedAlertmanager(namespace string, labels map[string]string) []runtime.Object {
+	return []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: labels}},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "alertmanager", Namespace: namespace},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 9093}}},
+		},
+	}
+}
+
+func TestFindServices_NamespacesOnly(t *testing.T) {
+	client := fake.NewSimpleClientset(append(
+		namespacedAlertmanager("team-a", nil),
+		namespacedAlertmanager("team-b", nil)...)...)
+	// Namespace-scoped RBAC forbids listing namespaces
+	client.PrependReactor("list", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("namespaces is forbidden")
+	})
+
+	cfg := DiscoveryConfig{ServiceName: "alertmanager", Port: 9093, PreferNamespaces: []string{"monitoring", "team-b"}, NamespacesOnly: true}
+	services, err := findServices(context.Background(), client, cfg, "Alertmanager")
+	if err != nil {
+		t.Fatalf("findServices() failed: %v", err)
+	}
+	if len(services) != 1 || services[0].Namespace != "team-b" {
+		t.Errorf("Expected the service in team-b, got %+v", services)
+	}
+
+	cfg.PreferNamespaces = []string{"monitoring"}
+	services, err = findServices(context.Background(), client, cfg, "Alertmanager")
+	if err != nil || len(services) != 0 {
+		t.Errorf("Expected no services outside the allowlist, got %+v, %v", services, err)
+	}
+}
+
+func TestFindServices_NamespaceSelector(t *testing.T) {
+	client := fake.NewSimpleClientset(append(
+		namespacedAlertmanager("team-a", map[string]string{"monitoring": "enabled"}),
+		namespacedAlertmanager("team-b", nil)...)...)
+
+	cfg := DiscoveryConfig{ServiceName: "alertmanager", Port: 9093, NamespaceSelector: "monitoring=enabled"}
+	services, err := findServices(context.Background(), client, cfg, "Alertmanager")
+	if err != nil {
+		t.Fatalf("findServices() failed: %v", err)
+	}
+	if len(services) != 1 || services[0].Namespace != "team-a" {
+		t.Errorf("Expected only the service in team-a, got %+v", services)
+	}
+}
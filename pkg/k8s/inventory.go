@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// namespaceFile is where Kubernetes mounts the pod's namespace for in-cluster service accounts
+const namespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// InventoryConfig holds configuration for publishing the managed silence inventory
+type InventoryConfig struct {
+	Name      string // ConfigMap name
+	Namespace string // Namespace to publish to (default: the pod's namespace)
+}
+
+// PublishInventory writes the managed silence inventory to a ConfigMap so that GitOps
+// tooling and dashboards can consume it without calling silence-manager directly
+func PublishInventory(cfg InventoryConfig, data map[string]string) error {
+	// Create in-cluster config
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return fmt.Errorf("failed to create in-cluster config: %w", err)
+	}
+
+	// Create Kubernetes clientset
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	if cfg.Namespace == "" {
+		cfg.Namespace = CurrentNamespace()
+	}
+
+	return publishConfigMap(context.Background(), clientset, cfg, data)
+}
+
+// publishConfigMap creates or replaces the data of the inventory ConfigMap
+func publishConfigMap(ctx context.Context, client kubernetes.Interface, cfg InventoryConfig, data map[string]string) error {
+	configMaps := client.CoreV1().ConfigMaps(cfg.Namespace)
+
+	existing, err := configMaps.Get(ctx, cfg.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cfg.Name,
+				Namespace: cfg.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "silence-manager",
+				},
+			},
+			Data: data,
+		}
+		if _, err := configMaps.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create inventory ConfigMap: %w", err)
+		}
+		log.Printf("Created inventory ConfigMap %s/%s", cfg.Namespace, cfg.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get inventory ConfigMap: %w", err)
+	}
+
+	existing.Data = data
+	if _, err := configMaps.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update inventory ConfigMap: %w", err)
+	}
+	log.Printf("Updated inventory ConfigMap %s/%s", cfg.Namespace, cfg.Name)
+	return nil
+}
+
+// CurrentNamespace returns the namespace the pod is running in, falling back to "default"
+func CurrentNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(namespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
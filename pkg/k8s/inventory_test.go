@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPublishConfigMap_Create(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	cfg := InventoryConfig{Name: "silence-manager-inventory", Namespace: "monitoring"}
+
+	err := publishConfigMap(context.Background(), client, cfg, map[string]string{"inventory.json": "[]"})
+	if err != nil {
+		t.Fatalf("publishConfigMap() failed: %v", err)
+	}
+
+	cm, err := client.CoreV1().ConfigMaps("monitoring").Get(context.Background(), "silence-manager-inventory", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected ConfigMap to be created: %v", err)
+	}
+	if cm.Data["inventory.json"] != "[]" {
+		t.Errorf("Unexpected ConfigMap data: %v", cm.Data)
+	}
+	if cm.Labels["app.kubernetes.io/managed-by"] != "silence-manager" {
+		t.Errorf("Expected managed-by label, got %v", cm.Labels)
+	}
+}
+
+func TestPublishConfigMap_Update(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "inventory", Namespace: "monitoring"},
+		Data:       map[string]string{"stale": "true"},
+	})
+	cfg := InventoryConfig{Name: "inventory", Namespace: "monitoring"}
+
+	err := publishConfigMap(context.Background(), client, cfg, map[string]string{"inventory.json": `[{"silenceID":"s1"}]`})
+	if err != nil {
+		t.Fatalf("publishConfigMap() failed: %v", err)
+	}
+
+	cm, _ := client.CoreV1().ConfigMaps("monitoring").Get(context.Background(), "inventory", metav1.GetOptions{})
+	if _, ok := cm.Data["stale"]; ok {
+		t.Error("Expected stale keys to be replaced")
+	}
+	if cm.Data["inventory.json"] != `[{"silenceID":"s1"}]` {
+		t.Errorf("Unexpected ConfigMap data: %v", cm.Data)
+	}
+}
+
+func TestCurrentNamespace_FromEnv(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "observability")
+
+	if ns := CurrentNamespace(); ns != "observability" {
+		t.Errorf("Expected namespace 'observability', got '%s'", ns)
+	}
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ModeOperator is recorded on the leader election lease of operator replicas
+const ModeOperator = "operator"
+
+// ErrLeadershipLost is returned by LeaderElector.Run when the leader could not renew its
+// lease in time. Callers should stop mutating and exit, as another replica may take over.
+var ErrLeadershipLost = errors.New("leadership lost: the leader election lease could not be renewed")
+
+// LeaderStatus describes the leadership state of this instance
+type LeaderStatus struct {
+	Leading  bool
+	Identity string    // Identity of this instance
+	Since    time.Time // When this instance became the leader
+	Holder   string    // Identity of the current leader, if known
+}
+
+// LeaderElector campaigns for a run lock lease so that only one of several replicas
+// leads. The lease is acquired or renewed every retry period. Leadership ends when the
+// lease could not be renewed within its duration less one retry period, before another
+// replica may take it over.
+type LeaderElector struct {
+	lock        *RunLock
+	retryPeriod time.Duration
+
+	mu     sync.Mutex
+	status LeaderStatus
+}
+
+// NewLeaderElector creates a leader elector campaigning for the lease of lock
+func NewLeaderElector(lock *RunLock, retryPeriod time.Duration) *LeaderElector {
+	return &LeaderElector{
+		lock:        lock,
+		retryPeriod: retryPeriod,
+		status:      LeaderStatus{Identity: lock.cfg.Identity},
+	}
+}
+
+// Status returns the current leadership state
+func (e *LeaderElector) Status() LeaderStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status
+}
+
+// Run campaigns for leadership until ctx is done, calling onStarted, which must not
+// block, when this instance becomes the leader. It releases the lease and returns nil
+// when ctx ends, and returns ErrLeadershipLost when the lease could not be renewed.
+func (e *LeaderElector) Run(ctx context.Context, onStarted func()) error {
+	ticker := time.NewTicker(e.retryPeriod)
+	defer ticker.Stop()
+
+	var lastRenew time.Time
+	for {
+		if err := e.campaign(ctx, time.Now(), &lastRenew, onStarted); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if e.Status().Leading {
+				if err := e.lock.Release(context.Background()); err != nil {
+					log.Printf("Warning: failed to release leader election lease: %v", err)
+				}
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign makes one attempt to acquire or renew the lease at now, updating the status
+func (e *LeaderElector) campaign(ctx context.Context, now time.Time, lastRenew *time.Time, onStarted func()) error {
+	acquired, holder, err := e.lock.Acquire(ctx)
+
+	e.mu.Lock()
+	wasLeading := e.status.Leading
+	switch {
+	case err != nil:
+		if ctx.Err() == nil {
+			log.Printf("Warning: failed to renew leader election lease: %v", err)
+		}
+	case acquired:
+		*lastRenew = now
+		e.status.Holder = e.status.Identity
+		if !wasLeading {
+			e.status.Leading = true
+			e.status.Since = now
+		}
+	default:
+		if holder.Identity != "" && holder.Identity != e.status.Holder {
+			log.Printf("Leader election lease is held by %s", holder.Identity)
+			e.status.Holder = holder.Identity
+		}
+	}
+
+	// Another replica took the lease, or it was not renewed in time
+	lost := wasLeading && ((err == nil && !acquired) || now.Sub(*lastRenew) > e.lock.cfg.LeaseDuration-e.retryPeriod)
+	if lost {
+		e.status.Leading = false
+	}
+	leading := e.status.Leading
+	e.mu.Unlock()
+
+	if lost {
+		return ErrLeadershipLost
+	}
+	if leading && !wasLeading {
+		log.Printf("Became leader as %s", e.status.Identity)
+		onStarted()
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLeaderElector_Campaign(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+	now := time.Now()
+
+	first := NewLeaderElector(newRunLock(client, lockConfig("daemon-0", ModeDaemon)), 2*time.Second)
+	second := NewLeaderElector(newRunLock(client, lockConfig("daemon-1", ModeDaemon)), 2*time.Second)
+	var firstRenew, secondRenew time.Time
+	started := 0
+	onStarted := func() { started++ }
+
+	if err := first.campaign(ctx, now, &firstRenew, onStarted); err != nil {
+		t.Fatalf("campaign() failed: %v", err)
+	}
+	if status := first.Status(); !status.Leading || !status.Since.Equal(now) {
+		t.Errorf("Expected daemon-0 to lead since %v, got %+v", now, status)
+	}
+
+	if err := second.campaign(ctx, now, &secondRenew, onStarted); err != nil {
+		t.Fatalf("campaign() failed: %v", err)
+	}
+	if status := second.Status(); status.Leading || status.Holder != "daemon-0" {
+		t.Errorf("Expected daemon-1 to follow daemon-0, got %+v", status)
+	}
+
+	// Renewals keep the leadership without starting again
+	if err := first.campaign(ctx, now.Add(2*time.Second), &firstRenew, onStarted); err != nil {
+		t.Fatalf("campaign() failed: %v", err)
+	}
+	if started != 1 {
+		t.Errorf("Expected onStarted to be called once, got %d", started)
+	}
+
+	// Another replica holding the lease ends the leadership
+	lease, err := client.CoordinationV1().Leases("monitoring").Get(ctx, "silence-manager", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get lease: %v", err)
+	}
+	holder := "daemon-1"
+	lease.Spec.HolderIdentity = &holder
+	if _, err := client.CoordinationV1().Leases("monitoring").Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update lease: %v", err)
+	}
+	err = first.campaign(ctx, now.Add(4*time.Second), &firstRenew, onStarted)
+	if !errors.Is(err, ErrLeadershipLost) {
+		t.Errorf("Expected ErrLeadershipLost, got %v", err)
+	}
+	if first.Status().Leading {
+		t.Error("Expected daemon-0 to stop leading")
+	}
+}
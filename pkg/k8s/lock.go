@@ -0,0 +1,180 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// modeAnnotation records which deployment model (cronjob or daemon) holds the lease
+const modeAnnotation = "silence-manager/mode"
+
+// Run modes recorded on the lease
+const (
+	ModeCronJob = "cronjob"
+	ModeDaemon  = "daemon"
+	ModeAPI     = "api" // Runs triggered through the REST API of the serve command
+)
+
+// LockConfig holds configuration for the Lease-based run lock
+type LockConfig struct {
+	Name          string        // Lease name
+	Namespace     string        // Namespace of the Lease (default: the pod's namespace)
+	Identity      string        // Identity of this instance, usually the pod name
+	Mode          string        // ModeCronJob, ModeDaemon or ModeAPI
+	LeaseDuration time.Duration // How long the lease is valid without renewal
+}
+
+// LockHolder describes the instance currently holding the run lock
+type LockHolder struct {
+	Identity string
+	Mode     string
+}
+
+// RunLock ensures only one silence-manager instance synchronizes at a time, so that a
+// CronJob and a daemon can be deployed side by side during a migration
+type RunLock struct {
+	client kubernetes.Interface
+	cfg    LockConfig
+}
+
+// NewRunLock creates a run lock using the in-cluster Kubernetes configuration
+func NewRunLock(cfg LockConfig) (*RunLock, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	if cfg.Namespace == "" {
+		cfg.Namespace = CurrentNamespace()
+	}
+
+	return newRunLock(clientset, cfg), nil
+}
+
+func newRunLock(client kubernetes.Interface, cfg LockConfig) *RunLock {
+	return &RunLock{client: client, cfg: cfg}
+}
+
+// Acquire takes or renews the lease. If another instance holds an unexpired lease, it
+// returns false together with the current holder.
+func (l *RunLock) Acquire(ctx context.Context) (bool, *LockHolder, error) {
+	leases := l.client.CoordinationV1().Leases(l.cfg.Namespace)
+	now := metav1.NewMicroTime(time.Now())
+	durationSeconds := int32(l.cfg.LeaseDuration.Seconds())
+
+	lease, err := leases.Get(ctx, l.cfg.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      l.cfg.Name,
+				Namespace: l.cfg.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "silence-manager",
+				},
+				Annotations: map[string]string{modeAnnotation: l.cfg.Mode},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &l.cfg.Identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if _, err := leases.Create(ctx, lease, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// Another instance created the lease first
+				return false, &LockHolder{}, nil
+			}
+			return false, nil, fmt.Errorf("failed to create lease: %w", err)
+		}
+		log.Printf("Acquired run lock %s/%s as %s (%s)", l.cfg.Namespace, l.cfg.Name, l.cfg.Identity, l.cfg.Mode)
+		return true, nil, nil
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get lease: %w", err)
+	}
+
+	holder := holderOf(lease)
+	if holder.Identity != "" && holder.Identity != l.cfg.Identity && !leaseExpired(lease, now.Time) {
+		return false, holder, nil
+	}
+
+	if holder.Identity != l.cfg.Identity {
+		lease.Spec.AcquireTime = &now
+	}
+	lease.Spec.HolderIdentity = &l.cfg.Identity
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &now
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[modeAnnotation] = l.cfg.Mode
+
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			// Another instance updated the lease since we read it
+			return false, &LockHolder{}, nil
+		}
+		return false, nil, fmt.Errorf("failed to update lease: %w", err)
+	}
+	if holder.Identity != l.cfg.Identity {
+		log.Printf("Acquired run lock %s/%s as %s (%s)", l.cfg.Namespace, l.cfg.Name, l.cfg.Identity, l.cfg.Mode)
+	}
+	return true, nil, nil
+}
+
+// Release gives up the lease if this instance holds it
+func (l *RunLock) Release(ctx context.Context) error {
+	leases := l.client.CoordinationV1().Leases(l.cfg.Namespace)
+
+	lease, err := leases.Get(ctx, l.cfg.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get lease: %w", err)
+	}
+	if holderOf(lease).Identity != l.cfg.Identity {
+		return nil
+	}
+
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	log.Printf("Released run lock %s/%s", l.cfg.Namespace, l.cfg.Name)
+	return nil
+}
+
+// holderOf returns the current holder recorded on the lease
+func holderOf(lease *coordinationv1.Lease) *LockHolder {
+	holder := &LockHolder{Mode: lease.Annotations[modeAnnotation]}
+	if lease.Spec.HolderIdentity != nil {
+		holder.Identity = *lease.Spec.HolderIdentity
+	}
+	return holder
+}
+
+// leaseExpired reports whether the lease has not been renewed within its duration
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
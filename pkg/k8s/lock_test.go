@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func lockConfig(identity, mode string) LockConfig {
+	return LockConfig{
+		Name:          "silence-manager",
+		Namespace:     "monitoring",
+		Identity:      identity,
+		Mode:          mode,
+		LeaseDuration: 10 * time.Minute,
+	}
+}
+
+func TestRunLock_AcquireAndDefer(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	daemon := newRunLock(client, lockConfig("daemon-pod", ModeDaemon))
+	acquired, _, err := daemon.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	if !acquired {
+		t.Fatal("Expected daemon to acquire the lock")
+	}
+
+	// A CronJob run must defer while the daemon holds the lease
+	cron := newRunLock(client, lockConfig("cron-pod", ModeCronJob))
+	acquired, holder, err := cron.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	if acquired {
+		t.Fatal("Expected CronJob not to acquire the lock")
+	}
+	if holder.Identity != "daemon-pod" || holder.Mode != ModeDaemon {
+		t.Errorf("Expected holder daemon-pod (daemon), got %+v", holder)
+	}
+
+	// The daemon can renew its own lease
+	acquired, _, err = daemon.Acquire(ctx)
+	if err != nil || !acquired {
+		t.Errorf("Expected daemon to renew the lock, acquired=%v err=%v", acquired, err)
+	}
+}
+
+func TestRunLock_Release(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	cron := newRunLock(client, lockConfig("cron-pod", ModeCronJob))
+	if acquired, _, err := cron.Acquire(ctx); err != nil || !acquired {
+		t.Fatalf("Expected CronJob to acquire the lock, acquired=%v err=%v", acquired, err)
+	}
+	if err := cron.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+
+	daemon := newRunLock(client, lockConfig("daemon-pod", ModeDaemon))
+	acquired, _, err := daemon.Acquire(ctx)
+	if err != nil || !acquired {
+		t.Fatalf("Expected daemon to acquire the released lock, acquired=%v err=%v", acquired, err)
+	}
+
+	lease, _ := client.CoordinationV1().Leases("monitoring").Get(ctx, "silence-manager", metav1.GetOptions{})
+	if lease.Annotations[modeAnnotation] != ModeDaemon {
+		t.Errorf("Expected mode annotation %q, got %q", ModeDaemon, lease.Annotations[modeAnnotation])
+	}
+}
+
+func TestRunLock_TakeOverExpired(t *testing.T) {
+	holder := "old-pod"
+	duration := int32(60)
+	renewed := metav1.NewMicroTime(time.Now().Add(-time.Hour))
+	client := fake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "silence-manager",
+			Namespace:   "monitoring",
+			Annotations: map[string]string{modeAnnotation: ModeDaemon},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &renewed,
+		},
+	})
+
+	cron := newRunLock(client, lockConfig("cron-pod", ModeCronJob))
+	acquired, _, err := cron.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	if !acquired {
+		t.Error("Expected expired lease to be taken over")
+	}
+}
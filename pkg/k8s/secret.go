@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Keys of the Jira credentials in the Secret, matching deployments/secret.yaml.example
+const (
+	jiraUsernameKey = "jira-username"
+	jiraAPITokenKey = "jira-api-token"
+)
+
+// SecretCredentials reads the Jira credentials from a Kubernetes Secret and keeps them
+// current by watching it, so that rotated credentials are used without a restart. It
+// implements ticket.CredentialSource.
+type SecretCredentials struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+
+	mu       sync.RWMutex
+	username string
+	apiToken string
+}
+
+// NewSecretCredentials reads the Jira credentials from the named Secret using the
+// in-cluster Kubernetes configuration, and watches it for changes until ctx is done. An
+// empty namespace selects the pod's namespace.
+func NewSecretCredentials(ctx context.Context, namespace, name string) (*SecretCredentials, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	if namespace == "" {
+		namespace = CurrentNamespace()
+	}
+
+	return newSecretCredentials(ctx, clientset, namespace, name)
+}
+
+func newSecretCredentials(ctx context.Context, client kubernetes.Interface, namespace, name string) (*SecretCredentials, error) {
+	s := &SecretCredentials{client: client, namespace: namespace, name: name}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s/%s: %w", namespace, name, err)
+	}
+	if _, err := s.load(secret); err != nil {
+		return nil, err
+	}
+
+	go s.watch(ctx, secret.ResourceVersion)
+	return s, nil
+}
+
+// Credentials returns the current Jira username and API token
+func (s *SecretCredentials) Credentials() (string, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.username, s.apiToken
+}
+
+// load replaces the credentials with those of secret, reporting whether they changed
+func (s *SecretCredentials) load(secret *corev1.Secret) (bool, error) {
+	username := string(secret.Data[jiraUsernameKey])
+	apiToken := string(secret.Data[jiraAPITokenKey])
+	if username == "" || apiToken == "" {
+		return false, fmt.Errorf("secret %s/%s must contain %s and %s", s.namespace, s.name, jiraUsernameKey, jiraAPITokenKey)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := username != s.username || apiToken != s.apiToken
+	s.username = username
+	s.apiToken = apiToken
+	return changed, nil
+}
+
+// watch reloads the credentials whenever the Secret changes, re-establishing the watch
+// until ctx is done. A deleted or incomplete Secret keeps the last credentials.
+func (s *SecretCredentials) watch(ctx context.Context, resourceVersion string) {
+	secrets := s.client.CoreV1().Secrets(s.namespace)
+	for ctx.Err() == nil {
+		w, err := secrets.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   "metadata.name=" + s.name,
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			log.Printf("Warning: failed to watch Secret %s/%s: %v", s.namespace, s.name, err)
+		} else {
+			for event := range w.ResultChan() {
+				secret, ok := event.Object.(*corev1.Secret)
+				if !ok {
+					continue
+				}
+				switch event.Type {
+				case watch.Added, watch.Modified:
+					changed, err := s.load(secret)
+					if err != nil {
+						log.Printf("Warning: keeping the previous Jira credentials: %v", err)
+						continue
+					}
+					if changed {
+						log.Printf("Reloaded Jira credentials from Secret %s/%s", s.namespace, s.name)
+					}
+				case watch.Deleted:
+					log.Printf("Warning: Secret %s/%s was deleted, keeping the previous Jira credentials", s.namespace, s.name)
+				}
+			}
+			w.Stop()
+		}
+
+		// Restart from the current state, which replays the Secret as an added event
+		select {
+		case <-ctx.Done():
+		case <-time.After(watchRetryInterval):
+		}
+		resourceVersion = ""
+	}
+}
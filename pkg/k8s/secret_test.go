@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func jiraSecret(username, apiToken string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "silence-manager-secrets", Namespace: "monitoring"},
+		Data: map[string][]byte{
+			jiraUsernameKey: []byte(username),
+			jiraAPITokenKey: []byte(apiToken),
+		},
+	}
+}
+
+func TestSecretCredentials_Rotation(t *testing.T) {
+	client := fake.NewSimpleClientset(jiraSecret("bot@example.com", "old-token"))
+	events := watch.NewFake()
+	client.PrependWatchReactor("secrets", k8stesting.DefaultWatchReactor(events, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	credentials, err := newSecretCredentials(ctx, client, "monitoring", "silence-manager-secrets")
+	if err != nil {
+		t.Fatalf("newSecretCredentials() failed: %v", err)
+	}
+	if username, apiToken := credentials.Credentials(); username != "bot@example.com" || apiToken != "old-token" {
+		t.Errorf("Unexpected initial credentials %q, %q", username, apiToken)
+	}
+
+	// Incomplete updates keep the previous credentials
+	events.Modify(jiraSecret("bot@example.com", ""))
+	events.Modify(jiraSecret("bot@example.com", "new-token"))
+	// The fake watcher is unbuffered, so this send completes after the update was read
+	events.Delete(jiraSecret("bot@example.com", "new-token"))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, apiToken := credentials.Credentials(); apiToken == "new-token" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the rotated token to be loaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSecretCredentials_MissingKeys(t *testing.T) {
+	client := fake.NewSimpleClientset(jiraSecret("bot@example.com", ""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := newSecretCredentials(ctx, client, "monitoring", "silence-manager-secrets"); err == nil {
+		t.Error("Expected error for a Secret without an API token")
+	}
+}
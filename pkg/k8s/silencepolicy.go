@@ -0,0 +1,187 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/go-logr/stdr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/conallob/silence-manager/pkg/operator"
+)
+
+// silencePolicyKind identifies the SilencePolicy custom resource
+var silencePolicyKind = schema.GroupVersionKind{
+	Group:   operator.Group,
+	Version: operator.Version,
+	Kind:    operator.Kind,
+}
+
+// NewPolicyManager creates a controller-runtime manager using the in-cluster Kubernetes
+// configuration. Its cache holds the SilencePolicies of namespace, or of all namespaces
+// when empty. Leader election and metrics are left to silence-manager itself, which
+// serves them on the health endpoint.
+func NewPolicyManager(namespace string) (manager.Manager, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
+	}
+
+	ctrllog.SetLogger(stdr.New(log.Default()))
+	options := manager.Options{
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	}
+	if namespace != "" {
+		options.Cache.DefaultNamespaces = map[string]cache.Config{namespace: {}}
+	}
+	mgr, err := manager.New(config, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller manager: %w", err)
+	}
+	return mgr, nil
+}
+
+// WatchPolicies registers r with mgr to reconcile SilencePolicies as they are created,
+// changed or deleted. Updates that leave the generation alone, such as status and
+// finalizer updates, are not fed back; deleting a policy bumps its generation.
+func WatchPolicies(mgr manager.Manager, r reconcile.Reconciler) error {
+	return builder.ControllerManagedBy(mgr).
+		Named("silencepolicy").
+		For(newPolicyObject(), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// PolicyClient reads and updates SilencePolicy resources. It implements
+// operator.PolicyStore.
+type PolicyClient struct {
+	client client.Client
+}
+
+// NewPolicyClient creates a SilencePolicy client, e.g. on the client of a manager
+// created by NewPolicyManager, which reads policies from its cache
+func NewPolicyClient(c client.Client) *PolicyClient {
+	return &PolicyClient{client: c}
+}
+
+// newPolicyObject returns an empty SilencePolicy to read into
+func newPolicyObject() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(silencePolicyKind)
+	return obj
+}
+
+func (c *PolicyClient) get(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+	obj := newPolicyObject()
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// GetPolicy returns a SilencePolicy, or nil if it no longer exists
+func (c *PolicyClient) GetPolicy(ctx context.Context, namespace, name string) (*operator.SilencePolicy, error) {
+	obj, err := c.get(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SilencePolicy %s/%s: %w", namespace, name, err)
+	}
+	policy, err := policyFromUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("SilencePolicy %s/%s: %w", namespace, name, err)
+	}
+	return policy, nil
+}
+
+// UpdateStatus replaces the status subresource of a policy
+func (c *PolicyClient) UpdateStatus(ctx context.Context, policy *operator.SilencePolicy) error {
+	obj, err := c.get(ctx, policy.Namespace, policy.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get SilencePolicy: %w", err)
+	}
+
+	status, err := toUnstructuredMap(policy.Status)
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+	if err := unstructured.SetNestedField(obj.Object, status, "status"); err != nil {
+		return fmt.Errorf("failed to set status: %w", err)
+	}
+
+	if err := c.client.Status().Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed to update SilencePolicy status: %w", err)
+	}
+	return nil
+}
+
+// SetFinalizers replaces the finalizers of a policy
+func (c *PolicyClient) SetFinalizers(ctx context.Context, policy *operator.SilencePolicy, finalizers []string) error {
+	obj, err := c.get(ctx, policy.Namespace, policy.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get SilencePolicy: %w", err)
+	}
+
+	obj.SetFinalizers(finalizers)
+	if err := c.client.Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed to update SilencePolicy finalizers: %w", err)
+	}
+	return nil
+}
+
+// policyFromUnstructured decodes a SilencePolicy resource
+func policyFromUnstructured(obj *unstructured.Unstructured) (*operator.SilencePolicy, error) {
+	policy := &operator.SilencePolicy{
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Generation: obj.GetGeneration(),
+		Deleting:   obj.GetDeletionTimestamp() != nil,
+		Finalizers: obj.GetFinalizers(),
+	}
+	if err := fromUnstructuredField(obj, "spec", &policy.Spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if err := fromUnstructuredField(obj, "status", &policy.Status); err != nil {
+		return nil, fmt.Errorf("invalid status: %w", err)
+	}
+	return policy, nil
+}
+
+// fromUnstructuredField decodes a top-level field of obj into v through its JSON form
+func fromUnstructuredField(obj *unstructured.Unstructured, field string, v interface{}) error {
+	value, ok := obj.Object[field]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// toUnstructuredMap encodes v into the map form used by unstructured objects
+func toUnstructuredMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
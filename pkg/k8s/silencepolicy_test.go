@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/conallob/silence-manager/pkg/operator"
+)
+
+func newSilencePolicy(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": operator.Group + "/" + operator.Version,
+		"kind":       operator.Kind,
+		"metadata": map[string]interface{}{
+			"name":       name,
+			"namespace":  namespace,
+			"generation": int64(3),
+		},
+		"spec": map[string]interface{}{
+			"matchers": []interface{}{`alertname="DiskFull"`},
+			"project":  "OPS",
+			"duration": "3d",
+			"routing": map[string]interface{}{
+				"assignee": "storage-oncall@example.com",
+			},
+		},
+	}}
+}
+
+func newFakePolicyClient(objects ...client.Object) *PolicyClient {
+	c := fake.NewClientBuilder().
+		WithObjects(objects...).
+		WithStatusSubresource(newPolicyObject()).
+		Build()
+	return NewPolicyClient(c)
+}
+
+func TestPolicyClient_GetPolicy(t *testing.T) {
+	c := newFakePolicyClient(newSilencePolicy("storage", "disk-replacement"))
+	ctx := context.Background()
+
+	policy, err := c.GetPolicy(ctx, "storage", "disk-replacement")
+	if err != nil {
+		t.Fatalf("GetPolicy() failed: %v", err)
+	}
+	if policy.String() != "storage/disk-replacement" || policy.Generation != 3 {
+		t.Errorf("Unexpected policy metadata: %+v", policy)
+	}
+	if len(policy.Spec.Matchers) != 1 || policy.Spec.Project != "OPS" || policy.Spec.Routing.Assignee != "storage-oncall@example.com" {
+		t.Errorf("Unexpected policy spec: %+v", policy.Spec)
+	}
+
+	policy, err = c.GetPolicy(ctx, "storage", "gone")
+	if err != nil || policy != nil {
+		t.Errorf("Expected no policy once deleted, got %+v, %v", policy, err)
+	}
+}
+
+func TestPolicyClient_UpdateStatus(t *testing.T) {
+	c := newFakePolicyClient(newSilencePolicy("storage", "disk-replacement"))
+	ctx := context.Background()
+	policy, err := c.GetPolicy(ctx, "storage", "disk-replacement")
+	if err != nil {
+		t.Fatalf("GetPolicy() failed: %v", err)
+	}
+
+	endsAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy.Status = operator.PolicyStatus{
+		ObservedGeneration: 3,
+		SilenceID:          "silence-1",
+		Ticket:             "OPS-1",
+		EndsAt:             &endsAt,
+		Conditions: []operator.Condition{
+			{Type: operator.ConditionReady, Status: operator.ConditionTrue, Reason: "Reconciled", LastTransitionTime: endsAt},
+		},
+	}
+	if err := c.UpdateStatus(ctx, policy); err != nil {
+		t.Fatalf("UpdateStatus() failed: %v", err)
+	}
+	if err := c.SetFinalizers(ctx, policy, []string{operator.Finalizer}); err != nil {
+		t.Fatalf("SetFinalizers() failed: %v", err)
+	}
+
+	got, err := c.GetPolicy(ctx, "storage", "disk-replacement")
+	if err != nil {
+		t.Fatalf("GetPolicy() failed: %v", err)
+	}
+	if got.Status.SilenceID != "silence-1" || got.Status.ObservedGeneration != 3 || !got.Status.EndsAt.Equal(endsAt) {
+		t.Errorf("Unexpected status after update: %+v", got.Status)
+	}
+	if c := got.Status.FindCondition(operator.ConditionReady); c == nil || c.Status != operator.ConditionTrue {
+		t.Errorf("Expected Ready condition, got %+v", got.Status.Conditions)
+	}
+	if len(got.Finalizers) != 1 || got.Finalizers[0] != operator.Finalizer {
+		t.Errorf("Expected finalizer %s, got %v", operator.Finalizer, got.Finalizers)
+	}
+}
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// stateKey is the ConfigMap key holding the state document
+const stateKey = "state.json"
+
+// StateConfigMap keeps the silence-manager state document in a ConfigMap, for exporting
+// the state between CronJob runs on runners without persistent storage. ConfigMaps are
+// limited to 1 MiB.
+type StateConfigMap struct {
+	client    kubernetes.Interface
+	name      string
+	namespace string
+}
+
+// NewStateConfigMap creates a state copy kept in the named ConfigMap, in the pod's
+// namespace unless namespace is set
+func NewStateConfigMap(name, namespace string) (*StateConfigMap, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	if namespace == "" {
+		namespace = CurrentNamespace()
+	}
+	return &StateConfigMap{client: clientset, name: name, namespace: namespace}, nil
+}
+
+// Get returns the state document, or nil if the ConfigMap or its key does not exist
+func (s *StateConfigMap) Get() ([]byte, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(context.Background(), s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state ConfigMap: %w", err)
+	}
+	data, ok := cm.Data[stateKey]
+	if !ok {
+		return nil, nil
+	}
+	return []byte(data), nil
+}
+
+// Put creates the ConfigMap or replaces its state document
+func (s *StateConfigMap) Put(data []byte) error {
+	ctx := context.Background()
+	configMaps := s.client.CoreV1().ConfigMaps(s.namespace)
+
+	existing, err := configMaps.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.name,
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "silence-manager",
+				},
+			},
+			Data: map[string]string{stateKey: string(data)},
+		}
+		if _, err := configMaps.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create state ConfigMap: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get state ConfigMap: %w", err)
+	}
+
+	if existing.Data == nil {
+		existing.Data = make(map[string]string)
+	}
+	existing.Data[stateKey] = string(data)
+	if _, err := configMaps.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update state ConfigMap: %w", err)
+	}
+	return nil
+}
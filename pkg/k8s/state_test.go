@@ -0,0 +1,40 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStateConfigMap_RoundTrip(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := &StateConfigMap{client: client, name: "silence-manager-state", namespace: "monitoring"}
+
+	data, err := store.Get()
+	if err != nil || data != nil {
+		t.Fatalf("Expected no state before the first export, got %q, %v", data, err)
+	}
+
+	for _, doc := range []string{`{"idle":{}}`, `{"hygiene":[]}`} {
+		if err := store.Put([]byte(doc)); err != nil {
+			t.Fatalf("Put() failed: %v", err)
+		}
+		data, err := store.Get()
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if string(data) != doc {
+			t.Errorf("Expected %s, got %s", doc, data)
+		}
+	}
+
+	cm, err := client.CoreV1().ConfigMaps("monitoring").Get(context.Background(), "silence-manager-state", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the ConfigMap to be created: %v", err)
+	}
+	if cm.Labels["app.kubernetes.io/managed-by"] != "silence-manager" {
+		t.Errorf("Expected managed-by label, got %v", cm.Labels)
+	}
+}
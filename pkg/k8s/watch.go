@@ -0,0 +1,151 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// watchRetryInterval is how long to wait before re-establishing a failed watch
+const watchRetryInterval = 5 * time.Second
+
+// ServiceWatcher notices when a discovered service goes away or moves to another port,
+// so that long-running processes can discover it again
+type ServiceWatcher struct {
+	client kubernetes.Interface
+}
+
+// NewServiceWatcher creates a service watcher using the in-cluster Kubernetes configuration
+func NewServiceWatcher() (*ServiceWatcher, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return newServiceWatcher(clientset), nil
+}
+
+func newServiceWatcher(client kubernetes.Interface) *ServiceWatcher {
+	return &ServiceWatcher{client: client}
+}
+
+// Watch returns a channel that is closed once svc is deleted or no longer resolves to
+// the same URL under cfg, e.g. because its ports changed. With cfg.Endpoints the
+// endpoints are watched instead, and the channel is also closed once the selected
+// replica is no longer ready. Interrupted watches are
+// re-established until ctx is done; the channel is left open when ctx ends first.
+func (w *ServiceWatcher) Watch(ctx context.Context, cfg DiscoveryConfig, svc DiscoveredService) <-chan struct{} {
+	changed := make(chan struct{})
+	go func() {
+		for {
+			moved, err := w.watchOnce(ctx, cfg, svc)
+			if moved {
+				close(changed)
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Printf("Warning: failed to watch service %s/%s: %v", svc.Namespace, svc.Name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+		}
+	}()
+	return changed
+}
+
+// watchOnce checks svc and then watches it until the watch ends, reporting whether it
+// was deleted or moved
+func (w *ServiceWatcher) watchOnce(ctx context.Context, cfg DiscoveryConfig, svc DiscoveredService) (bool, error) {
+	current, err := w.client.CoreV1().Services(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resourceVersion := current.ResourceVersion
+
+	var endpoints *corev1.Endpoints
+	if cfg.Endpoints {
+		endpoints, err = w.client.CoreV1().Endpoints(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		resourceVersion = endpoints.ResourceVersion
+	}
+	if serviceMoved(current, endpoints, cfg, svc) {
+		return true, nil
+	}
+
+	opts := metav1.ListOptions{
+		FieldSelector:   "metadata.name=" + svc.Name,
+		ResourceVersion: resourceVersion,
+	}
+	var watcher watch.Interface
+	if cfg.Endpoints {
+		watcher, err = w.client.CoreV1().Endpoints(svc.Namespace).Watch(ctx, opts)
+	} else {
+		watcher, err = w.client.CoreV1().Services(svc.Namespace).Watch(ctx, opts)
+	}
+	if err != nil {
+		return false, err
+	}
+	defer watcher.Stop()
+
+	for event := range watcher.ResultChan() {
+		switch event.Type {
+		case watch.Deleted:
+			return true, nil
+		case watch.Added, watch.Modified:
+			switch obj := event.Object.(type) {
+			case *corev1.Service:
+				current = obj
+			case *corev1.Endpoints:
+				endpoints = obj
+			default:
+				continue
+			}
+			if serviceMoved(current, endpoints, cfg, svc) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// serviceMoved reports whether current no longer resolves to the URL of svc. With
+// cfg.Endpoints, the URL must remain one of the ready endpoints.
+func serviceMoved(current *corev1.Service, endpoints *corev1.Endpoints, cfg DiscoveryConfig, svc DiscoveredService) bool {
+	if cfg.Endpoints {
+		if endpoints == nil {
+			return true
+		}
+		ds := endpointsToDiscovered(*current, endpoints, cfg.Port, cfg.Scheme)
+		return ds == nil || !contains(ds.Replicas, svc.URL)
+	}
+	ds := serviceToDiscovered(*current, cfg.Port, cfg.Scheme)
+	return ds == nil || ds.URL != svc.URL
+}
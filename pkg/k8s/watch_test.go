@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func watchedService(port int32) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager", Namespace: "monitoring"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Port: port, Name: "web"}},
+		},
+	}
+}
+
+var watchedDiscovery = DiscoveredService{
+	Name:      "alertmanager",
+	Namespace: "monitoring",
+	URL:       "http://alertmanager.monitoring.svc.cluster.local:9093",
+}
+
+func expectChange(t *testing.T, changed <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the watch to report a change")
+	}
+}
+
+func TestServiceWatcher_Deleted(t *testing.T) {
+	client := fake.NewSimpleClientset(watchedService(9093))
+	events := watch.NewFake()
+	client.PrependWatchReactor("services", k8stesting.DefaultWatchReactor(events, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := newServiceWatcher(client).Watch(ctx, DiscoveryConfig{Port: 9093}, watchedDiscovery)
+
+	// Unrelated updates do not count as a change
+	events.Modify(watchedService(9093))
+	select {
+	case <-changed:
+		t.Fatal("Expected no change for an unchanged service")
+	default:
+	}
+
+	events.Delete(watchedService(9093))
+	expectChange(t, changed)
+}
+
+func TestServiceWatcher_PortChanged(t *testing.T) {
+	client := fake.NewSimpleClientset(watchedService(9093))
+	events := watch.NewFake()
+	client.PrependWatchReactor("services", k8stesting.DefaultWatchReactor(events, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := newServiceWatcher(client).Watch(ctx, DiscoveryConfig{Port: 9093}, watchedDiscovery)
+
+	events.Modify(watchedService(9094))
+	expectChange(t, changed)
+}
+
+func TestServiceWatcher_GoneBeforeWatch(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := newServiceWatcher(client).Watch(ctx, DiscoveryConfig{Port: 9093}, watchedDiscovery)
+
+	expectChange(t, changed)
+}
+
+func TestServiceWatcher_ReplicaNotReady(t *testing.T) {
+	endpoints := func(ips ...string) *corev1.Endpoints {
+		subset := corev1.EndpointSubset{Ports: []corev1.EndpointPort{{Port: 9093, Name: "web"}}}
+		for _, ip := range ips {
+			subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+		}
+		return &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "alertmanager", Namespace: "monitoring"},
+			Subsets:    []corev1.EndpointSubset{subset},
+		}
+	}
+	client := fake.NewSimpleClientset(watchedService(9093), endpoints("10.0.0.1", "10.0.0.2"))
+	events := watch.NewFake()
+	client.PrependWatchReactor("endpoints", k8stesting.DefaultWatchReactor(events, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	replica := DiscoveredService{Name: "alertmanager", Namespace: "monitoring", URL: "http://10.0.0.1:9093"}
+	changed := newServiceWatcher(client).Watch(ctx, DiscoveryConfig{Port: 9093, Endpoints: true}, replica)
+
+	// Other replicas coming and going do not count as a change
+	events.Modify(endpoints("10.0.0.1"))
+	select {
+	case <-changed:
+		t.Fatal("Expected no change while the selected replica is ready")
+	default:
+	}
+
+	events.Modify(endpoints("10.0.0.2"))
+	expectChange(t, changed)
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+// checkTimeout bounds the connectivity checks of metrics backends
+const checkTimeout = 10 * time.Second
+
+// CheckPushgateway verifies that the Pushgateway at url is ready to accept metrics
+func CheckPushgateway(url string, tlsConfig *tls.Config) error {
+	client := newHTTPClient(tlsConfig, checkTimeout)
+	resp, err := client.Get(strings.TrimSuffix(url, "/") + "/-/ready")
+	if err != nil {
+		return fmt.Errorf("failed to reach pushgateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pushgateway is not ready: status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newHTTPClient creates an HTTP client with the given timeout, using tlsConfig for HTTPS
+// connections if set
+func newHTTPClient(tlsConfig *tls.Config, timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+	return client
+}
+
+// CheckOTelCollector verifies that the OTLP endpoint, given as host:port or as a URL,
+// accepts connections
+func CheckOTelCollector(endpoint string) error {
+	address := endpoint
+	if u, err := neturl.Parse(endpoint); err == nil && u.Host != "" {
+		address = u.Host
+	}
+
+	conn, err := net.DialTimeout("tcp", address, checkTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to reach otel collector: %w", err)
+	}
+	return conn.Close()
+}
@@ -26,6 +26,66 @@ func (n *NoopPublisher) RecordSilenceExpiry(silenceID, ticketKey string, expires
 	// No-op
 }
 
+// RecordSilencedAlerts does nothing
+func (n *NoopPublisher) RecordSilencedAlerts(silenceID, ticketKey string, alerts int) {
+	// No-op
+}
+
+// RecordHygiene does nothing
+func (n *NoopPublisher) RecordHygiene(openTicketRatio, orphanRatio float64, medianAge time.Duration) {
+	// No-op
+}
+
+// RecordDeferredRun does nothing
+func (n *NoopPublisher) RecordDeferredRun(mode, holderMode string) {
+	// No-op
+}
+
+// RecordLeader does nothing
+func (n *NoopPublisher) RecordLeader(identity string, since time.Time) {
+	// No-op
+}
+
+// RecordThrottled does nothing
+func (n *NoopPublisher) RecordThrottled(system string, wait time.Duration) {
+	// No-op
+}
+
+// RecordRequestBudgetExhausted does nothing
+func (n *NoopPublisher) RecordRequestBudgetExhausted(system string) {
+	// No-op
+}
+
+// RecordForeignTicketRef does nothing
+func (n *NoopPublisher) RecordForeignTicketRef(project string) {
+	// No-op
+}
+
+// RecordAction does nothing
+func (n *NoopPublisher) RecordAction(action string, count int) {
+	// No-op
+}
+
+// RecordError does nothing
+func (n *NoopPublisher) RecordError(category string) {
+	// No-op
+}
+
+// RecordSyncSuccess does nothing
+func (n *NoopPublisher) RecordSyncSuccess(timestamp time.Time) {
+	// No-op
+}
+
+// RecordSyncDuration does nothing
+func (n *NoopPublisher) RecordSyncDuration(duration time.Duration) {
+	// No-op
+}
+
+// RecordRequestDuration does nothing
+func (n *NoopPublisher) RecordRequestDuration(system, operation string, duration time.Duration) {
+	// No-op
+}
+
 // Push does nothing
 func (n *NoopPublisher) Push() error {
 	return nil
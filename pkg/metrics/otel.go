@@ -28,6 +28,45 @@ type OTelPublisher struct {
 	// Metrics for recording
 	silenceChecks  []SilenceMetric
 	silenceExpiries []SilenceMetric
+	silencedAlerts  []SilenceMetric
+
+	// Hygiene indicators for the current run
+	hygieneRecorded   bool
+	hygieneOpenTicket float64
+	hygieneOrphan     float64
+	hygieneMedianAge  time.Duration
+
+	// Run deferral for the current run
+	deferredMode       string
+	deferredHolderMode string
+
+	// Leadership of this instance for the current run
+	leaderIdentity string
+	leaderSince    time.Time
+
+	// Backend throttling for the current run, keyed by system
+	throttledRequests map[string]int
+	throttleWait      map[string]time.Duration
+	budgetExhausted   map[string]bool
+
+	// Skipped references to tickets outside the allowed projects, keyed by project
+	foreignTicketRefs map[string]int
+
+	// Outcome of the current run
+	actions     map[string]int
+	errors      map[string]int
+	lastSuccess time.Time
+
+	// Durations of the current run and its backend requests
+	syncDuration     time.Duration
+	requestDurations []requestDuration
+}
+
+// requestDuration is the duration of a backend request
+type requestDuration struct {
+	system    string
+	operation string
+	duration  time.Duration
 }
 
 // OTelConfig holds configuration for OpenTelemetry
@@ -77,7 +116,7 @@ func NewOTelPublisher(cfg OTelConfig) (Publisher, error) {
 	// Create meter
 	meter := meterProvider.Meter("silence-manager")
 
-	log.Printf("Initialized OpenTelemetry metrics publisher: url=%s, insecure=%v", cfg.URL, cfg.Insecure)
+	log.Printf("Initialized OpenTelemetry metrics publisher: url=%s, insecure=%v", redactURL(cfg.URL), cfg.Insecure)
 
 	return &OTelPublisher{
 		meterProvider:   meterProvider,
@@ -121,6 +160,93 @@ func (o *OTelPublisher) RecordSilenceExpiry(silenceID, ticketKey string, expires
 	})
 }
 
+// RecordSilencedAlerts records how many alerts a silence currently mutes
+func (o *OTelPublisher) RecordSilencedAlerts(silenceID, ticketKey string, alerts int) {
+	o.silencedAlerts = append(o.silencedAlerts, SilenceMetric{
+		SilenceID: silenceID,
+		TicketKey: ticketKey,
+		Value:     float64(alerts),
+		Timestamp: time.Now(),
+	})
+}
+
+// RecordHygiene records the silence hygiene indicators for the current run
+func (o *OTelPublisher) RecordHygiene(openTicketRatio, orphanRatio float64, medianAge time.Duration) {
+	o.hygieneRecorded = true
+	o.hygieneOpenTicket = openTicketRatio
+	o.hygieneOrphan = orphanRatio
+	o.hygieneMedianAge = medianAge
+}
+
+// RecordDeferredRun records that a run was skipped because another instance holds the run lock
+func (o *OTelPublisher) RecordDeferredRun(mode, holderMode string) {
+	o.deferredMode = mode
+	o.deferredHolderMode = holderMode
+}
+
+// RecordLeader records that this instance holds leadership among its replicas
+func (o *OTelPublisher) RecordLeader(identity string, since time.Time) {
+	o.leaderIdentity = identity
+	o.leaderSince = since
+}
+
+// RecordThrottled records a request that a backend rejected because of rate limiting
+func (o *OTelPublisher) RecordThrottled(system string, wait time.Duration) {
+	if o.throttledRequests == nil {
+		o.throttledRequests = make(map[string]int)
+		o.throttleWait = make(map[string]time.Duration)
+	}
+	o.throttledRequests[system]++
+	o.throttleWait[system] += wait
+}
+
+// RecordRequestBudgetExhausted records that the run used up its request budget for a backend
+func (o *OTelPublisher) RecordRequestBudgetExhausted(system string) {
+	if o.budgetExhausted == nil {
+		o.budgetExhausted = make(map[string]bool)
+	}
+	o.budgetExhausted[system] = true
+}
+
+// RecordForeignTicketRef records a silence or alert referencing a ticket outside the allowed projects
+func (o *OTelPublisher) RecordForeignTicketRef(project string) {
+	if o.foreignTicketRefs == nil {
+		o.foreignTicketRefs = make(map[string]int)
+	}
+	o.foreignTicketRefs[project]++
+}
+
+// RecordAction records changes made by the run
+func (o *OTelPublisher) RecordAction(action string, count int) {
+	if o.actions == nil {
+		o.actions = make(map[string]int)
+	}
+	o.actions[action] += count
+}
+
+// RecordError records an error of the run
+func (o *OTelPublisher) RecordError(category string) {
+	if o.errors == nil {
+		o.errors = make(map[string]int)
+	}
+	o.errors[category]++
+}
+
+// RecordSyncSuccess records that a run completed without errors
+func (o *OTelPublisher) RecordSyncSuccess(timestamp time.Time) {
+	o.lastSuccess = timestamp
+}
+
+// RecordSyncDuration records how long the run took
+func (o *OTelPublisher) RecordSyncDuration(duration time.Duration) {
+	o.syncDuration = duration
+}
+
+// RecordRequestDuration records how long a backend request took
+func (o *OTelPublisher) RecordRequestDuration(system, operation string, duration time.Duration) {
+	o.requestDurations = append(o.requestDurations, requestDuration{system: system, operation: operation, duration: duration})
+}
+
 // Push sends all recorded metrics to the OpenTelemetry collector
 func (o *OTelPublisher) Push() error {
 	log.Println("Pushing metrics to OpenTelemetry collector")
@@ -210,6 +336,308 @@ func (o *OTelPublisher) Push() error {
 		}
 	}
 
+	// Record the alerts muted by each silence
+	if len(o.silencedAlerts) > 0 {
+		matchedAlerts, err := o.meter.Float64ObservableGauge("silence_manager_silence_matched_alerts",
+			metric.WithDescription("Number of alerts a silence currently mutes; 0 marks an idle silence"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create silence matched alerts gauge: %w", err)
+		}
+
+		counts := o.silencedAlerts // Capture for closure
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				for _, count := range counts {
+					obs.ObserveFloat64(matchedAlerts, count.Value,
+						metric.WithAttributes(
+							attribute.String("silence_id", count.SilenceID),
+							attribute.String("ticket", count.TicketKey),
+						),
+					)
+				}
+				return nil
+			},
+			matchedAlerts,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register silence matched alerts callback: %w", err)
+		}
+	}
+
+	// Record hygiene indicators
+	if o.hygieneRecorded {
+		openTicket, err := o.meter.Float64ObservableGauge("silence_manager_hygiene_open_ticket_ratio",
+			metric.WithDescription("Fraction of active silences backed by an open ticket"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create hygiene open ticket gauge: %w", err)
+		}
+		orphan, err := o.meter.Float64ObservableGauge("silence_manager_hygiene_orphan_ratio",
+			metric.WithDescription("Fraction of active silences without a ticket reference"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create hygiene orphan gauge: %w", err)
+		}
+		medianAge, err := o.meter.Float64ObservableGauge("silence_manager_hygiene_median_silence_age_seconds",
+			metric.WithDescription("Median age of active silences in seconds"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create hygiene median age gauge: %w", err)
+		}
+
+		openTicketRatio, orphanRatio, age := o.hygieneOpenTicket, o.hygieneOrphan, o.hygieneMedianAge // Capture for closure
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				obs.ObserveFloat64(openTicket, openTicketRatio)
+				obs.ObserveFloat64(orphan, orphanRatio)
+				obs.ObserveFloat64(medianAge, age.Seconds())
+				return nil
+			},
+			openTicket, orphan, medianAge,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register hygiene callback: %w", err)
+		}
+	}
+
+	// Record run deferral
+	if o.deferredMode != "" {
+		deferred, err := o.meter.Float64ObservableGauge("silence_manager_run_deferred",
+			metric.WithDescription("Set to 1 when a run was deferred because another instance holds the run lock"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create run deferred gauge: %w", err)
+		}
+
+		mode, holderMode := o.deferredMode, o.deferredHolderMode // Capture for closure
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				obs.ObserveFloat64(deferred, 1,
+					metric.WithAttributes(
+						attribute.String("mode", mode),
+						attribute.String("holder_mode", holderMode),
+					),
+				)
+				return nil
+			},
+			deferred,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register run deferred callback: %w", err)
+		}
+	}
+
+	// Record leadership
+	if o.leaderIdentity != "" {
+		leader, err := o.meter.Float64ObservableGauge("silence_manager_leader",
+			metric.WithDescription("Set to 1 by the replica holding the leader election lease"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create leader gauge: %w", err)
+		}
+
+		leaderSince, err := o.meter.Float64ObservableGauge("silence_manager_leader_since_timestamp_seconds",
+			metric.WithDescription("Unix timestamp of when the current leader acquired the leader election lease"),
+			metric.WithUnit("s"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create leader since gauge: %w", err)
+		}
+
+		identity, since := o.leaderIdentity, o.leaderSince // Capture for closure
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				attrs := metric.WithAttributes(attribute.String("identity", identity))
+				obs.ObserveFloat64(leader, 1, attrs)
+				obs.ObserveFloat64(leaderSince, float64(since.Unix()), attrs)
+				return nil
+			},
+			leader, leaderSince,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register leader callback: %w", err)
+		}
+	}
+
+	// Record backend throttling
+	if len(o.throttledRequests) > 0 || len(o.budgetExhausted) > 0 {
+		throttled, err := o.meter.Int64ObservableGauge("silence_manager_throttled_requests",
+			metric.WithDescription("Number of requests rejected by a backend's rate limit during the run"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create throttled requests gauge: %w", err)
+		}
+
+		throttleWait, err := o.meter.Float64ObservableGauge("silence_manager_throttle_wait_seconds",
+			metric.WithDescription("Time spent waiting before retrying throttled requests during the run"),
+			metric.WithUnit("s"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create throttle wait gauge: %w", err)
+		}
+
+		budgetExhausted, err := o.meter.Float64ObservableGauge("silence_manager_request_budget_exhausted",
+			metric.WithDescription("Set to 1 when the run used up its request budget for a backend"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create request budget gauge: %w", err)
+		}
+
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				for system, count := range o.throttledRequests {
+					attrs := metric.WithAttributes(attribute.String("system", system))
+					obs.ObserveInt64(throttled, int64(count), attrs)
+					obs.ObserveFloat64(throttleWait, o.throttleWait[system].Seconds(), attrs)
+				}
+				for system := range o.budgetExhausted {
+					obs.ObserveFloat64(budgetExhausted, 1, metric.WithAttributes(attribute.String("system", system)))
+				}
+				return nil
+			},
+			throttled, throttleWait, budgetExhausted,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register throttling callback: %w", err)
+		}
+	}
+
+	// Record skipped references to foreign tickets
+	if len(o.foreignTicketRefs) > 0 {
+		foreign, err := o.meter.Int64ObservableGauge("silence_manager_foreign_ticket_refs",
+			metric.WithDescription("Number of silences and alerts skipped during the run because they reference a ticket outside the allowed projects"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create foreign ticket references gauge: %w", err)
+		}
+
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				for project, count := range o.foreignTicketRefs {
+					obs.ObserveInt64(foreign, int64(count), metric.WithAttributes(attribute.String("project", project)))
+				}
+				return nil
+			},
+			foreign,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register foreign ticket references callback: %w", err)
+		}
+	}
+
+	// Record the actions of the run
+	if len(o.actions) > 0 {
+		counters := make(map[string]metric.Int64ObservableCounter, len(o.actions))
+		instruments := make([]metric.Observable, 0, len(o.actions))
+		for action := range o.actions {
+			counter, err := o.meter.Int64ObservableCounter("silence_manager_"+action+"_total",
+				metric.WithDescription(actionDescriptions[action]),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create %s counter: %w", action, err)
+			}
+			counters[action] = counter
+			instruments = append(instruments, counter)
+		}
+
+		actions := o.actions // Capture for closure
+		_, err := o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				for action, count := range actions {
+					obs.ObserveInt64(counters[action], int64(count))
+				}
+				return nil
+			},
+			instruments...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register actions callback: %w", err)
+		}
+	}
+
+	// Record the errors of the run
+	if len(o.errors) > 0 {
+		errorsTotal, err := o.meter.Int64ObservableCounter("silence_manager_errors_total",
+			metric.WithDescription("Total number of errors of synchronization runs by category"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create errors counter: %w", err)
+		}
+
+		errorCounts := o.errors // Capture for closure
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				for category, count := range errorCounts {
+					obs.ObserveInt64(errorsTotal, int64(count),
+						metric.WithAttributes(attribute.String("category", category)),
+					)
+				}
+				return nil
+			},
+			errorsTotal,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register errors callback: %w", err)
+		}
+	}
+
+	// Record the last successful run
+	if !o.lastSuccess.IsZero() {
+		lastSuccess, err := o.meter.Float64ObservableGauge("silence_manager_last_sync_success_timestamp_seconds",
+			metric.WithDescription("Unix timestamp of the last synchronization run that completed without errors"),
+			metric.WithUnit("s"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create last sync success gauge: %w", err)
+		}
+
+		success := o.lastSuccess // Capture for closure
+		_, err = o.meter.RegisterCallback(
+			func(ctx context.Context, obs metric.Observer) error {
+				obs.ObserveFloat64(lastSuccess, float64(success.Unix()))
+				return nil
+			},
+			lastSuccess,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register last sync success callback: %w", err)
+		}
+	}
+
+	// Record the duration of the run
+	if o.syncDuration > 0 {
+		syncDuration, err := o.meter.Float64Histogram("silence_manager_sync_duration_seconds",
+			metric.WithDescription("Duration of synchronization runs in seconds"),
+			metric.WithUnit("s"),
+			metric.WithExplicitBucketBoundaries(syncDurationBuckets...),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create sync duration histogram: %w", err)
+		}
+		syncDuration.Record(o.ctx, o.syncDuration.Seconds())
+	}
+
+	// Record the duration of backend requests
+	if len(o.requestDurations) > 0 {
+		requestDuration, err := o.meter.Float64Histogram("silence_manager_backend_request_duration_seconds",
+			metric.WithDescription("Duration of requests to Alertmanager and Jira in seconds"),
+			metric.WithUnit("s"),
+			metric.WithExplicitBucketBoundaries(requestDurationBuckets...),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create backend request duration histogram: %w", err)
+		}
+		for _, request := range o.requestDurations {
+			requestDuration.Record(o.ctx, request.duration.Seconds(),
+				metric.WithAttributes(
+					attribute.String("system", request.system),
+					attribute.String("operation", request.operation),
+				),
+			)
+		}
+	}
+
 	// Force a flush to ensure metrics are sent
 	if err := o.meterProvider.ForceFlush(o.ctx); err != nil {
 		return fmt.Errorf("failed to flush metrics: %w", err)
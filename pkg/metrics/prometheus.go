@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusExporter serves metrics for Prometheus to scrape, as an alternative to
+// pushing them. It lives as long as the process: every run records its metrics with a
+// publisher from NewPublisher, and pushing that publisher replaces the served run
+// metrics, so that scrapes never see a partly recorded run or silences of earlier runs.
+// The action and error counters and the latency histograms accumulate across runs. Process and Go runtime metrics
+// are always served.
+type PrometheusExporter struct {
+	runtime *prometheus.Registry
+	outcome *outcomeMetrics
+
+	mu     sync.RWMutex
+	latest prometheus.Gatherer
+}
+
+// NewPrometheusExporter creates an exporter serving process and Go runtime metrics until
+// the first run is pushed
+func NewPrometheusExporter() *PrometheusExporter {
+	runtime := prometheus.NewRegistry()
+	runtime.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	runtime.MustRegister(collectors.NewGoCollector())
+
+	return &PrometheusExporter{runtime: runtime, outcome: newOutcomeMetrics(runtime)}
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus exposition format
+func (e *PrometheusExporter) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gatherers := prometheus.Gatherers{e.runtime}
+		e.mu.RLock()
+		if e.latest != nil {
+			gatherers = append(gatherers, e.latest)
+		}
+		e.mu.RUnlock()
+
+		promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
+
+// NewPublisher creates a publisher recording the metrics of one run, served once pushed
+func (e *PrometheusExporter) NewPublisher() Publisher {
+	return &prometheusPublisher{exporter: e, registryMetrics: newRegistryMetrics()}
+}
+
+// prometheusPublisher records the metrics of a run for a PrometheusExporter
+type prometheusPublisher struct {
+	exporter *PrometheusExporter
+
+	*registryMetrics
+
+	// Outcome of the run, applied to the exporter's accumulating metrics when pushed
+	pending []func(*outcomeMetrics)
+}
+
+// RecordAction records changes made by the run
+func (p *prometheusPublisher) RecordAction(action string, count int) {
+	p.pending = append(p.pending, func(m *outcomeMetrics) { m.RecordAction(action, count) })
+}
+
+// RecordError records an error of the run
+func (p *prometheusPublisher) RecordError(category string) {
+	p.pending = append(p.pending, func(m *outcomeMetrics) { m.RecordError(category) })
+}
+
+// RecordSyncSuccess records that a run completed without errors
+func (p *prometheusPublisher) RecordSyncSuccess(timestamp time.Time) {
+	p.pending = append(p.pending, func(m *outcomeMetrics) { m.RecordSyncSuccess(timestamp) })
+}
+
+// RecordSyncDuration records how long the run took
+func (p *prometheusPublisher) RecordSyncDuration(duration time.Duration) {
+	p.pending = append(p.pending, func(m *outcomeMetrics) { m.RecordSyncDuration(duration) })
+}
+
+// RecordRequestDuration records how long a backend request took
+func (p *prometheusPublisher) RecordRequestDuration(system, operation string, duration time.Duration) {
+	p.pending = append(p.pending, func(m *outcomeMetrics) { m.RecordRequestDuration(system, operation, duration) })
+}
+
+// Push makes the metrics of this run the ones served by the exporter and adds its
+// outcome to the accumulating metrics
+func (p *prometheusPublisher) Push() error {
+	p.exporter.mu.Lock()
+	p.exporter.latest = p.registry
+	for _, apply := range p.pending {
+		apply(p.exporter.outcome)
+	}
+	p.exporter.mu.Unlock()
+	p.pending = nil
+
+	log.Println("Updated metrics served on /metrics")
+	return nil
+}
+
+// Close cleans up any resources
+func (p *prometheusPublisher) Close() error {
+	return nil
+}
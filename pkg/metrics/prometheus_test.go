@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T, exporter *PrometheusExporter) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	exporter.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	return rec.Body.String()
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	exporter := NewPrometheusExporter()
+
+	body := scrape(t, exporter)
+	if !strings.Contains(body, "go_goroutines") {
+		t.Error("Expected Go runtime metrics before the first run")
+	}
+	if strings.Contains(body, "silence_manager_silence_") {
+		t.Error("Expected no run metrics before the first run")
+	}
+
+	first := exporter.NewPublisher()
+	first.RecordSilenceCheck("silence-1", "OPS-1", time.Now())
+	if strings.Contains(scrape(t, exporter), "silence-1") {
+		t.Error("Expected run metrics to be served only once pushed")
+	}
+	if err := first.Push(); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+	if body := scrape(t, exporter); !strings.Contains(body, `silence_manager_silence_last_checked{silence_id="silence-1",ticket="OPS-1"}`) {
+		t.Errorf("Expected the pushed silence metric, got:\n%s", body)
+	}
+
+	// A later run replaces the metrics of silences it no longer manages
+	second := exporter.NewPublisher()
+	second.RecordSilenceCheck("silence-2", "OPS-2", time.Now())
+	if err := second.Push(); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+	body = scrape(t, exporter)
+	if strings.Contains(body, "silence-1") || !strings.Contains(body, "silence-2") {
+		t.Errorf("Expected only the metrics of the latest run, got:\n%s", body)
+	}
+	if !strings.Contains(body, "go_goroutines") {
+		t.Error("Expected Go runtime metrics alongside the run metrics")
+	}
+}
+
+func TestPrometheusExporter_OutcomeAccumulates(t *testing.T) {
+	exporter := NewPrometheusExporter()
+
+	for i := 0; i < 2; i++ {
+		publisher := exporter.NewPublisher()
+		publisher.RecordAction(ActionSilencesExtended, 2)
+		publisher.RecordError("silence")
+		publisher.RecordRequestDuration("jira", "get", 200*time.Millisecond)
+		if err := publisher.Push(); err != nil {
+			t.Fatalf("Push() failed: %v", err)
+		}
+	}
+	success := exporter.NewPublisher()
+	success.RecordSyncSuccess(time.Unix(1700000000, 0))
+	if err := success.Push(); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+
+	body := scrape(t, exporter)
+	for _, want := range []string{
+		"silence_manager_silences_extended_total 4",
+		`silence_manager_errors_total{category="silence"} 2`,
+		"silence_manager_last_sync_success_timestamp_seconds 1.7e+09",
+		`silence_manager_backend_request_duration_seconds_count{operation="get",system="jira"} 2`,
+		`silence_manager_backend_request_duration_seconds_bucket{operation="get",system="jira",le="0.25"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q, got:\n%s", want, body)
+		}
+	}
+}
@@ -1,30 +1,49 @@
 package metrics
 
 import (
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
 )
 
-// PushgatewayPublisher publishes metrics to a Prometheus Pushgateway
+// pushTimeout bounds a single push to the Pushgateway
+const pushTimeout = 30 * time.Second
+
+// silenceGroupingLabel groups the metrics of each silence on the Pushgateway
+const silenceGroupingLabel = "silence_id"
+
+// PushgatewayPublisher publishes metrics to a Prometheus Pushgateway. The metrics of the
+// run are pushed to the group {job, instance}, and the metrics of each silence to the
+// group {job, instance, silence_id}, so that the groups of silences that no longer exist
+// can be deleted after each push.
 type PushgatewayPublisher struct {
-	url      string
-	jobName  string
-	registry *prometheus.Registry
-
-	// Metrics
-	buildInfo         *prometheus.GaugeVec
-	silenceLastChecked *prometheus.GaugeVec
-	silenceExpiringIn  *prometheus.GaugeVec
+	url       string
+	jobName   string
+	instance  string
+	tlsConfig *tls.Config
+
+	*registryMetrics
+	*outcomeMetrics
+
+	// Set when the run could not list the silences, so that their groups are kept
+	silencesUnknown bool
 }
 
 // PushgatewayConfig holds configuration for Pushgateway
 type PushgatewayConfig struct {
-	URL     string
-	JobName string
+	URL       string
+	JobName   string
+	Instance  string      // Grouping label of this deployment's metrics
+	TLSConfig *tls.Config // Used for HTTPS connections instead of the defaults if set
 }
 
 // NewPushgatewayPublisher creates a new Pushgateway metrics publisher
@@ -36,87 +55,159 @@ func NewPushgatewayPublisher(cfg PushgatewayConfig) (Publisher, error) {
 	if cfg.JobName == "" {
 		cfg.JobName = "silence_manager"
 	}
+	if cfg.Instance == "" {
+		cfg.Instance = "silence-manager"
+	}
 
-	// Create a new registry for this publisher
-	registry := prometheus.NewRegistry()
-
-	// Create metrics
-	buildInfo := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "silence_manager_build_info",
-			Help: "Build information for silence-manager including version, commit, and build date",
-		},
-		[]string{"version", "commit", "build_date"},
-	)
-
-	silenceLastChecked := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "silence_manager_silence_last_checked",
-			Help: "Unix timestamp of when a silence was last checked",
-		},
-		[]string{"silence_id", "ticket"},
-	)
-
-	silenceExpiringIn := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "silence_manager_silence_expiring_in",
-			Help: "Seconds until a silence expires",
-		},
-		[]string{"silence_id", "ticket"},
-	)
-
-	// Register metrics
-	registry.MustRegister(buildInfo)
-	registry.MustRegister(silenceLastChecked)
-	registry.MustRegister(silenceExpiringIn)
-
-	log.Printf("Initialized Pushgateway metrics publisher: url=%s, job=%s", cfg.URL, cfg.JobName)
+	log.Printf("Initialized Pushgateway metrics publisher: url=%s, job=%s, instance=%s", redactURL(cfg.URL), cfg.JobName, cfg.Instance)
 
+	run := newRegistryMetrics()
 	return &PushgatewayPublisher{
-		url:                cfg.URL,
-		jobName:            cfg.JobName,
-		registry:           registry,
-		buildInfo:          buildInfo,
-		silenceLastChecked: silenceLastChecked,
-		silenceExpiringIn:  silenceExpiringIn,
+		url:             strings.TrimSuffix(cfg.URL, "/"),
+		jobName:         cfg.JobName,
+		instance:        cfg.Instance,
+		tlsConfig:       cfg.TLSConfig,
+		registryMetrics: run,
+		outcomeMetrics:  newOutcomeMetrics(run.registry),
 	}, nil
 }
 
-// RecordBuildInfo records version and build information
-func (p *PushgatewayPublisher) RecordBuildInfo(version, commit, buildDate string) {
-	p.buildInfo.WithLabelValues(version, commit, buildDate).Set(1)
+// RecordError records an error of the run. A run that could not list the silences from
+// Alertmanager does not delete the groups of silences it did not see.
+func (p *PushgatewayPublisher) RecordError(category string) {
+	if category == "alertmanager" {
+		p.silencesUnknown = true
+	}
+	p.outcomeMetrics.RecordError(category)
 }
 
-// RecordSilenceCheck records when a silence was checked
-func (p *PushgatewayPublisher) RecordSilenceCheck(silenceID, ticketKey string, timestamp time.Time) {
-	p.silenceLastChecked.WithLabelValues(silenceID, ticketKey).Set(float64(timestamp.Unix()))
+// Push sends all recorded metrics to the Pushgateway, replacing the previous metrics of
+// the run and of each silence, then deletes the groups of silences without metrics
+func (p *PushgatewayPublisher) Push() error {
+	log.Printf("Pushing metrics to Pushgateway: %s", redactURL(p.url))
+
+	families, err := p.registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	run, silences := splitBySilence(families)
+
+	if err := p.pusher("", run).Push(); err != nil {
+		return fmt.Errorf("failed to push metrics to pushgateway: %w", err)
+	}
+	for silenceID, families := range silences {
+		if err := p.pusher(silenceID, families).Push(); err != nil {
+			return fmt.Errorf("failed to push metrics of silence %s to pushgateway: %w", silenceID, err)
+		}
+	}
+
+	if p.silencesUnknown {
+		log.Println("Keeping the Pushgateway groups of all silences, as the silences could not be listed")
+	} else if err := p.deleteStaleGroups(silences); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully pushed metrics to Pushgateway (silences=%d)", len(silences))
+	return nil
 }
 
-// RecordSilenceExpiry records when a silence will expire
-func (p *PushgatewayPublisher) RecordSilenceExpiry(silenceID, ticketKey string, expiresAt time.Time) {
-	secondsUntilExpiry := time.Until(expiresAt).Seconds()
-	// If already expired, set to 0
-	if secondsUntilExpiry < 0 {
-		secondsUntilExpiry = 0
+// pusher creates a pusher for the group of the run, or of a silence if silenceID is set
+func (p *PushgatewayPublisher) pusher(silenceID string, families []*dto.MetricFamily) *push.Pusher {
+	pusher := push.New(p.url, p.jobName).
+		Grouping("instance", p.instance).
+		Client(newHTTPClient(p.tlsConfig, pushTimeout))
+	if silenceID != "" {
+		pusher = pusher.Grouping(silenceGroupingLabel, silenceID)
+	}
+	if families != nil {
+		pusher = pusher.Gatherer(prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+			return families, nil
+		}))
 	}
-	p.silenceExpiringIn.WithLabelValues(silenceID, ticketKey).Set(secondsUntilExpiry)
+	return pusher
 }
 
-// Push sends all recorded metrics to the Pushgateway
-func (p *PushgatewayPublisher) Push() error {
-	log.Printf("Pushing metrics to Pushgateway: %s", p.url)
+// pushgatewayGroups is the response of the Pushgateway's /api/v1/metrics endpoint
+type pushgatewayGroups struct {
+	Data []struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"data"`
+}
 
-	pusher := push.New(p.url, p.jobName).
-		Gatherer(p.registry)
+// deleteStaleGroups deletes the groups of this job and instance for silences that were
+// not pushed by this run
+func (p *PushgatewayPublisher) deleteStaleGroups(pushed map[string][]*dto.MetricFamily) error {
+	resp, err := newHTTPClient(p.tlsConfig, pushTimeout).Get(p.url + "/api/v1/metrics")
+	if err != nil {
+		return fmt.Errorf("failed to list pushgateway groups: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to list pushgateway groups: status code %d", resp.StatusCode)
+	}
 
-	if err := pusher.Push(); err != nil {
-		return fmt.Errorf("failed to push metrics to pushgateway: %w", err)
+	var groups pushgatewayGroups
+	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return fmt.Errorf("failed to decode pushgateway groups: %w", err)
 	}
 
-	log.Println("Successfully pushed metrics to Pushgateway")
+	deleted := 0
+	for _, group := range groups.Data {
+		silenceID := group.Labels[silenceGroupingLabel]
+		if group.Labels["job"] != p.jobName || group.Labels["instance"] != p.instance || silenceID == "" {
+			continue
+		}
+		if _, ok := pushed[silenceID]; ok {
+			continue
+		}
+		if err := p.pusher(silenceID, nil).Delete(); err != nil {
+			return fmt.Errorf("failed to delete pushgateway group of silence %s: %w", silenceID, err)
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		log.Printf("Deleted the Pushgateway groups of %d silence(s) that no longer exist", deleted)
+	}
 	return nil
 }
 
+// splitBySilence separates the metrics of each silence, identified by their silence_id
+// label, from the metrics of the run. The silence_id label is removed from the metrics
+// of a silence, as the Pushgateway adds it back from the grouping labels.
+func splitBySilence(families []*dto.MetricFamily) ([]*dto.MetricFamily, map[string][]*dto.MetricFamily) {
+	run := make([]*dto.MetricFamily, 0, len(families))
+	silences := make(map[string][]*dto.MetricFamily)
+	for _, family := range families {
+		runMetrics := make([]*dto.Metric, 0, len(family.Metric))
+		silenceMetrics := make(map[string][]*dto.Metric)
+		for _, metric := range family.Metric {
+			silenceID, labels := "", make([]*dto.LabelPair, 0, len(metric.Label))
+			for _, label := range metric.Label {
+				if label.GetName() == silenceGroupingLabel {
+					silenceID = label.GetValue()
+				} else {
+					labels = append(labels, label)
+				}
+			}
+			if silenceID == "" {
+				runMetrics = append(runMetrics, metric)
+				continue
+			}
+			stripped := proto.Clone(metric).(*dto.Metric)
+			stripped.Label = labels
+			silenceMetrics[silenceID] = append(silenceMetrics[silenceID], stripped)
+		}
+
+		if len(runMetrics) > 0 {
+			run = append(run, &dto.MetricFamily{Name: family.Name, Help: family.Help, Type: family.Type, Metric: runMetrics})
+		}
+		for silenceID, metrics := range silenceMetrics {
+			silences[silenceID] = append(silences[silenceID], &dto.MetricFamily{Name: family.Name, Help: family.Help, Type: family.Type, Metric: metrics})
+		}
+	}
+	return run, silences
+}
+
 // Close cleans up any resources
 func (p *PushgatewayPublisher) Close() error {
 	// No cleanup needed for Pushgateway
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePushgateway records the pushed and deleted groups and lists the given groups
+type fakePushgateway struct {
+	groups string // JSON data of /api/v1/metrics
+
+	mu      sync.Mutex
+	pushed  map[string]string // Body of each pushed group path
+	deleted []string
+}
+
+func (f *fakePushgateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/metrics":
+		io.WriteString(w, `{"status":"success","data":[`+f.groups+`]}`)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.pushed[r.URL.Path] = string(body)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodDelete:
+		f.deleted = append(f.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakePushgateway(t *testing.T, groups string) (*fakePushgateway, *PushgatewayPublisher) {
+	t.Helper()
+	fake := &fakePushgateway{groups: groups, pushed: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	publisher, err := NewPushgatewayPublisher(PushgatewayConfig{URL: server.URL, Instance: "prod"})
+	if err != nil {
+		t.Fatalf("NewPushgatewayPublisher() failed: %v", err)
+	}
+	return fake, publisher.(*PushgatewayPublisher)
+}
+
+const staleGroups = `
+	{"labels":{"job":"silence_manager","instance":"prod"}},
+	{"labels":{"job":"silence_manager","instance":"prod","silence_id":"silence-1"}},
+	{"labels":{"job":"silence_manager","instance":"prod","silence_id":"silence-gone"}},
+	{"labels":{"job":"silence_manager","instance":"staging","silence_id":"silence-other"}},
+	{"labels":{"job":"other","instance":"prod","silence_id":"silence-foreign"}}`
+
+func TestPushgatewayPublisher_GroupsPerSilence(t *testing.T) {
+	fake, publisher := newFakePushgateway(t, staleGroups)
+	publisher.RecordBuildInfo("1.0.0", "abc", "today")
+	publisher.RecordSilenceCheck("silence-1", "OPS-1", time.Now())
+	publisher.RecordSilenceExpiry("silence-1", "OPS-1", time.Now().Add(time.Hour))
+
+	if err := publisher.Push(); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+
+	run, ok := fake.pushed["/metrics/job/silence_manager/instance/prod"]
+	if !ok || !strings.Contains(run, "silence_manager_build_info") || strings.Contains(run, "silence-1") {
+		t.Errorf("Expected the run metrics without silences in the instance group, got %v", fake.pushed)
+	}
+	silence, ok := fake.pushed["/metrics/job/silence_manager/instance/prod/silence_id/silence-1"]
+	if !ok || !strings.Contains(silence, "silence_manager_silence_expiring_in") {
+		t.Fatalf("Expected the silence metrics in the silence group, got %v", fake.pushed)
+	}
+	if strings.Contains(silence, "silence_id") || strings.Contains(silence, "build_info") {
+		t.Errorf("Expected only the silence metrics without the grouping label, got:\n%s", silence)
+	}
+
+	sort.Strings(fake.deleted)
+	if len(fake.deleted) != 1 || fake.deleted[0] != "/metrics/job/silence_manager/instance/prod/silence_id/silence-gone" {
+		t.Errorf("Expected only the stale silence group of this instance to be deleted, got %v", fake.deleted)
+	}
+}
+
+func TestPushgatewayPublisher_KeepsGroupsWhenSilencesUnknown(t *testing.T) {
+	fake, publisher := newFakePushgateway(t, staleGroups)
+	publisher.RecordError("alertmanager")
+
+	if err := publisher.Push(); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+	if len(fake.deleted) != 0 {
+		t.Errorf("Expected no groups deleted when the silences could not be listed, got %v", fake.deleted)
+	}
+	if !strings.Contains(fake.pushed["/metrics/job/silence_manager/instance/prod"], "alertmanager") {
+		t.Errorf("Expected the error to be pushed, got %v", fake.pushed)
+	}
+}
@@ -0,0 +1,327 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registryMetrics records the metrics of a run in a Prometheus registry, which the
+// Pushgateway publisher pushes and the Prometheus exporter serves
+type registryMetrics struct {
+	registry *prometheus.Registry
+
+	buildInfo          *prometheus.GaugeVec
+	silenceLastChecked *prometheus.GaugeVec
+	silenceExpiringIn  *prometheus.GaugeVec
+	silencedAlerts     *prometheus.GaugeVec
+	hygieneOpenTicket  prometheus.Gauge
+	hygieneOrphan      prometheus.Gauge
+	hygieneMedianAge   prometheus.Gauge
+	runDeferred        *prometheus.GaugeVec
+	leader             *prometheus.GaugeVec
+	leaderSince        *prometheus.GaugeVec
+	throttledRequests  *prometheus.GaugeVec
+	throttleWait       *prometheus.GaugeVec
+	budgetExhausted    *prometheus.GaugeVec
+	foreignTicketRefs  *prometheus.GaugeVec
+}
+
+// newRegistryMetrics creates the metrics of a run in a new registry
+func newRegistryMetrics() *registryMetrics {
+	registry := prometheus.NewRegistry()
+
+	// Create metrics
+	buildInfo := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_build_info",
+			Help: "Build information for silence-manager including version, commit, and build date",
+		},
+		[]string{"version", "commit", "build_date"},
+	)
+
+	silenceLastChecked := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_silence_last_checked",
+			Help: "Unix timestamp of when a silence was last checked",
+		},
+		[]string{"silence_id", "ticket"},
+	)
+
+	silenceExpiringIn := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_silence_expiring_in",
+			Help: "Seconds until a silence expires",
+		},
+		[]string{"silence_id", "ticket"},
+	)
+
+	silencedAlerts := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_silence_matched_alerts",
+			Help: "Number of alerts a silence currently mutes; 0 marks an idle silence",
+		},
+		[]string{"silence_id", "ticket"},
+	)
+
+	hygieneOpenTicket := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_hygiene_open_ticket_ratio",
+			Help: "Fraction of active silences backed by an open ticket",
+		},
+	)
+
+	hygieneOrphan := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_hygiene_orphan_ratio",
+			Help: "Fraction of active silences without a ticket reference",
+		},
+	)
+
+	hygieneMedianAge := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_hygiene_median_silence_age_seconds",
+			Help: "Median age of active silences in seconds",
+		},
+	)
+
+	runDeferred := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_run_deferred",
+			Help: "Set to 1 when a run was deferred because another instance holds the run lock",
+		},
+		[]string{"mode", "holder_mode"},
+	)
+
+	leader := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_leader",
+			Help: "Set to 1 by the replica holding the leader election lease",
+		},
+		[]string{"identity"},
+	)
+
+	leaderSince := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_leader_since_timestamp_seconds",
+			Help: "Unix timestamp of when the current leader acquired the leader election lease",
+		},
+		[]string{"identity"},
+	)
+
+	throttledRequests := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_throttled_requests",
+			Help: "Number of requests rejected by a backend's rate limit during the run",
+		},
+		[]string{"system"},
+	)
+
+	throttleWait := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_throttle_wait_seconds",
+			Help: "Time spent waiting before retrying throttled requests during the run",
+		},
+		[]string{"system"},
+	)
+
+	budgetExhausted := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_request_budget_exhausted",
+			Help: "Set to 1 when the run used up its request budget for a backend",
+		},
+		[]string{"system"},
+	)
+
+	foreignTicketRefs := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "silence_manager_foreign_ticket_refs",
+			Help: "Number of silences and alerts skipped during the run because they reference a ticket outside the allowed projects",
+		},
+		[]string{"project"},
+	)
+
+	// Register metrics
+	registry.MustRegister(buildInfo)
+	registry.MustRegister(silenceLastChecked)
+	registry.MustRegister(silenceExpiringIn)
+	registry.MustRegister(silencedAlerts)
+	registry.MustRegister(hygieneOpenTicket)
+	registry.MustRegister(hygieneOrphan)
+	registry.MustRegister(hygieneMedianAge)
+	registry.MustRegister(runDeferred)
+	registry.MustRegister(leader)
+	registry.MustRegister(leaderSince)
+	registry.MustRegister(throttledRequests)
+	registry.MustRegister(throttleWait)
+	registry.MustRegister(budgetExhausted)
+	registry.MustRegister(foreignTicketRefs)
+
+	return &registryMetrics{
+		registry:           registry,
+		buildInfo:          buildInfo,
+		silenceLastChecked: silenceLastChecked,
+		silenceExpiringIn:  silenceExpiringIn,
+		silencedAlerts:     silencedAlerts,
+		hygieneOpenTicket:  hygieneOpenTicket,
+		hygieneOrphan:      hygieneOrphan,
+		hygieneMedianAge:   hygieneMedianAge,
+		runDeferred:        runDeferred,
+		leader:             leader,
+		leaderSince:        leaderSince,
+		throttledRequests:  throttledRequests,
+		throttleWait:       throttleWait,
+		budgetExhausted:    budgetExhausted,
+		foreignTicketRefs:  foreignTicketRefs,
+	}
+}
+
+// RecordBuildInfo records version and build information
+func (m *registryMetrics) RecordBuildInfo(version, commit, buildDate string) {
+	m.buildInfo.WithLabelValues(version, commit, buildDate).Set(1)
+}
+
+// RecordSilenceCheck records when a silence was checked
+func (m *registryMetrics) RecordSilenceCheck(silenceID, ticketKey string, timestamp time.Time) {
+	m.silenceLastChecked.WithLabelValues(silenceID, ticketKey).Set(float64(timestamp.Unix()))
+}
+
+// RecordSilenceExpiry records when a silence will expire
+func (m *registryMetrics) RecordSilenceExpiry(silenceID, ticketKey string, expiresAt time.Time) {
+	secondsUntilExpiry := time.Until(expiresAt).Seconds()
+	// If already expired, set to 0
+	if secondsUntilExpiry < 0 {
+		secondsUntilExpiry = 0
+	}
+	m.silenceExpiringIn.WithLabelValues(silenceID, ticketKey).Set(secondsUntilExpiry)
+}
+
+// RecordSilencedAlerts records how many alerts a silence currently mutes
+func (m *registryMetrics) RecordSilencedAlerts(silenceID, ticketKey string, alerts int) {
+	m.silencedAlerts.WithLabelValues(silenceID, ticketKey).Set(float64(alerts))
+}
+
+// RecordHygiene records the silence hygiene indicators for the current run
+func (m *registryMetrics) RecordHygiene(openTicketRatio, orphanRatio float64, medianAge time.Duration) {
+	m.hygieneOpenTicket.Set(openTicketRatio)
+	m.hygieneOrphan.Set(orphanRatio)
+	m.hygieneMedianAge.Set(medianAge.Seconds())
+}
+
+// RecordDeferredRun records that a run was skipped because another instance holds the run lock
+func (m *registryMetrics) RecordDeferredRun(mode, holderMode string) {
+	m.runDeferred.WithLabelValues(mode, holderMode).Set(1)
+}
+
+// RecordLeader records that this instance holds leadership among its replicas
+func (m *registryMetrics) RecordLeader(identity string, since time.Time) {
+	m.leader.WithLabelValues(identity).Set(1)
+	m.leaderSince.WithLabelValues(identity).Set(float64(since.Unix()))
+}
+
+// RecordThrottled records a request that a backend rejected because of rate limiting
+func (m *registryMetrics) RecordThrottled(system string, wait time.Duration) {
+	m.throttledRequests.WithLabelValues(system).Inc()
+	m.throttleWait.WithLabelValues(system).Add(wait.Seconds())
+}
+
+// RecordRequestBudgetExhausted records that the run used up its request budget for a backend
+func (m *registryMetrics) RecordRequestBudgetExhausted(system string) {
+	m.budgetExhausted.WithLabelValues(system).Set(1)
+}
+
+// RecordForeignTicketRef records a silence or alert referencing a ticket outside the allowed projects
+func (m *registryMetrics) RecordForeignTicketRef(project string) {
+	m.foreignTicketRefs.WithLabelValues(project).Inc()
+}
+
+// Histogram buckets of the run and backend request durations, in seconds. Runs span
+// from a second to over half an hour.
+var (
+	syncDurationBuckets    = prometheus.ExponentialBuckets(1, 2, 12)
+	requestDurationBuckets = prometheus.DefBuckets
+)
+
+// outcomeMetrics counts the actions and errors of runs, records the last successful run
+// and observes the duration of runs and backend requests in a Prometheus registry
+type outcomeMetrics struct {
+	actions         map[string]prometheus.Counter
+	errors          *prometheus.CounterVec
+	lastSuccess     prometheus.Gauge
+	syncDuration    prometheus.Histogram
+	requestDuration *prometheus.HistogramVec
+}
+
+// newOutcomeMetrics creates the outcome metrics in registry
+func newOutcomeMetrics(registry *prometheus.Registry) *outcomeMetrics {
+	m := &outcomeMetrics{
+		actions: make(map[string]prometheus.Counter, len(actionDescriptions)),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "silence_manager_errors_total",
+				Help: "Total number of errors of synchronization runs by category",
+			},
+			[]string{"category"},
+		),
+		lastSuccess: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "silence_manager_last_sync_success_timestamp_seconds",
+				Help: "Unix timestamp of the last synchronization run that completed without errors",
+			},
+		),
+		syncDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "silence_manager_sync_duration_seconds",
+				Help:    "Duration of synchronization runs in seconds",
+				Buckets: syncDurationBuckets,
+			},
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "silence_manager_backend_request_duration_seconds",
+				Help:    "Duration of requests to Alertmanager and Jira in seconds",
+				Buckets: requestDurationBuckets,
+			},
+			[]string{"system", "operation"},
+		),
+	}
+	for action, help := range actionDescriptions {
+		m.actions[action] = prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "silence_manager_" + action + "_total",
+			Help: help,
+		})
+		registry.MustRegister(m.actions[action])
+	}
+	registry.MustRegister(m.errors)
+	registry.MustRegister(m.lastSuccess)
+	registry.MustRegister(m.syncDuration)
+	registry.MustRegister(m.requestDuration)
+	return m
+}
+
+// RecordAction records changes made by the run
+func (m *outcomeMetrics) RecordAction(action string, count int) {
+	if counter, ok := m.actions[action]; ok {
+		counter.Add(float64(count))
+	}
+}
+
+// RecordError records an error of the run
+func (m *outcomeMetrics) RecordError(category string) {
+	m.errors.WithLabelValues(category).Inc()
+}
+
+// RecordSyncSuccess records that a run completed without errors
+func (m *outcomeMetrics) RecordSyncSuccess(timestamp time.Time) {
+	m.lastSuccess.Set(float64(timestamp.Unix()))
+}
+
+// RecordSyncDuration records how long the run took
+func (m *outcomeMetrics) RecordSyncDuration(duration time.Duration) {
+	m.syncDuration.Observe(duration.Seconds())
+}
+
+// RecordRequestDuration records how long a backend request took
+func (m *outcomeMetrics) RecordRequestDuration(system, operation string, duration time.Duration) {
+	m.requestDuration.WithLabelValues(system, operation).Observe(duration.Seconds())
+}
@@ -1,6 +1,9 @@
 package metrics
 
-import "time"
+import (
+	"net/url"
+	"time"
+)
 
 // Publisher defines the interface for metrics publishers
 type Publisher interface {
@@ -19,6 +22,63 @@ type Publisher interface {
 	// expiresAt is when the silence will expire
 	RecordSilenceExpiry(silenceID, ticketKey string, expiresAt time.Time)
 
+	// RecordSilencedAlerts records how many alerts a silence currently mutes
+	// silenceID is the unique identifier for the silence
+	// ticketKey is the associated ticket reference
+	// alerts is the number of alerts silenced by it, zero for an idle silence
+	RecordSilencedAlerts(silenceID, ticketKey string, alerts int)
+
+	// RecordHygiene records the silence hygiene indicators for the current run
+	// openTicketRatio is the fraction of silences backed by an open ticket
+	// orphanRatio is the fraction of silences without a ticket reference
+	// medianAge is the median age of all active silences
+	RecordHygiene(openTicketRatio, orphanRatio float64, medianAge time.Duration)
+
+	// RecordDeferredRun records that a run was skipped because another instance holds the run lock
+	// mode is the deployment model of this instance (cronjob or daemon)
+	// holderMode is the deployment model of the instance holding the lock
+	RecordDeferredRun(mode, holderMode string)
+
+	// RecordLeader records that this instance holds leadership among its replicas
+	// identity is the instance holding the leader election lease
+	// since is when it became the leader
+	RecordLeader(identity string, since time.Time)
+
+	// RecordThrottled records a request that a backend rejected because of rate limiting
+	// system is the throttling backend, e.g. jira
+	// wait is how long the request was delayed before its retry, zero if it was not retried
+	RecordThrottled(system string, wait time.Duration)
+
+	// RecordRequestBudgetExhausted records that the run used up its request budget for a backend
+	// system is the backend, e.g. jira
+	RecordRequestBudgetExhausted(system string)
+
+	// RecordForeignTicketRef records a silence or alert skipped because it references a ticket
+	// outside the allowed projects
+	// project is the project of the referenced ticket
+	RecordForeignTicketRef(project string)
+
+	// RecordAction records changes made by the run
+	// action is one of the Action constants
+	// count is the number of changes
+	RecordAction(action string, count int)
+
+	// RecordError records an error of the run
+	// category is the stage of the run that failed, e.g. alertmanager or silence
+	RecordError(category string)
+
+	// RecordSyncSuccess records that a run completed without errors
+	// timestamp is when the run completed
+	RecordSyncSuccess(timestamp time.Time)
+
+	// RecordSyncDuration records how long the run took
+	RecordSyncDuration(duration time.Duration)
+
+	// RecordRequestDuration records how long a backend request took
+	// system is the backend, e.g. jira or alertmanager
+	// operation is the kind of request, e.g. list, create, get or transition
+	RecordRequestDuration(system, operation string, duration time.Duration)
+
 	// Push sends all recorded metrics to the backend
 	// This should be called after all metrics have been recorded
 	Push() error
@@ -27,6 +87,23 @@ type Publisher interface {
 	Close() error
 }
 
+// Actions counted by RecordAction. Each is published as a counter named
+// silence_manager_<action>_total.
+const (
+	ActionSilencesExtended = "silences_extended"
+	ActionSilencesDeleted  = "silences_deleted"
+	ActionSilencesCreated  = "silences_created"
+	ActionTicketsReopened  = "tickets_reopened"
+)
+
+// actionDescriptions describe the counter of each action
+var actionDescriptions = map[string]string{
+	ActionSilencesExtended: "Total number of silences extended",
+	ActionSilencesDeleted:  "Total number of silences deleted",
+	ActionSilencesCreated:  "Total number of silences created",
+	ActionTicketsReopened:  "Total number of tickets reopened",
+}
+
 // SilenceMetric represents a metric associated with a silence
 type SilenceMetric struct {
 	SilenceID string
@@ -41,3 +118,12 @@ type BuildInfo struct {
 	Commit    string
 	BuildDate string
 }
+
+// redactURL hides the password of a URL for logging
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,75 @@
+package oncall
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// Escalation describes a problem that needs the attention of whoever is on call
+type Escalation struct {
+	// ID identifies the problem, so that repeated escalations of it are grouped
+	ID      string
+	Title   string
+	Message string
+	// Link points to the details, e.g. the ticket
+	Link string
+}
+
+// Escalator pages the right humans through their on-call tool
+type Escalator interface {
+	Escalate(escalation Escalation) error
+}
+
+// GrafanaOnCall triggers alert groups through a Grafana OnCall webhook integration, whose
+// escalation chain and routes decide who is paged
+type GrafanaOnCall struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewGrafanaOnCall creates a client of the webhook integration URL, e.g.
+// https://oncall.example.com/integrations/v1/formatted_webhook/XXXX/
+func NewGrafanaOnCall(webhookURL string) *GrafanaOnCall {
+	return &GrafanaOnCall{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type grafanaOnCallAlert struct {
+	AlertUID              string `json:"alert_uid"`
+	Title                 string `json:"title"`
+	State                 string `json:"state"`
+	Message               string `json:"message"`
+	LinkToUpstreamDetails string `json:"link_to_upstream_details,omitempty"`
+}
+
+// Escalate posts an alerting alert to the integration, in the format of its formatted
+// webhook, which Grafana OnCall groups by the escalation ID
+func (g *GrafanaOnCall) Escalate(escalation Escalation) error {
+	body, err := json.Marshal(grafanaOnCallAlert{
+		AlertUID:              escalation.ID,
+		Title:                 escalation.Title,
+		State:                 "alerting",
+		Message:               escalation.Message,
+		LinkToUpstreamDetails: escalation.Link,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	resp, err := g.httpClient.Post(g.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to trigger escalation: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package oncall
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Resolver looks up who is currently on call
+type Resolver interface {
+	// CurrentOnCall returns the email address of the person currently on call for the
+	// given schedule
+	CurrentOnCall(schedule string) (string, error)
+}
+
+// defaultTimeout is the HTTP timeout used by the on-call provider clients
+const defaultTimeout = 30 * time.Second
+
+// do sends an authenticated request and returns the response body if the status is 200
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query on-call schedule: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
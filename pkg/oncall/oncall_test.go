@@ -0,0 +1,121 @@
+package oncall
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPagerDuty_CurrentOnCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oncalls" {
+			t.Errorf("Expected path '/oncalls', got '%s'", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("schedule_ids[]"); got != "PSTORAGE" {
+			t.Errorf("Expected schedule PSTORAGE, got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Token token=secret" {
+			t.Errorf("Unexpected Authorization header %q", got)
+		}
+		w.Write([]byte(`{"oncalls": [
+			{"escalation_level": 2, "user": {"email": "manager@example.com"}},
+			{"escalation_level": 1, "user": {"email": "alice@example.com"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	email, err := NewPagerDuty(server.URL, "secret").CurrentOnCall("PSTORAGE")
+	if err != nil {
+		t.Fatalf("CurrentOnCall() failed: %v", err)
+	}
+	if email != "alice@example.com" {
+		t.Errorf("Expected the first escalation level, got %q", email)
+	}
+}
+
+func TestPagerDuty_NobodyOnCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"oncalls": []}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewPagerDuty(server.URL, "secret").CurrentOnCall("PSTORAGE"); err == nil {
+		t.Error("Expected error when nobody is on call")
+	}
+}
+
+func TestOpsgenie_CurrentOnCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/schedules/storage team/on-calls" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("scheduleIdentifierType") != "name" || r.URL.Query().Get("flat") != "true" {
+			t.Errorf("Unexpected query %q", r.URL.RawQuery)
+		}
+		if got := r.Header.Get("Authorization"); got != "GenieKey secret" {
+			t.Errorf("Unexpected Authorization header %q", got)
+		}
+		w.Write([]byte(`{"data": {"onCallRecipients": ["bob@example.com"]}}`))
+	}))
+	defer server.Close()
+
+	email, err := NewOpsgenie(server.URL, "secret").CurrentOnCall("storage team")
+	if err != nil {
+		t.Fatalf("CurrentOnCall() failed: %v", err)
+	}
+	if email != "bob@example.com" {
+		t.Errorf("Expected bob@example.com, got %q", email)
+	}
+}
+
+func TestOpsgenie_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Schedule not found"}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewOpsgenie(server.URL, "secret").CurrentOnCall("missing"); err == nil {
+		t.Error("Expected error for an unknown schedule")
+	}
+}
+
+func TestGrafanaOnCall_Escalate(t *testing.T) {
+	var got grafanaOnCallAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/integrations/v1/formatted_webhook/abc/" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode alert: %v", err)
+		}
+		w.Write([]byte("Ok."))
+	}))
+	defer server.Close()
+
+	err := NewGrafanaOnCall(server.URL + "/integrations/v1/formatted_webhook/abc/").Escalate(Escalation{
+		ID:      "silence-1",
+		Title:   "Silence about to lapse",
+		Message: "PROJ-1 is still open",
+		Link:    "https://jira.example.com/browse/PROJ-1",
+	})
+	if err != nil {
+		t.Fatalf("Escalate() failed: %v", err)
+	}
+	if got.AlertUID != "silence-1" || got.State != "alerting" || got.Title != "Silence about to lapse" ||
+		got.Message != "PROJ-1 is still open" || got.LinkToUpstreamDetails != "https://jira.example.com/browse/PROJ-1" {
+		t.Errorf("Unexpected alert: %+v", got)
+	}
+}
+
+func TestGrafanaOnCall_EscalateError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "integration not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if err := NewGrafanaOnCall(server.URL).Escalate(Escalation{ID: "silence-1"}); err == nil {
+		t.Error("Expected error for an unknown integration")
+	}
+}
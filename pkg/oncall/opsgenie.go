@@ -0,0 +1,62 @@
+package oncall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultOpsgenieURL is the Opsgenie REST API base URL. EU accounts use
+// https://api.eu.opsgenie.com.
+const DefaultOpsgenieURL = "https://api.opsgenie.com"
+
+// Opsgenie resolves the on-call user of an Opsgenie schedule
+type Opsgenie struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewOpsgenie creates an Opsgenie client authenticating with an API integration key
+func NewOpsgenie(baseURL, apiKey string) *Opsgenie {
+	if baseURL == "" {
+		baseURL = DefaultOpsgenieURL
+	}
+	return &Opsgenie{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type opsgenieOnCalls struct {
+	Data struct {
+		OnCallRecipients []string `json:"onCallRecipients"`
+	} `json:"data"`
+}
+
+// CurrentOnCall returns the username (email) of the user on call for the schedule name
+func (o *Opsgenie) CurrentOnCall(schedule string) (string, error) {
+	endpoint := fmt.Sprintf("%s/v2/schedules/%s/on-calls?scheduleIdentifierType=name&flat=true", o.baseURL, url.PathEscape(schedule))
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "GenieKey "+o.apiKey)
+
+	body, err := do(o.httpClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var response opsgenieOnCalls
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(response.Data.OnCallRecipients) == 0 {
+		return "", fmt.Errorf("nobody is on call for schedule %s", schedule)
+	}
+	return response.Data.OnCallRecipients[0], nil
+}
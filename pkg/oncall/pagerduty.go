@@ -0,0 +1,77 @@
+package oncall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultPagerDutyURL is the PagerDuty REST API base URL
+const DefaultPagerDutyURL = "https://api.pagerduty.com"
+
+// PagerDuty resolves the on-call user of a PagerDuty schedule
+type PagerDuty struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewPagerDuty creates a PagerDuty client authenticating with a REST API token
+func NewPagerDuty(baseURL, token string) *PagerDuty {
+	if baseURL == "" {
+		baseURL = DefaultPagerDutyURL
+	}
+	return &PagerDuty{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type pagerDutyOnCalls struct {
+	OnCalls []struct {
+		EscalationLevel int `json:"escalation_level"`
+		User            struct {
+			Email string `json:"email"`
+		} `json:"user"`
+	} `json:"oncalls"`
+}
+
+// CurrentOnCall returns the email of the first-level on-call user of the schedule ID
+func (p *PagerDuty) CurrentOnCall(schedule string) (string, error) {
+	query := url.Values{}
+	query.Set("schedule_ids[]", schedule)
+	query.Set("include[]", "users")
+	query.Set("earliest", "true")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/oncalls?%s", p.baseURL, query.Encode()), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token token="+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	body, err := do(p.httpClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var response pagerDutyOnCalls
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	email := ""
+	level := 0
+	for _, oc := range response.OnCalls {
+		if oc.User.Email != "" && (email == "" || oc.EscalationLevel < level) {
+			email, level = oc.User.Email, oc.EscalationLevel
+		}
+	}
+	if email == "" {
+		return "", fmt.Errorf("nobody is on call for schedule %s", schedule)
+	}
+	return email, nil
+}
@@ -0,0 +1,127 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PolicyStore reads SilencePolicies and writes back their status, e.g. through the
+// Kubernetes API
+type PolicyStore interface {
+	// GetPolicy returns a SilencePolicy, or nil if it no longer exists
+	GetPolicy(ctx context.Context, namespace, name string) (*SilencePolicy, error)
+
+	// UpdateStatus replaces the status of a policy
+	UpdateStatus(ctx context.Context, policy *SilencePolicy) error
+
+	// SetFinalizers replaces the finalizers of a policy
+	SetFinalizers(ctx context.Context, policy *SilencePolicy, finalizers []string) error
+}
+
+// Controller reconciles SilencePolicies one request at a time. It implements
+// controller-runtime's reconcile.Reconciler: a manager feeds it the policies that
+// changed from its work queue, and retries those that failed with backoff.
+type Controller struct {
+	store      PolicyStore
+	reconciler *Reconciler
+	resync     time.Duration
+}
+
+// NewController creates a controller. Reconciled policies are requeued after resync at
+// the latest, so that expired silences are recreated and reopened tickets noticed.
+func NewController(store PolicyStore, reconciler *Reconciler, resync time.Duration) *Controller {
+	return &Controller{store: store, reconciler: reconciler, resync: resync}
+}
+
+// Reconcile reconciles one policy. A failure is returned so that the work queue retries
+// the policy with backoff; it is also reported on the policy's status.
+func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	policy, err := c.store.GetPolicy(ctx, req.Namespace, req.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if policy == nil {
+		return reconcile.Result{}, nil
+	}
+
+	if policy.Deleting {
+		if !hasFinalizer(policy) {
+			return reconcile.Result{}, nil
+		}
+		if err := c.reconciler.Finalize(policy); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to finalize SilencePolicy %s: %w", policy, err)
+		}
+		if err := c.store.SetFinalizers(ctx, policy, removeFinalizer(policy.Finalizers)); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to remove finalizer of SilencePolicy %s: %w", policy, err)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if !hasFinalizer(policy) {
+		finalizers := append(append([]string(nil), policy.Finalizers...), Finalizer)
+		if err := c.store.SetFinalizers(ctx, policy, finalizers); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to add finalizer to SilencePolicy %s: %w", policy, err)
+		}
+		policy.Finalizers = finalizers
+	}
+
+	status, reconcileErr := c.reconciler.Reconcile(policy)
+	if reconcileErr != nil {
+		log.Printf("Warning: failed to reconcile SilencePolicy %s: %v", policy, reconcileErr)
+	}
+	// Unchanged statuses are not written, sparing the API server and Jira a pointless
+	// round trip. They are compared in their serialized form, as times read back from
+	// the API lose their monotonic clock reading and location.
+	if !sameStatus(status, policy.Status) {
+		policy.Status = status
+		if err := c.store.UpdateStatus(ctx, policy); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update status of SilencePolicy %s: %w", policy, err)
+		}
+	}
+	if reconcileErr != nil {
+		return reconcile.Result{}, reconcileErr
+	}
+	return reconcile.Result{RequeueAfter: c.requeueAfter(status)}, nil
+}
+
+// requeueAfter returns when to look at a reconciled policy again: once its silence has
+// ended, unless the resync interval passes first
+func (c *Controller) requeueAfter(status PolicyStatus) time.Duration {
+	if status.EndsAt != nil {
+		if until := time.Until(*status.EndsAt) + time.Second; until > 0 && until < c.resync {
+			return until
+		}
+	}
+	return c.resync
+}
+
+func sameStatus(a, b PolicyStatus) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func hasFinalizer(policy *SilencePolicy) bool {
+	for _, f := range policy.Finalizers {
+		if f == Finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(finalizers []string) []string {
+	var out []string
+	for _, f := range finalizers {
+		if f != Finalizer {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,256 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// Backend is the ticket system and synchronizer used for the tickets of one project
+type Backend struct {
+	Tickets      ticket.TicketSystem
+	Synchronizer *sync.Synchronizer
+}
+
+// Backends returns the backend of a Jira project; an empty project selects the default
+type Backends func(project string) (Backend, error)
+
+// requestBudgetResetter is implemented by ticket systems that bound the number of
+// requests per run
+type requestBudgetResetter interface {
+	ResetRequestBudget()
+}
+
+// Reconciler reconciles SilencePolicies into managed silences and tickets. Once created,
+// silences are extended and expired by the regular synchronization like any other
+// managed silence; the reconciler recreates them while the policy exists and its ticket
+// is open.
+type Reconciler struct {
+	alertManager alertmanager.AlertManager
+	backends     Backends
+	now          func() time.Time
+}
+
+// NewReconciler creates a reconciler
+func NewReconciler(am alertmanager.AlertManager, backends Backends) *Reconciler {
+	return &Reconciler{alertManager: am, backends: backends, now: time.Now}
+}
+
+// Reconcile brings the silence and ticket of policy in line with its spec and returns
+// its new status. The error, if any, is also reported through the Ready condition; it
+// is returned so the caller can retry.
+func (r *Reconciler) Reconcile(policy *SilencePolicy) (PolicyStatus, error) {
+	status := policy.Status
+	status.Conditions = append([]Condition(nil), policy.Status.Conditions...)
+	// Times are stored in UTC with second precision, as Kubernetes serializes them
+	now := r.now().UTC().Truncate(time.Second)
+	changed := policy.Generation != status.ObservedGeneration
+
+	fail := func(reason string, err error) (PolicyStatus, error) {
+		status.setCondition(Condition{Type: ConditionReady, Status: ConditionFalse, Reason: reason, Message: err.Error(), LastTransitionTime: now})
+		return status, err
+	}
+
+	def, err := silenceDefinition(policy)
+	if err != nil {
+		// Retrying does not help until the spec changes
+		status.ObservedGeneration = policy.Generation
+		status, _ = fail("InvalidSpec", err)
+		return status, nil
+	}
+	backend, err := r.backend(policy.Spec.Project)
+	if err != nil {
+		return fail("InvalidSpec", err)
+	}
+
+	var current *alertmanager.Silence
+	if status.SilenceID != "" {
+		current, err = r.alertManager.GetSilence(status.SilenceID)
+		if err != nil && !errors.Is(err, alertmanager.ErrSilenceNotFound) {
+			return fail("AlertmanagerUnavailable", fmt.Errorf("failed to get silence %s: %w", status.SilenceID, err))
+		}
+	}
+
+	key := status.Ticket
+	if key == "" {
+		key = policy.Spec.Ticket
+	}
+	if key != "" {
+		t, err := backend.Tickets.GetTicket(key)
+		if err != nil {
+			return fail("TicketUnavailable", fmt.Errorf("failed to get ticket %s: %w", key, err))
+		}
+		if !backend.Tickets.IsOpen(t) {
+			// The regular synchronization expires the silence of a closed ticket, and
+			// recreating it would silence alerts the ticket's owner considers resolved
+			status.Ticket = key
+			status.ObservedGeneration = policy.Generation
+			status.setCondition(Condition{Type: ConditionReady, Status: ConditionTrue, Reason: "Reconciled", LastTransitionTime: now})
+			status.setCondition(Condition{Type: ConditionSilenced, Status: ConditionFalse, Reason: "TicketClosed",
+				Message: fmt.Sprintf("Ticket %s is %s", key, t.Status), LastTransitionTime: now})
+			return status, nil
+		}
+	}
+
+	active := current != nil && current.EndsAt.After(now)
+	if active && changed && !sameMatchers(current.Matchers, def.Matchers) {
+		// Alertmanager gives a silence a new ID when its matchers change, so the old
+		// silence is expired and a new one is linked to the same ticket
+		if err := r.alertManager.DeleteSilence(current.ID); err != nil {
+			return fail("AlertmanagerUnavailable", fmt.Errorf("failed to expire silence %s: %w", current.ID, err))
+		}
+		log.Printf("Expired silence %s of SilencePolicy %s after its matchers changed", current.ID, policy)
+		active = false
+	}
+
+	if !active {
+		def.Ticket = key
+		silence, err := backend.Synchronizer.CreateSilence(def)
+		if err != nil {
+			return fail("CreateFailed", err)
+		}
+		log.Printf("Created silence %s for SilencePolicy %s", silence.ID, policy)
+		if key == "" {
+			changed = true // Route the new ticket
+		}
+		current = silence
+		key = silence.TicketRef
+	}
+	if changed {
+		routeTicket(backend.Tickets, key, policy.Spec.Routing)
+	}
+
+	endsAt := current.EndsAt.UTC()
+	status.SilenceID = current.ID
+	status.Ticket = key
+	status.EndsAt = &endsAt
+	status.ObservedGeneration = policy.Generation
+	status.setCondition(Condition{Type: ConditionReady, Status: ConditionTrue, Reason: "Reconciled", LastTransitionTime: now})
+	status.setCondition(Condition{Type: ConditionSilenced, Status: ConditionTrue, Reason: "Active",
+		Message: fmt.Sprintf("Silence %s is active until %s", current.ID, endsAt.Format(time.RFC3339)), LastTransitionTime: now})
+	return status, nil
+}
+
+// backend returns the backend of project with a fresh request budget, since backends are
+// kept across reconciles and each reconcile is a run of its own
+func (r *Reconciler) backend(project string) (Backend, error) {
+	backend, err := r.backends(project)
+	if err != nil {
+		return backend, err
+	}
+	if resetter, ok := backend.Tickets.(requestBudgetResetter); ok {
+		resetter.ResetRequestBudget()
+	}
+	return backend, nil
+}
+
+// Finalize expires the silence of a deleted policy and notes the deletion on its ticket
+func (r *Reconciler) Finalize(policy *SilencePolicy) error {
+	if policy.Status.SilenceID == "" {
+		return nil
+	}
+	silence, err := r.alertManager.GetSilence(policy.Status.SilenceID)
+	if errors.Is(err, alertmanager.ErrSilenceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get silence %s: %w", policy.Status.SilenceID, err)
+	}
+	if !silence.EndsAt.After(r.now()) {
+		return nil
+	}
+	if err := r.alertManager.DeleteSilence(silence.ID); err != nil {
+		return fmt.Errorf("failed to expire silence %s: %w", silence.ID, err)
+	}
+	log.Printf("Expired silence %s of deleted SilencePolicy %s", silence.ID, policy)
+
+	if policy.Status.Ticket != "" {
+		if backend, err := r.backend(policy.Spec.Project); err == nil {
+			msg := fmt.Sprintf("Silence %s expired because SilencePolicy %s was deleted.", silence.ID, policy)
+			if err := backend.Tickets.AddComment(policy.Status.Ticket, msg); err != nil {
+				log.Printf("Warning: failed to add comment to ticket %s: %v", policy.Status.Ticket, err)
+			}
+		}
+	}
+	return nil
+}
+
+// silenceDefinition converts the spec of policy into a silence definition
+func silenceDefinition(policy *SilencePolicy) (sync.SilenceDefinition, error) {
+	if len(policy.Spec.Matchers) == 0 {
+		return sync.SilenceDefinition{}, fmt.Errorf("at least one matcher is required")
+	}
+	matchers, err := sync.ParseMatchers(policy.Spec.Matchers)
+	if err != nil {
+		return sync.SilenceDefinition{}, err
+	}
+
+	def := sync.SilenceDefinition{
+		Matchers:  matchers,
+		Comment:   policy.Spec.Comment,
+		CreatedBy: "SilencePolicy " + policy.String(),
+		Summary:   policy.Spec.Summary,
+	}
+	if def.Comment == "" {
+		def.Comment = "Managed by SilencePolicy " + policy.String()
+	}
+	if policy.Spec.Duration != "" {
+		if def.Duration, err = sync.ParseDuration(policy.Spec.Duration); err != nil {
+			return sync.SilenceDefinition{}, fmt.Errorf("invalid duration %q: %w", policy.Spec.Duration, err)
+		}
+	}
+	return def, nil
+}
+
+// routeTicket applies the routing of a policy to its ticket. Failures are logged, as
+// the silence itself is in place.
+func routeTicket(tickets ticket.TicketSystem, key string, routing Routing) {
+	if routing.Assignee != "" {
+		if err := tickets.AssignTicket(key, routing.Assignee); err != nil {
+			log.Printf("Warning: failed to assign ticket %s to %s: %v", key, routing.Assignee, err)
+		}
+	}
+	if routing.Priority != "" {
+		if err := tickets.SetPriority(key, routing.Priority); err != nil {
+			log.Printf("Warning: failed to set priority of ticket %s: %v", key, err)
+		}
+	}
+	if len(routing.Labels) > 0 {
+		if err := tickets.UpdateLabels(key, routing.Labels, nil); err != nil {
+			log.Printf("Warning: failed to label ticket %s: %v", key, err)
+		}
+	}
+	for _, watcher := range routing.Watchers {
+		if err := tickets.AddWatcher(key, watcher); err != nil {
+			log.Printf("Warning: failed to add watcher %s to ticket %s: %v", watcher, key, err)
+		}
+	}
+}
+
+// sameMatchers reports whether two sets of matchers are equal regardless of their order
+func sameMatchers(a, b []alertmanager.Matcher) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keys := func(matchers []alertmanager.Matcher) []string {
+		out := make([]string, 0, len(matchers))
+		for _, m := range matchers {
+			out = append(out, m.String())
+		}
+		sort.Strings(out)
+		return out
+	}
+	ka, kb := keys(a), keys(b)
+	for i := range ka {
+		if ka[i] != kb[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,412 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+type fakeAlertManager struct {
+	silences map[string]*alertmanager.Silence
+	nextID   int
+	deleted  []string
+}
+
+func newFakeAlertManager() *fakeAlertManager {
+	return &fakeAlertManager{silences: make(map[string]*alertmanager.Silence)}
+}
+
+func (f *fakeAlertManager) GetSilence(id string) (*alertmanager.Silence, error) {
+	s, ok := f.silences[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", alertmanager.ErrSilenceNotFound, id)
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (f *fakeAlertManager) ListSilences() ([]*alertmanager.Silence, error) {
+	var out []*alertmanager.Silence
+	for _, s := range f.silences {
+		if s.EndsAt.After(time.Now()) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAlertManager) CreateSilence(s *alertmanager.Silence) (string, error) {
+	f.nextID++
+	copied := *s
+	copied.ID = fmt.Sprintf("silence-%d", f.nextID)
+	f.silences[copied.ID] = &copied
+	return copied.ID, nil
+}
+
+func (f *fakeAlertManager) UpdateSilence(s *alertmanager.Silence) error {
+	f.silences[s.ID] = s
+	return nil
+}
+
+func (f *fakeAlertManager) DeleteSilence(id string) error {
+	f.silences[id].EndsAt = time.Now()
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeAlertManager) ExtendSilence(id string, newEndTime time.Time) error {
+	f.silences[id].EndsAt = newEndTime
+	return nil
+}
+
+func (f *fakeAlertManager) GetAlerts(matchers []alertmanager.Matcher) ([]*alertmanager.Alert, error) {
+	return nil, nil
+}
+
+func (f *fakeAlertManager) GetSilencedAlerts(silenceID string) ([]*alertmanager.Alert, error) {
+	return nil, nil
+}
+
+// fakeTickets implements the ticket operations used by the reconciler; other methods
+// of the embedded interface are not called
+type fakeTickets struct {
+	ticket.TicketSystem
+	tickets  map[string]*ticket.Ticket
+	comments map[string][]string
+	assigned map[string]string
+	labels   map[string][]string
+}
+
+func newFakeTickets() *fakeTickets {
+	return &fakeTickets{
+		tickets:  make(map[string]*ticket.Ticket),
+		comments: make(map[string][]string),
+		assigned: make(map[string]string),
+		labels:   make(map[string][]string),
+	}
+}
+
+func (f *fakeTickets) GetTicket(key string) (*ticket.Ticket, error) {
+	t, ok := f.tickets[key]
+	if !ok {
+		return nil, fmt.Errorf("ticket %s not found", key)
+	}
+	return t, nil
+}
+
+func (f *fakeTickets) CreateTicket(t *ticket.Ticket) (string, error) {
+	key := fmt.Sprintf("OPS-%d", len(f.tickets)+1)
+	t.Key = key
+	t.Status = ticket.StatusOpen
+	f.tickets[key] = t
+	return key, nil
+}
+
+func (f *fakeTickets) AddComment(key, comment string) error {
+	f.comments[key] = append(f.comments[key], comment)
+	return nil
+}
+
+func (f *fakeTickets) AssignTicket(key, user string) error {
+	f.assigned[key] = user
+	return nil
+}
+
+func (f *fakeTickets) UpdateLabels(key string, add, remove []string) error {
+	f.labels[key] = append(f.labels[key], add...)
+	return nil
+}
+
+func (f *fakeTickets) IsOpen(t *ticket.Ticket) bool {
+	return t.Status == ticket.StatusOpen || t.Status == ticket.StatusInProgress || t.Status == ticket.StatusReopened
+}
+
+func newTestReconciler(am *fakeAlertManager, tickets *fakeTickets) *Reconciler {
+	synchronizer := sync.NewSynchronizer(am, tickets, sync.SyncConfig{DefaultSilenceDuration: 24 * time.Hour})
+	return NewReconciler(am, func(project string) (Backend, error) {
+		if project != "" && project != "OPS" {
+			return Backend{}, fmt.Errorf("unknown project %s", project)
+		}
+		return Backend{Tickets: tickets, Synchronizer: synchronizer}, nil
+	})
+}
+
+func testPolicy() *SilencePolicy {
+	return &SilencePolicy{
+		Name:       "disk-replacement",
+		Namespace:  "storage",
+		Generation: 1,
+		Spec: PolicySpec{
+			Matchers: []string{`alertname="DiskFull"`, `instance="db-1"`},
+			Summary:  "Disk replacement on db-1",
+			Duration: "3d",
+			Routing:  Routing{Assignee: "storage-oncall@example.com", Labels: []string{"storage"}},
+		},
+	}
+}
+
+func TestReconcile_CreatesSilenceAndTicket(t *testing.T) {
+	am, tickets := newFakeAlertManager(), newFakeTickets()
+	r := newTestReconciler(am, tickets)
+	policy := testPolicy()
+
+	status, err := r.Reconcile(policy)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if status.SilenceID == "" || status.Ticket != "OPS-1" {
+		t.Fatalf("Expected silence linked to OPS-1, got %+v", status)
+	}
+	if status.ObservedGeneration != 1 {
+		t.Errorf("Expected observed generation 1, got %d", status.ObservedGeneration)
+	}
+	if c := status.FindCondition(ConditionReady); c == nil || c.Status != ConditionTrue {
+		t.Errorf("Expected Ready condition to be true, got %+v", c)
+	}
+	if c := status.FindCondition(ConditionSilenced); c == nil || c.Status != ConditionTrue {
+		t.Errorf("Expected Silenced condition to be true, got %+v", c)
+	}
+
+	silence := am.silences[status.SilenceID]
+	if silence.TicketRef != "OPS-1" {
+		t.Errorf("Expected silence to reference OPS-1, got %q", silence.TicketRef)
+	}
+	if d := time.Until(silence.EndsAt); d < 71*time.Hour || d > 73*time.Hour {
+		t.Errorf("Expected a 3 day silence, got one ending in %v", d)
+	}
+	if tickets.assigned["OPS-1"] != "storage-oncall@example.com" {
+		t.Errorf("Expected ticket to be routed to storage-oncall, got %q", tickets.assigned["OPS-1"])
+	}
+
+	// Reconciling again without changes leaves everything in place
+	policy.Status = status
+	again, err := r.Reconcile(policy)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if again.SilenceID != status.SilenceID || len(am.silences) != 1 {
+		t.Errorf("Expected the existing silence to be kept, got %+v", again)
+	}
+}
+
+func TestReconcile_MatchersChanged(t *testing.T) {
+	am, tickets := newFakeAlertManager(), newFakeTickets()
+	r := newTestReconciler(am, tickets)
+	policy := testPolicy()
+	status, err := r.Reconcile(policy)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	policy.Status = status
+	policy.Generation = 2
+	policy.Spec.Matchers = []string{`alertname="DiskFull"`, `instance=~"db-[12]"`}
+	updated, err := r.Reconcile(policy)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if updated.SilenceID == status.SilenceID {
+		t.Fatal("Expected a new silence for the changed matchers")
+	}
+	if len(am.deleted) != 1 || am.deleted[0] != status.SilenceID {
+		t.Errorf("Expected the old silence to be expired, got %v", am.deleted)
+	}
+	if updated.Ticket != "OPS-1" || len(tickets.tickets) != 1 {
+		t.Errorf("Expected the new silence to reuse ticket OPS-1, got %s", updated.Ticket)
+	}
+}
+
+func TestReconcile_TicketClosed(t *testing.T) {
+	am, tickets := newFakeAlertManager(), newFakeTickets()
+	tickets.tickets["OPS-7"] = &ticket.Ticket{Key: "OPS-7", Status: ticket.StatusClosed}
+	r := newTestReconciler(am, tickets)
+	policy := testPolicy()
+	policy.Spec.Ticket = "OPS-7"
+
+	status, err := r.Reconcile(policy)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(am.silences) != 0 {
+		t.Error("Expected no silence for a closed ticket")
+	}
+	if c := status.FindCondition(ConditionSilenced); c == nil || c.Reason != "TicketClosed" {
+		t.Errorf("Expected Silenced condition with reason TicketClosed, got %+v", c)
+	}
+}
+
+func TestReconcile_InvalidSpec(t *testing.T) {
+	r := newTestReconciler(newFakeAlertManager(), newFakeTickets())
+	policy := testPolicy()
+	policy.Spec.Duration = "soon"
+
+	status, err := r.Reconcile(policy)
+	if err != nil {
+		t.Fatalf("Expected invalid specs not to be retried, got %v", err)
+	}
+	if c := status.FindCondition(ConditionReady); c == nil || c.Status != ConditionFalse || c.Reason != "InvalidSpec" {
+		t.Errorf("Expected Ready condition to be false with reason InvalidSpec, got %+v", c)
+	}
+
+	policy.Spec.Duration = ""
+	policy.Spec.Project = "UNKNOWN"
+	if _, err := r.Reconcile(policy); err == nil {
+		t.Error("Expected error for an unknown project")
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	am, tickets := newFakeAlertManager(), newFakeTickets()
+	r := newTestReconciler(am, tickets)
+	policy := testPolicy()
+	status, err := r.Reconcile(policy)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	policy.Status = status
+	if err := r.Finalize(policy); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	if len(am.deleted) != 1 {
+		t.Errorf("Expected the silence to be expired, got %v", am.deleted)
+	}
+	if comments := tickets.comments["OPS-1"]; len(comments) != 2 {
+		t.Errorf("Expected a deletion comment on OPS-1, got %v", comments)
+	}
+}
+
+type fakeStore struct {
+	policy    *SilencePolicy
+	updated   int
+	finalized bool
+}
+
+func (f *fakeStore) GetPolicy(ctx context.Context, namespace, name string) (*SilencePolicy, error) {
+	if f.policy == nil || f.policy.Namespace != namespace || f.policy.Name != name {
+		return nil, nil
+	}
+	return f.policy, nil
+}
+
+func (f *fakeStore) UpdateStatus(ctx context.Context, policy *SilencePolicy) error {
+	f.updated++
+	return nil
+}
+
+func (f *fakeStore) SetFinalizers(ctx context.Context, policy *SilencePolicy, finalizers []string) error {
+	f.finalized = len(finalizers) == 0
+	policy.Finalizers = finalizers
+	return nil
+}
+
+func TestController_Reconcile(t *testing.T) {
+	am, tickets := newFakeAlertManager(), newFakeTickets()
+	policy := testPolicy()
+	store := &fakeStore{policy: policy}
+	c := NewController(store, newTestReconciler(am, tickets), 7*24*time.Hour)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "storage", Name: "disk-replacement"}}
+
+	result, err := c.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if !hasFinalizer(policy) {
+		t.Error("Expected the finalizer to be added")
+	}
+	if store.updated != 1 {
+		t.Errorf("Expected one status update, got %d", store.updated)
+	}
+	// Requeued once the 3d silence ends rather than after the weekly resync
+	if result.RequeueAfter < 71*time.Hour || result.RequeueAfter > 73*time.Hour {
+		t.Errorf("Expected a requeue when the silence ends, got %v", result.RequeueAfter)
+	}
+
+	// An unchanged status is not written again
+	if _, err := c.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if store.updated != 1 {
+		t.Errorf("Expected no further status update, got %d", store.updated)
+	}
+
+	policy.Deleting = true
+	if _, err := c.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if len(am.deleted) != 1 || !store.finalized {
+		t.Errorf("Expected the silence of the deleted policy to be expired and the finalizer removed, got %v", am.deleted)
+	}
+
+	// A policy that no longer exists is done
+	store.policy = nil
+	if result, err := c.Reconcile(context.Background(), req); err != nil || result.RequeueAfter != 0 {
+		t.Errorf("Expected a deleted policy to be dropped, got %+v, %v", result, err)
+	}
+}
+
+func TestController_ReconcileFailure(t *testing.T) {
+	am, tickets := newFakeAlertManager(), newFakeTickets()
+	policy := testPolicy()
+	policy.Spec.Project = "UNKNOWN"
+	store := &fakeStore{policy: policy}
+	c := NewController(store, newTestReconciler(am, tickets), 5*time.Minute)
+
+	_, err := c.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "storage", Name: "disk-replacement"}})
+	if err == nil {
+		t.Fatal("Expected the failure to be returned for a retry with backoff")
+	}
+	if cond := policy.Status.FindCondition(ConditionReady); store.updated != 1 || cond == nil || cond.Status != ConditionFalse {
+		t.Errorf("Expected the failure on the status, got %+v", policy.Status.Conditions)
+	}
+}
+
+// budgetedTickets refuses ticket lookups once a run has used up its request budget, like
+// a Jira client with JIRA_REQUEST_BUDGET set
+type budgetedTickets struct {
+	*fakeTickets
+	budget   int
+	requests int
+}
+
+func (b *budgetedTickets) GetTicket(key string) (*ticket.Ticket, error) {
+	if b.requests >= b.budget {
+		return nil, ticket.ErrRequestBudgetExhausted
+	}
+	b.requests++
+	return b.fakeTickets.GetTicket(key)
+}
+
+func (b *budgetedTickets) ResetRequestBudget() {
+	b.requests = 0
+}
+
+func TestReconcile_ResetsRequestBudget(t *testing.T) {
+	am := newFakeAlertManager()
+	tickets := &budgetedTickets{fakeTickets: newFakeTickets(), budget: 1}
+	synchronizer := sync.NewSynchronizer(am, tickets, sync.SyncConfig{DefaultSilenceDuration: 24 * time.Hour})
+	r := NewReconciler(am, func(project string) (Backend, error) {
+		return Backend{Tickets: tickets, Synchronizer: synchronizer}, nil
+	})
+	policy := testPolicy()
+
+	// The backend is kept across reconciles, which each get the whole budget
+	for i := 0; i < 3; i++ {
+		status, err := r.Reconcile(policy)
+		if err != nil {
+			t.Fatalf("Reconcile %d failed: %v", i+1, err)
+		}
+		policy.Status = status
+	}
+	if c := policy.Status.FindCondition(ConditionReady); c == nil || c.Status != ConditionTrue {
+		t.Errorf("Expected Ready condition to be true, got %+v", c)
+	}
+}
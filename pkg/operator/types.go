@@ -0,0 +1,114 @@
+package operator
+
+import (
+	"time"
+)
+
+// Group, version and resource of the SilencePolicy custom resource
+const (
+	Group    = "silence-manager.conallob.github.io"
+	Version  = "v1alpha1"
+	Kind     = "SilencePolicy"
+	Resource = "silencepolicies"
+)
+
+// Finalizer keeps a deleted SilencePolicy until its silence has been expired
+const Finalizer = Group + "/silence"
+
+// Condition types reported on a SilencePolicy
+const (
+	// ConditionReady is true when the policy was reconciled without error
+	ConditionReady = "Ready"
+	// ConditionSilenced is true while the policy's silence is active
+	ConditionSilenced = "Silenced"
+)
+
+// Condition statuses, as in Kubernetes API conventions
+const (
+	ConditionTrue    = "True"
+	ConditionFalse   = "False"
+	ConditionUnknown = "Unknown"
+)
+
+// SilencePolicy describes a silence and its ticket declaratively. Each policy is
+// reconciled into one managed silence linked to a ticket.
+type SilencePolicy struct {
+	Name       string
+	Namespace  string
+	Generation int64
+	Deleting   bool // Set once the policy has a deletion timestamp
+	Finalizers []string
+	Spec       PolicySpec
+	Status     PolicyStatus
+}
+
+// PolicySpec is the desired state of a SilencePolicy
+type PolicySpec struct {
+	// Matchers are label matchers such as alertname="DiskFull" or instance=~"db-.*"
+	Matchers []string `json:"matchers"`
+	// Project is the Jira project of the created ticket; empty uses JIRA_PROJECT_KEY
+	Project string `json:"project,omitempty"`
+	// Ticket links an existing ticket instead of creating one
+	Ticket  string `json:"ticket,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// Duration of the silence, e.g. 4h or 3d; empty uses SYNC_DEFAULT_SILENCE_DURATION.
+	// The silence is extended by the regular synchronization while its ticket is open.
+	Duration string  `json:"duration,omitempty"`
+	Routing  Routing `json:"routing,omitempty"`
+}
+
+// Routing decides who the created ticket is routed to
+type Routing struct {
+	Assignee string   `json:"assignee,omitempty"`
+	Priority string   `json:"priority,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	Watchers []string `json:"watchers,omitempty"`
+}
+
+// PolicyStatus is the observed state of a SilencePolicy
+type PolicyStatus struct {
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	SilenceID          string      `json:"silenceID,omitempty"`
+	Ticket             string      `json:"ticket,omitempty"`
+	EndsAt             *time.Time  `json:"endsAt,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
+}
+
+// Condition is a status condition following Kubernetes API conventions
+type Condition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// FindCondition returns the condition of the given type, or nil
+func (s *PolicyStatus) FindCondition(conditionType string) *Condition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// setCondition adds or replaces a condition. The transition time is kept when the
+// status of the condition does not change.
+func (s *PolicyStatus) setCondition(condition Condition) {
+	existing := s.FindCondition(condition.Type)
+	if existing == nil {
+		s.Conditions = append(s.Conditions, condition)
+		return
+	}
+	if existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	*existing = condition
+}
+
+// String identifies the policy as namespace/name
+func (p *SilencePolicy) String() string {
+	return p.Namespace + "/" + p.Name
+}
@@ -0,0 +1,105 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// AlertManager wraps an Alertmanager client, passing reads through and recording writes
+// in the plan instead of applying them
+type AlertManager struct {
+	next    alertmanager.AlertManager
+	plan    *Plan
+	created map[string]*alertmanager.Silence
+}
+
+// WrapAlertManager returns an Alertmanager client that records writes in p
+func WrapAlertManager(next alertmanager.AlertManager, p *Plan) *AlertManager {
+	return &AlertManager{next: next, plan: p, created: make(map[string]*alertmanager.Silence)}
+}
+
+// GetSilence retrieves a silence by ID, including silences created by the plan
+func (a *AlertManager) GetSilence(id string) (*alertmanager.Silence, error) {
+	if silence, ok := a.created[id]; ok {
+		return silence, nil
+	}
+	return a.next.GetSilence(id)
+}
+
+// ListSilences returns all active silences
+func (a *AlertManager) ListSilences() ([]*alertmanager.Silence, error) {
+	return a.next.ListSilences()
+}
+
+// CreateSilence records the creation of a silence and returns a placeholder ID
+func (a *AlertManager) CreateSilence(silence *alertmanager.Silence) (string, error) {
+	id := fmt.Sprintf("(new silence %d)", len(a.created)+1)
+	created := *silence
+	created.ID = id
+	a.created[id] = &created
+
+	detail := fmt.Sprintf("%s until %s", formatMatchers(silence.Matchers), silence.EndsAt.Format(time.RFC3339))
+	if silence.TicketRef != "" {
+		detail += " linked to " + silence.TicketRef
+	}
+	a.plan.add(Change{Action: ActionCreate, Target: TargetSilence, ID: id, Operation: "create", Detail: detail})
+	return id, nil
+}
+
+// UpdateSilence records the fields of the silence that would change
+func (a *AlertManager) UpdateSilence(silence *alertmanager.Silence) error {
+	var diffs []string
+	if current, err := a.GetSilence(silence.ID); err == nil {
+		if !current.EndsAt.Equal(silence.EndsAt) {
+			diffs = append(diffs, fmt.Sprintf("endsAt %s -> %s", current.EndsAt.Format(time.RFC3339), silence.EndsAt.Format(time.RFC3339)))
+		}
+		if m, n := formatMatchers(current.Matchers), formatMatchers(silence.Matchers); m != n {
+			diffs = append(diffs, fmt.Sprintf("matchers %q -> %q", m, n))
+		}
+		if current.TicketRef != silence.TicketRef {
+			diffs = append(diffs, fmt.Sprintf("ticket %q -> %q", current.TicketRef, silence.TicketRef))
+		}
+		if current.Comment != silence.Comment {
+			diffs = append(diffs, "comment")
+		}
+	}
+	a.plan.add(Change{Action: ActionChange, Target: TargetSilence, ID: silence.ID, Operation: "update", Detail: strings.Join(diffs, ", ")})
+	return nil
+}
+
+// DeleteSilence records the deletion of a silence
+func (a *AlertManager) DeleteSilence(id string) error {
+	a.plan.add(Change{Action: ActionDelete, Target: TargetSilence, ID: id, Operation: "delete"})
+	return nil
+}
+
+// ExtendSilence records the new end time of a silence
+func (a *AlertManager) ExtendSilence(id string, newEndTime time.Time) error {
+	detail := "until " + newEndTime.Format(time.RFC3339)
+	if current, err := a.GetSilence(id); err == nil {
+		detail = fmt.Sprintf("endsAt %s -> %s", current.EndsAt.Format(time.RFC3339), newEndTime.Format(time.RFC3339))
+	}
+	a.plan.add(Change{Action: ActionChange, Target: TargetSilence, ID: id, Operation: "extend", Detail: detail})
+	return nil
+}
+
+// GetAlerts returns all active alerts matching the given matchers
+func (a *AlertManager) GetAlerts(matchers []alertmanager.Matcher) ([]*alertmanager.Alert, error) {
+	return a.next.GetAlerts(matchers)
+}
+
+// GetSilencedAlerts returns the alerts currently silenced by the given silence
+func (a *AlertManager) GetSilencedAlerts(silenceID string) ([]*alertmanager.Alert, error) {
+	return a.next.GetSilencedAlerts(silenceID)
+}
+
+func formatMatchers(matchers []alertmanager.Matcher) string {
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		parts = append(parts, m.String())
+	}
+	return strings.Join(parts, " ")
+}
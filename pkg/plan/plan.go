@@ -0,0 +1,112 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Change actions, rendered terraform-style as "+" (create), "-" (delete) and "~" (change)
+const (
+	ActionCreate = "create"
+	ActionDelete = "delete"
+	ActionChange = "change"
+)
+
+// Targets of a change
+const (
+	TargetSilence = "silence"
+	TargetTicket  = "ticket"
+)
+
+// Change is a single write a synchronization run intends to make
+type Change struct {
+	Action string `json:"action"`
+	Target string `json:"target"`
+	ID     string `json:"id"`
+	// Operation names the write, e.g. "extend", "reopen" or "comment"
+	Operation string `json:"operation"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// String renders the change as a line of the plan
+func (c Change) String() string {
+	symbol := "~"
+	switch c.Action {
+	case ActionCreate:
+		symbol = "+"
+	case ActionDelete:
+		symbol = "-"
+	}
+	line := fmt.Sprintf("%s %s %s: %s", symbol, c.Target, c.ID, c.Operation)
+	if c.Detail != "" {
+		line += " " + c.Detail
+	}
+	return line
+}
+
+// Plan is the ordered set of changes recorded by the wrapped clients
+type Plan struct {
+	mu      sync.Mutex
+	Changes []Change `json:"changes"`
+}
+
+// New creates an empty plan
+func New() *Plan {
+	return &Plan{Changes: make([]Change, 0)}
+}
+
+func (p *Plan) add(c Change) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Changes = append(p.Changes, c)
+}
+
+// Counts returns the number of changes per action
+func (p *Plan) Counts() (create, change, remove int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			create++
+		case ActionDelete:
+			remove++
+		default:
+			change++
+		}
+	}
+	return create, change, remove
+}
+
+// WriteText renders the plan as a diff, one change per line, followed by a summary
+func (p *Plan) WriteText(w io.Writer) error {
+	var b strings.Builder
+	p.mu.Lock()
+	if len(p.Changes) == 0 {
+		p.mu.Unlock()
+		_, err := io.WriteString(w, "No changes. Silences and tickets are in sync.\n")
+		return err
+	}
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteString("\n")
+	}
+	p.mu.Unlock()
+
+	create, change, remove := p.Counts()
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to change, %d to delete.\n", create, change, remove)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// summarize returns the first line of a comment, shortened for the plan
+func summarize(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	const maxLen = 100
+	if len(line) > maxLen {
+		line = line[:maxLen] + "..."
+	}
+	return fmt.Sprintf("%q", line)
+}
@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// stubAlertManager serves a single silence and panics on anything but reads
+type stubAlertManager struct {
+	alertmanager.AlertManager
+	silence *alertmanager.Silence
+}
+
+func (s *stubAlertManager) GetSilence(id string) (*alertmanager.Silence, error) {
+	return s.silence, nil
+}
+
+// stubTicketSystem panics on every call, so any pass-through write fails the test
+type stubTicketSystem struct {
+	ticket.TicketSystem
+}
+
+func TestPlan_RecordsWrites(t *testing.T) {
+	endsAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	p := New()
+	am := WrapAlertManager(&stubAlertManager{silence: &alertmanager.Silence{ID: "abc", EndsAt: endsAt}}, p)
+	ts := WrapTicketSystem(&stubTicketSystem{}, p)
+
+	if err := am.ExtendSilence("abc", endsAt.Add(7*24*time.Hour)); err != nil {
+		t.Fatalf("ExtendSilence() failed: %v", err)
+	}
+	if err := am.DeleteSilence("def"); err != nil {
+		t.Fatalf("DeleteSilence() failed: %v", err)
+	}
+	key, err := ts.CreateTicket(&ticket.Ticket{Summary: "Alert refired"})
+	if err != nil {
+		t.Fatalf("CreateTicket() failed: %v", err)
+	}
+	id, err := am.CreateSilence(&alertmanager.Silence{
+		EndsAt:    endsAt,
+		TicketRef: key,
+		Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+	})
+	if err != nil {
+		t.Fatalf("CreateSilence() failed: %v", err)
+	}
+	if err := ts.ReopenTicket("PROJ-5", "Alert has refired\n\nDetails follow"); err != nil {
+		t.Fatalf("ReopenTicket() failed: %v", err)
+	}
+
+	// Created objects can be read back by later steps of the run
+	if tkt, err := ts.GetTicket(key); err != nil || tkt.Status != ticket.StatusOpen {
+		t.Errorf("Expected created ticket %s to be readable and open, got %+v, %v", key, tkt, err)
+	}
+	if silence, err := am.GetSilence(id); err != nil || silence.TicketRef != key {
+		t.Errorf("Expected created silence %s to be readable, got %+v, %v", id, silence, err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() failed: %v", err)
+	}
+	expected := strings.Join([]string{
+		"~ silence abc: extend endsAt 2025-06-01T09:00:00Z -> 2025-06-08T09:00:00Z",
+		"- silence def: delete",
+		`+ ticket (new ticket 1): create "Alert refired"`,
+		"+ silence (new silence 1): create alertname=DiskFull until 2025-06-01T09:00:00Z linked to (new ticket 1)",
+		`~ ticket PROJ-5: reopen "Alert has refired"`,
+		"",
+		"Plan: 2 to create, 2 to change, 1 to delete.",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Errorf("Unexpected plan:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestPlan_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New().WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "No changes.") {
+		t.Errorf("Expected no changes, got %q", buf.String())
+	}
+}
@@ -0,0 +1,184 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// TicketSystem wraps a ticket system client, passing reads through and recording writes
+// in the plan instead of applying them
+type TicketSystem struct {
+	next    ticket.TicketSystem
+	plan    *Plan
+	created map[string]*ticket.Ticket
+}
+
+// WrapTicketSystem returns a ticket system client that records writes in p
+func WrapTicketSystem(next ticket.TicketSystem, p *Plan) *TicketSystem {
+	return &TicketSystem{next: next, plan: p, created: make(map[string]*ticket.Ticket)}
+}
+
+func (t *TicketSystem) record(action, key, operation, detail string) {
+	t.plan.add(Change{Action: action, Target: TargetTicket, ID: key, Operation: operation, Detail: detail})
+}
+
+// GetTicket retrieves a ticket by its key, including tickets created by the plan
+func (t *TicketSystem) GetTicket(key string) (*ticket.Ticket, error) {
+	if tkt, ok := t.created[key]; ok {
+		return tkt, nil
+	}
+	return t.next.GetTicket(key)
+}
+
+// GetTickets retrieves several tickets at once, including tickets created by the plan
+func (t *TicketSystem) GetTickets(keys []string) (map[string]*ticket.Ticket, error) {
+	var existing []string
+	for _, key := range keys {
+		if _, ok := t.created[key]; !ok {
+			existing = append(existing, key)
+		}
+	}
+	tickets, err := t.next.GetTickets(existing)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if tkt, ok := t.created[key]; ok {
+			tickets[key] = tkt
+		}
+	}
+	return tickets, nil
+}
+
+// SearchTickets returns all tickets matching a query
+func (t *TicketSystem) SearchTickets(query string) ([]*ticket.Ticket, error) {
+	return t.next.SearchTickets(query)
+}
+
+// CreateTicket records the creation of a ticket and returns a placeholder key
+func (t *TicketSystem) CreateTicket(tkt *ticket.Ticket) (string, error) {
+	key := fmt.Sprintf("(new ticket %d)", len(t.created)+1)
+	created := *tkt
+	created.Key = key
+	created.Status = ticket.StatusOpen
+	t.created[key] = &created
+	t.record(ActionCreate, key, "create", fmt.Sprintf("%q", tkt.Summary))
+	return key, nil
+}
+
+// UpdateTicket records an update of the ticket fields
+func (t *TicketSystem) UpdateTicket(tkt *ticket.Ticket) error {
+	detail := ""
+	if current, err := t.GetTicket(tkt.Key); err == nil && current.SilenceRef != tkt.SilenceRef {
+		detail = fmt.Sprintf("silence %q -> %q", current.SilenceRef, tkt.SilenceRef)
+	}
+	t.record(ActionChange, tkt.Key, "update", detail)
+	return nil
+}
+
+// UpdateLabels records the labels added to and removed from a ticket
+func (t *TicketSystem) UpdateLabels(key string, add, remove []string) error {
+	var parts []string
+	for _, l := range add {
+		parts = append(parts, "+"+l)
+	}
+	for _, l := range remove {
+		parts = append(parts, "-"+l)
+	}
+	t.record(ActionChange, key, "labels", strings.Join(parts, " "))
+	return nil
+}
+
+// SetPriority records the new priority of a ticket
+func (t *TicketSystem) SetPriority(key string, priority string) error {
+	t.record(ActionChange, key, "priority", priority)
+	return nil
+}
+
+// SetDueDate records the new due date of a ticket
+func (t *TicketSystem) SetDueDate(key string, due time.Time) error {
+	t.record(ActionChange, key, "due date", due.Format("2006-01-02"))
+	return nil
+}
+
+// AssignTicket records the new assignee of a ticket
+func (t *TicketSystem) AssignTicket(key string, user string) error {
+	t.record(ActionChange, key, "assign", user)
+	return nil
+}
+
+// AddWatcher records a watcher added to a ticket
+func (t *TicketSystem) AddWatcher(key string, user string) error {
+	t.record(ActionChange, key, "watch", user)
+	return nil
+}
+
+// SetRemoteLink records a remote link added to a ticket
+func (t *TicketSystem) SetRemoteLink(key string, link ticket.RemoteLink) error {
+	t.record(ActionChange, key, "link", link.URL)
+	return nil
+}
+
+// DeleteRemoteLink records a remote link removed from a ticket
+func (t *TicketSystem) DeleteRemoteLink(key string, globalID string) error {
+	t.record(ActionChange, key, "unlink", globalID)
+	return nil
+}
+
+// LinkTickets records an issue link between two tickets
+func (t *TicketSystem) LinkTickets(key, linkedKey, linkType string) error {
+	t.record(ActionChange, key, "issue link", fmt.Sprintf("%s %s", linkType, linkedKey))
+	return nil
+}
+
+// ReopenTicket records the reopening of a ticket
+func (t *TicketSystem) ReopenTicket(key string, comment string) error {
+	t.record(ActionChange, key, "reopen", summarize(comment))
+	return nil
+}
+
+// CloseTicket records the closing of a ticket
+func (t *TicketSystem) CloseTicket(key string, comment string) error {
+	t.record(ActionChange, key, "close", summarize(comment))
+	return nil
+}
+
+// AddComment records a comment added to a ticket
+func (t *TicketSystem) AddComment(key string, comment string) error {
+	t.record(ActionChange, key, "comment", summarize(comment))
+	return nil
+}
+
+// GetComments retrieves all comments of a ticket
+func (t *TicketSystem) GetComments(key string) ([]*ticket.Comment, error) {
+	if _, ok := t.created[key]; ok {
+		return nil, nil
+	}
+	return t.next.GetComments(key)
+}
+
+// CurrentUser returns the user the wrapped client comments as, if it reports one
+func (t *TicketSystem) CurrentUser() (string, error) {
+	if cu, ok := t.next.(interface{ CurrentUser() (string, error) }); ok {
+		return cu.CurrentUser()
+	}
+	return "", nil
+}
+
+// IsResolved checks if a ticket is in a resolved state
+func (t *TicketSystem) IsResolved(tkt *ticket.Ticket) bool {
+	return t.next.IsResolved(tkt)
+}
+
+// IsClosed checks if a ticket is in a closed state
+func (t *TicketSystem) IsClosed(tkt *ticket.Ticket) bool {
+	return t.next.IsClosed(tkt)
+}
+
+// IsOpen checks if a ticket is in an open state
+func (t *TicketSystem) IsOpen(tkt *ticket.Ticket) bool {
+	return t.next.IsOpen(tkt)
+}
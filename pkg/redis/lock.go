@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// LockConfig holds configuration for the Redis run lock
+type LockConfig struct {
+	Key      string        // Key holding the lock
+	Identity string        // Identity of this instance, usually the pod or host name
+	Token    string        // Unique to this process, so that instances sharing a name do not share the lock
+	Mode     string        // How this instance runs, e.g. "cronjob" or "daemon"
+	TTL      time.Duration // How long the lock is held without renewal
+}
+
+// Holder describes the instance holding the lock
+type Holder struct {
+	Identity string `json:"identity"`
+	Mode     string `json:"mode"`
+	Token    string `json:"token,omitempty"`
+}
+
+// renewScript extends the lock if this process holds it, replying 1, and otherwise
+// replies the value of the lock, or nil if it has just expired. Ownership is decided by
+// the token rather than the identity, which two processes on one host share.
+const renewScript = `local v = redis.call('GET', KEYS[1])
+if v and cjson.decode(v).token == ARGV[1] then
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+  return 1
+end
+return v`
+
+// releaseScript deletes the lock if this process holds it
+const releaseScript = `local v = redis.call('GET', KEYS[1])
+if v and cjson.decode(v).token == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+// Lock ensures only one silence-manager instance synchronizes at a time, including
+// instances outside the cluster such as a manual run from a workstation. The lock is a
+// key that expires after the TTL, so a crashed holder cannot block runs for longer.
+type Lock struct {
+	client *Client
+	cfg    LockConfig
+}
+
+// NewLock creates a run lock kept under cfg.Key. Without a token, one is generated.
+func NewLock(client *Client, cfg LockConfig) *Lock {
+	if cfg.Token == "" {
+		cfg.Token = NewLockToken(cfg.Identity)
+	}
+	return &Lock{client: client, cfg: cfg}
+}
+
+// Acquire takes or renews the lock. If another instance holds it, it returns false
+// together with the current holder.
+func (l *Lock) Acquire(ctx context.Context) (bool, *Holder, error) {
+	if err := ctx.Err(); err != nil {
+		return false, nil, err
+	}
+	value, err := json.Marshal(Holder{Identity: l.cfg.Identity, Mode: l.cfg.Mode, Token: l.cfg.Token})
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to marshal lock holder: %w", err)
+	}
+	ttl := strconv.FormatInt(l.cfg.TTL.Milliseconds(), 10)
+
+	reply, err := l.client.Do("SET", l.cfg.Key, string(value), "NX", "PX", ttl)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to set lock: %w", err)
+	}
+	if reply != nil {
+		log.Printf("Acquired run lock %s as %s (%s)", l.cfg.Key, l.cfg.Identity, l.cfg.Mode)
+		return true, nil, nil
+	}
+
+	reply, err = l.client.Do("EVAL", renewScript, "1", l.cfg.Key, l.cfg.Token, string(value), ttl)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to renew lock: %w", err)
+	}
+	switch reply := reply.(type) {
+	case int64:
+		return true, nil, nil
+	case []byte:
+		holder := &Holder{}
+		if err := json.Unmarshal(reply, holder); err != nil {
+			return false, nil, fmt.Errorf("failed to decode lock holder: %w", err)
+		}
+		return false, holder, nil
+	default:
+		// The lock expired between the two commands; the next attempt takes it
+		return false, &Holder{}, nil
+	}
+}
+
+// NewLockToken returns a token unique to this process, made of the identity, the process ID
+// and a random suffix
+func NewLockToken(identity string) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", identity, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Release gives up the lock if this instance holds it
+func (l *Lock) Release(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	reply, err := l.client.Do("EVAL", releaseScript, "1", l.cfg.Key, l.cfg.Token)
+	if err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	if deleted, _ := reply.(int64); deleted > 0 {
+		log.Printf("Released run lock %s", l.cfg.Key)
+	}
+	return nil
+}
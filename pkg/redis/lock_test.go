@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	server := newFakeServer(t, "")
+	client := NewClient(Config{Address: server.ln.Addr().String()})
+	ctx := context.Background()
+	cronjob := NewLock(client, LockConfig{Key: "silence-manager:lock", Identity: "cronjob-abc", Mode: "cronjob", TTL: 10 * time.Minute})
+	manual := NewLock(client, LockConfig{Key: "silence-manager:lock", Identity: "laptop", Mode: "cronjob", TTL: 10 * time.Minute})
+
+	if acquired, _, err := cronjob.Acquire(ctx); err != nil || !acquired {
+		t.Fatalf("Expected to acquire the free lock, got %v, %v", acquired, err)
+	}
+	if acquired, _, err := cronjob.Acquire(ctx); err != nil || !acquired {
+		t.Fatalf("Expected the holder to renew the lock, got %v, %v", acquired, err)
+	}
+
+	acquired, holder, err := manual.Acquire(ctx)
+	if err != nil || acquired {
+		t.Fatalf("Expected the held lock to be refused, got %v, %v", acquired, err)
+	}
+	if holder.Identity != "cronjob-abc" || holder.Mode != "cronjob" {
+		t.Errorf("Unexpected holder %+v", holder)
+	}
+
+	// Only the holder can release the lock
+	if err := manual.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if acquired, _, _ := manual.Acquire(ctx); acquired {
+		t.Fatal("Expected the lock to stay held after a release by another instance")
+	}
+	if err := cronjob.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if acquired, _, err := manual.Acquire(ctx); err != nil || !acquired {
+		t.Errorf("Expected to acquire the released lock, got %v, %v", acquired, err)
+	}
+}
+
+func TestLock_TakenOver(t *testing.T) {
+	server := newFakeServer(t, "")
+	lock := NewLock(NewClient(Config{Address: server.ln.Addr().String()}), LockConfig{Key: "lock", Identity: "a", TTL: time.Second})
+
+	if _, _, err := lock.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	// The lock expired during a long run and another instance took it
+	server.mu.Lock()
+	server.data["lock"] = `{"identity":"b","mode":"daemon"}`
+	server.mu.Unlock()
+	acquired, holder, err := lock.Acquire(context.Background())
+	if err != nil || acquired || holder.Identity != "b" {
+		t.Errorf("Expected the lock taken by b to be refused, got %v, %+v, %v", acquired, holder, err)
+	}
+}
+
+func TestLock_SameIdentity(t *testing.T) {
+	server := newFakeServer(t, "")
+	client := NewClient(Config{Address: server.ln.Addr().String()})
+	ctx := context.Background()
+	// Two processes on one host, e.g. a manual run inside the daemon's pod
+	daemon := NewLock(client, LockConfig{Key: "lock", Identity: "pod-a", Mode: "daemon", TTL: time.Minute})
+	manual := NewLock(client, LockConfig{Key: "lock", Identity: "pod-a", Mode: "cronjob", TTL: time.Minute})
+
+	if acquired, _, err := daemon.Acquire(ctx); err != nil || !acquired {
+		t.Fatalf("Expected to acquire the free lock, got %v, %v", acquired, err)
+	}
+	acquired, holder, err := manual.Acquire(ctx)
+	if err != nil || acquired {
+		t.Fatalf("Expected the lock held by another process to be refused, got %v, %v", acquired, err)
+	}
+	if holder.Identity != "pod-a" || holder.Mode != "daemon" {
+		t.Errorf("Unexpected holder %+v", holder)
+	}
+	if err := manual.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if acquired, _, _ := manual.Acquire(ctx); acquired {
+		t.Error("Expected the lock to stay held after a release by another process")
+	}
+}
@@ -0,0 +1,206 @@
+// Package redis is a minimal client of the Redis protocol (RESP), covering the few
+// commands silence-manager needs to keep its state in Redis.
+package redis
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// defaultTimeout bounds connecting to Redis and each command
+const defaultTimeout = 10 * time.Second
+
+// Config holds the connection settings of a Redis server
+type Config struct {
+	Address  string // host:port
+	Username string // ACL user; empty authenticates with the password only
+	Password string // Empty skips authentication
+	DB       int
+	// TLSConfig, when set, connects with TLS
+	TLSConfig *tls.Config
+}
+
+// Error is an error reply of the server
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client runs commands on a Redis server, on a new connection for each command, which
+// suits the handful of commands of a run
+type Client struct {
+	config  Config
+	timeout time.Duration
+}
+
+// NewClient creates a client of the server at config.Address
+func NewClient(config Config) *Client {
+	return &Client{config: config, timeout: defaultTimeout}
+}
+
+// Get returns the value of key, or nil if the key does not exist
+func (c *Client) Get(key string) ([]byte, error) {
+	reply, err := c.Do("GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply to GET: %v", reply)
+	}
+	return value, nil
+}
+
+// Set sets key to value
+func (c *Client) Set(key string, value []byte) error {
+	_, err := c.Do("SET", key, string(value))
+	return err
+}
+
+// Do runs a command and returns its reply: a string for status replies, an int64, a
+// []byte for bulk strings, nil for null replies, or a []any for arrays. Error replies
+// are returned as Error.
+func (c *Client) Do(args ...string) (any, error) {
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	r := bufio.NewReader(conn)
+	if c.config.Password != "" {
+		auth := []string{"AUTH", c.config.Password}
+		if c.config.Username != "" {
+			auth = []string{"AUTH", c.config.Username, c.config.Password}
+		}
+		if _, err := roundTrip(conn, r, auth); err != nil {
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	if c.config.DB != 0 {
+		if _, err := roundTrip(conn, r, []string{"SELECT", strconv.Itoa(c.config.DB)}); err != nil {
+			return nil, fmt.Errorf("failed to select database %d: %w", c.config.DB, err)
+		}
+	}
+	return roundTrip(conn, r, args)
+}
+
+// dial connects to the server
+func (c *Client) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	var conn net.Conn
+	var err error
+	if c.config.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.config.Address, c.config.TLSConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", c.config.Address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", c.config.Address, err)
+	}
+	return conn, nil
+}
+
+// roundTrip sends a command and reads its reply
+func roundTrip(w io.Writer, r *bufio.Reader, args []string) (any, error) {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := w.Write(buf); err != nil {
+		return nil, fmt.Errorf("failed to send command: %w", err)
+	}
+	reply, err := readReply(r)
+	if err != nil {
+		var replyErr Error
+		if !errors.As(err, &replyErr) {
+			err = fmt.Errorf("failed to read reply: %w", err)
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+// readReply reads a RESP reply
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, Error(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unknown reply type %q", kind)
+}
+
+// StateKey keeps the silence-manager state document under a key, for exporting the
+// state between runs
+type StateKey struct {
+	client *Client
+	key    string
+}
+
+// NewStateKey creates a state copy kept under key
+func NewStateKey(client *Client, key string) *StateKey {
+	return &StateKey{client: client, key: key}
+}
+
+// Get returns the state document, or nil if the key does not exist
+func (k *StateKey) Get() ([]byte, error) {
+	return k.client.Get(k.key)
+}
+
+// Put replaces the state document
+func (k *StateKey) Put(data []byte) error {
+	return k.client.Set(k.key, data)
+}
@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeServer is a Redis server keeping string keys in memory, requiring a password. It
+// runs the lock scripts natively and ignores expiry; tests expire keys by deleting them.
+type fakeServer struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{ln: ln, password: password, data: make(map[string]string)}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range reply.([]any) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args[0])
+		var out string
+		switch {
+		case args[0] == "AUTH":
+			authenticated = args[len(args)-1] == s.password
+			out = "+OK\r\n"
+			if !authenticated {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			out = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "SELECT":
+			out = "+OK\r\n"
+		case args[0] == "GET":
+			value, ok := s.data[args[1]]
+			out = "$-1\r\n"
+			if ok {
+				out = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			}
+		case args[0] == "SET":
+			out = "+OK\r\n"
+			if _, exists := s.data[args[1]]; exists && len(args) > 3 && args[3] == "NX" {
+				out = "$-1\r\n"
+			} else {
+				s.data[args[1]] = args[2]
+			}
+		case args[0] == "EVAL":
+			out = s.eval(args[1], args[3], args[4:])
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+		conn.Write([]byte(out))
+	}
+}
+
+// eval runs the lock scripts for key
+func (s *fakeServer) eval(script, key string, argv []string) string {
+	value, exists := s.data[key]
+	var holder Holder
+	json.Unmarshal([]byte(value), &holder)
+	held := exists && holder.Token == argv[0]
+	switch script {
+	case renewScript:
+		if held {
+			s.data[key] = argv[1]
+			return ":1\r\n"
+		}
+		if !exists {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case releaseScript:
+		if held {
+			delete(s.data, key)
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	}
+	return "-ERR unknown script\r\n"
+}
+
+func TestClient_GetSet(t *testing.T) {
+	server := newFakeServer(t, "secret")
+	client := NewClient(Config{Address: server.ln.Addr().String(), Password: "secret", DB: 2})
+
+	value, err := client.Get("missing")
+	if err != nil || value != nil {
+		t.Fatalf("Expected nil for a missing key, got %q, %v", value, err)
+	}
+	if err := client.Set("state", []byte("{\"hygiene\":[]}\r\n")); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	value, err = client.Get("state")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if string(value) != "{\"hygiene\":[]}\r\n" {
+		t.Errorf("Unexpected value %q", value)
+	}
+	if got := strings.Join(server.commands[:3], ","); got != "AUTH,SELECT,GET" {
+		t.Errorf("Expected authentication and database selection first, got %s", got)
+	}
+}
+
+func TestClient_ErrorReply(t *testing.T) {
+	server := newFakeServer(t, "secret")
+
+	_, err := NewClient(Config{Address: server.ln.Addr().String(), Password: "wrong"}).Get("state")
+	var replyErr Error
+	if !errors.As(err, &replyErr) || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Expected the error reply of the server, got %v", err)
+	}
+}
+
+func TestStateKey(t *testing.T) {
+	server := newFakeServer(t, "")
+	key := NewStateKey(NewClient(Config{Address: server.ln.Addr().String()}), "silence-manager:state")
+
+	if data, err := key.Get(); err != nil || data != nil {
+		t.Fatalf("Expected no state yet, got %q, %v", data, err)
+	}
+	if err := key.Put([]byte(`{"idle":{}}`)); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	if data, _ := key.Get(); string(data) != `{"idle":{}}` {
+		t.Errorf("Unexpected state %q", data)
+	}
+}
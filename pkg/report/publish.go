@@ -0,0 +1,227 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// defaultTimeout bounds a single publishing request
+const defaultTimeout = 30 * time.Second
+
+// Publisher publishes a report where management can read it
+type Publisher interface {
+	Publish(ctx context.Context, r *Report) error
+	// Target names where the report is published, for logging
+	Target() string
+}
+
+// Commenter adds comments to tickets, as the ticket system does
+type Commenter interface {
+	AddComment(key string, comment string) error
+}
+
+// JiraComment publishes reports as comments on a Jira issue, such as an issue shown on a
+// dashboard. The Markdown report is converted to tables and headings by the ticket system.
+type JiraComment struct {
+	commenter Commenter
+	issue     string
+}
+
+// NewJiraComment creates a publisher commenting on issue
+func NewJiraComment(commenter Commenter, issue string) *JiraComment {
+	return &JiraComment{commenter: commenter, issue: issue}
+}
+
+// Publish adds the report as a comment
+func (j *JiraComment) Publish(ctx context.Context, r *Report) error {
+	var b strings.Builder
+	if err := r.WriteMarkdown(&b); err != nil {
+		return err
+	}
+	if err := j.commenter.AddComment(j.issue, b.String()); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", j.issue, err)
+	}
+	return nil
+}
+
+// Target returns the issue key
+func (j *JiraComment) Target() string {
+	return "Jira issue " + j.issue
+}
+
+// ConfluenceConfig holds configuration for publishing to a Confluence page
+type ConfluenceConfig struct {
+	URL      string // Base URL including the context path, e.g. https://example.atlassian.net/wiki
+	SpaceKey string
+	Title    string // Title of the page, created on the first run and updated afterwards
+	ParentID string // ID of the parent of a created page; the space home if empty
+	Username string
+	APIToken string
+	// Credentials, when set, supplies the username and API token for each request instead
+	Credentials ticket.CredentialSource
+	// TLSConfig, when set, is used for HTTPS connections instead of the defaults
+	TLSConfig *tls.Config
+}
+
+// Confluence publishes reports to a Confluence page, replacing its content on every run.
+// Earlier reports stay available in the page history.
+type Confluence struct {
+	cfg    ConfluenceConfig
+	client *http.Client
+}
+
+// NewConfluence creates a publisher for the page titled cfg.Title in cfg.SpaceKey
+func NewConfluence(cfg ConfluenceConfig) *Confluence {
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSConfig != nil {
+		transport.TLSClientConfig = cfg.TLSConfig
+	}
+	return &Confluence{cfg: cfg, client: &http.Client{Transport: transport, Timeout: defaultTimeout}}
+}
+
+// confluencePage is the part of a Confluence content object used for publishing
+type confluencePage struct {
+	ID        string               `json:"id,omitempty"`
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Space     *confluenceSpace     `json:"space,omitempty"`
+	Ancestors []confluenceAncestor `json:"ancestors,omitempty"`
+	Version   *confluenceVersion   `json:"version,omitempty"`
+	Body      *confluenceBody      `json:"body,omitempty"`
+}
+
+type confluenceSpace struct {
+	Key string `json:"key"`
+}
+
+type confluenceAncestor struct {
+	ID string `json:"id"`
+}
+
+type confluenceVersion struct {
+	Number  int    `json:"number"`
+	Message string `json:"message,omitempty"`
+}
+
+type confluenceBody struct {
+	Storage struct {
+		Value          string `json:"value"`
+		Representation string `json:"representation"`
+	} `json:"storage"`
+}
+
+// Publish writes the HTML report to the page, creating the page if it does not exist
+func (c *Confluence) Publish(ctx context.Context, r *Report) error {
+	var html strings.Builder
+	if err := r.WriteHTML(&html); err != nil {
+		return err
+	}
+	body := &confluenceBody{}
+	body.Storage.Value = html.String()
+	body.Storage.Representation = "storage"
+
+	existing, err := c.findPage(ctx)
+	if err != nil {
+		return err
+	}
+
+	page := confluencePage{Type: "page", Title: c.cfg.Title, Body: body}
+	if existing == nil {
+		page.Space = &confluenceSpace{Key: c.cfg.SpaceKey}
+		if c.cfg.ParentID != "" {
+			page.Ancestors = []confluenceAncestor{{ID: c.cfg.ParentID}}
+		}
+		return c.send(ctx, http.MethodPost, "/rest/api/content", page, nil)
+	}
+
+	page.ID = existing.ID
+	page.Version = &confluenceVersion{
+		Number:  existing.Version.Number + 1,
+		Message: fmt.Sprintf("Report of %s to %s", r.Since.Format("2006-01-02"), r.Until.Format("2006-01-02")),
+	}
+	return c.send(ctx, http.MethodPut, "/rest/api/content/"+url.PathEscape(existing.ID), page, nil)
+}
+
+// findPage looks up the page by space and title, returning nil if it does not exist
+func (c *Confluence) findPage(ctx context.Context) (*confluencePage, error) {
+	query := url.Values{}
+	query.Set("spaceKey", c.cfg.SpaceKey)
+	query.Set("title", c.cfg.Title)
+	query.Set("type", "page")
+	query.Set("expand", "version")
+
+	var found struct {
+		Results []confluencePage `json:"results"`
+	}
+	if err := c.send(ctx, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &found); err != nil {
+		return nil, err
+	}
+	if len(found.Results) == 0 {
+		return nil, nil
+	}
+	page := found.Results[0]
+	if page.Version == nil {
+		return nil, fmt.Errorf("page %s has no version", page.ID)
+	}
+	return &page, nil
+}
+
+// send makes a request to the Confluence REST API, decoding the response into out if set
+func (c *Confluence) send(ctx context.Context, method, path string, in, out any) error {
+	var reqBody io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.cfg.URL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	username, apiToken := c.cfg.Username, c.cfg.APIToken
+	if c.cfg.Credentials != nil {
+		username, apiToken = c.cfg.Credentials.Credentials()
+	}
+	req.SetBasicAuth(username, apiToken)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Confluence: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("unexpected status code %d from Confluence: %s", resp.StatusCode, string(responseBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Target returns the space and title of the page
+func (c *Confluence) Target() string {
+	return fmt.Sprintf("Confluence page %q in space %s", c.cfg.Title, c.cfg.SpaceKey)
+}
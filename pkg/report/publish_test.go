@@ -0,0 +1,115 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type recordingCommenter struct {
+	comments map[string][]string
+}
+
+func (c *recordingCommenter) AddComment(key string, comment string) error {
+	c.comments[key] = append(c.comments[key], comment)
+	return nil
+}
+
+func TestJiraCommentPublish(t *testing.T) {
+	commenter := &recordingCommenter{comments: make(map[string][]string)}
+	if err := NewJiraComment(commenter, "OPS-1").Publish(context.Background(), newTestReport()); err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if len(commenter.comments["OPS-1"]) != 1 || !strings.Contains(commenter.comments["OPS-1"][0], "| Silences extended | 5 |") {
+		t.Errorf("Expected the Markdown report as a comment, got %v", commenter.comments)
+	}
+}
+
+// fakeConfluence serves the content endpoints for one page
+type fakeConfluence struct {
+	pageID  string
+	version int
+	pages   []confluencePage // Pages created or updated
+}
+
+func (f *fakeConfluence) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, token, ok := r.BasicAuth(); !ok || user != "bot@example.com" || token != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/wiki/rest/api/content":
+		var results []confluencePage
+		if f.pageID != "" && r.URL.Query().Get("title") == "Silence report" && r.URL.Query().Get("spaceKey") == "OPS" {
+			results = append(results, confluencePage{ID: f.pageID, Type: "page", Version: &confluenceVersion{Number: f.version}})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	case r.Method == http.MethodPost && r.URL.Path == "/wiki/rest/api/content",
+		r.Method == http.MethodPut && r.URL.Path == "/wiki/rest/api/content/"+f.pageID:
+		var page confluencePage
+		if err := json.NewDecoder(r.Body).Decode(&page); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.pages = append(f.pages, page)
+		json.NewEncoder(w).Encode(page)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestConfluencePublish(t *testing.T) {
+	fake := &fakeConfluence{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c := NewConfluence(ConfluenceConfig{
+		URL:      server.URL + "/wiki/",
+		Username: "bot@example.com",
+		APIToken: "secret",
+		SpaceKey: "OPS",
+		Title:    "Silence report",
+		ParentID: "42",
+	})
+
+	// The first run creates the page below the parent
+	if err := c.Publish(context.Background(), newTestReport()); err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if len(fake.pages) != 1 {
+		t.Fatalf("Expected a page to be created, got %d requests", len(fake.pages))
+	}
+	created := fake.pages[0]
+	if created.Space == nil || created.Space.Key != "OPS" || len(created.Ancestors) != 1 || created.Ancestors[0].ID != "42" {
+		t.Errorf("Expected the page in space OPS below page 42, got %+v", created)
+	}
+	if created.Body == nil || created.Body.Storage.Representation != "storage" || !strings.Contains(created.Body.Storage.Value, "<h1>Silence hygiene report</h1>") {
+		t.Errorf("Expected the HTML report as the page body, got %+v", created.Body)
+	}
+
+	// Later runs update the existing page with the next version
+	fake.pageID, fake.version = "1234", 7
+	if err := c.Publish(context.Background(), newTestReport()); err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if len(fake.pages) != 2 {
+		t.Fatalf("Expected the page to be updated, got %d requests", len(fake.pages))
+	}
+	if updated := fake.pages[1]; updated.ID != "1234" || updated.Version == nil || updated.Version.Number != 8 {
+		t.Errorf("Expected version 8 of page 1234, got %+v", updated)
+	}
+}
+
+func TestConfluencePublishError(t *testing.T) {
+	server := httptest.NewServer(&fakeConfluence{})
+	defer server.Close()
+
+	c := NewConfluence(ConfluenceConfig{URL: server.URL + "/wiki", Username: "bot@example.com", APIToken: "wrong", SpaceKey: "OPS", Title: "Silence report"})
+	err := c.Publish(context.Background(), newTestReport())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected an authentication error, got %v", err)
+	}
+}
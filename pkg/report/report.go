@@ -0,0 +1,219 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// Silence describes an active silence listed in a report
+type Silence struct {
+	ID        string        `json:"id"`
+	TicketRef string        `json:"ticketRef,omitempty"`
+	CreatedBy string        `json:"createdBy"`
+	Matchers  string        `json:"matchers"`
+	StartsAt  time.Time     `json:"startsAt"`
+	EndsAt    time.Time     `json:"endsAt"`
+	Age       time.Duration `json:"age"`
+}
+
+// Report summarizes silence-manager activity over a window and the current silences
+// that need attention
+type Report struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+	Runs  int       `json:"runs"`
+
+	SilencesExtended int `json:"silencesExtended"`
+	SilencesDeleted  int `json:"silencesDeleted"`
+	TicketsReopened  int `json:"ticketsReopened"`
+
+	ActiveSilences int `json:"activeSilences"`
+	// LongLived lists active silences older than LongLivedAge, oldest first
+	LongLivedAge time.Duration `json:"longLivedAge"`
+	LongLived    []Silence     `json:"longLived"`
+	// Unticketed lists active silences without a ticket reference, oldest first
+	Unticketed []Silence `json:"unticketed"`
+	// Idle lists active silences that match no alerts, oldest first. It is nil unless
+	// the silenced alerts were checked with AddIdle.
+	Idle []Silence `json:"idle,omitempty"`
+	// Acknowledged lists active karma acknowledgements, oldest first, with the ticket
+	// they are linked to, if any. It is nil unless added with AddAcknowledged.
+	Acknowledged []Silence `json:"acknowledged,omitempty"`
+}
+
+// Build computes a report from the hygiene samples taken in the window and the currently
+// active silences
+func Build(samples []state.HygieneSample, silences []*alertmanager.Silence, longLivedAge time.Duration, since, until time.Time) *Report {
+	r := &Report{
+		Since:          since,
+		Until:          until,
+		ActiveSilences: len(silences),
+		LongLivedAge:   longLivedAge,
+		LongLived:      make([]Silence, 0),
+		Unticketed:     make([]Silence, 0),
+	}
+
+	for _, sample := range samples {
+		if sample.Timestamp.Before(since) || sample.Timestamp.After(until) {
+			continue
+		}
+		r.Runs++
+		r.SilencesExtended += sample.SilencesExtended
+		r.SilencesDeleted += sample.SilencesDeleted
+		r.TicketsReopened += sample.TicketsReopened
+	}
+
+	for _, s := range silences {
+		entry := newSilence(s, until)
+		if entry.Age >= longLivedAge {
+			r.LongLived = append(r.LongLived, entry)
+		}
+		if s.TicketRef == "" {
+			r.Unticketed = append(r.Unticketed, entry)
+		}
+	}
+	sortOldestFirst(r.LongLived)
+	sortOldestFirst(r.Unticketed)
+
+	return r
+}
+
+// AddIdle lists the given silences, which currently match no alerts, in the report
+func (r *Report) AddIdle(silences []*alertmanager.Silence) {
+	r.Idle = make([]Silence, 0, len(silences))
+	for _, s := range silences {
+		r.Idle = append(r.Idle, newSilence(s, r.Until))
+	}
+	sortOldestFirst(r.Idle)
+}
+
+// AddAcknowledged lists the given silences, which acknowledge alerts in karma, in the report
+func (r *Report) AddAcknowledged(silences []*alertmanager.Silence) {
+	r.Acknowledged = make([]Silence, 0, len(silences))
+	for _, s := range silences {
+		r.Acknowledged = append(r.Acknowledged, newSilence(s, r.Until))
+	}
+	sortOldestFirst(r.Acknowledged)
+}
+
+// AcknowledgementsChecked reports whether karma acknowledgements were added to the report
+func (r *Report) AcknowledgementsChecked() bool {
+	return r.Acknowledged != nil
+}
+
+// IdleChecked reports whether the silenced alerts were checked for idle silences
+func (r *Report) IdleChecked() bool {
+	return r.Idle != nil
+}
+
+func newSilence(s *alertmanager.Silence, now time.Time) Silence {
+	matchers := make([]string, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		matchers = append(matchers, m.String())
+	}
+	return Silence{
+		ID:        s.ID,
+		TicketRef: s.TicketRef,
+		CreatedBy: s.CreatedBy,
+		Matchers:  strings.Join(matchers, " "),
+		StartsAt:  s.StartsAt,
+		EndsAt:    s.EndsAt,
+		Age:       now.Sub(s.StartsAt),
+	}
+}
+
+func sortOldestFirst(silences []Silence) {
+	sort.SliceStable(silences, func(i, j int) bool {
+		return silences[i].StartsAt.Before(silences[j].StartsAt)
+	})
+}
+
+// formatAge renders an age in whole days, or hours below a day
+func formatAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// WriteMarkdown renders the report as Markdown
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Silence hygiene report\n\n")
+	fmt.Fprintf(&b, "%s to %s (%d sync runs)\n\n", r.Since.Format("2006-01-02"), r.Until.Format("2006-01-02"), r.Runs)
+
+	fmt.Fprintf(&b, "## Activity\n\n")
+	fmt.Fprintf(&b, "| | Count |\n|---|---:|\n")
+	fmt.Fprintf(&b, "| Silences extended | %d |\n", r.SilencesExtended)
+	fmt.Fprintf(&b, "| Silences deleted | %d |\n", r.SilencesDeleted)
+	fmt.Fprintf(&b, "| Tickets reopened | %d |\n", r.TicketsReopened)
+	fmt.Fprintf(&b, "| Active silences | %d |\n\n", r.ActiveSilences)
+
+	writeMarkdownSilences(&b, fmt.Sprintf("Long-lived silences (older than %s)", formatAge(r.LongLivedAge)), r.LongLived)
+	writeMarkdownSilences(&b, "Silences without tickets", r.Unticketed)
+	if r.IdleChecked() {
+		writeMarkdownSilences(&b, "Silences matching no alerts", r.Idle)
+	}
+	if r.AcknowledgementsChecked() {
+		writeMarkdownSilences(&b, "Acknowledgements in karma", r.Acknowledged)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownSilences(b *strings.Builder, title string, silences []Silence) {
+	fmt.Fprintf(b, "## %s\n\n", title)
+	if len(silences) == 0 {
+		fmt.Fprintf(b, "None.\n\n")
+		return
+	}
+	fmt.Fprintf(b, "| Silence | Ticket | Matchers | Created by | Age | Expires |\n|---|---|---|---|---:|---|\n")
+	for _, s := range silences {
+		ticketRef := s.TicketRef
+		if ticketRef == "" {
+			ticketRef = "-"
+		}
+		fmt.Fprintf(b, "| %s | %s | `%s` | %s | %s | %s |\n",
+			s.ID, ticketRef, s.Matchers, s.CreatedBy, formatAge(s.Age), s.EndsAt.Format(time.RFC3339))
+	}
+	b.WriteString("\n")
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"age":  formatAge,
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+	"time": func(t time.Time) string { return t.Format(time.RFC3339) },
+}).Parse(`<h1>Silence hygiene report</h1>
+<p>{{date .Since}} to {{date .Until}} ({{.Runs}} sync runs)</p>
+<h2>Activity</h2>
+<table>
+<tr><td>Silences extended</td><td>{{.SilencesExtended}}</td></tr>
+<tr><td>Silences deleted</td><td>{{.SilencesDeleted}}</td></tr>
+<tr><td>Tickets reopened</td><td>{{.TicketsReopened}}</td></tr>
+<tr><td>Active silences</td><td>{{.ActiveSilences}}</td></tr>
+</table>
+<h2>Long-lived silences (older than {{age .LongLivedAge}})</h2>
+{{template "silences" .LongLived}}
+<h2>Silences without tickets</h2>
+{{template "silences" .Unticketed}}
+{{if .IdleChecked}}<h2>Silences matching no alerts</h2>
+{{template "silences" .Idle}}
+{{end}}{{if .AcknowledgementsChecked}}<h2>Acknowledgements in karma</h2>
+{{template "silences" .Acknowledged}}
+{{end}}{{define "silences"}}{{if .}}<table>
+<tr><th>Silence</th><th>Ticket</th><th>Matchers</th><th>Created by</th><th>Age</th><th>Expires</th></tr>
+{{range .}}<tr><td>{{.ID}}</td><td>{{or .TicketRef "-"}}</td><td><code>{{.Matchers}}</code></td><td>{{.CreatedBy}}</td><td>{{age .Age}}</td><td>{{time .EndsAt}}</td></tr>
+{{end}}</table>{{else}}<p>None.</p>{{end}}{{end}}`))
+
+// WriteHTML renders the report as an HTML fragment, e.g. for a Confluence page
+func (r *Report) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, r)
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+func newTestReport() *Report {
+	now := time.Now()
+	samples := []state.HygieneSample{
+		// Outside the window
+		{Timestamp: now.Add(-30 * 24 * time.Hour), SilencesExtended: 100},
+		{Timestamp: now.Add(-2 * time.Hour), SilencesExtended: 3, SilencesDeleted: 1},
+		{Timestamp: now.Add(-1 * time.Hour), SilencesExtended: 2, TicketsReopened: 1},
+	}
+	silences := []*alertmanager.Silence{
+		{ID: "recent", TicketRef: "PROJ-1", StartsAt: now.Add(-24 * time.Hour), EndsAt: now.Add(time.Hour)},
+		{ID: "old", TicketRef: "PROJ-2", StartsAt: now.Add(-60 * 24 * time.Hour), EndsAt: now.Add(time.Hour)},
+		{ID: "older-orphan", CreatedBy: "bob", StartsAt: now.Add(-90 * 24 * time.Hour), EndsAt: now.Add(time.Hour),
+			Matchers: []alertmanager.Matcher{{Name: "instance", Value: "db-.*", IsRegex: true, IsEqual: true}}},
+	}
+	return Build(samples, silences, 30*24*time.Hour, now.Add(-7*24*time.Hour), now)
+}
+
+func TestBuild(t *testing.T) {
+	r := newTestReport()
+
+	if r.Runs != 2 || r.SilencesExtended != 5 || r.SilencesDeleted != 1 || r.TicketsReopened != 1 {
+		t.Errorf("Unexpected activity: runs=%d extended=%d deleted=%d reopened=%d",
+			r.Runs, r.SilencesExtended, r.SilencesDeleted, r.TicketsReopened)
+	}
+	if r.ActiveSilences != 3 {
+		t.Errorf("Expected 3 active silences, got %d", r.ActiveSilences)
+	}
+	if len(r.LongLived) != 2 || r.LongLived[0].ID != "older-orphan" || r.LongLived[1].ID != "old" {
+		t.Errorf("Expected long-lived silences [older-orphan old], got %+v", r.LongLived)
+	}
+	if len(r.Unticketed) != 1 || r.Unticketed[0].Matchers != "instance=~db-.*" {
+		t.Errorf("Expected one silence without a ticket, got %+v", r.Unticketed)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestReport().WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"| Silences extended | 5 |",
+		"## Long-lived silences (older than 30d)",
+		"| older-orphan | - | `instance=~db-.*` | bob | 90d |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	r := newTestReport()
+	r.Unticketed[0].CreatedBy = "<script>"
+
+	var buf bytes.Buffer
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML() failed: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "<tr><td>Tickets reopened</td><td>1</td></tr>") {
+		t.Errorf("Expected activity table in report, got:\n%s", out)
+	}
+	if strings.Contains(out, "<script>") {
+		t.Error("Expected silence fields to be escaped")
+	}
+}
+
+func TestAddIdle(t *testing.T) {
+	r := newTestReport()
+
+	var buf bytes.Buffer
+	if err := r.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Silences matching no alerts") {
+		t.Error("Expected no idle section when silenced alerts were not checked")
+	}
+
+	now := time.Now()
+	r.AddIdle([]*alertmanager.Silence{
+		{ID: "idle-new", TicketRef: "PROJ-3", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{ID: "idle-old", TicketRef: "PROJ-4", StartsAt: now.Add(-48 * time.Hour), EndsAt: now.Add(time.Hour)},
+	})
+	if len(r.Idle) != 2 || r.Idle[0].ID != "idle-old" {
+		t.Errorf("Expected idle silences oldest first, got %+v", r.Idle)
+	}
+
+	buf.Reset()
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML() failed: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "<h2>Silences matching no alerts</h2>") || !strings.Contains(out, "<td>idle-old</td><td>PROJ-4</td>") {
+		t.Errorf("Expected idle section in report, got:\n%s", out)
+	}
+}
+
+func TestAddAcknowledged(t *testing.T) {
+	r := newTestReport()
+	now := time.Now()
+	r.AddAcknowledged([]*alertmanager.Silence{
+		{ID: "ack-linked", TicketRef: "PROJ-5", CreatedBy: "alice", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{ID: "ack-new", CreatedBy: "bob", StartsAt: now.Add(-time.Minute), EndsAt: now.Add(15 * time.Minute)},
+	})
+
+	var buf bytes.Buffer
+	if err := r.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"## Acknowledgements in karma", "| ack-linked | PROJ-5 |", "| ack-new | - |"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package rules reads the alerting rules loaded by Prometheus, Thanos Ruler or any other
+// ruler serving the Prometheus rules API, so that silences of removed rules are noticed.
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// Source lists the alerting rules that currently exist
+type Source interface {
+	// AlertNames returns the names of the alerting rules, sorted and without duplicates
+	AlertNames() ([]string, error)
+}
+
+// Prometheus reads alerting rules from the /api/v1/rules endpoint of Prometheus or
+// Thanos Ruler, or of Thanos Query to see the rules of all rulers at once
+type Prometheus struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+// NewPrometheus creates a client of the rules API at url, e.g. http://prometheus:9090,
+// sending token as a bearer token unless empty
+func NewPrometheus(url, token string) *Prometheus {
+	return &Prometheus{
+		url:   strings.TrimSuffix(url, "/"),
+		token: token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+type rulesResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Groups []struct {
+			Rules []struct {
+				Name string `json:"name"`
+				Type string `json:"type"`
+			} `json:"rules"`
+		} `json:"groups"`
+	} `json:"data"`
+}
+
+// AlertNames returns the names of the alerting rules of all rule groups
+func (p *Prometheus) AlertNames() ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.url+"/api/v1/rules?type=alert", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rules: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apierror.FromStatus(resp.StatusCode, string(body))
+	}
+
+	var response rulesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("rules API returned status %q: %s", response.Status, response.Error)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, group := range response.Data.Groups {
+		for _, rule := range group.Rules {
+			if rule.Type == "alerting" && rule.Name != "" && !seen[rule.Name] {
+				seen[rule.Name] = true
+				names = append(names, rule.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
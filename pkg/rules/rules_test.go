@@ -0,0 +1,51 @@
+package rules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheus_AlertNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/rules" || r.URL.Query().Get("type") != "alert" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Unexpected Authorization header: %q", auth)
+		}
+		w.Write([]byte(`{"status":"success","data":{"groups":[
+			{"name":"node","rules":[
+				{"name":"NodeDown","type":"alerting"},
+				{"name":"node:cpu:rate5m","type":"recording"},
+				{"name":"DiskFull","type":"alerting"}]},
+			{"name":"node-replica","rules":[{"name":"NodeDown","type":"alerting"}]}]}}`))
+	}))
+	defer server.Close()
+
+	names, err := NewPrometheus(server.URL+"/", "secret").AlertNames()
+	if err != nil {
+		t.Fatalf("AlertNames() failed: %v", err)
+	}
+	if got := strings.Join(names, ","); got != "DiskFull,NodeDown" {
+		t.Errorf("Unexpected alert names: %s", got)
+	}
+}
+
+func TestPrometheus_AlertNamesError(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"status code": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		},
+		"api error": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"status":"error","error":"rules not loaded"}`))
+		},
+	} {
+		server := httptest.NewServer(handler)
+		if _, err := NewPrometheus(server.URL, "").AlertNames(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,146 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BusinessHours describes the staffed hours of a team, used to make silences expire
+// while someone is around to respond to the alerts they release
+type BusinessHours struct {
+	Location *time.Location
+	Start    time.Duration // Offset from midnight when business hours start
+	End      time.Duration // Offset from midnight when business hours end
+	Days     map[time.Weekday]bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseBusinessHours parses business hours such as "09:00-17:00", work days such as
+// "Mon,Tue,Wed,Thu,Fri" (or a range like "Mon-Fri") and an IANA timezone name
+func ParseBusinessHours(hours, days, timezone string) (*BusinessHours, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+
+	startStr, endStr, ok := strings.Cut(hours, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid business hours %q (expected HH:MM-HH:MM)", hours)
+	}
+	start, err := parseClock(startStr)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return nil, err
+	}
+	if end <= start {
+		return nil, fmt.Errorf("invalid business hours %q (end must be after start)", hours)
+	}
+
+	workDays, err := parseDays(days)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BusinessHours{
+		Location: loc,
+		Start:    start,
+		End:      end,
+		Days:     workDays,
+	}, nil
+}
+
+// parseClock parses a HH:MM time of day into an offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (expected HH:MM)", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseDays parses a comma-separated list of weekdays or weekday ranges
+func parseDays(value string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return nil, fmt.Errorf("invalid weekday %q", to)
+			}
+		}
+
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+
+	if len(days) == 0 {
+		return nil, fmt.Errorf("at least one work day is required")
+	}
+	return days, nil
+}
+
+// Contains reports whether t falls within business hours
+func (b *BusinessHours) Contains(t time.Time) bool {
+	local := t.In(b.Location)
+	if !b.Days[local.Weekday()] {
+		return false
+	}
+	offset := local.Sub(midnight(local))
+	return offset >= b.Start && offset < b.End
+}
+
+// Next returns t if it falls within business hours, otherwise the start of the next
+// business-hours window after t. Times are only ever moved later, so a silence aligned
+// with Next never expires earlier than requested.
+func (b *BusinessHours) Next(t time.Time) time.Time {
+	if b.Contains(t) {
+		return t
+	}
+
+	local := t.In(b.Location)
+	day := midnight(local)
+	for i := 0; i <= 7; i++ {
+		if b.Days[day.Weekday()] {
+			start := day.Add(b.Start)
+			if start.After(local) {
+				return start
+			}
+		}
+		day = midnight(day.AddDate(0, 0, 1))
+	}
+
+	// Unreachable with at least one work day configured
+	return t
+}
+
+// midnight returns the start of the day of t in its location
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
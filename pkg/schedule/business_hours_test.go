@@ -0,0 +1,103 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBusinessHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   string
+		days    string
+		tz      string
+		wantErr bool
+	}{
+		{name: "weekdays list", hours: "09:00-17:00", days: "Mon,Tue,Wed,Thu,Fri", tz: "UTC"},
+		{name: "weekday range", hours: "08:30-18:00", days: "Mon-Fri", tz: "Europe/Dublin"},
+		{name: "wrapping range", hours: "09:00-17:00", days: "Sun-Thu", tz: "Asia/Jerusalem"},
+		{name: "missing separator", hours: "09:00", days: "Mon", tz: "UTC", wantErr: true},
+		{name: "end before start", hours: "17:00-09:00", days: "Mon", tz: "UTC", wantErr: true},
+		{name: "invalid day", hours: "09:00-17:00", days: "Mon,Funday", tz: "UTC", wantErr: true},
+		{name: "no days", hours: "09:00-17:00", days: "", tz: "UTC", wantErr: true},
+		{name: "invalid timezone", hours: "09:00-17:00", days: "Mon", tz: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBusinessHours(tt.hours, tt.days, tt.tz)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBusinessHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBusinessHours_WrappingRange(t *testing.T) {
+	bh, err := ParseBusinessHours("09:00-17:00", "Sun-Thu", "UTC")
+	if err != nil {
+		t.Fatalf("ParseBusinessHours() failed: %v", err)
+	}
+	for _, d := range []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday} {
+		if !bh.Days[d] {
+			t.Errorf("Expected %s to be a work day", d)
+		}
+	}
+	if bh.Days[time.Friday] || bh.Days[time.Saturday] {
+		t.Error("Expected Friday and Saturday not to be work days")
+	}
+}
+
+func TestBusinessHours_Next(t *testing.T) {
+	bh, err := ParseBusinessHours("09:00-17:00", "Mon-Fri", "Europe/Dublin")
+	if err != nil {
+		t.Fatalf("ParseBusinessHours() failed: %v", err)
+	}
+	dublin := bh.Location
+
+	tests := []struct {
+		name     string
+		input    time.Time
+		expected time.Time
+	}{
+		{
+			name:     "within business hours",
+			input:    time.Date(2024, 3, 13, 11, 30, 0, 0, dublin), // Wednesday
+			expected: time.Date(2024, 3, 13, 11, 30, 0, 0, dublin),
+		},
+		{
+			name:     "before opening",
+			input:    time.Date(2024, 3, 13, 3, 0, 0, 0, dublin),
+			expected: time.Date(2024, 3, 13, 9, 0, 0, 0, dublin),
+		},
+		{
+			name:     "after closing",
+			input:    time.Date(2024, 3, 13, 17, 0, 0, 0, dublin),
+			expected: time.Date(2024, 3, 14, 9, 0, 0, 0, dublin),
+		},
+		{
+			name:     "friday evening moves to monday",
+			input:    time.Date(2024, 3, 15, 22, 0, 0, 0, dublin),
+			expected: time.Date(2024, 3, 18, 9, 0, 0, 0, dublin),
+		},
+		{
+			name:     "sunday 3am moves to monday",
+			input:    time.Date(2024, 3, 17, 3, 0, 0, 0, dublin),
+			expected: time.Date(2024, 3, 18, 9, 0, 0, 0, dublin),
+		},
+		{
+			name:     "converts from UTC",
+			input:    time.Date(2024, 7, 10, 7, 30, 0, 0, time.UTC), // 08:30 IST
+			expected: time.Date(2024, 7, 10, 9, 0, 0, 0, dublin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bh.Next(tt.input)
+			if !got.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package slack
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/teams"
+)
+
+// ticketKeyPattern matches Jira issue keys such as PROJ-123
+var ticketKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-[0-9]+$`)
+
+// usage is the reply to the help command and to commands that cannot be parsed
+const usage = "Usage:\n" +
+	"• `create <matcher>... [duration] [TICKET-1] [summary]` creates a silence linked to the ticket, or to a new ticket with the summary, e.g. `create alertname=DiskFull instance=db-1 7d PROJ-123`\n" +
+	"• `list [label:value]...` lists the managed silences, optionally only those with the given matchers or ticket, e.g. `list team:payments`; without filters in a team's channel, only the team's silences"
+
+// command is a parsed slash command
+type command struct {
+	name    string                 // "create", "list" or "help"
+	def     sync.SilenceDefinition // Silence to create
+	filters []string               // Matchers (name=value) or ticket keys the listed silences must have
+	team    *teams.Team            // Team whose silences are listed, when listing without filters in its channel
+}
+
+// operation returns the authorization class of the command
+func (c *command) operation() authz.Operation {
+	if c.name == "create" {
+		return authz.OpWrite
+	}
+	return authz.OpRead
+}
+
+// parseCommand parses the text of a slash command, e.g.
+// "create alertname=Foo 7d PROJ-123" or "list team:payments"
+func parseCommand(text string) (*command, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return &command{name: "help"}, nil
+	}
+
+	cmd := &command{name: strings.ToLower(fields[0])}
+	switch cmd.name {
+	case "help":
+	case "create":
+		if err := parseCreate(cmd, fields[1:]); err != nil {
+			return nil, err
+		}
+	case "list":
+		for _, field := range fields[1:] {
+			if name, value, ok := strings.Cut(field, ":"); ok && !strings.Contains(field, "=") {
+				field = name + "=" + value
+			}
+			cmd.filters = append(cmd.filters, field)
+		}
+	default:
+		return nil, fmt.Errorf("unknown command %q", fields[0])
+	}
+	return cmd, nil
+}
+
+// parseCreate sorts the arguments of the create command into matchers, the duration,
+// the ticket and the words of the summary. Given a ticket, the words become the comment.
+func parseCreate(cmd *command, args []string) error {
+	var words []string
+	for _, arg := range args {
+		if matchers, err := sync.ParseMatchers([]string{arg}); err == nil {
+			cmd.def.Matchers = append(cmd.def.Matchers, matchers...)
+			continue
+		}
+		if cmd.def.Duration == 0 {
+			if d, err := sync.ParseDuration(arg); err == nil {
+				cmd.def.Duration = d
+				continue
+			}
+		}
+		if cmd.def.Ticket == "" && ticketKeyPattern.MatchString(arg) {
+			cmd.def.Ticket = arg
+			continue
+		}
+		words = append(words, arg)
+	}
+
+	if len(cmd.def.Matchers) == 0 {
+		return fmt.Errorf("at least one matcher such as alertname=Foo is required")
+	}
+	text := strings.Join(words, " ")
+	switch {
+	case cmd.def.Ticket != "":
+		cmd.def.Comment = text
+	case text != "":
+		cmd.def.Summary = text
+	default:
+		return fmt.Errorf("either a ticket such as PROJ-123 or a summary for a new ticket is required")
+	}
+	return nil
+}
+
+// matches reports whether a listed silence has every matcher or ticket of the filters
+func (c *command) matches(p sync.PlannedAction) bool {
+	for _, filter := range c.filters {
+		found := filter == p.TicketRef
+		for _, m := range p.Matchers {
+			found = found || m == filter
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ownedBy reports whether a listed silence matches one of the team's values of label
+func ownedBy(p sync.PlannedAction, team *teams.Team, label string) bool {
+	for _, value := range team.Values() {
+		if slices.Contains(p.Matchers, label+"="+value) {
+			return true
+		}
+	}
+	return false
+}
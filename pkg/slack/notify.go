@@ -0,0 +1,45 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// Notifier posts messages to the channel of a Slack incoming webhook
+type Notifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewNotifier creates a notifier posting to an incoming webhook URL, e.g.
+// https://hooks.slack.com/services/T000/B000/XXXX
+func NewNotifier(webhookURL string) *Notifier {
+	return &Notifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Notify posts text, which may use Slack's mrkdwn formatting, to the channel
+func (n *Notifier) Notify(text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	resp, err := n.httpClient.Post(n.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(respBody))
+	}
+	return nil
+}
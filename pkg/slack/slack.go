@@ -0,0 +1,268 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/teams"
+)
+
+const (
+	// maxRequestAge bounds the age of a signed request, preventing replays
+	maxRequestAge = 5 * time.Minute
+
+	// commandTimeout bounds how long a command may take; Slack accepts responses for 30 minutes
+	commandTimeout = 2 * time.Minute
+
+	// maxListed is the number of silences listed in one response
+	maxListed = 25
+)
+
+// Backend performs the operations of the slash commands
+type Backend interface {
+	// Create creates a silence linked to a ticket
+	Create(ctx context.Context, def sync.SilenceDefinition) (*alertmanager.Silence, error)
+
+	// List returns the active silences, their tickets and the next action for each
+	List(ctx context.Context) ([]sync.PlannedAction, error)
+}
+
+// Handler serves the slash command of a Slack app, e.g. "/silence create alertname=Foo 7d
+// PROJ-123" or "/silence list team:payments". Requests must carry the signature of the
+// app's signing secret. Commands are acknowledged at once and answered through the
+// request's response URL, as Slack expects a reply within three seconds.
+type Handler struct {
+	backend       Backend
+	signingSecret []byte
+	operators     map[string]bool
+	teams         *teams.Directory
+	httpClient    *http.Client
+	now           func() time.Time
+}
+
+// NewHandler creates a slash command handler. Only the Slack user IDs in operators may
+// create silences; everyone in the workspace may list them.
+func NewHandler(backend Backend, signingSecret string, operators []string) *Handler {
+	h := &Handler{
+		backend:       backend,
+		signingSecret: []byte(signingSecret),
+		operators:     make(map[string]bool, len(operators)),
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		now:           time.Now,
+	}
+	for _, id := range operators {
+		h.operators[id] = true
+	}
+	return h
+}
+
+// SetTeams sets the team mapping. Listing silences without filters in the Slack channel
+// of a team then lists only the silences of that team.
+func (h *Handler) SetTeams(directory *teams.Directory) {
+	h.teams = directory
+}
+
+// message is a response to a slash command
+type message struct {
+	ResponseType string `json:"response_type"` // "ephemeral" or "in_channel"
+	Text         string `json:"text"`
+}
+
+// ServeHTTP handles a slash command
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<16))
+	if err != nil {
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := h.verify(r.Header, body); err != nil {
+		log.Printf("Warning: rejected Slack request: %v", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	cmd, err := parseCommand(form.Get("text"))
+	if err != nil {
+		writeMessage(w, message{ResponseType: "ephemeral", Text: fmt.Sprintf("%v\n%s", err, usage)})
+		return
+	}
+	if cmd.name == "help" {
+		writeMessage(w, message{ResponseType: "ephemeral", Text: usage})
+		return
+	}
+
+	userID := form.Get("user_id")
+	role := authz.RoleViewer
+	if h.operators[userID] {
+		role = authz.RoleOperator
+	}
+	if err := authz.Authorize(role, cmd.operation(), false); err != nil {
+		log.Printf("Slack user %s may not %s silences", userID, cmd.name)
+		writeMessage(w, message{ResponseType: "ephemeral", Text: fmt.Sprintf("You may not %s silences. Ask an administrator to add your Slack user ID (%s) to SLACK_OPERATORS.", cmd.name, userID)})
+		return
+	}
+
+	if cmd.name == "list" && len(cmd.filters) == 0 {
+		cmd.team = h.teams.ForChannel(form.Get("channel_name"))
+	}
+
+	responseURL := form.Get("response_url")
+	if responseURL == "" {
+		http.Error(w, "missing response_url", http.StatusBadRequest)
+		return
+	}
+	log.Printf("Slack command %q from user %s", cmd.name, userID)
+	go h.respond(responseURL, cmd, userID, form.Get("user_name"))
+	writeMessage(w, message{ResponseType: "ephemeral", Text: "Working on it..."})
+}
+
+func (h *Handler) create(ctx context.Context, def sync.SilenceDefinition, userID, userName string) message {
+	if userName == "" {
+		userName = userID
+	}
+	def.CreatedBy = userName
+	comment := "Created from Slack by " + userName
+	if def.Comment != "" {
+		comment += ": " + def.Comment
+	}
+	def.Comment = comment
+
+	silence, err := h.backend.Create(ctx, def)
+	if err != nil {
+		return message{ResponseType: "ephemeral", Text: fmt.Sprintf("Failed to create silence: %v", err)}
+	}
+	return message{
+		ResponseType: "in_channel",
+		Text: fmt.Sprintf("<@%s> created silence `%s` for `%s` linked to %s, expiring at %s",
+			userID, silence.ID, formatMatchers(def.Matchers), silence.TicketRef, silence.EndsAt.UTC().Format(time.RFC3339)),
+	}
+}
+
+func (h *Handler) list(ctx context.Context, cmd *command) message {
+	silences, err := h.backend.List(ctx)
+	if err != nil {
+		return message{ResponseType: "ephemeral", Text: fmt.Sprintf("Failed to list silences: %v", err)}
+	}
+
+	var lines []string
+	for _, p := range silences {
+		if p.TicketRef == "" || !cmd.matches(p) {
+			continue
+		}
+		if cmd.team != nil && !ownedBy(p, cmd.team, h.teams.Label) {
+			continue
+		}
+		if len(lines) == maxListed {
+			lines = append(lines, "…")
+			break
+		}
+		status := p.TicketStatus
+		if status == "" {
+			status = "unknown"
+		}
+		lines = append(lines, fmt.Sprintf("• `%s` `%s` %s (%s), expires %s, next: %s",
+			p.SilenceID, strings.Join(p.Matchers, " "), p.TicketRef, status, p.EndsAt.UTC().Format(time.RFC3339), p.Action))
+	}
+
+	scope := ""
+	if cmd.team != nil {
+		scope = " of team " + cmd.team.Name
+	}
+	if len(lines) == 0 {
+		return message{ResponseType: "ephemeral", Text: fmt.Sprintf("No managed silences%s found.", scope)}
+	}
+	return message{ResponseType: "ephemeral", Text: fmt.Sprintf("Managed silences%s:\n%s", scope, strings.Join(lines, "\n"))}
+}
+
+// respond performs a command and posts the reply to the request's response URL
+func (h *Handler) respond(responseURL string, cmd *command, userID, userName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var reply message
+	switch cmd.name {
+	case "create":
+		reply = h.create(ctx, cmd.def, userID, userName)
+	default:
+		reply = h.list(ctx, cmd)
+	}
+
+	body, err := json.Marshal(reply)
+	if err != nil {
+		log.Printf("Warning: failed to marshal Slack response: %v", err)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Warning: failed to create Slack response: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		log.Printf("Warning: failed to send Slack response: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Warning: Slack rejected response with status %d", resp.StatusCode)
+	}
+}
+
+// verify checks the request signature, computed by Slack as an HMAC-SHA256 of
+// "v0:<timestamp>:<body>" with the signing secret
+func (h *Handler) verify(header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid request timestamp")
+	}
+	if age := h.now().Sub(time.Unix(seconds, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("request timestamp is %v off", age.Round(time.Second))
+	}
+
+	mac := hmac.New(sha256.New, h.signingSecret)
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func writeMessage(w http.ResponseWriter, msg message) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(msg); err != nil {
+		log.Printf("Warning: failed to write Slack response: %v", err)
+	}
+}
+
+func formatMatchers(matchers []alertmanager.Matcher) string {
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		parts = append(parts, m.String())
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,230 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/sync"
+	"github.com/conallob/silence-manager/pkg/teams"
+)
+
+const testSecret = "signing-secret"
+
+// fakeBackend records the silences created through the handler
+type fakeBackend struct {
+	created []sync.SilenceDefinition
+}
+
+func (f *fakeBackend) Create(ctx context.Context, def sync.SilenceDefinition) (*alertmanager.Silence, error) {
+	f.created = append(f.created, def)
+	return &alertmanager.Silence{ID: "silence-9", TicketRef: def.Ticket, EndsAt: time.Now().Add(def.Duration)}, nil
+}
+
+func (f *fakeBackend) List(ctx context.Context) ([]sync.PlannedAction, error) {
+	return []sync.PlannedAction{
+		{SilenceID: "silence-1", Matchers: []string{"alertname=Latency", "team=payments"}, TicketRef: "PAY-1", TicketStatus: "In Progress", Action: sync.ActionNone},
+		{SilenceID: "silence-2", Matchers: []string{"alertname=DiskFull", "team=storage"}, TicketRef: "STO-7", TicketStatus: "Open", Action: sync.ActionExtend},
+		{SilenceID: "silence-3", Matchers: []string{"team=payments"}, Action: sync.ActionSkip},
+	}, nil
+}
+
+// slashCommand sends a signed slash command and returns the immediate reply
+func slashCommand(t *testing.T, handler http.Handler, userID, text, responseURL string) (int, message) {
+	t.Helper()
+	return channelCommand(t, handler, "general", userID, text, responseURL)
+}
+
+// channelCommand sends a signed slash command from a channel and returns the immediate reply
+func channelCommand(t *testing.T, handler http.Handler, channel, userID, text, responseURL string) (int, message) {
+	t.Helper()
+	body := url.Values{
+		"command":      {"/silence"},
+		"text":         {text},
+		"channel_name": {channel},
+		"user_id":      {userID},
+		"user_name":    {"jdoe"},
+		"response_url": {responseURL},
+	}.Encode()
+	timestamp := fmt.Sprint(time.Now().Unix())
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var msg message
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&msg); err != nil {
+			t.Fatalf("Failed to decode reply: %v", err)
+		}
+	}
+	return rec.Code, msg
+}
+
+// responseServer receives the replies posted to the response URL
+func responseServer(t *testing.T) (*httptest.Server, chan message) {
+	replies := make(chan message, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("Failed to decode response: %v", err)
+		}
+		replies <- msg
+	}))
+	t.Cleanup(server.Close)
+	return server, replies
+}
+
+func awaitReply(t *testing.T, replies chan message) message {
+	t.Helper()
+	select {
+	case msg := <-replies:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("No reply was posted to the response URL")
+		return message{}
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	cmd, err := parseCommand("create alertname=Foo instance=~\"web-.*\" 7d PROJ-123 rolling restart")
+	if err != nil {
+		t.Fatalf("parseCommand() failed: %v", err)
+	}
+	if cmd.name != "create" || len(cmd.def.Matchers) != 2 || cmd.def.Duration != 7*24*time.Hour ||
+		cmd.def.Ticket != "PROJ-123" || cmd.def.Comment != "rolling restart" || cmd.def.Summary != "" {
+		t.Errorf("Unexpected create command: %+v", cmd.def)
+	}
+
+	cmd, err = parseCommand("create alertname=Foo Disk replacement on db-1")
+	if err != nil {
+		t.Fatalf("parseCommand() failed: %v", err)
+	}
+	if cmd.def.Ticket != "" || cmd.def.Summary != "Disk replacement on db-1" || cmd.def.Duration != 0 {
+		t.Errorf("Expected a summary for a new ticket, got %+v", cmd.def)
+	}
+
+	cmd, err = parseCommand("list team:payments severity=page")
+	if err != nil {
+		t.Fatalf("parseCommand() failed: %v", err)
+	}
+	if len(cmd.filters) != 2 || cmd.filters[0] != "team=payments" || cmd.filters[1] != "severity=page" {
+		t.Errorf("Unexpected list filters: %v", cmd.filters)
+	}
+
+	for _, text := range []string{"create 7d PROJ-1", "create alertname=Foo", "delete silence-1"} {
+		if _, err := parseCommand(text); err == nil {
+			t.Errorf("Expected an error for %q", text)
+		}
+	}
+}
+
+func TestHandler_RejectsInvalidSignature(t *testing.T) {
+	handler := NewHandler(&fakeBackend{}, "other-secret", nil)
+	if code, _ := slashCommand(t, handler, "U1", "list", "http://unused"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a request signed with another secret, got %d", code)
+	}
+
+	handler = NewHandler(&fakeBackend{}, testSecret, nil)
+	handler.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+	if code, _ := slashCommand(t, handler, "U1", "list", "http://unused"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a replayed request, got %d", code)
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	backend := &fakeBackend{}
+	handler := NewHandler(backend, testSecret, []string{"U-OPERATOR"})
+	server, replies := responseServer(t)
+
+	_, msg := slashCommand(t, handler, "U-VIEWER", "create alertname=Foo 7d PROJ-123", server.URL)
+	if !strings.Contains(msg.Text, "SLACK_OPERATORS") {
+		t.Errorf("Expected a user outside SLACK_OPERATORS to be refused, got %q", msg.Text)
+	}
+
+	if _, msg := slashCommand(t, handler, "U-OPERATOR", "create alertname=Foo 7d PROJ-123", server.URL); msg.Text != "Working on it..." {
+		t.Errorf("Expected an acknowledgement, got %q", msg.Text)
+	}
+	reply := awaitReply(t, replies)
+	if reply.ResponseType != "in_channel" || !strings.Contains(reply.Text, "silence-9") || !strings.Contains(reply.Text, "PROJ-123") {
+		t.Errorf("Unexpected reply: %+v", reply)
+	}
+	if len(backend.created) != 1 || backend.created[0].CreatedBy != "jdoe" || backend.created[0].Comment != "Created from Slack by jdoe" {
+		t.Errorf("Unexpected silences created: %+v", backend.created)
+	}
+}
+
+func TestHandler_List(t *testing.T) {
+	handler := NewHandler(&fakeBackend{}, testSecret, nil)
+	server, replies := responseServer(t)
+
+	slashCommand(t, handler, "U-VIEWER", "list team:payments", server.URL)
+	reply := awaitReply(t, replies)
+	if !strings.Contains(reply.Text, "silence-1") || strings.Contains(reply.Text, "silence-2") || strings.Contains(reply.Text, "silence-3") {
+		t.Errorf("Expected only the managed payments silence, got %q", reply.Text)
+	}
+
+	slashCommand(t, handler, "U-VIEWER", "list STO-7", server.URL)
+	if reply := awaitReply(t, replies); !strings.Contains(reply.Text, "silence-2") || strings.Contains(reply.Text, "silence-1") {
+		t.Errorf("Expected only the silence of STO-7, got %q", reply.Text)
+	}
+}
+
+func TestHandler_ListInTeamChannel(t *testing.T) {
+	directory, err := teams.Parse([]byte("teams: [{name: storage, slackChannel: '#storage-oncall'}]"))
+	if err != nil {
+		t.Fatalf("teams.Parse() failed: %v", err)
+	}
+	handler := NewHandler(&fakeBackend{}, testSecret, nil)
+	handler.SetTeams(directory)
+	server, replies := responseServer(t)
+
+	channelCommand(t, handler, "storage-oncall", "U-VIEWER", "list", server.URL)
+	reply := awaitReply(t, replies)
+	if !strings.Contains(reply.Text, "of team storage") || !strings.Contains(reply.Text, "silence-2") || strings.Contains(reply.Text, "silence-1") {
+		t.Errorf("Expected only the storage silence, got %q", reply.Text)
+	}
+
+	channelCommand(t, handler, "storage-oncall", "U-VIEWER", "list team:payments", server.URL)
+	if reply := awaitReply(t, replies); !strings.Contains(reply.Text, "silence-1") {
+		t.Errorf("Expected filters to take precedence over the channel, got %q", reply.Text)
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode message: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	if err := NewNotifier(server.URL).Notify("Ticket PROJ-1 has been escalated"); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	if got["text"] != "Ticket PROJ-1 has been escalated" {
+		t.Errorf("Unexpected message: %v", got)
+	}
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	})
+	if err := NewNotifier(server.URL).Notify("text"); err == nil {
+		t.Error("Expected an error for a rejected message")
+	}
+}
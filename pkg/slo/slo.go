@@ -0,0 +1,123 @@
+package slo
+
+import (
+	"sort"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// Objectives are the silence hygiene targets a deployment aims to meet
+type Objectives struct {
+	// MinOpenTicketRatio is the minimum fraction of silences that should be backed by an open ticket
+	MinOpenTicketRatio float64
+	// MaxOrphanRatio is the maximum fraction of silences allowed without a ticket reference
+	MaxOrphanRatio float64
+	// MaxMedianAge is the maximum acceptable median silence age
+	MaxMedianAge time.Duration
+}
+
+// DefaultObjectives returns the default hygiene objectives
+func DefaultObjectives() Objectives {
+	return Objectives{
+		MinOpenTicketRatio: 0.9,
+		MaxOrphanRatio:     0.1,
+		MaxMedianAge:       30 * 24 * time.Hour,
+	}
+}
+
+// Report summarizes hygiene samples over a window and how well they met the objectives
+type Report struct {
+	Since      time.Time            `json:"since"`
+	Until      time.Time            `json:"until"`
+	Samples    int                  `json:"samples"`
+	Objectives Objectives           `json:"objectives"`
+	Latest     *state.HygieneSample `json:"latest,omitempty"`
+
+	AvgOpenTicketRatio float64       `json:"avgOpenTicketRatio"`
+	AvgOrphanRatio     float64       `json:"avgOrphanRatio"`
+	MedianAge          time.Duration `json:"medianAge"` // Median of the per-run median ages
+
+	// Compliance is the fraction of samples in the window that met each objective
+	OpenTicketCompliance float64 `json:"openTicketCompliance"`
+	OrphanCompliance     float64 `json:"orphanCompliance"`
+	MedianAgeCompliance  float64 `json:"medianAgeCompliance"`
+}
+
+// Met reports whether the latest sample meets every objective
+func (r *Report) Met() bool {
+	if r.Latest == nil {
+		return true
+	}
+	return r.Latest.OpenTicketRatio() >= r.Objectives.MinOpenTicketRatio &&
+		r.Latest.OrphanRatio() <= r.Objectives.MaxOrphanRatio &&
+		r.Latest.MedianAge <= r.Objectives.MaxMedianAge
+}
+
+// Evaluate computes a report for the samples taken at or after since
+func Evaluate(samples []state.HygieneSample, objectives Objectives, since, until time.Time) *Report {
+	report := &Report{
+		Since:      since,
+		Until:      until,
+		Objectives: objectives,
+	}
+
+	var ages []time.Duration
+	var openMet, orphanMet, ageMet int
+	for i := range samples {
+		sample := samples[i]
+		if sample.Timestamp.Before(since) || sample.Timestamp.After(until) {
+			continue
+		}
+
+		report.Samples++
+		report.AvgOpenTicketRatio += sample.OpenTicketRatio()
+		report.AvgOrphanRatio += sample.OrphanRatio()
+		ages = append(ages, sample.MedianAge)
+
+		if sample.OpenTicketRatio() >= objectives.MinOpenTicketRatio {
+			openMet++
+		}
+		if sample.OrphanRatio() <= objectives.MaxOrphanRatio {
+			orphanMet++
+		}
+		if sample.MedianAge <= objectives.MaxMedianAge {
+			ageMet++
+		}
+
+		if report.Latest == nil || sample.Timestamp.After(report.Latest.Timestamp) {
+			report.Latest = &sample
+		}
+	}
+
+	if report.Samples == 0 {
+		return report
+	}
+
+	n := float64(report.Samples)
+	report.AvgOpenTicketRatio /= n
+	report.AvgOrphanRatio /= n
+	report.MedianAge = MedianDuration(ages)
+	report.OpenTicketCompliance = float64(openMet) / n
+	report.OrphanCompliance = float64(orphanMet) / n
+	report.MedianAgeCompliance = float64(ageMet) / n
+
+	return report
+}
+
+// MedianDuration returns the median of the given durations, or 0 if there are none
+func MedianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,80 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+func TestMedianDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []time.Duration
+		expected time.Duration
+	}{
+		{"Empty", nil, 0},
+		{"Single", []time.Duration{time.Hour}, time.Hour},
+		{"Odd", []time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour}, 2 * time.Hour},
+		{"Even", []time.Duration{4 * time.Hour, time.Hour, 2 * time.Hour, 3 * time.Hour}, 150 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MedianDuration(tt.input); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	samples := []state.HygieneSample{
+		// Outside the window
+		{Timestamp: now.Add(-30 * 24 * time.Hour), TotalSilences: 10, OpenTicketSilences: 0, OrphanSilences: 10},
+		// Meets every objective
+		{Timestamp: now.Add(-2 * time.Hour), TotalSilences: 10, OpenTicketSilences: 10, OrphanSilences: 0, MedianAge: 24 * time.Hour},
+		// Misses the open ticket and orphan objectives
+		{Timestamp: now.Add(-1 * time.Hour), TotalSilences: 10, OpenTicketSilences: 5, OrphanSilences: 5, MedianAge: 48 * time.Hour},
+	}
+
+	report := Evaluate(samples, DefaultObjectives(), now.Add(-7*24*time.Hour), now)
+
+	if report.Samples != 2 {
+		t.Fatalf("Expected 2 samples in window, got %d", report.Samples)
+	}
+	if report.AvgOpenTicketRatio != 0.75 {
+		t.Errorf("Expected average open ticket ratio 0.75, got %v", report.AvgOpenTicketRatio)
+	}
+	if report.AvgOrphanRatio != 0.25 {
+		t.Errorf("Expected average orphan ratio 0.25, got %v", report.AvgOrphanRatio)
+	}
+	if report.OpenTicketCompliance != 0.5 || report.OrphanCompliance != 0.5 {
+		t.Errorf("Expected 50%% compliance, got open=%v orphan=%v", report.OpenTicketCompliance, report.OrphanCompliance)
+	}
+	if report.MedianAgeCompliance != 1 {
+		t.Errorf("Expected 100%% median age compliance, got %v", report.MedianAgeCompliance)
+	}
+	if report.MedianAge != 36*time.Hour {
+		t.Errorf("Expected median age 36h, got %v", report.MedianAge)
+	}
+	if report.Latest == nil || !report.Latest.Timestamp.Equal(samples[2].Timestamp) {
+		t.Error("Expected latest sample to be the most recent one")
+	}
+	if report.Met() {
+		t.Error("Expected objectives not to be met by the latest sample")
+	}
+}
+
+func TestEvaluate_NoSamples(t *testing.T) {
+	now := time.Now()
+	report := Evaluate(nil, DefaultObjectives(), now.Add(-time.Hour), now)
+
+	if report.Samples != 0 || report.Latest != nil {
+		t.Error("Expected an empty report")
+	}
+	if !report.Met() {
+		t.Error("Expected an empty report to count as met")
+	}
+}
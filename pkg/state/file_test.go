@@ -0,0 +1,106 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore_LoadMissingFile(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(st.Hygiene) != 0 {
+		t.Errorf("Expected empty state, got %d hygiene samples", len(st.Hygiene))
+	}
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	store := NewFileStore(path)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	st := NewState()
+	st.AddHygieneSample(HygieneSample{
+		Timestamp:          now,
+		TotalSilences:      10,
+		OpenTicketSilences: 8,
+		OrphanSilences:     1,
+		MedianAge:          36 * time.Hour,
+	}, DefaultHygieneRetention)
+
+	if err := store.Save(st); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected temporary file to be removed after save")
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(loaded.Hygiene) != 1 {
+		t.Fatalf("Expected 1 hygiene sample, got %d", len(loaded.Hygiene))
+	}
+	if got := loaded.Hygiene[0]; !got.Timestamp.Equal(now) || got.TotalSilences != 10 || got.MedianAge != 36*time.Hour {
+		t.Errorf("Unexpected sample after round trip: %+v", got)
+	}
+}
+
+func TestFileStore_LoadInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := NewFileStore(path).Load(); err == nil {
+		t.Error("Expected error for invalid state file")
+	}
+}
+
+func TestAddHygieneSample_Retention(t *testing.T) {
+	now := time.Now()
+	st := NewState()
+	st.AddHygieneSample(HygieneSample{Timestamp: now.Add(-10 * 24 * time.Hour)}, 7*24*time.Hour)
+	st.AddHygieneSample(HygieneSample{Timestamp: now.Add(-2 * 24 * time.Hour)}, 7*24*time.Hour)
+	st.AddHygieneSample(HygieneSample{Timestamp: now}, 7*24*time.Hour)
+
+	if len(st.Hygiene) != 2 {
+		t.Errorf("Expected samples older than retention to be dropped, got %d samples", len(st.Hygiene))
+	}
+}
+
+func TestAddJournalRun_Retention(t *testing.T) {
+	now := time.Now()
+	st := NewState()
+	st.AddJournalRun(JournalRun{ID: "old", StartedAt: now.Add(-10 * 24 * time.Hour)}, 7*24*time.Hour)
+	st.AddJournalRun(JournalRun{ID: "recent", StartedAt: now.Add(-2 * 24 * time.Hour)}, 7*24*time.Hour)
+	st.AddJournalRun(JournalRun{ID: "latest", StartedAt: now}, 7*24*time.Hour)
+
+	if _, ok := st.FindJournalRun("old"); ok || len(st.Journal) != 2 {
+		t.Errorf("Expected journals older than retention to be dropped, got %d journals", len(st.Journal))
+	}
+	if run, ok := st.FindJournalRun("recent"); !ok || run.ID != "recent" {
+		t.Errorf("Expected to find the recent journal, got %+v", run)
+	}
+}
+
+func TestHygieneSample_Ratios(t *testing.T) {
+	empty := HygieneSample{}
+	if empty.OpenTicketRatio() != 1 || empty.OrphanRatio() != 0 {
+		t.Error("Expected an empty sample to be perfectly healthy")
+	}
+
+	sample := HygieneSample{TotalSilences: 4, OpenTicketSilences: 3, OrphanSilences: 1}
+	if sample.OpenTicketRatio() != 0.75 {
+		t.Errorf("Expected open ticket ratio 0.75, got %v", sample.OpenTicketRatio())
+	}
+	if sample.OrphanRatio() != 0.25 {
+		t.Errorf("Expected orphan ratio 0.25, got %v", sample.OrphanRatio())
+	}
+}
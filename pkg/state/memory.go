@@ -0,0 +1,23 @@
+package state
+
+// MemoryStore keeps state in memory for the lifetime of the process
+// Used when no persistent state backend is configured (the default)
+type MemoryStore struct {
+	state *State
+}
+
+// NewMemoryStore creates a new in-memory state store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{state: NewState()}
+}
+
+// Load returns the in-memory state
+func (m *MemoryStore) Load() (*State, error) {
+	return m.state, nil
+}
+
+// Save replaces the in-memory state
+func (m *MemoryStore) Save(st *State) error {
+	m.state = st
+	return nil
+}
@@ -0,0 +1,45 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RemoteStore persists state directly in a Remote such as a Redis key, for runners
+// without local storage that should share the state of every run as it happens
+type RemoteStore struct {
+	remote Remote
+}
+
+// NewRemoteStore creates a store reading and writing the state document in remote
+func NewRemoteStore(remote Remote) *RemoteStore {
+	return &RemoteStore{remote: remote}
+}
+
+// Load reads the state, returning an empty state if none has been saved yet
+func (r *RemoteStore) Load() (*State, error) {
+	data, err := r.remote.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	st := NewState()
+	if data == nil {
+		return st, nil
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("failed to decode state: %w", err)
+	}
+	return st, nil
+}
+
+// Save replaces the state document
+func (r *RemoteStore) Save(st *State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err := r.remote.Put(data); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
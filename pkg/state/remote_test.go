@@ -0,0 +1,38 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRemoteStore(t *testing.T) {
+	remote := &memoryRemote{}
+	store := NewRemoteStore(remote)
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if st.Idle == nil || len(st.Idle) != 0 {
+		t.Fatalf("Expected an empty state before the first save, got %+v", st.Idle)
+	}
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	st.Idle["silence-1"] = IdleSilence{TicketKey: "PROJ-1", Since: since}
+	if err := store.Save(st); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	loaded, err := NewRemoteStore(remote).Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if rec := loaded.Idle["silence-1"]; rec.TicketKey != "PROJ-1" || !rec.Since.Equal(since) {
+		t.Errorf("Expected the saved state, got %+v", loaded.Idle)
+	}
+
+	remote.err = errors.New("connection refused")
+	if _, err := store.Load(); err == nil {
+		t.Error("Expected an error when the remote cannot be read")
+	}
+}
@@ -0,0 +1,105 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Remote holds a copy of the state outside the pod, such as a ConfigMap, an object in a
+// bucket or a Redis key, for runners that lose their filesystem between runs
+type Remote interface {
+	// Get returns the state document, or nil if none has been exported yet
+	Get() ([]byte, error)
+
+	// Put replaces the state document
+	Put(data []byte) error
+}
+
+// SyncedStore keeps the state in a local store during a run and carries it between
+// runs in a Remote. The first Load imports the remote copy into the local store, and
+// Export writes the local state back once the run has saved changes.
+type SyncedStore struct {
+	local  Store
+	remote Remote
+
+	mu       sync.Mutex
+	imported bool
+	changed  bool
+}
+
+// NewSyncedStore creates a store importing from and exporting to remote
+func NewSyncedStore(local Store, remote Remote) *SyncedStore {
+	return &SyncedStore{local: local, remote: remote}
+}
+
+// Load returns the local state, importing the remote copy first if it has not been
+// imported yet. Without a remote copy, the local state is used as it is.
+func (s *SyncedStore) Load() (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.importRemote(); err != nil {
+		return nil, err
+	}
+	return s.local.Load()
+}
+
+// Save saves the state locally; it is exported by the next Export
+func (s *SyncedStore) Save(st *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Saving over a state that was never imported would lose the remote copy on export
+	if err := s.importRemote(); err != nil {
+		return err
+	}
+	if err := s.local.Save(st); err != nil {
+		return err
+	}
+	s.changed = true
+	return nil
+}
+
+// Export writes the local state to the remote if it has been saved since the last
+// export. It returns whether the state was exported.
+func (s *SyncedStore) Export() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.changed {
+		return false, nil
+	}
+	st, err := s.local.Load()
+	if err != nil {
+		return false, err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err := s.remote.Put(data); err != nil {
+		return false, fmt.Errorf("failed to export state: %w", err)
+	}
+	s.changed = false
+	return true, nil
+}
+
+// importRemote replaces the local state with the remote copy, once
+func (s *SyncedStore) importRemote() error {
+	if s.imported {
+		return nil
+	}
+	data, err := s.remote.Get()
+	if err != nil {
+		return fmt.Errorf("failed to import state: %w", err)
+	}
+	if data != nil {
+		st := NewState()
+		if err := json.Unmarshal(data, st); err != nil {
+			return fmt.Errorf("failed to decode imported state: %w", err)
+		}
+		if err := s.local.Save(st); err != nil {
+			return err
+		}
+	}
+	s.imported = true
+	return nil
+}
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryRemote is a remote state copy kept in memory
+type memoryRemote struct {
+	data []byte
+	puts int
+	err  error
+}
+
+func (r *memoryRemote) Get() ([]byte, error) {
+	return r.data, r.err
+}
+
+func (r *memoryRemote) Put(data []byte) error {
+	r.data = data
+	r.puts++
+	return nil
+}
+
+func TestSyncedStore_ImportExport(t *testing.T) {
+	remote := &memoryRemote{data: []byte(`{"idle":{"silence-1":{"ticketKey":"PROJ-1","since":"2026-01-01T00:00:00Z"}}}`)}
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := NewSyncedStore(NewFileStore(path), remote)
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if st.Idle["silence-1"].TicketKey != "PROJ-1" {
+		t.Fatalf("Expected the remote state to be imported, got %+v", st.Idle)
+	}
+	if exported, err := store.Export(); err != nil || exported {
+		t.Errorf("Expected an unchanged state not to be exported, got %v, %v", exported, err)
+	}
+
+	st.Idle["silence-2"] = IdleSilence{TicketKey: "PROJ-2", Since: time.Now()}
+	if err := store.Save(st); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if exported, err := store.Export(); err != nil || !exported {
+		t.Fatalf("Expected the changed state to be exported, got %v, %v", exported, err)
+	}
+	if remote.puts != 1 || !strings.Contains(string(remote.data), "PROJ-2") {
+		t.Errorf("Unexpected exported state: %s", remote.data)
+	}
+
+	// A new runner starts from the exported copy
+	st, err = NewSyncedStore(NewFileStore(filepath.Join(t.TempDir(), "state.json")), remote).Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(st.Idle) != 2 {
+		t.Errorf("Expected both idle silences on the new runner, got %+v", st.Idle)
+	}
+}
+
+func TestSyncedStore_ImportError(t *testing.T) {
+	remote := &memoryRemote{err: errors.New("unavailable")}
+	store := NewSyncedStore(NewMemoryStore(), remote)
+
+	if _, err := store.Load(); err == nil {
+		t.Error("Expected Load() to fail when the remote state cannot be read")
+	}
+	if err := store.Save(NewState()); err == nil {
+		t.Error("Expected Save() not to replace a remote state that could not be read")
+	}
+	if exported, _ := store.Export(); exported || remote.puts != 0 {
+		t.Error("Expected nothing to be exported")
+	}
+}
@@ -0,0 +1,281 @@
+package state
+
+import "time"
+
+// DefaultHygieneRetention is how long hygiene samples are kept in the state
+const DefaultHygieneRetention = 90 * 24 * time.Hour
+
+// DefaultDriftRetention is how long detected edits of managed silences are kept in the state
+const DefaultDriftRetention = 90 * 24 * time.Hour
+
+// State is the data silence-manager carries between synchronization runs
+type State struct {
+	// Hygiene holds one sample per synchronization run, oldest first
+	Hygiene []HygieneSample `json:"hygiene,omitempty"`
+	// Maintenance maps calendar event keys, the UID and for occurrences of recurring
+	// events the RECURRENCE-ID, to the silences and tickets created for them
+	Maintenance map[string]MaintenanceRecord `json:"maintenance,omitempty"`
+	// GitOps maps the names of declared silence definitions to their silences and tickets
+	GitOps map[string]GitOpsRecord `json:"gitops,omitempty"`
+	// Changes maps change ticket keys to the silences of their planned windows
+	Changes map[string]ChangeRecord `json:"changes,omitempty"`
+	// Watches maps ticket keys to their last known silence, for closing tickets whose
+	// alerts stay quiet after the silence has expired
+	Watches map[string]TicketWatch `json:"watches,omitempty"`
+	// Resolved maps the keys of resolved tickets to the silence deleted with them, for
+	// recreating it if the ticket is reopened
+	Resolved map[string]ResolvedSilence `json:"resolved,omitempty"`
+	// Idle maps the IDs of managed silences matching no alerts to when they were first
+	// found idle, for letting silences lapse that stay idle
+	Idle map[string]IdleSilence `json:"idle,omitempty"`
+	// SLAs maps the keys of tickets with silences of a severity that has an SLA to how
+	// long their alerts have been silenced
+	SLAs map[string]SLARecord `json:"slas,omitempty"`
+	// Failures tracks failing runs for the failure alert policy
+	Failures FailureRecord `json:"failures,omitempty"`
+	// Journal holds the undo journal of recent runs, oldest first, for rolling them back
+	Journal []JournalRun `json:"journal,omitempty"`
+	// Fingerprints maps the IDs of managed silences to their content as silence-manager
+	// last saw or left it, for detecting edits made outside silence-manager
+	Fingerprints map[string]SilenceFingerprint `json:"fingerprints,omitempty"`
+	// Drift holds the detected edits of managed silences made outside silence-manager,
+	// oldest first
+	Drift []DriftEvent `json:"drift,omitempty"`
+	// RemovedRules maps the IDs of silences of removed alerting rules to when their
+	// ticket was asked to clean them up, so that it is asked once
+	RemovedRules map[string]time.Time `json:"removedRules,omitempty"`
+	// Lifecycle maps the keys of tickets labelled silence-active or silence-expiring-soon
+	// to their label, for labelling them silence-expired once their silence is gone
+	Lifecycle map[string]string `json:"lifecycle,omitempty"`
+}
+
+// Journal actions
+const (
+	JournalDelete = "delete" // The silence was deleted
+	JournalExtend = "extend" // The end of the silence was moved
+	JournalUpdate = "update" // The silence was updated, e.g. its matchers or comment
+)
+
+// JournalRun is the undo journal of a synchronization run: the silences it deleted or
+// changed, with their state before the action
+type JournalRun struct {
+	ID         string         `json:"id"`
+	StartedAt  time.Time      `json:"startedAt"`
+	Entries    []JournalEntry `json:"entries"`
+	RolledBack time.Time      `json:"rolledBack,omitempty"` // When the run was rolled back
+}
+
+// JournalEntry is a silence deleted or changed by a run, as it was before the action
+type JournalEntry struct {
+	Action    string    `json:"action"` // JournalDelete, JournalExtend or JournalUpdate
+	SilenceID string    `json:"silenceID"`
+	Matchers  []string  `json:"matchers"` // e.g. "instance=~db-.*"
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+	TicketRef string    `json:"ticketRef,omitempty"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	// NewEndsAt is the end of the silence after an extension or update, for detecting
+	// silences changed since
+	NewEndsAt time.Time `json:"newEndsAt,omitempty"`
+	// NewSilenceID is the silence that replaced SilenceID in an update, as Alertmanager
+	// gives a silence a new ID when its matchers change
+	NewSilenceID string `json:"newSilenceID,omitempty"`
+	// RolledBack is when the action was rolled back, so that a rollback stopped by an
+	// error resumes after it
+	RolledBack time.Time `json:"rolledBack,omitempty"`
+}
+
+// SilenceFingerprint is a hash of the matchers and end time of a managed silence
+type SilenceFingerprint struct {
+	TicketRef string    `json:"ticketRef"`
+	Hash      string    `json:"hash"`
+	Matchers  string    `json:"matchers"` // e.g. "instance=~db-.* job=node", for describing edits
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+// DriftEvent is an edit of a managed silence made outside silence-manager
+type DriftEvent struct {
+	Detected  time.Time `json:"detected"`
+	SilenceID string    `json:"silenceID"`
+	// ReplacedID is the silence the edited one replaced, when Alertmanager created a new
+	// silence for the edit, as it does when matchers change
+	ReplacedID string `json:"replacedID,omitempty"`
+	TicketRef  string `json:"ticketRef"`
+	CreatedBy  string `json:"createdBy"` // Alertmanager records the editor as the creator
+	Changes    string `json:"changes"`
+}
+
+// FailureRecord counts the runs failing in a row and whether an alert was raised for them
+type FailureRecord struct {
+	Consecutive int       `json:"consecutive,omitempty"`
+	Since       time.Time `json:"since,omitempty"` // When the first of the failing runs started
+	Alerting    bool      `json:"alerting,omitempty"`
+}
+
+// MaintenanceRecord links a maintenance calendar event to its silence and ticket
+type MaintenanceRecord struct {
+	SilenceID string    `json:"silenceID"`
+	TicketKey string    `json:"ticketKey"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+// ChangeRecord links a change ticket to the silence of its planned window
+type ChangeRecord struct {
+	SilenceID string    `json:"silenceID"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Matchers  string    `json:"matchers"` // As read from the ticket, for detecting edits
+	// Scheduled is set when the change has no planned end, so that its silence is
+	// managed like any other once it has started
+	Scheduled bool `json:"scheduled,omitempty"`
+}
+
+// GitOpsRecord links a declared silence definition to its silence and ticket
+type GitOpsRecord struct {
+	SilenceID string   `json:"silenceID"`
+	TicketKey string   `json:"ticketKey"`
+	Matchers  []string `json:"matchers"` // As declared, for detecting changed definitions
+}
+
+// TicketWatch remembers the last silence of a ticket and when its alerts last fired
+type TicketWatch struct {
+	SilenceID string    `json:"silenceID"`
+	Matchers  []string  `json:"matchers"` // e.g. "instance=~db-.*"
+	EndsAt    time.Time `json:"endsAt"`
+	LastFired time.Time `json:"lastFired,omitempty"`
+}
+
+// ResolvedSilence is the definition of a silence deleted because its ticket was resolved
+type ResolvedSilence struct {
+	SilenceID  string    `json:"silenceID"`
+	Matchers   []string  `json:"matchers"` // e.g. "instance=~db-.*"
+	CreatedBy  string    `json:"createdBy"`
+	Comment    string    `json:"comment"`
+	ResolvedAt time.Time `json:"resolvedAt"`
+}
+
+// IdleSilence tracks a managed silence that matched no alerts in consecutive runs
+type IdleSilence struct {
+	TicketKey string    `json:"ticketKey"`
+	Since     time.Time `json:"since"`
+	Notified  bool      `json:"notified,omitempty"` // The ticket was told the silence will lapse
+}
+
+// SLARecord tracks how long the alerts of a ticket have been silenced, for escalating
+// tickets whose silences outlast the SLA of their severity
+type SLARecord struct {
+	Severity  string    `json:"severity"`
+	Since     time.Time `json:"since"`               // Start of the earliest silence of the ticket
+	Escalated time.Time `json:"escalated,omitempty"` // When the ticket was escalated
+}
+
+// HygieneSample captures silence hygiene indicators observed during a single run
+type HygieneSample struct {
+	Timestamp          time.Time     `json:"timestamp"`
+	TotalSilences      int           `json:"totalSilences"`
+	OpenTicketSilences int           `json:"openTicketSilences"` // Silences whose ticket is open
+	OrphanSilences     int           `json:"orphanSilences"`     // Silences without a ticket reference
+	MedianAge          time.Duration `json:"medianAge"`
+	IdleSilences       int           `json:"idleSilences,omitempty"` // Managed silences matching no alerts, when checked
+
+	// Actions taken during the run
+	SilencesExtended int `json:"silencesExtended,omitempty"`
+	SilencesDeleted  int `json:"silencesDeleted,omitempty"`
+	TicketsReopened  int `json:"ticketsReopened,omitempty"`
+}
+
+// AddJournalRun appends the journal of a run and drops the journals of runs started more
+// than retention before it
+func (s *State) AddJournalRun(run JournalRun, retention time.Duration) {
+	s.Journal = append(s.Journal, run)
+
+	cutoff := run.StartedAt.Add(-retention)
+	kept := s.Journal[:0]
+	for _, r := range s.Journal {
+		if !r.StartedAt.Before(cutoff) {
+			kept = append(kept, r)
+		}
+	}
+	s.Journal = kept
+}
+
+// FindJournalRun returns the journal of the run with the given ID
+func (s *State) FindJournalRun(id string) (*JournalRun, bool) {
+	for i := range s.Journal {
+		if s.Journal[i].ID == id {
+			return &s.Journal[i], true
+		}
+	}
+	return nil, false
+}
+
+// OpenTicketRatio returns the fraction of silences backed by an open ticket
+func (h HygieneSample) OpenTicketRatio() float64 {
+	if h.TotalSilences == 0 {
+		return 1
+	}
+	return float64(h.OpenTicketSilences) / float64(h.TotalSilences)
+}
+
+// OrphanRatio returns the fraction of silences without a ticket reference
+func (h HygieneSample) OrphanRatio() float64 {
+	if h.TotalSilences == 0 {
+		return 0
+	}
+	return float64(h.OrphanSilences) / float64(h.TotalSilences)
+}
+
+// Store is the interface that all state store implementations must satisfy
+type Store interface {
+	// Load returns the persisted state, or an empty state if nothing has been saved yet
+	Load() (*State, error)
+
+	// Save persists the state
+	Save(state *State) error
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{
+		Hygiene:      make([]HygieneSample, 0),
+		Maintenance:  make(map[string]MaintenanceRecord),
+		GitOps:       make(map[string]GitOpsRecord),
+		Changes:      make(map[string]ChangeRecord),
+		Watches:      make(map[string]TicketWatch),
+		Resolved:     make(map[string]ResolvedSilence),
+		Idle:         make(map[string]IdleSilence),
+		SLAs:         make(map[string]SLARecord),
+		Fingerprints: make(map[string]SilenceFingerprint),
+	}
+}
+
+// AddDriftEvent appends a detected edit and drops edits detected more than retention
+// before it
+func (s *State) AddDriftEvent(event DriftEvent, retention time.Duration) {
+	s.Drift = append(s.Drift, event)
+
+	cutoff := event.Detected.Add(-retention)
+	kept := s.Drift[:0]
+	for _, d := range s.Drift {
+		if !d.Detected.Before(cutoff) {
+			kept = append(kept, d)
+		}
+	}
+	s.Drift = kept
+}
+
+// AddHygieneSample appends a sample and drops samples older than the retention period
+func (s *State) AddHygieneSample(sample HygieneSample, retention time.Duration) {
+	s.Hygiene = append(s.Hygiene, sample)
+
+	cutoff := sample.Timestamp.Add(-retention)
+	kept := s.Hygiene[:0]
+	for _, h := range s.Hygiene {
+		if !h.Timestamp.Before(cutoff) {
+			kept = append(kept, h)
+		}
+	}
+	s.Hygiene = kept
+}
@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// closeQuietTickets closes tickets whose silence has expired and whose alerts have not
+// fired for AutoCloseAfter. The silences still managed at the end of this run are
+// remembered in the state store, so their tickets can be followed up once they are gone.
+func (s *Synchronizer) closeQuietTickets(silences []*alertmanager.Silence, result *SyncResult) error {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.Watches == nil {
+		st.Watches = make(map[string]state.TicketWatch)
+	}
+
+	byID := make(map[string]*alertmanager.Silence, len(silences))
+	for _, silence := range silences {
+		byID[silence.ID] = silence
+	}
+	silenced := make(map[string]bool, len(result.Managed))
+	for _, m := range result.Managed {
+		silence, ok := byID[m.SilenceID]
+		if !ok {
+			continue
+		}
+		silenced[m.TicketRef] = true
+		watch := st.Watches[m.TicketRef]
+		watch.SilenceID = silence.ID
+		watch.EndsAt = silence.EndsAt
+		watch.Matchers = watch.Matchers[:0]
+		for _, matcher := range silence.Matchers {
+			watch.Matchers = append(watch.Matchers, formatMatchers([]alertmanager.Matcher{matcher}))
+		}
+		st.Watches[m.TicketRef] = watch
+	}
+
+	now := s.now()
+	for key, watch := range st.Watches {
+		if silenced[key] {
+			continue
+		}
+		if s.closeIfQuiet(key, &watch, now, result) {
+			delete(st.Watches, key)
+		} else {
+			st.Watches[key] = watch
+		}
+	}
+
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// closeIfQuiet closes the ticket if its alerts have been quiet for AutoCloseAfter since
+// the silence ended. It records when the alerts fire and reports whether the ticket no
+// longer needs to be watched.
+func (s *Synchronizer) closeIfQuiet(key string, watch *state.TicketWatch, now time.Time, result *SyncResult) bool {
+	matchers := make([]alertmanager.Matcher, 0, len(watch.Matchers))
+	for _, value := range watch.Matchers {
+		m, err := parseMatcher(value)
+		if err != nil {
+			log.Printf("Warning: dropping watch on ticket %s: %v", key, err)
+			return true
+		}
+		matchers = append(matchers, m)
+	}
+
+	alerts, err := s.alertManager.GetAlerts(matchers)
+	if err != nil {
+		log.Printf("Warning: failed to get alerts for ticket %s: %v", key, err)
+		return false
+	}
+	if len(alerts) > 0 {
+		watch.LastFired = now
+		return false
+	}
+
+	quietSince := watch.EndsAt
+	if watch.LastFired.After(quietSince) {
+		quietSince = watch.LastFired
+	}
+	if now.Sub(quietSince) < s.config.AutoCloseAfter {
+		return false
+	}
+
+	tkt, err := s.ticketSystem.GetTicket(key)
+	if err != nil {
+		log.Printf("Warning: failed to get ticket %s: %v", key, err)
+		return false
+	}
+	if s.ticketSystem.IsClosed(tkt) {
+		return true
+	}
+
+	log.Printf("Closing ticket %s: silence %s expired and its alerts have been quiet since %v", key, watch.SilenceID, quietSince)
+	msg := fmt.Sprintf("Closing automatically: silence %s ended at %s and its alerts (%s) have not fired since %s. The ticket is reopened if the alerts fire again.",
+		watch.SilenceID, watch.EndsAt.Format(time.RFC3339), formatMatchers(matchers), quietSince.Format(time.RFC3339))
+	if err := s.ticketSystem.CloseTicket(key, msg); err != nil {
+		log.Printf("Error closing ticket %s: %v", key, err)
+		result.Errors = append(result.Errors, fmt.Errorf("close ticket %s: %w", key, err))
+		return false
+	}
+	result.TicketsClosed++
+	return true
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// autoCloseFixture is an open ticket whose silence auto-closing watches
+func autoCloseFixture() fixture {
+	return fixture{
+		silences: []*alertmanager.Silence{{
+			ID:        "silence-1",
+			EndsAt:    time.Now().Add(72 * time.Hour),
+			TicketRef: "PROJ-1",
+			Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+		}},
+		tickets: []*ticket.Ticket{{Key: "PROJ-1", Status: ticket.StatusOpen}},
+		configure: func(cfg *SyncConfig) {
+			cfg.CheckAlerts = false
+			cfg.AutoCloseAfter = 3 * 24 * time.Hour
+		},
+	}
+}
+
+// expireWatch moves the remembered end of the ticket's silence into the past
+func expireWatch(t *testing.T, sync *Synchronizer, key string, endedAgo time.Duration) {
+	t.Helper()
+	st, _ := sync.stateStore.Load()
+	watch, ok := st.Watches[key]
+	if !ok {
+		t.Fatalf("Expected ticket %s to be watched", key)
+	}
+	watch.EndsAt = time.Now().Add(-endedAgo)
+	st.Watches[key] = watch
+}
+
+func TestAutoClose_QuietTicketClosed(t *testing.T) {
+	am, ts, sync := newFixture(autoCloseFixture())
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	st, _ := sync.stateStore.Load()
+	if watch := st.Watches["PROJ-1"]; watch.SilenceID != "silence-1" || len(watch.Matchers) != 1 || watch.Matchers[0] != "alertname=DiskFull" {
+		t.Fatalf("Unexpected watch: %+v", watch)
+	}
+
+	// The silence expires; the ticket stays open until the alerts have been quiet long enough
+	delete(am.silences, "silence-1")
+	expireWatch(t, sync, "PROJ-1", 24*time.Hour)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsClosed != 0 {
+		t.Fatalf("Expected ticket to stay open, got %d closed", result.TicketsClosed)
+	}
+
+	expireWatch(t, sync, "PROJ-1", 4*24*time.Hour)
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsClosed != 1 || len(ts.closedKeys) != 1 || ts.closedKeys[0] != "PROJ-1" {
+		t.Fatalf("Expected PROJ-1 to be closed, got %d closed (%v)", result.TicketsClosed, ts.closedKeys)
+	}
+	if len(ts.comments["PROJ-1"]) != 1 {
+		t.Errorf("Expected a summary comment, got %v", ts.comments["PROJ-1"])
+	}
+	if st, _ := sync.stateStore.Load(); len(st.Watches) != 0 {
+		t.Errorf("Expected watch to be dropped, got %v", st.Watches)
+	}
+}
+
+func TestAutoClose_FiringAlertKeepsTicketOpen(t *testing.T) {
+	am, ts, sync := newFixture(autoCloseFixture())
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	delete(am.silences, "silence-1")
+	expireWatch(t, sync, "PROJ-1", 4*24*time.Hour)
+	am.alerts = []*alertmanager.Alert{{Labels: map[string]string{"alertname": "DiskFull"}}}
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsClosed != 0 || len(ts.closedKeys) != 0 {
+		t.Fatalf("Expected ticket with firing alerts to stay open")
+	}
+
+	st, _ := sync.stateStore.Load()
+	if st.Watches["PROJ-1"].LastFired.IsZero() {
+		t.Error("Expected the firing alert to be recorded")
+	}
+
+	// Once the alert stops, the quiet period starts from when it last fired
+	am.alerts = nil
+	if result, _ := sync.Sync(); result.TicketsClosed != 0 {
+		t.Error("Expected ticket to stay open right after the alert stopped")
+	}
+}
+
+func TestAutoClose_ResolvedTicketForgotten(t *testing.T) {
+	am, ts, sync := newFixture(autoCloseFixture())
+	sync.SetStateStore(state.NewMemoryStore())
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	delete(am.silences, "silence-1")
+	expireWatch(t, sync, "PROJ-1", 4*24*time.Hour)
+	ts.tickets["PROJ-1"].Status = ticket.StatusResolved
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsClosed != 0 || len(ts.closedKeys) != 0 {
+		t.Error("Expected resolved ticket not to be closed again")
+	}
+	if st, _ := sync.stateStore.Load(); len(st.Watches) != 0 {
+		t.Errorf("Expected watch to be dropped, got %v", st.Watches)
+	}
+}
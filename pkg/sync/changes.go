@@ -0,0 +1,299 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// changeTimeLayouts are the accepted formats of the planned start and end fields. Jira
+// date-time fields use the first; date fields the last, covering the whole day.
+var changeTimeLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02",
+}
+
+// changeWindow is the planned window and scope of a change ticket. A scheduled window
+// has no planned end: it lasts the ticket's extension duration, and its silence is
+// handed over to the regular lifecycle once it has started.
+type changeWindow struct {
+	start, end time.Time
+	matchers   []alertmanager.Matcher
+	scheduled  bool
+}
+
+// parseChangeTime parses a planned start or end field. A date without time starts at
+// midnight UTC; as an end, it covers the whole day.
+func parseChangeTime(value string, end bool) (time.Time, error) {
+	for _, layout := range changeTimeLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if layout == "2006-01-02" && end {
+			t = t.Add(24 * time.Hour)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// changeWindowOf reads the planned window and the matchers of a change ticket. Without
+// ChangeEndField, or when the ticket has no planned end, the window is scheduled.
+func (s *Synchronizer) changeWindowOf(tkt *ticket.Ticket) (changeWindow, error) {
+	var w changeWindow
+	var err error
+	startValue := tkt.CustomFields[s.config.ChangeStartField]
+	if startValue == "" {
+		return w, fmt.Errorf("planned start is not set")
+	}
+	if w.start, err = parseChangeTime(startValue, false); err != nil {
+		return w, fmt.Errorf("planned start: %w", err)
+	}
+	if endValue := tkt.CustomFields[s.config.ChangeEndField]; s.config.ChangeEndField == "" || endValue == "" {
+		w.scheduled = true
+		w.end = w.start.Add(s.extensionDurationFor(tkt))
+		if s.config.BusinessHours != nil {
+			w.end = s.config.BusinessHours.Next(w.end)
+		}
+	} else if w.end, err = parseChangeTime(endValue, true); err != nil {
+		return w, fmt.Errorf("planned end: %w", err)
+	}
+	if !w.end.After(w.start) {
+		return w, fmt.Errorf("planned end %s is not after the start", w.end.Format(time.RFC3339))
+	}
+
+	// Matchers are separated by spaces, commas or line breaks
+	fields := strings.FieldsFunc(tkt.CustomFields[s.config.ChangeMatchersField], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return w, fmt.Errorf("no matchers in field %s", s.config.ChangeMatchersField)
+	}
+	if w.matchers, err = ParseMatchers(fields); err != nil {
+		return w, err
+	}
+	return w, nil
+}
+
+// syncChanges silences the planned windows of approved change tickets found by
+// ChangeQuery, linking each silence to its change ticket. Silences follow rescheduled
+// windows and edited matchers, and are removed when the change closes before its window
+// ends. Scheduled silences are handed over to the regular lifecycle once they have
+// started. It returns the IDs of the silences it manages so that the regular lifecycle
+// leaves them alone.
+func (s *Synchronizer) syncChanges(result *SyncResult) (map[string]bool, error) {
+	managed := make(map[string]bool)
+
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return managed, fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.Changes == nil {
+		st.Changes = make(map[string]state.ChangeRecord)
+	}
+
+	tickets, err := s.ticketSystem.SearchTickets(s.config.ChangeQuery)
+	if err != nil {
+		// Keep managing the known silences, but do not remove anything we cannot see
+		for _, rec := range st.Changes {
+			managed[rec.SilenceID] = true
+		}
+		return managed, fmt.Errorf("failed to search change tickets: %w", err)
+	}
+
+	now := s.now()
+	current := make(map[string]bool)
+	for _, tkt := range tickets {
+		if s.ticketSystem.IsResolved(tkt) || s.ticketSystem.IsClosed(tkt) {
+			continue
+		}
+		rec, known := st.Changes[tkt.Key]
+		if known && s.handedOver(rec) {
+			// The record is kept so that the silence is not scheduled again
+			current[tkt.Key] = true
+			continue
+		}
+		window, err := s.changeWindowOf(tkt)
+		if err != nil {
+			log.Printf("Error reading change ticket %s: %v", tkt.Key, err)
+			result.Errors = append(result.Errors, fmt.Errorf("change ticket %s: %w", tkt.Key, err))
+			if known {
+				current[tkt.Key] = true
+				managed[rec.SilenceID] = true
+			}
+			continue
+		}
+		if !window.end.After(now) {
+			continue
+		}
+
+		if !known && window.start.After(now.Add(s.config.MaintenanceLookahead)) {
+			continue
+		}
+		current[tkt.Key] = true
+		if known && rec.StartsAt.Equal(window.start) && rec.Scheduled == window.scheduled &&
+			(window.scheduled || rec.EndsAt.Equal(window.end)) && rec.Matchers == formatMatchers(window.matchers) {
+			managed[rec.SilenceID] = true
+			continue
+		}
+
+		updated, err := s.applyChangeWindow(tkt, window, rec, known)
+		if err != nil {
+			log.Printf("Error silencing change ticket %s: %v", tkt.Key, err)
+			result.Errors = append(result.Errors, fmt.Errorf("change ticket %s: %w", tkt.Key, err))
+			if known {
+				managed[rec.SilenceID] = true
+			} else {
+				delete(current, tkt.Key)
+			}
+			continue
+		}
+		st.Changes[tkt.Key] = updated
+		managed[updated.SilenceID] = true
+		if !known {
+			result.ChangeSilencesCreated++
+		}
+	}
+
+	// Changes that left the query are only removed once they are closed, so that a
+	// change moving from approved to in progress keeps its silence
+	for key, rec := range st.Changes {
+		if current[key] {
+			continue
+		}
+		if !rec.Scheduled && !rec.EndsAt.After(now) {
+			delete(st.Changes, key)
+			continue
+		}
+		tkt, err := s.ticketSystem.GetTicket(key)
+		if err != nil {
+			log.Printf("Error checking change ticket %s: %v", key, err)
+			result.Errors = append(result.Errors, fmt.Errorf("change ticket %s: %w", key, err))
+			managed[rec.SilenceID] = true
+			continue
+		}
+		if !s.ticketSystem.IsResolved(tkt) && !s.ticketSystem.IsClosed(tkt) {
+			if !s.handedOver(rec) {
+				managed[rec.SilenceID] = true
+			}
+			continue
+		}
+		if s.handedOver(rec) {
+			// The regular lifecycle deletes the silence of the resolved ticket
+			delete(st.Changes, key)
+			continue
+		}
+		removed, err := s.removeChangeSilence(key, rec)
+		if err != nil {
+			log.Printf("Error removing silence %s of change ticket %s: %v", rec.SilenceID, key, err)
+			result.Errors = append(result.Errors, fmt.Errorf("change ticket %s: %w", key, err))
+		}
+		if !removed {
+			managed[rec.SilenceID] = true
+			continue
+		}
+		delete(st.Changes, key)
+		result.ChangeSilencesRemoved++
+	}
+
+	if err := s.stateStore.Save(st); err != nil {
+		return managed, fmt.Errorf("failed to save state: %w", err)
+	}
+	return managed, nil
+}
+
+// handedOver reports whether the silence of a scheduled window has started, and is
+// extended and deleted like any other silence from then on
+func (s *Synchronizer) handedOver(rec state.ChangeRecord) bool {
+	return rec.Scheduled && !rec.StartsAt.After(s.now())
+}
+
+// applyChangeWindow creates the silence of a change window, replacing the previous
+// silence when the window or matchers changed
+func (s *Synchronizer) applyChangeWindow(tkt *ticket.Ticket, window changeWindow, rec state.ChangeRecord, known bool) (state.ChangeRecord, error) {
+	event := ActionEvent{Action: ActionCreate, TicketKey: tkt.Key, NewEndsAt: window.end}
+	if err := s.vetoAction(event); err != nil {
+		return rec, err
+	}
+	if known {
+		replaced := ActionEvent{Action: ActionDelete, SilenceID: rec.SilenceID, TicketKey: tkt.Key}
+		if err := s.vetoAction(replaced); err != nil {
+			return rec, err
+		}
+		err := s.alertManager.DeleteSilence(rec.SilenceID)
+		s.afterAction(replaced, err)
+		if err != nil && !errors.Is(err, alertmanager.ErrSilenceNotFound) {
+			return rec, fmt.Errorf("failed to delete silence %s: %w", rec.SilenceID, err)
+		}
+	}
+
+	silence := &alertmanager.Silence{
+		CreatedBy: "silence-manager",
+		Comment:   fmt.Sprintf("Change %s: %s", tkt.Key, tkt.Summary),
+		StartsAt:  window.start,
+		EndsAt:    window.end,
+		Matchers:  window.matchers,
+		TicketRef: tkt.Key,
+	}
+	silenceID, err := s.alertManager.CreateSilence(silence)
+	event.SilenceID = silenceID
+	s.afterAction(event, err)
+	if err != nil {
+		return rec, fmt.Errorf("failed to create silence: %w", err)
+	}
+	silence.ID = silenceID
+
+	verb := "created"
+	if known {
+		verb = "replaced"
+		log.Printf("Replaced silence %s of change ticket %s with %s", rec.SilenceID, tkt.Key, silenceID)
+	} else {
+		log.Printf("Created silence %s for change ticket %s", silenceID, tkt.Key)
+	}
+	s.linkSilence(tkt.Key, silenceID)
+	msg := fmt.Sprintf("Silence %s %s for the planned change window %s - %s.", silenceID, verb,
+		window.start.Format(time.RFC3339), window.end.Format(time.RFC3339))
+	if window.scheduled {
+		msg = fmt.Sprintf("Silence %s %s, scheduled to start at %s. Once active, it is extended while this ticket is open and deleted when it is resolved.",
+			silenceID, verb, window.start.Format(time.RFC3339))
+	}
+	if err := s.ticketSystem.AddComment(tkt.Key, msg+silenceDefinition(silence)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+
+	return state.ChangeRecord{
+		SilenceID: silenceID,
+		StartsAt:  window.start,
+		EndsAt:    window.end,
+		Matchers:  formatMatchers(window.matchers),
+		Scheduled: window.scheduled,
+	}, nil
+}
+
+// removeChangeSilence deletes the silence of a change closed before its window ended. It
+// reports false when the deletion was vetoed, leaving the silence to a later run.
+func (s *Synchronizer) removeChangeSilence(key string, rec state.ChangeRecord) (bool, error) {
+	deleted, err := s.deleteManagedSilence(rec.SilenceID, key)
+	if err != nil && !errors.Is(err, alertmanager.ErrSilenceNotFound) {
+		return false, fmt.Errorf("failed to delete silence: %w", err)
+	}
+	if !deleted && err == nil {
+		return false, nil
+	}
+
+	log.Printf("Removed silence %s of closed change ticket %s", rec.SilenceID, key)
+	msg := fmt.Sprintf("Change closed; silence %s has been deleted.", rec.SilenceID)
+	if err := s.ticketSystem.AddComment(key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,244 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// changeTicketSystem returns the change tickets for any search, whatever the query
+type changeTicketSystem struct {
+	*mockTicketSystem
+	changes []*ticket.Ticket
+}
+
+func (m *changeTicketSystem) SearchTickets(query string) ([]*ticket.Ticket, error) {
+	m.searchQueries = append(m.searchQueries, query)
+	if m.searchErr != nil {
+		return nil, m.searchErr
+	}
+	return m.changes, nil
+}
+
+func changeConfig() SyncConfig {
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ChangeQuery = "project = CHG AND status = Approved"
+	cfg.ChangeStartField = "customfield_start"
+	cfg.ChangeEndField = "customfield_end"
+	cfg.ChangeMatchersField = "customfield_matchers"
+	return cfg
+}
+
+func changeTicket(key string, start, end time.Time, matchers string) *ticket.Ticket {
+	return &ticket.Ticket{
+		Key:     key,
+		Summary: "Upgrade checkout database",
+		Status:  ticket.StatusOpen,
+		CustomFields: map[string]string{
+			"customfield_start":    start.Format("2006-01-02T15:04:05.000-0700"),
+			"customfield_end":      end.Format("2006-01-02T15:04:05.000-0700"),
+			"customfield_matchers": matchers,
+		},
+	}
+}
+
+func TestSync_ChangeWindows(t *testing.T) {
+	am := newMockAlertManager()
+	ts := &changeTicketSystem{mockTicketSystem: newMockTicketSystem()}
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	end := start.Add(2 * time.Hour)
+	chg := changeTicket("CHG-1", start, end, "service=checkout, env=prod")
+	ts.tickets[chg.Key] = chg
+	ts.changes = []*ticket.Ticket{chg}
+
+	sync := NewSynchronizer(am, ts, changeConfig())
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.ChangeSilencesCreated != 1 || len(am.silences) != 1 {
+		t.Fatalf("Expected 1 change silence, created %d with %d silences", result.ChangeSilencesCreated, len(am.silences))
+	}
+	var silenceID string
+	for id, silence := range am.silences {
+		silenceID = id
+		if silence.TicketRef != "CHG-1" || !silence.StartsAt.Equal(start) || !silence.EndsAt.Equal(end) {
+			t.Errorf("Expected a silence of CHG-1 covering the planned window, got %+v", silence)
+		}
+		if got := formatMatchers(silence.Matchers); got != "service=checkout env=prod" {
+			t.Errorf("Expected the matchers of the ticket, got %s", got)
+		}
+	}
+	if len(ts.comments["CHG-1"]) != 1 || !strings.Contains(ts.comments["CHG-1"][0], silenceID) {
+		t.Errorf("Expected a comment naming the silence, got %v", ts.comments["CHG-1"])
+	}
+
+	// An unchanged change is left alone, and its silence is never extended
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.ChangeSilencesCreated != 0 || len(am.deletedIDs) != 0 || len(am.extendedIDs) != 0 {
+		t.Errorf("Expected no changes, got %+v", result)
+	}
+
+	// A rescheduled change replaces its silence
+	end = end.Add(time.Hour)
+	chg.CustomFields["customfield_end"] = end.Format(time.RFC3339)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(am.deletedIDs) != 1 || am.deletedIDs[0] != silenceID || len(am.silences) != 1 {
+		t.Fatalf("Expected silence %s to be replaced, deleted %v", silenceID, am.deletedIDs)
+	}
+	for id, silence := range am.silences {
+		silenceID = id
+		if !silence.EndsAt.Equal(end) {
+			t.Errorf("Expected the silence to end at the rescheduled end %v, got %v", end, silence.EndsAt)
+		}
+	}
+
+	// A change leaving the query while in progress keeps its silence
+	ts.changes = nil
+	chg.Status = ticket.StatusInProgress
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if am.silences[silenceID] == nil {
+		t.Fatal("Expected the silence of the change in progress to be kept")
+	}
+
+	// Closing the change removes its silence
+	chg.Status = ticket.StatusClosed
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.ChangeSilencesRemoved != 1 || am.silences[silenceID] != nil {
+		t.Errorf("Expected the silence of the closed change to be removed, got %+v", result)
+	}
+	if st, _ := sync.stateStore.Load(); len(st.Changes) != 0 {
+		t.Errorf("Expected the change record to be dropped, got %v", st.Changes)
+	}
+}
+
+func TestSync_ChangeWindowsSkipped(t *testing.T) {
+	am := newMockAlertManager()
+	ts := &changeTicketSystem{mockTicketSystem: newMockTicketSystem()}
+	now := time.Now()
+	later := changeTicket("CHG-1", now.Add(72*time.Hour), now.Add(74*time.Hour), "service=checkout")
+	past := changeTicket("CHG-2", now.Add(-3*time.Hour), now.Add(-time.Hour), "service=checkout")
+	invalid := changeTicket("CHG-3", now, now.Add(time.Hour), "")
+	ts.changes = []*ticket.Ticket{later, past, invalid}
+
+	sync := NewSynchronizer(am, ts, changeConfig())
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(am.silences) != 0 {
+		t.Errorf("Expected no silences beyond the lookahead, past or without matchers, got %d", len(am.silences))
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "CHG-3") {
+		t.Errorf("Expected an error for the change without matchers, got %v", result.Errors)
+	}
+}
+
+func TestParseChangeTime(t *testing.T) {
+	tests := []struct {
+		value string
+		end   bool
+		want  time.Time
+	}{
+		{"2026-03-01T22:00:00.000+0100", false, time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC)},
+		{"2026-03-01T21:00:00Z", false, time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC)},
+		{"2026-03-01", false, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-03-01", true, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseChangeTime(tt.value, tt.end)
+		if err != nil {
+			t.Errorf("parseChangeTime(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseChangeTime(%q, %v) = %v, want %v", tt.value, tt.end, got, tt.want)
+		}
+	}
+	if _, err := parseChangeTime("next tuesday", false); err == nil {
+		t.Error("Expected error for an invalid date")
+	}
+}
+
+func TestSync_ScheduledChangeSilences(t *testing.T) {
+	am := newMockAlertManager()
+	ts := &changeTicketSystem{mockTicketSystem: newMockTicketSystem()}
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	chg := changeTicket("CHG-1", start, time.Time{}, "service=checkout")
+	delete(chg.CustomFields, "customfield_end")
+	ts.tickets[chg.Key] = chg
+	ts.changes = []*ticket.Ticket{chg}
+
+	cfg := changeConfig()
+	cfg.ChangeEndField = ""
+	sync := NewSynchronizer(am, ts, cfg)
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.ChangeSilencesCreated != 1 || len(am.silences) != 1 {
+		t.Fatalf("Expected 1 scheduled silence, created %d with %d silences", result.ChangeSilencesCreated, len(am.silences))
+	}
+	var silenceID string
+	for id, silence := range am.silences {
+		silenceID = id
+		if !silence.StartsAt.Equal(start) || !silence.EndsAt.Equal(start.Add(cfg.ExtensionDuration)) {
+			t.Errorf("Expected the silence to start at the planned start for the extension duration, got %v - %v", silence.StartsAt, silence.EndsAt)
+		}
+	}
+	if len(ts.comments["CHG-1"]) != 1 || !strings.Contains(ts.comments["CHG-1"][0], "scheduled to start") {
+		t.Errorf("Expected a comment announcing the scheduled silence, got %v", ts.comments["CHG-1"])
+	}
+
+	// Once started, the silence is extended like any other and never scheduled again
+	started := start.Add(-2 * time.Hour)
+	am.silences[silenceID].StartsAt = started
+	am.silences[silenceID].EndsAt = time.Now().Add(time.Hour)
+	st, _ := sync.stateStore.Load()
+	rec := st.Changes["CHG-1"]
+	rec.StartsAt = started
+	st.Changes["CHG-1"] = rec
+	if err := sync.stateStore.Save(st); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	chg.CustomFields["customfield_start"] = started.Format(time.RFC3339)
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.ChangeSilencesCreated != 0 || len(am.silences) != 1 {
+		t.Errorf("Expected the started silence not to be scheduled again, got %d silences", len(am.silences))
+	}
+	if result.SilencesExtended != 1 || len(am.extendedIDs) != 1 || am.extendedIDs[0] != silenceID {
+		t.Errorf("Expected the started silence to be extended, extended %v", am.extendedIDs)
+	}
+
+	// Resolving the ticket deletes the silence through the regular lifecycle
+	ts.changes = nil
+	chg.Status = ticket.StatusResolved
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesDeleted != 1 || result.ChangeSilencesRemoved != 0 || am.silences[silenceID] != nil {
+		t.Errorf("Expected the silence to be deleted for the resolved ticket, got %+v", result)
+	}
+	if st, _ := sync.stateStore.Load(); len(st.Changes) != 0 {
+		t.Errorf("Expected the change record to be dropped, got %v", st.Changes)
+	}
+}
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// minClockOffset is the smallest offset from the Alertmanager clock that is corrected,
+// as the server time is only known to the second
+const minClockOffset = time.Second
+
+// syncClock measures how far the local clock is off the clock of am's server when
+// UseServerTime is set. The offset stays zero when am cannot report its time.
+func (s *Synchronizer) syncClock(am alertmanager.AlertManager) {
+	s.clockOffset = 0
+	if !s.config.UseServerTime {
+		return
+	}
+	serverTime, err := alertmanager.ServerTime(am)
+	if errors.Is(err, alertmanager.ErrNoServerTime) {
+		return
+	}
+	if err != nil {
+		log.Printf("Warning: failed to get the Alertmanager server time, using the local clock: %v", err)
+		return
+	}
+	offset := time.Until(serverTime)
+	if offset.Abs() < minClockOffset {
+		return
+	}
+	direction := "behind"
+	if offset < 0 {
+		direction = "ahead of"
+	}
+	log.Printf("Local clock is %v %s the Alertmanager server, using the server time", offset.Abs().Round(time.Second), direction)
+	s.clockOffset = offset
+}
+
+// now returns the current time by the clock of the Alertmanager server, as far as known
+func (s *Synchronizer) now() time.Time {
+	return time.Now().Add(s.clockOffset)
+}
+
+// expiringSoon reports whether a silence ending at endsAt is due for extension. Silences
+// ending up to ClockTolerance after ExpiryThreshold are extended too.
+func (s *Synchronizer) expiringSoon(endsAt time.Time) bool {
+	return endsAt.Sub(s.now()) < s.config.ExpiryThreshold+s.config.ClockTolerance
+}
+
+// hasExpired reports whether a silence ending at endsAt has expired by more than
+// ClockTolerance
+func (s *Synchronizer) hasExpired(endsAt time.Time) bool {
+	return endsAt.Sub(s.now()) <= -s.config.ClockTolerance
+}
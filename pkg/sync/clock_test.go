@@ -0,0 +1,103 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// skewedAlertManager reports a server clock running offset ahead of the local clock
+type skewedAlertManager struct {
+	*mockAlertManager
+	offset time.Duration
+}
+
+func (s *skewedAlertManager) ServerTime() (time.Time, error) {
+	return time.Now().Add(s.offset), nil
+}
+
+func TestSync_UsesAlertmanagerServerTime(t *testing.T) {
+	am := &skewedAlertManager{mockAlertManager: newMockAlertManager(), offset: 2 * time.Hour}
+	ts := newMockTicketSystem()
+	now := time.Now()
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		TicketRef: "PROJ-1",
+		StartsAt:  now.Add(-time.Hour),
+		EndsAt:    now.Add(25 * time.Hour),
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.UseServerTime = false
+	result, err := New(am, ts, WithConfig(cfg)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesExtended != 0 {
+		t.Fatalf("Expected no extension by the local clock, got %d", result.SilencesExtended)
+	}
+
+	// By the server's clock the silence expires within the threshold
+	cfg.UseServerTime = true
+	result, err = New(am, ts, WithConfig(cfg)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesExtended != 1 {
+		t.Fatalf("Expected the silence to be extended by the server clock, got %d", result.SilencesExtended)
+	}
+	if want := now.Add(2*time.Hour + cfg.ExtensionDuration); am.silences["silence-1"].EndsAt.Before(want) {
+		t.Errorf("Expected the extension to count from the server time, got %v", am.silences["silence-1"].EndsAt)
+	}
+}
+
+func TestClockTolerance(t *testing.T) {
+	s := New(newMockAlertManager(), newMockTicketSystem())
+	s.config.ExpiryThreshold = 24 * time.Hour
+	s.config.ClockTolerance = time.Hour
+	now := time.Now()
+
+	if !s.expiringSoon(now.Add(24*time.Hour + 30*time.Minute)) {
+		t.Error("Expected a silence ending within the tolerance past the threshold to be expiring soon")
+	}
+	if s.expiringSoon(now.Add(26 * time.Hour)) {
+		t.Error("Expected a silence ending past the threshold and tolerance not to be expiring soon")
+	}
+	if s.hasExpired(now.Add(-30 * time.Minute)) {
+		t.Error("Expected a silence that ended within the tolerance not to count as expired")
+	}
+	if !s.hasExpired(now.Add(-2 * time.Hour)) {
+		t.Error("Expected a silence that ended before the tolerance to count as expired")
+	}
+}
+
+func TestServerTime_OnRequest(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.ExtensionHistory = true
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	s := New(am, ts, WithConfig(cfg))
+	// The last run found the local clock 2 hours behind the server
+	s.clockOffset = 2 * time.Hour
+
+	def := SilenceDefinition{
+		Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+		EndsAt:   time.Now().Add(time.Hour),
+		Ticket:   "PROJ-1",
+	}
+	if _, err := s.CreateSilence(def); err == nil || !strings.Contains(err.Error(), "already be expired") {
+		t.Errorf("Expected a silence ending before the server time to be refused, got %v", err)
+	}
+
+	history := extensionHistory{Extensions: 1, PreviousEnds: []time.Time{time.Now().Add(time.Hour).Truncate(time.Second)}}
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", EndsAt: time.Now().Add(24 * time.Hour), Comment: history.String()}
+	if _, err := s.RevertExtension("silence-1"); err == nil {
+		t.Error("Expected a revert to an end before the server time to be refused")
+	}
+}
@@ -0,0 +1,61 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// ErrSilenceExists is returned by CreateSilence when an active silence already has the
+// definition's matchers
+var ErrSilenceExists = errors.New("an active silence has the same matchers")
+
+// CreateSilence creates a ticket and a silence for the definition in one step and links
+// them, so the silence is managed from the start. An existing ticket is linked instead
+// when def.Ticket is set. Definitions whose matchers equal those of an active silence are
+// rejected, since that silence already covers the alerts.
+func (s *Synchronizer) CreateSilence(def SilenceDefinition) (*alertmanager.Silence, error) {
+	if len(def.Matchers) == 0 {
+		return nil, fmt.Errorf("at least one matcher is required")
+	}
+
+	existing, err := s.alertManager.ListSilences()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	key := matcherKey(def.Matchers)
+	for _, silence := range existing {
+		if matcherKey(silence.Matchers) == key {
+			return nil, fmt.Errorf("%w: silence %s already matches %s", ErrSilenceExists, silence.ID, formatMatchers(def.Matchers))
+		}
+	}
+
+	now := s.now()
+	startsAt, endsAt := s.importWindow(&def, now)
+	if !endsAt.After(now) {
+		return nil, fmt.Errorf("silence would already be expired at %v", endsAt.Format(time.RFC3339))
+	}
+
+	silence, _, err := s.createLinkedSilence(&def, startsAt, endsAt, "Created")
+	return silence, err
+}
+
+// ParseMatchers parses label matchers such as severity=warning or instance=~"web-.*"
+func ParseMatchers(values []string) ([]alertmanager.Matcher, error) {
+	matchers := make([]alertmanager.Matcher, 0, len(values))
+	for _, value := range values {
+		m, err := parseMatcher(value)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// ParseDuration parses a Go duration string, additionally accepting a "d" suffix for days
+func ParseDuration(value string) (time.Duration, error) {
+	return parseDirectiveDuration(value)
+}
@@ -0,0 +1,71 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+func TestCreateSilence(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+
+	matchers, err := ParseMatchers([]string{"alertname=DiskFull", `instance=~"db-.*"`})
+	if err != nil {
+		t.Fatalf("ParseMatchers() failed: %v", err)
+	}
+	silence, err := sync.CreateSilence(SilenceDefinition{
+		Matchers:  matchers,
+		Summary:   "Disk replacement",
+		Comment:   "Waiting for hardware",
+		CreatedBy: "alice",
+		Duration:  72 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("CreateSilence() failed: %v", err)
+	}
+
+	tkt, ok := ts.tickets[silence.TicketRef]
+	if !ok {
+		t.Fatalf("Expected silence to be linked to a created ticket, got %q", silence.TicketRef)
+	}
+	if tkt.Summary != "Disk replacement" || !strings.Contains(tkt.Description, "Waiting for hardware") {
+		t.Errorf("Unexpected ticket: %+v", tkt)
+	}
+	if silence.CreatedBy != "alice" || silence.EndsAt.Before(time.Now().Add(71*time.Hour)) {
+		t.Errorf("Unexpected silence: %+v", silence)
+	}
+	comments := ts.comments[silence.TicketRef]
+	if len(comments) != 1 || !strings.Contains(comments[0], "Silence "+silence.ID+" created") {
+		t.Errorf("Expected a comment announcing the silence, got %v", comments)
+	}
+
+	// The same matchers are already silenced
+	if _, err := sync.CreateSilence(SilenceDefinition{Matchers: matchers, Summary: "Again"}); err == nil {
+		t.Error("Expected error when an active silence has the same matchers")
+	}
+	if len(am.silences) != 1 || len(ts.tickets) != 1 {
+		t.Errorf("Expected nothing to be created, got %d silences and %d tickets", len(am.silences), len(ts.tickets))
+	}
+}
+
+func TestCreateSilence_Invalid(t *testing.T) {
+	sync := NewSynchronizer(newMockAlertManager(), newMockTicketSystem(), DefaultConfig())
+
+	if _, err := sync.CreateSilence(SilenceDefinition{Summary: "No matchers"}); err == nil {
+		t.Error("Expected error without matchers")
+	}
+	expired := SilenceDefinition{
+		Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "Old", IsEqual: true}},
+		EndsAt:   time.Now().Add(-time.Hour),
+	}
+	if _, err := sync.CreateSilence(expired); err == nil {
+		t.Error("Expected error for a silence ending in the past")
+	}
+	if _, err := ParseMatchers([]string{"not a matcher"}); err == nil {
+		t.Error("Expected error for an invalid matcher")
+	}
+}
@@ -0,0 +1,53 @@
+package sync
+
+import (
+	"log"
+	"strings"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// matchesPattern reports whether value equals pattern, or starts with its prefix when the
+// pattern ends in "*"
+func matchesPattern(value, pattern string) bool {
+	prefix, isPrefix := strings.CutSuffix(pattern, "*")
+	return value == pattern || (isPrefix && strings.HasPrefix(value, prefix))
+}
+
+// managesCreator reports whether silences created by createdBy are managed: it must match
+// one of ManagedCreators, if any are set, and none of IgnoredCreators
+func (s *Synchronizer) managesCreator(createdBy string) bool {
+	for _, pattern := range s.config.IgnoredCreators {
+		if matchesPattern(createdBy, pattern) {
+			return false
+		}
+	}
+	if len(s.config.ManagedCreators) == 0 {
+		return true
+	}
+	for _, pattern := range s.config.ManagedCreators {
+		if matchesPattern(createdBy, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// managedSilences drops the silences of creators that are not managed, so that a shared
+// Alertmanager's silences owned by other teams are never extended or deleted, and the
+// silences protected from management
+func (s *Synchronizer) managedSilences(silences []*alertmanager.Silence) []*alertmanager.Silence {
+	silences = s.unprotectedSilences(silences)
+	if len(s.config.ManagedCreators) == 0 && len(s.config.IgnoredCreators) == 0 {
+		return silences
+	}
+	managed := silences[:0:0]
+	for _, silence := range silences {
+		if s.managesCreator(silence.CreatedBy) {
+			managed = append(managed, silence)
+		} else {
+			log.Printf("Silence %s was created by %q, which is not managed, skipping", silence.ID, silence.CreatedBy)
+		}
+	}
+	return managed
+}
@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestSync_SkipsSilencesOfUnmanagedCreators(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	for id, createdBy := range map[string]string{
+		"silence-own":     "silence-manager",
+		"silence-webhook": "silence-manager (webhook)",
+		"silence-other":   "team-b-bot",
+		"silence-ignored": "silence-manager (legacy)",
+	} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			CreatedBy: createdBy,
+			TicketRef: "PROJ-" + id,
+			StartsAt:  time.Now().Add(-time.Hour),
+			EndsAt:    time.Now().Add(12 * time.Hour),
+		}
+		ts.tickets["PROJ-"+id] = &ticket.Ticket{Key: "PROJ-" + id, Status: ticket.StatusResolved}
+	}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ManagedCreators = []string{"silence-manager*"}
+	cfg.IgnoredCreators = []string{"silence-manager (legacy)"}
+	result, err := New(am, ts, WithConfig(cfg)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if result.SilencesDeleted != 2 {
+		t.Errorf("Expected the 2 managed silences to be deleted, got %d", result.SilencesDeleted)
+	}
+	for _, id := range []string{"silence-other", "silence-ignored"} {
+		if _, ok := am.silences[id]; !ok {
+			t.Errorf("Expected silence %s of an unmanaged creator to be kept", id)
+		}
+	}
+}
+
+func TestManagesCreator(t *testing.T) {
+	s := New(newMockAlertManager(), newMockTicketSystem())
+	if !s.managesCreator("anyone") {
+		t.Error("Expected all creators to be managed by default")
+	}
+
+	s.config.IgnoredCreators = []string{"bot-*"}
+	if s.managesCreator("bot-payments") || !s.managesCreator("alice") {
+		t.Error("Expected only ignored creators to be skipped")
+	}
+
+	s.config.ManagedCreators = []string{"alice", "bot-*"}
+	if !s.managesCreator("alice") || s.managesCreator("bob") || s.managesCreator("bot-payments") {
+		t.Error("Expected only managed creators that are not ignored to be managed")
+	}
+}
@@ -0,0 +1,47 @@
+package sync
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// silenceDefinitionJSON is the form in which silences are recorded on their ticket. It
+// follows the Alertmanager API, so it can be passed back to import-silences or amtool.
+type silenceDefinitionJSON struct {
+	ID        string       `json:"id"`
+	Matchers  []rawMatcher `json:"matchers"`
+	StartsAt  time.Time    `json:"startsAt"`
+	EndsAt    time.Time    `json:"endsAt"`
+	CreatedBy string       `json:"createdBy"`
+	Comment   string       `json:"comment"`
+	Ticket    string       `json:"ticket,omitempty"`
+}
+
+// silenceDefinition returns a JSON code block recording the silence, to be appended to
+// the ticket comment announcing it. Responders can rebuild or audit the silence from it
+// after it has expired or been deleted.
+func silenceDefinition(silence *alertmanager.Silence) string {
+	def := silenceDefinitionJSON{
+		ID:        silence.ID,
+		Matchers:  make([]rawMatcher, 0, len(silence.Matchers)),
+		StartsAt:  silence.StartsAt.UTC(),
+		EndsAt:    silence.EndsAt.UTC(),
+		CreatedBy: silence.CreatedBy,
+		Comment:   silence.Comment,
+		Ticket:    silence.TicketRef,
+	}
+	for _, m := range silence.Matchers {
+		isEqual := m.IsEqual
+		def.Matchers = append(def.Matchers, rawMatcher{Name: m.Name, Value: m.Value, IsRegex: m.IsRegex, IsEqual: &isEqual})
+	}
+
+	data, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		log.Printf("Warning: failed to encode silence %s: %v", silence.ID, err)
+		return ""
+	}
+	return "\n\n### Silence definition\n" + codeBlock("json", string(data))
+}
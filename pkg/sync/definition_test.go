@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestSilenceDefinition_RoundTrip(t *testing.T) {
+	silence := &alertmanager.Silence{
+		ID:        "silence-1",
+		CreatedBy: "silence-manager",
+		Comment:   "Automatically recreated for refired alert",
+		StartsAt:  time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		EndsAt:    time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC),
+		TicketRef: "PROJ-1",
+		Matchers: []alertmanager.Matcher{
+			{Name: "alertname", Value: "DiskFull", IsEqual: true},
+			{Name: "env", Value: "dev|test", IsRegex: true, IsEqual: false},
+		},
+	}
+
+	block := silenceDefinition(silence)
+	start := strings.Index(block, "```json\n")
+	end := strings.LastIndex(block, "\n```")
+	if start < 0 || end < start {
+		t.Fatalf("Expected a JSON code block, got %q", block)
+	}
+
+	defs, err := ParseSilenceDefinitions([]byte("[" + block[start+len("```json\n"):end] + "]"))
+	if err != nil {
+		t.Fatalf("ParseSilenceDefinitions() failed: %v", err)
+	}
+	if len(defs) != 1 {
+		t.Fatalf("Expected 1 definition, got %d", len(defs))
+	}
+	def := defs[0]
+	if matcherKey(def.Matchers) != matcherKey(silence.Matchers) {
+		t.Errorf("Expected matchers %v, got %v", silence.Matchers, def.Matchers)
+	}
+	if !def.StartsAt.Equal(silence.StartsAt) || !def.EndsAt.Equal(silence.EndsAt) {
+		t.Errorf("Expected window %v - %v, got %v - %v", silence.StartsAt, silence.EndsAt, def.StartsAt, def.EndsAt)
+	}
+	if def.CreatedBy != silence.CreatedBy || def.Comment != silence.Comment || def.Ticket != "PROJ-1" {
+		t.Errorf("Unexpected definition: %+v", def)
+	}
+}
+
+func TestCheckRefiredAlerts_AttachesDefinition(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "instance": "db-1", "ticket": "PROJ-1"}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	comments := ts.comments["PROJ-1"]
+	last := comments[len(comments)-1]
+	if !strings.Contains(last, "New silence created: silence-0") || !strings.Contains(last, "```json") {
+		t.Fatalf("Expected the new silence comment to include its definition, got %q", last)
+	}
+	if !strings.Contains(last, `"id": "silence-0"`) || !strings.Contains(last, `"value": "db-1"`) {
+		t.Errorf("Expected definition to record the silence ID and matchers, got %q", last)
+	}
+}
+
+func TestCheckRefiredAlerts_ReopenCommentFormatting(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "instance": "db-1", "ticket": "PROJ-1"}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+
+	cfg := DefaultConfig()
+	cfg.SilenceUIURL = "https://alertmanager.example.com/"
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	comments := ts.comments["PROJ-1"]
+	if len(comments) != 2 {
+		t.Fatalf("Expected reopen and new silence comments, got %v", comments)
+	}
+	table := "### Alert labels\n| Label | Value |\n| --- | --- |\n| alertname | DiskFull |\n| instance | db-1 |\n| ticket | PROJ-1 |"
+	if !strings.HasSuffix(comments[0], table) {
+		t.Errorf("Expected the reopen comment to end with the labels table, got %q", comments[0])
+	}
+	link := "[silence-0](https://alertmanager.example.com/#/silences/silence-0)"
+	if !strings.Contains(comments[1], "New silence created: "+link) || !strings.Contains(comments[1], "### Silence definition") {
+		t.Errorf("Expected the new silence comment to link the silence, got %q", comments[1])
+	}
+}
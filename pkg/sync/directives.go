@@ -0,0 +1,295 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// DirectiveAction identifies what a chat command directive asks the synchronizer to do
+type DirectiveAction string
+
+const (
+	// DirectiveExtend extends the linked silence by the given duration
+	DirectiveExtend DirectiveAction = "extend"
+	// DirectiveExpire expires the linked silence immediately
+	DirectiveExpire DirectiveAction = "expire"
+	// DirectiveMatchers replaces the matchers of the linked silence
+	DirectiveMatchers DirectiveAction = "matchers"
+)
+
+// directivePrefix is the command word that introduces a directive in a ticket comment
+const directivePrefix = "/silence"
+
+// directiveAckMarker is included in acknowledgement comments so directives are only applied once
+const directiveAckMarker = "Applied directive from comment"
+
+// Directive is a command parsed from a ticket comment, e.g. "/silence extend 72h"
+type Directive struct {
+	CommentID string
+	Action    DirectiveAction
+	Duration  time.Duration          // For DirectiveExtend
+	Matchers  []alertmanager.Matcher // For DirectiveMatchers
+}
+
+// matcherPattern matches label matchers of the form name=value, name!=value, name=~regex and name!~regex
+var matcherPattern = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)(=~|!~|!=|=)(.*)$`)
+
+// ParseDirectives extracts directives from a ticket comment.
+// Each line starting with "/silence" is parsed as a single directive.
+func ParseDirectives(comment *ticket.Comment) ([]*Directive, error) {
+	var directives []*Directive
+
+	for _, line := range strings.Split(comment.Body, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != directivePrefix {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("missing action in directive %q", strings.TrimSpace(line))
+		}
+
+		directive := &Directive{
+			CommentID: comment.ID,
+			Action:    DirectiveAction(strings.ToLower(fields[1])),
+		}
+		args := fields[2:]
+
+		switch directive.Action {
+		case DirectiveExtend:
+			if len(args) != 1 {
+				return nil, fmt.Errorf("extend directive requires exactly one duration argument")
+			}
+			d, err := parseDirectiveDuration(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid extend duration %q: %w", args[0], err)
+			}
+			directive.Duration = d
+		case DirectiveExpire:
+			if len(args) != 0 {
+				return nil, fmt.Errorf("expire directive takes no arguments")
+			}
+		case DirectiveMatchers:
+			if len(args) == 0 {
+				return nil, fmt.Errorf("matchers directive requires at least one matcher")
+			}
+			for _, arg := range args {
+				m, err := parseMatcher(arg)
+				if err != nil {
+					return nil, err
+				}
+				directive.Matchers = append(directive.Matchers, m)
+			}
+		default:
+			return nil, fmt.Errorf("unknown directive action %q", fields[1])
+		}
+
+		directives = append(directives, directive)
+	}
+
+	return directives, nil
+}
+
+// parseDirectiveDuration parses a Go duration string, additionally accepting a "d" suffix for days
+func parseDirectiveDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		d, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return d, nil
+}
+
+// parseMatcher parses a single label matcher such as severity=warning or instance=~"web-.*"
+func parseMatcher(value string) (alertmanager.Matcher, error) {
+	parts := matcherPattern.FindStringSubmatch(value)
+	if parts == nil {
+		return alertmanager.Matcher{}, fmt.Errorf("invalid matcher %q", value)
+	}
+
+	matcher := alertmanager.Matcher{
+		Name:  parts[1],
+		Value: strings.Trim(parts[3], `"`),
+	}
+	switch parts[2] {
+	case "=":
+		matcher.IsEqual = true
+	case "!=":
+		matcher.IsEqual = false
+	case "=~":
+		matcher.IsEqual = true
+		matcher.IsRegex = true
+	case "!~":
+		matcher.IsEqual = false
+		matcher.IsRegex = true
+	}
+
+	if matcher.IsRegex {
+		if _, err := regexp.Compile(matcher.Value); err != nil {
+			return alertmanager.Matcher{}, fmt.Errorf("invalid regex in matcher %q: %w", value, err)
+		}
+	}
+
+	return matcher, nil
+}
+
+// currentUserGetter is implemented by ticket systems that report the user they comment as
+type currentUserGetter interface {
+	CurrentUser() (string, error)
+}
+
+// pendingDirectives returns directives from comments that have not yet been acknowledged.
+// Only acknowledgements authored by self count, so that a user cannot suppress a directive
+// by quoting the marker; an empty self accepts any author.
+// Comments that fail to parse are reported through the returned errors so they can be flagged on the ticket.
+func pendingDirectives(comments []*ticket.Comment, self string) ([]*Directive, map[string]error) {
+	acked := make(map[string]bool)
+	for _, c := range comments {
+		if self != "" && c.Author != self {
+			continue
+		}
+		for _, line := range strings.Split(c.Body, "\n") {
+			if idx := strings.Index(line, directiveAckMarker); idx >= 0 {
+				fields := strings.Fields(line[idx+len(directiveAckMarker):])
+				if len(fields) > 0 {
+					acked[strings.TrimSuffix(fields[0], ":")] = true
+				}
+			}
+		}
+	}
+
+	var directives []*Directive
+	invalid := make(map[string]error)
+	for _, c := range comments {
+		if c.ID == "" || acked[c.ID] {
+			continue
+		}
+		parsed, err := ParseDirectives(c)
+		if err != nil {
+			invalid[c.ID] = err
+			continue
+		}
+		directives = append(directives, parsed...)
+	}
+
+	return directives, invalid
+}
+
+// applyDirectives applies any pending chat command directives found on the ticket to the silence.
+// It returns true if the silence no longer exists after the directives have been applied.
+func (s *Synchronizer) applyDirectives(silence *alertmanager.Silence, tkt *ticket.Ticket, result *SyncResult) (bool, error) {
+	comments, err := s.ticketSystem.GetComments(tkt.Key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get comments for ticket %s: %w", tkt.Key, err)
+	}
+
+	var self string
+	if cu, ok := s.ticketSystem.(currentUserGetter); ok {
+		if self, err = cu.CurrentUser(); err != nil {
+			return false, fmt.Errorf("failed to look up the ticket system user: %w", err)
+		}
+	}
+	directives, invalid := pendingDirectives(comments, self)
+
+	for commentID, parseErr := range invalid {
+		s.rejectDirective(tkt.Key, commentID, parseErr)
+	}
+
+	for _, d := range directives {
+		var summary string
+
+		switch d.Action {
+		case DirectiveExtend:
+			newEndTime := s.now().Add(d.Duration)
+			event := ActionEvent{Action: ActionExtend, SilenceID: silence.ID, TicketKey: tkt.Key, NewEndsAt: newEndTime}
+			if err := s.vetoAction(event); err != nil {
+				s.rejectDirective(tkt.Key, d.CommentID, err)
+				continue
+			}
+			log.Printf("Applying directive from ticket %s: extending silence %s until %v", tkt.Key, silence.ID, newEndTime)
+			err := s.extendSilence(silence, newEndTime)
+			s.afterAction(event, err)
+			if err != nil {
+				return false, fmt.Errorf("failed to apply extend directive: %w", err)
+			}
+			s.updateDueDate(tkt.Key, newEndTime)
+			result.SilencesExtended++
+			summary = fmt.Sprintf("silence %s extended until %v", silence.ID, newEndTime.Format(time.RFC3339))
+		case DirectiveExpire:
+			event := ActionEvent{Action: ActionDelete, SilenceID: silence.ID, TicketKey: tkt.Key}
+			if err := s.vetoAction(event); err != nil {
+				s.rejectDirective(tkt.Key, d.CommentID, err)
+				continue
+			}
+			log.Printf("Applying directive from ticket %s: expiring silence %s", tkt.Key, silence.ID)
+			err := s.alertManager.DeleteSilence(silence.ID)
+			s.afterAction(event, err)
+			if err != nil {
+				return false, fmt.Errorf("failed to apply expire directive: %w", err)
+			}
+			result.SilencesDeleted++
+			summary = fmt.Sprintf("silence %s expired", silence.ID)
+		case DirectiveMatchers:
+			log.Printf("Applying directive from ticket %s: replacing matchers of silence %s", tkt.Key, silence.ID)
+			previousID := silence.ID
+			silence.Matchers = d.Matchers
+			if err := s.alertManager.UpdateSilence(silence); err != nil {
+				return false, fmt.Errorf("failed to apply matchers directive: %w", err)
+			}
+			// Alertmanager replaces a silence whose matchers change, so the following
+			// directives and the regular extension act on the new ID
+			s.followRenewedSilence(tkt, previousID, silence.ID)
+			summary = fmt.Sprintf("silence %s matchers set to %s", silence.ID, formatMatchers(d.Matchers))
+			if silence.ID != previousID {
+				summary += fmt.Sprintf(", replacing silence %s", previousID)
+			}
+		}
+
+		result.DirectivesApplied++
+		if err := s.ticketSystem.AddComment(tkt.Key, fmt.Sprintf("%s %s: %s.", directiveAckMarker, d.CommentID, summary)); err != nil {
+			log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+		}
+
+		if d.Action == DirectiveExpire {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// rejectDirective acknowledges a directive that was not applied, with the reason, so
+// that it is not retried
+func (s *Synchronizer) rejectDirective(key, commentID string, reason error) {
+	log.Printf("Rejecting directive in comment %s on ticket %s: %v", commentID, key, reason)
+	if err := s.ticketSystem.AddComment(key, fmt.Sprintf("%s %s: rejected, %v", directiveAckMarker, commentID, reason)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+}
+
+// formatMatchers renders matchers in the same syntax accepted by the matchers directive
+func formatMatchers(matchers []alertmanager.Matcher) string {
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		parts = append(parts, m.String())
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,326 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestParseDirectives(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		expectError bool
+		expected    []Directive
+	}{
+		{
+			name:     "No directive",
+			body:     "Looking into this, will update tomorrow",
+			expected: nil,
+		},
+		{
+			name: "Extend with hours",
+			body: "/silence extend 72h",
+			expected: []Directive{
+				{Action: DirectiveExtend, Duration: 72 * time.Hour},
+			},
+		},
+		{
+			name: "Extend with days",
+			body: "Need more time\n/silence extend 3d",
+			expected: []Directive{
+				{Action: DirectiveExtend, Duration: 72 * time.Hour},
+			},
+		},
+		{
+			name: "Expire",
+			body: "/silence expire",
+			expected: []Directive{
+				{Action: DirectiveExpire},
+			},
+		},
+		{
+			name: "Matchers",
+			body: `/silence matchers severity=warning instance=~"web-.*" env!=dev`,
+			expected: []Directive{
+				{Action: DirectiveMatchers, Matchers: []alertmanager.Matcher{
+					{Name: "severity", Value: "warning", IsEqual: true},
+					{Name: "instance", Value: "web-.*", IsEqual: true, IsRegex: true},
+					{Name: "env", Value: "dev", IsEqual: false},
+				}},
+			},
+		},
+		{
+			name:        "Unknown action",
+			body:        "/silence snooze",
+			expectError: true,
+		},
+		{
+			name:        "Invalid duration",
+			body:        "/silence extend soon",
+			expectError: true,
+		},
+		{
+			name:        "Negative duration",
+			body:        "/silence extend -1h",
+			expectError: true,
+		},
+		{
+			name:        "Invalid regex matcher",
+			body:        "/silence matchers instance=~web-(",
+			expectError: true,
+		},
+		{
+			name:        "Missing action",
+			body:        "/silence",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directives, err := ParseDirectives(&ticket.Comment{ID: "100", Body: tt.body})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDirectives() failed: %v", err)
+			}
+			if len(directives) != len(tt.expected) {
+				t.Fatalf("Expected %d directives, got %d", len(tt.expected), len(directives))
+			}
+			for i, d := range directives {
+				want := tt.expected[i]
+				if d.CommentID != "100" {
+					t.Errorf("Expected comment ID '100', got '%s'", d.CommentID)
+				}
+				if d.Action != want.Action {
+					t.Errorf("Expected action %s, got %s", want.Action, d.Action)
+				}
+				if d.Duration != want.Duration {
+					t.Errorf("Expected duration %v, got %v", want.Duration, d.Duration)
+				}
+				if len(d.Matchers) != len(want.Matchers) {
+					t.Fatalf("Expected %d matchers, got %d", len(want.Matchers), len(d.Matchers))
+				}
+				for j, m := range d.Matchers {
+					if m != want.Matchers[j] {
+						t.Errorf("Expected matcher %+v, got %+v", want.Matchers[j], m)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPendingDirectives_SkipsAcknowledged(t *testing.T) {
+	comments := []*ticket.Comment{
+		{ID: "1", Body: "/silence extend 24h"},
+		{ID: "2", Body: "Applied directive from comment 1: silence s1 extended until 2024-01-01T00:00:00Z."},
+		{ID: "3", Body: "/silence expire"},
+		{ID: "4", Body: "/silence bogus"},
+	}
+
+	directives, invalid := pendingDirectives(comments, "")
+
+	if len(directives) != 1 || directives[0].CommentID != "3" {
+		t.Fatalf("Expected only the directive from comment 3 to be pending, got %+v", directives)
+	}
+	if _, ok := invalid["4"]; !ok || len(invalid) != 1 {
+		t.Errorf("Expected comment 4 to be reported as invalid, got %v", invalid)
+	}
+}
+
+func TestPendingDirectives_IgnoresAcknowledgementsByOthers(t *testing.T) {
+	comments := []*ticket.Comment{
+		{ID: "1", Author: "alice", Body: "/silence extend 24h"},
+		{ID: "2", Author: "mallory", Body: "Applied directive from comment 1: silence s1 extended."},
+		{ID: "3", Author: "bob", Body: "/silence expire"},
+		{ID: "4", Author: "silence-bot", Body: "Applied directive from comment 3: silence s1 expired."},
+	}
+
+	directives, _ := pendingDirectives(comments, "silence-bot")
+
+	if len(directives) != 1 || directives[0].CommentID != "1" {
+		t.Fatalf("Expected only the directive from comment 1 to be pending, got %+v", directives)
+	}
+}
+
+func TestSync_DirectiveExtend(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ProcessDirectives = true
+
+	// Silence is far from expiry, so only the directive should extend it
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		EndsAt:    time.Now().Add(5 * 24 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.currentUser = "silence-bot"
+	// An acknowledgement posted by someone else does not count
+	ts.threads["PROJ-1"] = []*ticket.Comment{
+		{ID: "10", Author: "alice", Body: "/silence extend 30d"},
+		{ID: "11", Author: "mallory", Body: "Applied directive from comment 10: done."},
+	}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if result.DirectivesApplied != 1 {
+		t.Errorf("Expected 1 directive applied, got %d", result.DirectivesApplied)
+	}
+	if result.SilencesExtended != 1 {
+		t.Errorf("Expected 1 silence extended, got %d", result.SilencesExtended)
+	}
+	if until := time.Until(am.silences["silence-1"].EndsAt); until < 29*24*time.Hour {
+		t.Errorf("Expected silence to be extended by ~30 days, ends in %v", until)
+	}
+	if len(ts.comments["PROJ-1"]) != 1 || !strings.Contains(ts.comments["PROJ-1"][0], "Applied directive from comment 10") {
+		t.Errorf("Expected acknowledgement comment, got %v", ts.comments["PROJ-1"])
+	}
+
+	// A second run must not apply the same directive again
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.DirectivesApplied != 0 {
+		t.Errorf("Expected directive not to be re-applied, got %d", result.DirectivesApplied)
+	}
+}
+
+func TestSync_DirectiveExpire(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ProcessDirectives = true
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		EndsAt:    time.Now().Add(2 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.threads["PROJ-1"] = []*ticket.Comment{{ID: "10", Body: "/silence expire"}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if result.SilencesDeleted != 1 {
+		t.Errorf("Expected 1 silence deleted, got %d", result.SilencesDeleted)
+	}
+	// The silence was expiring soon, but must not be extended after being expired
+	if result.SilencesExtended != 0 {
+		t.Errorf("Expected 0 silences extended, got %d", result.SilencesExtended)
+	}
+}
+
+func TestSync_DirectiveMatchers(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ProcessDirectives = true
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		EndsAt:    time.Now().Add(5 * 24 * time.Hour),
+		TicketRef: "PROJ-1",
+		Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "HighCPU", IsEqual: true}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.threads["PROJ-1"] = []*ticket.Comment{{ID: "10", Body: "/silence matchers alertname=HighCPU severity=warning"}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	matchers := am.silences["silence-1"].Matchers
+	if len(matchers) != 2 || matchers[1].Name != "severity" || matchers[1].Value != "warning" {
+		t.Errorf("Expected matchers to be replaced, got %+v", matchers)
+	}
+}
+
+func TestSync_DirectiveMatchersRenewedSilence(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ProcessDirectives = true
+	am.renewOnUpdate = true
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		EndsAt:    time.Now().Add(5 * 24 * time.Hour),
+		TicketRef: "PROJ-1",
+		Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "HighCPU", IsEqual: true}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen, SilenceRef: "silence-1"}
+	ts.threads["PROJ-1"] = []*ticket.Comment{{ID: "10", Body: "/silence matchers alertname=HighCPU severity=warning\n/silence extend 30d"}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if result.DirectivesApplied != 2 {
+		t.Errorf("Expected 2 directives applied, got %d", result.DirectivesApplied)
+	}
+	if len(am.extendedIDs) != 1 || am.extendedIDs[0] != "silence-0" {
+		t.Errorf("Expected the replacement silence to be extended, got %v", am.extendedIDs)
+	}
+	if len(am.silences) != 1 || am.silences["silence-0"] == nil {
+		t.Errorf("Expected only the replacement silence, got %v", am.silences)
+	}
+	if got := ts.tickets["PROJ-1"].SilenceRef; got != "silence-0" {
+		t.Errorf("Expected the ticket to reference silence-0, got %q", got)
+	}
+	for _, comment := range ts.comments["PROJ-1"] {
+		if !strings.Contains(comment, "silence silence-0") {
+			t.Errorf("Expected acknowledgement about silence-0, got %q", comment)
+		}
+	}
+}
+
+func TestSync_DirectivesDisabled(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		EndsAt:    time.Now().Add(5 * 24 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.threads["PROJ-1"] = []*ticket.Comment{{ID: "10", Body: "/silence expire"}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.DirectivesApplied != 0 || len(am.deletedIDs) != 0 {
+		t.Error("Expected directives to be ignored when disabled")
+	}
+}
@@ -0,0 +1,127 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// discoverTickets reconciles the tickets found by DiscoveryQuery whose silence is not
+// among the active silences, which the silence-driven part of the run cannot see. The
+// expired silence of an open ticket is recreated under a new ID and the ticket updated
+// to reference it; silences that no longer exist are reported. The recreated silences
+// are returned.
+func (s *Synchronizer) discoverTickets(silences []*alertmanager.Silence, result *SyncResult) ([]*alertmanager.Silence, error) {
+	tickets, err := s.ticketSystem.SearchTickets(s.config.DiscoveryQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tickets: %w", err)
+	}
+	log.Printf("Discovered %d tickets referencing silences", len(tickets))
+
+	active := make(map[string]bool, len(silences))
+	for _, silence := range silences {
+		active[silence.ID] = true
+	}
+
+	var recreated []*alertmanager.Silence
+	for _, tkt := range tickets {
+		if tkt.SilenceRef == "" || active[tkt.SilenceRef] || !s.ticketSystem.IsOpen(tkt) {
+			continue
+		}
+
+		silence, err := s.alertManager.GetSilence(tkt.SilenceRef)
+		if err != nil {
+			log.Printf("Warning: silence %s of open ticket %s not found: %v", tkt.SilenceRef, tkt.Key, err)
+			result.Managed = append(result.Managed, ManagedSilence{
+				SilenceID:    tkt.SilenceRef,
+				TicketRef:    tkt.Key,
+				TicketStatus: string(tkt.Status),
+				Health:       HealthSilenceMissing,
+			})
+			continue
+		}
+		if s.protected(silence) {
+			log.Printf("Silence %s of ticket %s is protected from management, skipping", silence.ID, tkt.Key)
+			continue
+		}
+		if silence.TicketRef != "" && silence.TicketRef != tkt.Key {
+			log.Printf("Silence %s of ticket %s is linked to ticket %s, skipping", silence.ID, tkt.Key, silence.TicketRef)
+			continue
+		}
+
+		newSilence, err := s.recreateSilence(silence, tkt, result)
+		if newSilence != nil {
+			recreated = append(recreated, newSilence)
+		}
+		if err != nil {
+			log.Printf("Error recreating silence %s for ticket %s: %v", silence.ID, tkt.Key, err)
+			result.Errors = append(result.Errors, fmt.Errorf("ticket %s: %w", tkt.Key, err))
+		}
+	}
+
+	return recreated, nil
+}
+
+// recreateSilence replaces the expired silence of an open ticket with a new one, unless
+// its extension is withheld. It returns nil if no silence was created.
+func (s *Synchronizer) recreateSilence(silence *alertmanager.Silence, tkt *ticket.Ticket, result *SyncResult) (*alertmanager.Silence, error) {
+	action, endsAt := s.nextAction(silence, tkt)
+	switch action {
+	case ActionWithhold:
+		log.Printf("Ticket %s has no assignee, not recreating expired silence %s", tkt.Key, silence.ID)
+		result.ExtensionsWithheld++
+		s.requestOwnership(silence, tkt)
+		return nil, nil
+	case ActionExtend:
+	default:
+		return nil, nil
+	}
+
+	event := ActionEvent{Action: ActionRecreate, TicketKey: tkt.Key, NewEndsAt: endsAt}
+	if !s.beforeAction(event) {
+		return nil, nil
+	}
+	log.Printf("Ticket %s is open and silence %s has expired, recreating it until %v", tkt.Key, silence.ID, endsAt)
+	newSilence := &alertmanager.Silence{
+		CreatedBy: silence.CreatedBy,
+		Comment:   silence.Comment,
+		StartsAt:  s.now(),
+		EndsAt:    endsAt,
+		TicketRef: tkt.Key,
+		Matchers:  silence.Matchers,
+	}
+	silenceID, err := s.alertManager.CreateSilence(newSilence)
+	event.SilenceID = silenceID
+	s.afterAction(event, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recreate silence %s: %w", silence.ID, err)
+	}
+	newSilence.ID = silenceID
+	result.SilencesCreated++
+	result.TicketsDiscovered++
+
+	tkt.SilenceRef = silenceID
+	if err := s.ticketSystem.UpdateTicket(tkt); err != nil {
+		return newSilence, fmt.Errorf("failed to update ticket %s: %w", tkt.Key, err)
+	}
+
+	s.trackLifecycle(result, tkt, s.lifecycleLabelFor(endsAt, false))
+	s.updateDueDate(tkt.Key, endsAt)
+	s.relinkSilence(tkt.Key, silence.ID, silenceID)
+	msg := fmt.Sprintf("Silence %s had expired while the ticket is open. It has been recreated as silence %s until %v.",
+		silence.ID, s.silenceRef(silenceID), endsAt.Format(time.RFC3339))
+	if err := s.ticketSystem.AddComment(tkt.Key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+	result.Managed = append(result.Managed, ManagedSilence{
+		SilenceID:    silenceID,
+		TicketRef:    tkt.Key,
+		TicketStatus: string(tkt.Status),
+		EndsAt:       endsAt,
+		Health:       HealthHealthy,
+	})
+	return newSilence, nil
+}
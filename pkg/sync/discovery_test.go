@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// discoveryFixture is an open ticket whose silence expired; Alertmanager no longer lists it
+func discoveryFixture() fixture {
+	return fixture{
+		unlisted: []*alertmanager.Silence{{
+			ID:        "silence-old",
+			CreatedBy: "ops",
+			Comment:   "Disk replacement",
+			EndsAt:    time.Now().Add(-time.Hour),
+			TicketRef: "PROJ-1",
+			Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+		}},
+		tickets: []*ticket.Ticket{{Key: "PROJ-1", Status: ticket.StatusOpen, SilenceRef: "silence-old", Assignee: "alice"}},
+		configure: func(cfg *SyncConfig) {
+			cfg.CheckAlerts = false
+			cfg.DiscoveryQuery = `project = "PROJ" AND labels in ("silence-active")`
+		},
+	}
+}
+
+func TestSync_DiscoveryRecreatesExpiredSilence(t *testing.T) {
+	am, ts, sync := newFixture(discoveryFixture())
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(ts.searchQueries) != 1 || ts.searchQueries[0] != sync.config.DiscoveryQuery {
+		t.Errorf("Expected the discovery query to be searched once, got %v", ts.searchQueries)
+	}
+	if result.TicketsDiscovered != 1 || result.SilencesCreated != 1 {
+		t.Fatalf("Expected 1 recreated silence, got discovered=%d created=%d", result.TicketsDiscovered, result.SilencesCreated)
+	}
+
+	newID := ts.tickets["PROJ-1"].SilenceRef
+	created, ok := am.silences[newID]
+	if newID == "silence-old" || !ok {
+		t.Fatalf("Expected the ticket to reference the recreated silence, got %q", newID)
+	}
+	if created.TicketRef != "PROJ-1" || created.Comment != "Disk replacement" || len(created.Matchers) != 1 {
+		t.Errorf("Unexpected recreated silence: %+v", created)
+	}
+	if !created.EndsAt.After(time.Now().Add(6 * 24 * time.Hour)) {
+		t.Errorf("Expected the recreated silence to last the extension duration, ends at %v", created.EndsAt)
+	}
+	if len(result.Managed) != 1 || result.Managed[0].SilenceID != newID || result.Managed[0].Health != HealthHealthy {
+		t.Errorf("Unexpected inventory: %+v", result.Managed)
+	}
+
+	// The next run sees the recreated silence as active and leaves the ticket alone
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsDiscovered != 0 || result.SilencesCreated != 0 {
+		t.Errorf("Expected nothing to be recreated on the next run, got discovered=%d created=%d", result.TicketsDiscovered, result.SilencesCreated)
+	}
+}
+
+func TestSync_DiscoverySkipsTickets(t *testing.T) {
+	am, ts, sync := newFixture(discoveryFixture())
+	ts.tickets["PROJ-1"].Status = ticket.StatusClosed
+
+	// Silence of an open ticket that no longer exists at all
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusOpen, SilenceRef: "silence-gone"}
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesCreated != 0 || am.createdCount != 0 {
+		t.Errorf("Expected no silence to be created, got %d", result.SilencesCreated)
+	}
+	if len(result.Managed) != 1 || result.Managed[0].TicketRef != "PROJ-2" || result.Managed[0].Health != HealthSilenceMissing {
+		t.Errorf("Expected the missing silence of PROJ-2 to be reported, got %+v", result.Managed)
+	}
+}
+
+func TestSync_DiscoveryRequireAssignee(t *testing.T) {
+	am, ts, sync := newFixture(discoveryFixture())
+	sync.config.RequireAssignee = true
+	ts.tickets["PROJ-1"].Assignee = ""
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.ExtensionsWithheld != 1 || am.createdCount != 0 {
+		t.Errorf("Expected the recreation to be withheld, got withheld=%d created=%d", result.ExtensionsWithheld, am.createdCount)
+	}
+}
+
+func TestSync_DiscoverySearchError(t *testing.T) {
+	_, ts, sync := newFixture(discoveryFixture())
+	ts.searchErr = errors.New("jql error")
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected the search error to be reported, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,19 @@
+// Package sync is the sync engine of silence-manager: it keeps Alertmanager silences in
+// step with the tickets they reference, extending silences of open tickets, deleting
+// silences of resolved tickets and reopening tickets whose alerts refire.
+//
+// Programs can embed the engine with their own alertmanager.AlertManager and
+// ticket.TicketSystem implementations:
+//
+//	s := sync.New(am, tickets,
+//		sync.WithConfig(cfg),
+//		sync.WithStateStore(store),
+//		sync.WithBeforeAction(func(e sync.ActionEvent) error {
+//			if e.Action == sync.ActionExtend && freeze.Active() {
+//				return errors.New("change freeze")
+//			}
+//			return nil
+//		}),
+//	)
+//	result, err := s.SyncContext(ctx)
+package sync
@@ -0,0 +1,277 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// driftMarker identifies comments noting an edit made outside silence-manager
+const driftMarker = "was edited outside silence-manager"
+
+// fingerprintOf hashes the matchers and end time of a silence. Matcher order and
+// sub-second differences of the end time are ignored.
+func fingerprintOf(silence *alertmanager.Silence) state.SilenceFingerprint {
+	endsAt := silence.EndsAt.UTC().Truncate(time.Second)
+	sum := sha256.Sum256([]byte(matcherKey(silence.Matchers) + "\n" + endsAt.Format(time.RFC3339)))
+	return state.SilenceFingerprint{
+		TicketRef: silence.TicketRef,
+		Hash:      hex.EncodeToString(sum[:8]),
+		Matchers:  formatMatchers(silence.Matchers),
+		EndsAt:    endsAt,
+	}
+}
+
+// trackedAlertManager passes requests to the wrapped Alertmanager client, refreshing the
+// fingerprints of the silences silence-manager changes so that its own changes are not
+// taken for edits made outside it
+type trackedAlertManager struct {
+	alertmanager.AlertManager
+	s *Synchronizer
+}
+
+// tracked wraps am to fingerprint the silences it changes, if drift detection is enabled
+func (s *Synchronizer) tracked(am alertmanager.AlertManager) alertmanager.AlertManager {
+	if !s.config.DetectDrift {
+		return am
+	}
+	return &trackedAlertManager{AlertManager: am, s: s}
+}
+
+// untracked returns the client wrapped by tracked
+func untracked(am alertmanager.AlertManager) alertmanager.AlertManager {
+	if t, ok := am.(*trackedAlertManager); ok {
+		return t.AlertManager
+	}
+	return am
+}
+
+func (t *trackedAlertManager) CreateSilence(silence *alertmanager.Silence) (string, error) {
+	id, err := t.AlertManager.CreateSilence(silence)
+	if err == nil {
+		created := *silence
+		created.ID = id
+		t.s.refreshFingerprint(&created)
+	}
+	return id, err
+}
+
+func (t *trackedAlertManager) UpdateSilence(silence *alertmanager.Silence) error {
+	id := silence.ID
+	if err := t.AlertManager.UpdateSilence(silence); err != nil {
+		return err
+	}
+	if silence.ID != id {
+		t.s.forgetFingerprint(id)
+	}
+	t.s.refreshFingerprint(silence)
+	return nil
+}
+
+func (t *trackedAlertManager) ExtendSilence(id string, newEndTime time.Time) error {
+	if err := t.AlertManager.ExtendSilence(id, newEndTime); err != nil {
+		return err
+	}
+	silence, err := t.AlertManager.GetSilence(id)
+	if err != nil {
+		log.Printf("Warning: failed to fingerprint extended silence %s: %v", id, err)
+		return nil
+	}
+	t.s.refreshFingerprint(silence)
+	return nil
+}
+
+func (t *trackedAlertManager) DeleteSilence(id string) error {
+	if err := t.AlertManager.DeleteSilence(id); err != nil {
+		return err
+	}
+	t.s.forgetFingerprint(id)
+	return nil
+}
+
+// refreshFingerprint remembers the content silence-manager left a managed silence with.
+// Outside a run it is saved at once; a run saves it when it ends.
+func (s *Synchronizer) refreshFingerprint(silence *alertmanager.Silence) {
+	if silence.TicketRef == "" {
+		return
+	}
+	fp := fingerprintOf(silence)
+	s.setFingerprint(silence.ID, &fp)
+}
+
+// forgetFingerprint drops the fingerprint of a silence silence-manager deleted
+func (s *Synchronizer) forgetFingerprint(id string) {
+	s.setFingerprint(id, nil)
+}
+
+func (s *Synchronizer) setFingerprint(id string, fp *state.SilenceFingerprint) {
+	if s.fingerprints == nil {
+		s.fingerprints = make(map[string]*state.SilenceFingerprint)
+	}
+	s.fingerprints[id] = fp
+	if s.run == nil {
+		if err := s.saveFingerprints(); err != nil {
+			log.Printf("Warning: failed to save fingerprint of silence %s: %v", id, err)
+		}
+	}
+}
+
+// saveFingerprints writes the fingerprints refreshed by silence-manager's own changes to
+// the state. They are kept, so saving them again after a stale state was saved is harmless.
+func (s *Synchronizer) saveFingerprints() error {
+	if len(s.fingerprints) == 0 {
+		return nil
+	}
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.Fingerprints == nil {
+		st.Fingerprints = make(map[string]state.SilenceFingerprint)
+	}
+	for id, fp := range s.fingerprints {
+		if fp == nil {
+			delete(st.Fingerprints, id)
+		} else {
+			st.Fingerprints[id] = *fp
+		}
+	}
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// detectDrift compares the managed silences with their fingerprints and records the
+// silences edited since silence-manager last saw or changed them. Alertmanager replaces
+// a silence whose matchers are edited with a new one, so a new silence of a ticket whose
+// silence has gone is compared with the silence it replaced. New silences of other
+// tickets are fingerprinted without being reported, as are all silences on the first run.
+func (s *Synchronizer) detectDrift(result *SyncResult) error {
+	silences, err := s.alertManager.ListSilences()
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.Fingerprints == nil {
+		st.Fingerprints = make(map[string]state.SilenceFingerprint)
+	}
+
+	current := make(map[string]*alertmanager.Silence)
+	ids := make([]string, 0, len(silences))
+	for _, silence := range s.managedSilences(silences) {
+		if silence.TicketRef != "" {
+			current[silence.ID] = silence
+			ids = append(ids, silence.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	// Silences gone since the last run, by ticket, which an edit may have replaced
+	gone := make(map[string][]string)
+	for id, fp := range st.Fingerprints {
+		if current[id] == nil {
+			gone[fp.TicketRef] = append(gone[fp.TicketRef], id)
+		}
+	}
+	for _, g := range gone {
+		sort.Strings(g)
+	}
+
+	now := s.now()
+	for _, id := range ids {
+		silence := current[id]
+		fp := fingerprintOf(silence)
+		previous, known := st.Fingerprints[id]
+		replaced := ""
+		if !known {
+			candidates := gone[silence.TicketRef]
+			if len(candidates) == 0 {
+				st.Fingerprints[id] = fp
+				continue
+			}
+			// Prefer the silence silence-manager itself replaced with the same content
+			i := 0
+			for j, c := range candidates {
+				if st.Fingerprints[c].Hash == fp.Hash {
+					i = j
+					break
+				}
+			}
+			replaced = candidates[i]
+			gone[silence.TicketRef] = append(candidates[:i:i], candidates[i+1:]...)
+			previous = st.Fingerprints[replaced]
+			delete(st.Fingerprints, replaced)
+		}
+		st.Fingerprints[id] = fp
+		if previous.Hash == fp.Hash {
+			continue
+		}
+
+		event := state.DriftEvent{
+			Detected:   now,
+			SilenceID:  id,
+			ReplacedID: replaced,
+			TicketRef:  silence.TicketRef,
+			CreatedBy:  silence.CreatedBy,
+			Changes:    describeDrift(previous, fp),
+		}
+		st.AddDriftEvent(event, state.DefaultDriftRetention)
+		result.SilencesDrifted++
+		result.Drifts = append(result.Drifts, event)
+		log.Printf("Silence %s of ticket %s %s by %s: %s", id, silence.TicketRef, driftMarker, silence.CreatedBy, event.Changes)
+		if s.config.DriftComments {
+			s.commentDrift(event)
+		}
+	}
+
+	// Silences that expired or were deleted are forgotten
+	for _, g := range gone {
+		for _, id := range g {
+			delete(st.Fingerprints, id)
+		}
+	}
+
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// describeDrift describes the changes between two fingerprints of a silence
+func describeDrift(previous, current state.SilenceFingerprint) string {
+	var changes []string
+	if previous.Matchers != current.Matchers {
+		changes = append(changes, fmt.Sprintf("matchers changed from %s to %s", previous.Matchers, current.Matchers))
+	}
+	if !previous.EndsAt.Equal(current.EndsAt) {
+		changes = append(changes, fmt.Sprintf("end time changed from %s to %s",
+			previous.EndsAt.Format(time.RFC3339), current.EndsAt.Format(time.RFC3339)))
+	}
+	if len(changes) == 0 {
+		return "content changed"
+	}
+	return strings.Join(changes, ", ")
+}
+
+// commentDrift tells the ticket of a silence that it was edited outside silence-manager
+func (s *Synchronizer) commentDrift(event state.DriftEvent) {
+	msg := fmt.Sprintf("Silence %s %s by %s: %s.", s.silenceRef(event.SilenceID), driftMarker, event.CreatedBy, event.Changes)
+	if event.ReplacedID != "" {
+		msg += fmt.Sprintf(" Alertmanager replaced silence %s with it.", event.ReplacedID)
+	}
+	msg += " silence-manager manages the silence as edited from now on."
+	if err := s.ticketSystem.AddComment(event.TicketRef, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", event.TicketRef, err)
+	}
+}
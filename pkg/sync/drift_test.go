@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestFingerprintOf(t *testing.T) {
+	ends := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	a := &alertmanager.Silence{TicketRef: "PROJ-1", EndsAt: ends, Matchers: []alertmanager.Matcher{
+		{Name: "alertname", Value: "DiskFull", IsEqual: true},
+		{Name: "instance", Value: "db-1", IsEqual: true},
+	}}
+	b := &alertmanager.Silence{TicketRef: "PROJ-1", EndsAt: ends.Add(300 * time.Millisecond), Matchers: []alertmanager.Matcher{a.Matchers[1], a.Matchers[0]}}
+	if fingerprintOf(a).Hash != fingerprintOf(b).Hash {
+		t.Error("Expected matcher order and sub-second end times to be ignored")
+	}
+	b.EndsAt = ends.Add(time.Hour)
+	if fingerprintOf(a).Hash == fingerprintOf(b).Hash {
+		t.Error("Expected a different end time to change the fingerprint")
+	}
+}
+
+func TestSync_DetectDrift(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	now := time.Now()
+	disk := []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}}
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3"} {
+		ts.tickets[key] = &ticket.Ticket{Key: key, Status: ticket.StatusOpen}
+	}
+	am.silences["edited"] = &alertmanager.Silence{ID: "edited", TicketRef: "PROJ-1", Matchers: disk, CreatedBy: "silence-manager", StartsAt: now, EndsAt: now.Add(72 * time.Hour)}
+	am.silences["expiring"] = &alertmanager.Silence{ID: "expiring", TicketRef: "PROJ-2", Matchers: disk, CreatedBy: "silence-manager", StartsAt: now, EndsAt: now.Add(time.Hour)}
+	am.silences["widened"] = &alertmanager.Silence{ID: "widened", TicketRef: "PROJ-3", Matchers: disk, CreatedBy: "silence-manager", StartsAt: now, EndsAt: now.Add(72 * time.Hour)}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.DetectDrift = true
+	cfg.DriftComments = true
+	sync := New(am, ts, WithConfig(cfg), WithStateStore(store))
+
+	// The first run fingerprints the silences and extends the expiring one itself
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesDrifted != 0 || len(am.extendedIDs) != 1 {
+		t.Fatalf("Expected a baseline and 1 extension, got %d drifted and %v extended", result.SilencesDrifted, am.extendedIDs)
+	}
+
+	// A human moves one end time and replaces another silence with wider matchers
+	am.silences["edited"].EndsAt = now.Add(96 * time.Hour)
+	am.silences["edited"].CreatedBy = "alice"
+	delete(am.silences, "widened")
+	am.silences["widened-2"] = &alertmanager.Silence{
+		ID:        "widened-2",
+		TicketRef: "PROJ-3",
+		Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "Disk.*", IsEqual: true, IsRegex: true}},
+		CreatedBy: "bob",
+		StartsAt:  now,
+		EndsAt:    now.Add(72 * time.Hour),
+	}
+
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesDrifted != 2 {
+		t.Fatalf("Expected the 2 edited silences to drift and not the extended one, got %+v", result.Drifts)
+	}
+	drifts := make(map[string]state.DriftEvent)
+	for _, d := range result.Drifts {
+		drifts[d.SilenceID] = d
+	}
+	if d := drifts["edited"]; d.CreatedBy != "alice" || !strings.HasPrefix(d.Changes, "end time changed") {
+		t.Errorf("Expected the end time edit by alice, got %+v", d)
+	}
+	if d := drifts["widened-2"]; d.ReplacedID != "widened" || !strings.HasPrefix(d.Changes, "matchers changed") {
+		t.Errorf("Expected the replacement of widened with new matchers, got %+v", d)
+	}
+	if len(ts.comments["PROJ-1"]) != 1 || !strings.Contains(ts.comments["PROJ-1"][0], driftMarker) {
+		t.Errorf("Expected a comment on the ticket of the edited silence, got %v", ts.comments["PROJ-1"])
+	}
+	if len(ts.comments["PROJ-2"]) != 1 || strings.Contains(ts.comments["PROJ-2"][0], driftMarker) {
+		t.Errorf("Expected only the extension comment on the ticket of the extended silence, got %v", ts.comments["PROJ-2"])
+	}
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(st.Drift) != 2 {
+		t.Errorf("Expected 2 drift events in the state, got %d", len(st.Drift))
+	}
+	if _, ok := st.Fingerprints["widened"]; ok {
+		t.Error("Expected the fingerprint of the replaced silence to be dropped")
+	}
+
+	// Edits are reported once
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesDrifted != 0 {
+		t.Errorf("Expected no drift on the next run, got %+v", result.Drifts)
+	}
+}
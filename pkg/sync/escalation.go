@@ -0,0 +1,83 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/oncall"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// SetEscalator sets the on-call tool paged when a silence of an open ticket is left to
+// lapse because it reached MaxExtensions
+func (s *Synchronizer) SetEscalator(escalator oncall.Escalator) {
+	s.escalator = escalator
+}
+
+// extensionCapped returns how often a silence has been extended if that reaches
+// MaxExtensions. The count is read from the extension history of the silence comment.
+func (s *Synchronizer) extensionCapped(silence *alertmanager.Silence) (int, bool) {
+	if s.config.MaxExtensions <= 0 {
+		return 0, false
+	}
+	history, _ := parseExtensionHistory(silence.Comment)
+	return history.Extensions, history.Extensions >= s.config.MaxExtensions
+}
+
+// extensionCapMarker identifies comments reporting that a silence reached MaxExtensions
+const extensionCapMarker = "has reached the maximum number of automatic extensions"
+
+// withholdCapped reports whether a silence due for extension has reached MaxExtensions
+// and is left to expire instead. The escalator is paged and the ticket told once; a
+// failed escalation is retried on the next run.
+func (s *Synchronizer) withholdCapped(silence *alertmanager.Silence, tkt *ticket.Ticket, result *SyncResult) bool {
+	extensions, capped := s.extensionCapped(silence)
+	if !capped {
+		return false
+	}
+	log.Printf("Silence %s has been extended %d times, not extending it", silence.ID, extensions)
+
+	comments, err := s.ticketSystem.GetComments(tkt.Key)
+	if err != nil {
+		log.Printf("Warning: failed to get comments for ticket %s: %v", tkt.Key, err)
+		return true
+	}
+	for _, c := range comments {
+		if strings.Contains(c.Body, silence.ID) && strings.Contains(c.Body, extensionCapMarker) {
+			return true
+		}
+	}
+
+	msg := fmt.Sprintf("Silence %s %s (%d) and expires at %s while this ticket is still open. "+
+		"Please fix the underlying problem, or create a new silence if the alerts must stay muted.",
+		s.silenceRef(silence.ID), extensionCapMarker, s.config.MaxExtensions, silence.EndsAt.Format(time.RFC3339))
+	if s.escalator != nil {
+		assignee := tkt.Assignee
+		if assignee == "" {
+			assignee = "nobody"
+		}
+		escalation := oncall.Escalation{
+			ID:    silence.ID,
+			Title: fmt.Sprintf("Silence for %s (%s) is about to lapse", tkt.Key, tkt.Summary),
+			Message: fmt.Sprintf("Silence %s of alerts matching %s has been extended %d times, the maximum, and expires at %s. "+
+				"Ticket %s is still open with status %s and assigned to %s.",
+				silence.ID, formatMatchers(silence.Matchers), extensions, silence.EndsAt.Format(time.RFC3339),
+				tkt.Key, tkt.Status, assignee),
+		}
+		if s.config.SilenceUIURL != "" {
+			escalation.Link = s.silenceURL(silence.ID)
+		}
+		if err := s.escalator.Escalate(escalation); err != nil {
+			log.Printf("Warning: failed to escalate lapsing silence %s, retrying on the next run: %v", silence.ID, err)
+			return true
+		}
+		log.Printf("Escalated lapsing silence %s of ticket %s to on-call", silence.ID, tkt.Key)
+		msg += " The on-call has been paged."
+	}
+	s.addComment(tkt.Key, msg)
+	result.ExtensionCapsReached++
+	return true
+}
@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/oncall"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// recordingEscalator records escalations, failing while err is set
+type recordingEscalator struct {
+	escalations []oncall.Escalation
+	err         error
+}
+
+func (e *recordingEscalator) Escalate(escalation oncall.Escalation) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.escalations = append(e.escalations, escalation)
+	return nil
+}
+
+func TestSync_MaxExtensions(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	for id, extensions := range map[string]int{"capped": 3, "below": 2} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: "PROJ-" + id,
+			Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+			Comment:   "Disk replacement\n" + extensionHistory{Extensions: extensions, Synced: now}.String(),
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(time.Hour),
+		}
+		ts.tickets["PROJ-"+id] = &ticket.Ticket{Key: "PROJ-" + id, Summary: "Disk failing", Status: ticket.StatusOpen}
+	}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ExtensionHistory = true
+	cfg.MaxExtensions = 3
+	escalator := &recordingEscalator{err: errors.New("unavailable")}
+	s := New(am, ts, WithConfig(cfg), WithEscalator(escalator))
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	for _, p := range plan {
+		if want := p.SilenceID == "capped"; (p.Action == ActionLapse) != want {
+			t.Errorf("Expected silence %s to lapse=%v, got %s", p.SilenceID, want, p.Action)
+		}
+	}
+
+	// The failed escalation is retried, and the ticket told once it succeeds
+	result, err := s.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.ExtensionCapsReached != 0 || len(ts.comments["PROJ-capped"]) != 0 {
+		t.Errorf("Expected nothing to be reported while escalating fails, got %d and %v", result.ExtensionCapsReached, ts.comments["PROJ-capped"])
+	}
+	escalator.err = nil
+	for run := 0; run < 2; run++ {
+		if result, err = s.Sync(); err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+		if want := 1 - run; result.ExtensionCapsReached != want {
+			t.Errorf("Run %d: expected %d capped silences, got %d", run, want, result.ExtensionCapsReached)
+		}
+	}
+
+	if len(escalator.escalations) != 1 {
+		t.Fatalf("Expected one escalation, got %d", len(escalator.escalations))
+	}
+	if e := escalator.escalations[0]; e.ID != "capped" || !strings.Contains(e.Title, "PROJ-capped") || !strings.Contains(e.Message, "extended 3 times") {
+		t.Errorf("Unexpected escalation: %+v", e)
+	}
+	if comments := ts.comments["PROJ-capped"]; len(comments) != 1 || !strings.Contains(comments[0], extensionCapMarker) {
+		t.Errorf("Expected one comment about the cap, got %v", comments)
+	}
+	if am.silences["capped"].EndsAt.After(now.Add(time.Hour)) {
+		t.Error("Expected the capped silence not to be extended")
+	}
+	if !am.silences["below"].EndsAt.After(now.Add(time.Hour)) {
+		t.Error("Expected the silence below the cap to be extended")
+	}
+}
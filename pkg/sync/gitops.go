@@ -0,0 +1,203 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/gitops"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// SetGitOpsSource sets the source of declared silences. Each definition gets a silence
+// linked to its ticket, recreated while the ticket is open; silences of definitions
+// removed from the source are expired when SyncConfig.GitOpsPrune is set.
+func (s *Synchronizer) SetGitOpsSource(source gitops.Source) {
+	s.gitOps = source
+}
+
+// syncGitOps converges Alertmanager to the declared silences: it creates silences and
+// tickets for new definitions, replaces silences whose matchers or ticket changed,
+// recreates expired silences of open tickets and prunes removed definitions. Once
+// created, the silences follow the regular lifecycle of their tickets.
+func (s *Synchronizer) syncGitOps(result *SyncResult) error {
+	defs, err := s.gitOps.Definitions()
+	if err != nil {
+		// Do not prune anything we cannot see
+		return err
+	}
+
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.GitOps == nil {
+		st.GitOps = make(map[string]state.GitOpsRecord)
+	}
+
+	active, err := s.alertManager.ListSilences()
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	silences := make(map[string]*alertmanager.Silence, len(active))
+	for _, silence := range active {
+		silences[silence.ID] = silence
+	}
+
+	declared := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		declared[def.Name] = true
+		rec, err := s.applyGitOpsDefinition(def, st.GitOps[def.Name], silences, active)
+		if err != nil {
+			log.Printf("Error applying silence definition %s: %v", def, err)
+			result.Errors = append(result.Errors, fmt.Errorf("silence definition %s: %w", def, err))
+			continue
+		}
+		if rec == nil {
+			continue
+		}
+		st.GitOps[def.Name] = *rec
+		result.GitOpsApplied++
+	}
+
+	for name, rec := range st.GitOps {
+		if declared[name] || !s.config.GitOpsPrune {
+			continue
+		}
+		pruned, err := s.pruneGitOpsSilence(name, rec, silences)
+		if err != nil {
+			log.Printf("Error pruning silence definition %q: %v", name, err)
+			result.Errors = append(result.Errors, fmt.Errorf("silence definition %q: %w", name, err))
+			continue
+		}
+		if !pruned {
+			continue
+		}
+		delete(st.GitOps, name)
+		result.GitOpsPruned++
+	}
+
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// applyGitOpsDefinition brings the silence of a definition in line with it. It returns
+// the new record when a silence was created or adopted, and nil when nothing changed.
+func (s *Synchronizer) applyGitOpsDefinition(def gitops.Definition, rec state.GitOpsRecord, silences map[string]*alertmanager.Silence, active []*alertmanager.Silence) (*state.GitOpsRecord, error) {
+	matchers, err := ParseMatchers(def.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	var duration time.Duration
+	if def.Duration != "" {
+		if duration, err = ParseDuration(def.Duration); err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", def.Duration, err)
+		}
+	}
+
+	key := matcherKey(matchers)
+	silence := silences[rec.SilenceID]
+	if rec.SilenceID == "" {
+		// Adopt a silence already covering the definition, e.g. created before the definition was committed
+		for _, existing := range active {
+			if matcherKey(existing.Matchers) == key && existing.TicketRef != "" && (def.Ticket == "" || def.Ticket == existing.TicketRef) && !s.protected(existing) {
+				log.Printf("Adopted silence %s for definition %s", existing.ID, def)
+				return &state.GitOpsRecord{SilenceID: existing.ID, TicketKey: existing.TicketRef, Matchers: def.Matchers}, nil
+			}
+		}
+	} else {
+		ticketKey := rec.TicketKey
+		if def.Ticket != "" {
+			ticketKey = def.Ticket
+		}
+		changed := ticketKey != rec.TicketKey || matcherKey(recordedMatchers(rec.Matchers)) != key
+		if silence != nil && !changed {
+			return nil, nil
+		}
+		if silence != nil && s.protected(silence) {
+			log.Printf("Silence %s of definition %s is protected from management, not replacing it", silence.ID, def)
+			return nil, nil
+		}
+
+		// The silence expired, was deleted or no longer matches the definition: recreate
+		// it while its ticket is open
+		tkt, err := s.ticketSystem.GetTicket(ticketKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ticket %s: %w", ticketKey, err)
+		}
+		if tkt.Status != ticket.StatusOpen {
+			return nil, nil
+		}
+		if silence != nil {
+			deleted, err := s.deleteManagedSilence(silence.ID, silence.TicketRef)
+			if err != nil {
+				return nil, fmt.Errorf("failed to delete silence %s: %w", silence.ID, err)
+			}
+			if !deleted {
+				return nil, nil
+			}
+			log.Printf("Deleted silence %s replaced by definition %s", silence.ID, def)
+			delete(silences, silence.ID)
+		}
+		def.Ticket = ticketKey
+	}
+
+	comment := def.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("Declared as %s", def)
+	}
+	created, err := s.CreateSilence(SilenceDefinition{
+		Matchers:  matchers,
+		Comment:   comment,
+		CreatedBy: "silence-manager (gitops)",
+		Duration:  duration,
+		Ticket:    def.Ticket,
+		Summary:   def.Summary,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Applied definition %s: silence %s linked to ticket %s", def, created.ID, created.TicketRef)
+	return &state.GitOpsRecord{SilenceID: created.ID, TicketKey: created.TicketRef, Matchers: def.Matchers}, nil
+}
+
+// pruneGitOpsSilence expires the silence of a definition removed from the source. The
+// ticket is told about it but left open. A protected silence is left alone. It reports
+// false when the deletion was vetoed, keeping the definition to be pruned by a later run.
+func (s *Synchronizer) pruneGitOpsSilence(name string, rec state.GitOpsRecord, silences map[string]*alertmanager.Silence) (bool, error) {
+	if silence := silences[rec.SilenceID]; silence != nil && s.protected(silence) {
+		log.Printf("Silence %s of removed definition %q is protected from management, leaving it", rec.SilenceID, name)
+		return true, nil
+	}
+	if silences[rec.SilenceID] != nil {
+		deleted, err := s.deleteManagedSilence(rec.SilenceID, rec.TicketKey)
+		if err != nil {
+			return false, fmt.Errorf("failed to delete silence %s: %w", rec.SilenceID, err)
+		}
+		if !deleted {
+			return false, nil
+		}
+	}
+
+	log.Printf("Pruned silence %s of removed definition %q", rec.SilenceID, name)
+	msg := fmt.Sprintf("Silence %s has been deleted because its definition %q was removed from the repository.", rec.SilenceID, name)
+	if err := s.ticketSystem.AddComment(rec.TicketKey, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", rec.TicketKey, err)
+	}
+	return true, nil
+}
+
+// recordedMatchers parses matchers recorded by an earlier run, skipping invalid ones
+func recordedMatchers(values []string) []alertmanager.Matcher {
+	var matchers []alertmanager.Matcher
+	for _, value := range values {
+		if m, err := parseMatcher(value); err == nil {
+			matchers = append(matchers, m)
+		}
+	}
+	return matchers
+}
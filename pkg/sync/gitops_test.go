@@ -0,0 +1,144 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/gitops"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// staticDefinitions is a gitops.Source returning a fixed set of definitions
+type staticDefinitions struct {
+	defs []gitops.Definition
+	err  error
+}
+
+func (d *staticDefinitions) Definitions() ([]gitops.Definition, error) {
+	return d.defs, d.err
+}
+
+func TestSync_GitOps(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	ts.tickets["PROJ-9"] = &ticket.Ticket{Key: "PROJ-9", Status: ticket.StatusOpen}
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	disk := gitops.Definition{Name: "disk-full", Matchers: []string{"alertname=DiskFull"}, Summary: "Known disk alerts", File: "storage.yaml"}
+	db := gitops.Definition{Name: "db-flapping", Matchers: []string{"team=db"}, Ticket: "PROJ-9", Duration: "30d", File: "db.yaml"}
+	source := &staticDefinitions{defs: []gitops.Definition{disk, db}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetGitOpsSource(source)
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.GitOpsApplied != 2 || len(am.silences) != 2 {
+		t.Fatalf("Expected 2 declared silences, applied %d with %d silences", result.GitOpsApplied, len(am.silences))
+	}
+	var diskID, dbID string
+	for id, silence := range am.silences {
+		if silence.TicketRef == "PROJ-9" {
+			dbID = id
+			if until := time.Until(silence.EndsAt); until < 29*24*time.Hour {
+				t.Errorf("Expected the declared duration of 30d, silence ends in %v", until)
+			}
+		} else {
+			diskID = id
+		}
+	}
+	diskTicket := am.silences[diskID].TicketRef
+	if tkt := ts.tickets[diskTicket]; tkt == nil || tkt.Summary != "Known disk alerts" {
+		t.Fatalf("Expected a ticket created with the declared summary, got %+v", tkt)
+	}
+
+	// Unchanged definitions are left alone
+	result, _ = sync.Sync()
+	if result.GitOpsApplied != 0 || len(am.silences) != 2 {
+		t.Errorf("Expected no changes, applied %d with %d silences", result.GitOpsApplied, len(am.silences))
+	}
+
+	// Changed matchers replace the silence, linked to the same ticket
+	db.Matchers = []string{"team=database"}
+	source.defs = []gitops.Definition{disk, db}
+	result, _ = sync.Sync()
+	if result.GitOpsApplied != 1 || am.silences[dbID] != nil {
+		t.Fatalf("Expected silence %s to be replaced, applied %d", dbID, result.GitOpsApplied)
+	}
+	for id, silence := range am.silences {
+		if silence.TicketRef == "PROJ-9" {
+			dbID = id
+		}
+	}
+	if am.silences[dbID].Matchers[0].Value != "database" {
+		t.Errorf("Expected the new matchers, got %+v", am.silences[dbID].Matchers)
+	}
+
+	// A silence deleted in Alertmanager is recreated while its ticket is open...
+	delete(am.silences, dbID)
+	result, _ = sync.Sync()
+	if result.GitOpsApplied != 1 || len(am.silences) != 2 {
+		t.Errorf("Expected the silence to be recreated, applied %d with %d silences", result.GitOpsApplied, len(am.silences))
+	}
+
+	// ...but not once the ticket is closed
+	for id, silence := range am.silences {
+		if silence.TicketRef == "PROJ-9" {
+			delete(am.silences, id)
+		}
+	}
+	ts.tickets["PROJ-9"].Status = ticket.StatusClosed
+	result, _ = sync.Sync()
+	if result.GitOpsApplied != 0 || len(am.silences) != 1 {
+		t.Errorf("Expected no silence for a closed ticket, applied %d with %d silences", result.GitOpsApplied, len(am.silences))
+	}
+
+	// A source that cannot be read prunes nothing
+	source.err = errors.New("checkout missing")
+	result, _ = sync.Sync()
+	if result.GitOpsPruned != 0 || len(result.Errors) != 1 || am.silences[diskID] == nil {
+		t.Errorf("Expected an error and no pruning, pruned %d with errors %v", result.GitOpsPruned, result.Errors)
+	}
+
+	// Removed definitions are pruned and their ticket is told
+	source.err = nil
+	source.defs = []gitops.Definition{db}
+	result, _ = sync.Sync()
+	if result.GitOpsPruned != 1 || am.silences[diskID] != nil {
+		t.Errorf("Expected silence %s to be pruned, pruned %d", diskID, result.GitOpsPruned)
+	}
+	comments := ts.comments[diskTicket]
+	if len(comments) == 0 || !strings.Contains(comments[len(comments)-1], `"disk-full" was removed`) {
+		t.Errorf("Expected a comment announcing the pruning, got %v", comments)
+	}
+}
+
+func TestSync_GitOpsAdoptsExistingSilence(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.CreateSilence(SilenceDefinition{Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}}, Ticket: "PROJ-1"}); err != nil {
+		t.Fatalf("CreateSilence() failed: %v", err)
+	}
+	sync.SetGitOpsSource(&staticDefinitions{defs: []gitops.Definition{
+		{Name: "disk-full", Matchers: []string{"alertname=DiskFull"}, File: "storage.yaml"},
+	}})
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.GitOpsApplied != 1 || len(result.Errors) != 0 || len(am.silences) != 1 {
+		t.Errorf("Expected the silence to be adopted, applied %d with %d silences and errors %v", result.GitOpsApplied, len(am.silences), result.Errors)
+	}
+}
@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// ticketGroup holds the silences referencing one ticket and the decision shared by them
+type ticketGroup struct {
+	silences []*alertmanager.Silence
+	decided  bool
+	extend   bool      // One of the silences is due for extension
+	endsAt   time.Time // End time of all silences extended together
+}
+
+// groupByTicket groups the silences processed in a run by their ticket, leaving out
+// those in skip
+func (s *Synchronizer) groupByTicket(silences []*alertmanager.Silence, skip map[string]bool) {
+	s.groups = make(map[string]*ticketGroup)
+	for _, silence := range silences {
+		if silence.TicketRef == "" || skip[silence.ID] || !s.allowsTicketRef(silence.TicketRef) {
+			continue
+		}
+		group := s.groups[silence.TicketRef]
+		if group == nil {
+			group = &ticketGroup{}
+			s.groups[silence.TicketRef] = group
+		}
+		group.silences = append(group.silences, silence)
+	}
+}
+
+// nextGroupAction is nextAction for a silence that may share its ticket with others. When
+// one silence of a ticket is due for extension, all of them are extended to the same end
+// time, so that they keep expiring together.
+func (s *Synchronizer) nextGroupAction(silence *alertmanager.Silence, tkt *ticket.Ticket) (string, time.Time) {
+	action, endsAt := s.nextAction(silence, tkt)
+	group := s.groups[silence.TicketRef]
+	if group == nil || len(group.silences) < 2 || (action != ActionNone && action != ActionExtend) {
+		return action, endsAt
+	}
+
+	// Decide before the first silence of the group is extended
+	if !group.decided {
+		group.decided = true
+		for _, sibling := range group.silences {
+			if siblingAction, siblingEnd := s.nextAction(sibling, tkt); siblingAction == ActionExtend {
+				group.extend, group.endsAt = true, siblingEnd
+				break
+			}
+		}
+	}
+	if !group.extend || !silence.EndsAt.Before(group.endsAt) {
+		return action, endsAt
+	}
+	return ActionExtend, group.endsAt
+}
+
+// addComment comments on a ticket. While a run processes silences, the comments are
+// collected and posted by flushComments, one per ticket.
+func (s *Synchronizer) addComment(key, msg string) {
+	if s.comments == nil {
+		if err := s.ticketSystem.AddComment(key, msg); err != nil {
+			log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+		}
+		return
+	}
+	if _, ok := s.comments[key]; !ok {
+		s.commentOrder = append(s.commentOrder, key)
+	}
+	s.comments[key] = append(s.comments[key], msg)
+}
+
+// collectComments starts collecting the comments of addComment
+func (s *Synchronizer) collectComments() {
+	s.comments = make(map[string][]string)
+	s.commentOrder = nil
+}
+
+// flushComments posts the collected comments, consolidating those of a ticket linked to
+// several silences into one comment, and stops collecting
+func (s *Synchronizer) flushComments() {
+	comments, order := s.comments, s.commentOrder
+	s.comments, s.commentOrder = nil, nil
+
+	for _, key := range order {
+		msgs := comments[key]
+		msg := msgs[0]
+		if len(msgs) > 1 {
+			var b strings.Builder
+			fmt.Fprintf(&b, "%d silences linked to this ticket were updated:", len(msgs))
+			for _, m := range msgs {
+				b.WriteString("\n- " + m)
+			}
+			msg = b.String()
+		}
+		if err := s.ticketSystem.AddComment(key, msg); err != nil {
+			log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestSync_ProcessesSilencesOfATicketTogether(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	for id, silence := range map[string]struct {
+		ref    string
+		endsIn time.Duration
+	}{
+		"expiring": {"PROJ-1", time.Hour},
+		"later":    {"PROJ-1", 48 * time.Hour},
+		"long":     {"PROJ-1", 30 * 24 * time.Hour},
+		"db":       {"PROJ-2", 48 * time.Hour},
+		"web":      {"PROJ-2", 48 * time.Hour},
+		"single":   {"PROJ-3", time.Hour},
+	} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: silence.ref,
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(silence.endsIn),
+		}
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusResolved}
+	ts.tickets["PROJ-3"] = &ticket.Ticket{Key: "PROJ-3", Status: ticket.StatusOpen}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	result, err := New(am, ts, WithConfig(cfg)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	// The silences of an open ticket are extended together to the same end time
+	if result.SilencesExtended != 3 {
+		t.Errorf("Expected 3 extensions, got %d", result.SilencesExtended)
+	}
+	if !am.silences["expiring"].EndsAt.Equal(am.silences["later"].EndsAt) {
+		t.Errorf("Expected the silences of PROJ-1 to end together, got %v and %v", am.silences["expiring"].EndsAt, am.silences["later"].EndsAt)
+	}
+	if !am.silences["long"].EndsAt.Equal(now.Add(30 * 24 * time.Hour)) {
+		t.Errorf("Expected a silence outlasting the extension to be left alone, got %v", am.silences["long"].EndsAt)
+	}
+
+	// The silences of a resolved ticket are deleted together
+	if result.SilencesDeleted != 2 {
+		t.Errorf("Expected both silences of PROJ-2 to be deleted, got %d", result.SilencesDeleted)
+	}
+
+	// Each ticket gets a single comment
+	for key, count := range map[string]int{"PROJ-1": 2, "PROJ-2": 2} {
+		comments := ts.comments[key]
+		if len(comments) != 1 || !strings.Contains(comments[0], "silences linked to this ticket were updated") || strings.Count(comments[0], "\n- ") != count {
+			t.Errorf("Expected one consolidated comment on %s, got %v", key, comments)
+		}
+	}
+	if comments := ts.comments["PROJ-3"]; len(comments) != 1 || !strings.HasPrefix(comments[0], "Silence single has been automatically extended") {
+		t.Errorf("Expected the usual comment on a ticket with a single silence, got %v", comments)
+	}
+}
@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// historyMarker starts the extension history line kept at the end of silence comments
+const historyMarker = "silence-manager history:"
+
+// maxPreviousEnds bounds the end times kept in the history, and so how many extensions
+// can be reverted
+const maxPreviousEnds = 10
+
+// extensionHistory summarizes the automatic extensions of a silence
+type extensionHistory struct {
+	Extensions int
+	// PreviousEnds holds the end times before each recorded extension, oldest first
+	PreviousEnds []time.Time
+	Synced       time.Time
+}
+
+// PreviousEnd returns the end time before the latest extension, or the zero time
+func (h extensionHistory) PreviousEnd() time.Time {
+	if len(h.PreviousEnds) == 0 {
+		return time.Time{}
+	}
+	return h.PreviousEnds[len(h.PreviousEnds)-1]
+}
+
+// String formats the history as a single line, e.g.
+// "silence-manager history: extensions=2 previous-ends=2024-02-22T09:00:00Z,2024-03-01T09:00:00Z synced=2024-03-01T08:15:00Z"
+func (h extensionHistory) String() string {
+	ends := make([]string, len(h.PreviousEnds))
+	for i, end := range h.PreviousEnds {
+		ends[i] = end.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%s extensions=%d previous-ends=%s synced=%s", historyMarker, h.Extensions,
+		strings.Join(ends, ","), h.Synced.UTC().Format(time.RFC3339))
+}
+
+// parseExtensionHistory returns the history recorded in a silence comment and the comment
+// without the history line. Unreadable values are treated as missing. The single
+// previous-end of earlier versions is read as the only previous end.
+func parseExtensionHistory(comment string) (extensionHistory, string) {
+	var history extensionHistory
+	lines := strings.Split(comment, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), historyMarker)
+		if !ok {
+			kept = append(kept, line)
+			continue
+		}
+		for _, field := range strings.Fields(rest) {
+			key, value, _ := strings.Cut(field, "=")
+			switch key {
+			case "extensions":
+				history.Extensions, _ = strconv.Atoi(value)
+			case "previous-end", "previous-ends":
+				history.PreviousEnds = nil
+				for _, v := range strings.Split(value, ",") {
+					if end, err := time.Parse(time.RFC3339, v); err == nil {
+						history.PreviousEnds = append(history.PreviousEnds, end)
+					}
+				}
+			case "synced":
+				history.Synced, _ = time.Parse(time.RFC3339, value)
+			}
+		}
+	}
+	return history, strings.TrimRight(strings.Join(kept, "\n"), "\n")
+}
+
+// extendSilence moves the end of the silence to newEndTime. With ExtensionHistory, the
+// history line at the end of the silence comment is updated in the same request.
+func (s *Synchronizer) extendSilence(silence *alertmanager.Silence, newEndTime time.Time) error {
+	if !s.config.ExtensionHistory {
+		return s.updateSilence(silence, newEndTime, silence.Comment)
+	}
+
+	history, comment := parseExtensionHistory(silence.Comment)
+	history.Extensions++
+	history.PreviousEnds = append(history.PreviousEnds, silence.EndsAt)
+	if len(history.PreviousEnds) > maxPreviousEnds {
+		history.PreviousEnds = history.PreviousEnds[len(history.PreviousEnds)-maxPreviousEnds:]
+	}
+	history.Synced = s.now()
+	return s.updateHistory(silence, newEndTime, comment, history)
+}
+
+// updateHistory sets the end and the history line of the silence in a single request
+func (s *Synchronizer) updateHistory(silence *alertmanager.Silence, endsAt time.Time, comment string, history extensionHistory) error {
+	if comment != "" {
+		comment += "\n"
+	}
+
+	return s.updateSilence(silence, endsAt, comment+history.String())
+}
+
+// updateSilence sets the end and the comment of the silence. Alertmanager may replace the
+// silence under a new ID, e.g. when it had expired, so the ticket follows the new ID.
+func (s *Synchronizer) updateSilence(silence *alertmanager.Silence, endsAt time.Time, comment string) error {
+	updated := *silence
+	updated.EndsAt = endsAt
+	updated.Comment = comment
+	if err := s.alertManager.UpdateSilence(&updated); err != nil {
+		return err
+	}
+	previousID := silence.ID
+	silence.ID = updated.ID
+	silence.EndsAt = updated.EndsAt
+	silence.Comment = updated.Comment
+	if silence.ID != previousID && silence.TicketRef != "" {
+		s.followRenewedSilenceOf(silence.TicketRef, previousID, silence.ID)
+	}
+	return nil
+}
+
+// RevertExtension undoes the latest recorded extension of a silence, moving its end back
+// to the time recorded in the extension history. Extensions are only recorded with
+// ExtensionHistory, and the ticket is told about the revert. It returns the reverted
+// silence.
+func (s *Synchronizer) RevertExtension(silenceID string) (*alertmanager.Silence, error) {
+	silence, err := s.alertManager.GetSilence(silenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get silence %s: %w", silenceID, err)
+	}
+
+	history, comment := parseExtensionHistory(silence.Comment)
+	previousEnd := history.PreviousEnd()
+	if previousEnd.IsZero() {
+		return nil, fmt.Errorf("silence %s has no recorded extension to revert", silenceID)
+	}
+	if !previousEnd.After(s.now()) {
+		return nil, fmt.Errorf("silence %s would end in the past at %s; delete it instead",
+			silenceID, previousEnd.Format(time.RFC3339))
+	}
+
+	extendedEnd := silence.EndsAt
+	history.PreviousEnds = history.PreviousEnds[:len(history.PreviousEnds)-1]
+	if history.Extensions > 0 {
+		history.Extensions--
+	}
+	history.Synced = s.now()
+	if err := s.updateHistory(silence, previousEnd, comment, history); err != nil {
+		return nil, fmt.Errorf("failed to update silence %s: %w", silenceID, err)
+	}
+
+	log.Printf("Reverted extension of silence %s from %s to %s", silence.ID,
+		extendedEnd.Format(time.RFC3339), previousEnd.Format(time.RFC3339))
+	if silence.TicketRef != "" {
+		s.updateDueDate(silence.TicketRef, previousEnd)
+		msg := fmt.Sprintf("The extension of silence %s has been reverted. It now expires at %s instead of %s.",
+			s.silenceRef(silence.ID), previousEnd.Format(time.RFC3339), extendedEnd.Format(time.RFC3339))
+		if err := s.ticketSystem.AddComment(silence.TicketRef, msg); err != nil {
+			log.Printf("Warning: failed to add comment to ticket %s: %v", silence.TicketRef, err)
+		}
+	}
+	return silence, nil
+}
@@ -0,0 +1,191 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestParseExtensionHistory(t *testing.T) {
+	comment := "# silence-manager: PROJ-1\nDisk alerts\nsilence-manager history: extensions=2 previous-ends=2024-02-22T09:00:00Z,2024-03-01T09:00:00Z synced=2024-03-01T08:15:00Z"
+
+	history, rest := parseExtensionHistory(comment)
+	if rest != "# silence-manager: PROJ-1\nDisk alerts" {
+		t.Errorf("Unexpected comment without history: %q", rest)
+	}
+	if history.Extensions != 2 {
+		t.Errorf("Expected 2 extensions, got %d", history.Extensions)
+	}
+	if len(history.PreviousEnds) != 2 || !history.PreviousEnd().Equal(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected previous ends: %v", history.PreviousEnds)
+	}
+	if history.String() != comment[strings.LastIndex(comment, "\n")+1:] {
+		t.Errorf("Expected history to format as it was parsed, got %q", history.String())
+	}
+
+	// The single previous end of earlier versions is still read
+	history, _ = parseExtensionHistory("silence-manager history: extensions=1 previous-end=2024-03-01T09:00:00Z synced=2024-03-01T08:15:00Z")
+	if len(history.PreviousEnds) != 1 || !history.PreviousEnd().Equal(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected previous ends from the single previous end: %v", history.PreviousEnds)
+	}
+
+	history, rest = parseExtensionHistory("Hand-written silence")
+	if history.Extensions != 0 || rest != "Hand-written silence" {
+		t.Errorf("Expected no history, got %+v and %q", history, rest)
+	}
+}
+
+func TestProcessSilence_ExtensionHistory(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ExtensionHistory = true
+
+	previousEnd := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		Comment:   "# silence-manager: PROJ-1\nDisk alerts",
+		EndsAt:    previousEnd,
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesExtended != 1 {
+		t.Fatalf("Expected 1 silence extended, got %d", result.SilencesExtended)
+	}
+
+	silence := am.silences["silence-1"]
+	if !silence.EndsAt.After(previousEnd) {
+		t.Errorf("Expected silence to be extended beyond %v, got %v", previousEnd, silence.EndsAt)
+	}
+	history, rest := parseExtensionHistory(silence.Comment)
+	if rest != "# silence-manager: PROJ-1\nDisk alerts" {
+		t.Errorf("Expected original comment to be kept, got %q", rest)
+	}
+	if history.Extensions != 1 || !history.PreviousEnd().Equal(previousEnd) || history.Synced.IsZero() {
+		t.Errorf("Unexpected history: %+v", history)
+	}
+
+	// A second extension replaces the history line instead of appending another
+	silence.EndsAt = time.Now().Add(time.Hour)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	comment := am.silences["silence-1"].Comment
+	if strings.Count(comment, historyMarker) != 1 {
+		t.Errorf("Expected a single history line, got %q", comment)
+	}
+	if history, _ := parseExtensionHistory(comment); history.Extensions != 2 {
+		t.Errorf("Expected 2 extensions, got %d", history.Extensions)
+	}
+}
+
+func TestProcessSilence_ExtensionRenewsSilence(t *testing.T) {
+	for _, history := range []bool{false, true} {
+		am := newMockAlertManager()
+		am.renewExpired = true
+		ts := newMockTicketSystem()
+		cfg := DefaultConfig()
+		cfg.CheckAlerts = false
+		cfg.ExtensionHistory = history
+		cfg.SilenceUIURL = "https://alertmanager.example.com"
+
+		am.silences["silence-1"] = &alertmanager.Silence{
+			ID:        "silence-1",
+			StartsAt:  time.Now().Add(-48 * time.Hour),
+			EndsAt:    time.Now().Add(-time.Hour),
+			TicketRef: "PROJ-1",
+		}
+		am.createdCount = 2
+		ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen, SilenceRef: "silence-1"}
+		ts.remoteLinks["PROJ-1"] = map[string]ticket.RemoteLink{silenceLinkID("silence-1"): {GlobalID: silenceLinkID("silence-1")}}
+
+		// Alertmanager recreates the expired silence under a new ID
+		if _, err := NewSynchronizer(am, ts, cfg).Sync(); err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+		if _, ok := am.silences["silence-2"]; !ok || len(am.silences) != 1 {
+			t.Fatalf("Expected the silence to be replaced by silence-2, got %v", am.silences)
+		}
+		if ref := ts.tickets["PROJ-1"].SilenceRef; ref != "silence-2" {
+			t.Errorf("With history %v: expected the ticket to reference silence-2, got %q", history, ref)
+		}
+		if _, ok := ts.remoteLinks["PROJ-1"][silenceLinkID("silence-2")]; !ok || len(ts.remoteLinks["PROJ-1"]) != 1 {
+			t.Errorf("With history %v: expected the ticket to link only silence-2, got %v", history, ts.remoteLinks["PROJ-1"])
+		}
+		if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "[silence-2]") {
+			t.Errorf("With history %v: expected the comment to refer to silence-2, got %v", history, comments)
+		}
+	}
+}
+
+func TestRevertExtension(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.ExtensionHistory = true
+
+	firstEnd := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		Comment:   "# silence-manager: PROJ-1\nDisk alerts",
+		EndsAt:    firstEnd,
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	secondEnd := firstEnd.Add(24 * time.Hour)
+	if err := sync.extendSilence(am.silences["silence-1"], secondEnd); err != nil {
+		t.Fatalf("extendSilence() failed: %v", err)
+	}
+	if err := sync.extendSilence(am.silences["silence-1"], secondEnd.Add(24*time.Hour)); err != nil {
+		t.Fatalf("extendSilence() failed: %v", err)
+	}
+
+	// Each revert restores the end before one extension
+	for _, want := range []time.Time{secondEnd, firstEnd} {
+		silence, err := sync.RevertExtension("silence-1")
+		if err != nil {
+			t.Fatalf("RevertExtension() failed: %v", err)
+		}
+		if !silence.EndsAt.Equal(want) || !am.silences["silence-1"].EndsAt.Equal(want) {
+			t.Errorf("Expected silence to end at %v, got %v", want, am.silences["silence-1"].EndsAt)
+		}
+	}
+	history, rest := parseExtensionHistory(am.silences["silence-1"].Comment)
+	if history.Extensions != 0 || len(history.PreviousEnds) != 0 || rest != "# silence-manager: PROJ-1\nDisk alerts" {
+		t.Errorf("Unexpected history after reverting: %+v, %q", history, rest)
+	}
+	if len(ts.comments["PROJ-1"]) != 2 || !strings.Contains(ts.comments["PROJ-1"][0], "has been reverted") {
+		t.Errorf("Expected a ticket comment per revert, got %v", ts.comments["PROJ-1"])
+	}
+
+	if _, err := sync.RevertExtension("silence-1"); err == nil || !strings.Contains(err.Error(), "no recorded extension") {
+		t.Errorf("Expected error without a recorded extension, got %v", err)
+	}
+}
+
+func TestRevertExtension_PreviousEndPassed(t *testing.T) {
+	am := newMockAlertManager()
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:      "silence-1",
+		Comment: "silence-manager history: extensions=1 previous-ends=" + past + " synced=" + past,
+		EndsAt:  time.Now().Add(24 * time.Hour),
+	}
+
+	_, err := NewSynchronizer(am, newMockTicketSystem(), DefaultConfig()).RevertExtension("silence-1")
+	if err == nil || !strings.Contains(err.Error(), "delete it instead") {
+		t.Errorf("Expected error for a previous end in the past, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Actions reported to hooks in addition to ActionExtend and ActionDelete
+const (
+	ActionReopen   = "reopen"   // A closed ticket is reopened because its alert refired
+	ActionCreate   = "create"   // A silence is created, with its ticket unless one is given
+	ActionRecreate = "recreate" // A silence is created for a refired alert, an expired silence of an open ticket, or a reopened ticket
+)
+
+// ActionEvent describes an action a synchronization run takes
+type ActionEvent struct {
+	Action    string    // ActionExtend, ActionDelete, ActionReopen, ActionCreate or ActionRecreate
+	SilenceID string    // The silence acted on; for ActionCreate and ActionRecreate only known after the action
+	TicketKey string    // Empty before ActionCreate when the ticket is created along with the silence
+	NewEndsAt time.Time // New end of the silence for ActionExtend, ActionCreate and ActionRecreate
+}
+
+// BeforeActionFunc is called before an action. Returning an error vetoes the action, e.g.
+// to hold extensions during a change freeze. Vetoed extensions, deletions, reopenings and
+// recreations are skipped and logged without counting as errors; a vetoed ActionCreate,
+// requested by a user or a source such as GitOps, fails with the veto.
+type BeforeActionFunc func(event ActionEvent) error
+
+// AfterActionFunc is called after an action with its error, if any
+type AfterActionFunc func(event ActionEvent, err error)
+
+// ActionHooks observes and vetoes each kind of action, e.g. to notify a team channel or
+// hold extensions during a change freeze. The On methods are called before their action
+// and returning an error vetoes it; OnCreate covers ActionCreate and ActionRecreate.
+// AfterAction is called with the outcome of every action that went ahead. Embed NopHooks
+// to implement only some of the methods.
+type ActionHooks interface {
+	OnExtend(event ActionEvent) error
+	OnDelete(event ActionEvent) error
+	OnReopen(event ActionEvent) error
+	OnCreate(event ActionEvent) error
+	AfterAction(event ActionEvent, err error)
+}
+
+// NopHooks allows every action and ignores outcomes
+type NopHooks struct{}
+
+func (NopHooks) OnExtend(ActionEvent) error     { return nil }
+func (NopHooks) OnDelete(ActionEvent) error     { return nil }
+func (NopHooks) OnReopen(ActionEvent) error     { return nil }
+func (NopHooks) OnCreate(ActionEvent) error     { return nil }
+func (NopHooks) AfterAction(ActionEvent, error) {}
+
+// AddActionHooks adds hooks for each kind of action, after those already added
+func (s *Synchronizer) AddActionHooks(hooks ActionHooks) {
+	s.beforeHooks = append(s.beforeHooks, func(event ActionEvent) error {
+		switch event.Action {
+		case ActionExtend:
+			return hooks.OnExtend(event)
+		case ActionDelete:
+			return hooks.OnDelete(event)
+		case ActionReopen:
+			return hooks.OnReopen(event)
+		case ActionCreate, ActionRecreate:
+			return hooks.OnCreate(event)
+		}
+		return nil
+	})
+	s.afterHooks = append(s.afterHooks, hooks.AfterAction)
+}
+
+// beforeAction runs the before hooks and reports whether the action may go ahead
+func (s *Synchronizer) beforeAction(event ActionEvent) bool {
+	if err := s.vetoAction(event); err != nil {
+		log.Printf("Skipping %s of silence %s for ticket %s: %v", event.Action, event.SilenceID, event.TicketKey, err)
+		return false
+	}
+	return true
+}
+
+// deleteManagedSilence deletes a silence for an automated action, such as pruning a
+// removed definition, unless a hook vetoes the deletion. It reports whether the silence
+// was deleted; a vetoed deletion is logged and left for a later run.
+func (s *Synchronizer) deleteManagedSilence(silenceID, ticketKey string) (bool, error) {
+	event := ActionEvent{Action: ActionDelete, SilenceID: silenceID, TicketKey: ticketKey}
+	if !s.beforeAction(event) {
+		return false, nil
+	}
+	err := s.alertManager.DeleteSilence(silenceID)
+	s.afterAction(event, err)
+	return err == nil, err
+}
+
+// vetoAction runs the before hooks and returns the veto of the first hook rejecting the
+// action, for actions requested by a user, who is told why it did not happen
+func (s *Synchronizer) vetoAction(event ActionEvent) error {
+	for _, hook := range s.beforeHooks {
+		if err := hook(event); err != nil {
+			return fmt.Errorf("%s vetoed: %w", event.Action, err)
+		}
+	}
+	return nil
+}
+
+// afterAction records the outcome of an action in the run in progress and runs the after hooks
+func (s *Synchronizer) afterAction(event ActionEvent, err error) {
+	if s.run != nil {
+		outcome := ActionOutcome{Action: event.Action, SilenceID: event.SilenceID, TicketKey: event.TicketKey, NewEndsAt: event.NewEndsAt}
+		if err != nil {
+			outcome.Error = err.Error()
+		}
+		s.run.Actions = append(s.run.Actions, outcome)
+	}
+	for _, hook := range s.afterHooks {
+		hook(event, err)
+	}
+}
@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// recordingHooks vetoes reopenings and creations and records the outcomes
+type recordingHooks struct {
+	NopHooks
+	vetoReopen bool
+	vetoCreate bool
+	done       []ActionEvent
+}
+
+func (h *recordingHooks) OnReopen(ActionEvent) error {
+	if h.vetoReopen {
+		return errors.New("ticket frozen")
+	}
+	return nil
+}
+
+func (h *recordingHooks) OnCreate(ActionEvent) error {
+	if h.vetoCreate {
+		return errors.New("no new silences")
+	}
+	return nil
+}
+
+func (h *recordingHooks) AfterAction(event ActionEvent, err error) {
+	h.done = append(h.done, event)
+}
+
+func TestActionHooks_Reopen(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.alerts = []*alertmanager.Alert{{Labels: map[string]string{"alertname": "TestAlert", "ticket": "PROJ-1"}}}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+
+	hooks := &recordingHooks{vetoReopen: true}
+	sync := New(am, ts, WithActionHooks(hooks))
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(ts.reopenedKeys) != 0 || result.SilencesCreated != 0 || len(result.Errors) != 0 {
+		t.Errorf("Expected the vetoed reopening to be skipped without errors, got %+v", result)
+	}
+
+	// Once allowed, the reopening and the new silence are reported
+	hooks.vetoReopen = false
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(hooks.done) != 2 || hooks.done[0].Action != ActionReopen || hooks.done[1].Action != ActionRecreate {
+		t.Fatalf("Expected reopen and recreate outcomes, got %+v", hooks.done)
+	}
+	if hooks.done[1].SilenceID == "" || hooks.done[1].TicketKey != "PROJ-1" {
+		t.Errorf("Expected the new silence of PROJ-1, got %+v", hooks.done[1])
+	}
+}
+
+func TestActionHooks_CreateVetoed(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	sync := New(am, ts, WithActionHooks(&recordingHooks{vetoCreate: true}))
+
+	matchers, _ := ParseMatchers([]string{"alertname=DiskFull"})
+	_, err := sync.CreateSilence(SilenceDefinition{Matchers: matchers, Duration: time.Hour})
+	if err == nil || !strings.Contains(err.Error(), "no new silences") {
+		t.Errorf("Expected the veto as error, got %v", err)
+	}
+	if len(am.silences) != 0 || len(ts.tickets) != 0 {
+		t.Errorf("Expected nothing to be created, got %d silences and %d tickets", len(am.silences), len(ts.tickets))
+	}
+}
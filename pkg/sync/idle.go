@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// checkIdle counts the alerts a managed silence currently mutes and reports whether it
+// mutes none. An idle silence is a strong sign that the problem is gone and the silence
+// can be allowed to lapse. Silences that have not started yet are never idle.
+func (s *Synchronizer) checkIdle(silence *alertmanager.Silence, result *SyncResult) bool {
+	if silence.StartsAt.After(s.now()) {
+		return false
+	}
+	alerts, err := s.alertManager.GetSilencedAlerts(silence.ID)
+	if err != nil {
+		log.Printf("Warning: failed to get alerts silenced by %s: %v", silence.ID, err)
+		return false
+	}
+	s.metricsPublisher.RecordSilencedAlerts(silence.ID, silence.TicketRef, len(alerts))
+	if len(alerts) > 0 {
+		return false
+	}
+	log.Printf("Silence %s (ticket %s) does not match any alerts", silence.ID, silence.TicketRef)
+	result.Hygiene.IdleSilences++
+	return true
+}
+
+// loadIdle loads the idle silences tracked by earlier runs
+func (s *Synchronizer) loadIdle() error {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	s.idle = make(map[string]state.IdleSilence, len(st.Idle))
+	for id, rec := range st.Idle {
+		s.idle[id] = rec
+	}
+	return nil
+}
+
+// saveIdle stores the idle silences still managed at the end of this run. Silences that
+// have expired or were deleted are forgotten.
+func (s *Synchronizer) saveIdle(result *SyncResult) error {
+	idle := s.idle
+	s.idle = nil
+
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	st.Idle = make(map[string]state.IdleSilence)
+	for _, m := range result.Managed {
+		if rec, ok := idle[m.SilenceID]; ok {
+			st.Idle[m.SilenceID] = rec
+		}
+	}
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// trackIdle records since when a silence has been idle, and forgets it once it mutes
+// alerts again
+func (s *Synchronizer) trackIdle(silence *alertmanager.Silence, tkt *ticket.Ticket, idle bool) {
+	if s.idle == nil {
+		return
+	}
+	if !idle {
+		delete(s.idle, silence.ID)
+		return
+	}
+	if _, ok := s.idle[silence.ID]; !ok {
+		s.idle[silence.ID] = state.IdleSilence{TicketKey: tkt.Key, Since: time.Now()}
+	}
+}
+
+// lapseIdle reports whether a silence due for extension has been idle for
+// ExpireIdleAfter and is left to expire instead. The ticket is told once.
+func (s *Synchronizer) lapseIdle(silence *alertmanager.Silence, tkt *ticket.Ticket, idle bool, result *SyncResult) bool {
+	rec, ok := s.idle[silence.ID]
+	if !idle || !ok || time.Since(rec.Since) < s.config.ExpireIdleAfter {
+		return false
+	}
+	log.Printf("Silence %s has matched no alerts since %s, letting it expire", silence.ID, rec.Since.Format(time.RFC3339))
+	if rec.Notified {
+		return true
+	}
+
+	msg := fmt.Sprintf("Silence %s has not matched any alerts since %s and appears unnecessary. It will not be extended and expires at %s, "+
+		"unless it mutes alerts again before then.",
+		s.silenceRef(silence.ID), rec.Since.Format(time.RFC3339), silence.EndsAt.Format(time.RFC3339))
+	s.addComment(tkt.Key, msg)
+	rec.Notified = true
+	s.idle[silence.ID] = rec
+	result.IdleSilencesLapsed++
+	return true
+}
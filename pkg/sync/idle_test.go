@@ -0,0 +1,136 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestSync_IdleSilences(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	for _, id := range []string{"busy", "idle", "pending"} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: "PROJ-" + id,
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(72 * time.Hour),
+		}
+		ts.tickets["PROJ-"+id] = &ticket.Ticket{Key: "PROJ-" + id, Status: ticket.StatusOpen}
+	}
+	am.silences["pending"].StartsAt = now.Add(time.Hour)
+	am.silenced = map[string][]*alertmanager.Alert{
+		"busy": {{Labels: map[string]string{"alertname": "DiskFull"}}},
+	}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.CheckSilencedAlerts = true
+	result, err := NewSynchronizer(am, ts, cfg).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if result.Hygiene.IdleSilences != 1 {
+		t.Errorf("Expected 1 idle silence, got %d", result.Hygiene.IdleSilences)
+	}
+	for _, m := range result.Managed {
+		if want := m.SilenceID == "idle"; m.Idle != want {
+			t.Errorf("Expected silence %s idle=%v, got %v", m.SilenceID, want, m.Idle)
+		}
+	}
+	if len(am.deletedIDs) != 0 || len(am.extendedIDs) != 0 {
+		t.Errorf("Expected idle silences to be left alone, deleted %v extended %v", am.deletedIDs, am.extendedIDs)
+	}
+}
+
+func TestSync_IdleSilencesDisabled(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["idle"] = &alertmanager.Silence{
+		ID:        "idle",
+		TicketRef: "PROJ-1",
+		StartsAt:  time.Now().Add(-time.Hour),
+		EndsAt:    time.Now().Add(72 * time.Hour),
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	result, err := NewSynchronizer(am, ts, DefaultConfig()).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.Hygiene.IdleSilences != 0 || result.Managed[0].Idle {
+		t.Errorf("Expected no idle check unless enabled, got %+v", result.Managed)
+	}
+}
+
+func TestSync_IdleSilencesLapse(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	now := time.Now()
+	for _, id := range []string{"busy", "idle", "new"} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: "PROJ-" + id,
+			StartsAt:  now.Add(-96 * time.Hour),
+			EndsAt:    now.Add(time.Hour),
+		}
+		ts.tickets["PROJ-"+id] = &ticket.Ticket{Key: "PROJ-" + id, Status: ticket.StatusOpen}
+	}
+	am.silenced = map[string][]*alertmanager.Alert{
+		"busy": {{Labels: map[string]string{"alertname": "DiskFull"}}},
+	}
+	st := state.NewState()
+	st.Idle["busy"] = state.IdleSilence{TicketKey: "PROJ-busy", Since: now.Add(-96 * time.Hour)}
+	st.Idle["idle"] = state.IdleSilence{TicketKey: "PROJ-idle", Since: now.Add(-96 * time.Hour)}
+	st.Idle["gone"] = state.IdleSilence{TicketKey: "PROJ-gone", Since: now.Add(-96 * time.Hour)}
+	if err := store.Save(st); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.CheckSilencedAlerts = true
+	cfg.ExpireIdleAfter = 72 * time.Hour
+	for run := 0; run < 2; run++ {
+		result, err := New(am, ts, WithConfig(cfg), WithStateStore(store)).Sync()
+		if err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+		if want := 1 - run; result.IdleSilencesLapsed != want {
+			t.Errorf("Run %d: expected %d lapsed silence(s), got %d", run, want, result.IdleSilencesLapsed)
+		}
+	}
+
+	// Silences muting alerts and silences only just found idle are extended
+	if am.silences["idle"].EndsAt.After(now.Add(time.Hour)) {
+		t.Errorf("Expected the idle silence not to be extended")
+	}
+	for _, id := range []string{"busy", "new"} {
+		if !am.silences[id].EndsAt.After(now.Add(time.Hour)) {
+			t.Errorf("Expected silence %s to be extended", id)
+		}
+	}
+	if comments := ts.comments["PROJ-idle"]; len(comments) != 1 || !strings.Contains(comments[0], "appears unnecessary") {
+		t.Errorf("Expected one comment on the idle silence's ticket, got %v", comments)
+	}
+
+	st, _ = store.Load()
+	if rec, ok := st.Idle["idle"]; !ok || !rec.Notified {
+		t.Errorf("Expected the lapsing silence to be remembered as notified, got %+v", st.Idle)
+	}
+	if _, ok := st.Idle["new"]; !ok {
+		t.Errorf("Expected the newly idle silence to be tracked, got %+v", st.Idle)
+	}
+	for _, id := range []string{"busy", "gone"} {
+		if _, ok := st.Idle[id]; ok {
+			t.Errorf("Expected silence %s to be forgotten, got %+v", id, st.Idle)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// SilenceDefinition describes a silence to import. Definitions are read from YAML or
+// from the JSON printed by "amtool silence query -o json".
+type SilenceDefinition struct {
+	Matchers  []alertmanager.Matcher
+	Comment   string
+	CreatedBy string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Duration  time.Duration // Used when EndsAt is not set
+	Ticket    string        // Existing ticket to link; a ticket is created when empty
+	Summary   string        // Summary of the created ticket
+	// Description of the created ticket, after the generated first line; defaults to Comment
+	Description string
+}
+
+// rawSilenceDefinition is the serialized form of a SilenceDefinition. Matchers are either
+// strings such as `instance=~"web-.*"` or amtool matcher objects.
+type rawSilenceDefinition struct {
+	Matchers  []json.RawMessage `json:"matchers"`
+	Comment   string            `json:"comment"`
+	CreatedBy string            `json:"createdBy"`
+	StartsAt  *time.Time        `json:"startsAt"`
+	EndsAt    *time.Time        `json:"endsAt"`
+	Duration  string            `json:"duration"`
+	Ticket    string            `json:"ticket"`
+	Summary   string            `json:"summary"`
+	Status    *struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+type rawMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual *bool  `json:"isEqual"`
+}
+
+// ParseSilenceDefinitions parses silence definitions from YAML or amtool JSON. The
+// document is either a list of definitions or an object with a "silences" list.
+// Expired silences in amtool output are skipped.
+func ParseSilenceDefinitions(data []byte) ([]SilenceDefinition, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse silence definitions: %w", err)
+	}
+
+	var raws []rawSilenceDefinition
+	if err := json.Unmarshal(jsonData, &raws); err != nil {
+		var doc struct {
+			Silences []rawSilenceDefinition `json:"silences"`
+		}
+		if err := json.Unmarshal(jsonData, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse silence definitions: %w", err)
+		}
+		raws = doc.Silences
+	}
+
+	defs := make([]SilenceDefinition, 0, len(raws))
+	for i, raw := range raws {
+		if raw.Status != nil && raw.Status.State == "expired" {
+			continue
+		}
+		def, err := raw.definition()
+		if err != nil {
+			return nil, fmt.Errorf("silence %d: %w", i+1, err)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func (r rawSilenceDefinition) definition() (SilenceDefinition, error) {
+	def := SilenceDefinition{
+		Comment:   r.Comment,
+		CreatedBy: r.CreatedBy,
+		Ticket:    r.Ticket,
+		Summary:   r.Summary,
+	}
+	if r.StartsAt != nil {
+		def.StartsAt = *r.StartsAt
+	}
+	if r.EndsAt != nil {
+		def.EndsAt = *r.EndsAt
+	}
+	if r.Duration != "" {
+		d, err := parseDirectiveDuration(r.Duration)
+		if err != nil {
+			return def, fmt.Errorf("invalid duration %q: %w", r.Duration, err)
+		}
+		def.Duration = d
+	}
+
+	for _, rm := range r.Matchers {
+		var text string
+		if err := json.Unmarshal(rm, &text); err == nil {
+			m, err := parseMatcher(text)
+			if err != nil {
+				return def, err
+			}
+			def.Matchers = append(def.Matchers, m)
+			continue
+		}
+
+		var obj rawMatcher
+		if err := json.Unmarshal(rm, &obj); err != nil || obj.Name == "" {
+			return def, fmt.Errorf("invalid matcher %s", string(rm))
+		}
+		m := alertmanager.Matcher{Name: obj.Name, Value: obj.Value, IsRegex: obj.IsRegex, IsEqual: true}
+		if obj.IsEqual != nil {
+			m.IsEqual = *obj.IsEqual
+		}
+		def.Matchers = append(def.Matchers, m)
+	}
+	if len(def.Matchers) == 0 {
+		return def, fmt.Errorf("at least one matcher is required")
+	}
+
+	return def, nil
+}
+
+// ImportResult contains the results of importing silence definitions
+type ImportResult struct {
+	SilencesCreated int
+	TicketsCreated  int
+	// Skipped counts definitions matching an existing active silence or already expired
+	Skipped int
+	Errors  []error
+}
+
+// ImportSilences creates a silence with a linked ticket for each definition, so that the
+// silence is managed by subsequent sync runs. Definitions whose matchers equal those of an
+// active silence are skipped, so an import can be repeated safely. With dryRun, nothing
+// is created.
+func (s *Synchronizer) ImportSilences(defs []SilenceDefinition, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{Errors: make([]error, 0)}
+
+	existing, err := s.alertManager.ListSilences()
+	if err != nil {
+		return result, fmt.Errorf("failed to list silences: %w", err)
+	}
+	active := make(map[string]string, len(existing))
+	for _, silence := range existing {
+		active[matcherKey(silence.Matchers)] = silence.ID
+	}
+
+	now := s.now()
+	for i := range defs {
+		def := &defs[i]
+		key := matcherKey(def.Matchers)
+		if id, ok := active[key]; ok {
+			log.Printf("Skipping silence for %s: matches active silence %s", formatMatchers(def.Matchers), id)
+			result.Skipped++
+			continue
+		}
+
+		startsAt, endsAt := s.importWindow(def, now)
+		if !endsAt.After(now) {
+			log.Printf("Skipping silence for %s: already expired at %v", formatMatchers(def.Matchers), endsAt)
+			result.Skipped++
+			continue
+		}
+
+		if dryRun {
+			ticketRef := def.Ticket
+			if ticketRef == "" {
+				ticketRef = "(new ticket)"
+				result.TicketsCreated++
+			}
+			log.Printf("Would create silence for %s until %v linked to %s", formatMatchers(def.Matchers), endsAt.Format(time.RFC3339), ticketRef)
+			result.SilencesCreated++
+			active[key] = ""
+			continue
+		}
+
+		silenceID, err := s.importSilence(def, startsAt, endsAt, result)
+		if err != nil {
+			log.Printf("Error importing silence for %s: %v", formatMatchers(def.Matchers), err)
+			result.Errors = append(result.Errors, fmt.Errorf("import %s: %w", formatMatchers(def.Matchers), err))
+			continue
+		}
+		active[key] = silenceID
+		result.SilencesCreated++
+	}
+
+	return result, nil
+}
+
+// importWindow returns the start and end time of an imported silence
+func (s *Synchronizer) importWindow(def *SilenceDefinition, now time.Time) (time.Time, time.Time) {
+	startsAt := def.StartsAt
+	if startsAt.Before(now) {
+		startsAt = now
+	}
+	if !def.EndsAt.IsZero() {
+		return startsAt, def.EndsAt
+	}
+	duration := def.Duration
+	if duration == 0 {
+		duration = s.config.DefaultSilenceDuration
+	}
+	return startsAt, s.endTime(startsAt.Sub(now) + duration)
+}
+
+// importSilence links or creates the ticket for an imported definition and creates its silence
+func (s *Synchronizer) importSilence(def *SilenceDefinition, startsAt, endsAt time.Time, result *ImportResult) (string, error) {
+	silence, ticketCreated, err := s.createLinkedSilence(def, startsAt, endsAt, "Imported")
+	if ticketCreated {
+		result.TicketsCreated++
+	}
+	if err != nil {
+		return "", err
+	}
+	return silence.ID, nil
+}
+
+// createLinkedSilence links or creates the ticket for a definition and creates its silence.
+// origin ("Imported", "Created") describes how the silence came about in the ticket and
+// silence comments. It also reports whether a ticket was created, even on error.
+func (s *Synchronizer) createLinkedSilence(def *SilenceDefinition, startsAt, endsAt time.Time, origin string) (*alertmanager.Silence, bool, error) {
+	event := ActionEvent{Action: ActionCreate, TicketKey: def.Ticket, NewEndsAt: endsAt}
+	if err := s.vetoAction(event); err != nil {
+		return nil, false, err
+	}
+
+	ticketCreated := false
+	key := def.Ticket
+	if key != "" {
+		if _, err := s.ticketSystem.GetTicket(key); err != nil {
+			return nil, false, fmt.Errorf("failed to get ticket %s: %w", key, err)
+		}
+	} else {
+		summary := def.Summary
+		if summary == "" {
+			summary = fmt.Sprintf("Silence: %s", formatMatchers(def.Matchers))
+		}
+		description := def.Description
+		if description == "" {
+			description = def.Comment
+		}
+		var err error
+		key, err = s.ticketSystem.CreateTicket(&ticket.Ticket{
+			Summary:     summary,
+			Description: fmt.Sprintf("%s silence for %s.\n\n%s", origin, formatMatchers(def.Matchers), description),
+			Project:     teamProject(s.teamOfMatchers(def.Matchers)),
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create ticket: %w", err)
+		}
+		ticketCreated = true
+		s.addWatchers(key)
+	}
+
+	createdBy := def.CreatedBy
+	if createdBy == "" {
+		createdBy = "silence-manager"
+	}
+	comment := def.Comment
+	if comment == "" {
+		comment = origin + " by silence-manager"
+	}
+
+	silence := &alertmanager.Silence{
+		CreatedBy: createdBy,
+		Comment:   comment,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Matchers:  def.Matchers,
+		TicketRef: key,
+	}
+	silenceID, err := s.alertManager.CreateSilence(silence)
+	event.SilenceID, event.TicketKey = silenceID, key
+	s.afterAction(event, err)
+	if err != nil {
+		return nil, ticketCreated, fmt.Errorf("failed to create silence for ticket %s: %w", key, err)
+	}
+
+	log.Printf("%s silence %s linked to ticket %s", origin, silenceID, key)
+	s.updateDueDate(key, endsAt)
+	s.linkSilence(key, silenceID)
+	silence.ID = silenceID
+	msg := fmt.Sprintf("Silence %s %s for %s, expiring at %s.", silenceID, strings.ToLower(origin), formatMatchers(def.Matchers), endsAt.Format(time.RFC3339))
+	if err := s.ticketSystem.AddComment(key, msg+silenceDefinition(silence)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+	return silence, ticketCreated, nil
+}
+
+// matcherKey returns a key identifying a set of matchers regardless of their order
+func matcherKey(matchers []alertmanager.Matcher) string {
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		parts = append(parts, formatMatchers([]alertmanager.Matcher{m}))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,164 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestParseSilenceDefinitions_YAML(t *testing.T) {
+	data := []byte(`
+silences:
+  - matchers:
+      - alertname=DiskFull
+      - instance=~"db-.*"
+    comment: Disk replacement pending
+    createdBy: alice
+    duration: 3d
+    ticket: OPS-12
+  - matchers: ["team=network"]
+    endsAt: 2030-01-02T15:04:05Z
+    summary: Network migration
+`)
+
+	defs, err := ParseSilenceDefinitions(data)
+	if err != nil {
+		t.Fatalf("ParseSilenceDefinitions() failed: %v", err)
+	}
+	if len(defs) != 2 {
+		t.Fatalf("Expected 2 definitions, got %d", len(defs))
+	}
+
+	first := defs[0]
+	if len(first.Matchers) != 2 || !first.Matchers[1].IsRegex || first.Matchers[1].Value != "db-.*" {
+		t.Errorf("Unexpected matchers: %+v", first.Matchers)
+	}
+	if first.Duration != 3*24*time.Hour || first.Ticket != "OPS-12" || first.CreatedBy != "alice" {
+		t.Errorf("Unexpected definition: %+v", first)
+	}
+	if !defs[1].EndsAt.Equal(time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("Unexpected end time: %v", defs[1].EndsAt)
+	}
+}
+
+func TestParseSilenceDefinitions_Amtool(t *testing.T) {
+	data := []byte(`[
+  {
+    "id": "abc",
+    "matchers": [
+      {"name": "alertname", "value": "HighLatency", "isRegex": false, "isEqual": true},
+      {"name": "env", "value": "staging", "isRegex": false, "isEqual": false}
+    ],
+    "startsAt": "2030-01-01T00:00:00Z",
+    "endsAt": "2030-01-08T00:00:00Z",
+    "createdBy": "bob",
+    "comment": "Known issue",
+    "status": {"state": "active"}
+  },
+  {
+    "id": "def",
+    "matchers": [{"name": "alertname", "value": "Old", "isRegex": false}],
+    "endsAt": "2020-01-01T00:00:00Z",
+    "status": {"state": "expired"}
+  }
+]`)
+
+	defs, err := ParseSilenceDefinitions(data)
+	if err != nil {
+		t.Fatalf("ParseSilenceDefinitions() failed: %v", err)
+	}
+	if len(defs) != 1 {
+		t.Fatalf("Expected expired silence to be skipped, got %d definitions", len(defs))
+	}
+	if defs[0].Matchers[1].IsEqual {
+		t.Error("Expected env matcher to be a negative match")
+	}
+	if defs[0].Comment != "Known issue" || defs[0].CreatedBy != "bob" {
+		t.Errorf("Unexpected definition: %+v", defs[0])
+	}
+}
+
+func TestParseSilenceDefinitions_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no matchers":      "- comment: nothing to match\n",
+		"invalid matcher":  "- matchers: [\"not a matcher\"]\n",
+		"invalid duration": "- matchers: [\"a=b\"]\n  duration: forever\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseSilenceDefinitions([]byte(data)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestImportSilences(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	ts.tickets["OPS-12"] = &ticket.Ticket{Key: "OPS-12", Status: ticket.StatusOpen}
+
+	// An active silence with the same matchers in a different order
+	am.silences["existing"] = &alertmanager.Silence{
+		ID:        "existing",
+		EndsAt:    time.Now().Add(time.Hour),
+		Matchers:  []alertmanager.Matcher{{Name: "team", Value: "network", IsEqual: true}, {Name: "env", Value: "prod", IsEqual: true}},
+		TicketRef: "OPS-1",
+	}
+
+	defs := []SilenceDefinition{
+		{Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}}, Ticket: "OPS-12", Duration: 72 * time.Hour},
+		{Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "HighLatency", IsEqual: true}}, Comment: "Known issue"},
+		{Matchers: []alertmanager.Matcher{{Name: "env", Value: "prod", IsEqual: true}, {Name: "team", Value: "network", IsEqual: true}}},
+		{Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "Old", IsEqual: true}}, EndsAt: time.Now().Add(-time.Hour)},
+	}
+
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+
+	// A dry run creates nothing
+	result, err := sync.ImportSilences(defs, true)
+	if err != nil {
+		t.Fatalf("ImportSilences() failed: %v", err)
+	}
+	if result.SilencesCreated != 2 || len(am.silences) != 1 || len(ts.tickets) != 1 {
+		t.Errorf("Expected dry run to plan 2 silences and create none, planned=%d silences=%d tickets=%d",
+			result.SilencesCreated, len(am.silences), len(ts.tickets))
+	}
+
+	result, err = sync.ImportSilences(defs, false)
+	if err != nil {
+		t.Fatalf("ImportSilences() failed: %v", err)
+	}
+	if result.SilencesCreated != 2 || result.TicketsCreated != 1 || result.Skipped != 2 {
+		t.Errorf("Expected 2 created, 1 ticket and 2 skipped, got %+v", result)
+	}
+
+	var linked []string
+	for _, silence := range am.silences {
+		if silence.ID != "existing" {
+			linked = append(linked, silence.TicketRef)
+			if silence.EndsAt.Before(time.Now().Add(71 * time.Hour)) {
+				t.Errorf("Expected silence %s to last at least 72 hours, ends at %v", silence.ID, silence.EndsAt)
+			}
+		}
+	}
+	if len(linked) != 2 {
+		t.Fatalf("Expected 2 imported silences, got %d", len(linked))
+	}
+	for _, ref := range linked {
+		if ref == "" {
+			t.Error("Expected every imported silence to be linked to a ticket")
+		}
+	}
+
+	// Importing again is a no-op
+	result, err = sync.ImportSilences(defs, false)
+	if err != nil {
+		t.Fatalf("ImportSilences() failed: %v", err)
+	}
+	if result.SilencesCreated != 0 || result.Skipped != 4 {
+		t.Errorf("Expected repeated import to skip every definition, got %+v", result)
+	}
+}
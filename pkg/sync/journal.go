@@ -0,0 +1,344 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+)
+
+// runIDFormat formats the start time of a run into its ID
+const runIDFormat = "20060102T150405Z"
+
+// runJournal records the state of the silences a run deletes or changes before each
+// action, so that the run can be rolled back
+type runJournal struct {
+	run state.JournalRun
+	// seen holds the silences as last read or written during the run, by ID
+	seen map[string]alertmanager.Silence
+}
+
+// journalAlertManager passes requests to the wrapped Alertmanager client, recording the
+// prior state of deleted and changed silences in the journal of the run
+type journalAlertManager struct {
+	alertmanager.AlertManager
+	journal *runJournal
+}
+
+// journaled wraps am to record the run's deletions and changes, if journaling is enabled
+func (s *Synchronizer) journaled(am alertmanager.AlertManager) alertmanager.AlertManager {
+	if s.journal == nil {
+		return am
+	}
+	return &journalAlertManager{AlertManager: am, journal: s.journal}
+}
+
+func (j *journalAlertManager) GetSilence(id string) (*alertmanager.Silence, error) {
+	silence, err := j.AlertManager.GetSilence(id)
+	if err == nil {
+		j.journal.seen[id] = *silence
+	}
+	return silence, err
+}
+
+func (j *journalAlertManager) ListSilences() ([]*alertmanager.Silence, error) {
+	silences, err := j.AlertManager.ListSilences()
+	for _, silence := range silences {
+		j.journal.seen[silence.ID] = *silence
+	}
+	return silences, err
+}
+
+func (j *journalAlertManager) UpdateSilence(silence *alertmanager.Silence) error {
+	id := silence.ID
+	prior, ok := j.prior(id)
+	if err := j.AlertManager.UpdateSilence(silence); err != nil {
+		return err
+	}
+	if ok {
+		action := state.JournalUpdate
+		if formatMatchers(prior.Matchers) == formatMatchers(silence.Matchers) && prior.TicketRef == silence.TicketRef {
+			action = state.JournalExtend
+		}
+		j.record(action, prior, silence.EndsAt)
+		if silence.ID != id {
+			j.journal.run.Entries[len(j.journal.run.Entries)-1].NewSilenceID = silence.ID
+		}
+	}
+	delete(j.journal.seen, id)
+	j.journal.seen[silence.ID] = *silence
+	return nil
+}
+
+func (j *journalAlertManager) DeleteSilence(id string) error {
+	prior, ok := j.prior(id)
+	if err := j.AlertManager.DeleteSilence(id); err != nil {
+		return err
+	}
+	if ok {
+		j.record(state.JournalDelete, prior, time.Time{})
+	}
+	delete(j.journal.seen, id)
+	return nil
+}
+
+func (j *journalAlertManager) ExtendSilence(id string, newEndTime time.Time) error {
+	prior, ok := j.prior(id)
+	if err := j.AlertManager.ExtendSilence(id, newEndTime); err != nil {
+		return err
+	}
+	if ok {
+		j.record(state.JournalExtend, prior, newEndTime)
+		prior.EndsAt = newEndTime
+		j.journal.seen[id] = prior
+	}
+	return nil
+}
+
+// prior returns the silence before an action, looking it up if the run has not seen it
+func (j *journalAlertManager) prior(id string) (alertmanager.Silence, bool) {
+	if silence, ok := j.journal.seen[id]; ok {
+		return silence, true
+	}
+	silence, err := j.AlertManager.GetSilence(id)
+	if err != nil {
+		log.Printf("Warning: failed to journal silence %s, the action cannot be rolled back: %v", id, err)
+		return alertmanager.Silence{}, false
+	}
+	return *silence, true
+}
+
+// record adds an action on the prior silence to the journal
+func (j *journalAlertManager) record(action string, prior alertmanager.Silence, newEndsAt time.Time) {
+	entry := state.JournalEntry{
+		Action:    action,
+		SilenceID: prior.ID,
+		CreatedBy: prior.CreatedBy,
+		Comment:   prior.Comment,
+		TicketRef: prior.TicketRef,
+		StartsAt:  prior.StartsAt,
+		EndsAt:    prior.EndsAt,
+		NewEndsAt: newEndsAt,
+	}
+	for _, matcher := range prior.Matchers {
+		entry.Matchers = append(entry.Matchers, formatMatchers([]alertmanager.Matcher{matcher}))
+	}
+	j.journal.run.Entries = append(j.journal.run.Entries, entry)
+}
+
+// startJournal starts the journal of a run started at started, if journaling is enabled,
+// and returns the run's ID
+func (s *Synchronizer) startJournal(started time.Time) string {
+	if s.config.JournalRetention <= 0 {
+		return ""
+	}
+	id := started.UTC().Format(runIDFormat)
+	s.journal = &runJournal{
+		run:  state.JournalRun{ID: id, StartedAt: started},
+		seen: make(map[string]alertmanager.Silence),
+	}
+	return id
+}
+
+// saveJournal persists the journal of the run, unless it took no undoable actions
+func (s *Synchronizer) saveJournal() error {
+	journal := s.journal
+	s.journal = nil
+	if journal == nil || len(journal.run.Entries) == 0 {
+		return nil
+	}
+
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	st.AddJournalRun(journal.run, s.config.JournalRetention)
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	log.Printf("Journaled %d action(s) of run %s for rollback", len(journal.run.Entries), journal.run.ID)
+	return nil
+}
+
+// JournalRuns returns the journaled runs, oldest first
+func (s *Synchronizer) JournalRuns() ([]state.JournalRun, error) {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	return st.Journal, nil
+}
+
+// RolledBackAction describes how a journaled action was rolled back
+type RolledBackAction struct {
+	Entry state.JournalEntry
+	// SilenceID is the silence restored or reverted; restored silences get a new ID
+	SilenceID string
+	EndsAt    time.Time
+	// Skipped explains why the action was not rolled back, e.g. because the silence was
+	// changed since
+	Skipped string
+}
+
+// RollbackRun undoes the deletions and changes of a journaled run, latest first. Deleted
+// silences that would still be active are recreated and linked to their tickets again,
+// and extended or updated silences get back their previous end, matchers and comment,
+// or are expired if their previous end has passed. Silences changed since the run, and
+// deleted silences whose matchers an active silence already has, are skipped. The
+// actions rolled back are returned, also when an error stops the rollback; they are
+// recorded, so that rolling back the run again resumes after them.
+func (s *Synchronizer) RollbackRun(id string) ([]RolledBackAction, error) {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	run, ok := st.FindJournalRun(id)
+	if !ok {
+		return nil, fmt.Errorf("no journal of run %s", id)
+	}
+	if !run.RolledBack.IsZero() {
+		return nil, fmt.Errorf("run %s was already rolled back at %s", id, run.RolledBack.Format(time.RFC3339))
+	}
+
+	active, err := s.alertManager.ListSilences()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	activeMatchers := make(map[string]bool, len(active))
+	for _, silence := range active {
+		activeMatchers[matcherKey(silence.Matchers)] = true
+	}
+
+	now := s.now()
+	actions := make([]RolledBackAction, 0, len(run.Entries))
+	for i := len(run.Entries) - 1; i >= 0; i-- {
+		entry := run.Entries[i]
+		if !entry.RolledBack.IsZero() {
+			continue
+		}
+		action, err := s.rollbackEntry(run.ID, entry, activeMatchers, now)
+		if err != nil {
+			err = fmt.Errorf("failed to roll back %s of silence %s: %w", entry.Action, entry.SilenceID, err)
+			if saveErr := s.stateStore.Save(st); saveErr != nil {
+				log.Printf("Warning: failed to record the partial rollback of run %s: %v", run.ID, saveErr)
+			}
+			return actions, err
+		}
+		run.Entries[i].RolledBack = now
+		actions = append(actions, action)
+	}
+
+	run.RolledBack = now
+	if err := s.stateStore.Save(st); err != nil {
+		return actions, fmt.Errorf("failed to save state: %w", err)
+	}
+	// The state loaded above predates the fingerprints of the silences rolled back
+	if err := s.saveFingerprints(); err != nil {
+		log.Printf("Warning: failed to save silence fingerprints: %v", err)
+	}
+	return actions, nil
+}
+
+// rollbackEntry undoes a journaled action
+func (s *Synchronizer) rollbackEntry(runID string, entry state.JournalEntry, activeMatchers map[string]bool, now time.Time) (RolledBackAction, error) {
+	action := RolledBackAction{Entry: entry, SilenceID: entry.SilenceID}
+	matchers := make([]alertmanager.Matcher, 0, len(entry.Matchers))
+	for _, value := range entry.Matchers {
+		m, err := parseMatcher(value)
+		if err != nil {
+			return action, fmt.Errorf("invalid journaled matcher: %w", err)
+		}
+		matchers = append(matchers, m)
+	}
+
+	if entry.Action == state.JournalDelete {
+		switch {
+		case !entry.EndsAt.After(now):
+			action.Skipped = fmt.Sprintf("it would have ended at %s", entry.EndsAt.Format(time.RFC3339))
+			return action, nil
+		case activeMatchers[matcherKey(matchers)]:
+			action.Skipped = "an active silence has the same matchers"
+			return action, nil
+		}
+		silence := &alertmanager.Silence{
+			ID:        entry.SilenceID,
+			CreatedBy: entry.CreatedBy,
+			Comment:   entry.Comment,
+			StartsAt:  entry.StartsAt,
+			EndsAt:    entry.EndsAt,
+			TicketRef: entry.TicketRef,
+			Matchers:  matchers,
+		}
+		silenceID, err := s.restoreSilence(silence, now, fmt.Sprintf("was deleted by run %s and has been restored by rolling back the run", runID))
+		if err != nil {
+			return action, err
+		}
+		activeMatchers[matcherKey(matchers)] = true
+		action.SilenceID, action.EndsAt = silenceID, entry.EndsAt
+		return action, nil
+	}
+
+	// An update may have replaced the silence under a new ID
+	if entry.NewSilenceID != "" {
+		action.SilenceID = entry.NewSilenceID
+	}
+	current, err := s.alertManager.GetSilence(action.SilenceID)
+	switch {
+	case errors.Is(err, alertmanager.ErrSilenceNotFound):
+		action.Skipped = "the silence no longer exists"
+		return action, nil
+	case err != nil:
+		return action, err
+	case !current.EndsAt.After(now):
+		action.Skipped = "the silence has ended"
+		return action, nil
+	case !current.EndsAt.Equal(entry.NewEndsAt):
+		action.Skipped = "the silence was changed since"
+		return action, nil
+	}
+
+	if !entry.EndsAt.After(now) {
+		if err := s.alertManager.DeleteSilence(current.ID); err != nil {
+			return action, err
+		}
+		action.EndsAt = now
+		s.rollbackComment(current.TicketRef, fmt.Sprintf("Rolled back the %s of silence %s by run %s. The silence would have ended at %s, so it has been expired.",
+			entry.Action, current.ID, runID, entry.EndsAt.Format(time.RFC3339)))
+		return action, nil
+	}
+
+	previousID := current.ID
+	current.EndsAt = entry.EndsAt
+	if entry.Action == state.JournalUpdate {
+		current.Matchers, current.Comment, current.TicketRef = matchers, entry.Comment, entry.TicketRef
+	}
+	if err := s.alertManager.UpdateSilence(current); err != nil {
+		return action, err
+	}
+	action.SilenceID, action.EndsAt = current.ID, entry.EndsAt
+	msg := fmt.Sprintf("Rolled back the %s of silence %s by run %s. The silence now expires at %s again.",
+		entry.Action, previousID, runID, entry.EndsAt.Format(time.RFC3339))
+	// Restoring the matchers replaces the silence under a new ID
+	if current.ID != previousID {
+		msg += fmt.Sprintf(" It has been replaced by silence %s.", s.silenceRef(current.ID))
+		if current.TicketRef != "" {
+			s.followRenewedSilenceOf(current.TicketRef, previousID, current.ID)
+		}
+	}
+	s.rollbackComment(current.TicketRef, msg)
+	return action, nil
+}
+
+// rollbackComment tells a ticket that an action on its silence was rolled back
+func (s *Synchronizer) rollbackComment(key, msg string) {
+	if key == "" {
+		return
+	}
+	log.Print(msg)
+	if err := s.ticketSystem.AddComment(key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+}
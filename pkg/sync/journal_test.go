@@ -0,0 +1,208 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestRollbackRun(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.JournalRetention = 7 * 24 * time.Hour
+
+	deletedEnd := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	extendedEnd := time.Now().Add(12 * time.Hour).Truncate(time.Second)
+	am.silences["s-deleted"] = &alertmanager.Silence{
+		ID:        "s-deleted",
+		CreatedBy: "alice",
+		Comment:   "Disk replacement",
+		StartsAt:  time.Now().Add(-time.Hour),
+		EndsAt:    deletedEnd,
+		TicketRef: "PROJ-1",
+		Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}},
+	}
+	am.silences["s-extended"] = &alertmanager.Silence{
+		ID:        "s-extended",
+		StartsAt:  time.Now().Add(-time.Hour),
+		EndsAt:    extendedEnd,
+		TicketRef: "PROJ-2",
+		Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "HighLatency", IsEqual: true}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusResolved, SilenceRef: "s-deleted"}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusOpen}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.RunID == "" || result.SilencesDeleted != 1 || result.SilencesExtended != 1 {
+		t.Fatalf("Unexpected run %q: deleted=%d extended=%d", result.RunID, result.SilencesDeleted, result.SilencesExtended)
+	}
+
+	runs, err := sync.JournalRuns()
+	if err != nil {
+		t.Fatalf("JournalRuns() failed: %v", err)
+	}
+	if len(runs) != 1 || len(runs[0].Entries) != 2 {
+		t.Fatalf("Expected one journaled run with two entries, got %+v", runs)
+	}
+
+	actions, err := sync.RollbackRun(result.RunID)
+	if err != nil {
+		t.Fatalf("RollbackRun() failed: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("Expected two actions rolled back, got %+v", actions)
+	}
+	for _, action := range actions {
+		if action.Skipped != "" {
+			t.Errorf("Expected %s of %s to be rolled back, skipped because %s", action.Entry.Action, action.Entry.SilenceID, action.Skipped)
+		}
+	}
+
+	if got := am.silences["s-extended"].EndsAt; !got.Equal(extendedEnd) {
+		t.Errorf("Expected the extension to be reverted to %v, got %v", extendedEnd, got)
+	}
+	var restored *alertmanager.Silence
+	for _, silence := range am.silences {
+		if silence.TicketRef == "PROJ-1" {
+			restored = silence
+		}
+	}
+	if restored == nil || restored.Comment != "Disk replacement" || !restored.EndsAt.Equal(deletedEnd) {
+		t.Fatalf("Expected the deleted silence to be restored, got %+v", restored)
+	}
+	if ref := ts.tickets["PROJ-1"].SilenceRef; ref != restored.ID {
+		t.Errorf("Expected PROJ-1 to reference the restored silence %s, got %s", restored.ID, ref)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) == 0 || !strings.Contains(comments[len(comments)-1], "rolling back the run") {
+		t.Errorf("Expected a rollback comment on PROJ-1, got %v", comments)
+	}
+
+	if _, err := sync.RollbackRun(result.RunID); err == nil || !strings.Contains(err.Error(), "already rolled back") {
+		t.Errorf("Expected a second rollback to be refused, got %v", err)
+	}
+}
+
+func TestRollbackRun_SkipsChangedSilences(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	st, _ := store.Load()
+	st.AddJournalRun(state.JournalRun{
+		ID:        "20261016T120000Z",
+		StartedAt: time.Now().Add(-time.Hour),
+		Entries: []state.JournalEntry{
+			{Action: state.JournalDelete, SilenceID: "s-1", Matchers: []string{"alertname=Ended"}, EndsAt: time.Now().Add(-time.Minute)},
+			{Action: state.JournalDelete, SilenceID: "s-2", Matchers: []string{"alertname=Recreated"}, EndsAt: time.Now().Add(time.Hour)},
+			{Action: state.JournalExtend, SilenceID: "s-3", EndsAt: time.Now().Add(time.Hour), NewEndsAt: time.Now().Add(24 * time.Hour)},
+		},
+	}, 7*24*time.Hour)
+	am.silences["s-4"] = &alertmanager.Silence{ID: "s-4", EndsAt: time.Now().Add(time.Hour),
+		Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "Recreated", IsEqual: true}}}
+	am.silences["s-3"] = &alertmanager.Silence{ID: "s-3", EndsAt: time.Now().Add(48 * time.Hour)}
+
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+	sync.SetStateStore(store)
+	actions, err := sync.RollbackRun("20261016T120000Z")
+	if err != nil {
+		t.Fatalf("RollbackRun() failed: %v", err)
+	}
+	for _, action := range actions {
+		if action.Skipped == "" {
+			t.Errorf("Expected %s of %s to be skipped", action.Entry.Action, action.Entry.SilenceID)
+		}
+	}
+	if len(am.silences) != 2 {
+		t.Errorf("Expected no silences to be created, got %d silences", len(am.silences))
+	}
+	if _, err := sync.RollbackRun("20260101T000000Z"); err == nil {
+		t.Error("Expected an error for a run without a journal")
+	}
+}
+
+func TestRollbackRun_ReplacedSilence(t *testing.T) {
+	am := newMockAlertManager()
+	am.renewOnUpdate = true
+	am.createdCount = 3
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	st, _ := store.Load()
+	newEnd := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	st.AddJournalRun(state.JournalRun{
+		ID:        "20261016T120000Z",
+		StartedAt: time.Now().Add(-time.Hour),
+		Entries: []state.JournalEntry{{
+			Action: state.JournalUpdate, SilenceID: "silence-1", NewSilenceID: "silence-2", TicketRef: "PROJ-1",
+			Matchers: []string{"alertname=DiskFull"}, EndsAt: time.Now().Add(time.Hour), NewEndsAt: newEnd,
+		}},
+	}, 7*24*time.Hour)
+	store.Save(st)
+	am.silences["silence-2"] = &alertmanager.Silence{ID: "silence-2", EndsAt: newEnd, TicketRef: "PROJ-1",
+		Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}, {Name: "cluster", Value: "prod", IsEqual: true}}}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen, SilenceRef: "silence-2"}
+
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+	sync.SetStateStore(store)
+	actions, err := sync.RollbackRun("20261016T120000Z")
+	if err != nil {
+		t.Fatalf("RollbackRun() failed: %v", err)
+	}
+	// Restoring the matchers replaced silence-2 with silence-3
+	if len(actions) != 1 || actions[0].Skipped != "" || actions[0].SilenceID != "silence-3" {
+		t.Fatalf("Expected the update to be rolled back onto silence-3, got %+v", actions)
+	}
+	if silence, ok := am.silences["silence-3"]; !ok || len(silence.Matchers) != 1 {
+		t.Errorf("Expected silence-3 with the previous matchers, got %v", am.silences)
+	}
+	if ref := ts.tickets["PROJ-1"].SilenceRef; ref != "silence-3" {
+		t.Errorf("Expected PROJ-1 to reference silence-3, got %q", ref)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "replaced by silence silence-3") {
+		t.Errorf("Expected the rollback comment to name silence-3, got %v", comments)
+	}
+}
+
+func TestRollbackRun_RecordsPartialProgress(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	st, _ := store.Load()
+	extendedEnd := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	st.AddJournalRun(state.JournalRun{
+		ID:        "20261016T120000Z",
+		StartedAt: time.Now().Add(-time.Hour),
+		Entries: []state.JournalEntry{
+			{Action: state.JournalDelete, SilenceID: "s-1", Matchers: []string{"not a matcher"}, EndsAt: time.Now().Add(time.Hour)},
+			{Action: state.JournalExtend, SilenceID: "s-2", EndsAt: time.Now().Add(time.Hour), NewEndsAt: extendedEnd},
+		},
+	}, 7*24*time.Hour)
+	store.Save(st)
+	am.silences["s-2"] = &alertmanager.Silence{ID: "s-2", EndsAt: extendedEnd}
+
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+	sync.SetStateStore(store)
+	actions, err := sync.RollbackRun("20261016T120000Z")
+	if err == nil || len(actions) != 1 || actions[0].Entry.SilenceID != "s-2" {
+		t.Fatalf("Expected the rollback to stop after s-2, got %+v, %v", actions, err)
+	}
+
+	// Retrying resumes after the extension already reverted
+	actions, err = sync.RollbackRun("20261016T120000Z")
+	if err == nil || len(actions) != 0 {
+		t.Errorf("Expected the retry to skip s-2, got %+v, %v", actions, err)
+	}
+	st, _ = store.Load()
+	run, _ := st.FindJournalRun("20261016T120000Z")
+	if run.Entries[1].RolledBack.IsZero() || !run.Entries[0].RolledBack.IsZero() || !run.RolledBack.IsZero() {
+		t.Errorf("Expected only the extension to be recorded as rolled back, got %+v", run)
+	}
+}
@@ -0,0 +1,82 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// DefaultAckPrefix starts the comment of the silences karma creates when an alert is
+// acknowledged, e.g. "ACK! This alert was acknowledged using karma on ..."
+const DefaultAckPrefix = "ACK!"
+
+// IsAcknowledgement reports whether a silence acknowledges alerts the way karma does,
+// i.e. whether its comment starts with prefix. A ticket reference header written by
+// silence-manager is ignored.
+func IsAcknowledgement(silence *alertmanager.Silence, prefix string) bool {
+	if prefix == "" {
+		return false
+	}
+	comment := silence.Comment
+	if strings.HasPrefix(comment, "# ") {
+		_, comment, _ = strings.Cut(comment, "\n")
+	}
+	return strings.HasPrefix(strings.TrimSpace(comment), prefix)
+}
+
+// ackTickets maps the matchers of the silences linked to tickets to their ticket, for
+// linking acknowledgements of the same alerts to the same ticket
+func ackTickets(silences []*alertmanager.Silence) map[string]string {
+	tickets := make(map[string]string)
+	for _, silence := range silences {
+		if silence.TicketRef != "" {
+			tickets[matcherKey(silence.Matchers)] = silence.TicketRef
+		}
+	}
+	return tickets
+}
+
+// linkAcknowledgement links an acknowledgement without a ticket to the ticket of an
+// active silence with the same matchers, or to a ticket created for it, so that the
+// acknowledgement is managed from then on
+func (s *Synchronizer) linkAcknowledgement(silence *alertmanager.Silence, tickets map[string]string, result *SyncResult) error {
+	key := tickets[matcherKey(silence.Matchers)]
+	created := key == ""
+	if created {
+		var err error
+		key, err = s.ticketSystem.CreateTicket(&ticket.Ticket{
+			Summary: fmt.Sprintf("Acknowledged: %s", formatMatchers(silence.Matchers)),
+			Description: fmt.Sprintf("Alerts matching %s were acknowledged in karma by %s.\n\n%s",
+				formatMatchers(silence.Matchers), silence.CreatedBy, silence.Comment),
+			Project: teamProject(s.teamOfMatchers(silence.Matchers)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create ticket: %w", err)
+		}
+		s.addWatchers(key)
+	}
+
+	silence.TicketRef = key
+	if err := s.alertManager.UpdateSilence(silence); err != nil {
+		silence.TicketRef = ""
+		return fmt.Errorf("failed to link acknowledgement to ticket %s: %w", key, err)
+	}
+	tickets[matcherKey(silence.Matchers)] = key
+	result.AcknowledgementsLinked++
+
+	log.Printf("Linked acknowledgement %s by %s to ticket %s", silence.ID, silence.CreatedBy, key)
+	s.linkSilence(key, silence.ID)
+	msg := fmt.Sprintf("%s acknowledged the alerts in karma with silence %s, expiring at %s. The acknowledgement has been linked to this ticket and is extended while the ticket is open.",
+		silence.CreatedBy, s.silenceRef(silence.ID), silence.EndsAt.Format(time.RFC3339))
+	if created {
+		msg += silenceDefinition(silence)
+	}
+	if err := s.ticketSystem.AddComment(key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+	return nil
+}
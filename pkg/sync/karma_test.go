@@ -0,0 +1,72 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestIsAcknowledgement(t *testing.T) {
+	for comment, want := range map[string]bool{
+		"ACK! This alert was acknowledged using karma on 2026-10-16":                   true,
+		"# ticket: PROJ-1\nACK! This alert was acknowledged using karma on 2026-10-16": true,
+		"Maintenance of db-1, ACK! later":                                              false,
+		"":                                                                             false,
+	} {
+		if got := IsAcknowledgement(&alertmanager.Silence{Comment: comment}, DefaultAckPrefix); got != want {
+			t.Errorf("IsAcknowledgement(%q) = %v, want %v", comment, got, want)
+		}
+	}
+	if IsAcknowledgement(&alertmanager.Silence{Comment: "ACK!"}, "") {
+		t.Error("Expected no acknowledgements without a prefix")
+	}
+}
+
+func TestSync_KarmaAcknowledgements(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	disk := []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}}
+	node := []alertmanager.Matcher{{Name: "alertname", Value: "NodeDown", IsEqual: true}}
+	am.silences["tracked"] = &alertmanager.Silence{ID: "tracked", TicketRef: "PROJ-1", Matchers: disk, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(72 * time.Hour)}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	for id, matchers := range map[string][]alertmanager.Matcher{"ack-disk": disk, "ack-node": node} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			CreatedBy: "alice",
+			Comment:   "ACK! This alert was acknowledged using karma",
+			Matchers:  matchers,
+			StartsAt:  now.Add(-time.Minute),
+			EndsAt:    now.Add(15 * time.Minute),
+		}
+	}
+	am.silences["manual"] = &alertmanager.Silence{ID: "manual", Comment: "Investigating", Matchers: node, StartsAt: now, EndsAt: now.Add(time.Hour)}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.AckPrefix = DefaultAckPrefix
+	result, err := NewSynchronizer(am, ts, cfg).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if result.AcknowledgementsLinked != 2 || result.Hygiene.OrphanSilences != 1 {
+		t.Errorf("Expected 2 linked acknowledgements and 1 orphan, got %d and %d", result.AcknowledgementsLinked, result.Hygiene.OrphanSilences)
+	}
+	if ref := am.silences["ack-disk"].TicketRef; ref != "PROJ-1" {
+		t.Errorf("Expected the acknowledgement to join the ticket of the same alerts, got %q", ref)
+	}
+	created := ts.tickets[am.silences["ack-node"].TicketRef]
+	if created == nil || created.Summary != "Acknowledged: alertname=NodeDown" {
+		t.Errorf("Expected a ticket to be created for the acknowledgement, got %+v", created)
+	}
+	// Linked acknowledgements are managed right away, so the short karma silence is extended
+	if !am.silences["ack-disk"].EndsAt.After(now.Add(time.Hour)) {
+		t.Errorf("Expected the acknowledgement to be extended, expires at %v", am.silences["ack-disk"].EndsAt)
+	}
+	if am.silences["manual"].TicketRef != "" {
+		t.Error("Expected silences other than acknowledgements to be left alone")
+	}
+}
@@ -0,0 +1,163 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// Lifecycle labels maintained on tickets linked to silences
+const (
+	LabelSilenceActive       = "silence-active"
+	LabelSilenceExpiringSoon = "silence-expiring-soon"
+	LabelSilenceExpired      = "silence-expired"
+)
+
+var lifecycleLabels = []string{LabelSilenceActive, LabelSilenceExpiringSoon, LabelSilenceExpired}
+
+// lifecyclePriority ranks lifecycle labels so that a ticket linked to several silences
+// reflects the best-covered one
+var lifecyclePriority = map[string]int{
+	LabelSilenceExpired:      1,
+	LabelSilenceExpiringSoon: 2,
+	LabelSilenceActive:       3,
+}
+
+// lifecycleState is the lifecycle label a ticket should carry after the current run
+type lifecycleState struct {
+	tkt   *ticket.Ticket
+	label string
+}
+
+// lifecycleLabelFor returns the lifecycle label for a silence ending at endsAt
+func (s *Synchronizer) lifecycleLabelFor(endsAt time.Time, deleted bool) string {
+	switch {
+	case deleted || s.hasExpired(endsAt):
+		return LabelSilenceExpired
+	case s.expiringSoon(endsAt):
+		return LabelSilenceExpiringSoon
+	default:
+		return LabelSilenceActive
+	}
+}
+
+// trackLifecycle records the lifecycle label for a ticket, keeping the highest priority
+// label when the ticket is linked to several silences
+func (s *Synchronizer) trackLifecycle(result *SyncResult, tkt *ticket.Ticket, label string) {
+	if !s.config.LifecycleLabels {
+		return
+	}
+	if result.lifecycle == nil {
+		result.lifecycle = make(map[string]*lifecycleState)
+	}
+	if existing, ok := result.lifecycle[tkt.Key]; ok && lifecyclePriority[existing.label] >= lifecyclePriority[label] {
+		return
+	}
+	result.lifecycle[tkt.Key] = &lifecycleState{tkt: tkt, label: label}
+}
+
+// applyLifecycleLabels updates tickets whose lifecycle label changed during the run. The
+// tickets left labelled silence-active or silence-expiring-soon are remembered in the
+// state store, so that they are labelled silence-expired once their silence no longer
+// appears in the list of silences.
+func (s *Synchronizer) applyLifecycleLabels(result *SyncResult) {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		log.Printf("Error loading labelled tickets: %v", err)
+		result.Errors = append(result.Errors, fmt.Errorf("load lifecycle labels: %w", err))
+		st = nil
+	} else {
+		s.trackVanishedLifecycles(st.Lifecycle, result)
+	}
+
+	failed := make(map[string]bool)
+	keys := make([]string, 0, len(result.lifecycle))
+	for key := range result.lifecycle {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		state := result.lifecycle[key]
+
+		var add, remove []string
+		if !hasLabel(state.tkt.Labels, state.label) {
+			add = append(add, state.label)
+		}
+		for _, label := range lifecycleLabels {
+			if label != state.label && hasLabel(state.tkt.Labels, label) {
+				remove = append(remove, label)
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+
+		log.Printf("Setting lifecycle label %s on ticket %s", state.label, key)
+		if err := s.ticketSystem.UpdateLabels(key, add, remove); err != nil {
+			log.Printf("Error updating lifecycle labels on ticket %s: %v", key, err)
+			result.Errors = append(result.Errors, fmt.Errorf("update labels on %s: %w", key, err))
+			failed[key] = true
+			continue
+		}
+		result.LabelsUpdated++
+	}
+
+	if st == nil {
+		return
+	}
+	if st.Lifecycle == nil {
+		st.Lifecycle = make(map[string]string)
+	}
+	for key, state := range result.lifecycle {
+		// A ticket that failed to be labelled expired is tried again next run
+		if state.label != LabelSilenceExpired {
+			st.Lifecycle[key] = state.label
+		} else if !failed[key] {
+			delete(st.Lifecycle, key)
+		}
+	}
+	if err := s.stateStore.Save(st); err != nil {
+		log.Printf("Error saving labelled tickets: %v", err)
+		result.Errors = append(result.Errors, fmt.Errorf("save lifecycle labels: %w", err))
+	}
+}
+
+// trackVanishedLifecycles labels silence-expired the tickets labelled in earlier runs
+// whose silences were not seen in this run, e.g. because they expired and Alertmanager no
+// longer lists them. Deleted tickets are forgotten.
+func (s *Synchronizer) trackVanishedLifecycles(labelled map[string]string, result *SyncResult) {
+	seen := make(map[string]bool, len(result.Managed))
+	for _, m := range result.Managed {
+		seen[m.TicketRef] = true
+	}
+	for key := range labelled {
+		if seen[key] || result.lifecycle[key] != nil {
+			continue
+		}
+		tkt, err := s.ticketSystem.GetTicket(key)
+		if apierror.CategoryOf(err) == apierror.NotFound {
+			delete(labelled, key)
+			continue
+		}
+		if err != nil {
+			log.Printf("Warning: failed to get ticket %s to label its silence expired: %v", key, err)
+			continue
+		}
+		s.trackLifecycle(result, tkt, LabelSilenceExpired)
+	}
+}
+
+// hasLabel reports whether labels contains label
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestLifecycleLabels(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.LifecycleLabels = true
+	cfg.CheckAlerts = false
+
+	addSilence := func(id, ref string, endsAt time.Time) {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			CreatedBy: "user",
+			Comment:   "Test",
+			StartsAt:  time.Now().Add(-48 * time.Hour),
+			EndsAt:    endsAt,
+			TicketRef: ref,
+		}
+	}
+
+	// Long-running silence on an open ticket
+	addSilence("silence-1", "PROJ-1", time.Now().Add(72*time.Hour))
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen, Labels: []string{"team-a", LabelSilenceExpiringSoon}}
+
+	// Expiring silence on a closed ticket is not extended
+	addSilence("silence-2", "PROJ-2", time.Now().Add(2*time.Hour))
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusClosed}
+
+	// Resolved ticket -> silence deleted
+	addSilence("silence-3", "PROJ-3", time.Now().Add(72*time.Hour))
+	ts.tickets["PROJ-3"] = &ticket.Ticket{Key: "PROJ-3", Status: ticket.StatusResolved, Labels: []string{LabelSilenceActive}}
+
+	// Ticket linked to an active and an expiring silence keeps the active label
+	addSilence("silence-4", "PROJ-4", time.Now().Add(72*time.Hour))
+	addSilence("silence-5", "PROJ-4", time.Now().Add(2*time.Hour))
+	ts.tickets["PROJ-4"] = &ticket.Ticket{Key: "PROJ-4", Status: ticket.StatusClosed}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"PROJ-1": LabelSilenceActive,
+		"PROJ-2": LabelSilenceExpiringSoon,
+		"PROJ-3": LabelSilenceExpired,
+		"PROJ-4": LabelSilenceActive,
+	}
+	for key, want := range expected {
+		var lifecycle []string
+		for _, l := range ts.tickets[key].Labels {
+			if lifecyclePriority[l] > 0 {
+				lifecycle = append(lifecycle, l)
+			}
+		}
+		if len(lifecycle) != 1 || lifecycle[0] != want {
+			t.Errorf("Expected %s to have lifecycle label %s, got %v", key, want, lifecycle)
+		}
+	}
+	if !hasLabel(ts.tickets["PROJ-1"].Labels, "team-a") {
+		t.Error("Expected unrelated labels to be preserved")
+	}
+	if result.LabelsUpdated != 4 {
+		t.Errorf("Expected 4 label updates, got %d", result.LabelsUpdated)
+	}
+
+	// A second run with unchanged state does not update tickets again
+	updates := ts.labelUpdates
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if ts.labelUpdates != updates {
+		t.Errorf("Expected no label updates on unchanged tickets, got %d", ts.labelUpdates-updates)
+	}
+}
+
+func TestLifecycleLabels_VanishedSilence(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.LifecycleLabels = true
+	cfg.CheckAlerts = false
+
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", EndsAt: time.Now().Add(2 * time.Hour), TicketRef: "PROJ-1"}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if !hasLabel(ts.tickets["PROJ-1"].Labels, LabelSilenceExpiringSoon) {
+		t.Fatalf("Expected PROJ-1 to be labelled %s, got %v", LabelSilenceExpiringSoon, ts.tickets["PROJ-1"].Labels)
+	}
+
+	// The silence expired between runs and Alertmanager no longer lists it
+	delete(am.silences, "silence-1")
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	labels := ts.tickets["PROJ-1"].Labels
+	if result.LabelsUpdated != 1 || !hasLabel(labels, LabelSilenceExpired) || hasLabel(labels, LabelSilenceExpiringSoon) {
+		t.Errorf("Expected PROJ-1 to be labelled %s, got %v", LabelSilenceExpired, labels)
+	}
+	if st, _ := sync.stateStore.Load(); len(st.Lifecycle) != 0 {
+		t.Errorf("Expected the expired ticket to be forgotten, got %v", st.Lifecycle)
+	}
+
+	// Nothing is left to relabel
+	if result, _ := sync.Sync(); result.LabelsUpdated != 0 {
+		t.Errorf("Expected no further label updates, got %d", result.LabelsUpdated)
+	}
+}
@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// LinkSilence attaches an existing silence to an existing ticket, so that subsequent sync
+// runs manage it. The ticket reference is written into the silence comment and the silence
+// reference into the ticket description. A silence linked to another ticket must be
+// unlinked first.
+func (s *Synchronizer) LinkSilence(silenceID, key string) error {
+	silence, err := s.alertManager.GetSilence(silenceID)
+	if err != nil {
+		return fmt.Errorf("failed to get silence %s: %w", silenceID, err)
+	}
+	if silence.TicketRef != "" && silence.TicketRef != key {
+		return fmt.Errorf("silence %s is already linked to ticket %s", silenceID, silence.TicketRef)
+	}
+	tkt, err := s.ticketSystem.GetTicket(key)
+	if err != nil {
+		return fmt.Errorf("failed to get ticket %s: %w", key, err)
+	}
+
+	silence.TicketRef = key
+	if err := s.alertManager.UpdateSilence(silence); err != nil {
+		return fmt.Errorf("failed to update silence %s: %w", silenceID, err)
+	}
+	if tkt.SilenceRef != silenceID {
+		tkt.SilenceRef = silenceID
+		if err := s.ticketSystem.UpdateTicket(tkt); err != nil {
+			return fmt.Errorf("failed to update ticket %s: %w", key, err)
+		}
+	}
+
+	log.Printf("Linked silence %s to ticket %s", silenceID, key)
+	s.updateDueDate(key, silence.EndsAt)
+	s.linkSilence(key, silenceID)
+	msg := fmt.Sprintf("Silence %s, expiring at %s, has been linked to this ticket. It is now extended while the ticket is open and deleted once it is resolved.",
+		s.silenceRef(silenceID), silence.EndsAt.Format(time.RFC3339))
+	if err := s.ticketSystem.AddComment(key, msg+silenceDefinition(silence)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+	return nil
+}
+
+// UnlinkSilence detaches a silence from its ticket, removing the references from the
+// silence comment and the ticket description. The silence itself is kept but is no longer
+// managed.
+func (s *Synchronizer) UnlinkSilence(silenceID string) error {
+	silence, err := s.alertManager.GetSilence(silenceID)
+	if err != nil {
+		return fmt.Errorf("failed to get silence %s: %w", silenceID, err)
+	}
+	key := silence.TicketRef
+	if key == "" {
+		return fmt.Errorf("silence %s is not linked to a ticket", silenceID)
+	}
+
+	silence.TicketRef = ""
+	if err := s.alertManager.UpdateSilence(silence); err != nil {
+		return fmt.Errorf("failed to update silence %s: %w", silenceID, err)
+	}
+	// Silences adopted through the ticket reference pattern mention the ticket in free text
+	if updated, err := s.alertManager.GetSilence(silenceID); err == nil && updated.TicketRef != "" {
+		return fmt.Errorf("silence %s comment still references ticket %s; edit the comment to remove it", silenceID, updated.TicketRef)
+	}
+	log.Printf("Unlinked silence %s from ticket %s", silenceID, key)
+
+	tkt, err := s.ticketSystem.GetTicket(key)
+	if err != nil {
+		log.Printf("Warning: failed to get ticket %s: %v", key, err)
+		return nil
+	}
+	if tkt.SilenceRef == silenceID {
+		tkt.SilenceRef = ""
+		if err := s.ticketSystem.UpdateTicket(tkt); err != nil {
+			return fmt.Errorf("failed to update ticket %s: %w", key, err)
+		}
+	}
+	if s.config.SilenceUIURL != "" {
+		if err := s.ticketSystem.DeleteRemoteLink(key, silenceLinkID(silenceID)); err != nil {
+			log.Printf("Warning: failed to remove link to silence %s from ticket %s: %v", silenceID, key, err)
+		}
+	}
+	msg := fmt.Sprintf("Silence %s has been unlinked from this ticket and is no longer managed by silence-manager.", silenceID)
+	if err := s.ticketSystem.AddComment(key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestLinkSilence(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", EndsAt: time.Now().Add(time.Hour)}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusOpen}
+
+	cfg := DefaultConfig()
+	cfg.SilenceUIURL = "http://alertmanager:9093"
+	sync := NewSynchronizer(am, ts, cfg)
+
+	if err := sync.LinkSilence("silence-1", "PROJ-1"); err != nil {
+		t.Fatalf("LinkSilence() failed: %v", err)
+	}
+	if am.silences["silence-1"].TicketRef != "PROJ-1" {
+		t.Errorf("Expected silence to reference PROJ-1, got %q", am.silences["silence-1"].TicketRef)
+	}
+	if ts.tickets["PROJ-1"].SilenceRef != "silence-1" {
+		t.Errorf("Expected ticket to reference silence-1, got %q", ts.tickets["PROJ-1"].SilenceRef)
+	}
+	if _, ok := ts.remoteLinks["PROJ-1"][silenceLinkID("silence-1")]; !ok {
+		t.Error("Expected a remote link to the silence")
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "has been linked") {
+		t.Errorf("Expected a comment announcing the link, got %v", comments)
+	}
+
+	// A linked silence must be unlinked before linking it to another ticket
+	if err := sync.LinkSilence("silence-1", "PROJ-2"); err == nil {
+		t.Error("Expected error when linking a silence that belongs to another ticket")
+	}
+
+	if err := sync.UnlinkSilence("silence-1"); err != nil {
+		t.Fatalf("UnlinkSilence() failed: %v", err)
+	}
+	if am.silences["silence-1"].TicketRef != "" || ts.tickets["PROJ-1"].SilenceRef != "" {
+		t.Errorf("Expected references to be removed, silence=%q ticket=%q",
+			am.silences["silence-1"].TicketRef, ts.tickets["PROJ-1"].SilenceRef)
+	}
+	if _, ok := ts.remoteLinks["PROJ-1"][silenceLinkID("silence-1")]; ok {
+		t.Error("Expected the remote link to the silence to be removed")
+	}
+
+	if err := sync.LinkSilence("silence-1", "PROJ-2"); err != nil {
+		t.Errorf("LinkSilence() failed after unlinking: %v", err)
+	}
+}
+
+func TestLinkSilence_Errors(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", EndsAt: time.Now().Add(time.Hour)}
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+
+	if err := sync.LinkSilence("missing", "PROJ-1"); err == nil {
+		t.Error("Expected error for a missing silence")
+	}
+	if err := sync.LinkSilence("silence-1", "PROJ-404"); err == nil {
+		t.Error("Expected error for a missing ticket")
+	}
+	if am.silences["silence-1"].TicketRef != "" {
+		t.Error("Expected the silence to stay unlinked when the ticket is missing")
+	}
+	if err := sync.UnlinkSilence("silence-1"); err == nil {
+		t.Error("Expected error when unlinking a silence without a ticket")
+	}
+}
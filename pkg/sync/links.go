@@ -0,0 +1,88 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// silenceLinkPrefix prefixes the global ID of remote links to silences
+const silenceLinkPrefix = "silence-manager:silence:"
+
+// silenceLinkID returns the global ID of the remote link to a silence
+func silenceLinkID(silenceID string) string {
+	return silenceLinkPrefix + silenceID
+}
+
+// silenceURL returns the URL of a silence in the Alertmanager web UI
+func (s *Synchronizer) silenceURL(silenceID string) string {
+	return fmt.Sprintf("%s/#/silences/%s", strings.TrimSuffix(s.config.SilenceUIURL, "/"), silenceID)
+}
+
+// silenceRef refers to a silence in a ticket comment, as a link to the Alertmanager UI
+// if its URL is configured
+func (s *Synchronizer) silenceRef(silenceID string) string {
+	if s.config.SilenceUIURL == "" {
+		return silenceID
+	}
+	return fmt.Sprintf("[%s](%s)", silenceID, s.silenceURL(silenceID))
+}
+
+// linkSilence adds a remote link from the ticket to the silence in the Alertmanager UI.
+// Links are keyed by silence ID, so linking an already linked silence is a no-op update.
+func (s *Synchronizer) linkSilence(key, silenceID string) {
+	if s.config.SilenceUIURL == "" {
+		return
+	}
+	link := ticket.RemoteLink{
+		GlobalID: silenceLinkID(silenceID),
+		URL:      s.silenceURL(silenceID),
+		Title:    fmt.Sprintf("Alertmanager silence %s", silenceID),
+	}
+	if err := s.ticketSystem.SetRemoteLink(key, link); err != nil {
+		log.Printf("Warning: failed to link silence %s from ticket %s: %v", silenceID, key, err)
+	}
+}
+
+// followRenewedSilence points a ticket at the silence that replaced oldSilenceID after an
+// update, as Alertmanager gives a silence a new ID when its matchers change
+func (s *Synchronizer) followRenewedSilence(tkt *ticket.Ticket, oldSilenceID, newSilenceID string) {
+	if oldSilenceID == newSilenceID {
+		return
+	}
+	log.Printf("Silence %s of ticket %s was replaced by silence %s", oldSilenceID, tkt.Key, newSilenceID)
+	if tkt.SilenceRef == oldSilenceID {
+		tkt.SilenceRef = newSilenceID
+		if err := s.ticketSystem.UpdateTicket(tkt); err != nil {
+			log.Printf("Warning: failed to update silence reference of ticket %s: %v", tkt.Key, err)
+		}
+	}
+	s.relinkSilence(tkt.Key, oldSilenceID, newSilenceID)
+}
+
+// followRenewedSilenceOf points the ticket with key at the silence that replaced
+// oldSilenceID. If the ticket cannot be read, only its link is replaced.
+func (s *Synchronizer) followRenewedSilenceOf(key, oldSilenceID, newSilenceID string) {
+	tkt, err := s.ticketSystem.GetTicket(key)
+	if err != nil {
+		log.Printf("Warning: failed to get ticket %s: %v", key, err)
+		s.relinkSilence(key, oldSilenceID, newSilenceID)
+		return
+	}
+	s.followRenewedSilence(tkt, oldSilenceID, newSilenceID)
+}
+
+// relinkSilence replaces the ticket's link to a silence that was recreated under a new ID
+func (s *Synchronizer) relinkSilence(key, oldSilenceID, newSilenceID string) {
+	if s.config.SilenceUIURL == "" {
+		return
+	}
+	if oldSilenceID != "" && oldSilenceID != newSilenceID {
+		if err := s.ticketSystem.DeleteRemoteLink(key, silenceLinkID(oldSilenceID)); err != nil {
+			log.Printf("Warning: failed to remove link to silence %s from ticket %s: %v", oldSilenceID, key, err)
+		}
+	}
+	s.linkSilence(key, newSilenceID)
+}
@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestSilenceLinks_Extension(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.SilenceUIURL = "https://alertmanager.example.com/"
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		StartsAt:  time.Now().Add(-24 * time.Hour),
+		EndsAt:    time.Now().Add(2 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	link, ok := ts.remoteLinks["PROJ-1"][silenceLinkID("silence-1")]
+	if !ok {
+		t.Fatal("Expected PROJ-1 to link to silence-1")
+	}
+	if link.URL != "https://alertmanager.example.com/#/silences/silence-1" {
+		t.Errorf("Unexpected link URL: %s", link.URL)
+	}
+}
+
+func TestSilenceLinks_ExpiredExtension(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.SilenceUIURL = "https://alertmanager.example.com/"
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		StartsAt:  time.Now().Add(-48 * time.Hour),
+		EndsAt:    time.Now().Add(-1 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	comments := ts.comments["PROJ-1"]
+	want := "Silence [silence-1](https://alertmanager.example.com/#/silences/silence-1) was expired"
+	if len(comments) != 1 || !strings.HasPrefix(comments[0], want) {
+		t.Errorf("Expected the comment to link the silence, got %v", comments)
+	}
+}
+
+func TestSilenceLinks_Recreated(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.SilenceUIURL = "https://alertmanager.example.com"
+
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "ticket": "PROJ-1", "silence_id": "old-silence"}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+	ts.remoteLinks["PROJ-1"] = map[string]ticket.RemoteLink{
+		silenceLinkID("old-silence"): {GlobalID: silenceLinkID("old-silence")},
+	}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesCreated != 1 {
+		t.Fatalf("Expected 1 silence created, got %d", result.SilencesCreated)
+	}
+
+	links := ts.remoteLinks["PROJ-1"]
+	if _, ok := links[silenceLinkID("old-silence")]; ok {
+		t.Error("Expected link to the replaced silence to be removed")
+	}
+	if _, ok := links[silenceLinkID("silence-0")]; !ok || len(links) != 1 {
+		t.Errorf("Expected a single link to the new silence, got %v", links)
+	}
+}
+
+func TestSilenceLinks_Disabled(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		EndsAt:    time.Now().Add(2 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(ts.remoteLinks["PROJ-1"]) != 0 {
+		t.Errorf("Expected no links without a UI URL, got %v", ts.remoteLinks["PROJ-1"])
+	}
+}
@@ -0,0 +1,233 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/calendar"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// maintenanceMatchersPrefix introduces the silence matchers in a maintenance event description
+const maintenanceMatchersPrefix = "matchers:"
+
+// MaintenanceLabel is added to tickets created for maintenance windows
+const MaintenanceLabel = "maintenance"
+
+// SetMaintenanceCalendar sets the source of planned maintenance windows. Each event
+// gets a silence and linked ticket covering the window, retired when the event ends
+// or is removed from the calendar.
+func (s *Synchronizer) SetMaintenanceCalendar(source calendar.Source) {
+	s.maintenance = source
+}
+
+// eventMatchers parses the matchers from "matchers: name=value ..." lines in an event description
+func eventMatchers(event calendar.Event) ([]alertmanager.Matcher, error) {
+	var matchers []alertmanager.Matcher
+	for _, line := range strings.Split(event.Description, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(strings.ToLower(line), maintenanceMatchersPrefix) {
+			continue
+		}
+		for _, field := range strings.Fields(line[len(maintenanceMatchersPrefix):]) {
+			m, err := parseMatcher(field)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, m)
+		}
+	}
+	return matchers, nil
+}
+
+// syncMaintenance creates silences and tickets for upcoming maintenance windows and
+// retires those whose events ended or were cancelled. It returns the IDs of the
+// silences it manages so that the regular lifecycle leaves them alone.
+func (s *Synchronizer) syncMaintenance(result *SyncResult) (map[string]bool, error) {
+	managed := make(map[string]bool)
+
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return managed, fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.Maintenance == nil {
+		st.Maintenance = make(map[string]state.MaintenanceRecord)
+	}
+
+	events, err := s.maintenance.Events()
+	if err != nil {
+		// Keep managing the known silences, but do not retire anything we cannot see
+		for _, rec := range st.Maintenance {
+			managed[rec.SilenceID] = true
+		}
+		return managed, fmt.Errorf("failed to read maintenance calendar: %w", err)
+	}
+
+	now := s.now()
+	current := make(map[string]bool)
+	for _, event := range events {
+		if event.Cancelled || !event.End.After(now) {
+			continue
+		}
+		current[event.Key()] = true
+
+		rec, known := st.Maintenance[event.Key()]
+		if !known {
+			if event.Start.After(now.Add(s.config.MaintenanceLookahead)) {
+				continue
+			}
+			rec, err = s.createMaintenanceSilence(event)
+			if err != nil {
+				log.Printf("Error creating maintenance silence for event %s: %v", event.Key(), err)
+				result.Errors = append(result.Errors, fmt.Errorf("maintenance event %s: %w", event.Key(), err))
+				delete(current, event.Key())
+				continue
+			}
+			st.Maintenance[event.Key()] = rec
+			result.MaintenanceCreated++
+		} else if !rec.StartsAt.Equal(event.Start) || !rec.EndsAt.Equal(event.End) {
+			if err := s.rescheduleMaintenanceSilence(event, &rec); err != nil {
+				log.Printf("Error rescheduling maintenance silence %s: %v", rec.SilenceID, err)
+				result.Errors = append(result.Errors, fmt.Errorf("maintenance event %s: %w", event.Key(), err))
+			} else {
+				st.Maintenance[event.Key()] = rec
+			}
+		}
+		managed[rec.SilenceID] = true
+	}
+
+	// Retire windows that ended or disappeared from the calendar
+	for eventKey, rec := range st.Maintenance {
+		if current[eventKey] {
+			continue
+		}
+		retired, err := s.retireMaintenanceSilence(rec, now)
+		if err != nil {
+			log.Printf("Error retiring maintenance silence %s: %v", rec.SilenceID, err)
+			result.Errors = append(result.Errors, fmt.Errorf("maintenance event %s: %w", eventKey, err))
+		}
+		if !retired {
+			managed[rec.SilenceID] = true
+			continue
+		}
+		delete(st.Maintenance, eventKey)
+		result.MaintenanceRetired++
+	}
+
+	if err := s.stateStore.Save(st); err != nil {
+		return managed, fmt.Errorf("failed to save state: %w", err)
+	}
+	return managed, nil
+}
+
+// createMaintenanceSilence creates the ticket and silence for a maintenance window
+func (s *Synchronizer) createMaintenanceSilence(event calendar.Event) (state.MaintenanceRecord, error) {
+	matchers, err := eventMatchers(event)
+	if err != nil {
+		return state.MaintenanceRecord{}, err
+	}
+	if len(matchers) == 0 {
+		return state.MaintenanceRecord{}, fmt.Errorf("event has no %q line in its description", maintenanceMatchersPrefix)
+	}
+	action := ActionEvent{Action: ActionCreate, NewEndsAt: event.End}
+	if err := s.vetoAction(action); err != nil {
+		return state.MaintenanceRecord{}, err
+	}
+
+	key, err := s.ticketSystem.CreateTicket(&ticket.Ticket{
+		Summary:     fmt.Sprintf("Maintenance: %s", event.Summary),
+		Description: fmt.Sprintf("Planned maintenance window from %s to %s.\n\n%s", event.Start.Format(time.RFC3339), event.End.Format(time.RFC3339), event.Description),
+		Labels:      []string{MaintenanceLabel},
+		Project:     teamProject(s.teamOfMatchers(matchers)),
+	})
+	if err != nil {
+		return state.MaintenanceRecord{}, fmt.Errorf("failed to create ticket: %w", err)
+	}
+	s.addWatchers(key)
+
+	silence := &alertmanager.Silence{
+		CreatedBy: "silence-manager",
+		Comment:   fmt.Sprintf("Maintenance window: %s (calendar event %s)", event.Summary, event.Key()),
+		StartsAt:  event.Start,
+		EndsAt:    event.End,
+		Matchers:  matchers,
+		TicketRef: key,
+	}
+	silenceID, err := s.alertManager.CreateSilence(silence)
+	action.SilenceID, action.TicketKey = silenceID, key
+	s.afterAction(action, err)
+	if err != nil {
+		return state.MaintenanceRecord{}, fmt.Errorf("failed to create silence for ticket %s: %w", key, err)
+	}
+
+	log.Printf("Created maintenance silence %s and ticket %s for event %s", silenceID, key, event.Key())
+	s.updateDueDate(key, event.End)
+	s.linkSilence(key, silenceID)
+	silence.ID = silenceID
+	if err := s.ticketSystem.AddComment(key, fmt.Sprintf("Silence %s created for this maintenance window.", silenceID)+silenceDefinition(silence)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", key, err)
+	}
+
+	return state.MaintenanceRecord{
+		SilenceID: silenceID,
+		TicketKey: key,
+		StartsAt:  event.Start,
+		EndsAt:    event.End,
+	}, nil
+}
+
+// rescheduleMaintenanceSilence moves the silence to the event's new start and end times
+func (s *Synchronizer) rescheduleMaintenanceSilence(event calendar.Event, rec *state.MaintenanceRecord) error {
+	silence, err := s.alertManager.GetSilence(rec.SilenceID)
+	if err != nil {
+		return fmt.Errorf("failed to get silence: %w", err)
+	}
+	previousID := silence.ID
+	silence.StartsAt = event.Start
+	silence.EndsAt = event.End
+	if err := s.alertManager.UpdateSilence(silence); err != nil {
+		return fmt.Errorf("failed to update silence: %w", err)
+	}
+	// Moving the start of an active window replaces the silence under a new ID
+	if silence.ID != previousID {
+		rec.SilenceID = silence.ID
+		s.followRenewedSilenceOf(rec.TicketKey, previousID, silence.ID)
+	}
+
+	log.Printf("Rescheduled maintenance silence %s to %v - %v", rec.SilenceID, event.Start, event.End)
+	s.updateDueDate(rec.TicketKey, event.End)
+	if err := s.ticketSystem.AddComment(rec.TicketKey, fmt.Sprintf("Maintenance window rescheduled to %s - %s.", event.Start.Format(time.RFC3339), event.End.Format(time.RFC3339))); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", rec.TicketKey, err)
+	}
+
+	rec.StartsAt = event.Start
+	rec.EndsAt = event.End
+	return nil
+}
+
+// retireMaintenanceSilence removes the silence of a cancelled window and closes its
+// ticket. It reports false when the window was not retired, e.g. because the deletion of
+// its silence was vetoed, so that a later run retires it.
+func (s *Synchronizer) retireMaintenanceSilence(rec state.MaintenanceRecord, now time.Time) (bool, error) {
+	comment := "Maintenance window completed."
+	if rec.EndsAt.After(now) {
+		deleted, err := s.deleteManagedSilence(rec.SilenceID, rec.TicketKey)
+		if err != nil {
+			return false, fmt.Errorf("failed to delete silence: %w", err)
+		}
+		if !deleted {
+			return false, nil
+		}
+		comment = fmt.Sprintf("Maintenance window was removed from the calendar; silence %s has been deleted.", rec.SilenceID)
+	}
+
+	log.Printf("Retiring maintenance silence %s and closing ticket %s", rec.SilenceID, rec.TicketKey)
+	if err := s.ticketSystem.CloseTicket(rec.TicketKey, comment); err != nil {
+		return false, fmt.Errorf("failed to close ticket %s: %w", rec.TicketKey, err)
+	}
+	return true, nil
+}
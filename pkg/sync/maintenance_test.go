@@ -0,0 +1,221 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/calendar"
+)
+
+// staticCalendar is a calendar.Source returning a fixed set of events
+type staticCalendar struct {
+	events []calendar.Event
+}
+
+func (c *staticCalendar) Events() ([]calendar.Event, error) {
+	return c.events, nil
+}
+
+func TestSync_MaintenanceCalendar(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	now := time.Now().Truncate(time.Second)
+	upcoming := calendar.Event{
+		UID:         "db-upgrade@example.com",
+		Summary:     "Database upgrade",
+		Description: "Upgrading the primary database.\nmatchers: alertname=~Postgres.* cluster=prod",
+		Start:       now.Add(time.Hour),
+		End:         now.Add(3 * time.Hour),
+	}
+	distant := calendar.Event{
+		UID:         "network@example.com",
+		Summary:     "Network maintenance",
+		Description: "matchers: team=network",
+		Start:       now.Add(72 * time.Hour),
+		End:         now.Add(74 * time.Hour),
+	}
+	cal := &staticCalendar{events: []calendar.Event{upcoming, distant}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetMaintenanceCalendar(cal)
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MaintenanceCreated != 1 {
+		t.Fatalf("Expected 1 maintenance silence created, got %d", result.MaintenanceCreated)
+	}
+	if len(am.silences) != 1 || len(ts.tickets) != 1 {
+		t.Fatalf("Expected 1 silence and 1 ticket, got %d and %d", len(am.silences), len(ts.tickets))
+	}
+
+	silence := am.silences["silence-0"]
+	if !silence.StartsAt.Equal(upcoming.Start) || !silence.EndsAt.Equal(upcoming.End) {
+		t.Errorf("Expected silence to cover %v - %v, got %v - %v", upcoming.Start, upcoming.End, silence.StartsAt, silence.EndsAt)
+	}
+	if len(silence.Matchers) != 2 || !silence.Matchers[0].IsRegex || silence.Matchers[1].Name != "cluster" {
+		t.Errorf("Unexpected matchers: %+v", silence.Matchers)
+	}
+	if silence.TicketRef != "PROJ-1" {
+		t.Errorf("Expected silence to reference PROJ-1, got %s", silence.TicketRef)
+	}
+	if !hasLabel(ts.tickets["PROJ-1"].Labels, MaintenanceLabel) {
+		t.Errorf("Expected ticket to carry the %q label", MaintenanceLabel)
+	}
+
+	// The window ends within the expiry threshold but must not be extended
+	if len(am.extendedIDs) != 0 {
+		t.Errorf("Expected maintenance silence not to be extended, got %v", am.extendedIDs)
+	}
+
+	// A second run must not duplicate the window
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MaintenanceCreated != 0 || len(am.silences) != 1 {
+		t.Errorf("Expected no duplicate maintenance silence, created=%d silences=%d", result.MaintenanceCreated, len(am.silences))
+	}
+
+	// Rescheduling the event moves the silence
+	cal.events[0].End = now.Add(4 * time.Hour)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if !am.silences["silence-0"].EndsAt.Equal(now.Add(4 * time.Hour)) {
+		t.Errorf("Expected silence to be rescheduled, ends at %v", am.silences["silence-0"].EndsAt)
+	}
+
+	// Removing the event from the calendar retires the silence and closes the ticket
+	cal.events = []calendar.Event{distant}
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MaintenanceRetired != 1 {
+		t.Errorf("Expected 1 maintenance silence retired, got %d", result.MaintenanceRetired)
+	}
+	if len(am.deletedIDs) != 1 || am.deletedIDs[0] != "silence-0" {
+		t.Errorf("Expected silence-0 to be deleted, got %v", am.deletedIDs)
+	}
+	if len(ts.closedKeys) != 1 || ts.closedKeys[0] != "PROJ-1" {
+		t.Errorf("Expected PROJ-1 to be closed, got %v", ts.closedKeys)
+	}
+}
+
+func TestSync_MaintenanceRescheduleActiveWindow(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.SilenceUIURL = "https://alertmanager.example.com"
+
+	now := time.Now().Truncate(time.Second)
+	cal := &staticCalendar{events: []calendar.Event{{
+		UID:         "db-upgrade@example.com",
+		Summary:     "Database upgrade",
+		Description: "matchers: cluster=prod",
+		Start:       now.Add(-time.Hour),
+		End:         now.Add(time.Hour),
+	}}}
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetMaintenanceCalendar(cal)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	// Alertmanager replaces an active silence whose start moves
+	am.renewOnUpdate = true
+	cal.events[0].Start = now.Add(-30 * time.Minute)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if _, ok := am.silences["silence-1"]; !ok || len(am.silences) != 1 {
+		t.Fatalf("Expected the window to be silenced by silence-1, got %v", am.silences)
+	}
+	if _, ok := ts.remoteLinks["PROJ-1"][silenceLinkID("silence-1")]; !ok {
+		t.Errorf("Expected PROJ-1 to link the new silence, got %v", ts.remoteLinks["PROJ-1"])
+	}
+
+	// The replacement is still managed as the window: not extended, and retired with it
+	am.renewOnUpdate = false
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(am.extendedIDs) != 0 {
+		t.Errorf("Expected the maintenance silence not to be extended, got %v", am.extendedIDs)
+	}
+	cal.events = nil
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MaintenanceRetired != 1 || len(am.deletedIDs) != 1 || am.deletedIDs[0] != "silence-1" {
+		t.Errorf("Expected silence-1 to be retired, got retired=%d deleted=%v", result.MaintenanceRetired, am.deletedIDs)
+	}
+	if len(ts.closedKeys) != 1 {
+		t.Errorf("Expected the maintenance ticket to be closed, got %v", ts.closedKeys)
+	}
+}
+
+func TestSync_MaintenanceEventWithoutMatchers(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetMaintenanceCalendar(&staticCalendar{events: []calendar.Event{{
+		UID:     "no-matchers@example.com",
+		Summary: "Unscoped maintenance",
+		Start:   time.Now().Add(time.Hour),
+		End:     time.Now().Add(2 * time.Hour),
+	}}})
+
+	result, _ := sync.Sync()
+	if result.MaintenanceCreated != 0 || len(am.silences) != 0 {
+		t.Errorf("Expected no silence for an event without matchers")
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(result.Errors))
+	}
+}
+
+func TestSync_MaintenanceRecurringEvent(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	// Two occurrences of one recurring event share its UID
+	now := time.Now().Truncate(time.Second)
+	first := calendar.Event{UID: "patching@example.com", Summary: "Patching", Description: "matchers: team=platform",
+		Start: now.Add(-time.Hour), End: now.Add(time.Hour), RecurrenceID: now.Add(-time.Hour)}
+	second := first
+	second.Start, second.End, second.RecurrenceID = now.Add(23*time.Hour), now.Add(25*time.Hour), now.Add(23*time.Hour)
+	cal := &staticCalendar{events: []calendar.Event{first, second}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetMaintenanceCalendar(cal)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MaintenanceCreated != 2 || len(am.silences) != 2 {
+		t.Fatalf("Expected a silence per occurrence, got %d created", result.MaintenanceCreated)
+	}
+
+	// Once the first occurrence drops out of the feed, only its silence is retired
+	cal.events = []calendar.Event{second}
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MaintenanceRetired != 1 || len(am.deletedIDs) != 1 || am.deletedIDs[0] != "silence-0" {
+		t.Errorf("Expected only the first occurrence to be retired, got %d retired and deleted %v", result.MaintenanceRetired, am.deletedIDs)
+	}
+}
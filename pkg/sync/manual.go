@@ -0,0 +1,61 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// ExtendSilence moves the end of a silence to duration from now on request, e.g. from the
+// web UI, recording the extension like a sync run does. The linked ticket is told about
+// it. It returns the extended silence.
+func (s *Synchronizer) ExtendSilence(silenceID string, duration time.Duration) (*alertmanager.Silence, error) {
+	silence, err := s.alertManager.GetSilence(silenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get silence %s: %w", silenceID, err)
+	}
+
+	previousEnd := silence.EndsAt
+	newEnd := s.endTime(duration)
+	if !newEnd.After(previousEnd) {
+		return nil, fmt.Errorf("silence %s already lasts until %s", silenceID, previousEnd.Format(time.RFC3339))
+	}
+	if err := s.extendSilence(silence, newEnd); err != nil {
+		return nil, fmt.Errorf("failed to extend silence %s: %w", silenceID, err)
+	}
+
+	log.Printf("Extended silence %s from %s to %s on request", silence.ID,
+		previousEnd.Format(time.RFC3339), newEnd.Format(time.RFC3339))
+	if silence.TicketRef != "" {
+		s.updateDueDate(silence.TicketRef, newEnd)
+		msg := fmt.Sprintf("Silence %s has been extended on request. It now expires at %s instead of %s.",
+			s.silenceRef(silence.ID), newEnd.Format(time.RFC3339), previousEnd.Format(time.RFC3339))
+		if err := s.ticketSystem.AddComment(silence.TicketRef, msg); err != nil {
+			log.Printf("Warning: failed to add comment to ticket %s: %v", silence.TicketRef, err)
+		}
+	}
+	return silence, nil
+}
+
+// DeleteSilence expires a silence on request, e.g. from the web UI. The linked ticket is
+// told about it but left open.
+func (s *Synchronizer) DeleteSilence(silenceID string) error {
+	silence, err := s.alertManager.GetSilence(silenceID)
+	if err != nil {
+		return fmt.Errorf("failed to get silence %s: %w", silenceID, err)
+	}
+	if err := s.alertManager.DeleteSilence(silenceID); err != nil {
+		return fmt.Errorf("failed to delete silence %s: %w", silenceID, err)
+	}
+
+	log.Printf("Deleted silence %s on request", silenceID)
+	if silence.TicketRef != "" {
+		msg := fmt.Sprintf("Silence %s has been deleted on request; its alerts notify again.", silenceID)
+		if err := s.ticketSystem.AddComment(silence.TicketRef, msg); err != nil {
+			log.Printf("Warning: failed to add comment to ticket %s: %v", silence.TicketRef, err)
+		}
+	}
+	return nil
+}
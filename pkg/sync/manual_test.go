@@ -0,0 +1,58 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestExtendSilence(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", TicketRef: "PROJ-1", EndsAt: time.Now().Add(time.Hour)}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+
+	silence, err := sync.ExtendSilence("silence-1", 48*time.Hour)
+	if err != nil {
+		t.Fatalf("ExtendSilence() failed: %v", err)
+	}
+	if until := time.Until(silence.EndsAt); until < 47*time.Hour || until > 48*time.Hour {
+		t.Errorf("Expected the silence to end in 48h, ends in %v", until)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "extended on request") {
+		t.Errorf("Expected a comment announcing the extension, got %v", comments)
+	}
+
+	// Shortening a silence is not an extension
+	if _, err := sync.ExtendSilence("silence-1", time.Hour); err == nil {
+		t.Error("Expected error when the new end is before the current end")
+	}
+}
+
+func TestDeleteSilence(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", TicketRef: "PROJ-1", EndsAt: time.Now().Add(time.Hour)}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+
+	if err := sync.DeleteSilence("silence-1"); err != nil {
+		t.Fatalf("DeleteSilence() failed: %v", err)
+	}
+	if len(am.deletedIDs) != 1 || am.deletedIDs[0] != "silence-1" {
+		t.Errorf("Expected silence-1 to be deleted, got %v", am.deletedIDs)
+	}
+	if ts.tickets["PROJ-1"].Status != ticket.StatusOpen {
+		t.Errorf("Expected the ticket to stay open, got %s", ts.tickets["PROJ-1"].Status)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "deleted on request") {
+		t.Errorf("Expected a comment announcing the deletion, got %v", comments)
+	}
+	if err := sync.DeleteSilence("silence-1"); err == nil {
+		t.Error("Expected error for an unknown silence")
+	}
+}
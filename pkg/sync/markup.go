@@ -0,0 +1,36 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Comments are written in the markup the ticket system converts to rich text: "### "
+// headings, ``` code blocks, "| a | b |" tables and [text](url) links.
+
+// labelTable returns a table of alert labels, sorted by name
+func labelTable(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("| Label | Value |\n| --- | --- |")
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n| %s | %s |", escapeCell(name), escapeCell(labels[name]))
+	}
+	return b.String()
+}
+
+// escapeCell keeps a value from ending its table cell early
+func escapeCell(value string) string {
+	return strings.NewReplacer("|", "/", "\n", " ").Replace(value)
+}
+
+// codeBlock fences text as a code block
+func codeBlock(language, text string) string {
+	return fmt.Sprintf("```%s\n%s\n```", language, text)
+}
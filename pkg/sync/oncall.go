@@ -0,0 +1,65 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/oncall"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// SetOnCallResolver sets the on-call schedule provider. Tickets reopened for refired
+// alerts are then assigned to whoever is on call for the team named in the alert labels.
+func (s *Synchronizer) SetOnCallResolver(resolver oncall.Resolver) {
+	s.onCall = resolver
+}
+
+// onCallSchedule returns the schedule of the team owning the alert, or "" if the alert
+// has no team label. The schedule of the team mapping takes precedence; teams without a
+// configured schedule use the team name.
+func (s *Synchronizer) onCallSchedule(alert *alertmanager.Alert) (team, schedule string) {
+	if t := s.config.Teams.Lookup(alert.Labels); t != nil && t.OnCallSchedule != "" {
+		return t.Name, t.OnCallSchedule
+	}
+	team = alert.Labels[s.config.OnCallTeamLabel]
+	if team == "" {
+		return "", ""
+	}
+	if schedule, ok := s.config.OnCallSchedules[team]; ok {
+		return team, schedule
+	}
+	return team, team
+}
+
+// assignOnCall assigns a reopened ticket to the current on-call of the alert's team
+func (s *Synchronizer) assignOnCall(tkt *ticket.Ticket, alert *alertmanager.Alert) {
+	if s.onCall == nil {
+		return
+	}
+
+	team, schedule := s.onCallSchedule(alert)
+	if team == "" {
+		log.Printf("Not assigning ticket %s: alert has no %q label", tkt.Key, s.config.OnCallTeamLabel)
+		return
+	}
+
+	user, err := s.onCall.CurrentOnCall(schedule)
+	if err != nil {
+		log.Printf("Warning: failed to look up on-call for team %s: %v", team, err)
+		return
+	}
+	if user == tkt.Assignee {
+		return
+	}
+
+	log.Printf("Assigning ticket %s to %s, on call for team %s", tkt.Key, user, team)
+	if err := s.ticketSystem.AssignTicket(tkt.Key, user); err != nil {
+		log.Printf("Warning: failed to assign ticket %s: %v", tkt.Key, err)
+		return
+	}
+	tkt.Assignee = user
+	if err := s.ticketSystem.AddComment(tkt.Key, fmt.Sprintf("Assigned to %s, currently on call for team %s.", user, team)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+}
@@ -0,0 +1,58 @@
+package sync
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// staticOnCall returns a fixed on-call user per schedule
+type staticOnCall map[string]string
+
+func (s staticOnCall) CurrentOnCall(schedule string) (string, error) {
+	if user, ok := s[schedule]; ok {
+		return user, nil
+	}
+	return "", fmt.Errorf("unknown schedule %s", schedule)
+}
+
+func TestCheckRefiredAlerts_AssignOnCall(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.OnCallSchedules = map[string]string{"storage": "PSTORAGE"}
+
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "ticket": "PROJ-1", "team": "storage"}},
+		{Labels: map[string]string{"alertname": "HighLatency", "ticket": "PROJ-2", "team": "network"}},
+		{Labels: map[string]string{"alertname": "Unowned", "ticket": "PROJ-3"}},
+		{Labels: map[string]string{"alertname": "NoSchedule", "ticket": "PROJ-4", "team": "billing"}},
+	}
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"} {
+		ts.tickets[key] = &ticket.Ticket{Key: key, Status: ticket.StatusClosed}
+	}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetOnCallResolver(staticOnCall{"PSTORAGE": "alice@example.com", "network": "bob@example.com"})
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsReopened != 4 {
+		t.Fatalf("Expected 4 tickets reopened, got %d", result.TicketsReopened)
+	}
+
+	expected := map[string]string{
+		"PROJ-1": "alice@example.com", // Mapped schedule
+		"PROJ-2": "bob@example.com",   // Team name used as schedule
+		"PROJ-3": "",                  // No team label
+		"PROJ-4": "",                  // Schedule lookup failed
+	}
+	for key, assignee := range expected {
+		if got := ts.tickets[key].Assignee; got != assignee {
+			t.Errorf("Expected %s to be assigned to %q, got %q", key, assignee, got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/calendar"
+	"github.com/conallob/silence-manager/pkg/gitops"
+	"github.com/conallob/silence-manager/pkg/metrics"
+	"github.com/conallob/silence-manager/pkg/oncall"
+	"github.com/conallob/silence-manager/pkg/rules"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// Option configures a Synchronizer created with New
+type Option func(*Synchronizer)
+
+// New creates a synchronizer between am and ts. Without options it uses DefaultConfig,
+// publishes no metrics and keeps its state in memory. This is the constructor for
+// programs embedding the sync engine with their own AlertManager and TicketSystem
+// implementations.
+func New(am alertmanager.AlertManager, ts ticket.TicketSystem, opts ...Option) *Synchronizer {
+	s := &Synchronizer{
+		alertManager:     am,
+		ticketSystem:     ts,
+		config:           DefaultConfig(),
+		metricsPublisher: metrics.NewNoopPublisher(),
+		stateStore:       state.NewMemoryStore(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.alertManager = s.tracked(s.alertManager)
+	return s
+}
+
+// WithConfig sets the synchronization settings, replacing DefaultConfig
+func WithConfig(config SyncConfig) Option {
+	return func(s *Synchronizer) { s.config = config }
+}
+
+// WithMetricsPublisher sets the publisher of run metrics
+func WithMetricsPublisher(publisher metrics.Publisher) Option {
+	return func(s *Synchronizer) { s.metricsPublisher = publisher }
+}
+
+// WithStateStore sets the store used to persist state between runs
+func WithStateStore(store state.Store) Option {
+	return func(s *Synchronizer) { s.stateStore = store }
+}
+
+// WithMaintenanceCalendar sets the source of planned maintenance windows
+func WithMaintenanceCalendar(source calendar.Source) Option {
+	return func(s *Synchronizer) { s.maintenance = source }
+}
+
+// WithGitOpsSource sets the source of declared silences
+func WithGitOpsSource(source gitops.Source) Option {
+	return func(s *Synchronizer) { s.gitOps = source }
+}
+
+// WithOnCallResolver sets the resolver assigning reopened tickets to the current on-call
+func WithOnCallResolver(resolver oncall.Resolver) Option {
+	return func(s *Synchronizer) { s.onCall = resolver }
+}
+
+// WithRuleSource sets the source of the existing alerting rules
+func WithRuleSource(source rules.Source) Option {
+	return func(s *Synchronizer) { s.rules = source }
+}
+
+// WithNotifier sets the channel notified of escalated tickets
+func WithNotifier(notifier Notifier) Option {
+	return func(s *Synchronizer) { s.notifier = notifier }
+}
+
+// WithEscalator sets the on-call tool paged when a silence lapses at MaxExtensions
+func WithEscalator(escalator oncall.Escalator) Option {
+	return func(s *Synchronizer) { s.escalator = escalator }
+}
+
+// WithBeforeAction adds a hook called before each action. Hooks run in the order added.
+func WithBeforeAction(hook BeforeActionFunc) Option {
+	return func(s *Synchronizer) { s.beforeHooks = append(s.beforeHooks, hook) }
+}
+
+// WithAfterAction adds a hook called after each action. Hooks run in the order added.
+func WithAfterAction(hook AfterActionFunc) Option {
+	return func(s *Synchronizer) { s.afterHooks = append(s.afterHooks, hook) }
+}
+
+// WithActionHooks adds hooks for each kind of action
+func WithActionHooks(hooks ActionHooks) Option {
+	return func(s *Synchronizer) { s.AddActionHooks(hooks) }
+}
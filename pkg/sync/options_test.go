@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	sync := New(newMockAlertManager(), newMockTicketSystem())
+	if sync.config.ExpiryThreshold != DefaultConfig().ExpiryThreshold {
+		t.Errorf("Expected the default configuration, got %+v", sync.config)
+	}
+	if sync.metricsPublisher == nil || sync.stateStore == nil {
+		t.Error("Expected a no-op metrics publisher and an in-memory state store")
+	}
+
+	store := state.NewMemoryStore()
+	cfg := DefaultConfig()
+	cfg.ExtensionDuration = time.Hour
+	sync = New(newMockAlertManager(), newMockTicketSystem(), WithConfig(cfg), WithStateStore(store))
+	if sync.config.ExtensionDuration != time.Hour || sync.stateStore != store {
+		t.Error("Expected the options to be applied")
+	}
+}
+
+func TestSync_ActionHooks(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID: "silence-1", TicketRef: "PROJ-1", StartsAt: time.Now(), EndsAt: time.Now().Add(12 * time.Hour),
+	}
+	am.silences["silence-2"] = &alertmanager.Silence{
+		ID: "silence-2", TicketRef: "PROJ-2", StartsAt: time.Now(), EndsAt: time.Now().Add(12 * time.Hour),
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusResolved}
+
+	var before, after []ActionEvent
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	sync := New(am, ts,
+		WithConfig(cfg),
+		WithBeforeAction(func(event ActionEvent) error {
+			before = append(before, event)
+			if event.Action == ActionExtend {
+				return errors.New("change freeze")
+			}
+			return nil
+		}),
+		WithAfterAction(func(event ActionEvent, err error) {
+			if err != nil {
+				t.Errorf("Unexpected error for %s: %v", event.Action, err)
+			}
+			after = append(after, event)
+		}),
+	)
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	// The vetoed extension is skipped without an error, the deletion goes ahead
+	if len(before) != 2 {
+		t.Fatalf("Expected 2 before hooks, got %+v", before)
+	}
+	if result.SilencesExtended != 0 || len(am.extendedIDs) != 0 || len(result.Errors) != 0 {
+		t.Errorf("Expected the extension to be skipped without errors, got %+v", result)
+	}
+	if len(after) != 1 || after[0].Action != ActionDelete || after[0].SilenceID != "silence-2" || after[0].TicketKey != "PROJ-2" {
+		t.Errorf("Expected the deletion of silence-2 after hook, got %+v", after)
+	}
+	if result.SilencesDeleted != 1 {
+		t.Errorf("Expected 1 deleted silence, got %d", result.SilencesDeleted)
+	}
+}
@@ -0,0 +1,154 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// Actions a synchronization run takes for a silence
+const (
+	ActionNone     = "none"     // The silence is left as it is
+	ActionExtend   = "extend"   // The silence is about to expire and is extended
+	ActionDelete   = "delete"   // The ticket is resolved and the silence is deleted
+	ActionWithhold = "withhold" // The silence is about to expire but the ticket has no assignee
+	ActionLapse    = "lapse"    // The silence is about to expire but has matched no alerts for long, its alerting rule was removed, or it reached MaxExtensions
+	ActionSkip     = "skip"     // The silence is not managed (no ticket, a foreign ticket, or a maintenance or change window)
+	ActionUnknown  = "unknown"  // The ticket could not be read
+)
+
+// PlannedAction describes a silence and what the next synchronization run would do with it
+type PlannedAction struct {
+	SilenceID    string    `json:"silenceID"`
+	Matchers     []string  `json:"matchers,omitempty"`
+	TicketRef    string    `json:"ticketRef,omitempty"`
+	TicketStatus string    `json:"ticketStatus,omitempty"`
+	SLA          string    `json:"sla,omitempty"` // The ticket's most urgent ongoing SLA, e.g. "Time to resolution: 3h left"
+	EndsAt       time.Time `json:"endsAt"`
+	Action       string    `json:"action"`
+	NewEndsAt    time.Time `json:"newEndsAt,omitzero"`
+	Reason       string    `json:"reason,omitempty"`
+}
+
+// nextAction decides what to do with a silence based on its linked ticket. For
+// ActionExtend it also returns the new end time.
+func (s *Synchronizer) nextAction(silence *alertmanager.Silence, tkt *ticket.Ticket) (string, time.Time) {
+	if s.ticketSystem.IsResolved(tkt) {
+		return ActionDelete, time.Time{}
+	}
+	if !s.ticketSystem.IsOpen(tkt) || !s.expiringSoon(silence.EndsAt) {
+		return ActionNone, time.Time{}
+	}
+	// Unowned tickets must not keep alerts silenced indefinitely
+	if s.config.RequireAssignee && tkt.Assignee == "" {
+		return ActionWithhold, time.Time{}
+	}
+	return ActionExtend, s.endTime(s.extensionDurationFor(tkt))
+}
+
+// Plan lists the active silences and the action the next synchronization run would
+// take for each, without changing anything. Directives and matcher edits on tickets are
+// not taken into account.
+func (s *Synchronizer) Plan() ([]PlannedAction, error) {
+	silences, err := s.alertManager.ListSilences()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	silences = s.managedSilences(silences)
+	s.syncClock(s.alertManager)
+
+	maintenance := make(map[string]bool)
+	changes := make(map[string]bool)
+	var idle map[string]state.IdleSilence
+	if st, err := s.stateStore.Load(); err == nil {
+		idle = st.Idle
+		for _, rec := range st.Maintenance {
+			maintenance[rec.SilenceID] = true
+		}
+		for _, rec := range st.Changes {
+			if !s.handedOver(rec) {
+				changes[rec.SilenceID] = true
+			}
+		}
+	}
+
+	s.prefetchTickets(silences)
+	skip := make(map[string]bool, len(maintenance)+len(changes))
+	for id := range maintenance {
+		skip[id] = true
+	}
+	for id := range changes {
+		skip[id] = true
+	}
+	s.groupByTicket(silences, skip)
+	if s.rules != nil {
+		if err := s.loadAlertNames(); err != nil {
+			log.Printf("Warning: failed to fetch alerting rules, not checking for removed rules: %v", err)
+		}
+	}
+	defer func() { s.prefetched, s.groups, s.alertNames = nil, nil, nil }()
+
+	plan := make([]PlannedAction, 0, len(silences))
+	for _, silence := range silences {
+		p := PlannedAction{
+			SilenceID: silence.ID,
+			TicketRef: silence.TicketRef,
+			EndsAt:    silence.EndsAt,
+		}
+		for _, m := range silence.Matchers {
+			p.Matchers = append(p.Matchers, m.String())
+		}
+
+		switch {
+		case silence.TicketRef == "":
+			p.Action, p.Reason = ActionSkip, "no ticket reference"
+		case !s.allowsTicketRef(silence.TicketRef):
+			p.Action, p.Reason = ActionSkip, "ticket outside the allowed projects"
+		case maintenance[silence.ID]:
+			p.Action, p.Reason = ActionSkip, "maintenance window"
+		case changes[silence.ID]:
+			p.Action, p.Reason = ActionSkip, "change window"
+		default:
+			tkt, err := s.getTicket(silence.TicketRef)
+			if err != nil {
+				p.Action, p.Reason = ActionUnknown, err.Error()
+				break
+			}
+			p.TicketStatus = string(tkt.Status)
+			if sla := tkt.UrgentSLA(); sla != nil {
+				p.SLA = sla.String()
+			}
+			p.Action, p.NewEndsAt = s.nextGroupAction(silence, tkt)
+			switch p.Action {
+			case ActionDelete:
+				p.Reason = "ticket is resolved"
+			case ActionWithhold:
+				p.Reason = "ticket has no assignee"
+			case ActionExtend:
+				p.Reason = fmt.Sprintf("expires within %v", s.config.ExpiryThreshold)
+				if !s.expiringSoon(silence.EndsAt) {
+					p.Reason = "extended with the other silences of the ticket"
+				}
+				// Idle silences are left to lapse unless they mute alerts by the next run
+				if rec, ok := idle[silence.ID]; ok && s.config.ExpireIdleAfter > 0 && time.Since(rec.Since) >= s.config.ExpireIdleAfter {
+					p.Action, p.NewEndsAt = ActionLapse, time.Time{}
+					p.Reason = fmt.Sprintf("matched no alerts since %s", rec.Since.Format(time.RFC3339))
+				}
+				if alertName, removed := s.removedRule(silence); removed {
+					p.Action, p.NewEndsAt = ActionLapse, time.Time{}
+					p.Reason = fmt.Sprintf("no alerting rule named %q exists", alertName)
+				}
+				if extensions, capped := s.extensionCapped(silence); capped {
+					p.Action, p.NewEndsAt = ActionLapse, time.Time{}
+					p.Reason = fmt.Sprintf("extended %d times, the maximum", extensions)
+				}
+			}
+		}
+		plan = append(plan, p)
+	}
+	return plan, nil
+}
@@ -0,0 +1,97 @@
+package sync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestPlan(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.RequireAssignee = true
+
+	soon := time.Now().Add(1 * time.Hour)
+	later := time.Now().Add(7 * 24 * time.Hour)
+	am.silences["resolved"] = &alertmanager.Silence{ID: "resolved", EndsAt: later, TicketRef: "PROJ-1"}
+	am.silences["expiring"] = &alertmanager.Silence{ID: "expiring", EndsAt: soon, TicketRef: "PROJ-2"}
+	am.silences["unowned"] = &alertmanager.Silence{ID: "unowned", EndsAt: soon, TicketRef: "PROJ-3"}
+	am.silences["quiet"] = &alertmanager.Silence{ID: "quiet", EndsAt: later, TicketRef: "PROJ-4"}
+	am.silences["sibling"] = &alertmanager.Silence{ID: "sibling", EndsAt: time.Now().Add(48 * time.Hour), TicketRef: "PROJ-2"}
+	am.silences["missing"] = &alertmanager.Silence{ID: "missing", EndsAt: later, TicketRef: "PROJ-404"}
+	am.silences["untracked"] = &alertmanager.Silence{ID: "untracked", EndsAt: soon}
+	am.silences["window"] = &alertmanager.Silence{ID: "window", EndsAt: soon, TicketRef: "PROJ-2"}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusResolved}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusOpen, Assignee: "alice"}
+	ts.tickets["PROJ-3"] = &ticket.Ticket{Key: "PROJ-3", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-4"] = &ticket.Ticket{Key: "PROJ-4", Status: ticket.StatusOpen, Assignee: "bob",
+		SLAs: []ticket.SLA{{Name: "Time to resolution", Ongoing: true, Remaining: 3 * time.Hour}}}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	st, _ := sync.stateStore.Load()
+	st.Maintenance["event-1"] = state.MaintenanceRecord{SilenceID: "window", TicketKey: "PROJ-2"}
+
+	plan, err := sync.Plan()
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	actions := make(map[string]PlannedAction, len(plan))
+	for _, p := range plan {
+		actions[p.SilenceID] = p
+	}
+
+	want := map[string]string{
+		"resolved":  ActionDelete,
+		"expiring":  ActionExtend,
+		"unowned":   ActionWithhold,
+		"quiet":     ActionNone,
+		"sibling":   ActionExtend, // Extended with "expiring", which references the same ticket
+		"missing":   ActionUnknown,
+		"untracked": ActionSkip,
+		"window":    ActionSkip,
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("Expected %d planned actions, got %d", len(want), len(actions))
+	}
+	for id, action := range want {
+		if actions[id].Action != action {
+			t.Errorf("Silence %s: expected action %q, got %q (%s)", id, action, actions[id].Action, actions[id].Reason)
+		}
+	}
+
+	if got := actions["expiring"].NewEndsAt; !got.After(soon) {
+		t.Errorf("Expected a new end time after %v, got %v", soon, got)
+	}
+	if got := actions["resolved"].TicketStatus; got != string(ticket.StatusResolved) {
+		t.Errorf("Expected ticket status %q, got %q", ticket.StatusResolved, got)
+	}
+
+	if got := actions["quiet"].SLA; got != "Time to resolution: 3h left" {
+		t.Errorf("Expected the ticket's SLA, got %q", got)
+	}
+
+	// Planning must not change anything
+	if len(am.deletedIDs) != 0 || len(am.extendedIDs) != 0 {
+		t.Errorf("Plan() modified silences: deleted %v, extended %v", am.deletedIDs, am.extendedIDs)
+	}
+	for key, comments := range ts.comments {
+		if len(comments) != 0 {
+			t.Errorf("Plan() commented on ticket %s", key)
+		}
+	}
+}
+
+func TestPlan_ListError(t *testing.T) {
+	am := newMockAlertManager()
+	am.listErr = fmt.Errorf("connection refused")
+	sync := NewSynchronizer(am, newMockTicketSystem(), DefaultConfig())
+
+	if _, err := sync.Plan(); err == nil {
+		t.Error("Expected error when listing silences fails")
+	}
+}
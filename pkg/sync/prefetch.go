@@ -0,0 +1,44 @@
+package sync
+
+import (
+	"log"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// prefetchTickets looks up the tickets referenced by the silences with a single bulk
+// request, so that processing each silence does not need its own ticket lookup. Tickets
+// that no longer exist are left out by the ticket system; if the bulk lookup fails
+// nonetheless, tickets are fetched one by one instead.
+func (s *Synchronizer) prefetchTickets(silences []*alertmanager.Silence) {
+	s.prefetched = nil
+
+	seen := make(map[string]bool)
+	keys := make([]string, 0, len(silences))
+	for _, silence := range silences {
+		if silence.TicketRef != "" && !seen[silence.TicketRef] && s.allowsTicketRef(silence.TicketRef) {
+			seen[silence.TicketRef] = true
+			keys = append(keys, silence.TicketRef)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	tickets, err := s.ticketSystem.GetTickets(keys)
+	if err != nil {
+		log.Printf("Warning: bulk lookup of %d tickets failed, fetching them individually: %v", len(keys), err)
+		return
+	}
+	log.Printf("Fetched %d of %d referenced tickets in bulk", len(tickets), len(keys))
+	s.prefetched = tickets
+}
+
+// getTicket returns a prefetched ticket, or fetches it if it was not prefetched
+func (s *Synchronizer) getTicket(key string) (*ticket.Ticket, error) {
+	if tkt, ok := s.prefetched[key]; ok {
+		return tkt, nil
+	}
+	return s.ticketSystem.GetTicket(key)
+}
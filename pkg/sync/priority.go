@@ -0,0 +1,47 @@
+package sync
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// severityLabel is the alert label mapped to a ticket priority
+const severityLabel = "severity"
+
+// priorityForSeverity returns the ticket priority configured for the alert's severity,
+// or "" if the alert has no severity or the severity is not mapped
+func (s *Synchronizer) priorityForSeverity(alert *alertmanager.Alert) string {
+	severity := strings.ToLower(alert.Labels[severityLabel])
+	if severity == "" {
+		return ""
+	}
+	return s.config.SeverityPriorities[severity]
+}
+
+// applySeverityPriority sets the ticket's priority from the alert's severity when it differs
+func (s *Synchronizer) applySeverityPriority(tkt *ticket.Ticket, alert *alertmanager.Alert) {
+	priority := s.priorityForSeverity(alert)
+	if priority == "" || strings.EqualFold(priority, tkt.Priority) {
+		return
+	}
+
+	log.Printf("Setting priority of ticket %s to %s for severity %s", tkt.Key, priority, alert.Labels[severityLabel])
+	if err := s.ticketSystem.SetPriority(tkt.Key, priority); err != nil {
+		log.Printf("Warning: failed to set priority of ticket %s: %v", tkt.Key, err)
+		return
+	}
+	tkt.Priority = priority
+}
+
+// priorityExtension returns the extension duration configured for the ticket's priority
+func (s *Synchronizer) priorityExtension(tkt *ticket.Ticket) (time.Duration, bool) {
+	if tkt.Priority == "" {
+		return 0, false
+	}
+	d, ok := s.config.PriorityExtensions[strings.ToLower(tkt.Priority)]
+	return d, ok
+}
@@ -0,0 +1,35 @@
+package sync
+
+import (
+	"log"
+	"strings"
+)
+
+// ticketProject returns the project key of a ticket key such as "OPS-123"
+func ticketProject(key string) string {
+	project, _, _ := strings.Cut(key, "-")
+	return project
+}
+
+// allowsTicketRef reports whether ref belongs to one of TicketProjects. All projects are
+// allowed when none are configured.
+func (s *Synchronizer) allowsTicketRef(ref string) bool {
+	if len(s.config.TicketProjects) == 0 {
+		return true
+	}
+	project := ticketProject(ref)
+	for _, allowed := range s.config.TicketProjects {
+		if strings.EqualFold(project, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportForeignTicketRef logs and counts a reference to a ticket outside TicketProjects,
+// which is left alone rather than looked up
+func (s *Synchronizer) reportForeignTicketRef(what, ref string, result *SyncResult) {
+	log.Printf("%s references ticket %s outside the allowed projects %v, skipping", what, ref, s.config.TicketProjects)
+	s.metricsPublisher.RecordForeignTicketRef(ticketProject(ref))
+	result.ForeignTicketRefs++
+}
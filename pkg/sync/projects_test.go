@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/metrics"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// foreignRefPublisher records the projects of skipped foreign ticket references
+type foreignRefPublisher struct {
+	*metrics.NoopPublisher
+	projects map[string]int
+}
+
+func (p *foreignRefPublisher) RecordForeignTicketRef(project string) {
+	p.projects[project]++
+}
+
+func TestSync_SkipsForeignTicketRefs(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	for id, ref := range map[string]string{"silence-own": "OPS-1", "silence-foreign": "PAY-7"} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: ref,
+			StartsAt:  time.Now().Add(-time.Hour),
+			EndsAt:    time.Now().Add(12 * time.Hour),
+		}
+	}
+	ts.tickets["OPS-1"] = &ticket.Ticket{Key: "OPS-1", Status: ticket.StatusResolved}
+	am.alerts = []*alertmanager.Alert{{Labels: map[string]string{"alertname": "PaymentsDown", "ticket": "PAY-8"}}}
+
+	publisher := &foreignRefPublisher{NoopPublisher: metrics.NewNoopPublisher().(*metrics.NoopPublisher), projects: map[string]int{}}
+	cfg := DefaultConfig()
+	cfg.TicketProjects = []string{"ops"}
+	result, err := New(am, ts, WithConfig(cfg), WithMetricsPublisher(publisher)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected foreign references not to cause errors, got %v", result.Errors)
+	}
+	if result.SilencesDeleted != 1 {
+		t.Errorf("Expected the silence of the allowed project to be managed, got %d deletions", result.SilencesDeleted)
+	}
+	if _, ok := am.silences["silence-foreign"]; !ok {
+		t.Error("Expected the silence of the foreign ticket to be left alone")
+	}
+	if result.ForeignTicketRefs != 2 || publisher.projects["PAY"] != 2 {
+		t.Errorf("Expected the foreign silence and alert to be reported, got %d (%v)", result.ForeignTicketRefs, publisher.projects)
+	}
+}
+
+func TestTicketProject(t *testing.T) {
+	for key, want := range map[string]string{"OPS-123": "OPS", "OPS": "OPS", "": ""} {
+		if got := ticketProject(key); got != want {
+			t.Errorf("ticketProject(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package sync
+
+import (
+	"log"
+	"strings"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// DefaultProtectToken marks a silence that silence-manager must leave alone when it
+// appears in the silence's comment
+const DefaultProtectToken = "#do-not-manage"
+
+// IsProtected reports whether a silence opts out of management, by carrying token in its
+// comment, ignoring case, or by having a matcher on the label protectLabel. Empty values
+// disable the respective marker.
+func IsProtected(silence *alertmanager.Silence, token, protectLabel string) bool {
+	if token != "" && strings.Contains(strings.ToLower(silence.Comment), strings.ToLower(token)) {
+		return true
+	}
+	if protectLabel != "" {
+		for _, m := range silence.Matchers {
+			if m.Name == protectLabel {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// protected reports whether a silence opts out of management under the configuration
+func (s *Synchronizer) protected(silence *alertmanager.Silence) bool {
+	return IsProtected(silence, s.config.ProtectToken, s.config.ProtectLabel)
+}
+
+// unprotectedSilences drops the silences that opt out of management, so that they are
+// never extended, deleted or commented on even if they carry a ticket reference
+func (s *Synchronizer) unprotectedSilences(silences []*alertmanager.Silence) []*alertmanager.Silence {
+	if s.config.ProtectToken == "" && s.config.ProtectLabel == "" {
+		return silences
+	}
+	kept := silences[:0:0]
+	for _, silence := range silences {
+		if s.protected(silence) {
+			log.Printf("Silence %s is protected from management, skipping", silence.ID)
+			continue
+		}
+		kept = append(kept, silence)
+	}
+	return kept
+}
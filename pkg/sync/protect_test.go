@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestIsProtected(t *testing.T) {
+	disk := alertmanager.Matcher{Name: "alertname", Value: "DiskFull", IsEqual: true}
+	for name, tc := range map[string]struct {
+		silence *alertmanager.Silence
+		want    bool
+	}{
+		"token":           {&alertmanager.Silence{Comment: "Vendor maintenance #DO-NOT-MANAGE", Matchers: []alertmanager.Matcher{disk}}, true},
+		"label":           {&alertmanager.Silence{Comment: "Vendor maintenance", Matchers: []alertmanager.Matcher{disk, {Name: "do_not_manage", IsEqual: true}}}, true},
+		"unmarked":        {&alertmanager.Silence{Comment: "Vendor maintenance", Matchers: []alertmanager.Matcher{disk}}, false},
+		"label as value":  {&alertmanager.Silence{Matchers: []alertmanager.Matcher{{Name: "team", Value: "do_not_manage", IsEqual: true}}}, false},
+		"token in ticket": {&alertmanager.Silence{Comment: "# ticket: PROJ-1\nLeave alone #do-not-manage"}, true},
+	} {
+		if got := IsProtected(tc.silence, DefaultProtectToken, "do_not_manage"); got != tc.want {
+			t.Errorf("%s: IsProtected() = %v, want %v", name, got, tc.want)
+		}
+	}
+	if IsProtected(&alertmanager.Silence{Comment: "#do-not-manage"}, "", "") {
+		t.Error("Expected no protection without markers")
+	}
+}
+
+func TestSync_SkipsProtectedSilences(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	for id, comment := range map[string]string{
+		"resolved-protected": "Keep until the vendor confirms #do-not-manage",
+		"resolved":           "Disk replacement",
+		"expiring-protected": "Hand-tuned window #do-not-manage",
+	} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			Comment:   comment,
+			TicketRef: "PROJ-" + id,
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(time.Hour),
+		}
+		ts.tickets["PROJ-"+id] = &ticket.Ticket{Key: "PROJ-" + id, Status: ticket.StatusResolved}
+	}
+	ts.tickets["PROJ-expiring-protected"].Status = ticket.StatusOpen
+	am.silences["labelled"] = &alertmanager.Silence{
+		ID:        "labelled",
+		TicketRef: "PROJ-labelled",
+		Matchers:  []alertmanager.Matcher{{Name: "do_not_manage", IsEqual: true}},
+		StartsAt:  now.Add(-time.Hour),
+		EndsAt:    now.Add(time.Hour),
+	}
+	ts.tickets["PROJ-labelled"] = &ticket.Ticket{Key: "PROJ-labelled", Status: ticket.StatusResolved}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ProtectLabel = "do_not_manage"
+	result, err := New(am, ts, WithConfig(cfg)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if result.SilencesDeleted != 1 || len(am.extendedIDs) != 0 {
+		t.Errorf("Expected only the unprotected silence to be deleted, got %d deleted and %v extended", result.SilencesDeleted, am.extendedIDs)
+	}
+	for _, id := range []string{"resolved-protected", "expiring-protected", "labelled"} {
+		if _, ok := am.silences[id]; !ok {
+			t.Errorf("Expected protected silence %s to be kept", id)
+		}
+		if len(ts.comments["PROJ-"+id]) != 0 {
+			t.Errorf("Expected no comments about protected silence %s, got %v", id, ts.comments["PROJ-"+id])
+		}
+	}
+}
+
+func TestSync_DiscoverySkipsProtectedSilence(t *testing.T) {
+	am, ts, sync := newFixture(discoveryFixture())
+	am.silences["silence-old"].Comment = "Disk replacement #do-not-manage"
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesCreated != 0 || am.createdCount != 0 || ts.tickets["PROJ-1"].SilenceRef != "silence-old" {
+		t.Errorf("Expected the protected silence not to be recreated, got %d created", result.SilencesCreated)
+	}
+}
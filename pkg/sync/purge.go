@@ -0,0 +1,40 @@
+package sync
+
+import (
+	"log"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// PurgeTargets returns the active silences a bulk purge would delete: those without a
+// ticket reference, or all of them. Silences protected from management or created by
+// unmanaged creators are left out, so that other teams' silences on a shared
+// Alertmanager are never purged.
+func (s *Synchronizer) PurgeTargets(all bool) ([]*alertmanager.Silence, error) {
+	silences, err := s.alertManager.ListSilences()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*alertmanager.Silence
+	for _, silence := range s.managedSilences(silences) {
+		if all || silence.TicketRef == "" {
+			targets = append(targets, silence)
+		}
+	}
+	return targets, nil
+}
+
+// Purge deletes the silences selected by PurgeTargets without touching their tickets,
+// and returns how many were deleted
+func (s *Synchronizer) Purge(targets []*alertmanager.Silence) int {
+	deleted := 0
+	for _, silence := range targets {
+		if err := s.alertManager.DeleteSilence(silence.ID); err != nil {
+			log.Printf("Error deleting silence %s: %v", silence.ID, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
@@ -0,0 +1,63 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+func TestPurge_SkipsProtectedSilences(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	later := time.Now().Add(time.Hour)
+	am.silences["orphan"] = &alertmanager.Silence{ID: "orphan", EndsAt: later}
+	am.silences["linked"] = &alertmanager.Silence{ID: "linked", TicketRef: "PROJ-1", EndsAt: later}
+	am.silences["protected"] = &alertmanager.Silence{ID: "protected", Comment: "Vendor window #do-not-manage", EndsAt: later}
+	am.silences["labelled"] = &alertmanager.Silence{ID: "labelled", EndsAt: later,
+		Matchers: []alertmanager.Matcher{{Name: "do_not_manage", IsEqual: true}}}
+
+	cfg := DefaultConfig()
+	cfg.ProtectLabel = "do_not_manage"
+	sync := NewSynchronizer(am, ts, cfg)
+
+	orphans, err := sync.PurgeTargets(false)
+	if err != nil {
+		t.Fatalf("PurgeTargets() failed: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].ID != "orphan" {
+		t.Errorf("Expected only the unprotected orphan, got %v", orphans)
+	}
+
+	all, err := sync.PurgeTargets(true)
+	if err != nil {
+		t.Fatalf("PurgeTargets() failed: %v", err)
+	}
+	if deleted := sync.Purge(all); deleted != 2 {
+		t.Errorf("Expected 2 silences to be deleted, got %d", deleted)
+	}
+	for _, id := range []string{"protected", "labelled"} {
+		if _, ok := am.silences[id]; !ok {
+			t.Errorf("Expected protected silence %s to be kept", id)
+		}
+	}
+}
+
+func TestPurge_SkipsUnmanagedCreators(t *testing.T) {
+	am := newMockAlertManager()
+	later := time.Now().Add(time.Hour)
+	am.silences["ours"] = &alertmanager.Silence{ID: "ours", CreatedBy: "silence-manager", EndsAt: later}
+	am.silences["theirs"] = &alertmanager.Silence{ID: "theirs", CreatedBy: "payments-bot", EndsAt: later}
+
+	cfg := DefaultConfig()
+	cfg.ManagedCreators = []string{"silence-manager"}
+	sync := NewSynchronizer(am, newMockTicketSystem(), cfg)
+
+	targets, err := sync.PurgeTargets(true)
+	if err != nil {
+		t.Fatalf("PurgeTargets() failed: %v", err)
+	}
+	if len(targets) != 1 || targets[0].ID != "ours" {
+		t.Errorf("Expected only the managed creator's silence, got %v", targets)
+	}
+}
@@ -0,0 +1,160 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// refireCommentMarker starts the comments noting a refired alert on a closed ticket
+const refireCommentMarker = "Alert has refired on this closed ticket"
+
+// followUpTicket handles an alert refiring on a closed ticket under the new-ticket
+// strategy. The closed ticket keeps the history of the earlier incident; a new ticket,
+// linked to it and carrying its selected labels, tracks the recurrence and its silence.
+// silenced holds the matcher keys of the active silences, so that an alert already
+// covered by a follow-up is not followed up again.
+func (s *Synchronizer) followUpTicket(closed *ticket.Ticket, alert *alertmanager.Alert, silenced map[string]bool, result *SyncResult) {
+	key := matcherKey(s.createMatchersFromAlert(alert))
+	if silenced[key] {
+		return
+	}
+
+	log.Printf("Alert refired for closed ticket %s, creating a follow-up ticket", closed.Key)
+	summary := closed.Summary
+	if summary == "" {
+		summary = fmt.Sprintf("Alert %s refired", alert.Labels["alertname"])
+	}
+	followUp := &ticket.Ticket{
+		Summary: fmt.Sprintf("%s (recurrence of %s)", summary, closed.Key),
+		Description: fmt.Sprintf("Alert has refired after %s was closed. This ticket tracks the recurrence.\n\n### Alert labels\n%s",
+			closed.Key, labelTable(alert.Labels)),
+		Labels:  s.refireLabels(closed.Labels),
+		Project: teamProject(s.config.Teams.Lookup(alert.Labels)),
+	}
+	followUpKey, err := s.ticketSystem.CreateTicket(followUp)
+	if err != nil {
+		log.Printf("Error creating follow-up ticket for %s: %v", closed.Key, err)
+		result.Errors = append(result.Errors, fmt.Errorf("create follow-up ticket for %s: %w", closed.Key, err))
+		return
+	}
+	followUp.Key = followUpKey
+	followUp.Status = ticket.StatusOpen
+	result.FollowUpTickets++
+	log.Printf("Created follow-up ticket %s for closed ticket %s", followUpKey, closed.Key)
+	s.addWatchers(followUpKey)
+
+	if err := s.ticketSystem.LinkTickets(closed.Key, followUpKey, s.config.RefireLinkType); err != nil {
+		log.Printf("Warning: failed to link ticket %s to %s: %v", closed.Key, followUpKey, err)
+	}
+	if err := s.ticketSystem.AddComment(closed.Key, fmt.Sprintf("Alert has refired. The recurrence is tracked in %s.", followUpKey)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", closed.Key, err)
+	}
+	s.applySeverityPriority(followUp, alert)
+	s.assignOnCall(followUp, alert)
+
+	if s.createRefireSilence(followUp, alert, "", result) != nil {
+		silenced[key] = true
+	}
+}
+
+// commentRefire handles an alert refiring on a closed ticket under the comment strategy.
+// The ticket stays closed and the alert is not silenced. An alert that keeps firing is
+// noted once, recognised by its labels in the earlier comment.
+func (s *Synchronizer) commentRefire(closed *ticket.Ticket, alert *alertmanager.Alert, result *SyncResult) {
+	labels := labelTable(alert.Labels)
+	comments, err := s.ticketSystem.GetComments(closed.Key)
+	if err != nil {
+		log.Printf("Warning: failed to get comments for ticket %s: %v", closed.Key, err)
+		return
+	}
+	for _, c := range comments {
+		if strings.Contains(c.Body, refireCommentMarker) && strings.Contains(c.Body, labels) {
+			return
+		}
+	}
+
+	log.Printf("Alert refired for closed ticket %s, commenting without reopening", closed.Key)
+	msg := refireCommentMarker + ". The ticket is left closed and the alert is not silenced.\n\n### Alert labels\n" + labels
+	if err := s.ticketSystem.AddComment(closed.Key, msg); err != nil {
+		log.Printf("Error commenting on ticket %s: %v", closed.Key, err)
+		result.Errors = append(result.Errors, fmt.Errorf("comment on ticket %s: %w", closed.Key, err))
+		return
+	}
+	result.RefireComments++
+}
+
+// reopenWithSubTasks reopens a closed ticket for an alert refiring with several
+// instances, and tracks each instance in a sub-task of the ticket with its own silence.
+// The sub-tasks are extended and resolved independently, and the reopened ticket lists
+// them instead of the labels of every instance.
+func (s *Synchronizer) reopenWithSubTasks(tkt *ticket.Ticket, alerts []*alertmanager.Alert, result *SyncResult) {
+	event := ActionEvent{Action: ActionReopen, TicketKey: tkt.Key}
+	if !s.beforeAction(event) {
+		return
+	}
+	log.Printf("Alert refired with %d instances for closed ticket %s, reopening and creating sub-tasks", len(alerts), tkt.Key)
+
+	reopenMsg := fmt.Sprintf("Alert has refired with %d instances. Automatically reopening ticket; each instance is tracked in a sub-task with its own silence.", len(alerts))
+	err := s.ticketSystem.ReopenTicket(tkt.Key, reopenMsg)
+	s.afterAction(event, err)
+	if err != nil {
+		log.Printf("Error reopening ticket %s: %v", tkt.Key, err)
+		result.Errors = append(result.Errors, fmt.Errorf("reopen ticket %s: %w", tkt.Key, err))
+		return
+	}
+	result.TicketsReopened++
+	s.addWatchers(tkt.Key)
+	s.applySeverityPriority(tkt, alerts[0])
+	s.assignOnCall(tkt, alerts[0])
+
+	var created []string
+	for _, alert := range alerts {
+		instance := formatMatchers(s.createMatchersFromAlert(alert))
+		subTask := &ticket.Ticket{
+			Summary:     fmt.Sprintf("%s: %s", tkt.Summary, instance),
+			Description: fmt.Sprintf("Alert instance refired on %s.\n\n### Alert labels\n%s", tkt.Key, labelTable(alert.Labels)),
+			Labels:      s.refireLabels(tkt.Labels),
+			Parent:      tkt.Key,
+		}
+		key, err := s.ticketSystem.CreateTicket(subTask)
+		if err != nil {
+			log.Printf("Error creating sub-task of %s for %s: %v", tkt.Key, instance, err)
+			result.Errors = append(result.Errors, fmt.Errorf("create sub-task of %s: %w", tkt.Key, err))
+			continue
+		}
+		subTask.Key = key
+		subTask.Status = ticket.StatusOpen
+		result.SubTasksCreated++
+		log.Printf("Created sub-task %s of ticket %s for %s", key, tkt.Key, instance)
+		s.addWatchers(key)
+		s.assignOnCall(subTask, alert)
+		s.createRefireSilence(subTask, alert, "", result)
+		created = append(created, fmt.Sprintf("- %s: %s", key, instance))
+	}
+
+	if len(created) == 0 {
+		return
+	}
+	msg := "Refired alert instances are tracked in sub-tasks:\n" + strings.Join(created, "\n")
+	if err := s.ticketSystem.AddComment(tkt.Key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+}
+
+// refireLabels returns the labels of a closed ticket that are copied to its follow-up
+func (s *Synchronizer) refireLabels(labels []string) []string {
+	var copied []string
+	for _, label := range labels {
+		for _, pattern := range s.config.RefireCopyLabels {
+			if matchesPattern(label, pattern) {
+				copied = append(copied, label)
+				break
+			}
+		}
+	}
+	return copied
+}
@@ -0,0 +1,182 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// refireFixture is a closed ticket whose alert fires again, followed up by a new ticket
+func refireFixture() fixture {
+	return fixture{
+		alerts: []*alertmanager.Alert{
+			{Labels: map[string]string{"alertname": "DiskFull", "instance": "db-1", "ticket": "PROJ-1"}},
+		},
+		tickets: []*ticket.Ticket{{
+			Key:     "PROJ-1",
+			Summary: "Disk full on db-1",
+			Status:  ticket.StatusClosed,
+			Labels:  []string{"service-db", "team-storage", "postmortem"},
+		}},
+		configure: func(cfg *SyncConfig) {
+			cfg.ReopenStrategy = ReopenStrategyNewTicket
+			cfg.RefireLinkType = "Cause"
+			cfg.RefireCopyLabels = []string{"service-db", "team-*"}
+		},
+	}
+}
+
+func TestCheckRefiredAlerts_NewTicketPolicy(t *testing.T) {
+	am, ts, sync := newFixture(refireFixture())
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.FollowUpTickets != 1 || result.TicketsReopened != 0 || len(ts.reopenedKeys) != 0 {
+		t.Fatalf("Expected a follow-up ticket instead of reopening, got follow-ups=%d reopened=%d", result.FollowUpTickets, result.TicketsReopened)
+	}
+	if result.Hygiene.TicketsReopened != 1 {
+		t.Errorf("Expected the follow-up to count as a recurrence, got %d", result.Hygiene.TicketsReopened)
+	}
+
+	followUp := ts.tickets["PROJ-2"]
+	if followUp == nil || !strings.Contains(followUp.Summary, "recurrence of PROJ-1") {
+		t.Fatalf("Expected follow-up ticket PROJ-2, got %+v", followUp)
+	}
+	if strings.Join(followUp.Labels, ",") != "service-db,team-storage" {
+		t.Errorf("Expected the selected labels to be copied, got %v", followUp.Labels)
+	}
+	if !strings.Contains(followUp.Description, "| instance | db-1 |") {
+		t.Errorf("Expected the alert labels in the description, got %q", followUp.Description)
+	}
+	if len(ts.issueLinks) != 1 || ts.issueLinks[0] != "PROJ-1 Cause PROJ-2" {
+		t.Errorf("Expected the tickets to be linked, got %v", ts.issueLinks)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "PROJ-2") {
+		t.Errorf("Expected the closed ticket to point to the follow-up, got %v", comments)
+	}
+	if silence := am.silences["silence-0"]; silence == nil || silence.TicketRef != "PROJ-2" {
+		t.Errorf("Expected the new silence to reference the follow-up, got %+v", silence)
+	}
+
+	// The silence of the follow-up covers the alert on the next run
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.FollowUpTickets != 0 || len(ts.tickets) != 2 {
+		t.Errorf("Expected no further follow-up tickets, got %d", result.FollowUpTickets)
+	}
+}
+
+func TestCheckRefiredAlerts_NewTicketCreateError(t *testing.T) {
+	am, ts, sync := newFixture(refireFixture())
+	ts.createErr = errors.New("jira unavailable")
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(result.Errors) != 1 || am.createdCount != 0 || len(ts.issueLinks) != 0 {
+		t.Errorf("Expected the error to be reported without a silence or link, got %v", result.Errors)
+	}
+}
+
+func TestCheckRefiredAlerts_CommentStrategy(t *testing.T) {
+	am, ts, sync := newFixture(refireFixture())
+	sync.config.ReopenStrategy = ReopenStrategyComment
+
+	for run := 0; run < 2; run++ {
+		result, err := sync.Sync()
+		if err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+		if result.TicketsReopened != 0 || result.FollowUpTickets != 0 || result.SilencesCreated != 0 {
+			t.Errorf("Expected the ticket to stay closed and the alert unsilenced, got %+v", result)
+		}
+	}
+
+	if len(ts.reopenedKeys) != 0 || len(ts.tickets) != 1 || am.createdCount != 0 {
+		t.Errorf("Expected no reopened ticket, follow-up or silence")
+	}
+	comments := ts.comments["PROJ-1"]
+	if len(comments) != 1 || !strings.Contains(comments[0], refireCommentMarker) || !strings.Contains(comments[0], "| instance | db-1 |") {
+		t.Errorf("Expected a single comment with the alert labels, got %v", comments)
+	}
+}
+
+func TestParseReopenStrategy(t *testing.T) {
+	for _, value := range []string{"reopen", "new-ticket", "comment"} {
+		if strategy, err := ParseReopenStrategy(value); err != nil || string(strategy) != value {
+			t.Errorf("ParseReopenStrategy(%q) = %q, %v", value, strategy, err)
+		}
+	}
+	if _, err := ParseReopenStrategy("clone"); err == nil {
+		t.Error("Expected error for an unknown strategy")
+	}
+}
+
+func TestCheckRefiredAlerts_SubTasks(t *testing.T) {
+	am, ts, sync := newFixture(refireFixture())
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "instance": "db-1", "ticket": "PROJ-1"}},
+		{Labels: map[string]string{"alertname": "DiskFull", "instance": "db-2", "ticket": "PROJ-1"}},
+		{Labels: map[string]string{"alertname": "DiskFull", "instance": "db-2", "ticket": "PROJ-1", "pod": "other"}},
+	}
+	sync.config.ReopenStrategy = ReopenStrategyReopen
+	sync.config.RefireSubTasks = true
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsReopened != 1 || len(ts.reopenedKeys) != 1 {
+		t.Fatalf("Expected the ticket to be reopened once, got %d", result.TicketsReopened)
+	}
+	if result.SubTasksCreated != 2 || result.SilencesCreated != 2 {
+		t.Fatalf("Expected a sub-task and a silence per instance, got %d sub-tasks and %d silences", result.SubTasksCreated, result.SilencesCreated)
+	}
+	for _, key := range []string{"PROJ-2", "PROJ-3"} {
+		subTask := ts.tickets[key]
+		if subTask == nil || subTask.Parent != "PROJ-1" {
+			t.Fatalf("Expected %s to be a sub-task of PROJ-1, got %+v", key, subTask)
+		}
+		if strings.Join(subTask.Labels, ",") != "service-db,team-storage" {
+			t.Errorf("Expected the selected labels on %s, got %v", key, subTask.Labels)
+		}
+	}
+	if !strings.Contains(ts.tickets["PROJ-3"].Description, "| instance | db-2 |") {
+		t.Errorf("Expected the labels of the instance in the sub-task, got %q", ts.tickets["PROJ-3"].Description)
+	}
+	refs := make(map[string]bool)
+	for _, silence := range am.silences {
+		refs[silence.TicketRef] = true
+	}
+	if !refs["PROJ-2"] || !refs["PROJ-3"] {
+		t.Errorf("Expected each silence to reference its sub-task, got %v", refs)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) == 0 || !strings.Contains(comments[len(comments)-1], "- PROJ-2: alertname=DiskFull instance=db-1") {
+		t.Errorf("Expected the reopened ticket to list its sub-tasks, got %v", comments)
+	}
+}
+
+func TestCheckRefiredAlerts_SubTasksSingleInstance(t *testing.T) {
+	am, ts, sync := newFixture(refireFixture())
+	sync.config.ReopenStrategy = ReopenStrategyReopen
+	sync.config.RefireSubTasks = true
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsReopened != 1 || result.SubTasksCreated != 0 || len(ts.tickets) != 1 {
+		t.Errorf("Expected a single instance to reopen the ticket without sub-tasks, got %+v", result)
+	}
+	if silence := am.silences["silence-0"]; silence == nil || silence.TicketRef != "PROJ-1" {
+		t.Errorf("Expected the silence to reference the reopened ticket, got %+v", silence)
+	}
+}
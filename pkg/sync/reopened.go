@@ -0,0 +1,195 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/apierror"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// rememberResolved records the definition of a silence deleted because its ticket was
+// resolved, so that it can be recreated if the ticket is reopened
+func (s *Synchronizer) rememberResolved(result *SyncResult, silence *alertmanager.Silence, tkt *ticket.Ticket) {
+	if s.config.RecreateOnReopenFor <= 0 {
+		return
+	}
+	if result.resolved == nil {
+		result.resolved = make(map[string]state.ResolvedSilence)
+	}
+	rec := state.ResolvedSilence{
+		SilenceID:  silence.ID,
+		CreatedBy:  silence.CreatedBy,
+		Comment:    silence.Comment,
+		ResolvedAt: s.now(),
+	}
+	for _, matcher := range silence.Matchers {
+		rec.Matchers = append(rec.Matchers, formatMatchers([]alertmanager.Matcher{matcher}))
+	}
+	result.resolved[tkt.Key] = rec
+}
+
+// recreateReopened recreates the silences of resolved tickets that were reopened by hand
+// since their silence was deleted, from the definition remembered when it was deleted, so
+// that the alert does not page anyone before the next refire. Tickets are remembered for
+// RecreateOnReopenFor; a ticket that already has an active silence, e.g. because its
+// alert refired, is forgotten. The recreated silences are returned.
+func (s *Synchronizer) recreateReopened(silences []*alertmanager.Silence, result *SyncResult) ([]*alertmanager.Silence, error) {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.Resolved == nil {
+		st.Resolved = make(map[string]state.ResolvedSilence)
+	}
+
+	silenced := make(map[string]bool, len(silences))
+	for _, silence := range silences {
+		if silence.TicketRef != "" {
+			silenced[silence.TicketRef] = true
+		}
+	}
+
+	now := s.now()
+	keys := make([]string, 0, len(st.Resolved))
+	for key, rec := range st.Resolved {
+		if silenced[key] || now.Sub(rec.ResolvedAt) > s.config.RecreateOnReopenFor {
+			delete(st.Resolved, key)
+			continue
+		}
+		if _, resolvedNow := result.resolved[key]; !resolvedNow {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var recreated []*alertmanager.Silence
+	tickets, failed := s.resolvedTickets(keys, result)
+	for _, key := range keys {
+		tkt, ok := tickets[key]
+		switch {
+		case failed[key]:
+			continue
+		case !ok:
+			log.Printf("Resolved ticket %s no longer exists, forgetting its silence", key)
+			delete(st.Resolved, key)
+			continue
+		case !s.ticketSystem.IsOpen(tkt):
+			continue
+		}
+		silence, err := s.recreateFromResolved(tkt, st.Resolved[key], result)
+		if err != nil {
+			log.Printf("Error recreating silence for reopened ticket %s: %v", key, err)
+			result.Errors = append(result.Errors, fmt.Errorf("reopened ticket %s: %w", key, err))
+			continue
+		}
+		if silence != nil {
+			recreated = append(recreated, silence)
+			delete(st.Resolved, key)
+		}
+	}
+
+	for key, rec := range result.resolved {
+		st.Resolved[key] = rec
+	}
+	if err := s.stateStore.Save(st); err != nil {
+		return recreated, fmt.Errorf("failed to save state: %w", err)
+	}
+	return recreated, nil
+}
+
+// resolvedTickets looks up the remembered tickets in bulk, or one by one if the bulk
+// lookup fails, e.g. because one of them was deleted. Tickets that no longer exist are
+// missing from the result; tickets that could not be looked up are reported as failed.
+func (s *Synchronizer) resolvedTickets(keys []string, result *SyncResult) (map[string]*ticket.Ticket, map[string]bool) {
+	failed := make(map[string]bool)
+	if len(keys) == 0 {
+		return nil, failed
+	}
+	tickets, err := s.ticketSystem.GetTickets(keys)
+	if err == nil {
+		return tickets, failed
+	}
+	log.Printf("Warning: bulk lookup of %d resolved tickets failed, fetching them individually: %v", len(keys), err)
+
+	tickets = make(map[string]*ticket.Ticket, len(keys))
+	for _, key := range keys {
+		tkt, err := s.ticketSystem.GetTicket(key)
+		switch {
+		case err == nil:
+			tickets[key] = tkt
+		case apierror.CategoryOf(err) != apierror.NotFound:
+			failed[key] = true
+			result.Errors = append(result.Errors, fmt.Errorf("resolved ticket %s: %w", key, err))
+		}
+	}
+	return tickets, failed
+}
+
+// recreateFromResolved creates a silence for a reopened ticket from the definition of the
+// silence deleted when it was resolved. It returns nil if the silence is withheld because
+// the ticket has no assignee, or vetoed by a hook.
+func (s *Synchronizer) recreateFromResolved(tkt *ticket.Ticket, rec state.ResolvedSilence, result *SyncResult) (*alertmanager.Silence, error) {
+	if s.config.RequireAssignee && tkt.Assignee == "" {
+		log.Printf("Ticket %s was reopened but has no assignee, not recreating silence %s", tkt.Key, rec.SilenceID)
+		return nil, nil
+	}
+	matchers := make([]alertmanager.Matcher, 0, len(rec.Matchers))
+	for _, value := range rec.Matchers {
+		m, err := parseMatcher(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored matcher: %w", err)
+		}
+		matchers = append(matchers, m)
+	}
+
+	newSilence := &alertmanager.Silence{
+		CreatedBy: rec.CreatedBy,
+		Comment:   rec.Comment,
+		StartsAt:  s.now(),
+		EndsAt:    s.endTime(s.extensionDurationFor(tkt)),
+		TicketRef: tkt.Key,
+		Matchers:  matchers,
+	}
+	event := ActionEvent{Action: ActionRecreate, TicketKey: tkt.Key, NewEndsAt: newSilence.EndsAt}
+	if !s.beforeAction(event) {
+		return nil, nil
+	}
+	log.Printf("Ticket %s was reopened, recreating silence %s until %v", tkt.Key, rec.SilenceID, newSilence.EndsAt)
+	silenceID, err := s.alertManager.CreateSilence(newSilence)
+	event.SilenceID = silenceID
+	s.afterAction(event, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create silence: %w", err)
+	}
+	newSilence.ID = silenceID
+	result.SilencesCreated++
+	result.SilencesRecreated++
+
+	if tkt.SilenceRef != "" {
+		tkt.SilenceRef = silenceID
+		if err := s.ticketSystem.UpdateTicket(tkt); err != nil {
+			log.Printf("Warning: failed to update silence reference of ticket %s: %v", tkt.Key, err)
+		}
+	}
+	s.trackLifecycle(result, tkt, s.lifecycleLabelFor(newSilence.EndsAt, false))
+	s.updateDueDate(tkt.Key, newSilence.EndsAt)
+	s.relinkSilence(tkt.Key, rec.SilenceID, silenceID)
+	msg := fmt.Sprintf("The ticket was reopened, so silence %s, deleted when it was resolved, has been recreated as silence %s until %v.",
+		rec.SilenceID, s.silenceRef(silenceID), newSilence.EndsAt.Format(time.RFC3339))
+	if err := s.ticketSystem.AddComment(tkt.Key, msg+silenceDefinition(newSilence)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+	result.Managed = append(result.Managed, ManagedSilence{
+		SilenceID:    silenceID,
+		TicketRef:    tkt.Key,
+		TicketStatus: string(tkt.Status),
+		EndsAt:       newSilence.EndsAt,
+		Health:       HealthHealthy,
+	})
+	return newSilence, nil
+}
@@ -0,0 +1,115 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func newReopenSync(am *mockAlertManager, ts *mockTicketSystem, store state.Store) *Synchronizer {
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.RecreateOnReopenFor = 7 * 24 * time.Hour
+	return New(am, ts, WithConfig(cfg), WithStateStore(store))
+}
+
+func TestSync_RecreatesSilenceOfReopenedTicket(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	am.silences["silence-a"] = &alertmanager.Silence{
+		ID:        "silence-a",
+		CreatedBy: "alice",
+		Comment:   "Disk replacement",
+		TicketRef: "PROJ-1",
+		StartsAt:  time.Now().Add(-time.Hour),
+		EndsAt:    time.Now().Add(12 * time.Hour),
+		Matchers:  []alertmanager.Matcher{{Name: "instance", Value: "db-.*", IsRegex: true, IsEqual: true}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusResolved}
+
+	// Resolving the ticket deletes its silence and remembers its definition
+	if _, err := newReopenSync(am, ts, store).Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(am.silences) != 0 {
+		t.Fatalf("Expected the silence to be deleted, got %v", am.silences)
+	}
+	st, _ := store.Load()
+	if rec, ok := st.Resolved["PROJ-1"]; !ok || rec.SilenceID != "silence-a" || len(rec.Matchers) != 1 {
+		t.Fatalf("Expected the deleted silence to be remembered, got %+v", st.Resolved)
+	}
+
+	// A resolved ticket stays without a silence
+	result, err := newReopenSync(am, ts, store).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesRecreated != 0 || len(am.silences) != 0 {
+		t.Errorf("Expected no silence while the ticket is resolved, got %+v", result)
+	}
+
+	// Reopening the ticket recreates the silence from its definition
+	ts.tickets["PROJ-1"].Status = ticket.StatusOpen
+	result, err = newReopenSync(am, ts, store).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesRecreated != 1 || len(am.silences) != 1 {
+		t.Fatalf("Expected the silence to be recreated, got %+v", result)
+	}
+	for _, silence := range am.silences {
+		if silence.TicketRef != "PROJ-1" || silence.CreatedBy != "alice" || silence.Comment != "Disk replacement" ||
+			len(silence.Matchers) != 1 || silence.Matchers[0].String() != "instance=~db-.*" {
+			t.Errorf("Expected the silence to match its definition, got %+v", silence)
+		}
+	}
+	comments := ts.comments["PROJ-1"]
+	if len(comments) == 0 || !strings.Contains(comments[len(comments)-1], "was reopened") {
+		t.Errorf("Expected a comment on the reopened ticket, got %v", comments)
+	}
+	if st, _ := store.Load(); len(st.Resolved) != 0 {
+		t.Errorf("Expected the ticket to be forgotten, got %+v", st.Resolved)
+	}
+
+	// The recreated silence is not recreated again
+	result, err = newReopenSync(am, ts, store).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesRecreated != 0 || len(am.silences) != 1 {
+		t.Errorf("Expected no further silences, got %+v", result)
+	}
+}
+
+func TestSync_ForgetsResolvedTickets(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	st := state.NewState()
+	st.Resolved["PROJ-1"] = state.ResolvedSilence{SilenceID: "old-1", Matchers: []string{"job=api"}, ResolvedAt: time.Now().Add(-30 * 24 * time.Hour)}
+	st.Resolved["PROJ-2"] = state.ResolvedSilence{SilenceID: "old-2", Matchers: []string{"job=web"}, ResolvedAt: time.Now()}
+	st.Resolved["PROJ-3"] = state.ResolvedSilence{SilenceID: "old-3", Matchers: []string{"job=db"}, ResolvedAt: time.Now()}
+	store.Save(st)
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	// PROJ-2 was deleted; PROJ-3 is silenced again because its alert refired
+	ts.tickets["PROJ-3"] = &ticket.Ticket{Key: "PROJ-3", Status: ticket.StatusOpen}
+	am.silences["silence-r"] = &alertmanager.Silence{ID: "silence-r", TicketRef: "PROJ-3", StartsAt: time.Now(), EndsAt: time.Now().Add(12 * time.Hour)}
+	ts.getTicketsErr = errors.New("issue does not exist")
+
+	result, err := newReopenSync(am, ts, store).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesRecreated != 0 || len(result.Errors) != 0 {
+		t.Errorf("Expected nothing to be recreated, got %+v", result)
+	}
+	if st, _ := store.Load(); len(st.Resolved) != 0 {
+		t.Errorf("Expected expired, deleted and silenced tickets to be forgotten, got %+v", st.Resolved)
+	}
+}
@@ -0,0 +1,115 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+)
+
+// RestoredSilence describes a backed up silence recreated by RestoreSilences
+type RestoredSilence struct {
+	PreviousID string
+	SilenceID  string
+	TicketRef  string
+	EndsAt     time.Time
+}
+
+// RestoreSilences recreates the backed up silences missing from Alertmanager, e.g. after
+// it lost its state, and points their tickets at the new silences. Silences that are
+// still active, that ended since the backup, or whose ticket already has another active
+// silence are skipped. The restored silences are returned, also when an error stops
+// the restore.
+func (s *Synchronizer) RestoreSilences(backup []*alertmanager.Silence) ([]RestoredSilence, error) {
+	active, err := s.alertManager.ListSilences()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	activeIDs := make(map[string]bool, len(active))
+	activeTickets := make(map[string]bool, len(active))
+	for _, silence := range active {
+		activeIDs[silence.ID] = true
+		if silence.TicketRef != "" {
+			activeTickets[silence.TicketRef] = true
+		}
+	}
+
+	now := s.now()
+	restored := make([]RestoredSilence, 0)
+	for _, silence := range backup {
+		switch {
+		case activeIDs[silence.ID]:
+			continue
+		case !silence.EndsAt.After(now):
+			log.Printf("Backed up silence %s ended at %v, not restoring it", silence.ID, silence.EndsAt)
+			continue
+		case activeTickets[silence.TicketRef]:
+			log.Printf("Ticket %s already has an active silence, not restoring silence %s", silence.TicketRef, silence.ID)
+			continue
+		}
+
+		silenceID, err := s.restoreSilence(silence, now, "was missing from Alertmanager and has been restored from a backup")
+		if err != nil {
+			return restored, fmt.Errorf("failed to restore silence %s: %w", silence.ID, err)
+		}
+		activeTickets[silence.TicketRef] = true
+		restored = append(restored, RestoredSilence{
+			PreviousID: silence.ID,
+			SilenceID:  silenceID,
+			TicketRef:  silence.TicketRef,
+			EndsAt:     silence.EndsAt,
+		})
+	}
+	return restored, nil
+}
+
+// restoreSilence recreates a silence under a new ID and updates its ticket to reference it.
+// reason tells the ticket what happened to the silence, e.g. "was missing from Alertmanager".
+func (s *Synchronizer) restoreSilence(silence *alertmanager.Silence, now time.Time, reason string) (string, error) {
+	startsAt := silence.StartsAt
+	if startsAt.Before(now) {
+		startsAt = now
+	}
+	newSilence := &alertmanager.Silence{
+		CreatedBy: silence.CreatedBy,
+		Comment:   silence.Comment,
+		StartsAt:  startsAt,
+		EndsAt:    silence.EndsAt,
+		TicketRef: silence.TicketRef,
+		Matchers:  silence.Matchers,
+	}
+	event := ActionEvent{Action: ActionCreate, TicketKey: silence.TicketRef, NewEndsAt: silence.EndsAt}
+	if err := s.vetoAction(event); err != nil {
+		return "", err
+	}
+	silenceID, err := s.alertManager.CreateSilence(newSilence)
+	event.SilenceID = silenceID
+	s.afterAction(event, err)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Restored silence %s as %s for ticket %s", silence.ID, silenceID, silence.TicketRef)
+
+	if silence.TicketRef == "" {
+		return silenceID, nil
+	}
+	tkt, err := s.ticketSystem.GetTicket(silence.TicketRef)
+	if err != nil {
+		log.Printf("Warning: failed to get ticket %s: %v", silence.TicketRef, err)
+		return silenceID, nil
+	}
+	if tkt.SilenceRef == "" || tkt.SilenceRef == silence.ID {
+		tkt.SilenceRef = silenceID
+		if err := s.ticketSystem.UpdateTicket(tkt); err != nil {
+			return silenceID, fmt.Errorf("failed to update ticket %s: %w", tkt.Key, err)
+		}
+	}
+	s.relinkSilence(tkt.Key, silence.ID, silenceID)
+	msg := fmt.Sprintf("Silence %s %s as silence %s until %v.",
+		silence.ID, reason, s.silenceRef(silenceID), silence.EndsAt.Format(time.RFC3339))
+	if err := s.ticketSystem.AddComment(tkt.Key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+	return silenceID, nil
+}
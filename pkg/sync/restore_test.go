@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestRestoreSilences(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	matchers := []alertmanager.Matcher{{Name: "alertname", Value: "DiskFull", IsEqual: true}}
+
+	am.silences["kept"] = &alertmanager.Silence{ID: "kept", TicketRef: "PROJ-1", EndsAt: now.Add(time.Hour), Matchers: matchers}
+	am.silences["other"] = &alertmanager.Silence{ID: "other", TicketRef: "PROJ-4", EndsAt: now.Add(time.Hour), Matchers: matchers}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusOpen, SilenceRef: "lost"}
+
+	backup := []*alertmanager.Silence{
+		{ID: "kept", TicketRef: "PROJ-1", EndsAt: now.Add(time.Hour), Matchers: matchers},
+		{ID: "lost", TicketRef: "PROJ-2", CreatedBy: "alice", Comment: "Disk swap", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(48 * time.Hour), Matchers: matchers},
+		{ID: "ended", TicketRef: "PROJ-3", EndsAt: now.Add(-time.Minute), Matchers: matchers},
+		{ID: "replaced", TicketRef: "PROJ-4", EndsAt: now.Add(time.Hour), Matchers: matchers},
+	}
+
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+	restored, err := sync.RestoreSilences(backup)
+	if err != nil {
+		t.Fatalf("RestoreSilences() failed: %v", err)
+	}
+	if len(restored) != 1 || restored[0].PreviousID != "lost" || restored[0].TicketRef != "PROJ-2" {
+		t.Fatalf("Expected only the lost silence to be restored, got %+v", restored)
+	}
+
+	silence := am.silences[restored[0].SilenceID]
+	if silence == nil || silence.Comment != "Disk swap" || !silence.EndsAt.Equal(backup[1].EndsAt) || silence.StartsAt.Before(now) {
+		t.Errorf("Unexpected restored silence: %+v", silence)
+	}
+	if ts.tickets["PROJ-2"].SilenceRef != restored[0].SilenceID {
+		t.Errorf("Expected the ticket to reference the restored silence, got %s", ts.tickets["PROJ-2"].SilenceRef)
+	}
+	if comments := ts.comments["PROJ-2"]; len(comments) != 1 || !strings.Contains(comments[0], "restored from a backup") {
+		t.Errorf("Expected a restore comment, got %v", comments)
+	}
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/rules"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// SetRuleSource sets the source of the existing alerting rules. Silences whose alertname
+// matches no rule are no longer extended, and their ticket is asked to clean them up.
+func (s *Synchronizer) SetRuleSource(source rules.Source) {
+	s.rules = source
+}
+
+// loadAlertNames fetches the alerting rules for the run. Until it succeeds no silence is
+// treated as muting a removed rule.
+func (s *Synchronizer) loadAlertNames() error {
+	names, err := s.rules.AlertNames()
+	if err != nil {
+		return err
+	}
+	s.alertNames = names
+	return nil
+}
+
+// removedRule returns the alertname matched by a silence if no alerting rule has it.
+// Silences without an alertname matcher, or excluding an alertname, are never reported.
+func (s *Synchronizer) removedRule(silence *alertmanager.Silence) (string, bool) {
+	if s.alertNames == nil {
+		return "", false
+	}
+	for _, m := range silence.Matchers {
+		if m.Name != "alertname" || !m.IsEqual {
+			continue
+		}
+		if !m.IsRegex {
+			for _, name := range s.alertNames {
+				if name == m.Value {
+					return "", false
+				}
+			}
+			return m.Value, true
+		}
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return "", false
+		}
+		for _, name := range s.alertNames {
+			if re.MatchString(name) {
+				return "", false
+			}
+		}
+		return m.Value, true
+	}
+	return "", false
+}
+
+// ruleRemovedMarker identifies comments suggesting the cleanup of a silence of a removed rule
+const ruleRemovedMarker = "matches no existing alerting rule"
+
+// removedRuleRetention is how long the state remembers that a ticket was asked to clean
+// up a silence, well beyond the silence ending as it is no longer extended
+const removedRuleRetention = 30 * 24 * time.Hour
+
+// withholdRemovedRule reports whether a silence due for extension mutes an alerting rule
+// that no longer exists and is left to expire instead. The ticket is told once.
+func (s *Synchronizer) withholdRemovedRule(silence *alertmanager.Silence, tkt *ticket.Ticket, result *SyncResult) bool {
+	alertName, removed := s.removedRule(silence)
+	if !removed {
+		return false
+	}
+	log.Printf("Silence %s matches alertname %q of no existing alerting rule, not extending it", silence.ID, alertName)
+	result.RemovedRuleSilences++
+
+	st, err := s.stateStore.Load()
+	if err != nil {
+		log.Printf("Warning: failed to load state: %v", err)
+		return true
+	}
+	if _, ok := st.RemovedRules[silence.ID]; ok {
+		return true
+	}
+	told, err := s.toldRemovedRule(silence, tkt)
+	if err != nil {
+		log.Printf("Warning: failed to get comments for ticket %s: %v", tkt.Key, err)
+		return true
+	}
+	if !told {
+		msg := fmt.Sprintf("Silence %s %s: alertname %q is no longer defined, so the rule appears to have been removed or renamed. "+
+			"The silence will not be extended and expires at %s. Please delete the silence and resolve this ticket if it is no longer needed.",
+			s.silenceRef(silence.ID), ruleRemovedMarker, alertName, silence.EndsAt.Format(time.RFC3339))
+		s.addComment(tkt.Key, msg)
+	}
+
+	now := s.now()
+	if st.RemovedRules == nil {
+		st.RemovedRules = make(map[string]time.Time)
+	}
+	for id, told := range st.RemovedRules {
+		if now.Sub(told) > removedRuleRetention {
+			delete(st.RemovedRules, id)
+		}
+	}
+	st.RemovedRules[silence.ID] = now
+	if err := s.stateStore.Save(st); err != nil {
+		log.Printf("Warning: failed to save state: %v", err)
+	}
+	return true
+}
+
+// toldRemovedRule reports whether the ticket was already asked to clean up the silence,
+// for tickets asked before the state recorded it
+func (s *Synchronizer) toldRemovedRule(silence *alertmanager.Silence, tkt *ticket.Ticket) (bool, error) {
+	comments, err := s.ticketSystem.GetComments(tkt.Key)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range comments {
+		if strings.Contains(c.Body, silence.ID) && strings.Contains(c.Body, ruleRemovedMarker) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
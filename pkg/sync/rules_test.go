@@ -0,0 +1,136 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// staticRules is a rule source with a fixed set of alerting rules
+type staticRules struct {
+	names []string
+	err   error
+}
+
+func (r *staticRules) AlertNames() ([]string, error) {
+	return r.names, r.err
+}
+
+func TestSync_RemovedRules(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	for id, matcher := range map[string]alertmanager.Matcher{
+		"exists":   {Name: "alertname", Value: "DiskFull", IsEqual: true},
+		"removed":  {Name: "alertname", Value: "OldAlert", IsEqual: true},
+		"regex":    {Name: "alertname", Value: "Disk.*", IsRegex: true, IsEqual: true},
+		"excluded": {Name: "alertname", Value: "OldAlert", IsEqual: false},
+		"instance": {Name: "instance", Value: "db-1", IsEqual: true},
+	} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: "PROJ-" + id,
+			Matchers:  []alertmanager.Matcher{matcher},
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(time.Hour),
+		}
+		ts.tickets["PROJ-"+id] = &ticket.Ticket{Key: "PROJ-" + id, Status: ticket.StatusOpen}
+	}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	s := New(am, ts, WithConfig(cfg), WithRuleSource(&staticRules{names: []string{"DiskFull", "NodeDown"}}))
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	for _, p := range plan {
+		if want := p.SilenceID == "removed"; (p.Action == ActionLapse) != want {
+			t.Errorf("Expected silence %s to lapse=%v, got %s", p.SilenceID, want, p.Action)
+		}
+	}
+	for run := 0; run < 2; run++ {
+		result, err := s.Sync()
+		if err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+		if result.RemovedRuleSilences != 1 {
+			t.Errorf("Run %d: expected 1 silence of a removed rule, got %d", run, result.RemovedRuleSilences)
+		}
+	}
+
+	if am.silences["removed"].EndsAt.After(now.Add(time.Hour)) {
+		t.Error("Expected the silence of the removed rule not to be extended")
+	}
+	for _, id := range []string{"exists", "regex", "excluded", "instance"} {
+		if !am.silences[id].EndsAt.After(now.Add(time.Hour)) {
+			t.Errorf("Expected silence %s to be extended", id)
+		}
+	}
+	if comments := ts.comments["PROJ-removed"]; len(comments) != 1 || !strings.Contains(comments[0], ruleRemovedMarker) {
+		t.Errorf("Expected one cleanup comment, got %v", comments)
+	}
+}
+
+func TestSync_RemovedRulesUnavailable(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["removed"] = &alertmanager.Silence{
+		ID:        "removed",
+		TicketRef: "PROJ-1",
+		Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "OldAlert", IsEqual: true}},
+		StartsAt:  time.Now().Add(-time.Hour),
+		EndsAt:    time.Now().Add(time.Hour),
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	result, err := New(am, ts, WithConfig(cfg), WithRuleSource(&staticRules{err: errors.New("ruler unavailable")})).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(result.Errors) != 1 || result.RemovedRuleSilences != 0 || result.SilencesExtended != 1 {
+		t.Errorf("Expected the silence to be extended despite the error, got %+v", result)
+	}
+}
+
+func TestSync_RemovedRulesConsolidated(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	for _, id := range []string{"silence-1", "silence-2"} {
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: "PROJ-1",
+			Matchers:  []alertmanager.Matcher{{Name: "alertname", Value: "OldAlert", IsEqual: true}},
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(time.Hour),
+		}
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	s := New(am, ts, WithConfig(cfg), WithRuleSource(&staticRules{names: []string{"DiskFull"}}))
+	if _, err := s.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	// Both silences are reported in one comment
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || strings.Count(comments[0], ruleRemovedMarker) != 2 {
+		t.Errorf("Expected one consolidated cleanup comment, got %v", comments)
+	}
+
+	// Later runs remember the request instead of reading the comments again
+	reads := ts.commentReads
+	if _, err := s.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if ts.commentReads != reads || len(ts.comments["PROJ-1"]) != 1 {
+		t.Errorf("Expected no further comment reads or comments, got %d reads and %v", ts.commentReads-reads, ts.comments["PROJ-1"])
+	}
+}
@@ -0,0 +1,139 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// Notifier posts messages to a team channel, e.g. through a Slack incoming webhook
+type Notifier interface {
+	Notify(text string) error
+}
+
+// SetNotifier sets the channel notified of escalated tickets
+func (s *Synchronizer) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// silenceSeverity returns the lowercase severity a silence mutes, from its severity
+// matcher, or "" if it does not match a single severity
+func silenceSeverity(silence *alertmanager.Silence) string {
+	for _, m := range silence.Matchers {
+		if m.Name == severityLabel && m.IsEqual && !m.IsRegex {
+			return strings.ToLower(m.Value)
+		}
+	}
+	return ""
+}
+
+// checkSLAs tracks since when the alerts of each ticket with silences of a severity in
+// SeveritySLAs have been silenced, and escalates tickets silenced longer than the SLA of
+// their severity once. A ticket whose silences mute several severities is held to the
+// strictest SLA. Tickets no longer linked to a managed silence are forgotten.
+func (s *Synchronizer) checkSLAs(silences []*alertmanager.Silence, result *SyncResult) error {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if st.SLAs == nil {
+		st.SLAs = make(map[string]state.SLARecord)
+	}
+
+	managed := make(map[string]bool, len(result.Managed))
+	for _, m := range result.Managed {
+		managed[m.SilenceID] = true
+	}
+	tracked := make(map[string]bool)
+	for _, silence := range silences {
+		severity := silenceSeverity(silence)
+		limit, ok := s.config.SeveritySLAs[severity]
+		if !ok || !managed[silence.ID] {
+			continue
+		}
+		key := silence.TicketRef
+		rec, exists := st.SLAs[key]
+		if !exists || silence.StartsAt.Before(rec.Since) {
+			rec.Since = silence.StartsAt
+		}
+		if current, ok := s.config.SeveritySLAs[rec.Severity]; !ok || limit < current {
+			rec.Severity = severity
+		}
+		st.SLAs[key] = rec
+		tracked[key] = true
+	}
+
+	keys := make([]string, 0, len(st.SLAs))
+	for key := range st.SLAs {
+		if !tracked[key] {
+			delete(st.SLAs, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	now := s.now()
+	for _, key := range keys {
+		rec := st.SLAs[key]
+		limit := s.config.SeveritySLAs[rec.Severity]
+		if !rec.Escalated.IsZero() || now.Sub(rec.Since) < limit {
+			continue
+		}
+		tkt, err := s.getTicket(key)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("ticket %s: %w", key, err))
+			continue
+		}
+		s.escalateSLA(tkt, rec, limit)
+		rec.Escalated = now
+		st.SLAs[key] = rec
+		result.SLAEscalations++
+	}
+
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// escalateSLA raises the priority of a ticket whose alerts have been silenced longer
+// than the SLA of their severity, labels it, and tells the ticket and the channel
+func (s *Synchronizer) escalateSLA(tkt *ticket.Ticket, rec state.SLARecord, limit time.Duration) {
+	log.Printf("Alerts of severity %s on ticket %s have been silenced since %s, longer than the SLA of %v, escalating",
+		rec.Severity, tkt.Key, rec.Since.Format(time.RFC3339), limit)
+
+	if priority := s.config.SLAPriority; priority != "" && !strings.EqualFold(priority, tkt.Priority) {
+		if err := s.ticketSystem.SetPriority(tkt.Key, priority); err != nil {
+			log.Printf("Warning: failed to set priority of ticket %s: %v", tkt.Key, err)
+		} else {
+			tkt.Priority = priority
+		}
+	}
+	if s.config.SLALabel != "" {
+		if err := s.ticketSystem.UpdateLabels(tkt.Key, []string{s.config.SLALabel}, nil); err != nil {
+			log.Printf("Warning: failed to label ticket %s: %v", tkt.Key, err)
+		}
+	}
+
+	msg := fmt.Sprintf("Alerts of severity %s have been silenced since %s, longer than the SLA of %v for this severity. "+
+		"This ticket has been escalated; please fix the underlying problem or resolve the ticket.",
+		rec.Severity, rec.Since.Format(time.RFC3339), limit)
+	if err := s.ticketSystem.AddComment(tkt.Key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+
+	if s.notifier != nil {
+		text := fmt.Sprintf("Ticket %s (%s) has kept alerts of severity %s silenced since %s, longer than the SLA of %v, and has been escalated.",
+			tkt.Key, tkt.Summary, rec.Severity, rec.Since.Format(time.RFC3339), limit)
+		if err := s.notifier.Notify(text); err != nil {
+			log.Printf("Warning: failed to notify channel of escalated ticket %s: %v", tkt.Key, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// recordingNotifier records the messages posted to the channel
+type recordingNotifier struct {
+	messages []string
+}
+
+func (n *recordingNotifier) Notify(text string) error {
+	n.messages = append(n.messages, text)
+	return nil
+}
+
+func TestSync_SeveritySLAs(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	store := state.NewMemoryStore()
+	notifier := &recordingNotifier{}
+	now := time.Now()
+	for id, severity := range map[string]string{"old-critical": "critical", "new-critical": "critical", "old-warning": "warning", "old-info": "info"} {
+		age := time.Hour
+		if id != "new-critical" {
+			age = 96 * time.Hour
+		}
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			TicketRef: "PROJ-" + id,
+			Matchers:  []alertmanager.Matcher{{Name: "severity", Value: severity, IsEqual: true}},
+			StartsAt:  now.Add(-age),
+			EndsAt:    now.Add(72 * time.Hour),
+		}
+		ts.tickets["PROJ-"+id] = &ticket.Ticket{Key: "PROJ-" + id, Status: ticket.StatusOpen, Priority: "Medium"}
+	}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.SeveritySLAs = map[string]time.Duration{"critical": 72 * time.Hour, "warning": 14 * 24 * time.Hour}
+	cfg.SLAPriority = "Highest"
+	cfg.SLALabel = "silence-sla-breached"
+	for run := 0; run < 2; run++ {
+		result, err := New(am, ts, WithConfig(cfg), WithStateStore(store), WithNotifier(notifier)).Sync()
+		if err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+		if want := 1 - run; result.SLAEscalations != want {
+			t.Errorf("Run %d: expected %d escalation(s), got %d", run, want, result.SLAEscalations)
+		}
+	}
+
+	escalated := ts.tickets["PROJ-old-critical"]
+	if escalated.Priority != "Highest" || !slices.Contains(escalated.Labels, "silence-sla-breached") {
+		t.Errorf("Expected the ticket to be escalated, got priority %s and labels %v", escalated.Priority, escalated.Labels)
+	}
+	if len(ts.comments["PROJ-old-critical"]) != 1 || len(notifier.messages) != 1 {
+		t.Errorf("Expected one comment and one notification, got %v and %v", ts.comments["PROJ-old-critical"], notifier.messages)
+	}
+	for _, id := range []string{"new-critical", "old-warning", "old-info"} {
+		if tkt := ts.tickets["PROJ-"+id]; tkt.Priority != "Medium" || len(tkt.Labels) != 0 {
+			t.Errorf("Expected ticket of %s not to be escalated, got %+v", id, tkt)
+		}
+	}
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.SLAs) != 3 {
+		t.Errorf("Expected the tickets of the 3 silences with an SLA to be tracked, got %v", st.SLAs)
+	}
+}
@@ -0,0 +1,112 @@
+package sync
+
+import (
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// ActionOutcome is an action taken by a synchronization run and its outcome
+type ActionOutcome struct {
+	Action    string    `json:"action"`
+	SilenceID string    `json:"silenceID,omitempty"`
+	TicketKey string    `json:"ticketKey,omitempty"`
+	NewEndsAt time.Time `json:"newEndsAt,omitzero"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// CategorizedError is an error of a synchronization run with the step it occurred in and
+// the kind of failure
+type CategorizedError struct {
+	Category string `json:"category"`
+	Type     string `json:"type"` // auth, not-found, rate-limited, transient or permanent
+	Message  string `json:"message"`
+}
+
+// Summary is the machine-readable outcome of a synchronization run, for CI pipelines and
+// log processors
+type Summary struct {
+	StartedAt  time.Time          `json:"startedAt"`
+	FinishedAt time.Time          `json:"finishedAt"`
+	Success    bool               `json:"success"`
+	Aborted    string             `json:"aborted,omitempty"` // Error that stopped the run before it completed
+	RunID      string             `json:"runID,omitempty"`   // ID of the run in the undo journal
+	Counts     map[string]int     `json:"counts"`
+	Actions    []ActionOutcome    `json:"actions"`
+	Managed    []ManagedSilence   `json:"managed"`
+	Errors     []CategorizedError `json:"errors"`
+}
+
+// Counts returns the counts of the result by name
+func (r *SyncResult) Counts() map[string]int {
+	return map[string]int{
+		"silencesExtended":       r.SilencesExtended,
+		"silencesDeleted":        r.SilencesDeleted,
+		"silencesCreated":        r.SilencesCreated,
+		"ticketsReopened":        r.TicketsReopened,
+		"ticketsClosed":          r.TicketsClosed,
+		"directivesApplied":      r.DirectivesApplied,
+		"extensionsWithheld":     r.ExtensionsWithheld,
+		"labelsUpdated":          r.LabelsUpdated,
+		"matchersUpdated":        r.MatchersUpdated,
+		"maintenanceCreated":     r.MaintenanceCreated,
+		"maintenanceRetired":     r.MaintenanceRetired,
+		"gitOpsApplied":          r.GitOpsApplied,
+		"gitOpsPruned":           r.GitOpsPruned,
+		"changeCreated":          r.ChangeSilencesCreated,
+		"changeRemoved":          r.ChangeSilencesRemoved,
+		"ticketsDiscovered":      r.TicketsDiscovered,
+		"followUpTickets":        r.FollowUpTickets,
+		"subTasksCreated":        r.SubTasksCreated,
+		"refireComments":         r.RefireComments,
+		"silencesRecreated":      r.SilencesRecreated,
+		"idleSilencesLapsed":     r.IdleSilencesLapsed,
+		"removedRuleSilences":    r.RemovedRuleSilences,
+		"extensionCapsReached":   r.ExtensionCapsReached,
+		"silencesDrifted":        r.SilencesDrifted,
+		"slaEscalations":         r.SLAEscalations,
+		"acknowledgementsLinked": r.AcknowledgementsLinked,
+		"foreignTicketRefs":      r.ForeignTicketRefs,
+	}
+}
+
+// ErrorsByCategory counts the errors of the run by the kind of failure, such as auth or
+// transient, as opposed to ErrorCategories naming the step each error occurred in
+func (r *SyncResult) ErrorsByCategory() map[apierror.Category]int {
+	return apierror.Count(r.Errors)
+}
+
+// NewSummary summarizes the run started at started and finished at finished. result may
+// be nil, and runErr is the error of a run that did not complete.
+func NewSummary(result *SyncResult, started, finished time.Time, runErr error) *Summary {
+	summary := &Summary{
+		StartedAt:  started,
+		FinishedAt: finished,
+		Counts:     (&SyncResult{}).Counts(),
+		Actions:    []ActionOutcome{},
+		Managed:    []ManagedSilence{},
+		Errors:     []CategorizedError{},
+	}
+	if runErr != nil {
+		summary.Aborted = runErr.Error()
+	}
+	if result != nil {
+		summary.RunID = result.RunID
+		summary.Counts = result.Counts()
+		summary.Actions = append(summary.Actions, result.Actions...)
+		summary.Managed = append(summary.Managed, result.Managed...)
+		for i, err := range result.Errors {
+			category := "other"
+			if i < len(result.ErrorCategories) {
+				category = result.ErrorCategories[i]
+			}
+			summary.Errors = append(summary.Errors, CategorizedError{
+				Category: category,
+				Type:     string(apierror.CategoryOf(err)),
+				Message:  err.Error(),
+			})
+		}
+	}
+	summary.Success = runErr == nil && len(summary.Errors) == 0
+	return summary
+}
@@ -0,0 +1,73 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/apierror"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestNewSummary(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID: "silence-1", TicketRef: "PROJ-1", StartsAt: time.Now(), EndsAt: time.Now().Add(12 * time.Hour),
+	}
+	am.silences["silence-2"] = &alertmanager.Silence{
+		ID: "silence-2", TicketRef: "PROJ-404", StartsAt: time.Now(), EndsAt: time.Now().Add(12 * time.Hour),
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusResolved}
+
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	started := time.Now()
+	result, err := New(am, ts, WithConfig(cfg)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	summary := NewSummary(result, started, time.Now(), nil)
+	if summary.Success {
+		t.Error("Expected a run with errors not to succeed")
+	}
+	if summary.Counts["silencesDeleted"] != 1 {
+		t.Errorf("Expected 1 deleted silence, got %v", summary.Counts)
+	}
+	if len(summary.Actions) != 1 || summary.Actions[0].Action != ActionDelete || summary.Actions[0].SilenceID != "silence-1" || summary.Actions[0].Error != "" {
+		t.Errorf("Expected the deletion of silence-1, got %+v", summary.Actions)
+	}
+	if len(summary.Errors) != 1 || summary.Errors[0].Category != "silence" || summary.Errors[0].Type != "not-found" {
+		t.Errorf("Expected the missing ticket as a not-found silence error, got %+v", summary.Errors)
+	}
+	if counts := result.ErrorsByCategory(); counts[apierror.NotFound] != 1 {
+		t.Errorf("Expected 1 not-found error, got %v", counts)
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	for _, key := range []string{"startedAt", "finishedAt", "success", "counts", "actions", "managed", "errors"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("Expected %q in the summary, got %s", key, data)
+		}
+	}
+}
+
+func TestNewSummary_Aborted(t *testing.T) {
+	summary := NewSummary(nil, time.Now(), time.Now(), errors.New("failed to list silences"))
+	if summary.Success || summary.Aborted != "failed to list silences" {
+		t.Errorf("Expected an aborted run, got %+v", summary)
+	}
+	if summary.Actions == nil || summary.Errors == nil || summary.Counts["silencesExtended"] != 0 {
+		t.Errorf("Expected empty lists and zero counts, got %+v", summary)
+	}
+}
@@ -0,0 +1,43 @@
+package sync
+
+import "errors"
+
+// ErrActionDisabled vetoes the actions turned off by ActionSwitches
+var ErrActionDisabled = errors.New("action disabled")
+
+// ActionSwitches turns kinds of actions off, for rolling silence-manager out step by step,
+// e.g. extending silences only until deletions are trusted. Disabled actions are vetoed
+// like those of any other hook: extensions, deletions, reopenings and recreations are
+// skipped and logged, including those of maintenance windows, changes and declared
+// silences, directives on tickets are rejected, and creations requested by users or
+// sources fail. Actions an
+// operator takes on one silence through ExtendSilence or DeleteSilence are not affected.
+type ActionSwitches struct {
+	NopHooks
+	Extend bool // ActionExtend
+	Delete bool // ActionDelete
+	Reopen bool // ActionReopen
+	Create bool // ActionCreate and ActionRecreate
+}
+
+// AllActions enables every kind of action
+func AllActions() ActionSwitches {
+	return ActionSwitches{Extend: true, Delete: true, Reopen: true, Create: true}
+}
+
+// AllEnabled reports whether no kind of action is turned off
+func (a ActionSwitches) AllEnabled() bool {
+	return a.Extend && a.Delete && a.Reopen && a.Create
+}
+
+func (a ActionSwitches) OnExtend(ActionEvent) error { return allowed(a.Extend) }
+func (a ActionSwitches) OnDelete(ActionEvent) error { return allowed(a.Delete) }
+func (a ActionSwitches) OnReopen(ActionEvent) error { return allowed(a.Reopen) }
+func (a ActionSwitches) OnCreate(ActionEvent) error { return allowed(a.Create) }
+
+func allowed(enabled bool) error {
+	if !enabled {
+		return ErrActionDisabled
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/calendar"
+	"github.com/conallob/silence-manager/pkg/gitops"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestActionSwitches(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	now := time.Now()
+	am.silences["expiring"] = &alertmanager.Silence{ID: "expiring", TicketRef: "PROJ-1", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+	am.silences["resolved"] = &alertmanager.Silence{ID: "resolved", TicketRef: "PROJ-2", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusResolved}
+	am.alerts = []*alertmanager.Alert{{Labels: map[string]string{"alertname": "TestAlert", "ticket": "PROJ-3"}}}
+	ts.tickets["PROJ-3"] = &ticket.Ticket{Key: "PROJ-3", Status: ticket.StatusClosed}
+
+	// Extensions only
+	switches := ActionSwitches{Extend: true}
+	if switches.AllEnabled() || !AllActions().AllEnabled() {
+		t.Error("Unexpected AllEnabled()")
+	}
+	sync := New(am, ts, WithActionHooks(switches))
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesExtended != 1 || result.SilencesDeleted != 0 || len(ts.reopenedKeys) != 0 || len(result.Errors) != 0 {
+		t.Errorf("Expected only the extension, got extended=%d deleted=%d reopened=%v errors=%v",
+			result.SilencesExtended, result.SilencesDeleted, ts.reopenedKeys, result.Errors)
+	}
+	if _, ok := am.silences["resolved"]; !ok {
+		t.Error("Expected the silence of the resolved ticket to be kept")
+	}
+
+	matchers, _ := ParseMatchers([]string{"alertname=DiskFull"})
+	if _, err := sync.CreateSilence(SilenceDefinition{Matchers: matchers, Duration: time.Hour}); !errors.Is(err, ErrActionDisabled) {
+		t.Errorf("Expected creating a silence to be disabled, got %v", err)
+	}
+}
+
+func TestActionSwitches_AutomatedDeletions(t *testing.T) {
+	switches := AllActions()
+	switches.Delete = false
+	now := time.Now().Truncate(time.Second)
+
+	// A removed definition is not pruned, and stays recorded for a later run
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	source := &staticDefinitions{defs: []gitops.Definition{{Name: "disk-full", Matchers: []string{"alertname=DiskFull"}, Summary: "Known disk alerts"}}}
+	sync := New(am, ts, WithConfig(cfg), WithActionHooks(switches))
+	sync.SetGitOpsSource(source)
+	sync.Sync()
+	source.defs = nil
+	result, _ := sync.Sync()
+	if result.GitOpsPruned != 0 || len(am.silences) != 1 || len(am.deletedIDs) != 0 {
+		t.Errorf("Expected the declared silence to be kept, pruned %d with deleted %v", result.GitOpsPruned, am.deletedIDs)
+	}
+	if st, _ := sync.stateStore.Load(); len(st.GitOps) != 1 {
+		t.Errorf("Expected the definition to stay recorded, got %v", st.GitOps)
+	}
+
+	// A cancelled maintenance window keeps its silence and ticket
+	am = newMockAlertManager()
+	ts = newMockTicketSystem()
+	cal := &staticCalendar{events: []calendar.Event{{UID: "db@example.com", Summary: "Database upgrade", Description: "matchers: cluster=prod", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}}}
+	sync = New(am, ts, WithConfig(cfg), WithActionHooks(switches))
+	sync.SetMaintenanceCalendar(cal)
+	sync.Sync()
+	cal.events = nil
+	result, _ = sync.Sync()
+	if result.MaintenanceRetired != 0 || len(am.deletedIDs) != 0 || len(ts.closedKeys) != 0 {
+		t.Errorf("Expected the maintenance window to be kept, retired %d with deleted %v", result.MaintenanceRetired, am.deletedIDs)
+	}
+
+	// A closed change keeps its silence
+	am = newMockAlertManager()
+	changes := &changeTicketSystem{mockTicketSystem: newMockTicketSystem()}
+	chg := changeTicket("CHG-1", now.Add(time.Hour), now.Add(3*time.Hour), "service=checkout")
+	changes.tickets[chg.Key] = chg
+	changes.changes = []*ticket.Ticket{chg}
+	sync = New(am, changes, WithConfig(changeConfig()), WithActionHooks(switches))
+	sync.Sync()
+	changes.changes = nil
+	chg.Status = ticket.StatusClosed
+	result, _ = sync.Sync()
+	if result.ChangeSilencesRemoved != 0 || len(am.deletedIDs) != 0 {
+		t.Errorf("Expected the change silence to be kept, removed %d with deleted %v", result.ChangeSilencesRemoved, am.deletedIDs)
+	}
+
+	// An expire directive is rejected on the ticket
+	am = newMockAlertManager()
+	ts = newMockTicketSystem()
+	directives := DefaultConfig()
+	directives.CheckAlerts = false
+	directives.ProcessDirectives = true
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", EndsAt: now.Add(5 * 24 * time.Hour), TicketRef: "PROJ-1"}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.threads["PROJ-1"] = []*ticket.Comment{{ID: "10", Body: "/silence expire"}}
+	sync = New(am, ts, WithConfig(directives), WithActionHooks(switches))
+	result, _ = sync.Sync()
+	if result.DirectivesApplied != 0 || len(am.deletedIDs) != 0 {
+		t.Errorf("Expected the expire directive not to be applied, deleted %v", am.deletedIDs)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "rejected, delete vetoed") {
+		t.Errorf("Expected the directive to be rejected, got %v", comments)
+	}
+}
+
+func TestActionSwitches_ExtendDirective(t *testing.T) {
+	switches := AllActions()
+	switches.Extend = false
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	cfg.ProcessDirectives = true
+	am.silences["silence-1"] = &alertmanager.Silence{ID: "silence-1", EndsAt: time.Now().Add(5 * 24 * time.Hour), TicketRef: "PROJ-1"}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.threads["PROJ-1"] = []*ticket.Comment{{ID: "10", Body: "/silence extend 30d"}}
+
+	result, err := New(am, ts, WithConfig(cfg), WithActionHooks(switches)).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.DirectivesApplied != 0 || len(am.extendedIDs) != 0 {
+		t.Errorf("Expected the extend directive not to be applied, extended %v", am.extendedIDs)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) != 1 || !strings.Contains(comments[0], "rejected, extend vetoed") {
+		t.Errorf("Expected the directive to be rejected, got %v", comments)
+	}
+}
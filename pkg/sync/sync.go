@@ -1,12 +1,27 @@
 package sync
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/calendar"
+	"github.com/conallob/silence-manager/pkg/gitops"
 	"github.com/conallob/silence-manager/pkg/metrics"
+	"github.com/conallob/silence-manager/pkg/oncall"
+	"github.com/conallob/silence-manager/pkg/rules"
+	"github.com/conallob/silence-manager/pkg/schedule"
+	"github.com/conallob/silence-manager/pkg/slo"
+	"github.com/conallob/silence-manager/pkg/state"
+	"github.com/conallob/silence-manager/pkg/teams"
 	"github.com/conallob/silence-manager/pkg/ticket"
 )
 
@@ -20,6 +35,165 @@ type SyncConfig struct {
 	DefaultSilenceDuration time.Duration
 	// CheckAlerts determines whether to check for refired alerts
 	CheckAlerts bool
+	// ProcessDirectives enables "/silence ..." chat command directives in ticket comments
+	ProcessDirectives bool
+	// DurationLabelPrefix is the ticket label prefix (e.g. "silence-duration" for
+	// "silence-duration=30d") that overrides ExtensionDuration for a single silence
+	DurationLabelPrefix string
+	// DurationField is an optional ticket custom field ID whose value overrides ExtensionDuration
+	DurationField string
+	// DurationFieldUnit is the unit of a plain number in DurationField, as Jira number
+	// fields hold, e.g. 24h for days
+	DurationFieldUnit time.Duration
+	// RequireAssignee refuses to extend silences whose ticket has no assignee
+	RequireAssignee bool
+	// BusinessHours, when set, moves new silence end times into the next business-hours window
+	BusinessHours *schedule.BusinessHours
+	// LifecycleLabels maintains silence-active/silence-expiring-soon/silence-expired labels on tickets
+	LifecycleLabels bool
+	// ExtensionHistory keeps a line at the end of each silence comment recording how often
+	// the silence was extended, its previous end time and when it was last extended
+	ExtensionHistory bool
+	// AutoCloseAfter, when positive, closes tickets whose silence has expired once their
+	// alerts have not fired for this long. It requires a persistent state store.
+	AutoCloseAfter time.Duration
+	// SilenceUIURL is the external base URL of the Alertmanager web UI. When set, tickets
+	// get a remote link to each of their silences.
+	SilenceUIURL string
+	// UpdateDueDate sets the linked ticket's due date to the silence end time whenever a
+	// silence is created or extended
+	UpdateDueDate bool
+	// SeverityPriorities maps lowercase alert severities to the ticket priority set when
+	// a ticket is reopened for a refired alert
+	SeverityPriorities map[string]string
+	// PriorityExtensions maps lowercase ticket priorities to the extension duration used
+	// instead of ExtensionDuration, so that a priority change alters the extension policy
+	PriorityExtensions map[string]time.Duration
+	// SeveritySLAs maps lowercase alert severities to how long their alerts may stay
+	// silenced before the ticket is escalated; the severity is taken from the silence's
+	// severity matcher
+	SeveritySLAs map[string]time.Duration
+	// SLAPriority is the priority escalated tickets are raised to; empty keeps the priority
+	SLAPriority string
+	// SLALabel is added to escalated tickets; empty adds no label
+	SLALabel string
+	// AckPrefix starts the comments of karma acknowledgements, which are linked to a
+	// ticket and managed; empty leaves acknowledgements without a ticket alone
+	AckPrefix string
+	// TicketMatchers applies the matchers block that ticket owners maintain in the ticket
+	// description (or in MatchersField) to the linked silence
+	TicketMatchers bool
+	// MatchersField is an optional ticket custom field ID holding the matchers block
+	MatchersField string
+	// OnCallTeamLabel is the alert label naming the team whose on-call is assigned reopened tickets
+	OnCallTeamLabel string
+	// OnCallSchedules maps team names to on-call schedule identifiers
+	OnCallSchedules map[string]string
+	// Teams maps the label values of alerts and silences to their owning team. Tickets
+	// created for a team go to its Jira project, and its on-call schedule takes precedence
+	// over OnCallSchedules.
+	Teams *teams.Directory
+	// MaintenanceLookahead is how far ahead of a maintenance window its silence is created
+	MaintenanceLookahead time.Duration
+	// ChangeQuery, when set, finds the approved change tickets (e.g. a JQL query) whose
+	// planned windows are silenced. ChangeStartField, ChangeEndField and
+	// ChangeMatchersField are the ticket custom fields holding the planned start and end
+	// and the matchers of the silence. Without ChangeEndField, or when a ticket has no
+	// planned end, its silence is scheduled: it starts at the planned start and is
+	// managed like any other silence once it has started.
+	ChangeQuery         string
+	ChangeStartField    string
+	ChangeEndField      string
+	ChangeMatchersField string
+	// GitOpsPrune expires the silences of definitions removed from the GitOps source
+	GitOpsPrune bool
+	// DiscoveryQuery, when set, finds the tickets referencing silences (e.g. a JQL query),
+	// so that open tickets whose silence is no longer active are reconciled as well
+	DiscoveryQuery string
+	// ReopenStrategy decides how alerts refiring on closed tickets are handled
+	ReopenStrategy ReopenStrategy
+	// RefireLinkType is the issue link type from a closed ticket to its follow-up, e.g. "Relates"
+	RefireLinkType string
+	// RefireCopyLabels lists the labels of a closed ticket copied to its follow-up. An
+	// entry ending in "*" copies all labels with that prefix.
+	RefireCopyLabels []string
+	// RefireSubTasks, under the reopen strategy, creates a sub-task of the reopened ticket
+	// for each instance of an alert refiring with several instances, each with its own
+	// silence, instead of reopening the ticket with the labels of a single instance
+	RefireSubTasks bool
+	// Watchers lists the users, as account IDs or email addresses, added as watchers to
+	// tickets that silence-manager creates or reopens
+	Watchers []string
+	// CheckSilencedAlerts asks Alertmanager which alerts each managed silence mutes, so
+	// that silences matching no alerts are reported as idle
+	CheckSilencedAlerts bool
+	// ClockTolerance allows for clocks that are slightly off: silences ending up to this
+	// long after ExpiryThreshold are extended, and silences only count as expired once
+	// they ended more than this long ago
+	ClockTolerance time.Duration
+	// UseServerTime judges silence expiry by the clock of the Alertmanager server instead
+	// of the local clock, when the alertmanager implements alertmanager.Clock
+	UseServerTime bool
+	// MaxExtensions, when positive, stops extending a silence once it has been extended
+	// this often, counted in its extension history, so it lapses while the ticket is
+	// still open. It requires ExtensionHistory.
+	MaxExtensions int
+	// DetectDrift compares the managed silences with the fingerprints kept of them at the
+	// start of each run and records edits made outside silence-manager. It requires a
+	// persistent state store.
+	DetectDrift bool
+	// DriftComments comments on the ticket of each silence edited outside silence-manager
+	DriftComments bool
+	// ExpireIdleAfter, when positive, stops extending silences that have matched no
+	// alerts for this long, commenting on the ticket once and letting the silence lapse.
+	// It requires CheckSilencedAlerts and a persistent state store.
+	ExpireIdleAfter time.Duration
+	// JournalRetention, when positive, journals the silences each run deletes or changes,
+	// with their prior state, and keeps the journals this long so that a run can be
+	// rolled back. It requires a persistent state store.
+	JournalRetention time.Duration
+	// TicketProjects, when set, lists the Jira projects whose tickets silence-manager
+	// manages. Silences and alerts referencing tickets of other projects are reported
+	// and skipped instead of being looked up.
+	TicketProjects []string
+	// ManagedCreators, when set, restricts the managed silences to those whose creator
+	// matches one of the entries, and silences whose creator matches IgnoredCreators are
+	// never managed. An entry ending in "*" matches a prefix. Silences of other creators
+	// are never extended or deleted, e.g. those of another team on a shared Alertmanager.
+	ManagedCreators []string
+	IgnoredCreators []string
+	// ProtectToken and ProtectLabel mark silences that opt out of management: a silence
+	// whose comment contains ProtectToken, or with a matcher on the label ProtectLabel, is
+	// never extended, deleted or commented on, even if it carries a ticket reference.
+	// Empty values disable the respective marker.
+	ProtectToken string
+	ProtectLabel string
+	// RecreateOnReopenFor, when positive, remembers the silences deleted because their
+	// ticket was resolved for this long, and recreates them if the ticket is reopened. It
+	// requires a persistent state store.
+	RecreateOnReopenFor time.Duration
+}
+
+// ReopenStrategy is the handling of an alert that refires on a closed ticket
+type ReopenStrategy string
+
+const (
+	// ReopenStrategyReopen transitions the closed ticket back to open and silences the alert
+	ReopenStrategyReopen ReopenStrategy = "reopen"
+	// ReopenStrategyNewTicket creates a follow-up ticket linked to the closed one and
+	// silences the alert, for workflows that keep done tickets closed
+	ReopenStrategyNewTicket ReopenStrategy = "new-ticket"
+	// ReopenStrategyComment only comments on the closed ticket; the alert is not silenced
+	ReopenStrategyComment ReopenStrategy = "comment"
+)
+
+// ParseReopenStrategy parses a reopen strategy name
+func ParseReopenStrategy(value string) (ReopenStrategy, error) {
+	switch strategy := ReopenStrategy(value); strategy {
+	case ReopenStrategyReopen, ReopenStrategyNewTicket, ReopenStrategyComment:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown reopen strategy %q (must be reopen, new-ticket or comment)", value)
 }
 
 // Synchronizer handles synchronization between alertmanager and ticket system
@@ -28,16 +202,51 @@ type Synchronizer struct {
 	ticketSystem     ticket.TicketSystem
 	config           SyncConfig
 	metricsPublisher metrics.Publisher
+	stateStore       state.Store
+	maintenance      calendar.Source
+	gitOps           gitops.Source
+	onCall           oncall.Resolver
+	rules            rules.Source
+	notifier         Notifier
+	escalator        oncall.Escalator
+	beforeHooks      []BeforeActionFunc
+	afterHooks       []AfterActionFunc
+
+	// prefetched holds the tickets looked up in bulk for the silences being processed
+	prefetched map[string]*ticket.Ticket
+	// run is the result of the run in progress, recording its actions
+	run *SyncResult
+	// idle tracks the idle silences during a run when idle silences are left to lapse
+	idle map[string]state.IdleSilence
+	// clockOffset is how far the Alertmanager server's clock is ahead of the local clock
+	clockOffset time.Duration
+	// groups holds the silences of each ticket during a run, which are extended together
+	groups map[string]*ticketGroup
+	// comments collects the comments of each ticket while silences are processed, so
+	// that a ticket linked to several silences gets a single comment
+	comments     map[string][]string
+	commentOrder []string
+	// journal records the prior state of the silences deleted or changed during a run
+	journal *runJournal
+	// fingerprints holds the fingerprints refreshed by silence-manager's own changes,
+	// nil for deleted silences, until they are saved
+	fingerprints map[string]*state.SilenceFingerprint
+	// alertNames holds the names of the existing alerting rules during a run, or nil
+	// when they are unknown
+	alertNames []string
+	// runSteps are run at the end of every run, see AddRunStep
+	runSteps []runStep
+}
+
+// runStep is a step added to the end of every run, with the category of its errors
+type runStep struct {
+	category string
+	run      func(result *SyncResult) error
 }
 
-// NewSynchronizer creates a new synchronizer
+// NewSynchronizer creates a new synchronizer. It is equivalent to New with WithConfig.
 func NewSynchronizer(am alertmanager.AlertManager, ts ticket.TicketSystem, config SyncConfig) *Synchronizer {
-	return &Synchronizer{
-		alertManager:     am,
-		ticketSystem:     ts,
-		config:           config,
-		metricsPublisher: metrics.NewNoopPublisher(), // Default to no-op
-	}
+	return New(am, ts, WithConfig(config))
 }
 
 // SetMetricsPublisher sets the metrics publisher for the synchronizer
@@ -45,36 +254,284 @@ func (s *Synchronizer) SetMetricsPublisher(publisher metrics.Publisher) {
 	s.metricsPublisher = publisher
 }
 
+// SetStateStore sets the store used to persist state between runs
+func (s *Synchronizer) SetStateStore(store state.Store) {
+	s.stateStore = store
+}
+
+// AddRunStep adds a step to the end of every run, such as publishing the inventory. Steps
+// run after all silences are processed and before the metrics are published, so that
+// their errors are counted under category like those of the run itself.
+func (s *Synchronizer) AddRunStep(category string, run func(result *SyncResult) error) {
+	s.runSteps = append(s.runSteps, runStep{category: category, run: run})
+}
+
 // SyncResult contains the results of a synchronization run
 type SyncResult struct {
+	// RunID identifies the run in the undo journal; empty unless journaling is enabled
+	RunID            string
 	SilencesExtended int
 	SilencesDeleted  int
 	SilencesCreated  int
 	TicketsReopened  int
-	Errors           []error
+	// TicketsClosed counts tickets closed because their alerts stayed quiet
+	TicketsClosed     int
+	DirectivesApplied int
+	// ExtensionsWithheld counts silences not extended because their ticket has no assignee
+	ExtensionsWithheld int
+	LabelsUpdated      int
+	// MatchersUpdated counts silences whose matchers were edited from their ticket
+	MatchersUpdated int
+	// MaintenanceCreated and MaintenanceRetired count maintenance window silences
+	MaintenanceCreated int
+	MaintenanceRetired int
+	// GitOpsApplied counts silences created or adopted for declared definitions, and
+	// GitOpsPruned those expired because their definition was removed
+	GitOpsApplied int
+	GitOpsPruned  int
+	// ChangeSilencesCreated counts silences created for change windows, and
+	// ChangeSilencesRemoved those deleted because their change closed early
+	ChangeSilencesCreated int
+	ChangeSilencesRemoved int
+	// TicketsDiscovered counts open tickets found by the discovery query whose expired
+	// silence was recreated
+	TicketsDiscovered int
+	// FollowUpTickets counts tickets created for alerts refiring on closed tickets
+	FollowUpTickets int
+	// SubTasksCreated counts sub-tasks created for the instances of refired alerts
+	SubTasksCreated int
+	// RefireComments counts closed tickets commented on for refired alerts instead of
+	// being reopened
+	RefireComments int
+	// SilencesRecreated counts silences recreated because their resolved ticket was reopened
+	SilencesRecreated int
+	// IdleSilencesLapsed counts silences no longer extended because they matched no
+	// alerts for ExpireIdleAfter
+	IdleSilencesLapsed int
+	// RemovedRuleSilences counts silences not extended because their alertname matches
+	// no existing alerting rule
+	RemovedRuleSilences int
+	// ExtensionCapsReached counts silences of open tickets left to lapse because they
+	// reached MaxExtensions
+	ExtensionCapsReached int
+	// SilencesDrifted counts managed silences found edited outside silence-manager, whose
+	// edits are listed in Drifts
+	SilencesDrifted int
+	Drifts          []state.DriftEvent
+	// SLAEscalations counts tickets escalated because their alerts were silenced longer
+	// than the SLA of their severity
+	SLAEscalations int
+	// AcknowledgementsLinked counts karma acknowledgements linked to a ticket
+	AcknowledgementsLinked int
+	// ForeignTicketRefs counts silences and alerts skipped because they reference a
+	// ticket outside TicketProjects
+	ForeignTicketRefs int
+	Hygiene           state.HygieneSample
+	Managed           []ManagedSilence
+	Errors            []error
+	// ErrorCategories holds the category of each error, such as "silence" or "gitops"
+	ErrorCategories []string
+	// Actions lists the actions the run took, in order
+	Actions []ActionOutcome
+
+	lifecycle map[string]*lifecycleState
+	// resolved holds the silences deleted in this run because their ticket was resolved
+	resolved map[string]state.ResolvedSilence
+}
+
+// Health values reported for managed silences
+const (
+	HealthHealthy           = "healthy"
+	HealthTicketNotOpen     = "ticket-not-open"
+	HealthTicketUnavailable = "ticket-unavailable"
+	HealthTicketUnassigned  = "ticket-unassigned"
+	// HealthSilenceMissing is reported for discovered tickets whose silence no longer exists
+	HealthSilenceMissing = "silence-missing"
+)
+
+// ManagedSilence describes a silence/ticket pair that remains in place after a synchronization run
+type ManagedSilence struct {
+	SilenceID    string    `json:"silenceID"`
+	TicketRef    string    `json:"ticketRef"`
+	TicketStatus string    `json:"ticketStatus,omitempty"`
+	EndsAt       time.Time `json:"endsAt"`
+	Health       string    `json:"health"`
+	// Idle is set when the silence was checked and matches no alerts
+	Idle bool `json:"idle,omitempty"`
 }
 
 // Sync performs a full synchronization between alertmanager and ticket system
 func (s *Synchronizer) Sync() (*SyncResult, error) {
+	return s.SyncContext(context.Background())
+}
+
+// SyncContext performs a full synchronization, sending the Alertmanager requests of the
+// run with ctx so that its deadline is enforced. Silences not processed by the time ctx
+// is done are left for the next run.
+func (s *Synchronizer) SyncContext(ctx context.Context) (*SyncResult, error) {
+	ctx, span := tracer.Start(ctx, "sync")
+	defer span.End()
+
+	started := time.Now()
+	runID := s.startJournal(started)
+	defer func() {
+		// Journal the actions of runs that stopped early, too
+		if err := s.saveJournal(); err != nil {
+			log.Printf("Warning: failed to save journal of run %s: %v", runID, err)
+		}
+	}()
+
+	upstream := s.alertManager
+	am := untracked(upstream)
+	bind := func(ctx context.Context) {
+		s.alertManager = s.tracked(s.journaled(alertmanager.WithContext(ctx, am)))
+		if rc, ok := s.ticketSystem.(requestContextSetter); ok {
+			rc.SetRequestContext(ctx)
+		}
+	}
+	bind(ctx)
+	s.syncClock(am)
+	defer func() {
+		s.alertManager = upstream
+		if rc, ok := s.ticketSystem.(requestContextSetter); ok {
+			rc.SetRequestContext(nil)
+		}
+	}()
+
 	result := &SyncResult{
-		Errors: make([]error, 0),
+		RunID:   runID,
+		Managed: make([]ManagedSilence, 0),
+		Errors:  make([]error, 0),
 	}
+	s.run = result
+	s.fingerprints = nil
+	defer func() {
+		if err := s.saveFingerprints(); err != nil {
+			log.Printf("Warning: failed to save silence fingerprints: %v", err)
+		}
+		s.fingerprints = nil
+		s.run = nil
+	}()
 
 	log.Println("Starting synchronization...")
 
+	// Errors appended to result since mark have not been counted in the metrics yet
+	mark := 0
+
+	// Compare the silences with their fingerprints before this run changes any
+	if s.config.DetectDrift {
+		if err := s.detectDrift(result); err != nil {
+			log.Printf("Error detecting edited silences: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("drift: %w", err))
+		}
+		s.recordErrors(result, &mark, "drift")
+	}
+
+	// Reconcile maintenance windows before the regular lifecycle
+	maintenanceSilences := make(map[string]bool)
+	if s.maintenance != nil {
+		var err error
+		maintenanceSilences, err = s.syncMaintenance(result)
+		if err != nil {
+			log.Printf("Error synchronizing maintenance calendar: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("maintenance calendar: %w", err))
+		}
+		s.recordErrors(result, &mark, "maintenance")
+	}
+	if s.config.ChangeQuery != "" {
+		changeSilences, err := s.syncChanges(result)
+		if err != nil {
+			log.Printf("Error synchronizing change tickets: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("change tickets: %w", err))
+		}
+		for id := range changeSilences {
+			maintenanceSilences[id] = true
+		}
+		s.recordErrors(result, &mark, "changes")
+	}
+
+	// Converge declared silences before the regular lifecycle, which then manages them
+	if s.gitOps != nil {
+		if err := s.syncGitOps(result); err != nil {
+			log.Printf("Error synchronizing declared silences: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("gitops: %w", err))
+		}
+		s.recordErrors(result, &mark, "gitops")
+	}
+
 	// Get all active silences
 	silences, err := s.alertManager.ListSilences()
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		s.metricsPublisher.RecordError("alertmanager")
+		s.metricsPublisher.RecordSyncDuration(time.Since(started))
+		if err := s.metricsPublisher.Push(); err != nil {
+			log.Printf("Warning: failed to push metrics: %v", err)
+		}
 		return result, fmt.Errorf("failed to list silences: %w", err)
 	}
+	silences = s.managedSilences(silences)
 
 	log.Printf("Found %d active silences", len(silences))
 
 	// Process each silence
-	now := time.Now()
-	for _, silence := range silences {
+	now := s.now()
+	ages := make([]time.Duration, 0, len(silences))
+	result.Hygiene.Timestamp = now
+	result.Hygiene.TotalSilences = len(silences)
+	s.prefetchTickets(silences)
+	s.groupByTicket(silences, maintenanceSilences)
+	s.collectComments()
+	if s.config.ExpireIdleAfter > 0 && s.config.CheckSilencedAlerts {
+		if err := s.loadIdle(); err != nil {
+			log.Printf("Error loading idle silences: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("idle silences: %w", err))
+		}
+		s.recordErrors(result, &mark, "idle")
+	}
+	if s.rules != nil {
+		if err := s.loadAlertNames(); err != nil {
+			log.Printf("Error fetching alerting rules, not checking for removed rules: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("alerting rules: %w", err))
+		}
+		s.recordErrors(result, &mark, "rules")
+	}
+	var acks map[string]string
+	if s.config.AckPrefix != "" {
+		acks = ackTickets(silences)
+	}
+	for i, silence := range silences {
+		if err := ctx.Err(); err != nil {
+			log.Printf("Stopping synchronization with %d silence(s) left: %v", len(silences)-i, err)
+			result.Errors = append(result.Errors, fmt.Errorf("process silences: %w", err))
+			break
+		}
+		ages = append(ages, now.Sub(silence.StartsAt))
+
+		// Acknowledgements in karma are linked to a ticket and managed from then on
+		if silence.TicketRef == "" && acks != nil && IsAcknowledgement(silence, s.config.AckPrefix) {
+			if err := s.linkAcknowledgement(silence, acks, result); err != nil {
+				log.Printf("Error linking acknowledgement %s: %v", silence.ID, err)
+				result.Errors = append(result.Errors, fmt.Errorf("acknowledgement %s: %w", silence.ID, err))
+			}
+		}
+
 		if silence.TicketRef == "" {
 			log.Printf("Silence %s has no ticket reference, skipping", silence.ID)
+			result.Hygiene.OrphanSilences++
+			continue
+		}
+
+		// References to the tickets of other teams' projects are reported, not looked up
+		if !s.allowsTicketRef(silence.TicketRef) {
+			s.reportForeignTicketRef("Silence "+silence.ID, silence.TicketRef, result)
+			continue
+		}
+
+		// Maintenance and change window silences end with their window and are never extended
+		if maintenanceSilences[silence.ID] {
+			log.Printf("Silence %s covers a maintenance or change window, skipping", silence.ID)
+			result.Hygiene.OpenTicketSilences++
 			continue
 		}
 
@@ -82,10 +539,63 @@ func (s *Synchronizer) Sync() (*SyncResult, error) {
 		s.metricsPublisher.RecordSilenceCheck(silence.ID, silence.TicketRef, now)
 		s.metricsPublisher.RecordSilenceExpiry(silence.ID, silence.TicketRef, silence.EndsAt)
 
+		silenceCtx, silenceSpan := tracer.Start(ctx, "silence", trace.WithAttributes(
+			attribute.String("silence.id", silence.ID),
+			attribute.String("ticket.key", silence.TicketRef),
+		))
+		bind(silenceCtx)
 		if err := s.processSilence(silence, result); err != nil {
 			log.Printf("Error processing silence %s: %v", silence.ID, err)
 			result.Errors = append(result.Errors, fmt.Errorf("silence %s: %w", silence.ID, err))
+			silenceSpan.SetStatus(codes.Error, err.Error())
+		}
+		silenceSpan.End()
+		bind(ctx)
+	}
+	s.prefetched = nil
+	s.groups = nil
+	s.alertNames = nil
+	s.flushComments()
+	s.recordErrors(result, &mark, "silence")
+
+	// Remember how long the remaining silences have been idle
+	if s.idle != nil {
+		if err := s.saveIdle(result); err != nil {
+			log.Printf("Error saving idle silences: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("idle silences: %w", err))
+		}
+		s.recordErrors(result, &mark, "idle")
+	}
+
+	// Escalate tickets whose alerts have been silenced for longer than their SLA
+	if len(s.config.SeveritySLAs) > 0 {
+		if err := s.checkSLAs(silences, result); err != nil {
+			log.Printf("Error checking severity SLAs: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("severity SLAs: %w", err))
 		}
+		s.recordErrors(result, &mark, "sla")
+	}
+
+	// Recreate the silences of resolved tickets that were reopened
+	if s.config.RecreateOnReopenFor > 0 {
+		recreated, err := s.recreateReopened(silences, result)
+		if err != nil {
+			log.Printf("Error recreating silences of reopened tickets: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("reopened tickets: %w", err))
+		}
+		silences = append(silences, recreated...)
+		s.recordErrors(result, &mark, "reopened")
+	}
+
+	// Reconcile open tickets whose silence is no longer active
+	if s.config.DiscoveryQuery != "" {
+		recreated, err := s.discoverTickets(silences, result)
+		if err != nil {
+			log.Printf("Error discovering tickets: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("discover tickets: %w", err))
+		}
+		silences = append(silences, recreated...)
+		s.recordErrors(result, &mark, "discovery")
 	}
 
 	// Check for refired alerts if enabled
@@ -94,79 +604,348 @@ func (s *Synchronizer) Sync() (*SyncResult, error) {
 			log.Printf("Error checking refired alerts: %v", err)
 			result.Errors = append(result.Errors, fmt.Errorf("check refired alerts: %w", err))
 		}
+		s.recordErrors(result, &mark, "refire")
+	}
+
+	// Close tickets whose alerts stayed quiet after their silence expired
+	if s.config.AutoCloseAfter > 0 {
+		if err := s.closeQuietTickets(silences, result); err != nil {
+			log.Printf("Error closing quiet tickets: %v", err)
+			result.Errors = append(result.Errors, fmt.Errorf("close quiet tickets: %w", err))
+		}
+		s.recordErrors(result, &mark, "autoclose")
 	}
 
-	log.Printf("Synchronization complete: extended=%d, deleted=%d, created=%d, reopened=%d, errors=%d",
-		result.SilencesExtended, result.SilencesDeleted, result.SilencesCreated, result.TicketsReopened, len(result.Errors))
+	// Update ticket lifecycle labels if enabled
+	if s.config.LifecycleLabels {
+		s.applyLifecycleLabels(result)
+		s.recordErrors(result, &mark, "lifecycle")
+	}
+
+	result.Hygiene.MedianAge = slo.MedianDuration(ages)
+	result.Hygiene.SilencesExtended = result.SilencesExtended
+	result.Hygiene.SilencesDeleted = result.SilencesDeleted
+	// A follow-up ticket is a recurrence just like a reopened ticket
+	result.Hygiene.TicketsReopened = result.TicketsReopened + result.FollowUpTickets
+	s.metricsPublisher.RecordHygiene(result.Hygiene.OpenTicketRatio(), result.Hygiene.OrphanRatio(), result.Hygiene.MedianAge)
+	if err := s.recordHygiene(result.Hygiene); err != nil {
+		log.Printf("Warning: failed to record hygiene sample: %v", err)
+		result.Errors = append(result.Errors, fmt.Errorf("record hygiene: %w", err))
+	}
+	if err := s.saveJournal(); err != nil {
+		log.Printf("Warning: failed to save journal of run %s: %v", runID, err)
+		result.Errors = append(result.Errors, fmt.Errorf("save journal: %w", err))
+	}
+	s.recordErrors(result, &mark, "state")
+
+	for _, step := range s.runSteps {
+		if err := step.run(result); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		s.recordErrors(result, &mark, step.category)
+	}
+
+	log.Printf("Synchronization complete: extended=%d, deleted=%d, created=%d, reopened=%d, closed=%d, directives=%d, withheld=%d, labels=%d, matchers=%d, maintenance-created=%d, maintenance-retired=%d, gitops-applied=%d, gitops-pruned=%d, change-created=%d, change-removed=%d, discovered=%d, follow-ups=%d, sub-tasks=%d, refire-comments=%d, recreated=%d, idle-lapsed=%d, removed-rules=%d, capped=%d, drifted=%d, sla-escalations=%d, acks-linked=%d, foreign-refs=%d, errors=%d",
+		result.SilencesExtended, result.SilencesDeleted, result.SilencesCreated, result.TicketsReopened, result.TicketsClosed, result.DirectivesApplied, result.ExtensionsWithheld, result.LabelsUpdated, result.MatchersUpdated, result.MaintenanceCreated, result.MaintenanceRetired, result.GitOpsApplied, result.GitOpsPruned, result.ChangeSilencesCreated, result.ChangeSilencesRemoved, result.TicketsDiscovered, result.FollowUpTickets, result.SubTasksCreated, result.RefireComments, result.SilencesRecreated, result.IdleSilencesLapsed, result.RemovedRuleSilences, result.ExtensionCapsReached, result.SilencesDrifted, result.SLAEscalations, result.AcknowledgementsLinked, result.ForeignTicketRefs, len(result.Errors))
+
+	span.SetAttributes(
+		attribute.Int("silences.total", result.Hygiene.TotalSilences),
+		attribute.Int("silences.extended", result.SilencesExtended),
+		attribute.Int("silences.deleted", result.SilencesDeleted),
+		attribute.Int("silences.created", result.SilencesCreated),
+		attribute.Int("tickets.reopened", result.TicketsReopened),
+		attribute.Int("errors", len(result.Errors)),
+	)
+	if len(result.Errors) > 0 {
+		span.SetStatus(codes.Error, fmt.Sprintf("%d error(s)", len(result.Errors)))
+	}
+
+	s.publishMetrics(result, started)
+
+	return result, nil
+}
+
+// recordErrors counts the errors appended to result since mark under category and
+// advances mark past them
+func (s *Synchronizer) recordErrors(result *SyncResult, mark *int, category string) {
+	for ; *mark < len(result.Errors); *mark++ {
+		s.metricsPublisher.RecordError(category)
+		result.ErrorCategories = append(result.ErrorCategories, category)
+	}
+}
+
+// publishMetrics records the outcome and duration of the run started at started and
+// pushes its metrics to the backend. A run counts as successful when it completed
+// without errors.
+func (s *Synchronizer) publishMetrics(result *SyncResult, started time.Time) {
+	s.metricsPublisher.RecordSyncDuration(time.Since(started))
+	s.metricsPublisher.RecordAction(metrics.ActionSilencesExtended, result.SilencesExtended)
+	s.metricsPublisher.RecordAction(metrics.ActionSilencesDeleted, result.SilencesDeleted)
+	s.metricsPublisher.RecordAction(metrics.ActionSilencesCreated, result.SilencesCreated)
+	s.metricsPublisher.RecordAction(metrics.ActionTicketsReopened, result.TicketsReopened)
+	if len(result.Errors) == 0 {
+		s.metricsPublisher.RecordSyncSuccess(time.Now())
+	}
 
-	// Push metrics to backend
 	if err := s.metricsPublisher.Push(); err != nil {
 		log.Printf("Warning: failed to push metrics: %v", err)
 		result.Errors = append(result.Errors, fmt.Errorf("push metrics: %w", err))
+		result.ErrorCategories = append(result.ErrorCategories, "metrics")
 	}
-
-	return result, nil
 }
 
 // processSilence handles the synchronization logic for a single silence
 func (s *Synchronizer) processSilence(silence *alertmanager.Silence, result *SyncResult) error {
 	// Get the associated ticket
-	tkt, err := s.ticketSystem.GetTicket(silence.TicketRef)
+	tkt, err := s.getTicket(silence.TicketRef)
 	if err != nil {
+		result.Managed = append(result.Managed, ManagedSilence{
+			SilenceID: silence.ID,
+			TicketRef: silence.TicketRef,
+			EndsAt:    silence.EndsAt,
+			Health:    HealthTicketUnavailable,
+		})
 		return fmt.Errorf("failed to get ticket %s: %w", silence.TicketRef, err)
 	}
 
+	// Record the pair in the inventory unless the silence is removed below
+	deleted := false
+	withheld := false
+	idleChecked, idle := false, false
+	isIdle := func() bool {
+		if !idleChecked {
+			idleChecked = true
+			idle = s.config.CheckSilencedAlerts && s.checkIdle(silence, result)
+		}
+		return idle
+	}
+	defer func() {
+		s.trackLifecycle(result, tkt, s.lifecycleLabelFor(silence.EndsAt, deleted))
+		if deleted {
+			return
+		}
+		health := HealthHealthy
+		if !s.ticketSystem.IsOpen(tkt) {
+			health = HealthTicketNotOpen
+		} else if withheld {
+			health = HealthTicketUnassigned
+		}
+		s.trackIdle(silence, tkt, isIdle())
+		result.Managed = append(result.Managed, ManagedSilence{
+			SilenceID:    silence.ID,
+			TicketRef:    tkt.Key,
+			TicketStatus: string(tkt.Status),
+			EndsAt:       silence.EndsAt,
+			Health:       health,
+			Idle:         isIdle(),
+		})
+	}()
+
 	log.Printf("Processing silence %s with ticket %s (status: %s)", silence.ID, tkt.Key, tkt.Status)
 
+	if s.ticketSystem.IsOpen(tkt) {
+		result.Hygiene.OpenTicketSilences++
+	}
+
+	// Apply any chat command directives from ticket comments before the automatic rules
+	if s.config.ProcessDirectives && !s.ticketSystem.IsResolved(tkt) {
+		gone, err := s.applyDirectives(silence, tkt, result)
+		if err != nil {
+			return err
+		}
+		if gone {
+			deleted = true
+			return nil
+		}
+	}
+
+	// Apply matchers edited on the ticket
+	if s.config.TicketMatchers && !s.ticketSystem.IsResolved(tkt) {
+		if err := s.applyTicketMatchers(silence, tkt, result); err != nil {
+			return err
+		}
+	}
+
+	action, newEndTime := s.nextGroupAction(silence, tkt)
+	switch action {
 	// Case 1: Ticket is resolved -> delete silence
-	if s.ticketSystem.IsResolved(tkt) {
+	case ActionDelete:
+		event := ActionEvent{Action: ActionDelete, SilenceID: silence.ID, TicketKey: tkt.Key}
+		if !s.beforeAction(event) {
+			return nil
+		}
 		log.Printf("Ticket %s is resolved, deleting silence %s", tkt.Key, silence.ID)
-		if err := s.alertManager.DeleteSilence(silence.ID); err != nil {
+		err := s.alertManager.DeleteSilence(silence.ID)
+		s.afterAction(event, err)
+		if err != nil {
 			return fmt.Errorf("failed to delete silence: %w", err)
 		}
-		if err := s.ticketSystem.AddComment(tkt.Key, fmt.Sprintf("Silence %s has been automatically deleted because the ticket is resolved.", silence.ID)); err != nil {
-			log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
-		}
+		s.addComment(tkt.Key, fmt.Sprintf("Silence %s has been automatically deleted because the ticket is resolved.", s.silenceRef(silence.ID)))
 		result.SilencesDeleted++
-		return nil
-	}
+		s.rememberResolved(result, silence, tkt)
+		deleted = true
 
-	// Case 2: Ticket is open and silence is about to expire -> extend silence
-	if s.ticketSystem.IsOpen(tkt) {
-		timeUntilExpiry := time.Until(silence.EndsAt)
-		if timeUntilExpiry < s.config.ExpiryThreshold && timeUntilExpiry > 0 {
-			newEndTime := time.Now().Add(s.config.ExtensionDuration)
+	// Unowned tickets must not keep alerts silenced indefinitely
+	case ActionWithhold:
+		log.Printf("Ticket %s has no assignee, not extending silence %s", tkt.Key, silence.ID)
+		withheld = true
+		result.ExtensionsWithheld++
+		s.requestOwnership(silence, tkt)
+
+	// Case 2: Ticket is open and silence is about to expire, or has expired -> extend silence
+	case ActionExtend:
+		if s.lapseIdle(silence, tkt, isIdle(), result) || s.withholdRemovedRule(silence, tkt, result) || s.withholdCapped(silence, tkt, result) {
+			return nil
+		}
+		event := ActionEvent{Action: ActionExtend, SilenceID: silence.ID, TicketKey: tkt.Key, NewEndsAt: newEndTime}
+		if !s.beforeAction(event) {
+			return nil
+		}
+		timeUntilExpiry := silence.EndsAt.Sub(s.now())
+		msg := "Silence %s has been automatically extended until %v."
+		if !s.hasExpired(silence.EndsAt) {
 			log.Printf("Ticket %s is open and silence %s expires in %v, extending until %v",
 				tkt.Key, silence.ID, timeUntilExpiry, newEndTime)
-			if err := s.alertManager.ExtendSilence(silence.ID, newEndTime); err != nil {
+			err := s.extendSilence(silence, newEndTime)
+			s.afterAction(event, err)
+			if err != nil {
 				return fmt.Errorf("failed to extend silence: %w", err)
 			}
-			if err := s.ticketSystem.AddComment(tkt.Key, fmt.Sprintf("Silence %s has been automatically extended until %v.", silence.ID, newEndTime.Format(time.RFC3339))); err != nil {
-				log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
-			}
-			result.SilencesExtended++
-			return nil
-		}
-
-		// If silence has already expired, extend it
-		if timeUntilExpiry <= 0 {
-			newEndTime := time.Now().Add(s.config.ExtensionDuration)
+		} else {
 			log.Printf("Ticket %s is open and silence %s has expired, extending until %v",
 				tkt.Key, silence.ID, newEndTime)
-			if err := s.alertManager.ExtendSilence(silence.ID, newEndTime); err != nil {
+			err := s.extendSilence(silence, newEndTime)
+			s.afterAction(event, err)
+			if err != nil {
 				return fmt.Errorf("failed to extend expired silence: %w", err)
 			}
-			if err := s.ticketSystem.AddComment(tkt.Key, fmt.Sprintf("Silence %s was expired and has been automatically extended until %v.", silence.ID, newEndTime.Format(time.RFC3339))); err != nil {
-				log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+			msg = "Silence %s was expired and has been automatically extended until %v."
+		}
+		// The extension may have replaced the silence under a new ID
+		s.updateDueDate(tkt.Key, newEndTime)
+		s.linkSilence(tkt.Key, silence.ID)
+		s.addComment(tkt.Key, fmt.Sprintf(msg, s.silenceRef(silence.ID), newEndTime.Format(time.RFC3339)))
+		result.SilencesExtended++
+	}
+
+	return nil
+}
+
+// ownershipRequestMarker identifies comments asking for a ticket to be assigned
+const ownershipRequestMarker = "has not been extended because the ticket has no assignee"
+
+// requestOwnership asks for the ticket to be assigned, unless it has already been asked
+func (s *Synchronizer) requestOwnership(silence *alertmanager.Silence, tkt *ticket.Ticket) {
+	comments, err := s.ticketSystem.GetComments(tkt.Key)
+	if err != nil {
+		log.Printf("Warning: failed to get comments for ticket %s: %v", tkt.Key, err)
+		return
+	}
+	for _, c := range comments {
+		if strings.Contains(c.Body, silence.ID) && strings.Contains(c.Body, ownershipRequestMarker) {
+			return
+		}
+	}
+
+	msg := fmt.Sprintf("Silence %s %s. Please assign an owner to this ticket so the silence can be extended before it expires at %v.",
+		silence.ID, ownershipRequestMarker, silence.EndsAt.Format(time.RFC3339))
+	s.addComment(tkt.Key, msg)
+}
+
+// endTime returns the end time for a silence lasting d from now, aligned to business
+// hours when configured
+func (s *Synchronizer) endTime(d time.Duration) time.Time {
+	end := s.now().Add(d)
+	if s.config.BusinessHours != nil {
+		end = s.config.BusinessHours.Next(end)
+	}
+	return end
+}
+
+// updateDueDate sets the ticket's due date to the silence end time when enabled, so
+// that Jira dashboards and queries on the due date surface silences about to expire
+func (s *Synchronizer) updateDueDate(key string, endsAt time.Time) {
+	if !s.config.UpdateDueDate {
+		return
+	}
+	if s.config.BusinessHours != nil {
+		endsAt = endsAt.In(s.config.BusinessHours.Location)
+	}
+	if err := s.ticketSystem.SetDueDate(key, endsAt); err != nil {
+		log.Printf("Warning: failed to set due date of ticket %s: %v", key, err)
+	}
+}
+
+// extensionDurationFor returns how long to extend a silence linked to the given ticket.
+// A duration set in the configured custom field takes precedence over a duration label,
+// then over a duration configured for the ticket's priority, and any of them overrides
+// the global ExtensionDuration. Invalid values are ignored.
+func (s *Synchronizer) extensionDurationFor(tkt *ticket.Ticket) time.Duration {
+	if s.config.DurationField != "" {
+		if value := tkt.CustomFields[s.config.DurationField]; value != "" {
+			d, err := s.parseFieldDuration(strings.TrimSpace(value))
+			if err == nil {
+				log.Printf("Using extension duration %v from field %s on ticket %s", d, s.config.DurationField, tkt.Key)
+				return d
 			}
-			result.SilencesExtended++
-			return nil
+			log.Printf("Warning: ignoring invalid duration %q in field %s on ticket %s: %v", value, s.config.DurationField, tkt.Key, err)
+		}
+	}
+
+	if s.config.DurationLabelPrefix != "" {
+		prefix := s.config.DurationLabelPrefix + "="
+		for _, label := range tkt.Labels {
+			if !strings.HasPrefix(label, prefix) {
+				continue
+			}
+			value := strings.TrimPrefix(label, prefix)
+			d, err := parseDirectiveDuration(value)
+			if err == nil {
+				log.Printf("Using extension duration %v from label %s on ticket %s", d, label, tkt.Key)
+				return d
+			}
+			log.Printf("Warning: ignoring invalid duration label %q on ticket %s: %v", label, tkt.Key, err)
 		}
 	}
 
+	if d, ok := s.priorityExtension(tkt); ok {
+		log.Printf("Using extension duration %v for priority %s on ticket %s", d, tkt.Priority, tkt.Key)
+		return d
+	}
+
+	return s.config.ExtensionDuration
+}
+
+// parseFieldDuration parses the value of the duration field. Jira number fields hold a
+// plain number, which is read in DurationFieldUnit; other values are parsed like the
+// extend directive, e.g. "30d" or "72h".
+func (s *Synchronizer) parseFieldDuration(value string) (time.Duration, error) {
+	if n, err := strconv.ParseFloat(value, 64); err == nil && s.config.DurationFieldUnit > 0 {
+		if n <= 0 {
+			return 0, fmt.Errorf("duration must be positive")
+		}
+		return time.Duration(n * float64(s.config.DurationFieldUnit)), nil
+	}
+	return parseDirectiveDuration(value)
+}
+
+// recordHygiene appends the run's hygiene sample to the persisted state
+func (s *Synchronizer) recordHygiene(sample state.HygieneSample) error {
+	st, err := s.stateStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	st.AddHygieneSample(sample, state.DefaultHygieneRetention)
+	if err := s.stateStore.Save(st); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
 	return nil
 }
 
+// ticketLabelMatcher selects the alerts with a ticket label, leaving Alertmanager to filter
+// out the others
+var ticketLabelMatcher = alertmanager.Matcher{Name: "ticket", Value: ".+", IsRegex: true, IsEqual: true}
+
 // checkRefiredAlerts checks if any alerts have refired for closed tickets and reopens them
 func (s *Synchronizer) checkRefiredAlerts(result *SyncResult) error {
 	// This is a more complex operation that requires tracking
@@ -178,14 +957,34 @@ func (s *Synchronizer) checkRefiredAlerts(result *SyncResult) error {
 	// For this implementation, we'll need to maintain some state or query both systems
 	// Since we're running as a cron job, we'll check recent alerts
 
-	// Get all alerts
-	allAlerts, err := s.alertManager.GetAlerts(nil)
+	// Get the alerts carrying a ticket reference
+	allAlerts, err := s.alertManager.GetAlerts([]alertmanager.Matcher{ticketLabelMatcher})
 	if err != nil {
 		return fmt.Errorf("failed to get alerts: %w", err)
 	}
 
 	log.Printf("Checking %d active alerts for closed tickets", len(allAlerts))
 
+	// Under the new-ticket strategy the closed ticket stays closed, so alerts already
+	// covered by a follow-up's silence are recognised by their matchers
+	var silenced map[string]bool
+	if s.config.ReopenStrategy == ReopenStrategyNewTicket {
+		silences, err := s.alertManager.ListSilences()
+		if err != nil {
+			return fmt.Errorf("failed to list silences: %w", err)
+		}
+		silenced = make(map[string]bool, len(silences))
+		for _, silence := range silences {
+			silenced[matcherKey(silence.Matchers)] = true
+		}
+	}
+
+	// With sub-tasks, the alerts refiring on each closed ticket are collected first, so
+	// that their instances are handled together
+	refired := make(map[string][]*alertmanager.Alert)
+	var reopening []*ticket.Ticket
+	instances := make(map[string]bool)
+
 	// For each alert, check if there's a ticket reference in the labels
 	for _, alert := range allAlerts {
 		ticketRef, hasTicket := alert.Labels["ticket"]
@@ -194,6 +993,10 @@ func (s *Synchronizer) checkRefiredAlerts(result *SyncResult) error {
 		if !hasTicket {
 			continue
 		}
+		if !s.allowsTicketRef(ticketRef) {
+			s.reportForeignTicketRef("Alert "+alert.Labels["alertname"], ticketRef, result)
+			continue
+		}
 
 		// Get the ticket
 		tkt, err := s.ticketSystem.GetTicket(ticketRef)
@@ -208,54 +1011,115 @@ func (s *Synchronizer) checkRefiredAlerts(result *SyncResult) error {
 			hasActiveSilence := false
 			if hasSilence {
 				silence, err := s.alertManager.GetSilence(silenceID)
-				if err == nil && time.Now().Before(silence.EndsAt) {
+				if err == nil && !s.hasExpired(silence.EndsAt) {
 					hasActiveSilence = true
 				}
 			}
 
 			if !hasActiveSilence {
-				log.Printf("Alert refired for closed ticket %s, reopening and creating silence", tkt.Key)
-
-				// Reopen the ticket
-				reopenMsg := fmt.Sprintf("Alert has refired. Automatically reopening ticket and creating new silence.\n\nAlert: %v", alert.Labels)
-				if err := s.ticketSystem.ReopenTicket(tkt.Key, reopenMsg); err != nil {
-					log.Printf("Error reopening ticket %s: %v", tkt.Key, err)
-					result.Errors = append(result.Errors, fmt.Errorf("reopen ticket %s: %w", tkt.Key, err))
+				switch s.config.ReopenStrategy {
+				case ReopenStrategyNewTicket:
+					s.followUpTicket(tkt, alert, silenced, result)
 					continue
-				}
-				result.TicketsReopened++
-
-				// Create a new silence with the same matchers as before
-				newSilence := &alertmanager.Silence{
-					CreatedBy: "silence-manager",
-					Comment:   fmt.Sprintf("Automatically recreated for refired alert"),
-					StartsAt:  time.Now(),
-					EndsAt:    time.Now().Add(s.config.DefaultSilenceDuration),
-					TicketRef: tkt.Key,
-					Matchers:  s.createMatchersFromAlert(alert),
-				}
-
-				silenceID, err := s.alertManager.CreateSilence(newSilence)
-				if err != nil {
-					log.Printf("Error creating silence for ticket %s: %v", tkt.Key, err)
-					result.Errors = append(result.Errors, fmt.Errorf("create silence for %s: %w", tkt.Key, err))
+				case ReopenStrategyComment:
+					s.commentRefire(tkt, alert, result)
 					continue
 				}
 
-				result.SilencesCreated++
-				log.Printf("Created new silence %s for reopened ticket %s", silenceID, tkt.Key)
-
-				// Add comment to ticket with new silence ID
-				if err := s.ticketSystem.AddComment(tkt.Key, fmt.Sprintf("New silence created: %s", silenceID)); err != nil {
-					log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+				if s.config.RefireSubTasks {
+					// Instances only differing in labels the silence ignores are one instance
+					instance := tkt.Key + " " + matcherKey(s.createMatchersFromAlert(alert))
+					if instances[instance] {
+						continue
+					}
+					instances[instance] = true
+					if refired[tkt.Key] == nil {
+						reopening = append(reopening, tkt)
+					}
+					refired[tkt.Key] = append(refired[tkt.Key], alert)
+					continue
 				}
+				s.reopenForAlert(tkt, alert, result)
 			}
 		}
 	}
 
+	for _, tkt := range reopening {
+		if alerts := refired[tkt.Key]; len(alerts) > 1 {
+			s.reopenWithSubTasks(tkt, alerts, result)
+		} else {
+			s.reopenForAlert(tkt, alerts[0], result)
+		}
+	}
+
 	return nil
 }
 
+// reopenForAlert reopens a closed ticket for a refired alert and silences the alert again
+func (s *Synchronizer) reopenForAlert(tkt *ticket.Ticket, alert *alertmanager.Alert, result *SyncResult) {
+	event := ActionEvent{Action: ActionReopen, SilenceID: alert.Labels["silence_id"], TicketKey: tkt.Key}
+	if !s.beforeAction(event) {
+		return
+	}
+	log.Printf("Alert refired for closed ticket %s, reopening and creating silence", tkt.Key)
+
+	// Reopen the ticket
+	reopenMsg := "Alert has refired. Automatically reopening ticket and creating new silence.\n\n### Alert labels\n" + labelTable(alert.Labels)
+	err := s.ticketSystem.ReopenTicket(tkt.Key, reopenMsg)
+	s.afterAction(event, err)
+	if err != nil {
+		log.Printf("Error reopening ticket %s: %v", tkt.Key, err)
+		result.Errors = append(result.Errors, fmt.Errorf("reopen ticket %s: %w", tkt.Key, err))
+		return
+	}
+	result.TicketsReopened++
+	s.addWatchers(tkt.Key)
+	s.applySeverityPriority(tkt, alert)
+	s.assignOnCall(tkt, alert)
+
+	// Create a new silence with the same matchers as before
+	s.createRefireSilence(tkt, alert, alert.Labels["silence_id"], result)
+}
+
+// createRefireSilence creates a silence for an alert refiring on a ticket and announces
+// it on the ticket. previousID is the silence it replaces, if any.
+func (s *Synchronizer) createRefireSilence(tkt *ticket.Ticket, alert *alertmanager.Alert, previousID string, result *SyncResult) *alertmanager.Silence {
+	newSilence := &alertmanager.Silence{
+		CreatedBy: "silence-manager",
+		Comment:   fmt.Sprintf("Automatically recreated for refired alert"),
+		StartsAt:  s.now(),
+		EndsAt:    s.endTime(s.config.DefaultSilenceDuration),
+		TicketRef: tkt.Key,
+		Matchers:  s.createMatchersFromAlert(alert),
+	}
+
+	event := ActionEvent{Action: ActionRecreate, TicketKey: tkt.Key, NewEndsAt: newSilence.EndsAt}
+	if !s.beforeAction(event) {
+		return nil
+	}
+	silenceID, err := s.alertManager.CreateSilence(newSilence)
+	event.SilenceID = silenceID
+	s.afterAction(event, err)
+	if err != nil {
+		log.Printf("Error creating silence for ticket %s: %v", tkt.Key, err)
+		result.Errors = append(result.Errors, fmt.Errorf("create silence for %s: %w", tkt.Key, err))
+		return nil
+	}
+
+	result.SilencesCreated++
+	log.Printf("Created new silence %s for ticket %s", silenceID, tkt.Key)
+	s.trackLifecycle(result, tkt, s.lifecycleLabelFor(newSilence.EndsAt, false))
+	s.updateDueDate(tkt.Key, newSilence.EndsAt)
+	s.relinkSilence(tkt.Key, previousID, silenceID)
+
+	// Add comment to ticket with new silence ID
+	newSilence.ID = silenceID
+	if err := s.ticketSystem.AddComment(tkt.Key, fmt.Sprintf("New silence created: %s", s.silenceRef(silenceID))+silenceDefinition(newSilence)); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+	return newSilence
+}
+
 // createMatchersFromAlert creates matchers from an alert's labels
 func (s *Synchronizer) createMatchersFromAlert(alert *alertmanager.Alert) []alertmanager.Matcher {
 	matchers := make([]alertmanager.Matcher, 0)
@@ -279,9 +1143,17 @@ func (s *Synchronizer) createMatchersFromAlert(alert *alertmanager.Alert) []aler
 // DefaultConfig returns a default synchronization configuration
 func DefaultConfig() SyncConfig {
 	return SyncConfig{
-		ExpiryThreshold:        24 * time.Hour, // Extend if expiring within 24 hours
+		ExpiryThreshold:        24 * time.Hour,     // Extend if expiring within 24 hours
 		ExtensionDuration:      7 * 24 * time.Hour, // Extend by 7 days
 		DefaultSilenceDuration: 7 * 24 * time.Hour, // New silences last 7 days
 		CheckAlerts:            true,
+		ReopenStrategy:         ReopenStrategyReopen,
+		DurationLabelPrefix:    "silence-duration",
+		DurationFieldUnit:      24 * time.Hour,
+		OnCallTeamLabel:        "team",
+		MaintenanceLookahead:   24 * time.Hour,
+		GitOpsPrune:            true,
+		UseServerTime:          true,
+		ProtectToken:           DefaultProtectToken,
 	}
 }
@@ -1,17 +1,25 @@
 package sync
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/apierror"
+	"github.com/conallob/silence-manager/pkg/faults"
+	"github.com/conallob/silence-manager/pkg/metrics"
+	"github.com/conallob/silence-manager/pkg/schedule"
+	"github.com/conallob/silence-manager/pkg/state"
 	"github.com/conallob/silence-manager/pkg/ticket"
 )
 
 // Mock AlertManager implementation
 type mockAlertManager struct {
 	silences      map[string]*alertmanager.Silence
+	unlisted      map[string]bool // Expired silences that ListSilences leaves out
 	alerts        []*alertmanager.Alert
 	deletedIDs    []string
 	extendedIDs   []string
@@ -22,6 +30,13 @@ type mockAlertManager struct {
 	extendErr     error
 	createErr     error
 	getAlertsErr  error
+	silenced      map[string][]*alertmanager.Alert // Alerts muted by each silence
+	// renewOnUpdate makes UpdateSilence expire the silence and recreate it under a new
+	// ID, as Alertmanager does when the matchers change; extensions keep the ID
+	renewOnUpdate bool
+	// renewExpired makes UpdateSilence recreate expired silences under a new ID, as
+	// Alertmanager does when an expired silence is extended
+	renewExpired bool
 }
 
 func newMockAlertManager() *mockAlertManager {
@@ -51,7 +66,9 @@ func (m *mockAlertManager) ListSilences() ([]*alertmanager.Silence, error) {
 	}
 	result := make([]*alertmanager.Silence, 0, len(m.silences))
 	for _, s := range m.silences {
-		result = append(result, s)
+		if !m.unlisted[s.ID] {
+			result = append(result, s)
+		}
 	}
 	return result, nil
 }
@@ -68,6 +85,21 @@ func (m *mockAlertManager) CreateSilence(silence *alertmanager.Silence) (string,
 }
 
 func (m *mockAlertManager) UpdateSilence(silence *alertmanager.Silence) error {
+	// Moving the end later is an extension
+	prior, ok := m.silences[silence.ID]
+	extended := ok && silence.EndsAt.After(prior.EndsAt) && silence.StartsAt.Equal(prior.StartsAt) &&
+		formatMatchers(silence.Matchers) == formatMatchers(prior.Matchers)
+	if extended {
+		if m.extendErr != nil {
+			return m.extendErr
+		}
+		m.extendedIDs = append(m.extendedIDs, silence.ID)
+	}
+	if (m.renewOnUpdate && !extended) || (m.renewExpired && ok && !prior.EndsAt.After(time.Now())) {
+		delete(m.silences, silence.ID)
+		silence.ID = fmt.Sprintf("silence-%d", m.createdCount)
+		m.createdCount++
+	}
 	m.silences[silence.ID] = silence
 	return nil
 }
@@ -99,10 +131,15 @@ func (m *mockAlertManager) GetAlerts(matchers []alertmanager.Matcher) ([]*alertm
 	return m.alerts, nil
 }
 
+func (m *mockAlertManager) GetSilencedAlerts(silenceID string) ([]*alertmanager.Alert, error) {
+	return m.silenced[silenceID], nil
+}
+
 // Mock TicketSystem implementation
 type mockTicketSystem struct {
 	tickets        map[string]*ticket.Ticket
 	comments       map[string][]string
+	threads        map[string][]*ticket.Comment
 	reopenedKeys   []string
 	closedKeys     []string
 	getErr         error
@@ -111,28 +148,76 @@ type mockTicketSystem struct {
 	reopenErr      error
 	closeErr       error
 	addCommentErr  error
+	getCommentsErr error
+	commentReads   int
+	labelUpdates   int
+	remoteLinks    map[string]map[string]ticket.RemoteLink
+	issueLinks     []string
+	watchers       map[string][]string
+	searchQueries  []string
+	searchErr      error
+	getCalls       int
+	bulkCalls      int
+	getTicketsErr  error
+	currentUser    string // Author of the comments added through the mock
 }
 
 func newMockTicketSystem() *mockTicketSystem {
 	return &mockTicketSystem{
 		tickets:      make(map[string]*ticket.Ticket),
 		comments:     make(map[string][]string),
+		threads:      make(map[string][]*ticket.Comment),
 		reopenedKeys: []string{},
 		closedKeys:   []string{},
+		remoteLinks:  make(map[string]map[string]ticket.RemoteLink),
+		watchers:     make(map[string][]string),
 	}
 }
 
 func (m *mockTicketSystem) GetTicket(key string) (*ticket.Ticket, error) {
+	m.getCalls++
 	if m.getErr != nil {
 		return nil, m.getErr
 	}
 	t, ok := m.tickets[key]
 	if !ok {
-		return nil, fmt.Errorf("ticket not found: %s", key)
+		return nil, apierror.Errorf(apierror.NotFound, "ticket not found: %s", key)
 	}
 	return t, nil
 }
 
+func (m *mockTicketSystem) GetTickets(keys []string) (map[string]*ticket.Ticket, error) {
+	m.bulkCalls++
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	if m.getTicketsErr != nil {
+		return nil, m.getTicketsErr
+	}
+	result := make(map[string]*ticket.Ticket)
+	for _, key := range keys {
+		if t, ok := m.tickets[key]; ok {
+			result[key] = t
+		}
+	}
+	return result, nil
+}
+
+// SearchTickets returns all tickets with a silence reference, whatever the query
+func (m *mockTicketSystem) SearchTickets(query string) ([]*ticket.Ticket, error) {
+	m.searchQueries = append(m.searchQueries, query)
+	if m.searchErr != nil {
+		return nil, m.searchErr
+	}
+	var result []*ticket.Ticket
+	for _, t := range m.tickets {
+		if t.SilenceRef != "" {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockTicketSystem) CreateTicket(t *ticket.Ticket) (string, error) {
 	if m.createErr != nil {
 		return "", m.createErr
@@ -151,6 +236,107 @@ func (m *mockTicketSystem) UpdateTicket(t *ticket.Ticket) error {
 	return nil
 }
 
+func (m *mockTicketSystem) UpdateLabels(key string, add, remove []string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	m.labelUpdates++
+	t, ok := m.tickets[key]
+	if !ok {
+		return fmt.Errorf("ticket not found: %s", key)
+	}
+	labels := []string{}
+	for _, l := range t.Labels {
+		keep := true
+		for _, r := range remove {
+			if l == r {
+				keep = false
+			}
+		}
+		if keep {
+			labels = append(labels, l)
+		}
+	}
+	t.Labels = append(labels, add...)
+	return nil
+}
+
+func (m *mockTicketSystem) SetPriority(key string, priority string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	t, ok := m.tickets[key]
+	if !ok {
+		return fmt.Errorf("ticket not found: %s", key)
+	}
+	t.Priority = priority
+	return nil
+}
+
+func (m *mockTicketSystem) SetDueDate(key string, due time.Time) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	t, ok := m.tickets[key]
+	if !ok {
+		return fmt.Errorf("ticket not found: %s", key)
+	}
+	t.DueDate = due
+	return nil
+}
+
+func (m *mockTicketSystem) AssignTicket(key string, user string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	t, ok := m.tickets[key]
+	if !ok {
+		return fmt.Errorf("ticket not found: %s", key)
+	}
+	t.Assignee = user
+	return nil
+}
+
+func (m *mockTicketSystem) AddWatcher(key string, user string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	for _, watcher := range m.watchers[key] {
+		if watcher == user {
+			return nil
+		}
+	}
+	m.watchers[key] = append(m.watchers[key], user)
+	return nil
+}
+
+func (m *mockTicketSystem) SetRemoteLink(key string, link ticket.RemoteLink) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	if m.remoteLinks[key] == nil {
+		m.remoteLinks[key] = make(map[string]ticket.RemoteLink)
+	}
+	m.remoteLinks[key][link.GlobalID] = link
+	return nil
+}
+
+func (m *mockTicketSystem) DeleteRemoteLink(key string, globalID string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	delete(m.remoteLinks[key], globalID)
+	return nil
+}
+
+func (m *mockTicketSystem) LinkTickets(key, linkedKey, linkType string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	m.issueLinks = append(m.issueLinks, fmt.Sprintf("%s %s %s", key, linkType, linkedKey))
+	return nil
+}
+
 func (m *mockTicketSystem) ReopenTicket(key string, comment string) error {
 	if m.reopenErr != nil {
 		return m.reopenErr
@@ -184,9 +370,26 @@ func (m *mockTicketSystem) AddComment(key string, comment string) error {
 		return m.addCommentErr
 	}
 	m.comments[key] = append(m.comments[key], comment)
+	m.threads[key] = append(m.threads[key], &ticket.Comment{
+		ID:     fmt.Sprintf("ack-%d", len(m.threads[key])),
+		Author: m.currentUser,
+		Body:   comment,
+	})
 	return nil
 }
 
+func (m *mockTicketSystem) CurrentUser() (string, error) {
+	return m.currentUser, nil
+}
+
+func (m *mockTicketSystem) GetComments(key string) ([]*ticket.Comment, error) {
+	m.commentReads++
+	if m.getCommentsErr != nil {
+		return nil, m.getCommentsErr
+	}
+	return m.threads[key], nil
+}
+
 func (m *mockTicketSystem) IsResolved(t *ticket.Ticket) bool {
 	return t.Status == ticket.StatusResolved
 }
@@ -200,6 +403,44 @@ func (m *mockTicketSystem) IsOpen(t *ticket.Ticket) bool {
 }
 
 // Tests
+// fixture describes what a synchronizer under test finds in Alertmanager and the ticket system
+type fixture struct {
+	silences []*alertmanager.Silence
+	// unlisted are silences that ListSilences leaves out, like expired ones
+	unlisted []*alertmanager.Silence
+	alerts   []*alertmanager.Alert
+	tickets  []*ticket.Ticket
+	// configure adjusts the default configuration
+	configure func(cfg *SyncConfig)
+}
+
+// newFixture creates mocks holding the fixture's silences, alerts and tickets, and a
+// synchronizer over them
+func newFixture(f fixture) (*mockAlertManager, *mockTicketSystem, *Synchronizer) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	for _, silence := range f.silences {
+		am.silences[silence.ID] = silence
+	}
+	for _, silence := range f.unlisted {
+		am.silences[silence.ID] = silence
+		if am.unlisted == nil {
+			am.unlisted = map[string]bool{}
+		}
+		am.unlisted[silence.ID] = true
+	}
+	am.alerts = append(am.alerts, f.alerts...)
+	for _, tkt := range f.tickets {
+		ts.tickets[tkt.Key] = tkt
+	}
+
+	cfg := DefaultConfig()
+	if f.configure != nil {
+		f.configure(&cfg)
+	}
+	return am, ts, NewSynchronizer(am, ts, cfg)
+}
+
 func TestNewSynchronizer(t *testing.T) {
 	am := newMockAlertManager()
 	ts := newMockTicketSystem()
@@ -284,6 +525,104 @@ func TestSync_SilenceWithoutTicketRef(t *testing.T) {
 	}
 }
 
+// outcomePublisher records the outcome metrics of a run
+type outcomePublisher struct {
+	*metrics.NoopPublisher
+	actions  map[string]int
+	errors   map[string]int
+	success  time.Time
+	duration time.Duration
+	pushed   bool
+}
+
+func newOutcomePublisher() *outcomePublisher {
+	return &outcomePublisher{
+		NoopPublisher: metrics.NewNoopPublisher().(*metrics.NoopPublisher),
+		actions:       map[string]int{},
+		errors:        map[string]int{},
+	}
+}
+
+func (p *outcomePublisher) RecordAction(action string, count int)     { p.actions[action] += count }
+func (p *outcomePublisher) RecordError(category string)               { p.errors[category]++ }
+func (p *outcomePublisher) RecordSyncSuccess(timestamp time.Time)     { p.success = timestamp }
+func (p *outcomePublisher) RecordSyncDuration(duration time.Duration) { p.duration = duration }
+func (p *outcomePublisher) Push() error                               { p.pushed = true; return nil }
+
+func TestSync_OutcomeMetrics(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(24 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusResolved}
+
+	publisher := newOutcomePublisher()
+	sync := NewSynchronizer(am, ts, DefaultConfig())
+	sync.SetMetricsPublisher(publisher)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if publisher.actions[metrics.ActionSilencesDeleted] != 1 || publisher.actions[metrics.ActionSilencesExtended] != 0 {
+		t.Errorf("Unexpected actions: %v", publisher.actions)
+	}
+	if len(publisher.errors) != 0 || publisher.success.IsZero() || !publisher.pushed {
+		t.Errorf("Expected a successful run to be pushed, got errors %v and success %v", publisher.errors, publisher.success)
+	}
+	if publisher.duration <= 0 {
+		t.Errorf("Expected the run duration to be recorded, got %v", publisher.duration)
+	}
+
+	// A silence whose ticket cannot be fetched fails the run
+	am.silences["silence-2"] = &alertmanager.Silence{
+		ID:        "silence-2",
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(24 * time.Hour),
+		TicketRef: "PROJ-2",
+	}
+	publisher = newOutcomePublisher()
+	sync.SetMetricsPublisher(publisher)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if publisher.errors["silence"] != 1 || !publisher.success.IsZero() {
+		t.Errorf("Expected a silence error and no success, got errors %v and success %v", publisher.errors, publisher.success)
+	}
+
+	// A failure to list silences fails the run entirely
+	am.listErr = errors.New("connection refused")
+	publisher = newOutcomePublisher()
+	sync.SetMetricsPublisher(publisher)
+	if _, err := sync.Sync(); err == nil {
+		t.Fatal("Expected Sync() to fail")
+	}
+	if publisher.errors["alertmanager"] != 1 || !publisher.success.IsZero() || !publisher.pushed {
+		t.Errorf("Expected an alertmanager error to be pushed, got errors %v and success %v", publisher.errors, publisher.success)
+	}
+}
+
+func TestSync_RunStepErrors(t *testing.T) {
+	publisher := newOutcomePublisher()
+	sync := NewSynchronizer(newMockAlertManager(), newMockTicketSystem(), DefaultConfig())
+	sync.SetMetricsPublisher(publisher)
+	sync.AddRunStep("backup", func(*SyncResult) error { return errors.New("bucket not found") })
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(result.Errors) != 1 || len(result.ErrorCategories) != 1 || result.ErrorCategories[0] != "backup" {
+		t.Errorf("Expected a backup error, got %v in %v", result.Errors, result.ErrorCategories)
+	}
+	if publisher.errors["backup"] != 1 || !publisher.success.IsZero() {
+		t.Errorf("Expected the backup error to be published and no success, got errors %v and success %v", publisher.errors, publisher.success)
+	}
+}
+
 func TestProcessSilence_ResolvedTicket(t *testing.T) {
 	am := newMockAlertManager()
 	ts := newMockTicketSystem()
@@ -367,6 +706,238 @@ func TestProcessSilence_OpenTicketExpiringNow(t *testing.T) {
 	}
 }
 
+func TestProcessSilence_UpdateDueDate(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		am := newMockAlertManager()
+		ts := newMockTicketSystem()
+		cfg := DefaultConfig()
+		cfg.CheckAlerts = false
+		cfg.UpdateDueDate = enabled
+
+		am.silences["silence-1"] = &alertmanager.Silence{
+			ID:        "silence-1",
+			StartsAt:  time.Now().Add(-24 * time.Hour),
+			EndsAt:    time.Now().Add(2 * time.Hour),
+			TicketRef: "PROJ-1",
+		}
+		ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+		sync := NewSynchronizer(am, ts, cfg)
+		if _, err := sync.Sync(); err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+
+		due := ts.tickets["PROJ-1"].DueDate
+		if enabled && !due.Equal(am.silences["silence-1"].EndsAt) {
+			t.Errorf("Expected due date to match the silence end time %v, got %v", am.silences["silence-1"].EndsAt, due)
+		}
+		if !enabled && !due.IsZero() {
+			t.Errorf("Expected due date to be left alone when disabled, got %v", due)
+		}
+	}
+}
+
+func TestExtensionDurationFor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DurationField = "customfield_10050"
+
+	tests := []struct {
+		name     string
+		tkt      *ticket.Ticket
+		expected time.Duration
+	}{
+		{
+			name:     "no override",
+			tkt:      &ticket.Ticket{Key: "PROJ-1", Labels: []string{"team-a"}},
+			expected: cfg.ExtensionDuration,
+		},
+		{
+			name:     "label in days",
+			tkt:      &ticket.Ticket{Key: "PROJ-1", Labels: []string{"team-a", "silence-duration=30d"}},
+			expected: 30 * 24 * time.Hour,
+		},
+		{
+			name:     "label as go duration",
+			tkt:      &ticket.Ticket{Key: "PROJ-1", Labels: []string{"silence-duration=72h"}},
+			expected: 72 * time.Hour,
+		},
+		{
+			name:     "invalid label ignored",
+			tkt:      &ticket.Ticket{Key: "PROJ-1", Labels: []string{"silence-duration=forever"}},
+			expected: cfg.ExtensionDuration,
+		},
+		{
+			name: "field takes precedence over label",
+			tkt: &ticket.Ticket{
+				Key:          "PROJ-1",
+				Labels:       []string{"silence-duration=30d"},
+				CustomFields: map[string]string{"customfield_10050": "2d"},
+			},
+			expected: 2 * 24 * time.Hour,
+		},
+		{
+			name: "number field in days",
+			tkt: &ticket.Ticket{
+				Key:          "PROJ-1",
+				CustomFields: map[string]string{"customfield_10050": "30"},
+			},
+			expected: 30 * 24 * time.Hour,
+		},
+		{
+			name: "invalid field falls back to label",
+			tkt: &ticket.Ticket{
+				Key:          "PROJ-1",
+				Labels:       []string{"silence-duration=30d"},
+				CustomFields: map[string]string{"customfield_10050": "-1d"},
+			},
+			expected: 30 * 24 * time.Hour,
+		},
+		{
+			name:     "priority extension",
+			tkt:      &ticket.Ticket{Key: "PROJ-1", Priority: "Highest"},
+			expected: 24 * time.Hour,
+		},
+		{
+			name:     "unmapped priority uses default",
+			tkt:      &ticket.Ticket{Key: "PROJ-1", Priority: "Low"},
+			expected: cfg.ExtensionDuration,
+		},
+		{
+			name:     "label takes precedence over priority",
+			tkt:      &ticket.Ticket{Key: "PROJ-1", Priority: "Highest", Labels: []string{"silence-duration=3d"}},
+			expected: 3 * 24 * time.Hour,
+		},
+	}
+
+	cfg.PriorityExtensions = map[string]time.Duration{"highest": 24 * time.Hour}
+	sync := NewSynchronizer(newMockAlertManager(), newMockTicketSystem(), cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sync.extensionDurationFor(tt.tkt); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProcessSilence_TicketDurationOverride(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		CreatedBy: "user",
+		Comment:   "Test",
+		StartsAt:  time.Now().Add(-48 * time.Hour),
+		EndsAt:    time.Now().Add(1 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{
+		Key:    "PROJ-1",
+		Status: ticket.StatusOpen,
+		Labels: []string{"silence-duration=30d"},
+	}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.SilencesExtended != 1 {
+		t.Fatalf("Expected 1 silence extended, got %d", result.SilencesExtended)
+	}
+	remaining := time.Until(am.silences["silence-1"].EndsAt)
+	if remaining < 29*24*time.Hour || remaining > 30*24*time.Hour {
+		t.Errorf("Expected silence to be extended by about 30 days, got %v", remaining)
+	}
+}
+
+func TestProcessSilence_RequireAssignee(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.RequireAssignee = true
+
+	for _, id := range []string{"1", "2"} {
+		am.silences["silence-"+id] = &alertmanager.Silence{
+			ID:        "silence-" + id,
+			CreatedBy: "user",
+			Comment:   "Test",
+			StartsAt:  time.Now().Add(-48 * time.Hour),
+			EndsAt:    time.Now().Add(1 * time.Hour),
+			TicketRef: "PROJ-" + id,
+		}
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusOpen, Assignee: "abc123"}
+
+	sync := NewSynchronizer(am, ts, cfg)
+
+	// Run twice to verify the ownership request is only posted once
+	for i := 0; i < 2; i++ {
+		result, err := sync.Sync()
+		if err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+		if result.ExtensionsWithheld != 1 {
+			t.Errorf("Expected 1 extension withheld, got %d", result.ExtensionsWithheld)
+		}
+		for _, m := range result.Managed {
+			if m.SilenceID == "silence-1" && m.Health != HealthTicketUnassigned {
+				t.Errorf("Expected silence-1 health to be %s, got %s", HealthTicketUnassigned, m.Health)
+			}
+		}
+	}
+
+	for _, id := range am.extendedIDs {
+		if id == "silence-1" {
+			t.Error("Expected silence-1 not to be extended")
+		}
+	}
+	if len(am.extendedIDs) == 0 || am.extendedIDs[0] != "silence-2" {
+		t.Error("Expected silence-2 to be extended")
+	}
+	if len(ts.comments["PROJ-1"]) != 1 {
+		t.Errorf("Expected 1 ownership request comment, got %d", len(ts.comments["PROJ-1"]))
+	}
+}
+
+func TestProcessSilence_BusinessHours(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	bh, err := schedule.ParseBusinessHours("09:00-17:00", "Mon-Fri", "UTC")
+	if err != nil {
+		t.Fatalf("ParseBusinessHours() failed: %v", err)
+	}
+	cfg.BusinessHours = bh
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		CreatedBy: "user",
+		Comment:   "Test",
+		StartsAt:  time.Now().Add(-48 * time.Hour),
+		EndsAt:    time.Now().Add(1 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	endsAt := am.silences["silence-1"].EndsAt
+	if !bh.Contains(endsAt) {
+		t.Errorf("Expected extended end time %v to fall within business hours", endsAt)
+	}
+	if endsAt.Before(time.Now().Add(cfg.ExtensionDuration - time.Minute)) {
+		t.Errorf("Expected end time %v not to be earlier than the extension duration", endsAt)
+	}
+}
+
 func TestProcessSilence_OpenTicketAlreadyExpired(t *testing.T) {
 	am := newMockAlertManager()
 	ts := newMockTicketSystem()
@@ -565,6 +1136,32 @@ func TestCheckRefiredAlerts_ReopenClosedTicket(t *testing.T) {
 	}
 }
 
+func TestCheckRefiredAlerts_SeverityPriority(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.SeverityPriorities = map[string]string{"critical": "Highest", "warning": "Medium"}
+
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "ticket": "PROJ-1", "severity": "Critical"}},
+		{Labels: map[string]string{"alertname": "HighLatency", "ticket": "PROJ-2", "severity": "info"}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed, Priority: "Low"}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusClosed, Priority: "Low"}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if ts.tickets["PROJ-1"].Priority != "Highest" {
+		t.Errorf("Expected PROJ-1 priority 'Highest', got '%s'", ts.tickets["PROJ-1"].Priority)
+	}
+	if ts.tickets["PROJ-2"].Priority != "Low" {
+		t.Errorf("Expected unmapped severity to leave PROJ-2 priority unchanged, got '%s'", ts.tickets["PROJ-2"].Priority)
+	}
+}
+
 func TestCheckRefiredAlerts_OpenTicketWithRefiredAlert(t *testing.T) {
 	am := newMockAlertManager()
 	ts := newMockTicketSystem()
@@ -711,6 +1308,32 @@ func TestSync_ListSilencesError(t *testing.T) {
 	}
 }
 
+func TestSyncContext_Cancelled(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusResolved}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := NewSynchronizer(am, ts, DefaultConfig()).SyncContext(ctx)
+	if err != nil {
+		t.Fatalf("SyncContext() failed: %v", err)
+	}
+	if len(am.deletedIDs) != 0 {
+		t.Errorf("Expected no silences to be processed after cancellation, deleted %v", am.deletedIDs)
+	}
+	if len(result.Errors) == 0 || !errors.Is(result.Errors[0], context.Canceled) {
+		t.Errorf("Expected cancellation error, got %v", result.Errors)
+	}
+}
+
 func TestSync_DeleteSilenceError(t *testing.T) {
 	am := newMockAlertManager()
 	ts := newMockTicketSystem()
@@ -780,3 +1403,164 @@ func TestSync_ExtendSilenceError(t *testing.T) {
 		t.Errorf("Expected 1 error, got %d", len(result.Errors))
 	}
 }
+
+func TestSync_RecordsHygiene(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		StartsAt:  time.Now().Add(-48 * time.Hour),
+		EndsAt:    time.Now().Add(5 * 24 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	am.silences["silence-2"] = &alertmanager.Silence{
+		ID:        "silence-2",
+		StartsAt:  time.Now().Add(-24 * time.Hour),
+		EndsAt:    time.Now().Add(5 * 24 * time.Hour),
+		TicketRef: "PROJ-2",
+	}
+	am.silences["silence-3"] = &alertmanager.Silence{
+		ID:       "silence-3",
+		StartsAt: time.Now().Add(-12 * time.Hour),
+		EndsAt:   time.Now().Add(5 * 24 * time.Hour),
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusClosed}
+
+	store := state.NewMemoryStore()
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetStateStore(store)
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	h := result.Hygiene
+	if h.TotalSilences != 3 || h.OpenTicketSilences != 1 || h.OrphanSilences != 1 {
+		t.Errorf("Unexpected hygiene counts: %+v", h)
+	}
+	if h.MedianAge < 23*time.Hour || h.MedianAge > 25*time.Hour {
+		t.Errorf("Expected median age ~24h, got %v", h.MedianAge)
+	}
+
+	st, _ := store.Load()
+	if len(st.Hygiene) != 1 {
+		t.Errorf("Expected hygiene sample to be persisted, got %d samples", len(st.Hygiene))
+	}
+}
+
+func TestSync_ManagedInventory(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+
+	am.silences["silence-open"] = &alertmanager.Silence{ID: "silence-open", EndsAt: time.Now().Add(5 * 24 * time.Hour), TicketRef: "PROJ-1"}
+	am.silences["silence-closed"] = &alertmanager.Silence{ID: "silence-closed", EndsAt: time.Now().Add(5 * 24 * time.Hour), TicketRef: "PROJ-2"}
+	am.silences["silence-resolved"] = &alertmanager.Silence{ID: "silence-resolved", EndsAt: time.Now().Add(5 * 24 * time.Hour), TicketRef: "PROJ-3"}
+	am.silences["silence-missing"] = &alertmanager.Silence{ID: "silence-missing", EndsAt: time.Now().Add(5 * 24 * time.Hour), TicketRef: "PROJ-404"}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusOpen}
+	ts.tickets["PROJ-2"] = &ticket.Ticket{Key: "PROJ-2", Status: ticket.StatusClosed}
+	ts.tickets["PROJ-3"] = &ticket.Ticket{Key: "PROJ-3", Status: ticket.StatusResolved}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	health := make(map[string]string)
+	for _, m := range result.Managed {
+		health[m.SilenceID] = m.Health
+	}
+
+	expected := map[string]string{
+		"silence-open":    HealthHealthy,
+		"silence-closed":  HealthTicketNotOpen,
+		"silence-missing": HealthTicketUnavailable,
+	}
+	if len(health) != len(expected) {
+		t.Fatalf("Expected %d managed silences, got %v", len(expected), health)
+	}
+	for id, want := range expected {
+		if health[id] != want {
+			t.Errorf("Expected %s to be %s, got %s", id, want, health[id])
+		}
+	}
+}
+
+func TestSync_InjectedTicketFaults(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	for i, key := range []string{"PROJ-1", "PROJ-2"} {
+		id := fmt.Sprintf("silence-%d", i+1)
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			StartsAt:  time.Now().Add(-time.Hour),
+			EndsAt:    time.Now().Add(2 * time.Hour),
+			TicketRef: key,
+		}
+		ts.tickets[key] = &ticket.Ticket{Key: key, Status: ticket.StatusOpen}
+	}
+
+	// Fail every ticket lookup while Alertmanager keeps working
+	injector := faults.NewInjector(faults.Config{ErrorRate: 1, Operations: []string{"GetTicket", "GetTickets"}, Seed: 1})
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	sync := NewSynchronizer(am, faults.WrapTicketSystem(ts, injector), cfg)
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %d: %v", len(result.Errors), result.Errors)
+	}
+	for _, e := range result.Errors {
+		if !errors.Is(e, faults.ErrInjected) {
+			t.Errorf("Expected injected fault to be wrapped, got %v", e)
+		}
+	}
+	if result.SilencesExtended != 0 || len(am.deletedIDs) != 0 {
+		t.Errorf("Expected silences to be left alone, extended=%d deleted=%v", result.SilencesExtended, am.deletedIDs)
+	}
+}
+
+func TestSync_BulkTicketLookup(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	for i, key := range []string{"PROJ-1", "PROJ-2", "PROJ-2"} {
+		id := fmt.Sprintf("silence-%d", i+1)
+		am.silences[id] = &alertmanager.Silence{
+			ID:        id,
+			StartsAt:  time.Now().Add(-time.Hour),
+			EndsAt:    time.Now().Add(72 * time.Hour),
+			TicketRef: key,
+		}
+		ts.tickets[key] = &ticket.Ticket{Key: key, Status: ticket.StatusOpen}
+	}
+	cfg := DefaultConfig()
+	cfg.CheckAlerts = false
+	sync := NewSynchronizer(am, ts, cfg)
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if ts.bulkCalls != 1 || ts.getCalls != 0 {
+		t.Errorf("Expected a single bulk lookup, got %d bulk and %d individual lookups", ts.bulkCalls, ts.getCalls)
+	}
+
+	// A failed bulk lookup falls back to individual lookups
+	ts.getTicketsErr = errors.New("issue does not exist")
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if ts.getCalls != 3 || len(result.Errors) != 0 {
+		t.Errorf("Expected 3 individual lookups without errors, got %d and %v", ts.getCalls, result.Errors)
+	}
+}
@@ -0,0 +1,29 @@
+package sync
+
+import (
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/teams"
+)
+
+// teamOfMatchers returns the team owning a silence, named by an equality matcher on the
+// team label, or nil
+func (s *Synchronizer) teamOfMatchers(matchers []alertmanager.Matcher) *teams.Team {
+	if s.config.Teams == nil {
+		return nil
+	}
+	for _, m := range matchers {
+		if m.Name == s.config.Teams.Label && m.IsEqual && !m.IsRegex {
+			return s.config.Teams.Lookup(map[string]string{m.Name: m.Value})
+		}
+	}
+	return nil
+}
+
+// teamProject returns the Jira project of the tickets created for a team, or "" for the
+// configured project
+func teamProject(team *teams.Team) string {
+	if team == nil {
+		return ""
+	}
+	return team.JiraProject
+}
@@ -0,0 +1,72 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/teams"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func testTeams(t *testing.T) *teams.Directory {
+	t.Helper()
+	directory, err := teams.Parse([]byte(`
+teams:
+  - name: storage
+    labelValues: [storage, ceph]
+    jiraProject: STOR
+    onCallSchedule: PSTORAGE
+`))
+	if err != nil {
+		t.Fatalf("teams.Parse() failed: %v", err)
+	}
+	return directory
+}
+
+func TestCreateSilence_TeamProject(t *testing.T) {
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.Teams = testTeams(t)
+	sync := NewSynchronizer(newMockAlertManager(), ts, cfg)
+
+	for matcher, project := range map[string]string{
+		"team=ceph":      "STOR", // Alternative label value of the team
+		"team=payments":  "",     // Team without a mapping
+		`team=~"ceph.*"`: "",     // Regular expressions name no team
+	} {
+		matchers, err := ParseMatchers([]string{"alertname=DiskFull", matcher})
+		if err != nil {
+			t.Fatalf("ParseMatchers() failed: %v", err)
+		}
+		silence, err := sync.CreateSilence(SilenceDefinition{Matchers: matchers, Duration: time.Hour})
+		if err != nil {
+			t.Fatalf("CreateSilence() failed: %v", err)
+		}
+		if got := ts.tickets[silence.TicketRef].Project; got != project {
+			t.Errorf("%s: expected ticket project %q, got %q", matcher, project, got)
+		}
+	}
+}
+
+func TestCheckRefiredAlerts_TeamOnCall(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	cfg := DefaultConfig()
+	cfg.Teams = testTeams(t)
+	cfg.OnCallSchedules = map[string]string{"ceph": "PCEPH"}
+
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "ticket": "PROJ-1", "team": "ceph"}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+
+	sync := NewSynchronizer(am, ts, cfg)
+	sync.SetOnCallResolver(staticOnCall{"PSTORAGE": "alice@example.com", "PCEPH": "bob@example.com"})
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if got := ts.tickets["PROJ-1"].Assignee; got != "alice@example.com" {
+		t.Errorf("Expected the schedule of the team mapping to be used, got assignee %q", got)
+	}
+}
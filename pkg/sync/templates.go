@@ -0,0 +1,163 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// SilenceTemplate is a named, reusable silence definition, such as "node-maintenance".
+// Matchers, summary and comment are Go templates filled in with the parameters given
+// when the template is used, e.g. instance=~"{{ .node }}:.*".
+type SilenceTemplate struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Matchers    []string      `json:"matchers"`
+	Duration    time.Duration `json:"-"`
+	Summary     string        `json:"summary,omitempty"`
+	Comment     string        `json:"comment,omitempty"`
+}
+
+// rawSilenceTemplate is the serialized form of a SilenceTemplate
+type rawSilenceTemplate struct {
+	SilenceTemplate
+	Duration string `json:"duration"`
+}
+
+// SilenceTemplates holds the silence templates by name
+type SilenceTemplates map[string]SilenceTemplate
+
+// LoadSilenceTemplates reads silence templates from a YAML file holding a list of
+// templates, or an object with a "templates" list
+func LoadSilenceTemplates(path string) (SilenceTemplates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silence templates: %w", err)
+	}
+	return ParseSilenceTemplates(data)
+}
+
+// ParseSilenceTemplates parses silence templates from YAML. Every template needs a
+// unique name and at least one matcher, and its fields must be valid templates.
+func ParseSilenceTemplates(data []byte) (SilenceTemplates, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse silence templates: %w", err)
+	}
+
+	var raws []rawSilenceTemplate
+	if err := json.Unmarshal(jsonData, &raws); err != nil {
+		var doc struct {
+			Templates []rawSilenceTemplate `json:"templates"`
+		}
+		if err := json.Unmarshal(jsonData, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse silence templates: %w", err)
+		}
+		raws = doc.Templates
+	}
+
+	templates := make(SilenceTemplates, len(raws))
+	for i, raw := range raws {
+		t := raw.SilenceTemplate
+		if t.Name == "" {
+			return nil, fmt.Errorf("template %d: name is required", i+1)
+		}
+		if _, exists := templates[t.Name]; exists {
+			return nil, fmt.Errorf("template %s is defined twice", t.Name)
+		}
+		if len(t.Matchers) == 0 {
+			return nil, fmt.Errorf("template %s: at least one matcher is required", t.Name)
+		}
+		if raw.Duration != "" {
+			if t.Duration, err = parseDirectiveDuration(raw.Duration); err != nil {
+				return nil, fmt.Errorf("template %s: invalid duration %q: %w", t.Name, raw.Duration, err)
+			}
+		}
+		for _, text := range append([]string{t.Summary, t.Comment}, t.Matchers...) {
+			if _, err := parseTemplate(t.Name, text); err != nil {
+				return nil, fmt.Errorf("template %s: %w", t.Name, err)
+			}
+		}
+		templates[t.Name] = t
+	}
+	return templates, nil
+}
+
+// Names returns the template names in alphabetical order
+func (t SilenceTemplates) Names() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Definition fills in the named template with params, returning the silence to create.
+// A parameter used by the template but missing from params is an error.
+func (t SilenceTemplates) Definition(name string, params map[string]string) (SilenceDefinition, error) {
+	var def SilenceDefinition
+	tmpl, ok := t[name]
+	if !ok {
+		return def, fmt.Errorf("unknown silence template %q (available: %s)", name, strings.Join(t.Names(), ", "))
+	}
+
+	var err error
+	def.Duration = tmpl.Duration
+	if def.Summary, err = expandTemplate(tmpl.Name, tmpl.Summary, params); err != nil {
+		return def, err
+	}
+	if def.Comment, err = expandTemplate(tmpl.Name, tmpl.Comment, params); err != nil {
+		return def, err
+	}
+	values := make([]string, 0, len(tmpl.Matchers))
+	for _, text := range tmpl.Matchers {
+		value, err := expandTemplate(tmpl.Name, text, params)
+		if err != nil {
+			return def, err
+		}
+		values = append(values, value)
+	}
+	if def.Matchers, err = ParseMatchers(values); err != nil {
+		return def, fmt.Errorf("template %s: %w", tmpl.Name, err)
+	}
+	return def, nil
+}
+
+// ParseTemplateParams parses template parameters given as name=value
+func ParseTemplateParams(values []string) (map[string]string, error) {
+	params := make(map[string]string, len(values))
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid template parameter %q (expected name=value)", value)
+		}
+		params[name] = v
+	}
+	return params, nil
+}
+
+// parseTemplate parses a field of the named silence template
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+// expandTemplate fills in a field of the named silence template with params
+func expandTemplate(name, text string, params map[string]string) (string, error) {
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,76 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTemplates = `
+templates:
+  - name: node-maintenance
+    description: Silence all alerts of a node during maintenance
+    matchers: ['instance=~"{{ .node }}(:.*)?"']
+    duration: 4h
+    summary: Maintenance of {{ .node }}
+    comment: Node {{ .node }} is being maintained
+  - name: noisy-dev-cluster
+    matchers: [cluster=dev, severity!=critical]
+    duration: 3d
+    summary: Dev cluster is noisy
+`
+
+func TestParseSilenceTemplates(t *testing.T) {
+	templates, err := ParseSilenceTemplates([]byte(testTemplates))
+	if err != nil {
+		t.Fatalf("ParseSilenceTemplates() failed: %v", err)
+	}
+	if names := strings.Join(templates.Names(), ","); names != "node-maintenance,noisy-dev-cluster" {
+		t.Fatalf("Unexpected templates: %s", names)
+	}
+
+	def, err := templates.Definition("node-maintenance", map[string]string{"node": "db-1"})
+	if err != nil {
+		t.Fatalf("Definition() failed: %v", err)
+	}
+	if def.Summary != "Maintenance of db-1" || def.Comment != "Node db-1 is being maintained" || def.Duration != 4*time.Hour {
+		t.Errorf("Unexpected definition: %+v", def)
+	}
+	if got := formatMatchers(def.Matchers); got != `instance=~db-1(:.*)?` {
+		t.Errorf("Unexpected matchers: %s", got)
+	}
+
+	if _, err := templates.Definition("node-maintenance", nil); err == nil || !strings.Contains(err.Error(), "node") {
+		t.Errorf("Expected an error for the missing parameter, got %v", err)
+	}
+	if _, err := templates.Definition("reboot", nil); err == nil || !strings.Contains(err.Error(), "noisy-dev-cluster") {
+		t.Errorf("Expected an error listing the templates, got %v", err)
+	}
+}
+
+func TestParseSilenceTemplates_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"no name":      "- matchers: [a=b]",
+		"no matchers":  "- name: empty",
+		"duplicate":    "- {name: a, matchers: [a=b]}\n- {name: a, matchers: [a=c]}",
+		"duration":     "- {name: a, matchers: [a=b], duration: soon}",
+		"bad template": "- {name: a, matchers: ['a={{ .b'}]",
+	} {
+		if _, err := ParseSilenceTemplates([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseTemplateParams(t *testing.T) {
+	params, err := ParseTemplateParams([]string{"node=db-1", "reason=disk=full"})
+	if err != nil {
+		t.Fatalf("ParseTemplateParams() failed: %v", err)
+	}
+	if params["node"] != "db-1" || params["reason"] != "disk=full" {
+		t.Errorf("Unexpected parameters: %v", params)
+	}
+	if _, err := ParseTemplateParams([]string{"node"}); err == nil {
+		t.Error("Expected an error for a parameter without a value")
+	}
+}
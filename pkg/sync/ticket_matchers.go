@@ -0,0 +1,171 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// ticketMatchersHeader introduces the matchers block in a ticket description
+const ticketMatchersHeader = "silence-matchers:"
+
+// ticketMatchersRejectedMarker identifies comments rejecting an invalid matchers block
+const ticketMatchersRejectedMarker = "Rejected silence matchers from the ticket"
+
+// ticketMatchers returns the matchers block the ticket owner maintains, either from the
+// configured custom field or from the "silence-matchers:" block in the description.
+// The block lists matchers on the header line and on the following lines up to the
+// next blank line, which may be fenced as a code block. It returns "" if the ticket has
+// no block.
+func (s *Synchronizer) ticketMatchers(tkt *ticket.Ticket) string {
+	if s.config.MatchersField != "" {
+		if value := strings.TrimSpace(tkt.CustomFields[s.config.MatchersField]); value != "" {
+			return value
+		}
+	}
+
+	var block []string
+	inBlock := false
+	for _, line := range strings.Split(tkt.Description, "\n") {
+		line = strings.TrimSpace(line)
+		if !inBlock {
+			if strings.HasPrefix(strings.ToLower(line), ticketMatchersHeader) {
+				inBlock = true
+				if rest := strings.TrimSpace(line[len(ticketMatchersHeader):]); rest != "" {
+					block = append(block, rest)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "```") {
+			if len(block) > 0 {
+				break
+			}
+			continue
+		}
+		if line == "" {
+			break
+		}
+		block = append(block, line)
+	}
+	return strings.Join(block, "\n")
+}
+
+// parseTicketMatchers parses a matchers block. Matchers are separated by whitespace and
+// must not all match the empty string, which Alertmanager rejects as silencing every alert.
+func parseTicketMatchers(block string) ([]alertmanager.Matcher, error) {
+	var matchers []alertmanager.Matcher
+	for _, field := range strings.Fields(block) {
+		m, err := parseMatcher(field)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("no matchers given")
+	}
+	for _, m := range matchers {
+		if !matchesEmpty(m) {
+			return matchers, nil
+		}
+	}
+	return nil, fmt.Errorf("at least one matcher must not match the empty string")
+}
+
+// matchesEmpty reports whether the matcher matches a missing (empty) label value
+func matchesEmpty(m alertmanager.Matcher) bool {
+	matches := m.Value == ""
+	if m.IsRegex {
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		matches = err == nil && re.MatchString("")
+	}
+	return matches == m.IsEqual
+}
+
+// applyTicketMatchers replaces the silence matchers with the ticket's matchers block when
+// they differ, and comments the change on the ticket. Invalid blocks are reported once.
+func (s *Synchronizer) applyTicketMatchers(silence *alertmanager.Silence, tkt *ticket.Ticket, result *SyncResult) error {
+	block := s.ticketMatchers(tkt)
+	if block == "" {
+		return nil
+	}
+
+	matchers, err := parseTicketMatchers(block)
+	if err != nil {
+		s.rejectTicketMatchers(tkt, block, err)
+		return nil
+	}
+	if matcherKey(matchers) == matcherKey(silence.Matchers) {
+		return nil
+	}
+
+	diff := matcherDiff(silence.Matchers, matchers)
+	log.Printf("Updating matchers of silence %s from ticket %s", silence.ID, tkt.Key)
+	previous, previousID := silence.Matchers, silence.ID
+	silence.Matchers = matchers
+	if err := s.alertManager.UpdateSilence(silence); err != nil {
+		silence.Matchers = previous
+		return fmt.Errorf("failed to update matchers from ticket: %w", err)
+	}
+	result.MatchersUpdated++
+	s.followRenewedSilence(tkt, previousID, silence.ID)
+
+	msg := fmt.Sprintf("Silence %s matchers updated from the ticket:\n%s", s.silenceRef(silence.ID), codeBlock("diff", diff))
+	if err := s.ticketSystem.AddComment(tkt.Key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+	return nil
+}
+
+// rejectTicketMatchers comments why a matchers block was not applied, unless the same
+// block has already been rejected
+func (s *Synchronizer) rejectTicketMatchers(tkt *ticket.Ticket, block string, reason error) {
+	log.Printf("Ignoring invalid matchers on ticket %s: %v", tkt.Key, reason)
+
+	comments, err := s.ticketSystem.GetComments(tkt.Key)
+	if err != nil {
+		log.Printf("Warning: failed to get comments for ticket %s: %v", tkt.Key, err)
+		return
+	}
+	for _, c := range comments {
+		if strings.Contains(c.Body, ticketMatchersRejectedMarker) && strings.Contains(c.Body, block) {
+			return
+		}
+	}
+
+	msg := fmt.Sprintf("%s (%v); the silence keeps its current matchers:\n%s", ticketMatchersRejectedMarker, reason, codeBlock("", block))
+	if err := s.ticketSystem.AddComment(tkt.Key, msg); err != nil {
+		log.Printf("Warning: failed to add comment to ticket %s: %v", tkt.Key, err)
+	}
+}
+
+// matcherDiff renders removed matchers with "-" and added matchers with "+"
+func matcherDiff(before, after []alertmanager.Matcher) string {
+	inBefore := make(map[string]bool, len(before))
+	for _, m := range before {
+		inBefore[formatMatchers([]alertmanager.Matcher{m})] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, m := range after {
+		inAfter[formatMatchers([]alertmanager.Matcher{m})] = true
+	}
+
+	var lines []string
+	for _, m := range before {
+		if f := formatMatchers([]alertmanager.Matcher{m}); !inAfter[f] {
+			lines = append(lines, "- "+f)
+		}
+	}
+	for _, m := range after {
+		if f := formatMatchers([]alertmanager.Matcher{m}); !inBefore[f] {
+			lines = append(lines, "+ "+f)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
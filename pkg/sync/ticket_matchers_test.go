@@ -0,0 +1,168 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+// ticketMatchersFixture is an open ticket with the given description and its silence
+func ticketMatchersFixture(description string) fixture {
+	return fixture{
+		silences: []*alertmanager.Silence{{
+			ID:        "silence-1",
+			EndsAt:    time.Now().Add(72 * time.Hour),
+			TicketRef: "PROJ-1",
+			Matchers: []alertmanager.Matcher{
+				{Name: "alertname", Value: "DiskFull", IsEqual: true},
+				{Name: "instance", Value: "db-1", IsEqual: true},
+			},
+		}},
+		tickets: []*ticket.Ticket{{Key: "PROJ-1", Status: ticket.StatusOpen, Description: description}},
+		configure: func(cfg *SyncConfig) {
+			cfg.CheckAlerts = false
+			cfg.TicketMatchers = true
+		},
+	}
+}
+
+func TestTicketMatchers_Applied(t *testing.T) {
+	am, ts, sync := newFixture(ticketMatchersFixture("Disk is filling up.\n\nsilence-matchers:\nalertname=DiskFull\ninstance=~\"db-.*\"\n\nOwner: storage team"))
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MatchersUpdated != 1 {
+		t.Fatalf("Expected 1 silence with updated matchers, got %d", result.MatchersUpdated)
+	}
+
+	matchers := am.silences["silence-1"].Matchers
+	if len(matchers) != 2 || !matchers[1].IsRegex || matchers[1].Value != "db-.*" {
+		t.Errorf("Unexpected matchers: %+v", matchers)
+	}
+
+	comments := ts.comments["PROJ-1"]
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 comment, got %d", len(comments))
+	}
+	if !strings.Contains(comments[0], "- instance=db-1") || !strings.Contains(comments[0], "+ instance=~db-.*") {
+		t.Errorf("Expected comment to contain the matcher diff, got %q", comments[0])
+	}
+
+	// A second run finds the silence up to date
+	result, err = sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MatchersUpdated != 0 || len(ts.comments["PROJ-1"]) != 1 {
+		t.Errorf("Expected no further changes, got %d updates and %d comments", result.MatchersUpdated, len(ts.comments["PROJ-1"]))
+	}
+}
+
+func TestTicketMatchers_RenewedSilence(t *testing.T) {
+	am, ts, sync := newFixture(ticketMatchersFixture("silence-matchers:\nalertname=DiskFull\ninstance=db-2"))
+	sync.config.SilenceUIURL = "https://alertmanager.example.com"
+	am.renewOnUpdate = true
+	am.silences["silence-1"].EndsAt = time.Now().Add(time.Hour)
+	ts.tickets["PROJ-1"].SilenceRef = "silence-1"
+	ts.remoteLinks["PROJ-1"] = map[string]ticket.RemoteLink{silenceLinkID("silence-1"): {GlobalID: silenceLinkID("silence-1")}}
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if _, ok := am.silences["silence-1"]; ok || am.silences["silence-0"] == nil {
+		t.Fatalf("Expected silence-1 to be replaced by silence-0, got %v", am.silences)
+	}
+	if len(am.extendedIDs) != 1 || am.extendedIDs[0] != "silence-0" {
+		t.Errorf("Expected the replacement to be extended, got %v", am.extendedIDs)
+	}
+	if len(am.silences) != 1 {
+		t.Errorf("Expected no duplicate silence, got %v", am.silences)
+	}
+	if got := ts.tickets["PROJ-1"].SilenceRef; got != "silence-0" {
+		t.Errorf("Expected the ticket to reference silence-0, got %q", got)
+	}
+	links := ts.remoteLinks["PROJ-1"]
+	if _, ok := links[silenceLinkID("silence-0")]; !ok || len(links) != 1 {
+		t.Errorf("Expected the ticket to link only silence-0, got %v", links)
+	}
+	if comments := ts.comments["PROJ-1"]; len(comments) == 0 || !strings.Contains(comments[0], "silence-0") {
+		t.Errorf("Expected the matchers comment to name silence-0, got %v", comments)
+	}
+}
+
+func TestTicketMatchers_CodeBlock(t *testing.T) {
+	am, _, sync := newFixture(ticketMatchersFixture("silence-matchers:\n```\nalertname=DiskFull\ninstance=db-2\n```\nOwner: storage team"))
+
+	result, err := sync.Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.MatchersUpdated != 1 || am.silences["silence-1"].Matchers[1].Value != "db-2" {
+		t.Errorf("Expected matchers from the code block, got %+v", am.silences["silence-1"].Matchers)
+	}
+}
+
+func TestTicketMatchers_CustomField(t *testing.T) {
+	am, ts, sync := newFixture(ticketMatchersFixture(""))
+	sync.config.MatchersField = "customfield_10060"
+	ts.tickets["PROJ-1"].CustomFields = map[string]string{"customfield_10060": "alertname=DiskFull instance=db-2"}
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if got := am.silences["silence-1"].Matchers[1].Value; got != "db-2" {
+		t.Errorf("Expected instance matcher from the custom field, got %q", got)
+	}
+}
+
+func TestTicketMatchers_Rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+	}{
+		{name: "invalid syntax", block: "alertname"},
+		{name: "matches everything", block: `alertname=~".*"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am, ts, sync := newFixture(ticketMatchersFixture("silence-matchers: " + tt.block))
+
+			for run := 0; run < 2; run++ {
+				result, err := sync.Sync()
+				if err != nil {
+					t.Fatalf("Sync() failed: %v", err)
+				}
+				if result.MatchersUpdated != 0 {
+					t.Errorf("Expected invalid matchers not to be applied")
+				}
+			}
+
+			if len(am.silences["silence-1"].Matchers) != 2 || am.silences["silence-1"].Matchers[1].Value != "db-1" {
+				t.Errorf("Expected silence matchers to be unchanged, got %+v", am.silences["silence-1"].Matchers)
+			}
+			comments := ts.comments["PROJ-1"]
+			if len(comments) != 1 || !strings.Contains(comments[0], ticketMatchersRejectedMarker) {
+				t.Errorf("Expected a single rejection comment, got %v", comments)
+			}
+		})
+	}
+}
+
+func TestTicketMatchers_Disabled(t *testing.T) {
+	am, _, sync := newFixture(ticketMatchersFixture("silence-matchers: alertname=Other"))
+	sync.config.TicketMatchers = false
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if am.silences["silence-1"].Matchers[0].Value != "DiskFull" {
+		t.Error("Expected matchers to be left alone when ticket matchers are disabled")
+	}
+}
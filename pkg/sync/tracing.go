@@ -0,0 +1,16 @@
+package sync
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// tracer traces synchronization runs; spans are dropped unless tracing is enabled
+var tracer = otel.Tracer("github.com/conallob/silence-manager/pkg/sync")
+
+// requestContextSetter is implemented by ticket systems that pass the values of a
+// context, such as the current trace span, to their requests
+type requestContextSetter interface {
+	SetRequestContext(ctx context.Context)
+}
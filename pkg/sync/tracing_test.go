@@ -0,0 +1,51 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestSync_Traces(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.silences["silence-1"] = &alertmanager.Silence{
+		ID:        "silence-1",
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(24 * time.Hour),
+		TicketRef: "PROJ-1",
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusResolved}
+
+	if _, err := NewSynchronizer(am, ts, DefaultConfig()).Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != "silence" || spans[1].Name != "sync" {
+		t.Fatalf("Expected a silence span and a sync span, got %v", spans)
+	}
+	if spans[0].Parent.SpanID() != spans[1].SpanContext.SpanID() {
+		t.Error("Expected the silence span to be a child of the sync span")
+	}
+	attrs := attribute.NewSet(spans[0].Attributes...)
+	if id, _ := attrs.Value("silence.id"); id.AsString() != "silence-1" {
+		t.Errorf("Expected the silence ID attribute, got %v", spans[0].Attributes)
+	}
+	if key, _ := attrs.Value("ticket.key"); key.AsString() != "PROJ-1" {
+		t.Errorf("Expected the ticket key attribute, got %v", spans[0].Attributes)
+	}
+}
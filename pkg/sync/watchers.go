@@ -0,0 +1,13 @@
+package sync
+
+import "log"
+
+// addWatchers adds the configured watchers to a ticket that silence-manager created or
+// reopened, so they are notified of changes made by the automation
+func (s *Synchronizer) addWatchers(key string) {
+	for _, user := range s.config.Watchers {
+		if err := s.ticketSystem.AddWatcher(key, user); err != nil {
+			log.Printf("Warning: failed to add watcher %s to ticket %s: %v", user, key, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/ticket"
+)
+
+func TestWatchers_ReopenedAndCreatedTickets(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "instance": "db-1", "ticket": "PROJ-1"}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+
+	cfg := DefaultConfig()
+	cfg.Watchers = []string{"sre@example.com", "abc123"}
+	sync := NewSynchronizer(am, ts, cfg)
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if got := ts.watchers["PROJ-1"]; len(got) != 2 || got[0] != "sre@example.com" || got[1] != "abc123" {
+		t.Errorf("Expected the reopened ticket to be watched, got %v", got)
+	}
+
+	silence, err := sync.CreateSilence(SilenceDefinition{
+		Matchers: []alertmanager.Matcher{{Name: "alertname", Value: "HighLatency", IsEqual: true}},
+	})
+	if err != nil {
+		t.Fatalf("CreateSilence() failed: %v", err)
+	}
+	if got := ts.watchers[silence.TicketRef]; len(got) != 2 {
+		t.Errorf("Expected the created ticket %s to be watched, got %v", silence.TicketRef, got)
+	}
+}
+
+func TestWatchers_FollowUpTicket(t *testing.T) {
+	_, ts, sync := newFixture(refireFixture())
+	sync.config.Watchers = []string{"abc123"}
+
+	if _, err := sync.Sync(); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if got := ts.watchers["PROJ-2"]; len(got) != 1 {
+		t.Errorf("Expected the follow-up ticket to be watched, got %v", got)
+	}
+	if got := ts.watchers["PROJ-1"]; len(got) != 0 {
+		t.Errorf("Expected the closed ticket to be left alone, got %v", got)
+	}
+}
+
+func TestWatchers_ErrorDoesNotFailReopen(t *testing.T) {
+	am := newMockAlertManager()
+	ts := newMockTicketSystem()
+	am.alerts = []*alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "DiskFull", "ticket": "PROJ-1"}},
+	}
+	ts.tickets["PROJ-1"] = &ticket.Ticket{Key: "PROJ-1", Status: ticket.StatusClosed}
+	ts.updateErr = errors.New("watchers disabled")
+
+	cfg := DefaultConfig()
+	cfg.Watchers = []string{"abc123"}
+	result, err := NewSynchronizer(am, ts, cfg).Sync()
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if result.TicketsReopened != 1 || result.SilencesCreated != 1 || len(result.Errors) != 0 {
+		t.Errorf("Expected the reopen to succeed despite the watcher error, got %+v", result)
+	}
+}
@@ -0,0 +1,157 @@
+// Package teams maps the teams owning alerts to their Jira project, Slack channel and
+// on-call schedule, so that per-team behavior is configured in one place.
+package teams
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultLabel is the alert label naming the owning team when the file sets none
+const DefaultLabel = "team"
+
+// Team is a team owning alerts and the silences muting them
+type Team struct {
+	Name string `json:"name"`
+	// LabelValues are the values of the team label that belong to the team, e.g. an old
+	// and a new team name; the name itself when empty
+	LabelValues    []string `json:"labelValues,omitempty"`
+	JiraProject    string   `json:"jiraProject,omitempty"`    // Project of the tickets created for the team
+	SlackChannel   string   `json:"slackChannel,omitempty"`   // Channel of the team, e.g. "#storage"
+	OnCallSchedule string   `json:"onCallSchedule,omitempty"` // PagerDuty schedule ID or Opsgenie schedule name
+}
+
+// Values returns the values of the team label that belong to the team
+func (t *Team) Values() []string {
+	if len(t.LabelValues) == 0 {
+		return []string{t.Name}
+	}
+	return t.LabelValues
+}
+
+// Directory looks up teams by the label values of alerts and silences and by their Slack
+// channel. A nil Directory has no teams.
+type Directory struct {
+	// Label is the alert label naming the owning team
+	Label string
+
+	teams     []*Team
+	byValue   map[string]*Team
+	byChannel map[string]*Team
+}
+
+// Load reads a team mapping file
+func Load(path string) (*Directory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team mapping: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses a team mapping from YAML:
+//
+//	label: team
+//	teams:
+//	  - name: storage
+//	    labelValues: [storage, ceph]
+//	    jiraProject: STOR
+//	    slackChannel: "#storage"
+//	    onCallSchedule: PABC123
+//
+// Team names and label values must be unique, and so must Slack channels.
+func Parse(data []byte) (*Directory, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse team mapping: %w", err)
+	}
+	var doc struct {
+		Label string  `json:"label"`
+		Teams []*Team `json:"teams"`
+	}
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse team mapping: %w", err)
+	}
+
+	d := &Directory{
+		Label:     doc.Label,
+		teams:     doc.Teams,
+		byValue:   make(map[string]*Team),
+		byChannel: make(map[string]*Team),
+	}
+	if d.Label == "" {
+		d.Label = DefaultLabel
+	}
+	names := make(map[string]bool, len(doc.Teams))
+	for i, team := range doc.Teams {
+		if team.Name == "" {
+			return nil, fmt.Errorf("team %d: name is required", i+1)
+		}
+		if names[team.Name] {
+			return nil, fmt.Errorf("team %s is defined twice", team.Name)
+		}
+		names[team.Name] = true
+		for _, value := range team.Values() {
+			if other, exists := d.byValue[value]; exists {
+				return nil, fmt.Errorf("label value %q belongs to both %s and %s", value, other.Name, team.Name)
+			}
+			d.byValue[value] = team
+		}
+		if team.SlackChannel != "" {
+			channel := channelName(team.SlackChannel)
+			if other, exists := d.byChannel[channel]; exists {
+				return nil, fmt.Errorf("channel %s belongs to both %s and %s", team.SlackChannel, other.Name, team.Name)
+			}
+			d.byChannel[channel] = team
+		}
+	}
+	return d, nil
+}
+
+// Teams returns the teams in the order of the file
+func (d *Directory) Teams() []*Team {
+	if d == nil {
+		return nil
+	}
+	return d.teams
+}
+
+// Lookup returns the team owning an alert or silence with the given labels, or nil
+func (d *Directory) Lookup(labels map[string]string) *Team {
+	if d == nil {
+		return nil
+	}
+	return d.byValue[labels[d.Label]]
+}
+
+// ForChannel returns the team of a Slack channel, given with or without "#", or nil
+func (d *Directory) ForChannel(channel string) *Team {
+	if d == nil || channel == "" {
+		return nil
+	}
+	return d.byChannel[channelName(channel)]
+}
+
+// Projects returns the Jira projects of the teams, sorted and without duplicates
+func (d *Directory) Projects() []string {
+	seen := make(map[string]bool)
+	var projects []string
+	for _, team := range d.Teams() {
+		if team.JiraProject != "" && !seen[team.JiraProject] {
+			seen[team.JiraProject] = true
+			projects = append(projects, team.JiraProject)
+		}
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// channelName returns a Slack channel name without the leading "#"
+func channelName(channel string) string {
+	return strings.TrimPrefix(channel, "#")
+}
@@ -0,0 +1,69 @@
+package teams
+
+import (
+	"strings"
+	"testing"
+)
+
+const testMapping = `
+label: owner
+teams:
+  - name: storage
+    labelValues: [storage, ceph]
+    jiraProject: STOR
+    slackChannel: "#storage"
+    onCallSchedule: PABC123
+  - name: payments
+    jiraProject: PAY
+  - name: network
+    jiraProject: STOR
+`
+
+func TestParse(t *testing.T) {
+	d, err := Parse([]byte(testMapping))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if d.Label != "owner" || len(d.Teams()) != 3 {
+		t.Fatalf("Unexpected directory: label %q with %d teams", d.Label, len(d.Teams()))
+	}
+
+	if team := d.Lookup(map[string]string{"owner": "ceph"}); team == nil || team.Name != "storage" || team.OnCallSchedule != "PABC123" {
+		t.Errorf("Expected ceph to belong to storage, got %+v", team)
+	}
+	if team := d.Lookup(map[string]string{"owner": "payments"}); team == nil || team.JiraProject != "PAY" {
+		t.Errorf("Expected the team name to be its label value, got %+v", team)
+	}
+	if team := d.Lookup(map[string]string{"team": "payments"}); team != nil {
+		t.Errorf("Expected only the configured label to be consulted, got %+v", team)
+	}
+	for _, channel := range []string{"storage", "#storage"} {
+		if team := d.ForChannel(channel); team == nil || team.Name != "storage" {
+			t.Errorf("Expected channel %s to belong to storage, got %+v", channel, team)
+		}
+	}
+	if projects := strings.Join(d.Projects(), ","); projects != "PAY,STOR" {
+		t.Errorf("Unexpected projects: %s", projects)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"no name":         "teams: [{jiraProject: OPS}]",
+		"duplicate team":  "teams: [{name: a}, {name: a}]",
+		"shared value":    "teams: [{name: a, labelValues: [x]}, {name: b, labelValues: [x]}]",
+		"shared channel":  "teams: [{name: a, slackChannel: '#ops'}, {name: b, slackChannel: ops}]",
+		"invalid mapping": "teams: storage",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDirectory_Nil(t *testing.T) {
+	var d *Directory
+	if d.Lookup(map[string]string{"team": "storage"}) != nil || d.ForChannel("storage") != nil || len(d.Projects()) != 0 {
+		t.Error("Expected a nil directory to have no teams")
+	}
+}
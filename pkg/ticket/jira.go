@@ -2,22 +2,60 @@ package ticket
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
 )
 
+// jiraDateFormat is the format of Jira date fields such as the due date
+const jiraDateFormat = "2006-01-02"
+
 // JiraTicketSystem implements the TicketSystem interface for Atlassian Jira
 type JiraTicketSystem struct {
 	baseURL          string
 	username         string
 	apiToken         string
+	credentials      CredentialSource
 	projectKey       string
 	httpClient       *http.Client
 	annotationPrefix string
+	customFields     []string
+	workflow         Workflow
+	silenceRefField  string
+	subTaskType      string
+	serviceDesk      ServiceDesk
+	slaFields        []string
+	currentUser      string // Comment author name of the API user, looked up once
+
+	// Rate limiting
+	rateLimit         RateLimit
+	rateLimitObserver RateLimitObserver
+	requestObserver   RequestObserver
+	requestCtx        context.Context
+	requests          int
+	budgetExhausted   bool
+	sleep             func(time.Duration)
+}
+
+// Workflow maps a custom Jira workflow onto ticket statuses and transitions. Statuses
+// and transitions that are not configured fall back to matching common Jira names.
+type Workflow struct {
+	// Statuses maps lowercase Jira status names to ticket statuses
+	Statuses map[string]TicketStatus
+	// ReopenTransition and CloseTransition are the names or IDs of the transitions used
+	// to reopen and close tickets
+	ReopenTransition string
+	CloseTransition  string
 }
 
 // NewJiraTicketSystem creates a new Jira ticket system client
@@ -35,7 +73,62 @@ func NewJiraTicketSystem(baseURL, username, apiToken, projectKey, annotationPref
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		subTaskType: "Sub-task",
+		rateLimit:   defaultRateLimit,
+		sleep:       time.Sleep,
+	}
+}
+
+// SetTLSConfig sets the TLS configuration used for HTTPS connections to Jira
+func (j *JiraTicketSystem) SetTLSConfig(tlsConfig *tls.Config) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	j.httpClient.Transport = transport
+}
+
+// CredentialSource supplies the Jira username and API token for each request, so that
+// credentials can be rotated while the process runs
+type CredentialSource interface {
+	Credentials() (username, apiToken string)
+}
+
+// SetCredentialSource makes requests use the credentials of source instead of the
+// username and API token given to NewJiraTicketSystem
+func (j *JiraTicketSystem) SetCredentialSource(source CredentialSource) {
+	j.credentials = source
+}
+
+// setAuth adds the current credentials to req
+func (j *JiraTicketSystem) setAuth(req *http.Request) {
+	username, apiToken := j.username, j.apiToken
+	if j.credentials != nil {
+		username, apiToken = j.credentials.Credentials()
 	}
+	req.SetBasicAuth(username, apiToken)
+}
+
+// SetWorkflow sets the status and transition mapping of the project's workflow
+func (j *JiraTicketSystem) SetWorkflow(workflow Workflow) {
+	j.workflow = workflow
+}
+
+// SetSilenceRefField stores the silence reference in the given custom field (e.g.
+// customfield_10070) instead of the first line of the description. Tickets whose field
+// is empty still fall back to a reference in the description.
+func (j *JiraTicketSystem) SetSilenceRefField(fieldID string) {
+	j.silenceRefField = fieldID
+}
+
+// SetSubTaskIssueType sets the issue type of the sub-tasks created for tickets with a
+// Parent, e.g. "Subtask" in team-managed projects. It defaults to "Sub-task".
+func (j *JiraTicketSystem) SetSubTaskIssueType(name string) {
+	j.subTaskType = name
+}
+
+// SetCustomFields sets the custom field IDs (e.g. customfield_10050) whose values are
+// exposed through Ticket.CustomFields
+func (j *JiraTicketSystem) SetCustomFields(fieldIDs []string) {
+	j.customFields = fieldIDs
 }
 
 // Jira API structures
@@ -53,24 +146,53 @@ type jiraFields struct {
 	Updated     string           `json:"updated,omitempty"`
 	Labels      []string         `json:"labels,omitempty"`
 	Assignee    *jiraUser        `json:"assignee,omitempty"`
+	Priority    *jiraPriority    `json:"priority,omitempty"`
+	DueDate     string           `json:"duedate,omitempty"`
 	Project     *jiraProject     `json:"project,omitempty"`
 	IssueType   *jiraIssueType   `json:"issuetype,omitempty"`
+	Parent      *jiraParent      `json:"parent,omitempty"`
+	// Custom holds custom field values, keyed by field ID; nil values clear the field
+	Custom map[string]interface{} `json:"-"`
 }
 
+// MarshalJSON encodes the standard fields together with the custom fields
+func (f jiraFields) MarshalJSON() ([]byte, error) {
+	type standardFields jiraFields
+	body, err := json.Marshal(standardFields(f))
+	if err != nil || len(f.Custom) == 0 {
+		return body, err
+	}
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	for id, value := range f.Custom {
+		fields[id] = value
+	}
+	return json.Marshal(fields)
+}
+
+// jiraDescription is an Atlassian Document Format (ADF) document
 type jiraDescription struct {
-	Type    string                   `json:"type"`
-	Version int                      `json:"version"`
-	Content []jiraDescriptionContent `json:"content"`
+	Type    string     `json:"type"`
+	Version int        `json:"version"`
+	Content []jiraNode `json:"content"`
 }
 
-type jiraDescriptionContent struct {
-	Type    string                     `json:"type"`
-	Content []jiraDescriptionParagraph `json:"content,omitempty"`
+// jiraNode is an ADF block or inline node
+type jiraNode struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []jiraNode             `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []jiraMark             `json:"marks,omitempty"`
 }
 
-type jiraDescriptionParagraph struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+// jiraMark formats an ADF text node, e.g. as a link
+type jiraMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
 }
 
 type jiraStatus struct {
@@ -86,12 +208,54 @@ type jiraProject struct {
 	Key string `json:"key"`
 }
 
+type jiraPriority struct {
+	Name string `json:"name"`
+}
+
 type jiraIssueType struct {
 	Name string `json:"name"`
 }
 
+type jiraParent struct {
+	Key string `json:"key"`
+}
+
 type jiraComment struct {
-	Body string `json:"body"`
+	ID      string           `json:"id,omitempty"`
+	Author  *jiraUser        `json:"author,omitempty"`
+	Body    *jiraDescription `json:"body,omitempty"`
+	Created string           `json:"created,omitempty"`
+}
+
+type jiraCommentsResponse struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Comments   []jiraComment `json:"comments"`
+}
+
+type jiraRemoteLink struct {
+	GlobalID string               `json:"globalId"`
+	Object   jiraRemoteLinkObject `json:"object"`
+}
+
+type jiraRemoteLinkObject struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+type jiraIssueLink struct {
+	Type         jiraIssueLinkType `json:"type"`
+	InwardIssue  jiraIssueRef      `json:"inwardIssue"`
+	OutwardIssue jiraIssueRef      `json:"outwardIssue"`
+}
+
+type jiraIssueLinkType struct {
+	Name string `json:"name"`
+}
+
+type jiraIssueRef struct {
+	Key string `json:"key"`
 }
 
 type jiraTransition struct {
@@ -114,37 +278,241 @@ func (j *JiraTicketSystem) GetTicket(key string) (*Ticket, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(j.username, j.apiToken)
+	j.setAuth(req)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := j.httpClient.Do(req)
+	resp, err := j.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ticket: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("ticket not found: %s", key)
+		return nil, apierror.Errorf(apierror.NotFound, "ticket not found: %s", key)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, apierror.FromStatus(resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return j.decodeTicket(body)
+}
+
+// searchPageSize is the number of issues requested per page of search results
+const searchPageSize = 100
+
+// bulkFetchSize is the number of keys looked up by a single search in GetTickets
+const bulkFetchSize = 50
+
+// GetTickets retrieves tickets by key through the search API, in batches of
+// bulkFetchSize keys. Jira rejects the query of a batch containing a key that does not
+// exist; the keys named in its error are left out of the result and the batch is
+// searched again without them.
+func (j *JiraTicketSystem) GetTickets(keys []string) (map[string]*Ticket, error) {
+	tickets := make(map[string]*Ticket, len(keys))
+	for start := 0; start < len(keys); start += bulkFetchSize {
+		found, err := j.searchKeys(keys[start:min(start+bulkFetchSize, len(keys))])
+		if err != nil {
+			return nil, err
+		}
+		for _, ticket := range found {
+			tickets[ticket.Key] = ticket
+		}
+	}
+	return tickets, nil
+}
+
+// unknownKeyPattern matches the keys named in Jira's errors for keys that do not exist,
+// e.g. "An issue with key 'PROJ-9' does not exist for field 'issuekey'." or "The issue
+// key 'PROJ' for field 'issuekey' is invalid."
+var unknownKeyPattern = regexp.MustCompile(`key '([^']+)'`)
+
+// searchKeys searches for a batch of keys, dropping the keys Jira reports as unknown
+func (j *JiraTicketSystem) searchKeys(batch []string) ([]*Ticket, error) {
+	for len(batch) > 0 {
+		quoted := make([]string, len(batch))
+		for i, key := range batch {
+			quoted[i] = fmt.Sprintf("%q", key)
+		}
+
+		found, err := j.SearchTickets(fmt.Sprintf("issuekey in (%s)", strings.Join(quoted, ", ")))
+		var apiErr *apierror.Error
+		if err == nil || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			return found, err
+		}
+
+		unknown := make(map[string]bool)
+		for _, match := range unknownKeyPattern.FindAllStringSubmatch(err.Error(), -1) {
+			unknown[match[1]] = true
+		}
+		remaining := make([]string, 0, len(batch))
+		for _, key := range batch {
+			if !unknown[key] {
+				remaining = append(remaining, key)
+			}
+		}
+		if len(remaining) == len(batch) {
+			return nil, err
+		}
+		batch = remaining
+	}
+	return nil, nil
+}
+
+// searchFields lists the standard fields requested for search results
+var searchFields = []string{"summary", "description", "status", "created", "updated", "labels", "assignee", "priority", "duedate", "parent"}
+
+// SearchTickets returns the tickets matching a JQL query, following all result pages
+func (j *JiraTicketSystem) SearchTickets(jql string) ([]*Ticket, error) {
+	fields := append([]string{}, searchFields...)
+	fields = append(fields, j.customFields...)
+	if j.silenceRefField != "" {
+		fields = append(fields, j.silenceRefField)
+	}
+	fields = append(fields, j.slaFields...)
+
+	var tickets []*Ticket
+	pageToken := ""
+	for {
+		search := map[string]interface{}{
+			"jql":        jql,
+			"fields":     fields,
+			"maxResults": searchPageSize,
+		}
+		if pageToken != "" {
+			search["nextPageToken"] = pageToken
+		}
+		body, err := json.Marshal(search)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal search: %w", err)
+		}
+
+		url := fmt.Sprintf("%s/rest/api/3/search/jql", j.baseURL)
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		j.setAuth(req)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := j.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search tickets: %w", err)
+		}
+
+		var page struct {
+			Issues        []json.RawMessage `json:"issues"`
+			NextPageToken string            `json:"nextPageToken"`
+			IsLast        bool              `json:"isLast"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			responseBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, apierror.FromStatus(resp.StatusCode, string(responseBody))
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for _, issue := range page.Issues {
+			ticket, err := j.decodeTicket(issue)
+			if err != nil {
+				return nil, err
+			}
+			tickets = append(tickets, ticket)
+		}
+
+		if page.IsLast || page.NextPageToken == "" {
+			return tickets, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// SilenceRefJQL returns a JQL query for the project's tickets that carry one of the given
+// labels or, if set, a value in the silence reference field
+func SilenceRefJQL(projectKey, silenceRefField string, labels []string) string {
+	var conditions []string
+	if len(labels) > 0 {
+		quoted := make([]string, len(labels))
+		for i, label := range labels {
+			quoted[i] = fmt.Sprintf("%q", label)
+		}
+		conditions = append(conditions, fmt.Sprintf("labels in (%s)", strings.Join(quoted, ", ")))
 	}
+	if id, ok := strings.CutPrefix(silenceRefField, "customfield_"); ok {
+		conditions = append(conditions, fmt.Sprintf("cf[%s] is not EMPTY", id))
+	} else if silenceRefField != "" {
+		conditions = append(conditions, fmt.Sprintf("%q is not EMPTY", silenceRefField))
+	}
+	return fmt.Sprintf("project = %q AND (%s)", projectKey, strings.Join(conditions, " OR "))
+}
 
+// decodeTicket converts a raw issue, as returned by the issue and search endpoints, into
+// a ticket including its configured custom fields
+func (j *JiraTicketSystem) decodeTicket(body []byte) (*Ticket, error) {
 	var ji jiraIssue
-	if err := json.NewDecoder(resp.Body).Decode(&ji); err != nil {
+	if err := json.Unmarshal(body, &ji); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return j.convertFromJiraIssue(&ji), nil
+	ticket := j.convertFromJiraIssue(&ji)
+	if len(j.customFields) > 0 {
+		customFields, err := j.extractCustomFields(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode custom fields: %w", err)
+		}
+		ticket.CustomFields = customFields
+	}
+	if j.silenceRefField != "" {
+		silenceRef, err := j.extractSilenceRefField(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode silence reference field: %w", err)
+		}
+		if silenceRef != "" {
+			ticket.SilenceRef = silenceRef
+		}
+	}
+	if len(j.slaFields) > 0 {
+		slas, err := j.extractSLAs(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode SLA fields: %w", err)
+		}
+		ticket.SLAs = slas
+	}
+
+	return ticket, nil
 }
 
 // CreateTicket creates a new ticket and returns its key
 func (j *JiraTicketSystem) CreateTicket(ticket *Ticket) (string, error) {
+	if j.raisesRequest(ticket) {
+		return j.createRequest(ticket)
+	}
+
 	ji := j.convertToJiraIssue(ticket)
 	ji.Fields.Project = &jiraProject{Key: j.projectKey}
+	if ticket.Project != "" {
+		ji.Fields.Project = &jiraProject{Key: ticket.Project}
+	}
 	ji.Fields.IssueType = &jiraIssueType{Name: "Task"}
+	if ticket.Parent != "" {
+		// A sub-task lives in the project of its parent
+		project, _, _ := strings.Cut(ticket.Parent, "-")
+		ji.Fields.Project = &jiraProject{Key: project}
+		ji.Fields.IssueType = &jiraIssueType{Name: j.subTaskType}
+		ji.Fields.Parent = &jiraParent{Key: ticket.Parent}
+	}
 
 	body, err := json.Marshal(ji)
 	if err != nil {
@@ -157,11 +525,11 @@ func (j *JiraTicketSystem) CreateTicket(ticket *Ticket) (string, error) {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(j.username, j.apiToken)
+	j.setAuth(req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := j.httpClient.Do(req)
+	resp, err := j.do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to create ticket: %w", err)
 	}
@@ -169,7 +537,7 @@ func (j *JiraTicketSystem) CreateTicket(ticket *Ticket) (string, error) {
 
 	if resp.StatusCode != http.StatusCreated {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(responseBody))
+		return "", apierror.FromStatus(resp.StatusCode, string(responseBody))
 	}
 
 	var result jiraIssue
@@ -180,9 +548,13 @@ func (j *JiraTicketSystem) CreateTicket(ticket *Ticket) (string, error) {
 	return result.Key, nil
 }
 
-// UpdateTicket updates an existing ticket
+// UpdateTicket updates an existing ticket. With a silence reference field the description
+// is left untouched, unless it still carries a reference that has moved to the field.
 func (j *JiraTicketSystem) UpdateTicket(ticket *Ticket) error {
 	ji := j.convertToJiraIssue(ticket)
+	if j.silenceRefField != "" && j.extractSilenceRef(ticket.Description) == "" {
+		ji.Fields.Description = nil
+	}
 
 	body, err := json.Marshal(ji)
 	if err != nil {
@@ -195,11 +567,11 @@ func (j *JiraTicketSystem) UpdateTicket(ticket *Ticket) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(j.username, j.apiToken)
+	j.setAuth(req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := j.httpClient.Do(req)
+	resp, err := j.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to update ticket: %w", err)
 	}
@@ -207,7 +579,288 @@ func (j *JiraTicketSystem) UpdateTicket(ticket *Ticket) error {
 
 	if resp.StatusCode != http.StatusNoContent {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(responseBody))
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
+
+// UpdateLabels adds and removes labels on a ticket without touching its other fields
+func (j *JiraTicketSystem) UpdateLabels(key string, add, remove []string) error {
+	ops := make([]map[string]string, 0, len(add)+len(remove))
+	for _, label := range add {
+		ops = append(ops, map[string]string{"add": label})
+	}
+	for _, label := range remove {
+		ops = append(ops, map[string]string{"remove": label})
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"update": map[string]interface{}{"labels": ops},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal label update: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s", j.baseURL, key)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update labels: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
+
+// SetPriority changes the priority of a ticket without touching its other fields
+func (j *JiraTicketSystem) SetPriority(key string, priority string) error {
+	return j.updateFields(key, map[string]interface{}{"priority": jiraPriority{Name: priority}})
+}
+
+// SetDueDate sets the due date of a ticket without touching its other fields. Jira due
+// dates have no time of day, so only the date of due in its own location is kept.
+func (j *JiraTicketSystem) SetDueDate(key string, due time.Time) error {
+	return j.updateFields(key, map[string]interface{}{"duedate": due.Format(jiraDateFormat)})
+}
+
+// AssignTicket assigns a ticket. A user containing "@" is treated as an email address
+// and resolved to the Jira account ID of the matching user.
+func (j *JiraTicketSystem) AssignTicket(key string, user string) error {
+	accountID := user
+	if strings.Contains(user, "@") {
+		var err error
+		accountID, err = j.findAccountID(user)
+		if err != nil {
+			return err
+		}
+	}
+	return j.updateFields(key, map[string]interface{}{"assignee": jiraUser{AccountID: accountID}})
+}
+
+// AddWatcher adds a user to the watchers of an issue
+func (j *JiraTicketSystem) AddWatcher(key string, user string) error {
+	accountID := user
+	if strings.Contains(user, "@") {
+		var err error
+		accountID, err = j.findAccountID(user)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The request body is the account ID as a JSON string
+	body, err := json.Marshal(accountID)
+	if err != nil {
+		return fmt.Errorf("failed to marshal watcher: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/watchers", j.baseURL, key)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to add watcher: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
+
+// findAccountID returns the account ID of the Jira user with the given email address
+func (j *JiraTicketSystem) findAccountID(email string) (string, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/3/user/search?query=%s", j.baseURL, url.QueryEscape(email))
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to search users: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", apierror.FromStatus(resp.StatusCode, string(body))
+	}
+
+	var users []struct {
+		AccountID    string `json:"accountId"`
+		EmailAddress string `json:"emailAddress"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return "", fmt.Errorf("failed to decode users: %w", err)
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.EmailAddress, email) {
+			return u.AccountID, nil
+		}
+	}
+	// Email addresses may be hidden by the user's profile visibility; accept a single result
+	if len(users) == 1 {
+		return users[0].AccountID, nil
+	}
+	return "", fmt.Errorf("no Jira user found for %s", email)
+}
+
+// SetRemoteLink adds a remote issue link, replacing any link with the same GlobalID
+func (j *JiraTicketSystem) SetRemoteLink(key string, link RemoteLink) error {
+	body, err := json.Marshal(jiraRemoteLink{
+		GlobalID: link.GlobalID,
+		Object: jiraRemoteLinkObject{
+			URL:   link.URL,
+			Title: link.Title,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal remote link: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/remotelink", j.baseURL, key)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to set remote link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Jira returns 201 for a new link and 200 when a link with the same GlobalID was updated
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
+
+// DeleteRemoteLink removes the remote issue link with the given GlobalID
+func (j *JiraTicketSystem) DeleteRemoteLink(key string, globalID string) error {
+	endpoint := fmt.Sprintf("%s/rest/api/3/issue/%s/remotelink?globalId=%s", j.baseURL, key, url.QueryEscape(globalID))
+	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete remote link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
+
+// LinkTickets creates an issue link of the given type. Jira reads a link as
+// "<inwardIssue> <outward description> <outwardIssue>", so key is sent as the inward issue.
+func (j *JiraTicketSystem) LinkTickets(key, linkedKey, linkType string) error {
+	body, err := json.Marshal(jiraIssueLink{
+		Type:         jiraIssueLinkType{Name: linkType},
+		InwardIssue:  jiraIssueRef{Key: key},
+		OutwardIssue: jiraIssueRef{Key: linkedKey},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue link: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/3/issueLink", j.baseURL)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to link tickets: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
+
+// updateFields sets the given fields on a ticket
+func (j *JiraTicketSystem) updateFields(key string, fields map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"fields": fields})
+	if err != nil {
+		return fmt.Errorf("failed to marshal field update: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s", j.baseURL, key)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update ticket fields: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
 	}
 
 	return nil
@@ -228,16 +881,8 @@ func (j *JiraTicketSystem) ReopenTicket(key string, comment string) error {
 		return fmt.Errorf("failed to get transitions: %w", err)
 	}
 
-	// Find "Reopen" or similar transition
-	var transitionID string
-	for _, t := range transitions {
-		if strings.EqualFold(t.Name, "reopen") || strings.EqualFold(t.To.Name, "open") ||
-			strings.EqualFold(t.To.Name, "reopened") || strings.EqualFold(t.To.Name, "to do") {
-			transitionID = t.ID
-			break
-		}
-	}
-
+	// Find the configured transition, or "Reopen" or similar
+	transitionID := findTransition(transitions, j.workflow.ReopenTransition, isReopenTransition)
 	if transitionID == "" {
 		return fmt.Errorf("no reopen transition found for ticket %s", key)
 	}
@@ -260,16 +905,8 @@ func (j *JiraTicketSystem) CloseTicket(key string, comment string) error {
 		return fmt.Errorf("failed to get transitions: %w", err)
 	}
 
-	// Find "Close" or "Done" transition
-	var transitionID string
-	for _, t := range transitions {
-		if strings.EqualFold(t.Name, "close") || strings.EqualFold(t.Name, "done") ||
-			strings.EqualFold(t.To.Name, "closed") || strings.EqualFold(t.To.Name, "done") {
-			transitionID = t.ID
-			break
-		}
-	}
-
+	// Find the configured transition, or "Close" or "Done"
+	transitionID := findTransition(transitions, j.workflow.CloseTransition, isCloseTransition)
 	if transitionID == "" {
 		return fmt.Errorf("no close transition found for ticket %s", key)
 	}
@@ -280,21 +917,7 @@ func (j *JiraTicketSystem) CloseTicket(key string, comment string) error {
 // AddComment adds a comment to a ticket
 func (j *JiraTicketSystem) AddComment(key string, comment string) error {
 	commentBody := map[string]interface{}{
-		"body": map[string]interface{}{
-			"type":    "doc",
-			"version": 1,
-			"content": []map[string]interface{}{
-				{
-					"type": "paragraph",
-					"content": []map[string]interface{}{
-						{
-							"type": "text",
-							"text": comment,
-						},
-					},
-				},
-			},
-		},
+		"body": j.createJiraComment(comment),
 	}
 
 	body, err := json.Marshal(commentBody)
@@ -308,11 +931,11 @@ func (j *JiraTicketSystem) AddComment(key string, comment string) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(j.username, j.apiToken)
+	j.setAuth(req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := j.httpClient.Do(req)
+	resp, err := j.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to add comment: %w", err)
 	}
@@ -320,12 +943,58 @@ func (j *JiraTicketSystem) AddComment(key string, comment string) error {
 
 	if resp.StatusCode != http.StatusCreated {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(responseBody))
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
 	}
 
 	return nil
 }
 
+// GetComments returns all comments on a ticket, oldest first
+func (j *JiraTicketSystem) GetComments(key string) ([]*Comment, error) {
+	comments := make([]*Comment, 0)
+	startAt := 0
+
+	for {
+		url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment?orderBy=created&startAt=%d", j.baseURL, key, startAt)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		j.setAuth(req)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := j.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comments: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, apierror.FromStatus(resp.StatusCode, string(body))
+		}
+
+		var page jiraCommentsResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for i := range page.Comments {
+			comments = append(comments, j.convertFromJiraComment(&page.Comments[i]))
+		}
+
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
 // IsResolved checks if a ticket is in a resolved state
 func (j *JiraTicketSystem) IsResolved(ticket *Ticket) bool {
 	return ticket.Status == StatusResolved
@@ -341,6 +1010,33 @@ func (j *JiraTicketSystem) IsOpen(ticket *Ticket) bool {
 	return ticket.Status == StatusOpen || ticket.Status == StatusInProgress
 }
 
+// findTransition returns the ID of the transition whose ID or name is configured, or of
+// the first transition matching fallback if none is configured. It returns an empty
+// string if no transition is available.
+func findTransition(transitions []jiraTransition, configured string, fallback func(jiraTransition) bool) string {
+	for _, t := range transitions {
+		if configured != "" && (t.ID == configured || strings.EqualFold(t.Name, configured)) {
+			return t.ID
+		}
+		if configured == "" && fallback(t) {
+			return t.ID
+		}
+	}
+	return ""
+}
+
+// isReopenTransition recognises the reopen transition of common workflows
+func isReopenTransition(t jiraTransition) bool {
+	return strings.EqualFold(t.Name, "reopen") || strings.EqualFold(t.To.Name, "open") ||
+		strings.EqualFold(t.To.Name, "reopened") || strings.EqualFold(t.To.Name, "to do")
+}
+
+// isCloseTransition recognises the close transition of common workflows
+func isCloseTransition(t jiraTransition) bool {
+	return strings.EqualFold(t.Name, "close") || strings.EqualFold(t.Name, "done") ||
+		strings.EqualFold(t.To.Name, "closed") || strings.EqualFold(t.To.Name, "done")
+}
+
 // Helper functions
 func (j *JiraTicketSystem) getTransitions(key string) ([]jiraTransition, error) {
 	url := fmt.Sprintf("%s/rest/api/3/issue/%s/transitions", j.baseURL, key)
@@ -349,10 +1045,10 @@ func (j *JiraTicketSystem) getTransitions(key string) ([]jiraTransition, error)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(j.username, j.apiToken)
+	j.setAuth(req)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := j.httpClient.Do(req)
+	resp, err := j.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transitions: %w", err)
 	}
@@ -360,7 +1056,7 @@ func (j *JiraTicketSystem) getTransitions(key string) ([]jiraTransition, error)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, apierror.FromStatus(resp.StatusCode, string(body))
 	}
 
 	var result jiraTransitionsResponse
@@ -389,11 +1085,11 @@ func (j *JiraTicketSystem) doTransition(key string, transitionID string) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(j.username, j.apiToken)
+	j.setAuth(req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := j.httpClient.Do(req)
+	resp, err := j.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to do transition: %w", err)
 	}
@@ -401,7 +1097,7 @@ func (j *JiraTicketSystem) doTransition(key string, transitionID string) error {
 
 	if resp.StatusCode != http.StatusNoContent {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(responseBody))
+		return apierror.FromStatus(resp.StatusCode, string(responseBody))
 	}
 
 	return nil
@@ -435,6 +1131,20 @@ func (j *JiraTicketSystem) convertFromJiraIssue(ji *jiraIssue) *Ticket {
 		}
 	}
 
+	if ji.Fields.Priority != nil {
+		ticket.Priority = ji.Fields.Priority.Name
+	}
+
+	if ji.Fields.Parent != nil {
+		ticket.Parent = ji.Fields.Parent.Key
+	}
+
+	if ji.Fields.DueDate != "" {
+		if t, err := time.Parse(jiraDateFormat, ji.Fields.DueDate); err == nil {
+			ticket.DueDate = t
+		}
+	}
+
 	if ji.Fields.Created != "" {
 		if t, err := time.Parse(time.RFC3339, ji.Fields.Created); err == nil {
 			ticket.CreatedAt = t
@@ -450,6 +1160,31 @@ func (j *JiraTicketSystem) convertFromJiraIssue(ji *jiraIssue) *Ticket {
 	return ticket
 }
 
+func (j *JiraTicketSystem) convertFromJiraComment(jc *jiraComment) *Comment {
+	comment := &Comment{
+		ID: jc.ID,
+	}
+
+	if jc.Author != nil {
+		comment.Author = jc.Author.Name
+		if comment.Author == "" {
+			comment.Author = jc.Author.AccountID
+		}
+	}
+
+	if jc.Body != nil {
+		comment.Body = j.extractDescriptionText(jc.Body)
+	}
+
+	if jc.Created != "" {
+		if t, err := time.Parse(time.RFC3339, jc.Created); err == nil {
+			comment.CreatedAt = t
+		}
+	}
+
+	return comment
+}
+
 func (j *JiraTicketSystem) convertToJiraIssue(ticket *Ticket) *jiraIssue {
 	ji := &jiraIssue{
 		Fields: jiraFields{
@@ -458,9 +1193,24 @@ func (j *JiraTicketSystem) convertToJiraIssue(ticket *Ticket) *jiraIssue {
 		},
 	}
 
-	// Embed silence reference in description if present
-	description := ticket.Description
-	if ticket.SilenceRef != "" {
+	if ticket.Priority != "" {
+		ji.Fields.Priority = &jiraPriority{Name: ticket.Priority}
+	}
+
+	if !ticket.DueDate.IsZero() {
+		ji.Fields.DueDate = ticket.DueDate.Format(jiraDateFormat)
+	}
+
+	// Store the silence reference in its field, or embed it in the description, replacing
+	// any previous reference
+	description := j.stripSilenceRef(ticket.Description)
+	if j.silenceRefField != "" {
+		var silenceRef interface{}
+		if ticket.SilenceRef != "" {
+			silenceRef = ticket.SilenceRef
+		}
+		ji.Fields.Custom = map[string]interface{}{j.silenceRefField: silenceRef}
+	} else if ticket.SilenceRef != "" {
 		description = fmt.Sprintf("%s: %s\n\n%s", j.annotationPrefix, ticket.SilenceRef, description)
 	}
 
@@ -469,41 +1219,27 @@ func (j *JiraTicketSystem) convertToJiraIssue(ticket *Ticket) *jiraIssue {
 	return ji
 }
 
+// extractDescriptionText renders an ADF document as the markup understood by
+// createJiraDescription
 func (j *JiraTicketSystem) extractDescriptionText(desc *jiraDescription) string {
-	var text strings.Builder
-	for _, content := range desc.Content {
-		for _, para := range content.Content {
-			if para.Text != "" {
-				if text.Len() > 0 {
-					text.WriteString("\n")
-				}
-				text.WriteString(para.Text)
-			}
-		}
-	}
-	return text.String()
+	return strings.Join(renderBlocks(desc.Content), "\n")
 }
 
+// createJiraDescription converts a description to ADF (see markupToADF)
 func (j *JiraTicketSystem) createJiraDescription(text string) *jiraDescription {
-	return &jiraDescription{
-		Type:    "doc",
-		Version: 1,
-		Content: []jiraDescriptionContent{
-			{
-				Type: "paragraph",
-				Content: []jiraDescriptionParagraph{
-					{
-						Type: "text",
-						Text: text,
-					},
-				},
-			},
-		},
-	}
+	return markupToADF(text)
+}
+
+// createJiraComment converts a comment to ADF (see markupToADF)
+func (j *JiraTicketSystem) createJiraComment(text string) *jiraDescription {
+	return markupToADF(text)
 }
 
 func (j *JiraTicketSystem) mapJiraStatus(status string) TicketStatus {
 	status = strings.ToLower(status)
+	if mapped, ok := j.workflow.Statuses[status]; ok {
+		return mapped
+	}
 	switch {
 	case strings.Contains(status, "open"), strings.Contains(status, "to do"):
 		return StatusOpen
@@ -520,6 +1256,77 @@ func (j *JiraTicketSystem) mapJiraStatus(status string) TicketStatus {
 	}
 }
 
+// extractCustomFields extracts the configured custom field values from a raw issue response
+func (j *JiraTicketSystem) extractCustomFields(body []byte) (map[string]string, error) {
+	var raw struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, id := range j.customFields {
+		if value := customFieldValue(raw.Fields[id]); value != "" {
+			values[id] = value
+		}
+	}
+	return values, nil
+}
+
+// extractSilenceRefField extracts the silence reference field from a raw issue response
+func (j *JiraTicketSystem) extractSilenceRefField(body []byte) (string, error) {
+	var raw struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return "", err
+	}
+	return customFieldValue(raw.Fields[j.silenceRefField]), nil
+}
+
+// customFieldValue renders a custom field value as a string. Text and number fields are
+// returned as-is, select fields return their selected option value.
+func customFieldValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(raw, &num); err == nil {
+		return num.String()
+	}
+
+	var option struct {
+		Value string `json:"value"`
+		Name  string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &option); err == nil {
+		if option.Value != "" {
+			return option.Value
+		}
+		if option.Name != "" {
+			return option.Name
+		}
+	}
+
+	return string(raw)
+}
+
+// stripSilenceRef removes the silence reference line from the start of a description
+func (j *JiraTicketSystem) stripSilenceRef(description string) string {
+	if j.extractSilenceRef(description) == "" {
+		return description
+	}
+	_, rest, _ := strings.Cut(description, "\n")
+	return strings.TrimLeft(rest, "\n")
+}
+
 // extractSilenceRef extracts the silence reference from a description
 func (j *JiraTicketSystem) extractSilenceRef(description string) string {
 	// Look for pattern "prefix: silence-id"
@@ -0,0 +1,333 @@
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// headingPattern matches "# Heading" to "###### Heading" lines
+	headingPattern = regexp.MustCompile(`^(#{1,6}) (.*)$`)
+	// listItemPattern matches "- item", "* item" and "1. item" lines
+	listItemPattern = regexp.MustCompile(`^\s*([-*]|\d+\.) (.*)$`)
+	// tableSeparatorPattern matches the cells of the "| --- | --- |" line below a header row
+	tableSeparatorPattern = regexp.MustCompile(`^:?-{3,}:?$`)
+	// linkPattern matches [text](url) links and bare URLs
+	linkPattern = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)|https?://[^\s<>()\[\]]+`)
+)
+
+// markupToADF converts text with lightweight markup to an ADF document:
+//
+//   - blocks fenced by ``` lines become code blocks, with the language taken from the
+//     opening fence
+//   - "# " to "###### " lines become headings
+//   - consecutive "- ", "* " or "1. " lines become bullet or ordered lists
+//   - consecutive "| a | b |" lines become a table, whose first row is a header row when
+//     followed by a "| --- | --- |" line
+//   - other lines become paragraphs, with line breaks and blank lines between their
+//     lines kept as hard breaks
+//
+// [text](url) links and bare URLs become links anywhere outside code blocks.
+func markupToADF(text string) *jiraDescription {
+	doc := &jiraDescription{Type: "doc", Version: 1}
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "```"):
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(lines[i], "```"); i++ {
+				code = append(code, lines[i])
+			}
+			i++
+			doc.Content = append(doc.Content, codeBlockNode(strings.TrimSpace(strings.TrimPrefix(line, "```")), code))
+
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			doc.Content = append(doc.Content, jiraNode{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": len(m[1])},
+				Content: inlineNodes(m[2]),
+			})
+			i++
+
+		case listItemPattern.MatchString(line):
+			ordered := isOrderedItem(line)
+			list := jiraNode{Type: "bulletList"}
+			if ordered {
+				list.Type = "orderedList"
+			}
+			for ; i < len(lines) && listItemPattern.MatchString(lines[i]) && isOrderedItem(lines[i]) == ordered; i++ {
+				m := listItemPattern.FindStringSubmatch(lines[i])
+				list.Content = append(list.Content, jiraNode{
+					Type:    "listItem",
+					Content: []jiraNode{{Type: "paragraph", Content: inlineNodes(m[2])}},
+				})
+			}
+			doc.Content = append(doc.Content, list)
+
+		case isTableRow(line):
+			var rows []string
+			for ; i < len(lines) && isTableRow(lines[i]); i++ {
+				rows = append(rows, lines[i])
+			}
+			doc.Content = append(doc.Content, tableNode(rows))
+
+		default:
+			var para []jiraNode
+			breaks := 0
+			for ; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) == "" {
+					breaks++
+					continue
+				}
+				if !startsParagraphLine(lines[i]) {
+					break
+				}
+				for ; len(para) > 0 && breaks > 0; breaks-- {
+					para = append(para, jiraNode{Type: "hardBreak"})
+				}
+				breaks = 1
+				para = append(para, inlineNodes(lines[i])...)
+			}
+			doc.Content = append(doc.Content, jiraNode{Type: "paragraph", Content: para})
+		}
+	}
+
+	// ADF documents must not be empty
+	if len(doc.Content) == 0 {
+		doc.Content = []jiraNode{{Type: "paragraph"}}
+	}
+	return doc
+}
+
+// startsParagraphLine reports whether a non-blank line continues a paragraph rather than
+// starting another block
+func startsParagraphLine(line string) bool {
+	return !strings.HasPrefix(line, "```") &&
+		!headingPattern.MatchString(line) &&
+		!listItemPattern.MatchString(line) &&
+		!isTableRow(line)
+}
+
+func isOrderedItem(line string) bool {
+	m := listItemPattern.FindStringSubmatch(line)
+	return m != nil && m[1] != "-" && m[1] != "*"
+}
+
+func isTableRow(line string) bool {
+	line = strings.TrimSpace(line)
+	return len(line) > 1 && strings.HasPrefix(line, "|") && strings.HasSuffix(line, "|")
+}
+
+// tableCells splits a "| a | b |" line into its cells
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	cells := strings.Split(row[1:len(row)-1], "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+func isTableSeparator(row string) bool {
+	for _, cell := range tableCells(row) {
+		if !tableSeparatorPattern.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+func tableNode(rows []string) jiraNode {
+	table := jiraNode{Type: "table"}
+	header := len(rows) > 1 && isTableSeparator(rows[1])
+	for i, row := range rows {
+		if header && i == 1 {
+			continue
+		}
+		cellType := "tableCell"
+		if header && i == 0 {
+			cellType = "tableHeader"
+		}
+		tableRow := jiraNode{Type: "tableRow"}
+		for _, cell := range tableCells(row) {
+			tableRow.Content = append(tableRow.Content, jiraNode{
+				Type:    cellType,
+				Content: []jiraNode{{Type: "paragraph", Content: inlineNodes(cell)}},
+			})
+		}
+		table.Content = append(table.Content, tableRow)
+	}
+	return table
+}
+
+func codeBlockNode(language string, lines []string) jiraNode {
+	node := jiraNode{Type: "codeBlock"}
+	if language != "" {
+		node.Attrs = map[string]interface{}{"language": language}
+	}
+	if code := strings.Trim(strings.Join(lines, "\n"), "\n"); code != "" {
+		node.Content = []jiraNode{{Type: "text", Text: code}}
+	}
+	return node
+}
+
+// inlineNodes converts a line to text nodes, turning links into text with a link mark
+func inlineNodes(text string) []jiraNode {
+	var nodes []jiraNode
+	addText := func(s string, href string) {
+		if s == "" {
+			return
+		}
+		node := jiraNode{Type: "text", Text: s}
+		if href != "" {
+			node.Marks = []jiraMark{{Type: "link", Attrs: map[string]interface{}{"href": href}}}
+		}
+		nodes = append(nodes, node)
+	}
+
+	for {
+		loc := linkPattern.FindStringSubmatchIndex(text)
+		if loc == nil {
+			addText(text, "")
+			return nodes
+		}
+		match := text[loc[0]:loc[1]]
+		label, href := match, match
+		if loc[2] >= 0 {
+			label, href = text[loc[2]:loc[3]], text[loc[4]:loc[5]]
+		} else {
+			// Punctuation ending a sentence is not part of a bare URL
+			trimmed := strings.TrimRight(match, ".,;:!?'\"")
+			loc[1] -= len(match) - len(trimmed)
+			label, href = trimmed, trimmed
+		}
+		addText(text[:loc[0]], "")
+		addText(label, href)
+		text = text[loc[1]:]
+	}
+}
+
+// renderBlocks renders ADF block nodes as markup lines, the reverse of markupToADF.
+// Nodes without a markup equivalent, such as panels, are rendered through their content.
+func renderBlocks(nodes []jiraNode) []string {
+	var lines []string
+	for _, node := range nodes {
+		switch node.Type {
+		case "paragraph":
+			if text := renderInline(node.Content); text != "" {
+				lines = append(lines, strings.Split(text, "\n")...)
+			}
+		case "heading":
+			level := max(min(attrInt(node.Attrs, "level"), 6), 1)
+			lines = append(lines, strings.Repeat("#", level)+" "+renderInline(node.Content))
+		case "codeBlock":
+			lines = append(lines, "```"+attrString(node.Attrs, "language"))
+			if text := renderInline(node.Content); text != "" {
+				lines = append(lines, strings.Split(text, "\n")...)
+			}
+			lines = append(lines, "```")
+		case "bulletList", "orderedList":
+			for i, item := range node.Content {
+				marker := "- "
+				if node.Type == "orderedList" {
+					marker = strconv.Itoa(i+max(attrInt(node.Attrs, "order"), 1)) + ". "
+				}
+				for j, line := range renderBlocks(item.Content) {
+					if j == 0 {
+						line = marker + line
+					} else {
+						line = strings.Repeat(" ", len(marker)) + line
+					}
+					lines = append(lines, line)
+				}
+			}
+		case "blockquote":
+			for _, line := range renderBlocks(node.Content) {
+				lines = append(lines, "> "+line)
+			}
+		case "table":
+			lines = append(lines, renderTable(node)...)
+		case "rule":
+			lines = append(lines, "---")
+		case "text", "hardBreak", "mention", "emoji", "inlineCard", "date":
+			if text := renderInline([]jiraNode{node}); text != "" {
+				lines = append(lines, strings.Split(text, "\n")...)
+			}
+		default:
+			lines = append(lines, renderBlocks(node.Content)...)
+		}
+	}
+	return lines
+}
+
+func renderTable(table jiraNode) []string {
+	var lines []string
+	for i, row := range table.Content {
+		cells := make([]string, 0, len(row.Content))
+		header := true
+		for _, cell := range row.Content {
+			cells = append(cells, strings.Join(renderBlocks(cell.Content), " "))
+			header = header && cell.Type == "tableHeader"
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 && header && len(cells) > 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+	return lines
+}
+
+// renderInline renders ADF inline nodes as text, with links in [text](url) form unless
+// the text is the URL itself
+func renderInline(nodes []jiraNode) string {
+	var text strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			href := ""
+			for _, mark := range node.Marks {
+				if mark.Type == "link" {
+					href = attrString(mark.Attrs, "href")
+				}
+			}
+			if href != "" && href != node.Text {
+				fmt.Fprintf(&text, "[%s](%s)", node.Text, href)
+			} else {
+				text.WriteString(node.Text)
+			}
+		case "hardBreak":
+			text.WriteString("\n")
+		case "mention", "emoji":
+			text.WriteString(attrString(node.Attrs, "text"))
+		case "inlineCard":
+			text.WriteString(attrString(node.Attrs, "url"))
+		default:
+			text.WriteString(node.Text)
+			text.WriteString(renderInline(node.Content))
+		}
+	}
+	return text.String()
+}
+
+func attrString(attrs map[string]interface{}, key string) string {
+	s, _ := attrs[key].(string)
+	return s
+}
+
+// attrInt returns a numeric attribute, which is a float64 in decoded documents
+func attrInt(attrs map[string]interface{}, key string) int {
+	switch v := attrs[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
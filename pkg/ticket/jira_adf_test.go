@@ -0,0 +1,77 @@
+package ticket
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMarkupToADF_Blocks(t *testing.T) {
+	text := "### Alert labels\n| Label | Value |\n| --- | --- |\n| alertname | DiskFull |\n- first\n- second\nSee [silence](https://am.example.com/#/silences/1).\n```json\n{\"id\": \"1\"}\n```"
+
+	doc := markupToADF(text)
+	var types []string
+	for _, node := range doc.Content {
+		types = append(types, node.Type)
+	}
+	want := []string{"heading", "table", "bulletList", "paragraph", "codeBlock"}
+	if len(types) != len(want) {
+		t.Fatalf("Expected blocks %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("Expected blocks %v, got %v", want, types)
+		}
+	}
+
+	if level := attrInt(doc.Content[0].Attrs, "level"); level != 3 {
+		t.Errorf("Expected heading level 3, got %d", level)
+	}
+	table := doc.Content[1]
+	if len(table.Content) != 2 || table.Content[0].Content[0].Type != "tableHeader" || table.Content[1].Content[1].Type != "tableCell" {
+		t.Errorf("Expected a header row and a data row, got %+v", table.Content)
+	}
+	link := doc.Content[3].Content[1]
+	if link.Text != "silence" || len(link.Marks) != 1 || attrString(link.Marks[0].Attrs, "href") != "https://am.example.com/#/silences/1" {
+		t.Errorf("Expected a link to the silence, got %+v", link)
+	}
+	if code := doc.Content[4]; attrString(code.Attrs, "language") != "json" || code.Content[0].Text != `{"id": "1"}` {
+		t.Errorf("Unexpected code block: %+v", code)
+	}
+
+	// Text renders back to the same markup, without the blank lines between blocks
+	if got := renderBlocks(doc.Content); strings.Join(got, "\n") != text {
+		t.Errorf("Expected round trip to %q, got %q", text, strings.Join(got, "\n"))
+	}
+}
+
+func TestMarkupToADF_Empty(t *testing.T) {
+	doc := markupToADF("")
+	if len(doc.Content) != 1 || doc.Content[0].Type != "paragraph" {
+		t.Errorf("Expected a single empty paragraph, got %+v", doc.Content)
+	}
+}
+
+func TestRenderBlocks_NestedNodes(t *testing.T) {
+	// Document as written in the Jira editor
+	body := `{"type":"doc","version":1,"content":[
+		{"type":"paragraph","content":[{"type":"text","text":"silence-matchers:"}]},
+		{"type":"codeBlock","content":[{"type":"text","text":"alertname=DiskFull\ninstance=db-1"}]},
+		{"type":"bulletList","content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"Owner: "},{"type":"mention","attrs":{"id":"1","text":"@alice"}}]}]},
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"Runbook"}]},
+				{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"https://wiki","marks":[{"type":"link","attrs":{"href":"https://wiki"}}]}]}]}]}]}
+		]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Ends ","marks":[{"type":"strong"}]},{"type":"date","attrs":{"timestamp":"0"}}]}]}
+	]}`
+
+	var doc jiraDescription
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+
+	want := "silence-matchers:\n```\nalertname=DiskFull\ninstance=db-1\n```\n- Owner: @alice\n- Runbook\n  - https://wiki\nEnds "
+	if got := strings.Join(renderBlocks(doc.Content), "\n"); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
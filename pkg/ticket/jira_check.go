@@ -0,0 +1,217 @@
+package ticket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// jiraIssueTypeStatuses lists the workflow statuses of an issue type in a project
+type jiraIssueTypeStatuses struct {
+	Name     string       `json:"name"`
+	Statuses []jiraStatus `json:"statuses"`
+}
+
+// CheckAuth verifies the credentials and returns the display name of the authenticated user
+func (j *JiraTicketSystem) CheckAuth() (string, error) {
+	var user struct {
+		DisplayName  string `json:"displayName"`
+		EmailAddress string `json:"emailAddress"`
+	}
+	if err := j.getJSON("/rest/api/3/myself", &user); err != nil {
+		return "", err
+	}
+	if user.EmailAddress != "" {
+		return fmt.Sprintf("%s <%s>", user.DisplayName, user.EmailAddress), nil
+	}
+	return user.DisplayName, nil
+}
+
+// CurrentUser returns the API user as comment authors are reported: the user name on
+// Jira Server, the account ID on Jira Cloud
+func (j *JiraTicketSystem) CurrentUser() (string, error) {
+	if j.currentUser != "" {
+		return j.currentUser, nil
+	}
+	var user jiraUser
+	if err := j.getJSON("/rest/api/3/myself", &user); err != nil {
+		return "", err
+	}
+	j.currentUser = user.Name
+	if j.currentUser == "" {
+		j.currentUser = user.AccountID
+	}
+	if j.currentUser == "" {
+		return "", fmt.Errorf("Jira did not report the account ID of the API user")
+	}
+	return j.currentUser, nil
+}
+
+// CheckProject verifies that the configured project exists and is visible to the user
+func (j *JiraTicketSystem) CheckProject() (string, error) {
+	var project struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	}
+	if err := j.getJSON(fmt.Sprintf("/rest/api/3/project/%s", j.projectKey), &project); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s (%s)", project.Key, project.Name), nil
+}
+
+// CheckTransitions verifies that ReopenTicket and CloseTicket will find a transition,
+// skipping whichever is not needed. The project's Task workflow, used for created tickets,
+// must have a status to transition to, either by its common name or through the
+// configured status mapping. The transition is then looked up, as ReopenTicket and
+// CloseTicket do, on the most recently updated closed or open ticket of the project, so
+// that a missing or misnamed transition is reported before a run fails on it. It
+// returns the transitions found.
+func (j *JiraTicketSystem) CheckTransitions(needReopen, needClose bool) (string, error) {
+	if err := j.checkWorkflowStatuses(needReopen, needClose); err != nil {
+		return "", err
+	}
+
+	var found []string
+	if needReopen {
+		detail, err := j.checkSampleTransition("reopen", "statusCategory = Done", j.workflow.ReopenTransition, isReopenTransition)
+		if err != nil {
+			return "", err
+		}
+		found = append(found, detail)
+	}
+	if needClose {
+		detail, err := j.checkSampleTransition("close", "statusCategory != Done", j.workflow.CloseTransition, isCloseTransition)
+		if err != nil {
+			return "", err
+		}
+		found = append(found, detail)
+	}
+	if len(found) == 0 {
+		return "no transitions needed", nil
+	}
+	return strings.Join(found, "; "), nil
+}
+
+// checkWorkflowStatuses verifies that the Task workflow has statuses to reopen and close
+// tickets in
+func (j *JiraTicketSystem) checkWorkflowStatuses(needReopen, needClose bool) error {
+	var issueTypes []jiraIssueTypeStatuses
+	if err := j.getJSON(fmt.Sprintf("/rest/api/3/project/%s/statuses", j.projectKey), &issueTypes); err != nil {
+		return err
+	}
+
+	for _, it := range issueTypes {
+		if !strings.EqualFold(it.Name, "Task") {
+			continue
+		}
+		var canReopen, canClose bool
+		for _, status := range it.Statuses {
+			name := strings.ToLower(status.Name)
+			switch name {
+			case "open", "reopened", "to do":
+				canReopen = true
+			case "closed", "done":
+				canClose = true
+			}
+			switch j.workflow.Statuses[name] {
+			case StatusOpen, StatusReopened:
+				canReopen = true
+			case StatusClosed, StatusResolved:
+				canClose = true
+			}
+		}
+		switch {
+		case needReopen && !canReopen:
+			return fmt.Errorf("task workflow has no Open, Reopened or To Do status to reopen tickets")
+		case needClose && !canClose:
+			return fmt.Errorf("task workflow has no Closed or Done status to close tickets")
+		}
+		return nil
+	}
+	return fmt.Errorf("issue type Task, used for created tickets, is not available in project %s", j.projectKey)
+}
+
+// checkSampleTransition looks up the transition for an action on the most recently
+// updated ticket matching the JQL condition. It passes without a ticket to check.
+func (j *JiraTicketSystem) checkSampleTransition(action, condition, configured string, fallback func(jiraTransition) bool) (string, error) {
+	var result struct {
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Status jiraStatus `json:"status"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	query := url.Values{
+		"jql":        {fmt.Sprintf("project = %q AND %s ORDER BY updated DESC", j.projectKey, condition)},
+		"fields":     {"status"},
+		"maxResults": {"1"},
+	}
+	if err := j.getJSON("/rest/api/3/search/jql?"+query.Encode(), &result); err != nil {
+		return "", fmt.Errorf("failed to find a ticket to check the %s transition on: %w", action, err)
+	}
+	if len(result.Issues) == 0 {
+		return fmt.Sprintf("%s not verified, no ticket to check", action), nil
+	}
+
+	issue := result.Issues[0]
+	transitions, err := j.getTransitions(issue.Key)
+	if err != nil {
+		return "", err
+	}
+	id := findTransition(transitions, configured, fallback)
+	if id == "" {
+		available := make([]string, 0, len(transitions))
+		for _, t := range transitions {
+			available = append(available, fmt.Sprintf("%q (%s)", t.Name, t.ID))
+		}
+		if len(available) == 0 {
+			available = append(available, "none")
+		}
+		if configured != "" {
+			return "", fmt.Errorf("%s transition %q is not available from status %q of %s; available: %s",
+				action, configured, issue.Fields.Status.Name, issue.Key, strings.Join(available, ", "))
+		}
+		return "", fmt.Errorf("no %s transition found from status %q of %s; configure one of: %s",
+			action, issue.Fields.Status.Name, issue.Key, strings.Join(available, ", "))
+	}
+
+	for _, t := range transitions {
+		if t.ID == id {
+			return fmt.Sprintf("%s via %q from %s", action, t.Name, issue.Key), nil
+		}
+	}
+	return fmt.Sprintf("%s via transition %s from %s", action, id, issue.Key), nil
+}
+
+// getJSON performs a GET request against the Jira API and decodes the response into out
+func (j *JiraTicketSystem) getJSON(path string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, j.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return apierror.FromStatus(resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
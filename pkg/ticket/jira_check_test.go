@@ -0,0 +1,160 @@
+package ticket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newCheckServer(t *testing.T, statuses string) *httptest.Server {
+	t.Helper()
+	return newWorkflowCheckServer(t, statuses, "")
+}
+
+// newWorkflowCheckServer serves the Done ticket PROJ-1 and the open ticket PROJ-2, both
+// offering the given transitions; without transitions the project has no tickets
+func newWorkflowCheckServer(t *testing.T, statuses, transitions string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok || r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/api/3/myself":
+			w.Write([]byte(`{"displayName": "Silence Bot", "emailAddress": "bot@test.com"}`))
+		case "/rest/api/3/project/PROJ":
+			w.Write([]byte(`{"key": "PROJ", "name": "Operations"}`))
+		case "/rest/api/3/project/PROJ/statuses":
+			w.Write([]byte(statuses))
+		case "/rest/api/3/search/jql":
+			switch {
+			case transitions == "":
+				w.Write([]byte(`{"issues": []}`))
+			case strings.Contains(r.URL.Query().Get("jql"), "statusCategory != Done"):
+				w.Write([]byte(`{"issues": [{"key": "PROJ-2", "fields": {"status": {"name": "In Progress"}}}]}`))
+			default:
+				w.Write([]byte(`{"issues": [{"key": "PROJ-1", "fields": {"status": {"name": "Done"}}}]}`))
+			}
+		case "/rest/api/3/issue/PROJ-1/transitions", "/rest/api/3/issue/PROJ-2/transitions":
+			w.Write([]byte(transitions))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestCurrentUser(t *testing.T) {
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/myself" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		lookups++
+		w.Write([]byte(`{"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "Silence Bot"}`))
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user", "token", "PROJ", "")
+	for i := 0; i < 2; i++ {
+		user, err := jira.CurrentUser()
+		if err != nil || user != "5b10ac8d82e05b22cc7d4ef5" {
+			t.Fatalf("Expected the account ID of the API user, got %q, %v", user, err)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("Expected the API user to be looked up once, got %d lookups", lookups)
+	}
+}
+
+func TestCheckAuthAndProject(t *testing.T) {
+	server := newCheckServer(t, `[]`)
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user", "token", "PROJ", "")
+	user, err := jira.CheckAuth()
+	if err != nil || user != "Silence Bot <bot@test.com>" {
+		t.Errorf("Expected authenticated user, got %q, %v", user, err)
+	}
+	project, err := jira.CheckProject()
+	if err != nil || project != "PROJ (Operations)" {
+		t.Errorf("Expected project, got %q, %v", project, err)
+	}
+
+	missing := NewJiraTicketSystem(server.URL, "user", "token", "NOPE", "")
+	if _, err := missing.CheckProject(); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected 404 error for a missing project, got %v", err)
+	}
+}
+
+func TestCheckTransitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses string
+		wantErr  string
+	}{
+		{"Complete workflow", `[{"name": "Task", "statuses": [{"name": "To Do"}, {"name": "In Progress"}, {"name": "Done"}]}]`, ""},
+		{"No close status", `[{"name": "Task", "statuses": [{"name": "Open"}, {"name": "In Progress"}]}]`, "close"},
+		{"No reopen status", `[{"name": "Task", "statuses": [{"name": "Backlog"}, {"name": "Closed"}]}]`, "reopen"},
+		{"No task issue type", `[{"name": "Bug", "statuses": [{"name": "Open"}, {"name": "Closed"}]}]`, "not available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCheckServer(t, tt.statuses)
+			defer server.Close()
+
+			_, err := NewJiraTicketSystem(server.URL, "user", "token", "PROJ", "").CheckTransitions(true, true)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckTransitions_Lookup(t *testing.T) {
+	statuses := `[{"name": "Task", "statuses": [{"name": "To Do"}, {"name": "Done"}]}]`
+	transitions := `{"transitions": [{"id": "11", "name": "Back to backlog", "to": {"name": "To Do"}}, {"id": "31", "name": "Resolve", "to": {"name": "Done"}}]}`
+
+	server := newWorkflowCheckServer(t, statuses, transitions)
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user", "token", "PROJ", "")
+	found, err := jira.CheckTransitions(true, true)
+	if err != nil {
+		t.Fatalf("Expected transitions to be found, got %v", err)
+	}
+	if found != `reopen via "Back to backlog" from PROJ-1; close via "Resolve" from PROJ-2` {
+		t.Errorf("Unexpected transitions: %s", found)
+	}
+
+	jira.SetWorkflow(Workflow{CloseTransition: "Mitigate"})
+	_, err = jira.CheckTransitions(false, true)
+	if err == nil || !strings.Contains(err.Error(), `close transition "Mitigate" is not available`) ||
+		!strings.Contains(err.Error(), `"Resolve" (31)`) {
+		t.Errorf("Expected missing configured transition with the available ones, got %v", err)
+	}
+
+	// Nothing is looked up when neither transition is needed
+	if found, err := jira.CheckTransitions(false, false); err != nil || found != "no transitions needed" {
+		t.Errorf("Expected no transitions needed, got %q, %v", found, err)
+	}
+}
+
+func TestCheckTransitions_NoTickets(t *testing.T) {
+	server := newWorkflowCheckServer(t, `[{"name": "Task", "statuses": [{"name": "Open"}, {"name": "Closed"}]}]`, "")
+	defer server.Close()
+
+	found, err := NewJiraTicketSystem(server.URL, "user", "token", "PROJ", "").CheckTransitions(true, false)
+	if err != nil || found != "reopen not verified, no ticket to check" {
+		t.Errorf("Expected unverified reopen transition, got %q, %v", found, err)
+	}
+}
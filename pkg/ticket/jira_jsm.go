@@ -0,0 +1,216 @@
+package ticket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// ServiceDesk raises the tickets created in the configured project as Jira Service
+// Management customer requests, for request types with required fields that plain issue
+// creation cannot fill in
+type ServiceDesk struct {
+	ID            string                 // Service desk ID, e.g. "4"
+	RequestTypeID string                 // Request type of the created requests
+	Fields        map[string]interface{} // requestFieldValues required by the request type, besides summary and description
+	Participants  []string               // Account IDs or email addresses added as request participants
+	OnBehalfOf    string                 // Account ID or email address of the customer the requests are raised for; the API user when empty
+}
+
+// SetServiceDesk raises the tickets created in the configured project as customer
+// requests of the service desk. Sub-tasks and tickets of other projects are created as
+// plain issues.
+func (j *JiraTicketSystem) SetServiceDesk(desk ServiceDesk) {
+	j.serviceDesk = desk
+}
+
+// SetSLAFields sets the Jira Service Management SLA fields (e.g. customfield_10030 for
+// "Time to resolution") whose state is exposed through Ticket.SLAs
+func (j *JiraTicketSystem) SetSLAFields(fieldIDs []string) {
+	j.slaFields = fieldIDs
+}
+
+// raisesRequest reports whether a ticket is created as a customer request
+func (j *JiraTicketSystem) raisesRequest(ticket *Ticket) bool {
+	return j.serviceDesk.ID != "" && ticket.Parent == "" &&
+		(ticket.Project == "" || strings.EqualFold(ticket.Project, j.projectKey))
+}
+
+type jsmRequest struct {
+	ServiceDeskID       string                 `json:"serviceDeskId"`
+	RequestTypeID       string                 `json:"requestTypeId"`
+	RequestFieldValues  map[string]interface{} `json:"requestFieldValues"`
+	RequestParticipants []string               `json:"requestParticipants,omitempty"`
+	RaiseOnBehalfOf     string                 `json:"raiseOnBehalfOf,omitempty"`
+}
+
+// createRequest raises a ticket as a customer request. The request type's form only
+// takes its own fields, so the labels, priority, due date and silence reference field are
+// set on the issue afterwards. The request exists at that point, so a failure to set them
+// is logged rather than returned, letting the caller link the request to its silence
+// instead of raising another one.
+func (j *JiraTicketSystem) createRequest(ticket *Ticket) (string, error) {
+	description := j.stripSilenceRef(ticket.Description)
+	if j.silenceRefField == "" && ticket.SilenceRef != "" {
+		description = fmt.Sprintf("%s: %s\n\n%s", j.annotationPrefix, ticket.SilenceRef, description)
+	}
+	values := make(map[string]interface{}, len(j.serviceDesk.Fields)+2)
+	for id, value := range j.serviceDesk.Fields {
+		values[id] = value
+	}
+	values["summary"] = ticket.Summary
+	values["description"] = description
+
+	request := jsmRequest{
+		ServiceDeskID:      j.serviceDesk.ID,
+		RequestTypeID:      j.serviceDesk.RequestTypeID,
+		RequestFieldValues: values,
+	}
+	for _, user := range j.serviceDesk.Participants {
+		accountID, err := j.accountID(user)
+		if err != nil {
+			return "", fmt.Errorf("request participant %s: %w", user, err)
+		}
+		request.RequestParticipants = append(request.RequestParticipants, accountID)
+	}
+	if j.serviceDesk.OnBehalfOf != "" {
+		accountID, err := j.accountID(j.serviceDesk.OnBehalfOf)
+		if err != nil {
+			return "", fmt.Errorf("customer %s: %w", j.serviceDesk.OnBehalfOf, err)
+		}
+		request.RaiseOnBehalfOf = accountID
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/rest/servicedeskapi/request", j.baseURL)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	j.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create customer request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		responseBody, _ := io.ReadAll(resp.Body)
+		return "", apierror.FromStatus(resp.StatusCode, string(responseBody))
+	}
+
+	var result struct {
+		IssueKey string `json:"issueKey"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	fields := make(map[string]interface{})
+	if len(ticket.Labels) > 0 {
+		fields["labels"] = ticket.Labels
+	}
+	if ticket.Priority != "" {
+		fields["priority"] = jiraPriority{Name: ticket.Priority}
+	}
+	if !ticket.DueDate.IsZero() {
+		fields["duedate"] = ticket.DueDate.Format(jiraDateFormat)
+	}
+	if j.silenceRefField != "" && ticket.SilenceRef != "" {
+		fields[j.silenceRefField] = ticket.SilenceRef
+	}
+	if len(fields) > 0 {
+		if err := j.updateFields(result.IssueKey, fields); err != nil {
+			log.Printf("Warning: failed to set labels, priority, due date and silence reference of request %s: %v", result.IssueKey, err)
+		}
+	}
+
+	return result.IssueKey, nil
+}
+
+// accountID returns user if it is an account ID, or looks up the account ID of an email address
+func (j *JiraTicketSystem) accountID(user string) (string, error) {
+	if strings.Contains(user, "@") {
+		return j.findAccountID(user)
+	}
+	return user, nil
+}
+
+// jsmTime is a point in time in Jira Service Management responses
+type jsmTime struct {
+	EpochMillis int64 `json:"epochMillis"`
+}
+
+// jsmDuration is a duration in Jira Service Management responses
+type jsmDuration struct {
+	Millis int64 `json:"millis"`
+}
+
+// jsmSLA is the value of an SLA field in an issue response
+type jsmSLA struct {
+	Name            string `json:"name"`
+	CompletedCycles []struct {
+		Breached bool `json:"breached"`
+	} `json:"completedCycles"`
+	OngoingCycle *struct {
+		Breached      bool         `json:"breached"`
+		Paused        bool         `json:"paused"`
+		BreachTime    *jsmTime     `json:"breachTime"`
+		RemainingTime *jsmDuration `json:"remainingTime"`
+	} `json:"ongoingCycle"`
+}
+
+// extractSLAs extracts the configured SLA fields from a raw issue response
+func (j *JiraTicketSystem) extractSLAs(body []byte) ([]SLA, error) {
+	var raw struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	var slas []SLA
+	for _, id := range j.slaFields {
+		value := raw.Fields[id]
+		if len(value) == 0 || string(value) == "null" {
+			continue
+		}
+		var field jsmSLA
+		if err := json.Unmarshal(value, &field); err != nil {
+			return nil, fmt.Errorf("field %s: %w", id, err)
+		}
+		sla := SLA{Name: field.Name}
+		if cycle := field.OngoingCycle; cycle != nil {
+			sla.Ongoing = true
+			sla.Breached = cycle.Breached
+			sla.Paused = cycle.Paused
+			if cycle.BreachTime != nil && cycle.BreachTime.EpochMillis != 0 {
+				sla.BreachTime = time.UnixMilli(cycle.BreachTime.EpochMillis)
+			}
+			if cycle.RemainingTime != nil {
+				sla.Remaining = time.Duration(cycle.RemainingTime.Millis) * time.Millisecond
+			}
+		} else if n := len(field.CompletedCycles); n > 0 {
+			sla.Breached = field.CompletedCycles[n-1].Breached
+		} else {
+			continue
+		}
+		slas = append(slas, sla)
+	}
+	return slas, nil
+}
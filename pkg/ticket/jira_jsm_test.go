@@ -0,0 +1,194 @@
+package ticket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateTicket_ServiceDeskRequest(t *testing.T) {
+	var updated map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/user/search":
+			w.Write([]byte(`[{"accountId": "abc123", "emailAddress": "alice@example.com"}]`))
+		case "/rest/servicedeskapi/request":
+			var request jsmRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			if request.ServiceDeskID != "4" || request.RequestTypeID != "12" {
+				t.Errorf("Expected service desk 4 and request type 12, got %s and %s", request.ServiceDeskID, request.RequestTypeID)
+			}
+			values := request.RequestFieldValues
+			if values["summary"] != "disk full" || values["customfield_10060"] != "production" {
+				t.Errorf("Unexpected request field values %v", values)
+			}
+			if description, _ := values["description"].(string); !strings.HasPrefix(description, "silence-manager: silence-1\n\n") {
+				t.Errorf("Expected the silence reference in the description, got %q", description)
+			}
+			if !reflect.DeepEqual(request.RequestParticipants, []string{"abc123", "def456"}) {
+				t.Errorf("Expected the resolved participants, got %v", request.RequestParticipants)
+			}
+			if request.RaiseOnBehalfOf != "cust789" {
+				t.Errorf("Expected the request to be raised for cust789, got %q", request.RaiseOnBehalfOf)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"issueId": "10010", "issueKey": "HELP-5"}`))
+		case "/rest/api/3/issue/HELP-5":
+			var body struct {
+				Fields map[string]interface{} `json:"fields"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			updated = body.Fields
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "HELP", "silence-manager")
+	jira.SetServiceDesk(ServiceDesk{
+		ID:            "4",
+		RequestTypeID: "12",
+		Fields:        map[string]interface{}{"customfield_10060": "production"},
+		Participants:  []string{"alice@example.com", "def456"},
+		OnBehalfOf:    "cust789",
+	})
+	key, err := jira.CreateTicket(&Ticket{
+		Summary:     "disk full",
+		Description: "Disk /var is full",
+		SilenceRef:  "silence-1",
+		Labels:      []string{"silence-manager"},
+		Priority:    "High",
+	})
+	if err != nil {
+		t.Fatalf("CreateTicket() failed: %v", err)
+	}
+	if key != "HELP-5" {
+		t.Errorf("Expected ticket key 'HELP-5', got '%s'", key)
+	}
+	if updated == nil || updated["priority"] == nil || updated["labels"] == nil {
+		t.Errorf("Expected the labels and priority to be set on the request, got %v", updated)
+	}
+}
+
+func TestCreateTicket_ServiceDeskFieldsRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/servicedeskapi/request":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"issueId": "10010", "issueKey": "HELP-5"}`))
+		case "/rest/api/3/issue/HELP-5":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": {"labels": "Field 'labels' cannot be set."}}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "HELP", "silence-manager")
+	jira.SetServiceDesk(ServiceDesk{ID: "4", RequestTypeID: "12"})
+	key, err := jira.CreateTicket(&Ticket{Summary: "disk full", Labels: []string{"silence-manager"}})
+	if err != nil || key != "HELP-5" {
+		t.Errorf("Expected the raised request despite the rejected fields, got %q, %v", key, err)
+	}
+}
+
+func TestCreateTicket_ServiceDeskSubTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue" {
+			t.Errorf("Expected sub-tasks to be created as issues, got a request to %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(jiraIssue{Key: "HELP-6"})
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "HELP", "silence-manager")
+	jira.SetServiceDesk(ServiceDesk{ID: "4", RequestTypeID: "12"})
+	if _, err := jira.CreateTicket(&Ticket{Summary: "db-1", Parent: "HELP-5"}); err != nil {
+		t.Fatalf("CreateTicket() failed: %v", err)
+	}
+	if _, err := jira.CreateTicket(&Ticket{Summary: "disk full", Project: "STOR"}); err != nil {
+		t.Fatalf("CreateTicket() failed: %v", err)
+	}
+}
+
+func TestGetTicket_SLAs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"key": "HELP-5",
+			"fields": {
+				"summary": "disk full",
+				"status": {"name": "Open"},
+				"customfield_10030": {
+					"name": "Time to resolution",
+					"completedCycles": [],
+					"ongoingCycle": {
+						"breachTime": {"iso8601": "2026-10-17T10:00:00+0000", "epochMillis": 1792231200000},
+						"breached": false,
+						"paused": false,
+						"remainingTime": {"millis": 10800000, "friendly": "3h"}
+					}
+				},
+				"customfield_10031": {
+					"name": "Time to first response",
+					"completedCycles": [{"breached": true}]
+				},
+				"customfield_10032": null
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "HELP", "")
+	jira.SetSLAFields([]string{"customfield_10030", "customfield_10031", "customfield_10032"})
+	ticket, err := jira.GetTicket("HELP-5")
+	if err != nil {
+		t.Fatalf("GetTicket() failed: %v", err)
+	}
+
+	if len(ticket.SLAs) != 2 {
+		t.Fatalf("Expected 2 SLAs, got %+v", ticket.SLAs)
+	}
+	resolution := ticket.SLAs[0]
+	if !resolution.Ongoing || resolution.Remaining != 3*time.Hour || !resolution.BreachTime.Equal(time.UnixMilli(1792231200000)) {
+		t.Errorf("Unexpected ongoing SLA %+v", resolution)
+	}
+	if got := resolution.String(); got != "Time to resolution: 3h left" {
+		t.Errorf("Expected 'Time to resolution: 3h left', got %q", got)
+	}
+	if response := ticket.SLAs[1]; response.Ongoing || !response.Breached {
+		t.Errorf("Expected a breached completed SLA, got %+v", response)
+	}
+	if urgent := ticket.UrgentSLA(); urgent == nil || urgent.Name != "Time to resolution" {
+		t.Errorf("Expected the ongoing SLA to be the most urgent, got %+v", urgent)
+	}
+}
+
+func TestUrgentSLA(t *testing.T) {
+	ticket := &Ticket{SLAs: []SLA{
+		{Name: "paused", Ongoing: true, Paused: true, Remaining: time.Minute},
+		{Name: "later", Ongoing: true, Remaining: 5 * time.Hour},
+		{Name: "sooner", Ongoing: true, Remaining: 90 * time.Minute},
+		{Name: "done", Breached: true},
+	}}
+	if urgent := ticket.UrgentSLA(); urgent.String() != "sooner: 1h30m left" {
+		t.Errorf("Expected the SLA closest to breaching, got %q", urgent)
+	}
+	ticket.SLAs = append(ticket.SLAs, SLA{Name: "late", Ongoing: true, Breached: true, Remaining: -20 * time.Minute})
+	if urgent := ticket.UrgentSLA(); urgent.String() != "late: breached 20m ago" {
+		t.Errorf("Expected the breached SLA, got %q", urgent)
+	}
+	if (&Ticket{}).UrgentSLA() != nil {
+		t.Error("Expected no urgent SLA without SLAs")
+	}
+}
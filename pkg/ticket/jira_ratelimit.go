@@ -0,0 +1,243 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// ErrRequestBudgetExhausted is returned for every Jira request once the run has used up
+// its request budget
+var ErrRequestBudgetExhausted = apierror.New(apierror.RateLimited, "jira request budget exhausted")
+
+// RateLimitError is returned when Jira keeps throttling a request after all retries, or
+// asks for a longer delay than RateLimit.MaxWait
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("jira rate limit exceeded, retry after %v", e.RetryAfter)
+}
+
+// Category classifies the error as rate limited
+func (e *RateLimitError) Category() apierror.Category {
+	return apierror.RateLimited
+}
+
+// RateLimit configures how throttled Jira requests are retried
+type RateLimit struct {
+	MaxRetries int           // Retries of a throttled or transiently failing request before giving up
+	MaxWait    time.Duration // Longest delay that is waited out before a retry
+	Budget     int           // Maximum number of requests per run; 0 is unlimited
+}
+
+// RateLimitObserver is notified when Jira throttles requests, e.g. to publish metrics
+type RateLimitObserver interface {
+	// RecordThrottled records a throttled request and the delay before it was retried,
+	// zero if it was not retried
+	RecordThrottled(system string, wait time.Duration)
+
+	// RecordRequestBudgetExhausted records that the run used up its request budget
+	RecordRequestBudgetExhausted(system string)
+}
+
+// RequestObserver is notified of the duration of every Jira request, e.g. to publish
+// latency metrics
+type RequestObserver interface {
+	// RecordRequestDuration records how long a request took until its response headers
+	// arrived or it failed. Every attempt of a retried request is recorded.
+	RecordRequestDuration(system, operation string, duration time.Duration)
+}
+
+// tracer traces Jira requests; spans are dropped unless tracing is enabled
+var tracer = otel.Tracer("github.com/conallob/silence-manager/pkg/ticket")
+
+// defaultRateLimit retries throttled requests a few times without bounding the run
+var defaultRateLimit = RateLimit{MaxRetries: 3, MaxWait: time.Minute}
+
+// SetRateLimit sets the retry behaviour and request budget for throttled requests
+func (j *JiraTicketSystem) SetRateLimit(rateLimit RateLimit) {
+	j.rateLimit = rateLimit
+}
+
+// ResetRequestBudget starts a new request budget, for clients kept across runs such as
+// those of the operator, which reconciles each policy as a run of its own
+func (j *JiraTicketSystem) ResetRequestBudget() {
+	j.requests = 0
+	j.budgetExhausted = false
+}
+
+// SetRateLimitObserver sets the observer notified when Jira throttles requests
+func (j *JiraTicketSystem) SetRateLimitObserver(observer RateLimitObserver) {
+	j.rateLimitObserver = observer
+}
+
+// SetRequestObserver sets the observer notified of the duration of every request
+func (j *JiraTicketSystem) SetRequestObserver(observer RequestObserver) {
+	j.requestObserver = observer
+}
+
+// SetRequestContext sets the context whose values, such as the trace span of the caller,
+// are passed to subsequent requests. Its cancellation is ignored. A nil ctx clears it.
+func (j *JiraTicketSystem) SetRequestContext(ctx context.Context) {
+	j.requestCtx = ctx
+}
+
+// do sends a request to Jira. Throttled requests are retried after the delay given by the
+// Retry-After header, or an exponential backoff without one. Requests other than POST are
+// also retried with backoff when they fail transiently; a failed POST may have taken
+// effect, so it is left to the next run.
+func (j *JiraTicketSystem) do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if j.rateLimit.Budget > 0 && j.requests >= j.rateLimit.Budget {
+			if !j.budgetExhausted {
+				j.budgetExhausted = true
+				if j.rateLimitObserver != nil {
+					j.rateLimitObserver.RecordRequestBudgetExhausted("jira")
+				}
+			}
+			return nil, ErrRequestBudgetExhausted
+		}
+		j.requests++
+
+		resp, err := j.send(req)
+		if isTransient(req, resp, err) && attempt < j.rateLimit.MaxRetries {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			j.sleep(time.Second << attempt)
+			if err := rewindBody(req); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil || !isThrottled(resp) {
+			return resp, err
+		}
+		resp.Body.Close()
+
+		wait := retryDelay(resp, attempt)
+		if attempt >= j.rateLimit.MaxRetries || wait > j.rateLimit.MaxWait {
+			if j.rateLimitObserver != nil {
+				j.rateLimitObserver.RecordThrottled("jira", 0)
+			}
+			return nil, &RateLimitError{RetryAfter: wait}
+		}
+		if j.rateLimitObserver != nil {
+			j.rateLimitObserver.RecordThrottled("jira", wait)
+		}
+		j.sleep(wait)
+
+		if err := rewindBody(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rewindBody restores the body of a request before it is resent
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	req.Body = body
+	return nil
+}
+
+// send sends a single attempt of a request, reporting its duration and tracing it as a
+// child of the span in the request context set with SetRequestContext
+func (j *JiraTicketSystem) send(req *http.Request) (*http.Response, error) {
+	operation := requestOperation(req)
+	ctx := req.Context()
+	if j.requestCtx != nil {
+		ctx = context.WithoutCancel(j.requestCtx)
+	}
+	ctx, span := tracer.Start(ctx, "jira "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	started := time.Now()
+	resp, err := j.httpClient.Do(req.WithContext(ctx))
+	if j.requestObserver != nil {
+		j.requestObserver.RecordRequestDuration("jira", operation, time.Since(started))
+	}
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	return resp, err
+}
+
+// requestOperation names the kind of a Jira request for latency metrics: transition,
+// search, or the lowercase HTTP method
+func requestOperation(req *http.Request) string {
+	switch {
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/transitions"):
+		return "transition"
+	case strings.Contains(req.URL.Path, "/search"):
+		return "search"
+	default:
+		return strings.ToLower(req.Method)
+	}
+}
+
+// isThrottled reports whether Jira rejected a request because of rate limiting
+func isThrottled(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != ""
+}
+
+// isTransient reports whether a request other than POST failed in a way that a retry may
+// fix: it could not be sent, or a proxy or an overloaded server answered with 502, 503 or
+// 504 without asking for a delay
+func isTransient(req *http.Request, resp *http.Response, err error) bool {
+	if req.Method == http.MethodPost || req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return apierror.CategoryOf(err) == apierror.Transient
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return !isThrottled(resp)
+	}
+	return false
+}
+
+// retryDelay returns the delay requested by the Retry-After header, in seconds or as an
+// HTTP date, falling back to 1s, 2s, 4s, ... for the given attempt
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(time.Until(at), 0)
+		}
+	}
+	return time.Second << attempt
+}
@@ -0,0 +1,192 @@
+package ticket
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/apierror"
+)
+
+// recordingObserver records the throttling and request notifications of a Jira client
+type recordingObserver struct {
+	waits      []time.Duration
+	exhausted  int
+	operations []string
+}
+
+func (o *recordingObserver) RecordThrottled(system string, wait time.Duration) {
+	o.waits = append(o.waits, wait)
+}
+
+func (o *recordingObserver) RecordRequestBudgetExhausted(system string) {
+	o.exhausted++
+}
+
+func (o *recordingObserver) RecordRequestDuration(system, operation string, duration time.Duration) {
+	o.operations = append(o.operations, operation)
+}
+
+func newThrottledJira(t *testing.T, handler http.HandlerFunc) (*JiraTicketSystem, *recordingObserver, *[]time.Duration) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var slept []time.Duration
+	observer := &recordingObserver{}
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	jira.sleep = func(d time.Duration) { slept = append(slept, d) }
+	jira.SetRateLimitObserver(observer)
+	jira.SetRequestObserver(observer)
+	return jira, observer, &slept
+}
+
+func TestDo_RetriesThrottledRequests(t *testing.T) {
+	var attempts int
+	var bodies []string
+	jira, observer, slept := newThrottledJira(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+
+	if err := jira.AddComment("PROJ-123", "Still firing"); err != nil {
+		t.Fatalf("AddComment() failed: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", attempts)
+	}
+	if bodies[2] == "" || bodies[2] != bodies[0] {
+		t.Errorf("Expected the request body to be resent, got %q", bodies)
+	}
+	expected := []time.Duration{2 * time.Second, 2 * time.Second}
+	if len(*slept) != 2 || (*slept)[0] != expected[0] || (*slept)[1] != expected[1] {
+		t.Errorf("Expected waits %v (Retry-After, then backoff), got %v", expected, *slept)
+	}
+	if len(observer.waits) != 2 {
+		t.Errorf("Expected 2 throttled requests to be observed, got %d", len(observer.waits))
+	}
+	if len(observer.operations) != 3 || observer.operations[0] != "post" {
+		t.Errorf("Expected the duration of every attempt to be observed, got %v", observer.operations)
+	}
+}
+
+func TestRequestOperation(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodGet, "/rest/api/3/issue/PROJ-1", "get"},
+		{http.MethodGet, "/rest/api/3/issue/PROJ-1/transitions", "get"},
+		{http.MethodPost, "/rest/api/3/issue/PROJ-1/transitions", "transition"},
+		{http.MethodPost, "/rest/api/3/search/jql", "search"},
+		{http.MethodPut, "/rest/api/3/issue/PROJ-1", "put"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := requestOperation(req); got != tt.want {
+			t.Errorf("requestOperation(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDo_GivesUpWhenThrottled(t *testing.T) {
+	jira, observer, slept := newThrottledJira(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	jira.SetRateLimit(RateLimit{MaxRetries: 2, MaxWait: time.Minute})
+
+	_, err := jira.GetTicket("PROJ-123")
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if len(*slept) != 2 || len(observer.waits) != 3 || observer.waits[2] != 0 {
+		t.Errorf("Expected 2 retries and a final unretried throttle, got waits %v and observed %v", *slept, observer.waits)
+	}
+
+	// Delays beyond MaxWait are not waited out
+	*slept = nil
+	jira.SetRateLimit(RateLimit{MaxRetries: 2, MaxWait: 500 * time.Millisecond})
+	if _, err := jira.GetTicket("PROJ-123"); !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if len(*slept) != 0 {
+		t.Errorf("Expected no retry, got waits %v", *slept)
+	}
+}
+
+func TestDo_RequestBudget(t *testing.T) {
+	jira, observer, _ := newThrottledJira(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	jira.SetRateLimit(RateLimit{Budget: 2})
+
+	for i := 0; i < 2; i++ {
+		if err := jira.AddComment("PROJ-123", "comment"); err != nil {
+			t.Fatalf("AddComment() failed within the budget: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := jira.AddComment("PROJ-123", "comment"); !errors.Is(err, ErrRequestBudgetExhausted) {
+			t.Errorf("Expected ErrRequestBudgetExhausted, got %v", err)
+		}
+	}
+	if observer.exhausted != 1 {
+		t.Errorf("Expected budget exhaustion to be observed once, got %d", observer.exhausted)
+	}
+
+	jira.ResetRequestBudget()
+	if err := jira.AddComment("PROJ-123", "comment"); err != nil {
+		t.Errorf("AddComment() failed after resetting the budget: %v", err)
+	}
+}
+
+func TestDo_RetriesTransientFailures(t *testing.T) {
+	var attempts int
+	jira, _, slept := newThrottledJira(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key": "PROJ-123", "fields": {"summary": "Test", "status": {"name": "Open"}}}`))
+	})
+
+	if _, err := jira.GetTicket("PROJ-123"); err != nil {
+		t.Fatalf("GetTicket() failed: %v", err)
+	}
+	if attempts != 2 || len(*slept) != 1 || (*slept)[0] != time.Second {
+		t.Errorf("Expected one retry after 1s, got %d attempts and waits %v", attempts, *slept)
+	}
+}
+
+func TestDo_DoesNotRetryFailedPosts(t *testing.T) {
+	var attempts int
+	jira, _, _ := newThrottledJira(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	err := jira.AddComment("PROJ-123", "Still firing")
+	if attempts != 1 {
+		t.Errorf("Expected a failed POST not to be retried, got %d attempts", attempts)
+	}
+	if apierror.CategoryOf(err) != apierror.Transient {
+		t.Errorf("Expected a transient error, got %v", err)
+	}
+}
@@ -2,8 +2,10 @@ package ticket
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -67,11 +69,12 @@ func TestGetTicket_Success(t *testing.T) {
 				Description: &jiraDescription{
 					Type:    "doc",
 					Version: 1,
-					Content: []jiraDescriptionContent{
+					Content: []jiraNode{
 						{
 							Type: "paragraph",
-							Content: []jiraDescriptionParagraph{
+							Content: []jiraNode{
 								{Type: "text", Text: "silence-manager: silence-id-123"},
+								{Type: "hardBreak"},
 								{Type: "text", Text: "Test description"},
 							},
 						},
@@ -80,7 +83,9 @@ func TestGetTicket_Success(t *testing.T) {
 				Status:  &jiraStatus{Name: "Open"},
 				Created: time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
 				Updated: time.Now().Format(time.RFC3339),
-				Labels:  []string{"label1", "label2"},
+				Labels:   []string{"label1", "label2"},
+				Priority: &jiraPriority{Name: "High"},
+				DueDate:  "2024-03-18",
 			},
 		}
 		json.NewEncoder(w).Encode(response)
@@ -108,6 +113,90 @@ func TestGetTicket_Success(t *testing.T) {
 	if len(ticket.Labels) != 2 {
 		t.Errorf("Expected 2 labels, got %d", len(ticket.Labels))
 	}
+	if ticket.Priority != "High" {
+		t.Errorf("Expected priority to be 'High', got '%s'", ticket.Priority)
+	}
+	if !ticket.DueDate.Equal(time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected due date to be 2024-03-18, got %v", ticket.DueDate)
+	}
+}
+
+type rotatingCredentials struct {
+	username, apiToken string
+}
+
+func (c *rotatingCredentials) Credentials() (string, string) {
+	return c.username, c.apiToken
+}
+
+func TestCredentialSource_Rotation(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if user != "bot@test.com" {
+			t.Errorf("Expected the username of the credential source, got %q", user)
+		}
+		tokens = append(tokens, pass)
+		json.NewEncoder(w).Encode(jiraIssue{ID: "10001", Key: "PROJ-123", Fields: jiraFields{Status: &jiraStatus{Name: "Open"}}})
+	}))
+	defer server.Close()
+
+	credentials := &rotatingCredentials{username: "bot@test.com", apiToken: "old-token"}
+	jira := NewJiraTicketSystem(server.URL, "", "", "PROJ", "silence-manager")
+	jira.SetCredentialSource(credentials)
+
+	if _, err := jira.GetTicket("PROJ-123"); err != nil {
+		t.Fatalf("GetTicket() failed: %v", err)
+	}
+	credentials.apiToken = "new-token"
+	if _, err := jira.GetTicket("PROJ-123"); err != nil {
+		t.Fatalf("GetTicket() failed: %v", err)
+	}
+
+	if len(tokens) != 2 || tokens[0] != "old-token" || tokens[1] != "new-token" {
+		t.Errorf("Expected the rotated token on the second request, got %v", tokens)
+	}
+}
+
+func TestGetTicket_CustomFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "10001",
+			"key": "PROJ-123",
+			"fields": {
+				"summary": "Test issue",
+				"status": {"name": "Open"},
+				"customfield_10050": "30d",
+				"customfield_10051": {"value": "14d"},
+				"customfield_10052": 42,
+				"customfield_10053": null
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	jira.SetCustomFields([]string{"customfield_10050", "customfield_10051", "customfield_10052", "customfield_10053"})
+	ticket, err := jira.GetTicket("PROJ-123")
+
+	if err != nil {
+		t.Fatalf("GetTicket() failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"customfield_10050": "30d",
+		"customfield_10051": "14d",
+		"customfield_10052": "42",
+	}
+	for id, want := range expected {
+		if got := ticket.CustomFields[id]; got != want {
+			t.Errorf("Expected %s to be '%s', got '%s'", id, want, got)
+		}
+	}
+	if _, ok := ticket.CustomFields["customfield_10053"]; ok {
+		t.Error("Expected null custom field to be omitted")
+	}
 }
 
 func TestGetTicket_NotFound(t *testing.T) {
@@ -175,6 +264,59 @@ func TestCreateTicket_Success(t *testing.T) {
 	}
 }
 
+func TestCreateTicket_SubTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ji jiraIssue
+		if err := json.NewDecoder(r.Body).Decode(&ji); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if ji.Fields.Parent == nil || ji.Fields.Parent.Key != "OPS-7" {
+			t.Errorf("Expected parent OPS-7, got %+v", ji.Fields.Parent)
+		}
+		if ji.Fields.Project == nil || ji.Fields.Project.Key != "OPS" {
+			t.Errorf("Expected the project of the parent, got %+v", ji.Fields.Project)
+		}
+		if ji.Fields.IssueType == nil || ji.Fields.IssueType.Name != "Subtask" {
+			t.Errorf("Expected the configured sub-task issue type, got %+v", ji.Fields.IssueType)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(jiraIssue{Key: "OPS-8"})
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "silence-manager")
+	jira.SetSubTaskIssueType("Subtask")
+	key, err := jira.CreateTicket(&Ticket{Summary: "db-1", Parent: "OPS-7"})
+	if err != nil {
+		t.Fatalf("CreateTicket() failed: %v", err)
+	}
+	if key != "OPS-8" {
+		t.Errorf("Expected ticket key 'OPS-8', got '%s'", key)
+	}
+}
+
+func TestCreateTicket_Project(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ji jiraIssue
+		if err := json.NewDecoder(r.Body).Decode(&ji); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if ji.Fields.Project == nil || ji.Fields.Project.Key != "STOR" {
+			t.Errorf("Expected project STOR, got %+v", ji.Fields.Project)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(jiraIssue{Key: "STOR-1"})
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "silence-manager")
+	if _, err := jira.CreateTicket(&Ticket{Summary: "disk full", Project: "STOR"}); err != nil {
+		t.Fatalf("CreateTicket() failed: %v", err)
+	}
+}
+
 func TestUpdateTicket_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/rest/api/3/issue/PROJ-123" {
@@ -202,6 +344,438 @@ func TestUpdateTicket_Success(t *testing.T) {
 	}
 }
 
+func TestSilenceRefField(t *testing.T) {
+	var updated map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/PROJ-1":
+			w.Write([]byte(`{"key":"PROJ-1","fields":{"summary":"Linked","customfield_10070":"silence-123"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/PROJ-2":
+			w.Write([]byte(`{"key":"PROJ-2","fields":{"summary":"Legacy","customfield_10070":null,` +
+				`"description":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"silence-manager: silence-456"}]}]}}}`))
+		case r.Method == http.MethodPut:
+			var body struct {
+				Fields map[string]json.RawMessage `json:"fields"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			updated = body.Fields
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	jira.SetSilenceRefField("customfield_10070")
+
+	linked, err := jira.GetTicket("PROJ-1")
+	if err != nil {
+		t.Fatalf("GetTicket() failed: %v", err)
+	}
+	if linked.SilenceRef != "silence-123" {
+		t.Errorf("Expected silence ref from the field, got '%s'", linked.SilenceRef)
+	}
+
+	// Updates leave the description untouched
+	linked.Description = "Edited by a user"
+	linked.SilenceRef = ""
+	if err := jira.UpdateTicket(linked); err != nil {
+		t.Fatalf("UpdateTicket() failed: %v", err)
+	}
+	if _, ok := updated["description"]; ok {
+		t.Errorf("Expected description not to be sent, got %s", updated["description"])
+	}
+	if string(updated["customfield_10070"]) != "null" {
+		t.Errorf("Expected silence ref field to be cleared, got %s", updated["customfield_10070"])
+	}
+
+	// Tickets created before the field was configured fall back to the description, and
+	// the reference moves to the field on their next update
+	legacy, err := jira.GetTicket("PROJ-2")
+	if err != nil {
+		t.Fatalf("GetTicket() failed: %v", err)
+	}
+	if legacy.SilenceRef != "silence-456" {
+		t.Errorf("Expected silence ref from the description, got '%s'", legacy.SilenceRef)
+	}
+	if err := jira.UpdateTicket(legacy); err != nil {
+		t.Fatalf("UpdateTicket() failed: %v", err)
+	}
+	if string(updated["customfield_10070"]) != `"silence-456"` {
+		t.Errorf("Expected silence ref field to be set, got %s", updated["customfield_10070"])
+	}
+	var description jiraDescription
+	json.Unmarshal(updated["description"], &description)
+	if text := jira.extractDescriptionText(&description); text != "" {
+		t.Errorf("Expected reference to be removed from the description, got '%s'", text)
+	}
+}
+
+func TestSearchTickets_Paginated(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var search map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&search)
+		requests = append(requests, search)
+
+		if search["nextPageToken"] == nil {
+			w.Write([]byte(`{"issues":[{"key":"PROJ-1","fields":{"status":{"name":"Open"},"customfield_10070":"silence-1"}}],"nextPageToken":"page-2"}`))
+			return
+		}
+		w.Write([]byte(`{"issues":[{"key":"PROJ-2","fields":{"status":{"name":"Done"},"customfield_10070":"silence-2"}}],"isLast":true}`))
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	jira.SetSilenceRefField("customfield_10070")
+	tickets, err := jira.SearchTickets(`project = "PROJ"`)
+	if err != nil {
+		t.Fatalf("SearchTickets() failed: %v", err)
+	}
+
+	if len(requests) != 2 || requests[1]["nextPageToken"] != "page-2" || requests[0]["jql"] != `project = "PROJ"` {
+		t.Fatalf("Unexpected search requests: %v", requests)
+	}
+	fields, _ := requests[0]["fields"].([]interface{})
+	if len(fields) == 0 || fields[len(fields)-1] != "customfield_10070" {
+		t.Errorf("Expected the silence reference field to be requested, got %v", fields)
+	}
+	if len(tickets) != 2 {
+		t.Fatalf("Expected 2 tickets, got %d", len(tickets))
+	}
+	if tickets[0].Key != "PROJ-1" || tickets[0].SilenceRef != "silence-1" || tickets[0].Status != StatusOpen {
+		t.Errorf("Unexpected first ticket: %+v", tickets[0])
+	}
+	if tickets[1].SilenceRef != "silence-2" || tickets[1].Status != StatusResolved {
+		t.Errorf("Unexpected second ticket: %+v", tickets[1])
+	}
+}
+
+func TestGetTickets_Batches(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var search struct {
+			JQL string `json:"jql"`
+		}
+		json.NewDecoder(r.Body).Decode(&search)
+		queries = append(queries, search.JQL)
+		w.Write([]byte(`{"issues":[{"key":"PROJ-1","fields":{"status":{"name":"Open"}}}],"isLast":true}`))
+	}))
+	defer server.Close()
+
+	keys := make([]string, bulkFetchSize+1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("PROJ-%d", i+1)
+	}
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	tickets, err := jira.GetTickets(keys)
+	if err != nil {
+		t.Fatalf("GetTickets() failed: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 searches for %d keys, got %d", len(keys), len(queries))
+	}
+	if !strings.HasPrefix(queries[0], `issuekey in ("PROJ-1", "PROJ-2",`) || queries[1] != fmt.Sprintf(`issuekey in ("PROJ-%d")`, len(keys)) {
+		t.Errorf("Unexpected queries: %v", queries)
+	}
+	if tickets["PROJ-1"] == nil || tickets["PROJ-1"].Status != StatusOpen {
+		t.Errorf("Expected PROJ-1 to be returned, got %v", tickets)
+	}
+}
+
+func TestGetTickets_MissingKey(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var search struct {
+			JQL string `json:"jql"`
+		}
+		json.NewDecoder(r.Body).Decode(&search)
+		queries = append(queries, search.JQL)
+		if strings.Contains(search.JQL, "PROJ-9") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errorMessages":["An issue with key 'PROJ-9' does not exist for field 'issuekey'."],"warningMessages":[]}`))
+			return
+		}
+		w.Write([]byte(`{"issues":[{"key":"PROJ-1","fields":{"status":{"name":"Open"}}}],"isLast":true}`))
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	tickets, err := jira.GetTickets([]string{"PROJ-1", "PROJ-9"})
+	if err != nil {
+		t.Fatalf("GetTickets() failed: %v", err)
+	}
+
+	if len(queries) != 2 || queries[1] != `issuekey in ("PROJ-1")` {
+		t.Errorf("Expected the batch to be searched again without PROJ-9, got %v", queries)
+	}
+	if len(tickets) != 1 || tickets["PROJ-1"] == nil {
+		t.Errorf("Expected only PROJ-1 to be returned, got %v", tickets)
+	}
+}
+
+func TestSilenceRefJQL(t *testing.T) {
+	tests := []struct {
+		field    string
+		labels   []string
+		expected string
+	}{
+		{"customfield_10070", nil, `project = "OPS" AND (cf[10070] is not EMPTY)`},
+		{"", []string{"silence-active", "silence-expired"}, `project = "OPS" AND (labels in ("silence-active", "silence-expired"))`},
+		{"customfield_10070", []string{"silence-active"}, `project = "OPS" AND (labels in ("silence-active") OR cf[10070] is not EMPTY)`},
+		{"Silence ID", nil, `project = "OPS" AND ("Silence ID" is not EMPTY)`},
+	}
+
+	for _, tt := range tests {
+		if got := SilenceRefJQL("OPS", tt.field, tt.labels); got != tt.expected {
+			t.Errorf("SilenceRefJQL(%q, %v) = %s, expected %s", tt.field, tt.labels, got, tt.expected)
+		}
+	}
+}
+
+func TestUpdateLabels_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-123" {
+			t.Errorf("Expected path '/rest/api/3/issue/PROJ-123', got '%s'", r.URL.Path)
+		}
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT method, got '%s'", r.Method)
+		}
+
+		var body struct {
+			Update struct {
+				Labels []map[string]string `json:"labels"`
+			} `json:"update"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Update.Labels) != 2 {
+			t.Fatalf("Expected 2 label operations, got %d", len(body.Update.Labels))
+		}
+		if body.Update.Labels[0]["add"] != "silence-active" {
+			t.Errorf("Expected add of 'silence-active', got %v", body.Update.Labels[0])
+		}
+		if body.Update.Labels[1]["remove"] != "silence-expired" {
+			t.Errorf("Expected remove of 'silence-expired', got %v", body.Update.Labels[1])
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	if err := jira.UpdateLabels("PROJ-123", []string{"silence-active"}, []string{"silence-expired"}); err != nil {
+		t.Fatalf("UpdateLabels() failed: %v", err)
+	}
+}
+
+func TestSetPriority_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-123" {
+			t.Errorf("Expected path '/rest/api/3/issue/PROJ-123', got '%s'", r.URL.Path)
+		}
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT method, got '%s'", r.Method)
+		}
+
+		var body struct {
+			Fields struct {
+				Priority jiraPriority `json:"priority"`
+			} `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Fields.Priority.Name != "Highest" {
+			t.Errorf("Expected priority 'Highest', got '%s'", body.Fields.Priority.Name)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	if err := jira.SetPriority("PROJ-123", "Highest"); err != nil {
+		t.Fatalf("SetPriority() failed: %v", err)
+	}
+}
+
+func TestAssignTicket_ByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/user/search":
+			if got := r.URL.Query().Get("query"); got != "alice@example.com" {
+				t.Errorf("Expected search for alice@example.com, got %q", got)
+			}
+			w.Write([]byte(`[{"accountId": "other", "emailAddress": "alice.smith@example.com"}, {"accountId": "abc123", "emailAddress": "Alice@example.com"}]`))
+		case "/rest/api/3/issue/PROJ-123":
+			var body struct {
+				Fields struct {
+					Assignee jiraUser `json:"assignee"`
+				} `json:"fields"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Fields.Assignee.AccountID != "abc123" {
+				t.Errorf("Expected assignee 'abc123', got '%s'", body.Fields.Assignee.AccountID)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	if err := jira.AssignTicket("PROJ-123", "alice@example.com"); err != nil {
+		t.Fatalf("AssignTicket() failed: %v", err)
+	}
+}
+
+func TestAssignTicket_UnknownEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/user/search" {
+			t.Errorf("Expected no update for an unknown user, got request to '%s'", r.URL.Path)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	if err := jira.AssignTicket("PROJ-123", "nobody@example.com"); err == nil {
+		t.Error("Expected error for an unknown email address")
+	}
+}
+
+func TestAddWatcher(t *testing.T) {
+	var added []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/user/search":
+			w.Write([]byte(`[{"accountId": "sre123", "emailAddress": "sre@example.com"}]`))
+		case "/rest/api/3/issue/PROJ-123/watchers":
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected POST method, got '%s'", r.Method)
+			}
+			var accountID string
+			json.NewDecoder(r.Body).Decode(&accountID)
+			added = append(added, accountID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	for _, user := range []string{"abc123", "sre@example.com"} {
+		if err := jira.AddWatcher("PROJ-123", user); err != nil {
+			t.Fatalf("AddWatcher(%q) failed: %v", user, err)
+		}
+	}
+	if len(added) != 2 || added[0] != "abc123" || added[1] != "sre123" {
+		t.Errorf("Expected watchers abc123 and sre123, got %v", added)
+	}
+}
+
+func TestSetDueDate_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT method, got '%s'", r.Method)
+		}
+
+		var body struct {
+			Fields map[string]string `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Fields["duedate"] != "2024-07-18" {
+			t.Errorf("Expected due date '2024-07-18', got '%s'", body.Fields["duedate"])
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// 00:30 in Dublin is still the previous day in UTC; the date in the given location is kept
+	dublin, _ := time.LoadLocation("Europe/Dublin")
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	if err := jira.SetDueDate("PROJ-123", time.Date(2024, 7, 18, 0, 30, 0, 0, dublin)); err != nil {
+		t.Fatalf("SetDueDate() failed: %v", err)
+	}
+}
+
+func TestSetRemoteLink_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-123/remotelink" {
+			t.Errorf("Expected path '/rest/api/3/issue/PROJ-123/remotelink', got '%s'", r.URL.Path)
+		}
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST method, got '%s'", r.Method)
+		}
+
+		var link jiraRemoteLink
+		json.NewDecoder(r.Body).Decode(&link)
+		if link.GlobalID != "silence-manager:silence:abc" {
+			t.Errorf("Expected global ID 'silence-manager:silence:abc', got '%s'", link.GlobalID)
+		}
+		if link.Object.URL != "https://am.example.com/#/silences/abc" {
+			t.Errorf("Unexpected link URL '%s'", link.Object.URL)
+		}
+
+		// An existing link with the same global ID is updated
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	err := jira.SetRemoteLink("PROJ-123", RemoteLink{
+		GlobalID: "silence-manager:silence:abc",
+		URL:      "https://am.example.com/#/silences/abc",
+		Title:    "Alertmanager silence abc",
+	})
+	if err != nil {
+		t.Fatalf("SetRemoteLink() failed: %v", err)
+	}
+}
+
+func TestLinkTickets_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issueLink" || r.Method != http.MethodPost {
+			t.Errorf("Expected POST /rest/api/3/issueLink, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var link jiraIssueLink
+		json.NewDecoder(r.Body).Decode(&link)
+		if link.Type.Name != "Cause" || link.InwardIssue.Key != "PROJ-1" || link.OutwardIssue.Key != "PROJ-2" {
+			t.Errorf("Unexpected issue link: %+v", link)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	if err := jira.LinkTickets("PROJ-1", "PROJ-2", "Cause"); err != nil {
+		t.Fatalf("LinkTickets() failed: %v", err)
+	}
+}
+
+func TestDeleteRemoteLink_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected DELETE method, got '%s'", r.Method)
+		}
+		if got := r.URL.Query().Get("globalId"); got != "silence-manager:silence:abc" {
+			t.Errorf("Expected globalId 'silence-manager:silence:abc', got '%s'", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	if err := jira.DeleteRemoteLink("PROJ-123", "silence-manager:silence:abc"); err != nil {
+		t.Fatalf("DeleteRemoteLink() failed: %v", err)
+	}
+}
+
 func TestAddComment_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/rest/api/3/issue/PROJ-123/comment" {
@@ -237,6 +811,90 @@ func TestAddComment_Success(t *testing.T) {
 	}
 }
 
+func TestAddComment_CodeBlock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var commentBody struct {
+			Body jiraDescription `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&commentBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		content := commentBody.Body.Content
+		if len(content) != 2 {
+			t.Fatalf("Expected a paragraph and a code block, got %+v", content)
+		}
+		if content[0].Type != "paragraph" || content[0].Content[0].Text != "New silence created: abc" {
+			t.Errorf("Unexpected paragraph: %+v", content[0])
+		}
+		if content[1].Type != "codeBlock" || content[1].Attrs["language"] != "json" {
+			t.Errorf("Expected a JSON code block, got %+v", content[1])
+		}
+		if content[1].Content[0].Text != "{\n  \"id\": \"abc\"\n}" {
+			t.Errorf("Unexpected code block text: %q", content[1].Content[0].Text)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	err := jira.AddComment("PROJ-123", "New silence created: abc\n\n```json\n{\n  \"id\": \"abc\"\n}\n```")
+	if err != nil {
+		t.Fatalf("AddComment() failed: %v", err)
+	}
+}
+
+func TestGetComments_Paginated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-123/comment" {
+			t.Errorf("Expected path '/rest/api/3/issue/PROJ-123/comment', got '%s'", r.URL.Path)
+		}
+
+		comment := func(id, text string) jiraComment {
+			return jiraComment{
+				ID:      id,
+				Author:  &jiraUser{AccountID: "abc123"},
+				Body:    &jiraDescription{Type: "doc", Version: 1, Content: []jiraNode{{Type: "paragraph", Content: []jiraNode{{Type: "text", Text: text}}}}},
+				Created: "2024-01-01T10:00:00Z",
+			}
+		}
+
+		var response jiraCommentsResponse
+		switch r.URL.Query().Get("startAt") {
+		case "0":
+			response = jiraCommentsResponse{StartAt: 0, Total: 2, Comments: []jiraComment{comment("1", "first")}}
+		case "1":
+			response = jiraCommentsResponse{StartAt: 1, Total: 2, Comments: []jiraComment{comment("2", "/silence expire")}}
+		default:
+			t.Errorf("Unexpected startAt %q", r.URL.Query().Get("startAt"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	comments, err := jira.GetComments("PROJ-123")
+
+	if err != nil {
+		t.Fatalf("GetComments() failed: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments, got %d", len(comments))
+	}
+	if comments[1].ID != "2" || comments[1].Body != "/silence expire" {
+		t.Errorf("Unexpected second comment: %+v", comments[1])
+	}
+	if comments[0].Author != "abc123" {
+		t.Errorf("Expected author 'abc123', got '%s'", comments[0].Author)
+	}
+	if comments[0].CreatedAt.IsZero() {
+		t.Error("Expected created time to be parsed")
+	}
+}
+
 func TestReopenTicket_Success(t *testing.T) {
 	callOrder := []string{}
 
@@ -322,6 +980,49 @@ func TestCloseTicket_Success(t *testing.T) {
 	}
 }
 
+func TestCloseTicket_ConfiguredTransition(t *testing.T) {
+	var transitioned string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/3/issue/PROJ-123/transitions" && r.Method == http.MethodGet {
+			response := jiraTransitionsResponse{
+				Transitions: []jiraTransition{
+					{ID: "1", Name: "Done", To: struct{ Name string `json:"name"` }{Name: "Done"}},
+					{ID: "2", Name: "Mitigated", To: struct{ Name string `json:"name"` }{Name: "Mitigated"}},
+				},
+			}
+			json.NewEncoder(w).Encode(response)
+		} else if r.URL.Path == "/rest/api/3/issue/PROJ-123/transitions" && r.Method == http.MethodPost {
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			transitioned = body.Transition.ID
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	jira := NewJiraTicketSystem(server.URL, "user@test.com", "token", "PROJ", "")
+	for _, configured := range []string{"mitigated", "2"} {
+		transitioned = ""
+		jira.SetWorkflow(Workflow{CloseTransition: configured})
+		if err := jira.CloseTicket("PROJ-123", ""); err != nil {
+			t.Fatalf("CloseTicket() failed: %v", err)
+		}
+		if transitioned != "2" {
+			t.Errorf("Expected transition 2 for %q, got %q", configured, transitioned)
+		}
+	}
+
+	// A configured transition is never replaced by a guess
+	jira.SetWorkflow(Workflow{CloseTransition: "Archive"})
+	if err := jira.CloseTicket("PROJ-123", ""); err == nil {
+		t.Error("Expected error when the configured transition is not available")
+	}
+}
+
 func TestIsResolved(t *testing.T) {
 	jira := NewJiraTicketSystem("http://test.com", "user", "token", "PROJ", "")
 
@@ -427,6 +1128,27 @@ func TestMapJiraStatus(t *testing.T) {
 	}
 }
 
+func TestMapJiraStatus_Workflow(t *testing.T) {
+	jira := NewJiraTicketSystem("http://test.com", "user", "token", "PROJ", "")
+	jira.SetWorkflow(Workflow{Statuses: map[string]TicketStatus{
+		"triage":    StatusOpen,
+		"won't fix": StatusClosed,
+		"reopened":  StatusReopened,
+	}})
+
+	tests := map[string]TicketStatus{
+		"Triage":      StatusOpen,
+		"Won't Fix":   StatusClosed,
+		"Reopened":    StatusReopened,
+		"In Progress": StatusInProgress, // unmapped statuses fall back to common names
+	}
+	for jiraStatus, expected := range tests {
+		if result := jira.mapJiraStatus(jiraStatus); result != expected {
+			t.Errorf("Expected %v for status '%s', got %v", expected, jiraStatus, result)
+		}
+	}
+}
+
 func TestExtractSilenceRef(t *testing.T) {
 	jira := NewJiraTicketSystem("http://test.com", "user", "token", "PROJ", "silence-manager")
 
@@ -489,17 +1211,18 @@ func TestExtractDescriptionText(t *testing.T) {
 	desc := &jiraDescription{
 		Type:    "doc",
 		Version: 1,
-		Content: []jiraDescriptionContent{
+		Content: []jiraNode{
 			{
 				Type: "paragraph",
-				Content: []jiraDescriptionParagraph{
+				Content: []jiraNode{
 					{Type: "text", Text: "First line"},
+					{Type: "hardBreak"},
 					{Type: "text", Text: "Second line"},
 				},
 			},
 			{
 				Type: "paragraph",
-				Content: []jiraDescriptionParagraph{
+				Content: []jiraNode{
 					{Type: "text", Text: "Third line"},
 				},
 			},
@@ -581,3 +1304,31 @@ func TestConvertToJiraIssue_WithoutSilenceRef(t *testing.T) {
 		t.Errorf("Expected description 'Original description', got '%s'", descText)
 	}
 }
+
+func TestConvertToJiraIssue_ReplacesSilenceRef(t *testing.T) {
+	jira := NewJiraTicketSystem("http://test.com", "user", "token", "PROJ", "silence-manager")
+
+	tests := []struct {
+		name       string
+		silenceRef string
+		expected   string
+	}{
+		{"Relinked", "silence-456", "silence-manager: silence-456\n\nOriginal description"},
+		{"Unlinked", "", "Original description"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := &Ticket{
+				Summary:     "Test ticket",
+				Description: "silence-manager: silence-123\n\nOriginal description",
+				SilenceRef:  tt.silenceRef,
+			}
+
+			descText := jira.extractDescriptionText(jira.convertToJiraIssue(ticket).Fields.Description)
+			if descText != tt.expected {
+				t.Errorf("Expected description '%s', got '%s'", tt.expected, descText)
+			}
+		})
+	}
+}
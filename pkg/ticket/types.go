@@ -1,6 +1,10 @@
 package ticket
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // TicketStatus represents the status of a ticket
 type TicketStatus string
@@ -13,6 +17,15 @@ const (
 	StatusReopened   TicketStatus = "reopened"
 )
 
+// ParseStatus parses a ticket status name such as "open" or "in_progress"
+func ParseStatus(value string) (TicketStatus, error) {
+	switch status := TicketStatus(value); status {
+	case StatusOpen, StatusInProgress, StatusResolved, StatusClosed, StatusReopened:
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown ticket status %q (must be open, in_progress, resolved, closed or reopened)", value)
+}
+
 // Ticket represents a ticket in a ticket tracking system
 type Ticket struct {
 	ID          string
@@ -25,6 +38,93 @@ type Ticket struct {
 	SilenceRef  string // Reference to the associated silence ID
 	Labels      []string
 	Assignee    string
+	Priority    string    // Priority name, e.g. "High"
+	DueDate     time.Time // Zero if the ticket has no due date
+	Parent      string    // Key of the parent ticket of a sub-task
+	Project     string    // Project to create the ticket in; the configured project when empty
+	// CustomFields holds the values of configured system-specific fields, keyed by field ID
+	CustomFields map[string]string
+	// SLAs holds the state of the ticket's configured service level agreements
+	SLAs []SLA
+}
+
+// SLA is the state of a service level agreement on a ticket, such as Jira Service
+// Management's "Time to resolution"
+type SLA struct {
+	Name       string
+	Ongoing    bool          // False once the SLA's last cycle has completed
+	Breached   bool          // Whether the ongoing or last completed cycle breached its goal
+	Paused     bool          // Whether the ongoing cycle's clock is stopped
+	BreachTime time.Time     // When the ongoing cycle breaches; zero if unknown
+	Remaining  time.Duration // Time left in the ongoing cycle; negative once breached
+}
+
+// String summarises the SLA, e.g. "Time to resolution: 3h left"
+func (s SLA) String() string {
+	switch {
+	case !s.Ongoing && s.Breached:
+		return s.Name + ": breached"
+	case !s.Ongoing:
+		return s.Name + ": met"
+	case s.Breached:
+		return fmt.Sprintf("%s: breached %s ago", s.Name, formatSLADuration(-s.Remaining))
+	case s.Paused:
+		return fmt.Sprintf("%s: paused, %s left", s.Name, formatSLADuration(s.Remaining))
+	}
+	return fmt.Sprintf("%s: %s left", s.Name, formatSLADuration(s.Remaining))
+}
+
+// formatSLADuration renders a duration to the minute, e.g. "3h0m" becomes "3h"
+func formatSLADuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d == 0 {
+		return "0m"
+	}
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// UrgentSLA returns the ticket's most urgent SLA: a breached ongoing one, else the
+// running one closest to breaching, else a paused one. It returns nil if no SLA is ongoing.
+func (t *Ticket) UrgentSLA() *SLA {
+	var urgent *SLA
+	rank := func(s *SLA) int {
+		switch {
+		case s.Breached:
+			return 0
+		case !s.Paused:
+			return 1
+		}
+		return 2
+	}
+	for i := range t.SLAs {
+		s := &t.SLAs[i]
+		if !s.Ongoing {
+			continue
+		}
+		if urgent == nil || rank(s) < rank(urgent) || rank(s) == rank(urgent) && s.Remaining < urgent.Remaining {
+			urgent = s
+		}
+	}
+	return urgent
+}
+
+// Comment represents a comment posted on a ticket
+type Comment struct {
+	ID        string
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// RemoteLink is a link from a ticket to a resource in another system
+type RemoteLink struct {
+	GlobalID string // Identifies the link; setting a link with the same GlobalID replaces it
+	URL      string
+	Title    string
 }
 
 // TicketSystem is the interface that all ticket system implementations must satisfy
@@ -32,12 +132,46 @@ type TicketSystem interface {
 	// GetTicket retrieves a ticket by its key
 	GetTicket(key string) (*Ticket, error)
 
+	// GetTickets retrieves several tickets at once, keyed by ticket key. Implementations
+	// may fail the whole lookup if any key does not exist.
+	GetTickets(keys []string) (map[string]*Ticket, error)
+
+	// SearchTickets returns all tickets matching a query in the system's query language,
+	// e.g. JQL for Jira
+	SearchTickets(query string) ([]*Ticket, error)
+
 	// CreateTicket creates a new ticket and returns its key
 	CreateTicket(ticket *Ticket) (string, error)
 
 	// UpdateTicket updates an existing ticket
 	UpdateTicket(ticket *Ticket) error
 
+	// UpdateLabels adds and removes labels on a ticket without touching its other fields
+	UpdateLabels(key string, add, remove []string) error
+
+	// SetPriority changes the priority of a ticket without touching its other fields
+	SetPriority(key string, priority string) error
+
+	// SetDueDate sets the due date of a ticket without touching its other fields
+	SetDueDate(key string, due time.Time) error
+
+	// AssignTicket assigns a ticket to a user, given as an account ID or an email address
+	AssignTicket(key string, user string) error
+
+	// AddWatcher adds a user, given as an account ID or an email address, to the watchers
+	// of a ticket. Adding an existing watcher is a no-op.
+	AddWatcher(key string, user string) error
+
+	// SetRemoteLink adds a link to an external resource, replacing any link with the same GlobalID
+	SetRemoteLink(key string, link RemoteLink) error
+
+	// DeleteRemoteLink removes the link with the given GlobalID, if present
+	DeleteRemoteLink(key string, globalID string) error
+
+	// LinkTickets links two tickets with a link of the given type, e.g. "Relates". The
+	// link reads "<key> <outward description> <linkedKey>", e.g. "PROJ-1 causes PROJ-2".
+	LinkTickets(key, linkedKey, linkType string) error
+
 	// ReopenTicket reopens a closed/resolved ticket
 	ReopenTicket(key string, comment string) error
 
@@ -47,6 +181,9 @@ type TicketSystem interface {
 	// AddComment adds a comment to a ticket
 	AddComment(key string, comment string) error
 
+	// GetComments returns all comments on a ticket, oldest first
+	GetComments(key string) ([]*Comment, error)
+
 	// IsResolved checks if a ticket is in a resolved state
 	IsResolved(ticket *Ticket) bool
 
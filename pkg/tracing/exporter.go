@@ -0,0 +1,214 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// exportTimeout bounds each export request
+const exportTimeout = 10 * time.Second
+
+// exporter sends spans to a collector with OTLP over HTTP, encoded as protobuf
+type exporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// newExporter creates an exporter for the collector at cfg.URL, which is a host and port
+// like the OTel metrics URL or a full URL. The /v1/traces path is added unless the URL
+// has a path.
+func newExporter(cfg Config) (*exporter, error) {
+	endpoint := cfg.URL
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https://"
+		if cfg.Insecure {
+			scheme = "http://"
+		}
+		endpoint = scheme + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid trace collector URL %q", cfg.URL)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSConfig != nil {
+		transport.TLSClientConfig = cfg.TLSConfig
+	}
+	return &exporter{
+		endpoint: u.String(),
+		client:   &http.Client{Transport: transport, Timeout: exportTimeout},
+	}, nil
+}
+
+// ExportSpans sends a batch of spans to the collector
+func (e *exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := proto.Marshal(encodeSpans(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d exporting spans: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// Shutdown releases the idle connections of the exporter
+func (e *exporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// encodeSpans converts spans to an OTLP export request, grouping them by instrumentation
+// scope. All spans of a provider share its resource.
+func encodeSpans(spans []sdktrace.ReadOnlySpan) *coltracepb.ExportTraceServiceRequest {
+	resourceSpans := &tracepb.ResourceSpans{Resource: &resourcepb.Resource{}}
+	if res := spans[0].Resource(); res != nil {
+		resourceSpans.Resource.Attributes = encodeAttributes(res.Attributes())
+		resourceSpans.SchemaUrl = res.SchemaURL()
+	}
+
+	scopes := make(map[string]*tracepb.ScopeSpans)
+	for _, span := range spans {
+		scope := span.InstrumentationScope()
+		scopeSpans, ok := scopes[scope.Name+"@"+scope.Version]
+		if !ok {
+			scopeSpans = &tracepb.ScopeSpans{
+				Scope:     &commonpb.InstrumentationScope{Name: scope.Name, Version: scope.Version},
+				SchemaUrl: scope.SchemaURL,
+			}
+			scopes[scope.Name+"@"+scope.Version] = scopeSpans
+			resourceSpans.ScopeSpans = append(resourceSpans.ScopeSpans, scopeSpans)
+		}
+		scopeSpans.Spans = append(scopeSpans.Spans, encodeSpan(span))
+	}
+
+	return &coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{resourceSpans}}
+}
+
+// encodeSpan converts a span to its OTLP representation
+func encodeSpan(span sdktrace.ReadOnlySpan) *tracepb.Span {
+	sc := span.SpanContext()
+	traceID, spanID := sc.TraceID(), sc.SpanID()
+	encoded := &tracepb.Span{
+		TraceId:                traceID[:],
+		SpanId:                 spanID[:],
+		TraceState:             sc.TraceState().String(),
+		Flags:                  uint32(sc.TraceFlags()),
+		Name:                   span.Name(),
+		Kind:                   tracepb.Span_SpanKind(span.SpanKind()),
+		StartTimeUnixNano:      uint64(span.StartTime().UnixNano()),
+		EndTimeUnixNano:        uint64(span.EndTime().UnixNano()),
+		Attributes:             encodeAttributes(span.Attributes()),
+		DroppedAttributesCount: uint32(span.DroppedAttributes()),
+		DroppedEventsCount:     uint32(span.DroppedEvents()),
+		DroppedLinksCount:      uint32(span.DroppedLinks()),
+		Status:                 &tracepb.Status{Message: span.Status().Description},
+	}
+	if parent := span.Parent(); parent.SpanID().IsValid() {
+		parentID := parent.SpanID()
+		encoded.ParentSpanId = parentID[:]
+	}
+	switch span.Status().Code {
+	case codes.Ok:
+		encoded.Status.Code = tracepb.Status_STATUS_CODE_OK
+	case codes.Error:
+		encoded.Status.Code = tracepb.Status_STATUS_CODE_ERROR
+	}
+	for _, event := range span.Events() {
+		encoded.Events = append(encoded.Events, &tracepb.Span_Event{
+			TimeUnixNano:           uint64(event.Time.UnixNano()),
+			Name:                   event.Name,
+			Attributes:             encodeAttributes(event.Attributes),
+			DroppedAttributesCount: uint32(event.DroppedAttributeCount),
+		})
+	}
+	for _, link := range span.Links() {
+		traceID, spanID := link.SpanContext.TraceID(), link.SpanContext.SpanID()
+		encoded.Links = append(encoded.Links, &tracepb.Span_Link{
+			TraceId:                traceID[:],
+			SpanId:                 spanID[:],
+			TraceState:             link.SpanContext.TraceState().String(),
+			Attributes:             encodeAttributes(link.Attributes),
+			DroppedAttributesCount: uint32(link.DroppedAttributeCount),
+		})
+	}
+	return encoded
+}
+
+// encodeAttributes converts attributes to their OTLP representation
+func encodeAttributes(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	encoded := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		encoded = append(encoded, &commonpb.KeyValue{Key: string(attr.Key), Value: encodeValue(attr.Value)})
+	}
+	return encoded
+}
+
+// encodeValue converts an attribute value to its OTLP representation
+func encodeValue(value attribute.Value) *commonpb.AnyValue {
+	switch value.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: value.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: value.AsFloat64()}}
+	case attribute.STRING:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value.AsString()}}
+	case attribute.BOOLSLICE:
+		return encodeSlice(value.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return encodeSlice(value.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return encodeSlice(value.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return encodeSlice(value.AsStringSlice(), attribute.StringValue)
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value.Emit()}}
+	}
+}
+
+// encodeSlice converts the elements of a slice attribute to an OTLP array
+func encodeSlice[T any](elements []T, toValue func(T) attribute.Value) *commonpb.AnyValue {
+	values := make([]*commonpb.AnyValue, 0, len(elements))
+	for _, element := range elements {
+		values = append(values, encodeValue(toValue(element)))
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+}
@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewExporter_Endpoint(t *testing.T) {
+	tests := []struct {
+		url      string
+		insecure bool
+		want     string
+	}{
+		{"otel-collector:4318", true, "http://otel-collector:4318/v1/traces"},
+		{"otel-collector:4318", false, "https://otel-collector:4318/v1/traces"},
+		{"https://collector.example.com", true, "https://collector.example.com/v1/traces"},
+		{"http://collector:4318/custom/traces", false, "http://collector:4318/custom/traces"},
+	}
+	for _, tt := range tests {
+		e, err := newExporter(Config{URL: tt.url, Insecure: tt.insecure})
+		if err != nil {
+			t.Fatalf("newExporter(%q) failed: %v", tt.url, err)
+		}
+		if e.endpoint != tt.want {
+			t.Errorf("newExporter(%q) endpoint = %q, want %q", tt.url, e.endpoint, tt.want)
+		}
+	}
+
+	if _, err := newExporter(Config{URL: "http://"}); err == nil {
+		t.Error("Expected an error for a URL without a host")
+	}
+}
+
+func TestExporter_ExportSpans(t *testing.T) {
+	var spans []*tracepb.Span
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("Unexpected request to %s with content type %q", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		for _, resourceSpans := range req.ResourceSpans {
+			for _, scopeSpans := range resourceSpans.ScopeSpans {
+				spans = append(spans, scopeSpans.Spans...)
+			}
+		}
+	}))
+	defer server.Close()
+
+	exporter, err := newExporter(Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("newExporter() failed: %v", err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "sync")
+	_, child := tracer.Start(ctx, "silence")
+	child.SetAttributes(attribute.String("silence.id", "silence-1"), attribute.StringSlice("labels", []string{"a", "b"}))
+	child.SetStatus(codes.Error, "ticket not found")
+	child.End()
+	root.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+
+	if len(spans) != 2 || spans[0].Name != "silence" || spans[1].Name != "sync" {
+		t.Fatalf("Expected the silence and sync spans, got %v", spans)
+	}
+	span := spans[0]
+	if !bytes.Equal(span.ParentSpanId, spans[1].SpanId) || !bytes.Equal(span.TraceId, spans[1].TraceId) {
+		t.Errorf("Expected the silence span under the sync span, got parent %x", span.ParentSpanId)
+	}
+	if len(span.Attributes) != 2 || span.Attributes[0].Value.GetStringValue() != "silence-1" ||
+		len(span.Attributes[1].Value.GetArrayValue().GetValues()) != 2 {
+		t.Errorf("Unexpected attributes: %v", span.Attributes)
+	}
+	if span.Status.Code != tracepb.Status_STATUS_CODE_ERROR || span.Status.Message != "ticket not found" {
+		t.Errorf("Unexpected status: %v", span.Status)
+	}
+}
+
+func TestExporter_ExportSpansError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exporter, err := newExporter(Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("newExporter() failed: %v", err)
+	}
+	spans := tracetest.SpanStubs{{Name: "sync"}}.Snapshots()
+	if err := exporter.ExportSpans(context.Background(), spans); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected a 503 export error, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// Config holds configuration for exporting traces
+type Config struct {
+	URL      string // OTLP/HTTP collector endpoint, e.g. otel-collector:4318
+	Insecure bool   // Use plain HTTP instead of HTTPS
+	// TLSConfig, when set, is used for HTTPS connections instead of the defaults
+	TLSConfig *tls.Config
+	Version   string // Reported as the service version of every span
+}
+
+// Start installs a global tracer provider exporting the spans of the process to the
+// collector at cfg.URL. Spans are exported in batches; the returned function exports
+// the remaining spans and stops the provider.
+func Start(cfg Config) (func(context.Context) error, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("trace collector URL is required")
+	}
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String("silence-manager"),
+			semconv.ServiceVersionKey.String(cfg.Version),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exporter),
+	)
+	otel.SetTracerProvider(provider)
+
+	log.Printf("Initialized OpenTelemetry trace exporter: url=%s, insecure=%v", exporter.endpoint, cfg.Insecure)
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+const (
+	// requestTimeout bounds how long filing the alerts of one notification may take
+	requestTimeout = 2 * time.Minute
+
+	// maxBodySize bounds the size of a notification
+	maxBodySize = 1 << 20
+)
+
+// Backend files the tickets and silences of alerts
+type Backend interface {
+	// Create creates a ticket and a silence linked to it
+	Create(ctx context.Context, def sync.SilenceDefinition) (*alertmanager.Silence, error)
+}
+
+// Message is a notification of the Alertmanager webhook receiver (version 4)
+type Message struct {
+	Version     string  `json:"version"`
+	Status      string  `json:"status"`
+	Receiver    string  `json:"receiver"`
+	ExternalURL string  `json:"externalURL"`
+	Alerts      []Alert `json:"alerts"`
+}
+
+// Alert is an alert of a webhook notification
+type Alert struct {
+	Status       string            `json:"status"` // "firing" or "resolved"
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Handler receives Alertmanager webhook notifications and files a ticket for every firing
+// alert carrying the file label, with a silence matching exactly that alert linked to it.
+// Silencing the alert stops the notifications, and the silence follows the lifecycle of
+// its ticket from then on. Notifications must carry the configured bearer token.
+type Handler struct {
+	backend    Backend
+	token      []byte
+	labelName  string
+	labelValue string
+}
+
+// NewHandler creates a webhook receiver filing alerts whose label labelName has labelValue
+func NewHandler(backend Backend, token, labelName, labelValue string) *Handler {
+	return &Handler{backend: backend, token: []byte(token), labelName: labelName, labelValue: labelValue}
+}
+
+// Filed is a ticket and silence filed for an alert
+type Filed struct {
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Ticket      string `json:"ticket"`
+	SilenceID   string `json:"silenceID"`
+}
+
+// response reports what was done with a notification
+type response struct {
+	Filed   []Filed  `json:"filed"`
+	Skipped int      `json:"skipped"` // Alerts without the label, resolved or already silenced
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// ServeHTTP handles a notification. It fails with 500 when an alert could not be filed,
+// so that Alertmanager retries; alerts filed meanwhile are skipped on the retry, as their
+// silence then exists.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), h.token) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid token"})
+		return
+	}
+
+	var msg Message
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&msg); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid notification: %v", err)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp := response{Filed: []Filed{}}
+	for _, alert := range msg.Alerts {
+		if alert.Status != "firing" || alert.Labels[h.labelName] != h.labelValue {
+			resp.Skipped++
+			continue
+		}
+		silence, err := h.backend.Create(ctx, definition(alert, msg.ExternalURL))
+		if errors.Is(err, sync.ErrSilenceExists) {
+			resp.Skipped++
+			continue
+		}
+		// Retrying would not help while creating silences is switched off
+		if errors.Is(err, sync.ErrActionDisabled) {
+			log.Printf("Not filing alert %s: %v", alertName(alert), err)
+			resp.Skipped++
+			continue
+		}
+		if err != nil {
+			log.Printf("Error filing alert %s: %v", alertName(alert), err)
+			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %v", alertName(alert), err))
+			continue
+		}
+		log.Printf("Filed ticket %s with silence %s for alert %s", silence.TicketRef, silence.ID, alertName(alert))
+		resp.Filed = append(resp.Filed, Filed{Fingerprint: alert.Fingerprint, Ticket: silence.TicketRef, SilenceID: silence.ID})
+	}
+
+	status := http.StatusOK
+	if len(resp.Errors) > 0 {
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, resp)
+}
+
+// definition describes the ticket and silence filed for an alert. The silence matches all
+// labels of the alert, so it mutes that alert only.
+func definition(alert Alert, externalURL string) sync.SilenceDefinition {
+	names := make([]string, 0, len(alert.Labels))
+	for name := range alert.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	matchers := make([]alertmanager.Matcher, 0, len(names))
+	var labels strings.Builder
+	for _, name := range names {
+		matchers = append(matchers, alertmanager.Matcher{Name: name, Value: alert.Labels[name], IsEqual: true})
+		fmt.Fprintf(&labels, "* %s=%s\n", name, alert.Labels[name])
+	}
+
+	summary := alert.Annotations["summary"]
+	if summary == "" {
+		summary = "Alert: " + alertName(alert)
+	}
+
+	var description strings.Builder
+	fmt.Fprintf(&description, "Alert %s firing since %s.\n", alertName(alert), alert.StartsAt.UTC().Format(time.RFC3339))
+	if text := alert.Annotations["description"]; text != "" {
+		fmt.Fprintf(&description, "\n%s\n", text)
+	}
+	fmt.Fprintf(&description, "\nLabels:\n%s", labels.String())
+	if alert.Annotations["runbook_url"] != "" {
+		fmt.Fprintf(&description, "\nRunbook: %s\n", alert.Annotations["runbook_url"])
+	}
+	if alert.GeneratorURL != "" {
+		fmt.Fprintf(&description, "Source: %s\n", alert.GeneratorURL)
+	}
+	if externalURL != "" {
+		fmt.Fprintf(&description, "Alertmanager: %s\n", externalURL)
+	}
+
+	return sync.SilenceDefinition{
+		Matchers:    matchers,
+		Comment:     fmt.Sprintf("Filed from alert %s", alertName(alert)),
+		CreatedBy:   "silence-manager (webhook)",
+		Summary:     summary,
+		Description: description.String(),
+	}
+}
+
+func alertName(alert Alert) string {
+	if name := alert.Labels["alertname"]; name != "" {
+		return name
+	}
+	return alert.Fingerprint
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Warning: failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+const testToken = "webhook-token"
+
+// fakeBackend records the silences created through the handler
+type fakeBackend struct {
+	created []sync.SilenceDefinition
+	err     error
+}
+
+func (f *fakeBackend) Create(ctx context.Context, def sync.SilenceDefinition) (*alertmanager.Silence, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.created = append(f.created, def)
+	return &alertmanager.Silence{ID: fmt.Sprintf("silence-%d", len(f.created)), TicketRef: fmt.Sprintf("OPS-%d", len(f.created))}, nil
+}
+
+const notification = `{
+  "version": "4",
+  "status": "firing",
+  "externalURL": "http://alertmanager.example.com",
+  "alerts": [
+    {"status": "firing", "fingerprint": "a1", "startsAt": "2025-06-01T09:00:00Z", "generatorURL": "http://prometheus.example.com/graph",
+     "labels": {"alertname": "DiskFull", "instance": "db-1", "file_ticket": "true"},
+     "annotations": {"summary": "Disk of db-1 is full", "description": "Less than 1% left.", "runbook_url": "https://runbooks.example.com/disk"}},
+    {"status": "firing", "fingerprint": "a2", "labels": {"alertname": "HighLatency"}},
+    {"status": "resolved", "fingerprint": "a3", "labels": {"alertname": "DiskFull", "instance": "db-2", "file_ticket": "true"}}
+  ]
+}`
+
+func post(t *testing.T, handler http.Handler, token, body string) (int, response) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/alertmanager/webhook", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var resp response
+	json.NewDecoder(rec.Body).Decode(&resp)
+	return rec.Code, resp
+}
+
+func TestHandler_FilesLabelledAlerts(t *testing.T) {
+	backend := &fakeBackend{}
+	handler := NewHandler(backend, testToken, "file_ticket", "true")
+
+	status, resp := post(t, handler, testToken, notification)
+	if status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if len(resp.Filed) != 1 || resp.Filed[0].Ticket != "OPS-1" || resp.Filed[0].Fingerprint != "a1" || resp.Skipped != 2 {
+		t.Fatalf("Expected one filed and two skipped alerts, got %+v", resp)
+	}
+
+	def := backend.created[0]
+	if len(def.Matchers) != 3 || def.Matchers[0].Name != "alertname" || def.Matchers[2].Name != "instance" {
+		t.Errorf("Expected exact matchers on all labels, got %+v", def.Matchers)
+	}
+	if def.Summary != "Disk of db-1 is full" {
+		t.Errorf("Expected the summary annotation as ticket summary, got %q", def.Summary)
+	}
+	for _, want := range []string{"Less than 1% left.", "* instance=db-1", "Runbook: https://runbooks.example.com/disk", "Source: http://prometheus.example.com/graph", "Alertmanager: http://alertmanager.example.com"} {
+		if !strings.Contains(def.Description, want) {
+			t.Errorf("Expected description to contain %q, got:\n%s", want, def.Description)
+		}
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	backend := &fakeBackend{}
+	handler := NewHandler(backend, testToken, "file_ticket", "true")
+
+	if status, _ := post(t, handler, "wrong", notification); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong token, got %d", status)
+	}
+	if status, _ := post(t, handler, testToken, "{"); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid body, got %d", status)
+	}
+
+	// Alerts already silenced are skipped, e.g. on a repeated notification
+	backend.err = fmt.Errorf("%w: silence 1 already matches", sync.ErrSilenceExists)
+	if status, resp := post(t, handler, testToken, notification); status != http.StatusOK || resp.Skipped != 3 {
+		t.Errorf("Expected 200 with all alerts skipped, got %d: %+v", status, resp)
+	}
+
+	// Nor are they filed while creating silences is switched off
+	backend.err = sync.ErrActionDisabled
+	if status, resp := post(t, handler, testToken, notification); status != http.StatusOK || resp.Skipped != 3 {
+		t.Errorf("Expected 200 with all alerts skipped, got %d: %+v", status, resp)
+	}
+
+	// Failures are retried by Alertmanager
+	backend.err = errors.New("jira unavailable")
+	if status, resp := post(t, handler, testToken, notification); status != http.StatusInternalServerError || len(resp.Errors) != 1 {
+		t.Errorf("Expected 500 with one error, got %d: %+v", status, resp)
+	}
+}
@@ -0,0 +1,59 @@
+package webui
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultJournalSize is the number of entries kept by a journal
+const DefaultJournalSize = 200
+
+// Entry is an action taken by a synchronization run or from the web UI
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`            // e.g. "sync run" or "web UI (operator)"
+	Action string    `json:"action"`           // e.g. "extend", "delete", "link" or "sync"
+	Target string    `json:"target,omitempty"` // Silence ID, if the action concerns one silence
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Journal keeps the most recent entries in memory
+type Journal struct {
+	mu      sync.Mutex
+	size    int
+	entries []Entry
+}
+
+// NewJournal creates a journal keeping the latest size entries
+func NewJournal(size int) *Journal {
+	if size <= 0 {
+		size = DefaultJournalSize
+	}
+	return &Journal{size: size}
+}
+
+// Record adds an entry, dropping the oldest entry when the journal is full
+func (j *Journal) Record(entry Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, entry)
+	if len(j.entries) > j.size {
+		j.entries = j.entries[len(j.entries)-j.size:]
+	}
+}
+
+// Recent returns the entries, newest first
+func (j *Journal) Recent() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	recent := make([]Entry, 0, len(j.entries))
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		recent = append(recent, j.entries[i])
+	}
+	return recent
+}
@@ -0,0 +1,236 @@
+// Dashboard of silence-manager's web UI. Data is fetched from /ui/api/ with the bearer
+// token entered on sign-in, kept in sessionStorage for this tab only.
+"use strict";
+
+const refreshInterval = 30000;
+const expiringWithin = 24 * 3600 * 1000;
+
+let config = null;
+let silences = [];
+
+function token() {
+  return sessionStorage.getItem("silence-manager-token");
+}
+
+async function request(method, path, body) {
+  const options = { method, headers: { Authorization: "Bearer " + token() } };
+  if (body !== undefined) {
+    options.headers["Content-Type"] = "application/json";
+    options.body = JSON.stringify(body);
+  }
+  const resp = await fetch("api/" + path, options);
+  if (resp.status === 401) {
+    signOut();
+    throw new Error("The token was rejected");
+  }
+  if (!resp.ok) {
+    const error = await resp.json().catch(() => ({}));
+    throw new Error(error.error || resp.statusText);
+  }
+  return resp.status === 204 ? null : resp.json();
+}
+
+function showMessage(text, isError) {
+  const message = document.getElementById("message");
+  message.textContent = text;
+  message.className = isError ? "error" : "";
+}
+
+function element(tag, text, className) {
+  const el = document.createElement(tag);
+  if (text !== undefined) {
+    el.textContent = text;
+  }
+  if (className) {
+    el.className = className;
+  }
+  return el;
+}
+
+function countdown(endsAt) {
+  let ms = new Date(endsAt) - Date.now();
+  if (ms <= 0) {
+    return "expired";
+  }
+  const days = Math.floor(ms / 86400000);
+  ms %= 86400000;
+  const hours = Math.floor(ms / 3600000);
+  ms %= 3600000;
+  const minutes = Math.floor(ms / 60000);
+  const seconds = Math.floor((ms % 60000) / 1000);
+  if (days > 0) {
+    return `${days}d ${hours}h ${minutes}m`;
+  }
+  return `${hours}h ${minutes}m ${seconds}s`;
+}
+
+function ticketCell(ref) {
+  const cell = element("td");
+  if (!ref) {
+    cell.textContent = "-";
+  } else if (config.ticketURL) {
+    const link = element("a", ref);
+    link.href = config.ticketURL + encodeURIComponent(ref);
+    link.target = "_blank";
+    link.rel = "noopener";
+    cell.appendChild(link);
+  } else {
+    cell.textContent = ref;
+  }
+  return cell;
+}
+
+function actionButton(label, handler) {
+  const button = element("button", label);
+  button.type = "button";
+  button.addEventListener("click", async () => {
+    button.disabled = true;
+    try {
+      await handler();
+      await refresh();
+    } catch (err) {
+      showMessage(err.message, true);
+    } finally {
+      button.disabled = false;
+    }
+  });
+  return button;
+}
+
+function actionsCell(silence) {
+  const cell = element("td", undefined, "actions");
+  if (!config.canWrite) {
+    return cell;
+  }
+  const path = "silences/" + encodeURIComponent(silence.silenceID);
+  cell.appendChild(actionButton("Extend", async () => {
+    const duration = prompt("Extend to how long from now? (e.g. 4h or 7d)", "7d");
+    if (duration) {
+      await request("POST", path + "/extend", { duration });
+      showMessage(`Extended silence ${silence.silenceID}`);
+    }
+  }));
+  if (!silence.ticketRef) {
+    cell.appendChild(actionButton("Link", async () => {
+      const ticket = prompt("Link to which ticket? (e.g. OPS-123)");
+      if (ticket) {
+        await request("PUT", path + "/ticket", { ticket: ticket.trim() });
+        showMessage(`Linked silence ${silence.silenceID} to ${ticket.trim()}`);
+      }
+    }));
+  }
+  cell.appendChild(actionButton("Delete", async () => {
+    if (confirm(`Delete silence ${silence.silenceID}? Its alerts will notify again.`)) {
+      await request("DELETE", path);
+      showMessage(`Deleted silence ${silence.silenceID}`);
+    }
+  }));
+  return cell;
+}
+
+function renderSilences() {
+  const body = document.getElementById("silences");
+  body.replaceChildren();
+  document.getElementById("count").textContent = `(${silences.length})`;
+  for (const silence of silences) {
+    const row = element("tr");
+    row.dataset.endsAt = silence.endsAt;
+    row.appendChild(element("td", silence.silenceID.slice(0, 8), "id")).title = silence.silenceID;
+    const matchers = element("td");
+    matchers.appendChild(element("code", (silence.matchers || []).join(" ")));
+    row.appendChild(matchers);
+    row.appendChild(ticketCell(silence.ticketRef));
+    row.appendChild(element("td", silence.ticketStatus || "-"));
+    row.appendChild(element("td", countdown(silence.endsAt), "expires"));
+    let next = silence.action;
+    if (silence.reason) {
+      next += ` (${silence.reason})`;
+    }
+    row.appendChild(element("td", next));
+    row.appendChild(actionsCell(silence));
+    body.appendChild(row);
+  }
+  tick();
+}
+
+function renderJournal(entries) {
+  const list = document.getElementById("journal");
+  list.replaceChildren();
+  if (entries.length === 0) {
+    list.appendChild(element("li", "No actions since the daemon started."));
+  }
+  for (const entry of entries) {
+    const item = element("li");
+    const time = element("time", new Date(entry.time).toLocaleString());
+    time.dateTime = entry.time;
+    item.appendChild(time);
+    let text = `${entry.actor}: ${entry.action}`;
+    if (entry.target) {
+      text += ` ${entry.target}`;
+    }
+    if (entry.detail) {
+      text += ` ${entry.detail}`;
+    }
+    item.appendChild(document.createTextNode(text));
+    list.appendChild(item);
+  }
+}
+
+// tick updates the countdowns every second without refetching
+function tick() {
+  for (const row of document.querySelectorAll("#silences tr")) {
+    const remaining = new Date(row.dataset.endsAt) - Date.now();
+    row.querySelector(".expires").textContent = countdown(row.dataset.endsAt);
+    row.classList.toggle("expiring", remaining < expiringWithin);
+  }
+}
+
+async function refresh() {
+  try {
+    const [list, journal] = await Promise.all([request("GET", "silences"), request("GET", "journal")]);
+    silences = list;
+    renderSilences();
+    renderJournal(journal);
+  } catch (err) {
+    showMessage(err.message, true);
+  }
+}
+
+async function signIn() {
+  try {
+    config = await request("GET", "config");
+  } catch (err) {
+    showMessage(err.message, true);
+    return;
+  }
+  document.getElementById("login").hidden = true;
+  document.getElementById("dashboard").hidden = false;
+  document.getElementById("logout").hidden = false;
+  document.getElementById("role").textContent = "Role: " + config.role;
+  await refresh();
+}
+
+function signOut() {
+  sessionStorage.removeItem("silence-manager-token");
+  config = null;
+  document.getElementById("login").hidden = false;
+  document.getElementById("dashboard").hidden = true;
+  document.getElementById("logout").hidden = true;
+  document.getElementById("role").textContent = "";
+}
+
+document.getElementById("login").addEventListener("submit", (event) => {
+  event.preventDefault();
+  sessionStorage.setItem("silence-manager-token", document.getElementById("token").value);
+  signIn();
+});
+document.getElementById("logout").addEventListener("click", signOut);
+
+setInterval(tick, 1000);
+setInterval(() => config && refresh(), refreshInterval);
+
+if (token()) {
+  signIn();
+} else {
+  signOut();
+}
@@ -0,0 +1,220 @@
+package webui
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+//go:embed static
+var static embed.FS
+
+// requestTimeout bounds how long an action may take
+const requestTimeout = time.Minute
+
+// Backend performs the actions of the web UI
+type Backend interface {
+	// List returns the active silences, their tickets and the next action for each
+	List(ctx context.Context) ([]sync.PlannedAction, error)
+
+	// Extend moves the end of a silence to duration from now
+	Extend(ctx context.Context, silenceID string, duration time.Duration) (*alertmanager.Silence, error)
+
+	// Delete expires a silence
+	Delete(ctx context.Context, silenceID string) error
+
+	// Link attaches a silence to a ticket
+	Link(ctx context.Context, silenceID, ticketKey string) error
+}
+
+// Config configures the web UI
+type Config struct {
+	Tokens    map[string]authz.Role // Bearer tokens with their roles
+	TicketURL string                // Prefix of ticket links, e.g. https://example.atlassian.net/browse/
+}
+
+// Handler serves the web UI below /ui/: a page showing the managed silences, their
+// tickets, expiry countdowns and the journal of recent actions, with buttons to extend,
+// delete and link silences. The page itself is static; its data and actions are served
+// under /ui/api/ and need a bearer token, whose role authorizes them as on the REST API.
+type Handler struct {
+	backend Backend
+	journal *Journal
+	config  Config
+	mux     *http.ServeMux
+}
+
+// NewHandler creates the web UI handler. Actions taken from the UI are recorded in journal.
+func NewHandler(backend Backend, journal *Journal, config Config) *Handler {
+	h := &Handler{backend: backend, journal: journal, config: config, mux: http.NewServeMux()}
+
+	assets, _ := fs.Sub(static, "static") // The embedded directory always exists
+	h.mux.Handle("GET /ui/", http.StripPrefix("/ui/", http.FileServer(http.FS(assets))))
+	h.mux.HandleFunc("GET /ui/api/config", h.authorized(authz.OpRead, h.handleConfig))
+	h.mux.HandleFunc("GET /ui/api/silences", h.authorized(authz.OpRead, h.handleList))
+	h.mux.HandleFunc("GET /ui/api/journal", h.authorized(authz.OpRead, h.handleJournal))
+	h.mux.HandleFunc("POST /ui/api/silences/{id}/extend", h.authorized(authz.OpWrite, h.handleExtend))
+	h.mux.HandleFunc("DELETE /ui/api/silences/{id}", h.authorized(authz.OpWrite, h.handleDelete))
+	h.mux.HandleFunc("PUT /ui/api/silences/{id}/ticket", h.authorized(authz.OpWrite, h.handleLink))
+	return h
+}
+
+// ServeHTTP serves the page, its assets and its API
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorized wraps a handler with authentication and authorization of op
+func (h *Handler) authorized(op authz.Operation, handler func(http.ResponseWriter, *http.Request, authz.Role)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		role, ok := authz.BearerRole(r.Header.Get("Authorization"), h.config.Tokens)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		if err := authz.Authorize(role, op, false); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer cancel()
+		handler(w, r.WithContext(ctx), role)
+	}
+}
+
+// uiConfig tells the page what the token may do and how to link tickets
+type uiConfig struct {
+	Role      authz.Role `json:"role"`
+	CanWrite  bool       `json:"canWrite"`
+	TicketURL string     `json:"ticketURL,omitempty"`
+}
+
+func (h *Handler) handleConfig(w http.ResponseWriter, r *http.Request, role authz.Role) {
+	writeJSON(w, http.StatusOK, uiConfig{
+		Role:      role,
+		CanWrite:  authz.Authorize(role, authz.OpWrite, false) == nil,
+		TicketURL: h.config.TicketURL,
+	})
+}
+
+func (h *Handler) handleList(w http.ResponseWriter, r *http.Request, role authz.Role) {
+	silences, err := h.backend.List(r.Context())
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	if silences == nil {
+		silences = []sync.PlannedAction{}
+	}
+	writeJSON(w, http.StatusOK, silences)
+}
+
+func (h *Handler) handleJournal(w http.ResponseWriter, r *http.Request, role authz.Role) {
+	writeJSON(w, http.StatusOK, h.journal.Recent())
+}
+
+// extendRequest is the body of an extend request
+type extendRequest struct {
+	Duration string `json:"duration"`
+}
+
+func (h *Handler) handleExtend(w http.ResponseWriter, r *http.Request, role authz.Role) {
+	var req extendRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	duration, err := sync.ParseDuration(req.Duration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid duration %q: %v", req.Duration, err))
+		return
+	}
+
+	id := r.PathValue("id")
+	silence, err := h.backend.Extend(r.Context(), id, duration)
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	h.record(role, "extend", id, "until "+silence.EndsAt.UTC().Format(time.RFC3339))
+	writeJSON(w, http.StatusOK, silence)
+}
+
+func (h *Handler) handleDelete(w http.ResponseWriter, r *http.Request, role authz.Role) {
+	id := r.PathValue("id")
+	if err := h.backend.Delete(r.Context(), id); err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	h.record(role, "delete", id, "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// linkRequest is the body of a link request
+type linkRequest struct {
+	Ticket string `json:"ticket"`
+}
+
+func (h *Handler) handleLink(w http.ResponseWriter, r *http.Request, role authz.Role) {
+	var req linkRequest
+	if err := decodeBody(w, r, &req); err != nil || req.Ticket == "" {
+		writeError(w, http.StatusBadRequest, errors.New(`expected a body like {"ticket": "OPS-1"}`))
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.backend.Link(r.Context(), id, req.Ticket); err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	h.record(role, "link", id, "to "+req.Ticket)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// record adds an action taken from the UI to the journal
+func (h *Handler) record(role authz.Role, action, silenceID, detail string) {
+	log.Printf("Web UI: %s silence %s %s (role: %s)", action, silenceID, detail, role)
+	h.journal.Record(Entry{Actor: fmt.Sprintf("web UI (%s)", role), Action: action, Target: silenceID, Detail: strings.TrimSpace(detail)})
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// writeBackendError maps an error of the backend to a response status
+func writeBackendError(w http.ResponseWriter, err error) {
+	if errors.Is(err, alertmanager.ErrSilenceNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	log.Printf("Warning: web UI request failed: %v", err)
+	writeError(w, http.StatusBadGateway, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Warning: failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package webui
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conallob/silence-manager/pkg/alertmanager"
+	"github.com/conallob/silence-manager/pkg/authz"
+	"github.com/conallob/silence-manager/pkg/sync"
+)
+
+// fakeBackend records the actions taken through the UI
+type fakeBackend struct {
+	extended map[string]time.Duration
+	deleted  []string
+}
+
+func (f *fakeBackend) List(ctx context.Context) ([]sync.PlannedAction, error) {
+	return []sync.PlannedAction{{SilenceID: "silence-1", TicketRef: "OPS-1", Action: sync.ActionNone}}, nil
+}
+
+func (f *fakeBackend) Extend(ctx context.Context, silenceID string, duration time.Duration) (*alertmanager.Silence, error) {
+	f.extended[silenceID] = duration
+	return &alertmanager.Silence{ID: silenceID, EndsAt: time.Now().Add(duration)}, nil
+}
+
+func (f *fakeBackend) Delete(ctx context.Context, silenceID string) error {
+	f.deleted = append(f.deleted, silenceID)
+	return nil
+}
+
+func (f *fakeBackend) Link(ctx context.Context, silenceID, ticketKey string) error {
+	return nil
+}
+
+func newTestUI(backend *fakeBackend, journal *Journal) *httptest.Server {
+	return httptest.NewServer(NewHandler(backend, journal, Config{
+		Tokens:    map[string]authz.Role{"viewer-token": authz.RoleViewer, "operator-token": authz.RoleOperator},
+		TicketURL: "https://example.atlassian.net/browse/",
+	}))
+}
+
+func do(t *testing.T, server *httptest.Server, method, path, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestHandler_ServesPage(t *testing.T) {
+	server := newTestUI(&fakeBackend{}, NewJournal(0))
+	defer server.Close()
+
+	resp := do(t, server, http.MethodGet, "/ui/", "", "")
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "app.js") {
+		t.Errorf("Expected the page without a token, got %d", resp.StatusCode)
+	}
+	if csp := resp.Header.Get("Content-Security-Policy"); !strings.Contains(csp, "default-src 'self'") {
+		t.Errorf("Expected a restrictive Content-Security-Policy, got %q", csp)
+	}
+	if resp := do(t, server, http.MethodGet, "/ui/app.js", "", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the script, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandler_Authorization(t *testing.T) {
+	backend := &fakeBackend{extended: map[string]time.Duration{}}
+	server := newTestUI(backend, NewJournal(0))
+	defer server.Close()
+
+	tests := []struct {
+		method, path, token string
+		want                int
+	}{
+		{http.MethodGet, "/ui/api/silences", "", http.StatusUnauthorized},
+		{http.MethodGet, "/ui/api/silences", "viewer-token", http.StatusOK},
+		{http.MethodGet, "/ui/api/journal", "viewer-token", http.StatusOK},
+		{http.MethodDelete, "/ui/api/silences/silence-1", "viewer-token", http.StatusForbidden},
+		{http.MethodPost, "/ui/api/silences/silence-1/extend", "viewer-token", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if resp := do(t, server, tt.method, tt.path, tt.token, `{"duration": "1d"}`); resp.StatusCode != tt.want {
+			t.Errorf("%s %s with %q: expected %d, got %d", tt.method, tt.path, tt.token, tt.want, resp.StatusCode)
+		}
+	}
+	if len(backend.extended) != 0 || len(backend.deleted) != 0 {
+		t.Errorf("Expected no actions by a viewer, got %v %v", backend.extended, backend.deleted)
+	}
+
+	var config uiConfig
+	json.NewDecoder(do(t, server, http.MethodGet, "/ui/api/config", "viewer-token", "").Body).Decode(&config)
+	if config.Role != authz.RoleViewer || config.CanWrite {
+		t.Errorf("Expected a read-only viewer, got %+v", config)
+	}
+}
+
+func TestHandler_ActionsAreJournaled(t *testing.T) {
+	backend := &fakeBackend{extended: map[string]time.Duration{}}
+	journal := NewJournal(0)
+	server := newTestUI(backend, journal)
+	defer server.Close()
+
+	if resp := do(t, server, http.MethodPost, "/ui/api/silences/silence-1/extend", "operator-token", `{"duration": "7d"}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 for extend, got %d", resp.StatusCode)
+	}
+	if backend.extended["silence-1"] != 7*24*time.Hour {
+		t.Errorf("Expected silence-1 extended by 7d, got %v", backend.extended)
+	}
+	if resp := do(t, server, http.MethodPost, "/ui/api/silences/silence-1/extend", "operator-token", `{"duration": "soon"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid duration, got %d", resp.StatusCode)
+	}
+	if resp := do(t, server, http.MethodDelete, "/ui/api/silences/silence-1", "operator-token", ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204 for delete, got %d", resp.StatusCode)
+	}
+
+	var entries []Entry
+	json.NewDecoder(do(t, server, http.MethodGet, "/ui/api/journal", "viewer-token", "").Body).Decode(&entries)
+	if len(entries) != 2 || entries[0].Action != "delete" || entries[1].Action != "extend" || entries[0].Actor != "web UI (operator)" {
+		t.Errorf("Expected the delete and extend actions, newest first, got %+v", entries)
+	}
+}
+
+func TestJournal_KeepsLatestEntries(t *testing.T) {
+	journal := NewJournal(2)
+	for _, action := range []string{"first", "second", "third"} {
+		journal.Record(Entry{Action: action})
+	}
+	recent := journal.Recent()
+	if len(recent) != 2 || recent[0].Action != "third" || recent[1].Action != "second" {
+		t.Errorf("Expected the two latest entries, newest first, got %+v", recent)
+	}
+}